@@ -1,61 +1,111 @@
 package analyzer
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/dorgu-ai/dorgu/internal/config"
 	"github.com/dorgu-ai/dorgu/internal/llm"
+	"github.com/dorgu-ai/dorgu/internal/progress"
 	"github.com/dorgu-ai/dorgu/internal/types"
 )
 
-// Analyze performs complete analysis of an application at the given path
-func Analyze(path string, llmProvider string) (*types.AppAnalysis, error) {
-	analysis := &types.AppAnalysis{}
+// Analyze performs complete analysis of an application at the given path,
+// keeping the fields pinned in its AnalysisFile. disabled names analyzers to
+// skip.
+func Analyze(ctx context.Context, path string, llmProvider string, disabled []string) (*types.AppAnalysis, error) {
+	analysis, err := AnalyzeStatic(ctx, path, disabled)
+	if err != nil {
+		return nil, err
+	}
+	pinned, err := LoadPinnedAnalysis(path)
+	if err != nil {
+		return nil, err
+	}
 
-	// Try to detect app name from directory
-	analysis.Name = filepath.Base(path)
+	pinned.Enhance(ctx, analysis, llmProvider)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
 
-	// Load app-specific config if available
-	appConfig, err := config.LoadAppConfig(path)
+// AnalyzeDeterministic performs complete analysis without calling an LLM:
+// the static analysis with deterministic defaults, keeping the fields pinned
+// in the app's AnalysisFile. Fields it marks auto get the defaults.
+func AnalyzeDeterministic(ctx context.Context, path string, disabled []string) (*types.AppAnalysis, error) {
+	analysis, err := AnalyzeStatic(ctx, path, disabled)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to load app config: %v\n", err)
+		return nil, err
 	}
-	if appConfig != nil {
-		// Apply app config to analysis
-		applyAppConfig(analysis, appConfig)
+	pinned, err := LoadPinnedAnalysis(path)
+	if err != nil {
+		return nil, err
 	}
 
-	// Check for Dockerfile
-	dockerfilePath := findDockerfile(path)
-	if dockerfilePath != "" {
-		dockerAnalysis, err := ParseDockerfile(dockerfilePath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse Dockerfile: %w", err)
+	populateDefaults(analysis)
+	if pinned != nil {
+		for _, name := range pinned.Pinned {
+			// Pinned holds only known field names
+			_ = CopyField(analysis, pinned.values, name)
 		}
-		analysis.Dockerfile = dockerAnalysis
 	}
+	return analysis, nil
+}
+
+// Enhance runs LLM enhancement on a statically analyzed application, falling
+// back to deterministic defaults when the LLM is unavailable or fails. The
+// returned error is the LLM failure, already handled by the fallback. When ctx
+// is done there is no fallback; callers check ctx.Err().
+func Enhance(ctx context.Context, analysis *types.AppAnalysis, llmProvider string) error {
+	err := enhanceWithLLM(ctx, analysis, llmProvider)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		// Non-fatal: continue with basic analysis
+		slog.Warn("LLM analysis failed, using basic analysis", "provider", llmProvider, "err", err)
+		populateDefaults(analysis)
+	}
+	return err
+}
 
-	// Check for docker-compose
-	composePath := findComposeFile(path)
-	if composePath != "" {
-		composeAnalysis, err := ParseComposeFile(composePath)
-		if err != nil {
-			// Non-fatal: continue without compose analysis
-			fmt.Fprintf(os.Stderr, "Warning: failed to parse docker-compose: %v\n", err)
-		} else {
-			analysis.Compose = composeAnalysis
-		}
+// AnalyzeStatic performs the deterministic part of the analysis without
+// calling an LLM: the registered analyzers (app config, Dockerfile, compose,
+// source code, git, existing manifests) in priority order, except those in
+// disabled.
+func AnalyzeStatic(ctx context.Context, path string, disabled []string) (*types.AppAnalysis, error) {
+	if err := ValidateDisabled(disabled); err != nil {
+		return nil, fmt.Errorf("analyzers.disabled: %w", err)
 	}
+	analysis := &types.AppAnalysis{}
 
-	// Analyze source code
-	codeAnalysis, err := AnalyzeCode(path)
-	if err != nil {
-		// Non-fatal: continue without code analysis
-		fmt.Fprintf(os.Stderr, "Warning: failed to analyze code: %v\n", err)
-	} else {
-		analysis.Code = codeAnalysis
+	// Try to detect app name from directory
+	analysis.Name = filepath.Base(path)
+
+	for _, a := range Analyzers() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if contains(disabled, a.Name()) {
+			slog.Debug("analyzer disabled", "analyzer", a.Name())
+			continue
+		}
+		done := progress.Start(ctx, "analyze/"+a.Name())
+		err := a.Analyze(ctx, path, analysis)
+		done()
+		if err != nil {
+			if a.Required() {
+				return nil, err
+			}
+			// Non-fatal: continue without this analyzer
+			slog.Warn("analyzer failed", "analyzer", a.Name(), "path", path, "err", err)
+		}
 	}
 
 	// If no Dockerfile or compose found, we can't proceed
@@ -63,13 +113,6 @@ func Analyze(path string, llmProvider string) (*types.AppAnalysis, error) {
 		return nil, fmt.Errorf("no Dockerfile or docker-compose.yml found in %s", path)
 	}
 
-	// Use LLM to enhance analysis
-	if err := enhanceWithLLM(analysis, llmProvider); err != nil {
-		// Non-fatal: continue with basic analysis
-		fmt.Fprintf(os.Stderr, "Warning: LLM analysis failed, using basic analysis: %v\n", err)
-		populateDefaults(analysis)
-	}
-
 	return analysis, nil
 }
 
@@ -108,16 +151,20 @@ func findComposeFile(path string) string {
 }
 
 // enhanceWithLLM uses an LLM to provide deeper analysis
-func enhanceWithLLM(analysis *types.AppAnalysis, provider string) error {
+func enhanceWithLLM(ctx context.Context, analysis *types.AppAnalysis, provider string) error {
 	client, err := llm.NewClient(provider)
 	if err != nil {
 		return err
 	}
 
-	enhanced, err := client.AnalyzeApp(analysis)
+	done := progress.Start(ctx, "analyze/llm")
+	start := time.Now()
+	enhanced, err := client.AnalyzeApp(ctx, analysis)
+	done()
 	if err != nil {
 		return err
 	}
+	slog.Info("LLM analysis complete", "provider", provider, "duration", time.Since(start).Round(time.Millisecond))
 
 	// Merge LLM analysis with existing analysis
 	if enhanced.Type != "" {
@@ -149,15 +196,10 @@ func enhanceWithLLM(analysis *types.AppAnalysis, provider string) error {
 	}
 
 	// Ensure we still have ports from Dockerfile if LLM didn't provide them
-	if len(analysis.Ports) == 0 && analysis.Dockerfile != nil {
-		for _, port := range analysis.Dockerfile.Ports {
-			analysis.Ports = append(analysis.Ports, types.Port{
-				Port:     port,
-				Protocol: "TCP",
-				Purpose:  "HTTP",
-			})
-		}
-	}
+	defaultPorts(analysis)
+
+	classifyWorker(analysis)
+	classifyStaticSite(analysis)
 
 	// Ensure we have defaults for required fields
 	if analysis.Type == "" {
@@ -191,6 +233,8 @@ func enhanceWithLLM(analysis *types.AppAnalysis, provider string) error {
 
 // populateDefaults fills in default values when LLM is not available
 func populateDefaults(analysis *types.AppAnalysis) {
+	classifyWorker(analysis)
+	classifyStaticSite(analysis)
 	if analysis.Type == "" {
 		analysis.Type = "api"
 	}
@@ -209,15 +253,7 @@ func populateDefaults(analysis *types.AppAnalysis) {
 	}
 
 	// Extract ports from Dockerfile if available
-	if analysis.Dockerfile != nil && len(analysis.Ports) == 0 {
-		for _, port := range analysis.Dockerfile.Ports {
-			analysis.Ports = append(analysis.Ports, types.Port{
-				Port:     port,
-				Protocol: "TCP",
-				Purpose:  "HTTP",
-			})
-		}
-	}
+	defaultPorts(analysis)
 
 	// Extract language/framework from code analysis if available
 	if analysis.Code != nil {
@@ -240,6 +276,52 @@ func populateDefaults(analysis *types.AppAnalysis) {
 	}
 }
 
+// defaultPorts sets the ports, when nothing else did, to those the
+// Dockerfile EXPOSEs, with the port the framework config or code listens on
+// in place of the first one when EXPOSE leaves it out: EXPOSE only documents
+// ports, while the app binds the one it is configured with. Validation warns
+// about the conflict.
+func defaultPorts(analysis *types.AppAnalysis) {
+	if len(analysis.Ports) > 0 {
+		return
+	}
+	var ports []int
+	if analysis.Dockerfile != nil {
+		ports = append(ports, analysis.Dockerfile.Ports...)
+	}
+	if analysis.Code != nil && analysis.Code.ListenPort > 0 && !slices.Contains(ports, analysis.Code.ListenPort) {
+		if len(ports) > 0 {
+			slog.Debug("listen port differs from EXPOSE", "listen_port", analysis.Code.ListenPort, "source", analysis.Code.ListenPortSource, "expose", ports)
+			ports[0] = analysis.Code.ListenPort
+		} else {
+			ports = []int{analysis.Code.ListenPort}
+		}
+	}
+	for _, port := range ports {
+		analysis.Ports = append(analysis.Ports, types.Port{
+			Port:     port,
+			Protocol: "TCP",
+			Purpose:  "HTTP",
+		})
+	}
+}
+
+// healthCheckFromConfig converts an app config probe; nil stays nil
+func healthCheckFromConfig(p *config.HealthProbe) *types.HealthCheck {
+	if p == nil {
+		return nil
+	}
+	return &types.HealthCheck{
+		Path:             p.Path,
+		Port:             p.Port,
+		InitialDelay:     p.InitialDelay,
+		Period:           p.Period,
+		Timeout:          p.Timeout,
+		SuccessThreshold: p.SuccessThreshold,
+		FailureThreshold: p.FailureThreshold,
+	}
+}
+
 // applyAppConfig applies app-specific configuration to the analysis
 func applyAppConfig(analysis *types.AppAnalysis, appConfig *config.AppConfig) {
 	// Create app config context
@@ -302,6 +384,9 @@ func applyAppConfig(analysis *types.AppAnalysis, appConfig *config.AppConfig) {
 			TargetCPU:    appConfig.Scaling.TargetCPU,
 			TargetMemory: appConfig.Scaling.TargetMemory,
 			Behavior:     appConfig.Scaling.Behavior,
+			ScaleUp:      scalingRules(appConfig.Scaling.ScaleUp),
+			ScaleDown:    scalingRules(appConfig.Scaling.ScaleDown),
+			Metrics:      scalingMetrics(appConfig.Scaling.Metrics),
 		}
 		// Also set on analysis for immediate use
 		analysis.Scaling = ctx.Scaling
@@ -320,46 +405,38 @@ func applyAppConfig(analysis *types.AppAnalysis, appConfig *config.AppConfig) {
 	// Ingress config
 	if appConfig.Ingress != nil && appConfig.Ingress.Enabled {
 		ctx.Ingress = &types.IngressContext{
-			Enabled:    true,
-			Host:       appConfig.Ingress.Host,
-			TLSEnabled: appConfig.Ingress.TLS != nil && appConfig.Ingress.TLS.Enabled,
+			Enabled:     true,
+			Host:        appConfig.Ingress.Host,
+			Class:       appConfig.Ingress.Class,
+			Annotations: appConfig.Ingress.Annotations,
+			TLSEnabled:  appConfig.Ingress.TLS != nil && appConfig.Ingress.TLS.Enabled,
 		}
 		if appConfig.Ingress.TLS != nil {
 			ctx.Ingress.TLSSecret = appConfig.Ingress.TLS.SecretName
 		}
+		for _, h := range appConfig.Ingress.Hosts {
+			ctx.Ingress.Hosts = append(ctx.Ingress.Hosts, types.IngressHostDef{
+				Host:      h.Host,
+				TLSSecret: h.TLSSecret,
+			})
+		}
 		for _, p := range appConfig.Ingress.Paths {
 			ctx.Ingress.Paths = append(ctx.Ingress.Paths, types.IngressPathDef{
 				Path:     p.Path,
 				PathType: p.PathType,
+				Port:     p.Port,
+				Service:  p.Service,
 			})
 		}
 	}
 
-	// Health check config
+	// Health check config. The detected health check (analysis.HealthCheck)
+	// is left to code analysis; probes are resolved at generation time.
 	if appConfig.Health != nil {
-		ctx.Health = &types.HealthContext{}
-		if appConfig.Health.Liveness != nil {
-			ctx.Health.LivenessPath = appConfig.Health.Liveness.Path
-			ctx.Health.LivenessPort = appConfig.Health.Liveness.Port
-			ctx.Health.InitialDelay = appConfig.Health.Liveness.InitialDelay
-			ctx.Health.Period = appConfig.Health.Liveness.Period
-		}
-		if appConfig.Health.Readiness != nil {
-			ctx.Health.ReadinessPath = appConfig.Health.Readiness.Path
-			ctx.Health.ReadinessPort = appConfig.Health.Readiness.Port
-		}
-		if appConfig.Health.StartupGracePeriod != "" {
-			ctx.Health.StartupGracePeriod = appConfig.Health.StartupGracePeriod
-		}
-
-		// Also update the analysis health check
-		if appConfig.Health.Liveness != nil {
-			analysis.HealthCheck = &types.HealthCheck{
-				Path:         appConfig.Health.Liveness.Path,
-				Port:         appConfig.Health.Liveness.Port,
-				InitialDelay: appConfig.Health.Liveness.InitialDelay,
-				Period:       appConfig.Health.Liveness.Period,
-			}
+		ctx.Health = &types.HealthContext{
+			Liveness:           healthCheckFromConfig(appConfig.Health.Liveness),
+			Readiness:          healthCheckFromConfig(appConfig.Health.Readiness),
+			StartupGracePeriod: appConfig.Health.StartupGracePeriod,
 		}
 	}
 
@@ -393,6 +470,145 @@ func applyAppConfig(analysis *types.AppAnalysis, appConfig *config.AppConfig) {
 		}
 	}
 
+	// Networking
+	if appConfig.Networking != nil {
+		ctx.Networking = &types.NetworkingContext{
+			Expose: appConfig.Networking.Expose,
+		}
+		if dns := appConfig.Networking.DNS; dns != nil {
+			ctx.Networking.DNS = &types.DNSContext{
+				Ndots:    dns.Ndots,
+				Searches: dns.Searches,
+			}
+			for _, a := range dns.HostAliases {
+				ctx.Networking.DNS.HostAliases = append(ctx.Networking.DNS.HostAliases, types.HostAliasContext{
+					IP:        a.IP,
+					Hostnames: a.Hostnames,
+				})
+			}
+		}
+	}
+
+	// Service
+	if appConfig.Service != nil {
+		ctx.Service = &types.ServiceContext{
+			Type:        appConfig.Service.Type,
+			Presets:     appConfig.Service.Presets,
+			StaticIP:    appConfig.Service.StaticIP,
+			Annotations: appConfig.Service.Annotations,
+		}
+	}
+
+	// Secrets
+	if appConfig.Secrets != nil {
+		ctx.Secrets = &types.SecretsContext{
+			Provider: appConfig.Secrets.Provider,
+			Role:     appConfig.Secrets.Role,
+			Path:     appConfig.Secrets.Path,
+		}
+	}
+
+	// Security
+	if appConfig.Security != nil {
+		ctx.Security = &types.SecurityContext{
+			RuntimeClass:        appConfig.Security.RuntimeClass,
+			AppArmorProfile:     appConfig.Security.AppArmorProfile,
+			PodSecurityStandard: appConfig.Security.PodSecurityStandard,
+		}
+		if sp := appConfig.Security.SeccompProfile; sp != nil {
+			ctx.Security.SeccompType = sp.Type
+			ctx.Security.SeccompLocalhostProfile = sp.LocalhostProfile
+		}
+	}
+
+	// SLO
+	if s := appConfig.SLO; s != nil {
+		ctx.SLO = &types.SLOContext{
+			Availability:      s.Availability,
+			Window:            s.Window,
+			ErrorBudgetPolicy: s.ErrorBudgetPolicy,
+			Format:            s.Format,
+		}
+		if s.Latency != nil {
+			ctx.SLO.LatencyThreshold = s.Latency.Threshold
+			ctx.SLO.LatencyTarget = s.Latency.Target
+		}
+		if s.Metrics != nil {
+			ctx.SLO.RequestsMetric = s.Metrics.Requests
+			ctx.SLO.DurationMetric = s.Metrics.Duration
+			ctx.SLO.Selector = s.Metrics.Selector
+		}
+	}
+
+	// Jobs
+	for _, j := range appConfig.Jobs {
+		ctx.Jobs = append(ctx.Jobs, types.JobContext{
+			Name:                  j.Name,
+			Command:               j.Command,
+			Image:                 j.Image,
+			Hook:                  j.Hook,
+			Schedule:              j.Schedule,
+			BackoffLimit:          j.BackoffLimit,
+			ActiveDeadlineSeconds: j.ActiveDeadlineSeconds,
+		})
+	}
+
+	// Additional containers
+	for _, c := range appConfig.Containers {
+		cc := types.ContainerContext{
+			Name:    c.Name,
+			Image:   c.Image,
+			Command: c.Command,
+			Args:    c.Args,
+			Env:     c.Env,
+		}
+		for _, p := range c.Ports {
+			cc.Ports = append(cc.Ports, types.ContainerPort{Name: p.Name, Port: p.Port})
+		}
+		if c.Health != nil {
+			cc.Health = &types.HealthContext{
+				Liveness:  healthCheckFromConfig(c.Health.Liveness),
+				Readiness: healthCheckFromConfig(c.Health.Readiness),
+			}
+		}
+		if c.Resources != nil {
+			cc.Resources = &types.ResourceOverrides{
+				RequestsCPU:    c.Resources.Requests.CPU,
+				RequestsMemory: c.Resources.Requests.Memory,
+				LimitsCPU:      c.Resources.Limits.CPU,
+				LimitsMemory:   c.Resources.Limits.Memory,
+			}
+		}
+		ctx.Containers = append(ctx.Containers, cc)
+	}
+
 	// Set the context on analysis
 	analysis.AppConfig = ctx
 }
+
+// scalingRules converts app config scaling rules to their analysis form
+func scalingRules(r *config.AppScalingRules) *types.ScalingRules {
+	if r == nil {
+		return nil
+	}
+	rules := &types.ScalingRules{StabilizationWindowSeconds: r.StabilizationWindowSeconds, SelectPolicy: r.SelectPolicy}
+	for _, p := range r.Policies {
+		rules.Policies = append(rules.Policies, types.ScalingPolicy{Type: p.Type, Value: p.Value, PeriodSeconds: p.PeriodSeconds})
+	}
+	return rules
+}
+
+// scalingMetrics converts app config scaling metrics to their analysis form
+func scalingMetrics(metrics []config.AppScalingMetric) []types.ScalingMetric {
+	var out []types.ScalingMetric
+	for _, m := range metrics {
+		out = append(out, types.ScalingMetric{
+			Type:         m.Type,
+			Name:         m.Name,
+			Selector:     m.Selector,
+			AverageValue: m.AverageValue,
+			Value:        m.Value,
+		})
+	}
+	return out
+}
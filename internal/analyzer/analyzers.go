@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// Names of the built-in analyzers
+const (
+	AnalyzerAppConfig   = "appconfig"
+	AnalyzerDockerfile  = "dockerfile"
+	AnalyzerCompose     = "compose"
+	AnalyzerCode        = "code"
+	AnalyzerGit         = "git"
+	AnalyzerK8sManifest = "k8smanifest"
+)
+
+// builtin adapts a function to Analyzer
+type builtin struct {
+	name     string
+	priority int
+	required bool
+	analyze  func(ctx context.Context, path string, analysis *types.AppAnalysis) error
+}
+
+func (b builtin) Name() string   { return b.name }
+func (b builtin) Priority() int  { return b.priority }
+func (b builtin) Required() bool { return b.required }
+func (b builtin) Analyze(ctx context.Context, path string, analysis *types.AppAnalysis) error {
+	return b.analyze(ctx, path, analysis)
+}
+
+func init() {
+	Register(builtin{AnalyzerAppConfig, 0, false, analyzeAppConfig})
+	Register(builtin{AnalyzerDockerfile, 10, true, analyzeDockerfile})
+	Register(builtin{AnalyzerCompose, 20, false, analyzeCompose})
+	Register(builtin{AnalyzerCode, 30, false, analyzeCode})
+	Register(builtin{AnalyzerGit, 40, false, analyzeGit})
+	Register(builtin{AnalyzerK8sManifest, 50, false, analyzeK8sManifests})
+}
+
+// analyzeAppConfig applies the app's .dorgu.yaml
+func analyzeAppConfig(_ context.Context, path string, analysis *types.AppAnalysis) error {
+	appConfig, err := config.LoadAppConfig(path)
+	if err != nil {
+		return err
+	}
+	if appConfig != nil {
+		applyAppConfig(analysis, appConfig)
+	}
+	return nil
+}
+
+func analyzeDockerfile(_ context.Context, path string, analysis *types.AppAnalysis) error {
+	dockerfilePath := findDockerfile(path)
+	if dockerfilePath == "" {
+		return nil
+	}
+	slog.Debug("parsing Dockerfile", "path", dockerfilePath)
+	dockerAnalysis, err := ParseDockerfile(dockerfilePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse Dockerfile: %w", err)
+	}
+	analysis.Dockerfile = dockerAnalysis
+	return nil
+}
+
+func analyzeCompose(_ context.Context, path string, analysis *types.AppAnalysis) error {
+	composePath := findComposeFile(path)
+	if composePath == "" {
+		return nil
+	}
+	slog.Debug("parsing compose file", "path", composePath)
+	composeAnalysis, err := ParseComposeFile(composePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse docker-compose: %w", err)
+	}
+	analysis.Compose = composeAnalysis
+
+	var hints []types.ScheduleHint
+	for _, svc := range composeAnalysis.Services {
+		if svc.Schedule == "" {
+			continue
+		}
+		command := svc.ScheduleCommand
+		if command == nil {
+			command = svc.Command
+		}
+		hints = append(hints, types.ScheduleHint{
+			Name:     jobName(svc.Name),
+			Schedule: svc.Schedule,
+			Command:  command,
+			Source:   filepath.Base(composePath) + ":services." + svc.Name,
+		})
+	}
+	appendSchedules(analysis, hints)
+	return nil
+}
+
+func analyzeCode(_ context.Context, path string, analysis *types.AppAnalysis) error {
+	codeAnalysis, err := AnalyzeCode(path)
+	if err != nil {
+		return fmt.Errorf("failed to analyze code: %w", err)
+	}
+	// The Dockerfile tells whether the container starts a job worker or
+	// migrates before starting the app
+	var commands []string
+	if analysis.Dockerfile != nil {
+		commands = append(commands, strings.Join(append(append([]string(nil), analysis.Dockerfile.Entrypoint...), analysis.Dockerfile.Cmd...), " "))
+	}
+	codeAnalysis.JobFramework, codeAnalysis.JobQueue = detectJobFramework(path, codeAnalysis.Language, commands)
+	appendSchedules(analysis, detectSchedules(path))
+	setMigrations(analysis, detectMigrations(path, codeAnalysis.Language, commands))
+	slog.Debug("analyzed source code", "language", codeAnalysis.Language, "framework", codeAnalysis.Framework, "job_framework", codeAnalysis.JobFramework)
+	analysis.Code = codeAnalysis
+	return nil
+}
+
+// analyzeGit takes the repository from the git remote unless the app config
+// set it
+func analyzeGit(ctx context.Context, path string, analysis *types.AppAnalysis) error {
+	if analysis.Repository == "" {
+		analysis.Repository = DetectGitRemoteURL(ctx, path)
+	}
+	return nil
+}
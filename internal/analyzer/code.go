@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/dorgu-ai/dorgu/internal/types"
@@ -22,6 +24,13 @@ func AnalyzeCode(path string) (*types.CodeAnalysis, error) {
 	// Look for health endpoints
 	analysis.HealthPath = detectHealthEndpoint(path, analysis.Language)
 	analysis.MetricsPath = detectMetricsEndpoint(path, analysis.Language)
+	analysis.Routes = detectRoutes(path)
+	analysis.StaticBuild, analysis.StaticAssetsDir = detectStaticBuild(path, analysis.Language)
+	// The port a static build's dev server or preview uses is not the one
+	// it is served on
+	if analysis.StaticBuild == "" {
+		analysis.ListenPort, analysis.ListenPortSource = detectListenPort(path, analysis.Language)
+	}
 
 	return analysis, nil
 }
@@ -125,7 +134,8 @@ func detectNodeFramework(packageJSON string) string {
 		"@angular/core": "angular",
 	}
 
-	for dep, framework := range frameworks {
+	for _, dep := range sortedKeys(frameworks) {
+		framework := frameworks[dep]
 		if _, ok := pkg.Dependencies[dep]; ok {
 			return framework
 		}
@@ -164,9 +174,9 @@ func extractNodeDependencies(packageJSON string) []string {
 		"elasticsearch": "elasticsearch",
 	}
 
-	for dep, service := range serviceDeps {
+	for _, dep := range sortedKeys(serviceDeps) {
 		if _, ok := pkg.Dependencies[dep]; ok {
-			externalDeps = append(externalDeps, service)
+			externalDeps = appendUnique(externalDeps, serviceDeps[dep])
 		}
 	}
 
@@ -191,7 +201,8 @@ func detectPythonFramework(path string) string {
 		"aiohttp":   "aiohttp",
 	}
 
-	for dep, framework := range frameworks {
+	for _, dep := range sortedKeys(frameworks) {
+		framework := frameworks[dep]
 		if strings.Contains(content, dep) {
 			return framework
 		}
@@ -222,9 +233,9 @@ func extractPythonDependencies(path string) []string {
 		"celery":        "redis", // Celery typically uses Redis
 	}
 
-	for dep, service := range serviceDeps {
+	for _, dep := range sortedKeys(serviceDeps) {
 		if strings.Contains(content, dep) {
-			externalDeps = append(externalDeps, service)
+			externalDeps = appendUnique(externalDeps, serviceDeps[dep])
 		}
 	}
 
@@ -248,7 +259,8 @@ func detectGoFramework(goMod string) string {
 		"github.com/beego/beego":   "beego",
 	}
 
-	for dep, framework := range frameworks {
+	for _, dep := range sortedKeys(frameworks) {
+		framework := frameworks[dep]
 		if strings.Contains(content, dep) {
 			return framework
 		}
@@ -276,9 +288,9 @@ func extractGoDependencies(goMod string) []string {
 		"github.com/streadway/amqp":      "rabbitmq",
 	}
 
-	for dep, service := range serviceDeps {
+	for _, dep := range sortedKeys(serviceDeps) {
 		if strings.Contains(content, dep) {
-			externalDeps = append(externalDeps, service)
+			externalDeps = appendUnique(externalDeps, serviceDeps[dep])
 		}
 	}
 
@@ -415,3 +427,95 @@ func detectMetricsEndpoint(path string, language string) string {
 
 	return foundPath
 }
+
+// routePatterns match GET route registrations and capture the path
+var routePatterns = []*regexp.Regexp{
+	// Express, Koa, Fastify, FastAPI: app.get("/users"), @router.get("/users")
+	regexp.MustCompile("\\.get\\(\\s*[\"'`](/[^\"'`]*)[\"'`]"),
+	// Flask: @app.route("/users")
+	regexp.MustCompile(`@\w+\.route\(\s*["'](/[^"']*)["']`),
+	// net/http, chi, gin, echo, fiber: HandleFunc("GET /users"), r.GET("/users")
+	regexp.MustCompile(`\.(?:HandleFunc|Handle|GET|Get)\(\s*"(?:GET )?(/[^"]*)"`),
+	// Spring: @GetMapping("/users"), @RequestMapping(path = "/users")
+	regexp.MustCompile(`@(?:Get|Request)Mapping\(\s*(?:(?:value|path)\s*=\s*)?"(/[^"]*)"`),
+	// Rails, Sinatra: get "/users"
+	regexp.MustCompile(`^\s*get\s+["'](/[^"']*)["']`),
+}
+
+// maxRoutes caps the routes kept from the code
+const maxRoutes = 20
+
+// detectRoutes returns the static GET routes registered in the source,
+// sorted. Routes with parameters or wildcards are left out, since they
+// cannot be requested as they are, and so are test files, which register
+// routes only to call them.
+func detectRoutes(path string) []string {
+	seen := map[string]bool{}
+	filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			switch info.Name() {
+			case "node_modules", "vendor", ".git", "test", "tests", "__tests__", "spec":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		ext := filepath.Ext(filePath)
+		relevantExts := map[string]bool{
+			".js": true, ".ts": true, ".py": true, ".go": true,
+			".rb": true, ".java": true, ".kt": true,
+		}
+		name := info.Name()
+		if !relevantExts[ext] || strings.Contains(name, "_test.") || strings.Contains(name, ".test.") ||
+			strings.Contains(name, ".spec.") || strings.HasPrefix(name, "test_") {
+			return nil
+		}
+
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			for _, re := range routePatterns {
+				for _, m := range re.FindAllStringSubmatch(scanner.Text(), -1) {
+					if !strings.ContainsAny(m[1], ":{}<>*()[] ") {
+						seen[m[1]] = true
+					}
+				}
+			}
+		}
+		return nil
+	})
+
+	routes := sortedKeys(seen)
+	if len(routes) > maxRoutes {
+		routes = routes[:maxRoutes]
+	}
+	return routes
+}
+
+// sortedKeys returns the keys of m in order, so lookups that can match more
+// than one entry always pick the same one
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
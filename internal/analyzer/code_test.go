@@ -3,6 +3,7 @@ package analyzer
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -205,6 +206,42 @@ app.listen(3000);`
 	}
 }
 
+func TestAnalyzeCodeRoutes(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"server.js": `app.get('/health', health);
+app.get("/api/users", listUsers);
+app.get('/api/users/:id', getUser);
+app.post('/api/users', createUser);`,
+		"main.go": `mux.HandleFunc("GET /api/orders", listOrders)
+r.GET("/api/orders/{id}", getOrder)`,
+		"app.py": `@app.route("/status")
+def status(): ...`,
+		"server.test.js":            `request(app).get('/from-test')`,
+		"node_modules/lib/index.js": `app.get('/vendored', handler)`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(name), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	result, err := AnalyzeCode(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeCode() error = %v", err)
+	}
+
+	want := []string{"/api/orders", "/api/users", "/health", "/status"}
+	if !reflect.DeepEqual(result.Routes, want) {
+		t.Errorf("Routes = %v, want %v", result.Routes, want)
+	}
+}
+
 func TestAnalyzeCodeMetricsPath(t *testing.T) {
 	tmpDir := t.TempDir()
 
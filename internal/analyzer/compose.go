@@ -25,7 +25,14 @@ type ComposeServiceDef struct {
 	Volumes     []string            `yaml:"volumes"`
 	DependsOn   interface{}         `yaml:"depends_on"` // Can be list or map
 	Healthcheck *ComposeHealthcheck `yaml:"healthcheck"`
-	Command     interface{}         `yaml:"command"`
+	Command     interface{}         `yaml:"command"` // Can be string or list
+	Labels      interface{}         `yaml:"labels"`  // Can be list or map
+	Deploy      *ComposeDeploy      `yaml:"deploy"`
+}
+
+// ComposeDeploy represents the deploy section of a compose service
+type ComposeDeploy struct {
+	Labels interface{} `yaml:"labels"` // Can be list or map
 }
 
 // ComposeHealthcheck represents a healthcheck in docker-compose
@@ -53,7 +60,8 @@ func ParseComposeFile(path string) (*types.ComposeAnalysis, error) {
 		Services: make([]types.ComposeService, 0, len(compose.Services)),
 	}
 
-	for name, svc := range compose.Services {
+	for _, name := range sortedKeys(compose.Services) {
+		svc := compose.Services[name]
 		service := types.ComposeService{
 			Name:    name,
 			Image:   svc.Image,
@@ -84,6 +92,17 @@ func ParseComposeFile(path string) (*types.ComposeAnalysis, error) {
 			service.HealthCheck = parseHealthcheck(svc.Healthcheck)
 		}
 
+		// Parse command and the schedule a scheduler such as Ofelia or
+		// swarm-cronjob runs it on
+		service.Command = parseCommand(svc.Command)
+		labels := parseLabels(svc.Labels)
+		if svc.Deploy != nil {
+			for k, v := range parseLabels(svc.Deploy.Labels) {
+				labels[k] = v
+			}
+		}
+		service.Schedule, service.ScheduleCommand = scheduleLabel(labels)
+
 		analysis.Services = append(analysis.Services, service)
 	}
 
@@ -159,7 +178,8 @@ func parseEnvironment(env interface{}) []types.EnvVar {
 		}
 	case map[string]interface{}:
 		// Map format: {KEY: value, KEY2: value2}
-		for key, val := range e {
+		for _, key := range sortedKeys(e) {
+			val := e[key]
 			envVar := types.EnvVar{Name: key}
 			if val != nil {
 				envVar.Value = fmt.Sprintf("%v", val)
@@ -171,6 +191,61 @@ func parseEnvironment(env interface{}) []types.EnvVar {
 	return result
 }
 
+// parseCommand converts a compose command to its arguments; a string runs
+// in a shell
+func parseCommand(command interface{}) []string {
+	switch c := command.(type) {
+	case string:
+		if c != "" {
+			return []string{"/bin/sh", "-c", c}
+		}
+	case []interface{}:
+		var result []string
+		for _, item := range c {
+			result = append(result, fmt.Sprintf("%v", item))
+		}
+		return result
+	}
+	return nil
+}
+
+// parseLabels converts compose labels to a map
+func parseLabels(labels interface{}) map[string]string {
+	result := map[string]string{}
+	switch l := labels.(type) {
+	case []interface{}:
+		// List format: ["key=value"]
+		for _, item := range l {
+			if s, ok := item.(string); ok {
+				k, v, _ := strings.Cut(s, "=")
+				result[k] = v
+			}
+		}
+	case map[string]interface{}:
+		for k, v := range l {
+			result[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return result
+}
+
+// scheduleLabel returns the cron schedule in labels such as Ofelia's
+// ofelia.job-exec.<job>.schedule or swarm-cronjob's swarm.cronjob.schedule,
+// and the command of the job when a sibling .command label sets it
+func scheduleLabel(labels map[string]string) (string, []string) {
+	for _, k := range sortedKeys(labels) {
+		if !strings.HasSuffix(k, ".schedule") {
+			continue
+		}
+		schedule, ok := normalizeSchedule(labels[k])
+		if !ok {
+			continue
+		}
+		return schedule, parseCommand(labels[strings.TrimSuffix(k, ".schedule")+".command"])
+	}
+	return "", nil
+}
+
 // parseDependsOn extracts service dependencies
 func parseDependsOn(deps interface{}) []string {
 	var result []string
@@ -183,9 +258,7 @@ func parseDependsOn(deps interface{}) []string {
 			}
 		}
 	case map[string]interface{}:
-		for key := range d {
-			result = append(result, key)
-		}
+		result = append(result, sortedKeys(d)...)
 	}
 
 	return result
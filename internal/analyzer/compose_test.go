@@ -3,6 +3,7 @@ package analyzer
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -246,3 +247,47 @@ func TestParseComposeFileNotFound(t *testing.T) {
 		t.Error("Expected error for non-existent file, got nil")
 	}
 }
+
+func TestParseComposeFileSchedule(t *testing.T) {
+	content := `services:
+  app:
+    build: .
+  scheduler:
+    image: mcuadros/ofelia
+    labels:
+      ofelia.job-exec.backup.schedule: "0 0 2 * * *"
+      ofelia.job-exec.backup.command: ./backup.sh
+  reports:
+    build: .
+    command: ["python", "reports.py"]
+    deploy:
+      labels:
+        - swarm.cronjob.enable=true
+        - swarm.cronjob.schedule=@daily
+`
+
+	tmpDir := t.TempDir()
+	composePath := filepath.Join(tmpDir, "docker-compose.yml")
+	if err := os.WriteFile(composePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write temp compose file: %v", err)
+	}
+
+	result, err := ParseComposeFile(composePath)
+	if err != nil {
+		t.Fatalf("ParseComposeFile() error = %v", err)
+	}
+
+	services := map[string]ComposeService{}
+	for _, s := range result.Services {
+		services[s.Name] = s
+	}
+	if s := services["app"]; s.Schedule != "" {
+		t.Errorf("app Schedule = %q, want none", s.Schedule)
+	}
+	if s := services["scheduler"]; s.Schedule != "0 2 * * *" || !reflect.DeepEqual(s.ScheduleCommand, []string{"/bin/sh", "-c", "./backup.sh"}) {
+		t.Errorf("scheduler Schedule = %q, ScheduleCommand = %v", s.Schedule, s.ScheduleCommand)
+	}
+	if s := services["reports"]; s.Schedule != "@daily" || !reflect.DeepEqual(s.Command, []string{"python", "reports.py"}) {
+		t.Errorf("reports Schedule = %q, Command = %v", s.Schedule, s.Command)
+	}
+}
@@ -3,6 +3,7 @@ package analyzer
 import (
 	"bufio"
 	"os"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
@@ -83,6 +84,8 @@ func parseInstruction(line string, analysis *types.DockerfileAnalysis) {
 		analysis.User = args
 	case "LABEL":
 		parseLabel(args, analysis)
+	case "COPY", "ADD":
+		parseCopy(args, analysis)
 	}
 }
 
@@ -99,6 +102,32 @@ func parseFrom(args string, analysis *types.DockerfileAnalysis) {
 
 	// Always use the last FROM as the base image (final stage)
 	analysis.BaseImage = image
+	analysis.NginxConfig = ""
+}
+
+// nginxConfigDir holds the configuration of the nginx images
+const nginxConfigDir = "/etc/nginx"
+
+// parseCopy handles COPY and ADD instructions, recording the first that
+// copies into nginxConfigDir
+func parseCopy(args string, analysis *types.DockerfileAnalysis) {
+	// Skip flags such as --from=build and --chown=nginx
+	for strings.HasPrefix(args, "--") {
+		_, rest, _ := strings.Cut(args, " ")
+		args = strings.TrimSpace(rest)
+	}
+	parts := parseStringList(args)
+	if len(parts) < 2 || analysis.NginxConfig != "" {
+		return
+	}
+	dest := parts[len(parts)-1]
+	if !path.IsAbs(dest) {
+		dest = path.Join(analysis.WorkDir, dest)
+	}
+	dest = path.Clean(dest)
+	if dest == nginxConfigDir || strings.HasPrefix(dest, nginxConfigDir+"/") {
+		analysis.NginxConfig = dest
+	}
 }
 
 // parseExpose handles EXPOSE instructions
@@ -130,10 +159,10 @@ func parseEnv(args string, analysis *types.DockerfileAnalysis) {
 	if strings.Contains(args, "=") {
 		// KEY=value format (can have multiple)
 		pairs := parseKeyValuePairs(args)
-		for key, value := range pairs {
+		for _, key := range sortedKeys(pairs) {
 			analysis.EnvVars = append(analysis.EnvVars, types.EnvVar{
 				Name:  key,
-				Value: value,
+				Value: pairs[key],
 			})
 		}
 	} else {
@@ -203,6 +203,33 @@ CMD ["node", "server.js"]`
 	}
 }
 
+func TestParseDockerfileNginxConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"conf.d", "FROM node:20 AS build\nRUN npm run build\nFROM nginx:1.27-alpine\nCOPY --from=build /app/dist /usr/share/nginx/html\nCOPY nginx.conf /etc/nginx/conf.d/default.conf\n", "/etc/nginx/conf.d/default.conf"},
+		{"exec form with flags", "FROM nginx\nCOPY --chown=nginx:nginx [\"nginx.conf\", \"/etc/nginx/\"]\n", "/etc/nginx"},
+		{"relative to workdir", "FROM nginx\nWORKDIR /etc/nginx\nADD nginx.conf .\n", "/etc/nginx"},
+		{"build stage only", "FROM nginx AS config\nCOPY nginx.conf /etc/nginx/nginx.conf\nFROM nginx\nCOPY dist /usr/share/nginx/html\n", ""},
+		{"site only", "FROM nginx\nCOPY dist /usr/share/nginx/html\n", ""},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "Dockerfile")
+		if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		result, err := ParseDockerfile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.NginxConfig != tt.want {
+			t.Errorf("%s: NginxConfig = %q, want %q", tt.name, result.NginxConfig, tt.want)
+		}
+	}
+}
+
 func TestParseDockerfileNotFound(t *testing.T) {
 	_, err := ParseDockerfile("/nonexistent/path/Dockerfile")
 	if err == nil {
@@ -1,17 +1,18 @@
 package analyzer
 
 import (
+	"context"
 	"os/exec"
 	"strings"
 )
 
 // DetectGitRemoteURL tries to detect the Git remote URL for a given path.
 // Returns empty string if git is not available or no remote is configured.
-func DetectGitRemoteURL(path string) string {
+func DetectGitRemoteURL(ctx context.Context, path string) string {
 	if _, err := exec.LookPath("git"); err != nil {
 		return ""
 	}
-	cmd := exec.Command("git", "-C", path, "remote", "get-url", "origin")
+	cmd := exec.CommandContext(ctx, "git", "-C", path, "remote", "get-url", "origin")
 	output, err := cmd.Output()
 	if err != nil {
 		return ""
@@ -21,11 +22,11 @@ func DetectGitRemoteURL(path string) string {
 }
 
 // DetectGitBranch returns the current branch name
-func DetectGitBranch(path string) string {
+func DetectGitBranch(ctx context.Context, path string) string {
 	if _, err := exec.LookPath("git"); err != nil {
 		return ""
 	}
-	cmd := exec.Command("git", "-C", path, "rev-parse", "--abbrev-ref", "HEAD")
+	cmd := exec.CommandContext(ctx, "git", "-C", path, "rev-parse", "--abbrev-ref", "HEAD")
 	output, err := cmd.Output()
 	if err != nil {
 		return ""
@@ -34,11 +35,11 @@ func DetectGitBranch(path string) string {
 }
 
 // IsGitRepo checks if the given path is inside a git repository
-func IsGitRepo(path string) bool {
+func IsGitRepo(ctx context.Context, path string) bool {
 	if _, err := exec.LookPath("git"); err != nil {
 		return false
 	}
-	cmd := exec.Command("git", "-C", path, "rev-parse", "--is-inside-work-tree")
+	cmd := exec.CommandContext(ctx, "git", "-C", path, "rev-parse", "--is-inside-work-tree")
 	output, err := cmd.Output()
 	if err != nil {
 		return false
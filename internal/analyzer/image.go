@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// labelImageSource is the OCI label naming the image's source repository
+const labelImageSource = "org.opencontainers.image.source"
+
+// baseImageEnv are variables base images set that say nothing about the app
+var baseImageEnv = map[string]bool{
+	"PATH": true, "HOME": true, "HOSTNAME": true, "LANG": true, "LC_ALL": true, "TERM": true,
+	"GPG_KEY": true, "GOPATH": true, "GOTOOLCHAIN": true, "JAVA_HOME": true,
+}
+
+// AnalyzeImage analyzes a container image when its source is not available.
+// The exposed ports, environment, entrypoint, user, and labels in the image
+// config stand in for a Dockerfile. Credentials come from the Docker config,
+// as for docker pull; only the manifest and config are downloaded.
+func AnalyzeImage(ctx context.Context, image string, opts ...remote.Option) (*types.AppAnalysis, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image %q: %w", image, err)
+	}
+	opts = append([]remote.Option{
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+		remote.WithPlatform(v1.Platform{OS: "linux", Architecture: "amd64"}),
+	}, opts...)
+	img, err := remote.Image(ref, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", image, err)
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the config of %s: %w", image, err)
+	}
+	slog.Debug("fetched image config", "image", ref.Name())
+
+	analysis := &types.AppAnalysis{
+		Name:       path.Base(ref.Context().RepositoryStr()),
+		Dockerfile: imageDockerfile(image, cfg.Config),
+		Repository: cfg.Config.Labels[labelImageSource],
+	}
+	return analysis, nil
+}
+
+// imageDockerfile maps an image config to the Dockerfile analysis it would
+// have come from
+func imageDockerfile(image string, cfg v1.Config) *types.DockerfileAnalysis {
+	d := &types.DockerfileAnalysis{
+		BaseImage:  image,
+		Ports:      []int{},
+		EnvVars:    []types.EnvVar{},
+		WorkDir:    cfg.WorkingDir,
+		Entrypoint: cfg.Entrypoint,
+		Cmd:        cfg.Cmd,
+		User:       cfg.User,
+		Labels:     map[string]string{},
+	}
+	for port := range cfg.ExposedPorts {
+		number, proto, _ := strings.Cut(port, "/")
+		if n, err := strconv.Atoi(number); err == nil && (proto == "" || proto == "tcp") {
+			d.Ports = append(d.Ports, n)
+		}
+	}
+	sort.Ints(d.Ports)
+	for _, kv := range cfg.Env {
+		k, v, _ := strings.Cut(kv, "=")
+		if baseImageEnv[k] || strings.HasSuffix(k, "_VERSION") || strings.HasSuffix(k, "_SHA256") {
+			continue
+		}
+		d.EnvVars = append(d.EnvVars, types.EnvVar{Name: k, Value: v})
+	}
+	for k, v := range cfg.Labels {
+		d.Labels[k] = v
+	}
+	return d
+}
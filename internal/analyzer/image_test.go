@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+func TestAnalyzeImage(t *testing.T) {
+	srv := httptest.NewServer(ggcrregistry.New())
+	defer srv.Close()
+	image := strings.TrimPrefix(srv.URL, "http://") + "/acme/orders:1.2.0"
+
+	img, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, _ := img.ConfigFile()
+	cfg.OS, cfg.Architecture = "linux", "amd64"
+	cfg.Config = v1.Config{
+		ExposedPorts: map[string]struct{}{"8080/tcp": {}, "9090": {}, "5353/udp": {}},
+		Env:          []string{"PATH=/usr/bin", "NODE_VERSION=20.1.0", "PORT=8080"},
+		Entrypoint:   []string{"node"},
+		Cmd:          []string{"server.js"},
+		User:         "1000",
+		WorkingDir:   "/app",
+		Labels:       map[string]string{labelImageSource: "https://github.com/acme/orders"},
+	}
+	if img, err = mutate.ConfigFile(img, cfg); err != nil {
+		t.Fatal(err)
+	}
+	ref, _ := name.ParseReference(image)
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := AnalyzeImage(context.Background(), image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := analysis.Dockerfile
+	if analysis.Name != "orders" || analysis.Repository != "https://github.com/acme/orders" {
+		t.Errorf("name %q, repository %q", analysis.Name, analysis.Repository)
+	}
+	if !reflect.DeepEqual(d.Ports, []int{8080, 9090}) {
+		t.Errorf("ports = %v", d.Ports)
+	}
+	if len(d.EnvVars) != 1 || d.EnvVars[0].Name != "PORT" {
+		t.Errorf("env = %+v", d.EnvVars)
+	}
+	if d.User != "1000" || d.WorkDir != "/app" || d.BaseImage != image || !reflect.DeepEqual(d.Entrypoint, []string{"node"}) {
+		t.Errorf("dockerfile = %+v", d)
+	}
+
+	if _, err := AnalyzeImage(context.Background(), "not a reference"); err == nil {
+		t.Error("expected error for an invalid reference")
+	}
+}
@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// manifestDirs are searched for existing Kubernetes manifests, relative to
+// the application directory
+var manifestDirs = []string{".", "k8s", "kubernetes", "deploy", "manifests"}
+
+// analyzeK8sManifests reads the Deployments the app already has, e.g. from
+// before it was onboarded, and takes ports and the health check from them
+// when the Dockerfile and app config leave them out. Deployments dorgu
+// generated are skipped so its own output doesn't feed back in.
+func analyzeK8sManifests(_ context.Context, path string, analysis *types.AppAnalysis) error {
+	var found *types.ManifestAnalysis
+	for _, dir := range manifestDirs {
+		files, err := filepath.Glob(filepath.Join(path, dir, "*.y*ml"))
+		if err != nil {
+			return err
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			deploy := findDeployment(data)
+			if deploy == nil {
+				continue
+			}
+			rel, _ := filepath.Rel(path, file)
+			if found == nil {
+				found = manifestAnalysis(deploy)
+			}
+			found.Files = append(found.Files, filepath.ToSlash(rel))
+		}
+	}
+	if found == nil {
+		return nil
+	}
+	analysis.Manifests = found
+
+	dockerfilePorts := analysis.Dockerfile != nil && len(analysis.Dockerfile.Ports) > 0
+	if len(analysis.Ports) == 0 && !dockerfilePorts {
+		for _, p := range found.Ports {
+			analysis.Ports = append(analysis.Ports, types.Port{Port: p, Protocol: "TCP", Purpose: "HTTP"})
+		}
+	}
+	if analysis.HealthCheck == nil && found.HealthPath != "" {
+		port := found.HealthPort
+		switch {
+		case port > 0:
+		case len(analysis.Ports) > 0:
+			port = analysis.Ports[0].Port
+		case dockerfilePorts:
+			port = analysis.Dockerfile.Ports[0]
+		default:
+			port = 8080
+		}
+		analysis.HealthCheck = &types.HealthCheck{Path: found.HealthPath, Port: port}
+	}
+	return nil
+}
+
+// findDeployment returns the first Deployment in a YAML stream that dorgu
+// did not generate
+func findDeployment(data []byte) *appsv1.Deployment {
+	for _, doc := range strings.Split(string(data), "\n---") {
+		var meta struct {
+			Kind string `json:"kind"`
+		}
+		if yaml.Unmarshal([]byte(doc), &meta) != nil || meta.Kind != "Deployment" {
+			continue
+		}
+		var d appsv1.Deployment
+		if yaml.Unmarshal([]byte(doc), &d) != nil || d.Labels["app.kubernetes.io/managed-by"] == "dorgu" {
+			continue
+		}
+		if len(d.Spec.Template.Spec.Containers) > 0 {
+			return &d
+		}
+	}
+	return nil
+}
+
+// manifestAnalysis records the first container of a Deployment
+func manifestAnalysis(d *appsv1.Deployment) *types.ManifestAnalysis {
+	c := d.Spec.Template.Spec.Containers[0]
+	m := &types.ManifestAnalysis{Image: c.Image}
+	if d.Spec.Replicas != nil {
+		m.Replicas = int(*d.Spec.Replicas)
+	}
+	for _, p := range c.Ports {
+		m.Ports = append(m.Ports, int(p.ContainerPort))
+	}
+	probe := c.ReadinessProbe
+	if probe == nil || probe.HTTPGet == nil {
+		probe = c.LivenessProbe
+	}
+	if probe != nil && probe.HTTPGet != nil {
+		m.HealthPath = probe.HTTPGet.Path
+		m.HealthPort = probePort(probe.HTTPGet, c.Ports)
+	}
+	return m
+}
+
+// probePort resolves a probe's port, which may name a container port
+func probePort(get *corev1.HTTPGetAction, ports []corev1.ContainerPort) int {
+	if get.Port.IntValue() > 0 {
+		return get.Port.IntValue()
+	}
+	for _, p := range ports {
+		if p.Name == get.Port.String() {
+			return int(p.ContainerPort)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// migrationTool is a database migration tool and how to tell an app uses it
+type migrationTool struct {
+	name     string
+	language string
+	// dependency is the library in the language's manifest. The app applies
+	// migrations with it as it starts when startupDependency is set, as Spring
+	// Boot does, or when its source matches startup.
+	dependency        string
+	startupDependency bool
+	startup           *regexp.Regexp
+	// files match the tool's config or migration files, relative to the app
+	files []string
+	// command applies pending migrations once; "{dir}" is replaced with the
+	// directory of the file found
+	command []string
+	// image is the tool's official image, when the app's image usually
+	// lacks its CLI
+	image string
+	// run matches a container command that applies migrations before the app
+	// starts
+	run *regexp.Regexp
+}
+
+// migrationTools are the database migration tools detected, in the order
+// they are checked
+var migrationTools = []migrationTool{
+	{name: "flyway", language: "java", dependency: "flyway-core", startupDependency: true,
+		files: []string{"flyway.conf", "conf/flyway.conf", "flyway.toml"}, command: []string{"flyway", "migrate"}, image: "flyway/flyway",
+		run: regexp.MustCompile(`\bflyway\b.*\bmigrate\b`)},
+	{name: "liquibase", language: "java", dependency: "liquibase-core", startupDependency: true,
+		files: []string{"liquibase.properties"}, command: []string{"liquibase", "update"}, image: "liquibase/liquibase",
+		run: regexp.MustCompile(`\bliquibase\b.*\bupdate\b`)},
+	{name: "alembic", language: "python",
+		files: []string{"alembic.ini"}, command: []string{"alembic", "upgrade", "head"},
+		run: regexp.MustCompile(`\balembic\b.*\bupgrade\b`)},
+	{name: "golang-migrate", language: "go", dependency: "github.com/golang-migrate/migrate",
+		startup: regexp.MustCompile(`\bmigrate\.New(?:WithDatabaseInstance|WithSourceInstance|WithInstance)?\(`),
+		files:   []string{"migrations/*.up.sql", "db/migrations/*.up.sql", "migrate/*.up.sql"},
+		command: []string{"migrate", "-path", "{dir}", "-database", "$(DATABASE_URL)", "up"},
+		image:   "migrate/migrate",
+		run:     regexp.MustCompile(`\bmigrate\b.*\s-path\b.*\bup\b`)},
+	{name: "prisma", language: "javascript",
+		files: []string{"prisma/migrations/migration_lock.toml"}, command: []string{"npx", "prisma", "migrate", "deploy"},
+		run: regexp.MustCompile(`\bprisma\s+migrate\s+deploy\b`)},
+}
+
+// detectMigrations returns how the app migrates its database schema, or nil.
+// commands are the container's commands; scripts they run from the app are
+// read too, so an entrypoint that migrates before starting the app counts as
+// migrating on startup.
+func detectMigrations(path, language string, commands []string) *types.Migrations {
+	if language == "javascript" {
+		if script := packageScript(filepath.Join(path, "package.json"), "start"); script != "" {
+			commands = append(commands, script)
+		}
+	}
+	commands = append(commands, entrypointScripts(path, commands)...)
+
+	for _, tool := range migrationTools {
+		if tool.language != language {
+			continue
+		}
+		if tool.dependency != "" && hasDependency(path, language, tool.dependency) {
+			if source := startupSource(path, tool); source != "" {
+				return &types.Migrations{Tool: tool.name, Source: source, OnStartup: true}
+			}
+		}
+
+		file := globFile(path, tool.files)
+		if file == "" {
+			continue
+		}
+		command := make([]string, len(tool.command))
+		for i, arg := range tool.command {
+			command[i] = strings.ReplaceAll(arg, "{dir}", filepath.ToSlash(filepath.Dir(file)))
+		}
+		m := &types.Migrations{Tool: tool.name, Source: filepath.ToSlash(file), Command: command, Image: tool.image}
+		for _, c := range commands {
+			if tool.run.MatchString(c) {
+				m.OnStartup = true
+				break
+			}
+		}
+		return m
+	}
+	return nil
+}
+
+// startupSource returns the file, relative to the app, showing it applies
+// migrations with the tool's library as it starts, or ""
+func startupSource(path string, tool migrationTool) string {
+	if tool.startupDependency {
+		return globFile(path, []string{"pom.xml", "build.gradle", "build.gradle.kts"})
+	}
+	var source string
+	if tool.startup != nil {
+		findInSource(path, sourceExts[tool.language], func(rel, line string) bool {
+			if tool.startup.MatchString(line) {
+				source = rel
+			}
+			return source != ""
+		})
+	}
+	return source
+}
+
+// globFile returns the first file under path matching one of patterns,
+// relative to path, or ""
+func globFile(path string, patterns []string) string {
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(filepath.Join(path, pattern))
+		sort.Strings(matches)
+		if len(matches) > 0 {
+			rel, _ := filepath.Rel(path, matches[0])
+			return rel
+		}
+	}
+	return ""
+}
+
+// entrypointScripts returns the contents of the shell scripts in the app
+// that commands run
+func entrypointScripts(path string, commands []string) []string {
+	var scripts []string
+	for _, c := range commands {
+		for _, arg := range strings.Fields(c) {
+			if !strings.HasSuffix(arg, ".sh") {
+				continue
+			}
+			for _, candidate := range []string{strings.TrimPrefix(arg, "./"), filepath.Base(arg)} {
+				if data, err := os.ReadFile(filepath.Join(path, candidate)); err == nil {
+					scripts = append(scripts, string(data))
+					break
+				}
+			}
+		}
+	}
+	return scripts
+}
+
+// setMigrations records the app's migrations and the migrations capability
+func setMigrations(analysis *types.AppAnalysis, m *types.Migrations) {
+	if m == nil {
+		return
+	}
+	analysis.Migrations = m
+	analysis.Capabilities = appendUnique(analysis.Capabilities, types.CapabilityMigrations)
+}
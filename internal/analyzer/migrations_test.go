@@ -0,0 +1,91 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestDetectMigrations(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		files    map[string]string
+		commands []string
+		want     *types.Migrations
+	}{
+		{"flyway with spring boot", "java", map[string]string{
+			"pom.xml": "<dependency><groupId>org.flywaydb</groupId><artifactId>flyway-core</artifactId></dependency>\n",
+		}, nil, &types.Migrations{Tool: "flyway", Source: "pom.xml", OnStartup: true}},
+		{"liquibase with gradle", "java", map[string]string{
+			"build.gradle": "implementation 'org.liquibase:liquibase-core'\n",
+		}, nil, &types.Migrations{Tool: "liquibase", Source: "build.gradle", OnStartup: true}},
+		{"flyway cli", "java", map[string]string{
+			"pom.xml":          "<project/>\n",
+			"conf/flyway.conf": "flyway.locations=filesystem:sql\n",
+		}, nil, &types.Migrations{Tool: "flyway", Source: "conf/flyway.conf", Command: []string{"flyway", "migrate"}, Image: "flyway/flyway"}},
+		{"alembic", "python", map[string]string{
+			"requirements.txt": "fastapi\nalembic==1.13\n",
+			"alembic.ini":      "[alembic]\nscript_location = migrations\n",
+		}, []string{"uvicorn app:app"}, &types.Migrations{Tool: "alembic", Source: "alembic.ini", Command: []string{"alembic", "upgrade", "head"}}},
+		{"alembic in entrypoint script", "python", map[string]string{
+			"requirements.txt":     "alembic==1.13\n",
+			"alembic.ini":          "[alembic]\n",
+			"docker-entrypoint.sh": "#!/bin/sh\nalembic upgrade head\nexec gunicorn app:app\n",
+		}, []string{"./docker-entrypoint.sh"}, &types.Migrations{Tool: "alembic", Source: "alembic.ini", Command: []string{"alembic", "upgrade", "head"}, OnStartup: true}},
+		{"golang-migrate library", "go", map[string]string{
+			"go.mod":                     "module example.com/api\n\nrequire github.com/golang-migrate/migrate/v4 v4.17.0\n",
+			"internal/db/db.go":          "m, err := migrate.New(\"file://migrations\", url)\n",
+			"migrations/1_init.up.sql":   "CREATE TABLE users (id int);\n",
+			"migrations/1_init.down.sql": "DROP TABLE users;\n",
+		}, nil, &types.Migrations{Tool: "golang-migrate", Source: "internal/db/db.go", OnStartup: true}},
+		{"golang-migrate cli", "go", map[string]string{
+			"go.mod":                      "module example.com/api\n",
+			"db/migrations/1_init.up.sql": "CREATE TABLE users (id int);\n",
+		}, nil, &types.Migrations{Tool: "golang-migrate", Source: "db/migrations/1_init.up.sql",
+			Command: []string{"migrate", "-path", "db/migrations", "-database", "$(DATABASE_URL)", "up"}, Image: "migrate/migrate"}},
+		{"prisma", "javascript", map[string]string{
+			"package.json":                          `{"dependencies": {"@prisma/client": "^5.0.0"}, "scripts": {"start": "node dist/main.js"}}`,
+			"prisma/migrations/migration_lock.toml": "provider = \"postgresql\"\n",
+		}, nil, &types.Migrations{Tool: "prisma", Source: "prisma/migrations/migration_lock.toml", Command: []string{"npx", "prisma", "migrate", "deploy"}}},
+		{"prisma in start script", "javascript", map[string]string{
+			"package.json":                          `{"scripts": {"start": "prisma migrate deploy && node dist/main.js"}}`,
+			"prisma/migrations/migration_lock.toml": "provider = \"postgresql\"\n",
+		}, nil, &types.Migrations{Tool: "prisma", Source: "prisma/migrations/migration_lock.toml", Command: []string{"npx", "prisma", "migrate", "deploy"}, OnStartup: true}},
+		{"prisma without migrations", "javascript", map[string]string{
+			"package.json":         `{"dependencies": {"@prisma/client": "^5.0.0"}}`,
+			"prisma/schema.prisma": "datasource db {}\n",
+		}, nil, nil},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		for name, content := range tt.files {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := detectMigrations(dir, tt.language, tt.commands); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: detectMigrations() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSetMigrations(t *testing.T) {
+	analysis := &types.AppAnalysis{}
+	setMigrations(analysis, nil)
+	if analysis.Migrations != nil || len(analysis.Capabilities) != 0 {
+		t.Errorf("analysis = %+v, want no migrations", analysis)
+	}
+	m := &types.Migrations{Tool: "alembic", Source: "alembic.ini"}
+	setMigrations(analysis, m)
+	if analysis.Migrations != m || !reflect.DeepEqual(analysis.Capabilities, []string{types.CapabilityMigrations}) {
+		t.Errorf("analysis = %+v", analysis)
+	}
+}
@@ -0,0 +1,141 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// AnalysisFile pins the LLM-derived fields of an app's analysis
+const AnalysisFile = ".dorgu-analysis.yaml"
+
+// AutoValue marks a pinned-file field the LLM derives on every run
+const AutoValue = "auto"
+
+// pinnedHeader opens a saved AnalysisFile
+const pinnedHeader = `# Analysis pinned by dorgu generate --save-analysis and used instead of
+# the LLM. Edit values to correct them; set a field to auto to have the LLM
+# derive it on every run.
+`
+
+// PinnedAnalysis is a loaded AnalysisFile
+type PinnedAnalysis struct {
+	values *types.AppAnalysis
+	// Pinned are the fields the file sets; Auto are the fields it marks
+	// auto or leaves out
+	Pinned []string
+	Auto   []string
+}
+
+// LoadPinnedAnalysis reads the AnalysisFile in dir; nil when there is none
+func LoadPinnedAnalysis(dir string) (*PinnedAnalysis, error) {
+	data, err := os.ReadFile(filepath.Join(dir, AnalysisFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", AnalysisFile, err)
+	}
+
+	p := &PinnedAnalysis{values: &types.AppAnalysis{}}
+	values := map[string]interface{}{}
+	known := map[string]bool{}
+	for _, f := range fields {
+		known[f.name] = true
+		v, ok := raw[f.name]
+		if !ok || v == AutoValue {
+			p.Auto = append(p.Auto, f.name)
+			continue
+		}
+		values[f.key] = v
+		p.Pinned = append(p.Pinned, f.name)
+	}
+	var unknown []string
+	for k := range raw {
+		if !known[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%s: unknown fields %s (fields are %s)", AnalysisFile, strings.Join(unknown, ", "), strings.Join(FieldNames(), ", "))
+	}
+
+	data, err = json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, p.values); err != nil {
+		return nil, fmt.Errorf("%s: %w", AnalysisFile, err)
+	}
+	return p, nil
+}
+
+// Enhance enhances the analysis with the LLM only when a field is auto, then
+// applies the pinned values. A nil PinnedAnalysis enhances as Enhance does.
+func (p *PinnedAnalysis) Enhance(ctx context.Context, analysis *types.AppAnalysis, llmProvider string) error {
+	if p == nil {
+		return Enhance(ctx, analysis, llmProvider)
+	}
+	var err error
+	if len(p.Auto) > 0 {
+		err = Enhance(ctx, analysis, llmProvider)
+	} else {
+		populateDefaults(analysis)
+	}
+	for _, name := range p.Pinned {
+		// Pinned holds only known field names
+		_ = CopyField(analysis, p.values, name)
+	}
+	return err
+}
+
+// MarshalPinnedAnalysis renders the AnalysisFile recording analysis. Fields
+// the existing file in dir marks auto stay auto.
+func MarshalPinnedAnalysis(dir string, analysis *types.AppAnalysis) ([]byte, error) {
+	existing, err := LoadPinnedAnalysis(dir)
+	if err != nil {
+		return nil, err
+	}
+	auto := map[string]bool{}
+	if existing != nil {
+		for _, name := range existing.Auto {
+			auto[name] = true
+		}
+	}
+
+	data, err := json.Marshal(analysis)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	out := []byte(pinnedHeader)
+	for _, f := range fields {
+		var v interface{} = AutoValue
+		if !auto[f.name] {
+			v = values[f.key]
+		}
+		doc, err := yaml.Marshal(map[string]interface{}{f.name: v})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, doc...)
+	}
+	return out, nil
+}
@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestPinnedAnalysisRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	analysis := &types.AppAnalysis{
+		Name:            "orders",
+		Type:            "api",
+		Language:        "go",
+		Framework:       "gin",
+		Description:     "Order API",
+		Ports:           []types.Port{{Port: 8080, Protocol: "TCP", Purpose: "HTTP"}},
+		HealthCheck:     &types.HealthCheck{Path: "/healthz", Port: 8080},
+		Dependencies:    []string{"postgres"},
+		ResourceProfile: "api",
+		Scaling:         &types.ScalingConfig{MinReplicas: 2, MaxReplicas: 6, TargetCPU: 60},
+	}
+	data, err := MarshalPinnedAnalysis(dir, analysis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "# Analysis pinned") || strings.Contains(string(data), "orders") {
+		t.Errorf("unexpected file:\n%s", data)
+	}
+	// Let the LLM keep deriving the description
+	data = []byte(strings.Replace(string(data), "description: Order API", "description: auto", 1))
+	if err := os.WriteFile(filepath.Join(dir, AnalysisFile), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadPinnedAnalysis(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"description"}; !reflect.DeepEqual(p.Auto, want) {
+		t.Errorf("Auto = %v, want %v", p.Auto, want)
+	}
+	if len(p.Pinned) != len(FieldNames())-1 {
+		t.Errorf("Pinned = %v", p.Pinned)
+	}
+	if changes := DiffAnalysis(p.values, analysis); len(changes) != 1 || changes[0].Field != "description" {
+		t.Errorf("pinned values differ: %+v", changes)
+	}
+
+	// Re-saving keeps auto fields auto
+	data, err = MarshalPinnedAnalysis(dir, analysis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "description: auto") {
+		t.Errorf("auto marker lost:\n%s", data)
+	}
+}
+
+func TestPinnedAnalysisEnhance(t *testing.T) {
+	dir := t.TempDir()
+	content := "type: worker\nports: []\nhealth: null\nscaling:\n  min_replicas: 1\n  max_replicas: 3\ndependencies: [redis]\nresource_profile: worker\nlanguage: python\nframework: celery\ndescription: Queue worker\n"
+	if err := os.WriteFile(filepath.Join(dir, AnalysisFile), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadPinnedAnalysis(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Auto) != 0 {
+		t.Fatalf("Auto = %v, want none", p.Auto)
+	}
+
+	// Nothing is auto, so the LLM is not called and the pinned values win
+	// over the Dockerfile
+	analysis := &types.AppAnalysis{Dockerfile: &types.DockerfileAnalysis{Ports: []int{8080}}}
+	if err := p.Enhance(context.Background(), analysis, "openai"); err != nil {
+		t.Fatal(err)
+	}
+	if analysis.Type != "worker" || len(analysis.Ports) != 0 || analysis.HealthCheck != nil || analysis.Scaling.MaxReplicas != 3 {
+		t.Errorf("analysis = %+v", analysis)
+	}
+}
+
+func TestLoadPinnedAnalysis(t *testing.T) {
+	dir := t.TempDir()
+	if p, err := LoadPinnedAnalysis(dir); p != nil || err != nil {
+		t.Errorf("LoadPinnedAnalysis() = %v, %v; want nil, nil without a file", p, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, AnalysisFile), []byte("type: api\nreplicas: 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPinnedAnalysis(dir); err == nil || !strings.Contains(err.Error(), "unknown fields replicas") {
+		t.Errorf("err = %v, want unknown field", err)
+	}
+}
@@ -0,0 +1,131 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dorgu-ai/dorgu/internal/llm"
+	"github.com/dorgu-ai/dorgu/internal/progress"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// PipelineOptions controls a combined analysis + persona run
+type PipelineOptions struct {
+	// LLMProvider is the provider used for both analysis and persona prompts
+	LLMProvider string
+	// Name overrides the detected application name
+	Name string
+	// Persona requests PERSONA.md generation alongside the analysis
+	Persona bool
+	// DisabledAnalyzers are skipped by the static analysis
+	DisabledAnalyzers []string
+	// Image analyzes this container image's config instead of the source at
+	// path, which may then be empty (see AnalyzeImage)
+	Image string
+	// KeepDeterministic keeps the analysis as it would be without the LLM,
+	// for reviewing what the LLM changed
+	KeepDeterministic bool
+}
+
+// PipelineResult is the outcome of RunPipeline
+type PipelineResult struct {
+	Analysis *types.AppAnalysis
+	// Persona is the LLM-generated persona markdown (empty when not requested
+	// or when generation failed; see PersonaErr)
+	Persona    string
+	PersonaErr error
+	// EnhanceErr is the LLM enhancement failure; the analysis then holds
+	// deterministic defaults
+	EnhanceErr error
+	// Deterministic is the analysis without LLM enhancement, when
+	// KeepDeterministic was set
+	Deterministic *types.AppAnalysis
+}
+
+// RunPipeline analyzes the application at path and, when requested, issues the
+// persona prompt concurrently with LLM enhancement. The persona prompt is built
+// from the stabilized static analysis (app config, Dockerfile, compose, code),
+// so both LLM round-trips overlap instead of running back to back. Fields
+// pinned in the app's AnalysisFile replace the LLM's. Cancelling ctx aborts
+// the LLM calls and the run.
+func RunPipeline(ctx context.Context, path string, opts PipelineOptions) (*PipelineResult, error) {
+	var analysis *types.AppAnalysis
+	var err error
+	if opts.Image != "" {
+		analysis, err = AnalyzeImage(ctx, opts.Image)
+	} else {
+		analysis, err = AnalyzeStatic(ctx, path, opts.DisabledAnalyzers)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pinned *PinnedAnalysis
+	if path != "" {
+		if pinned, err = LoadPinnedAnalysis(path); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Name != "" {
+		analysis.Name = opts.Name
+	}
+
+	result := &PipelineResult{Analysis: analysis}
+	if opts.KeepDeterministic {
+		result.Deterministic = cloneAnalysis(analysis)
+		populateDefaults(result.Deterministic)
+	}
+
+	var wg sync.WaitGroup
+	if opts.Persona {
+		// Enhancement mutates the analysis, so the persona works on a snapshot
+		snapshot := cloneAnalysis(analysis)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client, err := llm.NewClient(opts.LLMProvider)
+			if err != nil {
+				result.PersonaErr = err
+				return
+			}
+			done := progress.Start(ctx, "analyze/persona")
+			start := time.Now()
+			result.Persona, result.PersonaErr = client.GeneratePersona(ctx, snapshot)
+			done()
+			if result.PersonaErr != nil {
+				slog.Debug("persona generation failed", "provider", opts.LLMProvider, "err", result.PersonaErr)
+				return
+			}
+			slog.Info("persona generated", "provider", opts.LLMProvider, "duration", time.Since(start).Round(time.Millisecond))
+		}()
+	}
+
+	result.EnhanceErr = pinned.Enhance(ctx, analysis, opts.LLMProvider)
+	// Enhancement may rename the app; explicit overrides still win
+	if opts.Name != "" {
+		analysis.Name = opts.Name
+	}
+
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// cloneAnalysis returns a deep copy of the analysis
+func cloneAnalysis(analysis *types.AppAnalysis) *types.AppAnalysis {
+	data, err := json.Marshal(analysis)
+	if err != nil {
+		copied := *analysis
+		return &copied
+	}
+	var clone types.AppAnalysis
+	if err := json.Unmarshal(data, &clone); err != nil {
+		copied := *analysis
+		return &copied
+	}
+	return &clone
+}
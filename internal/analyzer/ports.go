@@ -0,0 +1,199 @@
+package analyzer
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// startScripts are the package.json scripts that run the app in production,
+// in the order they are checked; dev scripts often use another port
+var startScripts = []string{"start", "start:prod", "serve", "prod"}
+
+// scriptPortPattern matches the port a package.json script passes:
+// next start --port 3001, vite preview --port=4173, serve -p 8080,
+// PORT=3001 node server.js
+var scriptPortPattern = regexp.MustCompile(`(?:\bPORT=|--port[= ]\s*|(?:^|\s)-p\s+)(\d+)\b`)
+
+// listenPortPatterns match the port the source of a language binds
+var listenPortPatterns = map[string]*regexp.Regexp{
+	// Express, Koa, Fastify: app.listen(3000), app.listen(process.env.PORT || 3000),
+	// fastify.listen({ port: 3000 })
+	"javascript": regexp.MustCompile(`\.listen\(\s*(?:\{\s*port:\s*)?(?:process\.env\.PORT\s*(?:\|\||\?\?)\s*)?(\d+)\b`),
+	// Flask, uvicorn, aiohttp: app.run(port=5001), uvicorn.run(app, port=8000),
+	// web.run_app(app, port=8081)
+	"python": regexp.MustCompile(`\brun(?:_app)?\([^)]*\bport\s*=\s*(\d+)\b`),
+	// Gin, Echo, Fiber, net/http: r.Run(":8081"), e.Start(":8081"),
+	// http.ListenAndServe("0.0.0.0:8081", nil)
+	"go": regexp.MustCompile(`\.(?:Run|Start|Listen|ListenAndServe)\(\s*"[\w.-]*:(\d+)"`),
+}
+
+// sourceExts are the extensions of the source files of a language
+var sourceExts = map[string][]string{
+	"javascript": {".js", ".mjs", ".cjs", ".ts"},
+	"python":     {".py"},
+	"go":         {".go"},
+}
+
+// springConfigDirs hold Spring Boot's application.properties and
+// application.yml, relative to the app
+var springConfigDirs = []string{"src/main/resources", "."}
+
+// springPortPattern matches a server.port value, also as a placeholder
+// with a default: 8081, ${PORT:8081}
+var springPortPattern = regexp.MustCompile(`^(?:\$\{\w+:)?(\d+)\}?$`)
+
+// detectListenPort returns the port the app listens on according to its
+// framework config or code, and the file, relative to path, that says so.
+// It returns 0 when neither sets a literal port.
+func detectListenPort(path, language string) (int, string) {
+	if language == "javascript" {
+		if port := packageScriptPort(filepath.Join(path, "package.json")); port > 0 {
+			return port, "package.json"
+		}
+	}
+	if language == "java" {
+		return springServerPort(path)
+	}
+	pattern, ok := listenPortPatterns[language]
+	if !ok {
+		return 0, ""
+	}
+	return sourceListenPort(path, pattern, sourceExts[language])
+}
+
+// packageScriptPort returns the port the start script of a package.json
+// passes, or 0
+func packageScriptPort(packageJSON string) int {
+	for _, name := range startScripts {
+		if m := scriptPortPattern.FindStringSubmatch(packageScript(packageJSON, name)); m != nil {
+			if port := parsePort(m[1]); port > 0 {
+				return port
+			}
+		}
+	}
+	return 0
+}
+
+// springServerPort returns Spring Boot's server.port from the app's
+// application.properties or application.yml, and the file
+func springServerPort(path string) (int, string) {
+	for _, dir := range springConfigDirs {
+		for _, name := range []string{"application.properties", "application.yml", "application.yaml"} {
+			rel := filepath.ToSlash(filepath.Join(dir, name))
+			data, err := os.ReadFile(filepath.Join(path, rel))
+			if err != nil {
+				continue
+			}
+			var value string
+			if strings.HasSuffix(name, ".properties") {
+				value = propertiesValue(data, "server.port")
+			} else {
+				var doc struct {
+					Server struct {
+						Port string `yaml:"port"`
+					} `yaml:"server"`
+				}
+				// The first document holds the defaults; later ones are profiles
+				_ = yaml.Unmarshal(data, &doc)
+				value = doc.Server.Port
+			}
+			if m := springPortPattern.FindStringSubmatch(strings.TrimSpace(value)); m != nil {
+				if port := parsePort(m[1]); port > 0 {
+					return port, rel
+				}
+			}
+		}
+	}
+	return 0, ""
+}
+
+// propertiesValue returns the value of key in a Java properties file
+func propertiesValue(data []byte, key string) string {
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			k, v, ok = strings.Cut(line, ":")
+		}
+		if ok && strings.TrimSpace(k) == key {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// sourceListenPort returns the first port pattern matches in the source
+// files with exts, and the file
+func sourceListenPort(path string, pattern *regexp.Regexp, exts []string) (int, string) {
+	var port int
+	var source string
+	findInSource(path, exts, func(rel, line string) bool {
+		if m := pattern.FindStringSubmatch(line); m != nil {
+			if p := parsePort(m[1]); p > 0 {
+				port, source = p, rel
+				return true
+			}
+		}
+		return false
+	})
+	return port, source
+}
+
+// findInSource calls match with each line of the source files with exts
+// under path, and the file relative to path, until it returns true.
+// Dependencies and tests are skipped, as in detectRoutes.
+func findInSource(path string, exts []string, match func(rel, line string) bool) {
+	filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			switch info.Name() {
+			case "node_modules", "vendor", ".git", "test", "tests", "__tests__", "spec":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := info.Name()
+		if !contains(exts, filepath.Ext(name)) || strings.Contains(name, "_test.") || strings.Contains(name, ".test.") ||
+			strings.Contains(name, ".spec.") || strings.HasPrefix(name, "test_") {
+			return nil
+		}
+
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil
+		}
+		defer file.Close()
+
+		rel, _ := filepath.Rel(path, filePath)
+		rel = filepath.ToSlash(rel)
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if match(rel, scanner.Text()) {
+				return filepath.SkipAll
+			}
+		}
+		return nil
+	})
+}
+
+// parsePort returns s as a TCP port, or 0 when it is not one
+func parsePort(s string) int {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0
+	}
+	return port
+}
@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestDetectListenPort(t *testing.T) {
+	tests := []struct {
+		name       string
+		language   string
+		files      map[string]string
+		wantPort   int
+		wantSource string
+	}{
+		{"package.json start script", "javascript", map[string]string{
+			"package.json": `{"scripts": {"dev": "next dev --port 4000", "start": "next start --port 3001"}}`,
+			"server.js":    "app.listen(3000)",
+		}, 3001, "package.json"},
+		{"express listen", "javascript", map[string]string{
+			"package.json":  `{"scripts": {"start": "node src/server.js"}}`,
+			"src/server.js": "app.listen(process.env.PORT || 3000, () => {})",
+		}, 3000, "src/server.js"},
+		{"flask app.run", "python", map[string]string{
+			"app.py":          "if __name__ == '__main__':\n    app.run(host='0.0.0.0', port=5001)\n",
+			"tests/test_a.py": "app.run(port=9999)",
+		}, 5001, "app.py"},
+		{"spring properties", "java", map[string]string{
+			"src/main/resources/application.properties": "spring.application.name=orders\nserver.port = 8081\n",
+		}, 8081, "src/main/resources/application.properties"},
+		{"spring yaml placeholder", "java", map[string]string{
+			"src/main/resources/application.yml": "server:\n  port: ${PORT:9000}\n---\nserver:\n  port: 9100\n",
+		}, 9000, "src/main/resources/application.yml"},
+		{"gin run", "go", map[string]string{
+			"main.go":      "func main() {\n\tr := gin.Default()\n\tr.Run(\":8081\")\n}\n",
+			"main_test.go": "r.Run(\":9999\")",
+		}, 8081, "main.go"},
+		{"port from environment only", "python", map[string]string{
+			"app.py": "app.run(port=int(os.environ['PORT']))",
+		}, 0, ""},
+		{"out of range", "go", map[string]string{
+			"main.go": "http.ListenAndServe(\":99999\", nil)",
+		}, 0, ""},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		for name, content := range tt.files {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		port, source := detectListenPort(dir, tt.language)
+		if port != tt.wantPort || source != tt.wantSource {
+			t.Errorf("%s: detectListenPort() = %d, %q, want %d, %q", tt.name, port, source, tt.wantPort, tt.wantSource)
+		}
+	}
+}
+
+func TestDefaultPorts(t *testing.T) {
+	tests := []struct {
+		name     string
+		expose   []int
+		listen   int
+		existing []types.Port
+		want     []int
+	}{
+		{"EXPOSE only", []int{8080, 9090}, 0, nil, []int{8080, 9090}},
+		{"listen port replaces the first EXPOSEd", []int{8080, 9090}, 8081, nil, []int{8081, 9090}},
+		{"listen port already EXPOSEd", []int{8080, 9090}, 9090, nil, []int{8080, 9090}},
+		{"no EXPOSE", nil, 3000, nil, []int{3000}},
+		{"ports already set", []int{8080}, 8081, []types.Port{{Port: 7000}}, []int{7000}},
+	}
+	for _, tt := range tests {
+		analysis := &types.AppAnalysis{
+			Dockerfile: &types.DockerfileAnalysis{Ports: tt.expose},
+			Code:       &types.CodeAnalysis{ListenPort: tt.listen},
+			Ports:      tt.existing,
+		}
+		defaultPorts(analysis)
+		var got []int
+		for _, p := range analysis.Ports {
+			got = append(got, p.Port)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ports = %v, want %v", tt.name, got, tt.want)
+		}
+		if len(tt.expose) > 0 && tt.expose[0] != 8080 {
+			t.Errorf("%s: the Dockerfile's ports changed to %v", tt.name, tt.expose)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// Analyzer inspects one kind of source in an application directory and
+// records what it finds in the analysis
+type Analyzer interface {
+	// Name identifies the analyzer, e.g. in analyzers.disabled
+	Name() string
+	// Priority orders the analyzers; lower runs first, so later analyzers
+	// can build on what earlier ones found
+	Priority() int
+	// Required analyzers abort the analysis when they fail; failures of
+	// the others are logged and skipped
+	Required() bool
+	// Analyze inspects the application at path. Analyzers that run external
+	// commands stop them when ctx is cancelled.
+	Analyze(ctx context.Context, path string, analysis *types.AppAnalysis) error
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]Analyzer{}
+)
+
+// Register adds an analyzer to the static analysis, replacing one of the same
+// name. New source types register from an init function.
+func Register(a Analyzer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[a.Name()] = a
+}
+
+// Analyzers returns the registered analyzers in the order they run
+func Analyzers() []Analyzer {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	list := make([]Analyzer, 0, len(registry))
+	for _, a := range registry {
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Priority() != list[j].Priority() {
+			return list[i].Priority() < list[j].Priority()
+		}
+		return list[i].Name() < list[j].Name()
+	})
+	return list
+}
+
+// ValidateDisabled rejects names in analyzers.disabled that no analyzer has
+func ValidateDisabled(disabled []string) error {
+	var names []string
+	for _, a := range Analyzers() {
+		names = append(names, a.Name())
+	}
+	for _, d := range disabled {
+		if !contains(names, d) {
+			return fmt.Errorf("unknown analyzer %q (one of %s)", d, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAnalyzersOrder(t *testing.T) {
+	var names []string
+	for _, a := range Analyzers() {
+		names = append(names, a.Name())
+	}
+	want := []string{AnalyzerAppConfig, AnalyzerDockerfile, AnalyzerCompose, AnalyzerCode, AnalyzerGit, AnalyzerK8sManifest}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Analyzers() = %v, want %v", names, want)
+	}
+}
+
+func TestAnalyzeStaticDisabled(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"Dockerfile": "FROM golang:1.21\nEXPOSE 8080\n",
+		"go.mod":     "module example.com/orders\n\ngo 1.21\n",
+	})
+
+	analysis, err := AnalyzeStatic(context.Background(), dir, []string{AnalyzerCode})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analysis.Code != nil {
+		t.Errorf("code analyzer ran while disabled: %+v", analysis.Code)
+	}
+	if analysis.Dockerfile == nil {
+		t.Error("dockerfile analyzer did not run")
+	}
+
+	if _, err := AnalyzeStatic(context.Background(), dir, []string{"terraform"}); err == nil {
+		t.Error("expected error for unknown analyzer")
+	}
+	if _, err := AnalyzeStatic(context.Background(), dir, []string{AnalyzerDockerfile}); err == nil {
+		t.Error("expected error without Dockerfile analysis")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := AnalyzeStatic(ctx, dir, nil); err != context.Canceled {
+		t.Errorf("AnalyzeStatic() with cancelled context = %v, want context.Canceled", err)
+	}
+}
+
+type stubAnalyzer struct{}
+
+func (stubAnalyzer) Name() string   { return "stub" }
+func (stubAnalyzer) Priority() int  { return 100 }
+func (stubAnalyzer) Required() bool { return false }
+func (stubAnalyzer) Analyze(_ context.Context, path string, analysis *types.AppAnalysis) error {
+	analysis.Description = "from stub"
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	Register(stubAnalyzer{})
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "stub")
+		registryMu.Unlock()
+	}()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"Dockerfile": "FROM nginx\n"})
+	analysis, err := AnalyzeStatic(context.Background(), dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analysis.Description != "from stub" {
+		t.Errorf("Description = %q", analysis.Description)
+	}
+	if err := ValidateDisabled([]string{"stub"}); err != nil {
+		t.Errorf("ValidateDisabled: %v", err)
+	}
+}
+
+func TestAnalyzeK8sManifests(t *testing.T) {
+	deployment := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: orders
+  labels:
+    app.kubernetes.io/managed-by: %s
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+        - name: orders
+          image: ghcr.io/acme/orders:1.2.0
+          ports:
+            - name: http
+              containerPort: 9000
+          readinessProbe:
+            httpGet:
+              path: /ready
+              port: http
+`
+	tests := []struct {
+		name      string
+		managedBy string
+		want      *types.HealthCheck
+		wantPorts int
+	}{
+		{"existing", "helm", &types.HealthCheck{Path: "/ready", Port: 9000}, 1},
+		{"generated by dorgu", "dorgu", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				"Dockerfile":          "FROM nginx\n",
+				"k8s/deployment.yaml": "apiVersion: v1\nkind: ConfigMap\n---\n" + fmt.Sprintf(deployment, tt.managedBy),
+			})
+			analysis := &types.AppAnalysis{}
+			if err := analyzeDockerfile(context.Background(), dir, analysis); err != nil {
+				t.Fatal(err)
+			}
+			if err := analyzeK8sManifests(context.Background(), dir, analysis); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(analysis.HealthCheck, tt.want) {
+				t.Errorf("HealthCheck = %+v, want %+v", analysis.HealthCheck, tt.want)
+			}
+			if len(analysis.Ports) != tt.wantPorts {
+				t.Errorf("Ports = %+v", analysis.Ports)
+			}
+			if tt.want != nil && (analysis.Manifests == nil || analysis.Manifests.Replicas != 3 || analysis.Manifests.Image != "ghcr.io/acme/orders:1.2.0") {
+				t.Errorf("Manifests = %+v", analysis.Manifests)
+			}
+		})
+	}
+}
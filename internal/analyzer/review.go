@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// field is an analysis field the LLM may derive
+type field struct {
+	name string
+	// key is the field's JSON key in types.AppAnalysis
+	key    string
+	format func(a *types.AppAnalysis) string
+	// copy sets the field of dst to that of src
+	copy func(dst, src *types.AppAnalysis)
+}
+
+// fields are the LLM-derived fields, in review order
+var fields = []field{
+	{"type", "type", func(a *types.AppAnalysis) string { return a.Type }, func(d, s *types.AppAnalysis) { d.Type = s.Type }},
+	{"ports", "ports", formatPorts, func(d, s *types.AppAnalysis) { d.Ports = append([]types.Port(nil), s.Ports...) }},
+	{"health", "health_check", formatHealth, func(d, s *types.AppAnalysis) { d.HealthCheck = cloneHealth(s.HealthCheck) }},
+	{"scaling", "scaling", formatScaling, func(d, s *types.AppAnalysis) { d.Scaling = cloneScaling(s.Scaling) }},
+	{"dependencies", "dependencies", func(a *types.AppAnalysis) string { return strings.Join(a.Dependencies, ", ") },
+		func(d, s *types.AppAnalysis) { d.Dependencies = append([]string(nil), s.Dependencies...) }},
+	{"resource_profile", "resource_profile", func(a *types.AppAnalysis) string { return a.ResourceProfile }, func(d, s *types.AppAnalysis) { d.ResourceProfile = s.ResourceProfile }},
+	{"language", "language", func(a *types.AppAnalysis) string { return a.Language }, func(d, s *types.AppAnalysis) { d.Language = s.Language }},
+	{"framework", "framework", func(a *types.AppAnalysis) string { return a.Framework }, func(d, s *types.AppAnalysis) { d.Framework = s.Framework }},
+	{"description", "description", func(a *types.AppAnalysis) string { return a.Description }, func(d, s *types.AppAnalysis) { d.Description = s.Description }},
+}
+
+// FieldNames returns the names of the LLM-derived fields
+func FieldNames() []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.name
+	}
+	return names
+}
+
+// FieldChange is a field the LLM set to a different value than the
+// deterministic analysis
+type FieldChange struct {
+	Field         string `json:"field"`
+	Deterministic string `json:"deterministic"`
+	Enhanced      string `json:"enhanced"`
+}
+
+// DiffAnalysis lists the fields where the LLM-enhanced analysis differs from
+// the deterministic one
+func DiffAnalysis(deterministic, enhanced *types.AppAnalysis) []FieldChange {
+	var changes []FieldChange
+	for _, f := range fields {
+		before, after := f.format(deterministic), f.format(enhanced)
+		if before != after {
+			changes = append(changes, FieldChange{Field: f.name, Deterministic: before, Enhanced: after})
+		}
+	}
+	return changes
+}
+
+// CopyField sets the named field of dst to its value in src
+func CopyField(dst, src *types.AppAnalysis, name string) error {
+	for _, f := range fields {
+		if f.name == name {
+			f.copy(dst, src)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown analysis field %q (one of %s)", name, strings.Join(FieldNames(), ", "))
+}
+
+func formatPorts(a *types.AppAnalysis) string {
+	parts := make([]string, 0, len(a.Ports))
+	for _, p := range a.Ports {
+		s := fmt.Sprintf("%d/%s", p.Port, p.Protocol)
+		if p.Purpose != "" {
+			s += " (" + p.Purpose + ")"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatHealth(a *types.AppAnalysis) string {
+	if a.HealthCheck == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s on :%d", a.HealthCheck.Path, a.HealthCheck.Port)
+}
+
+func formatScaling(a *types.AppAnalysis) string {
+	s := a.Scaling
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d-%d replicas at %d%% CPU", s.MinReplicas, s.MaxReplicas, s.TargetCPU)
+}
+
+func cloneHealth(h *types.HealthCheck) *types.HealthCheck {
+	if h == nil {
+		return nil
+	}
+	c := *h
+	return &c
+}
+
+func cloneScaling(s *types.ScalingConfig) *types.ScalingConfig {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestDiffAnalysis(t *testing.T) {
+	deterministic := &types.AppAnalysis{
+		Type:            "api",
+		Ports:           []types.Port{{Port: 8080, Protocol: "TCP", Purpose: "HTTP"}},
+		ResourceProfile: "api",
+		Scaling:         &types.ScalingConfig{MinReplicas: 2, MaxReplicas: 10, TargetCPU: 70},
+		Language:        "go",
+	}
+	enhanced := &types.AppAnalysis{
+		Type:            "worker",
+		Ports:           []types.Port{{Port: 8080, Protocol: "TCP", Purpose: "HTTP"}, {Port: 9090, Protocol: "TCP", Purpose: "metrics"}},
+		HealthCheck:     &types.HealthCheck{Path: "/healthz", Port: 8080},
+		ResourceProfile: "api",
+		Scaling:         &types.ScalingConfig{MinReplicas: 2, MaxReplicas: 10, TargetCPU: 70},
+		Dependencies:    []string{"postgres"},
+		Language:        "go",
+	}
+
+	want := []FieldChange{
+		{Field: "type", Deterministic: "api", Enhanced: "worker"},
+		{Field: "ports", Deterministic: "8080/TCP (HTTP)", Enhanced: "8080/TCP (HTTP), 9090/TCP (metrics)"},
+		{Field: "health", Deterministic: "", Enhanced: "/healthz on :8080"},
+		{Field: "dependencies", Deterministic: "", Enhanced: "postgres"},
+	}
+	if got := DiffAnalysis(deterministic, enhanced); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffAnalysis() = %+v, want %+v", got, want)
+	}
+
+	for _, name := range []string{"type", "health"} {
+		if err := CopyField(enhanced, deterministic, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if enhanced.Type != "api" || enhanced.HealthCheck != nil || len(enhanced.Ports) != 2 {
+		t.Errorf("after reverting type and health: %+v", enhanced)
+	}
+	if err := CopyField(enhanced, deterministic, "replicas"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
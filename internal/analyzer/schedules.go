@@ -0,0 +1,249 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// crontabGlobs match the crontab files of an app, relative to it. Files in
+// cron.d directories have a user field after the schedule.
+var crontabGlobs = []string{"crontab", "crontab.txt", "*.crontab", "*.cron", "cron/*", "docker/crontab", "cron.d/*", "etc/cron.d/*"}
+
+// sourceSchedulePatterns match a schedule set in the source
+var sourceSchedulePatterns = []*regexp.Regexp{
+	// node-cron: cron.schedule("*/5 * * * *", ...)
+	regexp.MustCompile("\\bcron\\.schedule\\(\\s*[\"'`]([^\"'`]+)[\"'`]"),
+	// APScheduler: CronTrigger.from_crontab("0 3 * * *")
+	regexp.MustCompile(`\bCronTrigger\.from_crontab\(\s*["']([^"']+)["']`),
+	// Comments: # cron: 0 3 * * *, // schedule: "@daily", # example.com/schedule: ...
+	regexp.MustCompile(`(?:#|//)\s*(?:[\w.-]+/)?(?:cron|schedule)\s*[:=]\s*["']?([^"']+?)["']?\s*$`),
+}
+
+// apschedulerCronPattern matches an APScheduler cron job with keyword fields:
+// scheduler.add_job(report, "cron", hour=3), @sched.scheduled_job("cron", day_of_week="mon-fri", hour=17)
+var apschedulerCronPattern = regexp.MustCompile(`\b(?:add_job|scheduled_job)\(.*["']cron["']`)
+
+// apschedulerFieldPattern matches a keyword field of an APScheduler cron job
+var apschedulerFieldPattern = regexp.MustCompile(`\b(minute|hour|day|month|day_of_week)\s*=\s*(?:"([^"]*)"|'([^']*)'|(\d+))`)
+
+// scheduleSourceExts are the files searched for schedules set in the source
+var scheduleSourceExts = []string{".js", ".mjs", ".cjs", ".ts", ".py", ".go", ".rb", ".sh"}
+
+// interpreters are skipped when naming a job after its command
+var interpreters = map[string]bool{
+	"sh": true, "bash": true, "python": true, "python3": true, "node": true, "ruby": true,
+	"php": true, "bundle": true, "exec": true, "cd": true, "/usr/bin/env": true, "env": true,
+}
+
+// detectSchedules returns the cron schedules in the app's crontab files and
+// source, each proposed as a CronJob
+func detectSchedules(path string) []types.ScheduleHint {
+	var hints []types.ScheduleHint
+	seen := map[string]bool{}
+	for _, glob := range crontabGlobs {
+		files, _ := filepath.Glob(filepath.Join(path, glob))
+		sort.Strings(files)
+		for _, file := range files {
+			if seen[file] {
+				continue
+			}
+			seen[file] = true
+			if info, err := os.Stat(file); err != nil || info.IsDir() {
+				continue
+			}
+			rel, _ := filepath.Rel(path, file)
+			rel = filepath.ToSlash(rel)
+			hints = append(hints, parseCrontab(file, rel, strings.Contains(rel, "cron.d/"))...)
+		}
+	}
+
+	findInSource(path, scheduleSourceExts, func(rel, line string) bool {
+		if schedule, ok := sourceSchedule(line); ok {
+			hints = append(hints, types.ScheduleHint{
+				Name:     jobName(strings.TrimSuffix(filepath.Base(rel), filepath.Ext(rel))),
+				Schedule: schedule,
+				Source:   rel,
+			})
+		}
+		return false
+	})
+	return hints
+}
+
+// parseCrontab returns the schedules of a crontab file; rel names it in
+// the hints' sources
+func parseCrontab(file, rel string, hasUser bool) []types.ScheduleHint {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var hints []types.ScheduleHint
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		// Variable assignments, e.g. SHELL=/bin/bash
+		if strings.Contains(fields[0], "=") {
+			continue
+		}
+		length := 5
+		if strings.HasPrefix(fields[0], "@") {
+			length = 1
+		}
+		// The command follows the schedule and, in cron.d, the user
+		count := length
+		if hasUser {
+			count++
+		}
+		if len(fields) <= count {
+			continue
+		}
+		schedule, ok := normalizeSchedule(strings.Join(fields[:length], " "))
+		if !ok {
+			continue
+		}
+		command := strings.Join(fields[count:], " ")
+		hints = append(hints, types.ScheduleHint{
+			Name:     commandJobName(fields[count:]),
+			Schedule: schedule,
+			Command:  []string{"/bin/sh", "-c", command},
+			Source:   fmt.Sprintf("%s:%d", rel, n),
+		})
+	}
+	return hints
+}
+
+// sourceSchedule returns the schedule a line of source sets, if any
+func sourceSchedule(line string) (string, bool) {
+	for _, re := range sourceSchedulePatterns {
+		if m := re.FindStringSubmatch(line); m != nil {
+			return normalizeSchedule(m[1])
+		}
+	}
+	if apschedulerCronPattern.MatchString(line) {
+		return apschedulerSchedule(line)
+	}
+	return "", false
+}
+
+// apschedulerSchedule converts the keyword fields of an APScheduler cron job
+// to a schedule. As in APScheduler, fields less significant than the least
+// significant one set default to their minimum, the others to *. Numeric
+// days of the week count from Monday in APScheduler, so only names are
+// converted.
+func apschedulerSchedule(line string) (string, bool) {
+	set := map[string]string{}
+	for _, m := range apschedulerFieldPattern.FindAllStringSubmatch(line, -1) {
+		set[m[1]] = m[2] + m[3] + m[4]
+	}
+	if len(set) == 0 {
+		return "", false
+	}
+	if dow, ok := set["day_of_week"]; ok && strings.ContainsAny(dow, "0123456789") {
+		return "", false
+	}
+	// Most significant first, with the field's position in the schedule and
+	// its minimum
+	order := []struct {
+		name     string
+		position int
+		minimum  string
+	}{{"month", 3, "1"}, {"day", 2, "1"}, {"day_of_week", 4, "*"}, {"hour", 1, "0"}, {"minute", 0, "0"}}
+	least := 0
+	for i, f := range order {
+		if _, ok := set[f.name]; ok {
+			least = i
+		}
+	}
+	fields := make([]string, 5)
+	for i, f := range order {
+		switch value, ok := set[f.name]; {
+		case ok:
+			fields[f.position] = value
+		case i > least:
+			fields[f.position] = f.minimum
+		default:
+			fields[f.position] = "*"
+		}
+	}
+	return normalizeSchedule(strings.Join(fields, " "))
+}
+
+// normalizeSchedule returns s in CronJob form, dropping a leading seconds
+// field of 0 as node-cron and Ofelia allow, and false when it is not a
+// schedule the generated CronJob would accept
+func normalizeSchedule(s string) (string, bool) {
+	fields := strings.Fields(s)
+	if len(fields) == 6 && fields[0] == "0" {
+		fields = fields[1:]
+	}
+	schedule := strings.Join(fields, " ")
+	if generator.ValidateSchedule(schedule) != nil {
+		return "", false
+	}
+	return schedule, true
+}
+
+// commandJobName names a job after the script its command runs, e.g.
+// backup for "cd /app && ./backup.sh"
+func commandJobName(command []string) string {
+	for _, arg := range command {
+		if interpreters[arg] || strings.HasPrefix(arg, "-") || arg == "&&" || strings.HasSuffix(arg, "/") {
+			continue
+		}
+		base := filepath.Base(arg)
+		if ext := filepath.Ext(base); ext != "" {
+			return jobName(strings.TrimSuffix(base, ext))
+		}
+	}
+	if len(command) > 0 {
+		return jobName(filepath.Base(command[0]))
+	}
+	return "cron"
+}
+
+// invalidJobNameChars are replaced in job names
+var invalidJobNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// jobName turns s into a job name: lowercase alphanumerics and '-'
+func jobName(s string) string {
+	name := strings.Trim(invalidJobNameChars.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if len(name) > 40 {
+		name = strings.TrimRight(name[:40], "-")
+	}
+	if name == "" {
+		return "cron"
+	}
+	return name
+}
+
+// appendSchedules appends hints to the app's schedules, suffixing names
+// already taken
+func appendSchedules(analysis *types.AppAnalysis, hints []types.ScheduleHint) {
+	taken := map[string]bool{}
+	for _, h := range analysis.Schedules {
+		taken[h.Name] = true
+	}
+	for _, h := range hints {
+		name := h.Name
+		for i := 2; taken[name]; i++ {
+			name = fmt.Sprintf("%s-%d", h.Name, i)
+		}
+		taken[name] = true
+		h.Name = name
+		analysis.Schedules = append(analysis.Schedules, h)
+	}
+}
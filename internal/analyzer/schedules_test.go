@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestDetectSchedules(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []types.ScheduleHint
+	}{
+		{"crontab", map[string]string{
+			"crontab": "SHELL=/bin/bash\n# nightly backup\n0 2 * * * cd /app && ./scripts/backup.sh\n@hourly python manage.py clearsessions\n",
+		}, []types.ScheduleHint{
+			{Name: "backup", Schedule: "0 2 * * *", Command: []string{"/bin/sh", "-c", "cd /app && ./scripts/backup.sh"}, Source: "crontab:3"},
+			{Name: "manage", Schedule: "@hourly", Command: []string{"/bin/sh", "-c", "python manage.py clearsessions"}, Source: "crontab:4"},
+		}},
+		{"cron.d with user", map[string]string{
+			"cron.d/reports": "*/30 9-17 * * mon-fri www-data /usr/local/bin/send-reports\n@daily root /app/cleanup.py\n",
+		}, []types.ScheduleHint{
+			{Name: "send-reports", Schedule: "*/30 9-17 * * mon-fri", Command: []string{"/bin/sh", "-c", "/usr/local/bin/send-reports"}, Source: "cron.d/reports:1"},
+			{Name: "cleanup", Schedule: "@daily", Command: []string{"/bin/sh", "-c", "/app/cleanup.py"}, Source: "cron.d/reports:2"},
+		}},
+		{"node-cron with seconds", map[string]string{
+			"src/jobs/digest.js": "cron.schedule('0 0 8 * * *', () => sendDigest());\n",
+		}, []types.ScheduleHint{{Name: "digest", Schedule: "0 8 * * *", Source: "src/jobs/digest.js"}}},
+		{"apscheduler keywords", map[string]string{
+			"scheduler.py": "scheduler.add_job(report, \"cron\", day_of_week=\"mon-fri\", hour=17)\n",
+		}, []types.ScheduleHint{{Name: "scheduler", Schedule: "0 17 * * mon-fri", Source: "scheduler.py"}}},
+		{"apscheduler from_crontab", map[string]string{
+			"tasks.py": "trigger = CronTrigger.from_crontab('15 3 * * *')\n",
+		}, []types.ScheduleHint{{Name: "tasks", Schedule: "15 3 * * *", Source: "tasks.py"}}},
+		{"comment annotation", map[string]string{
+			"cmd/purge/main.go": "// example.com/schedule: \"@weekly\"\npackage main\n",
+		}, []types.ScheduleHint{{Name: "main", Schedule: "@weekly", Source: "cmd/purge/main.go"}}},
+		{"not schedules", map[string]string{
+			"app.js":          "// schedule: see the ops runbook\ncron.schedule(expr, run);\n",
+			"app_test.py":     "scheduler.add_job(report, 'cron', hour=3)\n",
+			"crontab.example": "0 2 * * * ./backup.sh\n",
+		}, nil},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		for name, content := range tt.files {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := detectSchedules(dir); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: detectSchedules() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSchedule(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"*/5 * * * *", "*/5 * * * *", true},
+		{" 0 3 * * 1-5 ", "0 3 * * 1-5", true},
+		{"0 30 4 * * *", "30 4 * * *", true},
+		{"@daily", "@daily", true},
+		{"@every 1h", "", false},
+		{"30 0 4 * * *", "", false},
+		{"run the nightly job", "", false},
+		{"mon tue wed thu fri", "", false},
+		{"0 25 * * *", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := normalizeSchedule(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("normalizeSchedule(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAppendSchedules(t *testing.T) {
+	analysis := &types.AppAnalysis{Schedules: []types.ScheduleHint{{Name: "backup", Schedule: "@daily"}}}
+	appendSchedules(analysis, []types.ScheduleHint{{Name: "backup", Schedule: "@hourly"}, {Name: "backup", Schedule: "@weekly"}})
+	var names []string
+	for _, h := range analysis.Schedules {
+		names = append(names, h.Name)
+	}
+	if want := []string{"backup", "backup-2", "backup-3"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+}
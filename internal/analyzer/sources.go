@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// skipSourceDirs are never searched for apps or hashed as app inputs
+var skipSourceDirs = map[string]bool{".git": true, "node_modules": true, "vendor": true, ".venv": true, "__pycache__": true}
+
+// FindApps returns the directories under root with a Dockerfile or
+// docker-compose file, in lexical order
+func FindApps(root string) ([]string, error) {
+	var apps []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && skipSourceDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		if findDockerfile(path) != "" || findComposeFile(path) != "" {
+			apps = append(apps, path)
+		}
+		return nil
+	})
+	return apps, err
+}
+
+// HashSources returns a digest of the files under dir: their slash-separated
+// paths relative to dir and their contents. Paths for which skip returns true
+// (generated output, typically) are left out; a skipped directory is not
+// descended into.
+func HashSources(dir string, skip func(rel string) bool) (string, error) {
+	sums := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if skipSourceDirs[d.Name()] || skip(rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || skip(rel) {
+			return nil
+		}
+		sum, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		sums[rel] = sum
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	paths := make([]string, 0, len(sums))
+	for p := range sums {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	h := sha256.New()
+	for _, p := range paths {
+		io.WriteString(h, p+"\x00"+sums[p]+"\n")
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
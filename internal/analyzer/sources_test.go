@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindApps(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"orders/Dockerfile":                   "FROM golang\n",
+		"billing/compose.yaml":                "services: {}\n",
+		"billing/node_modules/dep/Dockerfile": "FROM node\n",
+		"docs/README.md":                      "# docs\n",
+	})
+
+	apps, err := FindApps(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(root, "billing"), filepath.Join(root, "orders")}
+	if !reflect.DeepEqual(apps, want) {
+		t.Errorf("FindApps() = %v, want %v", apps, want)
+	}
+}
+
+func TestHashSources(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"Dockerfile":          "FROM golang\n",
+		"main.go":             "package main\n",
+		"k8s/deployment.yaml": "kind: Deployment\n",
+		".git/HEAD":           "ref: refs/heads/main\n",
+		"node_modules/x/x.js": "x\n",
+	})
+	skipK8s := func(rel string) bool { return rel == "k8s" }
+
+	before, err := HashSources(dir, skipK8s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Skipped paths don't change the hash
+	writeFiles(t, dir, map[string]string{"k8s/deployment.yaml": "kind: Deployment\nmetadata: {}\n", ".git/HEAD": "x\n"})
+	if after, _ := HashSources(dir, skipK8s); after != before {
+		t.Error("hash changed when only skipped files changed")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := HashSources(dir, skipK8s); after == before {
+		t.Error("hash unchanged after a source change")
+	}
+}
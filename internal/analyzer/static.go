@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// staticBuildTool is a frontend build tool that emits static files
+type staticBuildTool struct {
+	name string
+	// dependency is the package.json dependency or devDependency
+	dependency string
+	// assetsDir holds the content-hashed assets it emits, relative to the
+	// site root; "" when they sit in the root
+	assetsDir string
+}
+
+// staticBuildTools are the frontend build tools detected, in the order
+// they are checked
+var staticBuildTools = []staticBuildTool{
+	{name: "vite", dependency: "vite", assetsDir: "assets"},
+	{name: "create-react-app", dependency: "react-scripts", assetsDir: "static"},
+	{name: "vue-cli", dependency: "@vue/cli-service", assetsDir: "js"},
+	{name: "angular", dependency: "@angular/cli"},
+}
+
+// serverDependencies are the packages that make a Node.js app run a server
+// rather than only build static files: HTTP frameworks and server-rendering
+// meta-frameworks
+var serverDependencies = []string{
+	"express", "fastify", "koa", "@nestjs/core", "hapi", "@hapi/hapi",
+	"next", "nuxt", "@remix-run/node", "@remix-run/serve", "@sveltejs/kit", "astro",
+	"@angular/ssr", "@nguniversal/express-engine",
+}
+
+// nginxImages match the final stage images that serve static files with nginx
+var nginxImages = []string{"nginx", "nginxinc/nginx-unprivileged"}
+
+// detectStaticBuild returns the tool that builds the app to static files
+// and the directory its hashed assets go to, when the app is a pure
+// frontend: a build tool is a dependency and no server framework is
+func detectStaticBuild(path, language string) (string, string) {
+	if language != "javascript" {
+		return "", ""
+	}
+	data, err := os.ReadFile(filepath.Join(path, "package.json"))
+	if err != nil {
+		return "", ""
+	}
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return "", ""
+	}
+	for _, dep := range serverDependencies {
+		if _, ok := pkg.Dependencies[dep]; ok {
+			return "", ""
+		}
+	}
+	for _, tool := range staticBuildTools {
+		_, dep := pkg.Dependencies[tool.dependency]
+		_, devDep := pkg.DevDependencies[tool.dependency]
+		if dep || devDep {
+			return tool.name, tool.assetsDir
+		}
+	}
+	return "", ""
+}
+
+// nginxImage reports whether image runs nginx: the official image or the
+// unprivileged one, at any tag
+func nginxImage(image string) bool {
+	repo := image
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+	repo = strings.TrimPrefix(repo, "docker.io/")
+	repo = strings.TrimPrefix(repo, "library/")
+	for _, name := range nginxImages {
+		if repo == name {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyStaticSite makes a pure frontend a web app with the web resource
+// profile, unless its app config sets the type. When the Dockerfile's final
+// stage serves the build with nginx, the app is a static site: it listens
+// on 8080, where the generated nginx.conf serves the files and /healthz, so
+// nginx can run as a non-root user. A Dockerfile that copies its own nginx
+// configuration keeps it, and its port.
+func classifyStaticSite(analysis *types.AppAnalysis) {
+	if analysis.Code == nil || analysis.Code.StaticBuild == "" {
+		return
+	}
+	if analysis.AppConfig != nil && analysis.AppConfig.Type != "" {
+		return
+	}
+	analysis.Type = "web"
+	analysis.ResourceProfile = "web"
+	if analysis.Dockerfile == nil || !nginxImage(analysis.Dockerfile.BaseImage) {
+		slog.Debug("static build not served by nginx", "build", analysis.Code.StaticBuild)
+		return
+	}
+
+	analysis.StaticSite = &types.StaticSite{
+		BuildTool:   analysis.Code.StaticBuild,
+		AssetsDir:   analysis.Code.StaticAssetsDir,
+		NginxConfig: analysis.Dockerfile.NginxConfig,
+	}
+	if analysis.StaticSite.NginxConfig != "" {
+		slog.Debug("static site keeps its nginx configuration", "path", analysis.StaticSite.NginxConfig)
+		return
+	}
+	analysis.Ports = []types.Port{{Port: types.StaticSitePort, Protocol: "TCP", Purpose: "HTTP"}}
+	analysis.HealthCheck = &types.HealthCheck{Path: types.StaticSiteHealthPath, Port: types.StaticSitePort}
+}
@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestDetectStaticBuild(t *testing.T) {
+	tests := []struct {
+		name        string
+		language    string
+		packageJSON string
+		wantBuild   string
+		wantAssets  string
+	}{
+		{"vite", "javascript", `{"dependencies": {"react": "^18.2.0"}, "devDependencies": {"vite": "^5.0.0"}}`, "vite", "assets"},
+		{"create-react-app", "javascript", `{"dependencies": {"react": "^18.2.0", "react-scripts": "5.0.1"}}`, "create-react-app", "static"},
+		{"angular", "javascript", `{"dependencies": {"@angular/core": "^17.0.0"}, "devDependencies": {"@angular/cli": "^17.0.0"}}`, "angular", ""},
+		{"vite with express server", "javascript", `{"dependencies": {"express": "^4.18.0"}, "devDependencies": {"vite": "^5.0.0"}}`, "", ""},
+		{"next", "javascript", `{"dependencies": {"next": "14.0.0", "react": "^18.2.0"}}`, "", ""},
+		{"no build tool", "javascript", `{"dependencies": {"react": "^18.2.0"}}`, "", ""},
+		{"not javascript", "python", `{"devDependencies": {"vite": "^5.0.0"}}`, "", ""},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(tt.packageJSON), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		build, assets := detectStaticBuild(dir, tt.language)
+		if build != tt.wantBuild || assets != tt.wantAssets {
+			t.Errorf("%s: detectStaticBuild() = %q, %q, want %q, %q", tt.name, build, assets, tt.wantBuild, tt.wantAssets)
+		}
+	}
+}
+
+func TestNginxImage(t *testing.T) {
+	tests := map[string]bool{
+		"nginx":                              true,
+		"nginx:1.27-alpine":                  true,
+		"docker.io/library/nginx:stable":     true,
+		"nginxinc/nginx-unprivileged:1.27":   true,
+		"registry.example.com:5000/nginx":    false,
+		"node:20-alpine":                     false,
+		"bitnami/nginx:1.27":                 false,
+		"nginxinc/nginx-unprivileged:alpine": true,
+	}
+	for image, want := range tests {
+		if got := nginxImage(image); got != want {
+			t.Errorf("nginxImage(%q) = %v, want %v", image, got, want)
+		}
+	}
+}
+
+func TestClassifyStaticSite(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Type:        "api",
+		Ports:       []types.Port{{Port: 80, Protocol: "TCP", Purpose: "HTTP"}},
+		Dockerfile:  &types.DockerfileAnalysis{BaseImage: "nginx:1.27-alpine", Ports: []int{80}},
+		Code:        &types.CodeAnalysis{Language: "javascript", StaticBuild: "vite", StaticAssetsDir: "assets"},
+		HealthCheck: &types.HealthCheck{Path: "/", Port: 80},
+	}
+	classifyStaticSite(analysis)
+	if analysis.Type != "web" || analysis.ResourceProfile != "web" {
+		t.Errorf("type %q, resource profile %q, want web", analysis.Type, analysis.ResourceProfile)
+	}
+	if analysis.StaticSite == nil || analysis.StaticSite.BuildTool != "vite" || analysis.StaticSite.AssetsDir != "assets" {
+		t.Errorf("static site = %+v", analysis.StaticSite)
+	}
+	if len(analysis.Ports) != 1 || analysis.Ports[0].Port != types.StaticSitePort {
+		t.Errorf("ports = %+v, want %d", analysis.Ports, types.StaticSitePort)
+	}
+	if analysis.HealthCheck.Path != types.StaticSiteHealthPath || analysis.HealthCheck.Port != types.StaticSitePort {
+		t.Errorf("health check = %+v", analysis.HealthCheck)
+	}
+
+	// nginx with the Dockerfile's own configuration keeps its port
+	analysis = &types.AppAnalysis{
+		Ports:      []types.Port{{Port: 80, Protocol: "TCP", Purpose: "HTTP"}},
+		Dockerfile: &types.DockerfileAnalysis{BaseImage: "nginx:1.27-alpine", NginxConfig: "/etc/nginx/conf.d/default.conf"},
+		Code:       &types.CodeAnalysis{StaticBuild: "vite", StaticAssetsDir: "assets"},
+	}
+	classifyStaticSite(analysis)
+	if analysis.Type != "web" || analysis.StaticSite == nil || analysis.StaticSite.NginxConfig != "/etc/nginx/conf.d/default.conf" {
+		t.Errorf("type %q, static site %+v", analysis.Type, analysis.StaticSite)
+	}
+	if analysis.Ports[0].Port != 80 || analysis.HealthCheck != nil {
+		t.Errorf("ports %+v, health check %+v, want the Dockerfile's", analysis.Ports, analysis.HealthCheck)
+	}
+
+	// Served by something other than nginx: a web app, as it is
+	analysis = &types.AppAnalysis{
+		Ports:      []types.Port{{Port: 3000}},
+		Dockerfile: &types.DockerfileAnalysis{BaseImage: "node:20-alpine"},
+		Code:       &types.CodeAnalysis{StaticBuild: "create-react-app"},
+	}
+	classifyStaticSite(analysis)
+	if analysis.Type != "web" || analysis.StaticSite != nil || analysis.Ports[0].Port != 3000 {
+		t.Errorf("type %q, static site %+v, ports %+v", analysis.Type, analysis.StaticSite, analysis.Ports)
+	}
+
+	// The app config's type wins
+	analysis = &types.AppAnalysis{
+		Type:       "api",
+		AppConfig:  &types.AppConfigContext{Type: "api"},
+		Dockerfile: &types.DockerfileAnalysis{BaseImage: "nginx"},
+		Code:       &types.CodeAnalysis{StaticBuild: "vite"},
+	}
+	classifyStaticSite(analysis)
+	if analysis.Type != "api" || analysis.StaticSite != nil {
+		t.Errorf("type %q, static site %+v, want the app config's api", analysis.Type, analysis.StaticSite)
+	}
+}
@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// jobFramework is a background job framework and how to tell an app runs
+// its workers rather than only enqueueing jobs
+type jobFramework struct {
+	name     string
+	language string
+	// dependency is the package in the language's manifest
+	dependency string
+	// queue is the dependency the jobs are queued in
+	queue string
+	// command matches the container command that starts a worker
+	command *regexp.Regexp
+	// source matches the source that starts a worker
+	source *regexp.Regexp
+}
+
+// jobFrameworks are the background job frameworks detected, in the order
+// they are checked
+var jobFrameworks = []jobFramework{
+	{name: "celery", language: "python", dependency: "celery", queue: "redis",
+		command: regexp.MustCompile(`\bcelery\b.*\bworker\b`), source: regexp.MustCompile(`\.worker_main\(`)},
+	{name: "sidekiq", language: "ruby", dependency: "sidekiq", queue: "redis",
+		command: regexp.MustCompile(`\bsidekiq\b`)},
+	{name: "bullmq", language: "javascript", dependency: "bullmq", queue: "redis",
+		source: regexp.MustCompile(`\bnew Worker\(`)},
+	{name: "temporal", language: "javascript", dependency: "@temporalio/worker", queue: "temporal",
+		source: regexp.MustCompile(`\bWorker\.create\(`)},
+	{name: "river", language: "go", dependency: "github.com/riverqueue/river", queue: "postgresql",
+		source: regexp.MustCompile(`\briver\.NewWorkers\(`)},
+	{name: "temporal", language: "go", dependency: "go.temporal.io/sdk", queue: "temporal",
+		source: regexp.MustCompile(`\bworker\.New\(`)},
+	{name: "asynq", language: "go", dependency: "github.com/hibiken/asynq", queue: "redis",
+		source: regexp.MustCompile(`\basynq\.NewServer\(`)},
+}
+
+// detectJobFramework returns the background job framework whose workers the
+// app runs, and the dependency its jobs are queued in. The framework must be
+// a dependency, and the container command (commands, plus the package.json
+// start script) or the source must start a worker: apps that only enqueue
+// jobs stay APIs.
+func detectJobFramework(path, language string, commands []string) (string, string) {
+	if language == "javascript" {
+		if script := packageScript(filepath.Join(path, "package.json"), "start"); script != "" {
+			commands = append(commands, script)
+		}
+	}
+	for _, fw := range jobFrameworks {
+		if fw.language != language || !hasDependency(path, language, fw.dependency) {
+			continue
+		}
+		started := false
+		if fw.command != nil {
+			for _, command := range commands {
+				if fw.command.MatchString(command) {
+					started = true
+					break
+				}
+			}
+		}
+		if !started && fw.source != nil {
+			findInSource(path, sourceExts[language], func(_, line string) bool {
+				started = fw.source.MatchString(line)
+				return started
+			})
+		}
+		if started {
+			queue := fw.queue
+			// Celery also takes RabbitMQ as its broker
+			if fw.name == "celery" && contains(extractPythonDependencies(path), "rabbitmq") {
+				queue = "rabbitmq"
+			}
+			return fw.name, queue
+		}
+	}
+	return "", ""
+}
+
+// hasDependency reports whether the manifest of language lists dependency
+func hasDependency(path, language, dependency string) bool {
+	switch language {
+	case "javascript":
+		data, err := os.ReadFile(filepath.Join(path, "package.json"))
+		if err != nil {
+			return false
+		}
+		var pkg struct {
+			Dependencies map[string]string `json:"dependencies"`
+		}
+		if err := json.Unmarshal(data, &pkg); err != nil {
+			return false
+		}
+		_, ok := pkg.Dependencies[dependency]
+		return ok
+	case "python":
+		for _, name := range []string{"requirements.txt", "pyproject.toml", "Pipfile"} {
+			data, err := os.ReadFile(filepath.Join(path, name))
+			if err == nil && regexp.MustCompile(`(?im)(?:^|[\s"'\[,])`+regexp.QuoteMeta(dependency)+`(?:[\s"'\[\]=<>~!;,]|$)`).Match(data) {
+				return true
+			}
+		}
+	case "ruby":
+		data, err := os.ReadFile(filepath.Join(path, "Gemfile"))
+		return err == nil && regexp.MustCompile(`\bgem\s+["']`+regexp.QuoteMeta(dependency)+`["']`).Match(data)
+	case "go":
+		data, err := os.ReadFile(filepath.Join(path, "go.mod"))
+		return err == nil && strings.Contains(string(data), dependency)
+	case "java":
+		for _, name := range []string{"pom.xml", "build.gradle", "build.gradle.kts"} {
+			data, err := os.ReadFile(filepath.Join(path, name))
+			if err == nil && strings.Contains(string(data), dependency) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// packageScript returns a script of a package.json, or ""
+func packageScript(packageJSON, name string) string {
+	data, err := os.ReadFile(packageJSON)
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return ""
+	}
+	return pkg.Scripts[name]
+}
+
+// classifyWorker makes an app that runs background job workers a worker,
+// with the worker resource profile and its queue as a dependency, unless its
+// app config sets the type. Type and profile are set over an LLM's guess:
+// a job framework starting workers is the stronger signal.
+func classifyWorker(analysis *types.AppAnalysis) {
+	if analysis.Code == nil || analysis.Code.JobFramework == "" {
+		return
+	}
+	if analysis.AppConfig == nil || analysis.AppConfig.Type == "" {
+		analysis.Type = "worker"
+		analysis.ResourceProfile = "worker"
+	}
+	if analysis.Code.JobQueue != "" {
+		analysis.Dependencies = appendUnique(analysis.Dependencies, analysis.Code.JobQueue)
+	}
+}
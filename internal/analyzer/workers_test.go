@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestDetectJobFramework(t *testing.T) {
+	tests := []struct {
+		name          string
+		language      string
+		files         map[string]string
+		commands      []string
+		wantFramework string
+		wantQueue     string
+	}{
+		{"celery worker command", "python", map[string]string{
+			"requirements.txt": "flask==3.0\ncelery[redis]==5.3.6\n",
+		}, []string{"celery -A tasks worker --loglevel=info"}, "celery", "redis"},
+		{"celery with rabbitmq", "python", map[string]string{
+			"requirements.txt": "celery==5.3.6\npika==1.3\n",
+		}, []string{"celery -A tasks worker"}, "celery", "rabbitmq"},
+		{"celery api only enqueues", "python", map[string]string{
+			"requirements.txt": "flask==3.0\ncelery==5.3.6\n",
+			"app.py":           "task.delay(order_id)\n",
+		}, []string{"gunicorn app:app"}, "", ""},
+		{"django-celery-beat is not celery", "python", map[string]string{
+			"requirements.txt": "django-celery-beat==2.5\n",
+		}, []string{"celery -A proj worker"}, "", ""},
+		{"sidekiq", "ruby", map[string]string{
+			"Gemfile": "source 'https://rubygems.org'\ngem 'rails'\ngem \"sidekiq\", \"~> 7.0\"\n",
+		}, []string{"bundle exec sidekiq -C config/sidekiq.yml"}, "sidekiq", "redis"},
+		{"bullmq", "javascript", map[string]string{
+			"package.json":  `{"dependencies": {"bullmq": "^5.0.0"}, "scripts": {"start": "node src/worker.js"}}`,
+			"src/worker.js": "const worker = new Worker('emails', async job => send(job.data), { connection });\n",
+		}, nil, "bullmq", "redis"},
+		{"bullmq producer", "javascript", map[string]string{
+			"package.json": `{"dependencies": {"bullmq": "^5.0.0", "express": "^4.18.0"}}`,
+			"server.js":    "const queue = new Queue('emails');\n",
+		}, nil, "", ""},
+		{"temporal worker", "go", map[string]string{
+			"go.mod":  "module example.com/billing\n\nrequire go.temporal.io/sdk v1.26.0\n",
+			"main.go": "w := worker.New(c, \"billing\", worker.Options{})\n",
+		}, nil, "temporal", "temporal"},
+		{"river", "go", map[string]string{
+			"go.mod":  "module example.com/jobs\n\nrequire github.com/riverqueue/river v0.10.0\n",
+			"main.go": "workers := river.NewWorkers()\n",
+		}, nil, "river", "postgresql"},
+		{"asynq server", "go", map[string]string{
+			"go.mod":             "module example.com/mailer\n\nrequire github.com/hibiken/asynq v0.24.1\n",
+			"cmd/worker/main.go": "srv := asynq.NewServer(redisOpt, asynq.Config{Concurrency: 10})\n",
+		}, nil, "asynq", "redis"},
+		{"asynq client", "go", map[string]string{
+			"go.mod":  "module example.com/api\n\nrequire github.com/hibiken/asynq v0.24.1\n",
+			"main.go": "client := asynq.NewClient(redisOpt)\n",
+		}, nil, "", ""},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		for name, content := range tt.files {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		framework, queue := detectJobFramework(dir, tt.language, tt.commands)
+		if framework != tt.wantFramework || queue != tt.wantQueue {
+			t.Errorf("%s: detectJobFramework() = %q, %q, want %q, %q", tt.name, framework, queue, tt.wantFramework, tt.wantQueue)
+		}
+	}
+}
+
+func TestClassifyWorker(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Type:            "api",
+		ResourceProfile: "api",
+		Dependencies:    []string{"postgresql"},
+		Code:            &types.CodeAnalysis{JobFramework: "celery", JobQueue: "redis"},
+	}
+	classifyWorker(analysis)
+	if analysis.Type != "worker" || analysis.ResourceProfile != "worker" {
+		t.Errorf("type %q, resource profile %q, want worker", analysis.Type, analysis.ResourceProfile)
+	}
+	if want := []string{"postgresql", "redis"}; !reflect.DeepEqual(analysis.Dependencies, want) {
+		t.Errorf("dependencies = %v, want %v", analysis.Dependencies, want)
+	}
+
+	// The app config's type wins
+	analysis = &types.AppAnalysis{
+		Type:      "api",
+		AppConfig: &types.AppConfigContext{Type: "api"},
+		Code:      &types.CodeAnalysis{JobFramework: "sidekiq", JobQueue: "redis"},
+	}
+	classifyWorker(analysis)
+	if analysis.Type != "api" {
+		t.Errorf("type %q, want the app config's api", analysis.Type)
+	}
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/analyzer"
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var analyzeFlags struct {
+	name        string
+	llmProvider string
+	image       string
+}
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze [path]",
+	Short: "Analyze an application without generating manifests",
+	Long: `Run the same analysis as 'dorgu generate' and print what was detected:
+application type, language, ports, health checks, environment variables,
+dependencies, and scaling. Use -o json or -o yaml for the full analysis.
+
+Without the source, --image analyzes a container image instead: its exposed
+ports, environment, entrypoint, user, and labels are read from the registry
+(credentials from the Docker config, as for docker pull).
+
+Examples:
+  dorgu analyze .
+  dorgu analyze ./my-app -o json | jq .ports
+  dorgu analyze --image ghcr.io/org/app:1.4.2`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAnalyze,
+}
+
+func init() {
+	analyzeCmd.Flags().StringVar(&analyzeFlags.name, "name", "", "override application name")
+	analyzeCmd.Flags().StringVar(&analyzeFlags.image, "image", "", "analyze this container image instead of source code (e.g. ghcr.io/org/app:tag)")
+	analyzeCmd.Flags().StringVar(&analyzeFlags.llmProvider, "llm-provider", "", "LLM provider: openai, anthropic, gemini, ollama (default from config)")
+}
+
+func runAnalyze(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat, "", "json", "yaml"); err != nil {
+		return err
+	}
+
+	var absPath string
+	if analyzeFlags.image != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--image cannot be used with a path")
+		}
+	} else {
+		targetPath := "."
+		if len(args) > 0 {
+			targetPath = args[0]
+		}
+		var err error
+		if absPath, err = filepath.Abs(targetPath); err != nil {
+			return fmt.Errorf("failed to resolve path: %w", err)
+		}
+		if _, err := os.Stat(absPath); os.IsNotExist(err) {
+			return fmt.Errorf("path does not exist: %s", absPath)
+		}
+	}
+
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		globalCfg = config.DefaultGlobalConfig()
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.Default()
+	}
+	effectiveProvider := globalCfg.GetEffectiveProvider(analyzeFlags.llmProvider)
+	if effectiveProvider == "" {
+		effectiveProvider = cfg.LLM.Provider
+	}
+	if effectiveProvider == "" {
+		effectiveProvider = "openai"
+	}
+
+	s := newSpinner(" Analyzing application...")
+	s.Start()
+	pipeline, err := analyzer.RunPipeline(cmd.Context(), absPath, analyzer.PipelineOptions{
+		LLMProvider:       effectiveProvider,
+		Name:              analyzeFlags.name,
+		DisabledAnalyzers: cfg.Analyzers.Disabled,
+		Image:             analyzeFlags.image,
+	})
+	s.Stop()
+	if err != nil {
+		return fmt.Errorf("analysis failed: %w", err)
+	}
+	analysis := pipeline.Analysis
+
+	if handled, err := printStructured(analysis, outputFormat); handled {
+		return err
+	}
+
+	output.Header(fmt.Sprintf("Application: %s", analysis.Name))
+	printField("Type", analysis.Type)
+	printField("Language", analysis.Language)
+	printField("Framework", analysis.Framework)
+	printField("Description", analysis.Description)
+	printField("Profile", analysis.ResourceProfile)
+
+	if len(analysis.Ports) > 0 {
+		ports := make([]string, 0, len(analysis.Ports))
+		for _, p := range analysis.Ports {
+			port := fmt.Sprintf("%d/%s", p.Port, p.Protocol)
+			if p.Purpose != "" {
+				port += " (" + p.Purpose + ")"
+			}
+			ports = append(ports, port)
+		}
+		printField("Ports", strings.Join(ports, ", "))
+	}
+	if hc := analysis.HealthCheck; hc != nil {
+		printField("Health Check", fmt.Sprintf("%s on port %d", hc.Path, hc.Port))
+	}
+	if sc := analysis.Scaling; sc != nil {
+		printField("Scaling", fmt.Sprintf("%d-%d replicas", sc.MinReplicas, sc.MaxReplicas))
+	}
+	if len(analysis.Dependencies) > 0 {
+		printField("Dependencies", strings.Join(analysis.Dependencies, ", "))
+	}
+	if len(analysis.Capabilities) > 0 {
+		printField("Capabilities", strings.Join(analysis.Capabilities, ", "))
+	}
+	if m := analysis.Migrations; m != nil {
+		when := "applied with " + strings.Join(m.Command, " ")
+		if m.OnStartup {
+			when = "on startup"
+		}
+		printField("Migrations", fmt.Sprintf("%s (%s), %s", m.Tool, m.Source, when))
+	}
+	if len(analysis.EnvVars) > 0 {
+		names := make([]string, 0, len(analysis.EnvVars))
+		for _, e := range analysis.EnvVars {
+			names = append(names, e.Name)
+		}
+		printField("Env Vars", strings.Join(names, ", "))
+	}
+
+	fmt.Println()
+	output.Dim("Use -o json or -o yaml for the full analysis")
+	return nil
+}
@@ -1,21 +1,26 @@
 package cli
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 
+	"github.com/dorgu-ai/dorgu/internal/kube"
 	"github.com/dorgu-ai/dorgu/internal/output"
+	"github.com/dorgu-ai/dorgu/internal/types"
 )
 
 var clusterFlags struct {
-	name        string
-	environment string
-	dryRun      bool
+	name          string
+	environment   string
+	dryRun        bool
+	skipDiscovery bool
 }
 
 var clusterCmd = &cobra.Command{
@@ -41,7 +46,9 @@ discovered add-ons, and application count.
 
 Examples:
   dorgu cluster status
-  dorgu cluster status my-cluster`,
+  dorgu cluster status my-cluster
+  dorgu cluster status my-cluster -o wide
+  dorgu cluster status -o json`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runClusterStatus,
 }
@@ -53,9 +60,15 @@ var clusterInitCmd = &cobra.Command{
 This establishes the cluster's identity and allows the Dorgu Operator
 to discover and track cluster state.
 
+The API server is queried for the Kubernetes version, node count, platform
+(EKS, GKE, AKS, k3s, kind, ...) and well-known add-ons such as ArgoCD,
+ingress-nginx, and cert-manager; the discovered values are recorded under
+spec.discovered. Use --dry-run to review them without applying.
+
 Examples:
   dorgu cluster init --name production-cluster --environment production
-  dorgu cluster init --name dev-cluster --environment development --dry-run`,
+  dorgu cluster init --name dev-cluster --environment development --dry-run
+  dorgu cluster init --name offline --skip-discovery`,
 	RunE: runClusterInit,
 }
 
@@ -67,6 +80,7 @@ func init() {
 	clusterInitCmd.Flags().StringVar(&clusterFlags.name, "name", "", "cluster name (required)")
 	clusterInitCmd.Flags().StringVar(&clusterFlags.environment, "environment", "development", "cluster environment (development, staging, production, sandbox)")
 	clusterInitCmd.Flags().BoolVar(&clusterFlags.dryRun, "dry-run", false, "print to stdout without applying")
+	clusterInitCmd.Flags().BoolVar(&clusterFlags.skipDiscovery, "skip-discovery", false, "do not query the cluster for version, nodes, and add-ons")
 	clusterInitCmd.MarkFlagRequired("name")
 
 	// Register subcommands
@@ -75,9 +89,13 @@ func init() {
 }
 
 func runClusterStatus(cmd *cobra.Command, args []string) error {
-	// Check kubectl availability
-	if _, err := exec.LookPath("kubectl"); err != nil {
-		return fmt.Errorf("kubectl not found in PATH; required for cluster status")
+	if err := validateOutputFormat(outputFormat, "", "wide", "yaml", "json"); err != nil {
+		return err
+	}
+
+	client, err := kube.NewClient()
+	if err != nil {
+		return fmt.Errorf("%w; required for cluster status", err)
 	}
 
 	var name string
@@ -89,142 +107,140 @@ func runClusterStatus(cmd *cobra.Command, args []string) error {
 
 	if name == "" {
 		// List all ClusterPersonas
-		return listClusterPersonas()
+		return listClusterPersonas(cmd.Context(), client)
 	}
 
 	// Get specific ClusterPersona
-	return getClusterPersonaStatus(name)
+	return getClusterPersonaStatus(cmd.Context(), client, name)
 }
 
-func listClusterPersonas() error {
-	kubectlCmd := exec.Command("kubectl", "get", "clusterpersona", "-o", "wide")
-	rawOutput, err := kubectlCmd.CombinedOutput()
+func listClusterPersonas(ctx context.Context, client *kube.Client) error {
+	personas, err := client.ListClusterPersonas(ctx)
 	if err != nil {
-		outputStr := strings.TrimSpace(string(rawOutput))
-		if strings.Contains(outputStr, "the server doesn't have a resource type") {
-			return fmt.Errorf("ClusterPersona CRD is not installed on this cluster. Install the Dorgu Operator first")
-		}
-		if strings.Contains(outputStr, "No resources found") {
-			output.Info("No ClusterPersona resources found. Create one with: dorgu cluster init --name <name>")
-			return nil
-		}
-		return fmt.Errorf("failed to list cluster personas: %s", outputStr)
+		return clusterKubeError(err, "")
 	}
 
-	output.Header("ClusterPersonas")
-	fmt.Println(string(rawOutput))
-	return nil
-}
+	if handled, err := printStructured(personas, outputFormat); handled {
+		return err
+	}
 
-func getClusterPersonaStatus(name string) error {
-	kubectlCmd := exec.Command("kubectl", "get", "clusterpersona", name, "-o", "yaml")
-	rawOutput, err := kubectlCmd.CombinedOutput()
-	if err != nil {
-		outputStr := strings.TrimSpace(string(rawOutput))
-		if strings.Contains(outputStr, "not found") {
-			return fmt.Errorf("ClusterPersona '%s' not found", name)
+	if len(personas) == 0 {
+		output.Info("No ClusterPersona resources found. Create one with: dorgu cluster init --name <name>")
+		return nil
+	}
+
+	output.Header("ClusterPersonas")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	header := "NAME\tENVIRONMENT\tPHASE\tVERSION\tAPPS"
+	if outputFormat == "wide" {
+		header += "\tPLATFORM\tNODES\tPODS"
+	}
+	fmt.Fprintln(w, header+"\tAGE")
+	for _, p := range personas {
+		st := p.Status
+		if st == nil {
+			st = &types.ClusterPersonaStatus{}
 		}
-		if strings.Contains(outputStr, "the server doesn't have a resource type") {
-			return fmt.Errorf("ClusterPersona CRD is not installed on this cluster. Install the Dorgu Operator first")
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d", p.Metadata.Name, p.Spec.Environment, orNone(st.Phase), orNone(st.KubernetesVersion), st.ApplicationCount)
+		if outputFormat == "wide" {
+			pods := 0
+			if st.ResourceSummary != nil {
+				pods = st.ResourceSummary.RunningPods
+			}
+			fmt.Fprintf(w, "\t%s\t%d\t%d", orNone(st.Platform), len(st.Nodes), pods)
 		}
-		return fmt.Errorf("failed to get cluster persona: %s", outputStr)
+		fmt.Fprintf(w, "\t%s\n", formatAge(p.Metadata.CreationTimestamp))
 	}
-
-	displayClusterPersonaStatus(name, string(rawOutput))
-	return nil
+	return w.Flush()
 }
 
-func displayClusterPersonaStatus(name string, rawYAML string) {
-	output.Header(fmt.Sprintf("ClusterPersona: %s", name))
-
-	lines := strings.Split(rawYAML, "\n")
+func getClusterPersonaStatus(ctx context.Context, client *kube.Client, name string) error {
+	persona, err := client.GetClusterPersona(ctx, name)
+	if err != nil {
+		return clusterKubeError(err, name)
+	}
 
-	// Extract key information
-	var phase, kubeVersion, platform string
-	var nodeCount, appCount, runningPods int
-	var addons []string
+	if handled, err := printStructured(persona.Status, outputFormat); handled {
+		return err
+	}
 
-	inStatus := false
-	inNodes := false
-	inAddons := false
-	inResourceSummary := false
+	displayClusterPersonaStatus(persona, outputFormat == "wide")
+	return nil
+}
 
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
+// clusterKubeError turns kube client errors into user-facing messages
+func clusterKubeError(err error, name string) error {
+	switch {
+	case errors.Is(err, kube.ErrCRDNotInstalled):
+		return fmt.Errorf("ClusterPersona CRD is not installed on this cluster. Install it with: dorgu crd install")
+	case errors.Is(err, kube.ErrNotFound) && name != "":
+		return fmt.Errorf("ClusterPersona '%s' not found", name)
+	default:
+		return fmt.Errorf("kubectl failed: %w", err)
+	}
+}
 
-		if trimmed == "status:" {
-			inStatus = true
-			continue
-		}
+// displayClusterPersonaStatus prints cluster status in a human-friendly format.
+// wide adds per-node details and add-on namespaces/versions.
+func displayClusterPersonaStatus(persona *types.ClusterPersona, wide bool) {
+	name := persona.Metadata.Name
+	output.Header(fmt.Sprintf("ClusterPersona: %s", name))
 
-		if inStatus {
-			if strings.HasPrefix(trimmed, "phase:") {
-				phase = strings.TrimPrefix(trimmed, "phase:")
-				phase = strings.TrimSpace(phase)
-			}
-			if strings.HasPrefix(trimmed, "kubernetesVersion:") {
-				kubeVersion = strings.TrimPrefix(trimmed, "kubernetesVersion:")
-				kubeVersion = strings.TrimSpace(kubeVersion)
-			}
-			if strings.HasPrefix(trimmed, "platform:") {
-				platform = strings.TrimPrefix(trimmed, "platform:")
-				platform = strings.TrimSpace(platform)
-			}
-			if strings.HasPrefix(trimmed, "applicationCount:") {
-				fmt.Sscanf(trimmed, "applicationCount: %d", &appCount)
-			}
-			if trimmed == "nodes:" {
-				inNodes = true
-				continue
-			}
-			if inNodes && strings.HasPrefix(trimmed, "- name:") {
-				nodeCount++
-			}
-			if inNodes && !strings.HasPrefix(trimmed, "-") && !strings.HasPrefix(trimmed, " ") && trimmed != "" {
-				inNodes = false
-			}
-			if trimmed == "addons:" {
-				inAddons = true
-				continue
-			}
-			if inAddons && strings.HasPrefix(trimmed, "- name:") {
-				addonName := strings.TrimPrefix(trimmed, "- name:")
-				addonName = strings.TrimSpace(addonName)
-				addons = append(addons, addonName)
-			}
-			if inAddons && !strings.HasPrefix(trimmed, "-") && !strings.HasPrefix(trimmed, " ") && trimmed != "" {
-				inAddons = false
-			}
-			if trimmed == "resourceSummary:" {
-				inResourceSummary = true
-				continue
-			}
-			if inResourceSummary && strings.HasPrefix(trimmed, "runningPods:") {
-				fmt.Sscanf(trimmed, "runningPods: %d", &runningPods)
-			}
-		}
+	st := persona.Status
+	if st == nil {
+		st = &types.ClusterPersonaStatus{}
+	}
+	runningPods := 0
+	if st.ResourceSummary != nil {
+		runningPods = st.ResourceSummary.RunningPods
 	}
 
 	// Display summary
-	fmt.Println()
 	output.Info("Cluster Overview")
-	fmt.Printf("  Phase:              %s\n", colorPhase(phase))
-	fmt.Printf("  Kubernetes Version: %s\n", kubeVersion)
-	fmt.Printf("  Platform:           %s\n", platform)
-	fmt.Printf("  Nodes:              %d\n", nodeCount)
+	fmt.Printf("  Phase:              %s\n", colorPhase(st.Phase))
+	fmt.Printf("  Kubernetes Version: %s\n", st.KubernetesVersion)
+	fmt.Printf("  Platform:           %s\n", st.Platform)
+	fmt.Printf("  Nodes:              %d\n", len(st.Nodes))
 	fmt.Printf("  Running Pods:       %d\n", runningPods)
-	fmt.Printf("  Applications:       %d\n", appCount)
+	fmt.Printf("  Applications:       %d\n", st.ApplicationCount)
+	if wide && st.ResourceSummary != nil {
+		fmt.Printf("  Total CPU:          %s\n", st.ResourceSummary.TotalCPU)
+		fmt.Printf("  Total Memory:       %s\n", st.ResourceSummary.TotalMemory)
+	}
 
-	if len(addons) > 0 {
+	if wide && len(st.Nodes) > 0 {
+		fmt.Println()
+		output.Info("Nodes")
+		for _, node := range st.Nodes {
+			ready := output.Green("Ready")
+			if !node.Ready {
+				ready = output.Red("NotReady")
+			}
+			fmt.Printf("  • %s  %s  %s  %s\n", node.Name, ready, strings.Join(node.Roles, ","), node.KubeletVersion)
+		}
+	}
+
+	if len(st.Addons) > 0 {
 		fmt.Println()
 		output.Info("Discovered Add-ons")
-		for _, addon := range addons {
-			fmt.Printf("  • %s\n", addon)
+		for _, addon := range st.Addons {
+			if wide {
+				fmt.Printf("  • %s (%s) %s\n", addon.Name, addon.Namespace, addon.Version)
+			} else {
+				fmt.Printf("  • %s\n", addon.Name)
+			}
 		}
 	}
 
 	fmt.Println()
-	output.Dim("Use 'kubectl get clusterpersona " + name + " -o yaml' for full details")
+	output.Dim("Use 'dorgu cluster status " + name + " -o yaml' for full details")
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
 }
 
 func colorPhase(phase string) string {
@@ -241,13 +257,29 @@ func colorPhase(phase string) string {
 }
 
 func runClusterInit(cmd *cobra.Command, args []string) error {
-	// Check kubectl availability
-	if _, err := exec.LookPath("kubectl"); err != nil {
-		return fmt.Errorf("kubectl not found in PATH; required for cluster init")
+	ctx := cmd.Context()
+	client, err := kube.NewClient()
+	if err != nil {
+		return fmt.Errorf("%w; required for cluster init", err)
+	}
+
+	var facts *types.ClusterFacts
+	if !clusterFlags.skipDiscovery {
+		output.Info("Discovering cluster facts...")
+		facts, err = client.DiscoverCluster(ctx)
+		if err != nil {
+			output.Warn(fmt.Sprintf("Cluster discovery failed, using defaults: %v", err))
+			facts = nil
+		} else {
+			printClusterFacts(facts)
+		}
 	}
 
 	// Generate ClusterPersona YAML
-	clusterPersonaYAML := generateClusterPersonaYAML(clusterFlags.name, clusterFlags.environment)
+	clusterPersonaYAML, err := generateClusterPersonaYAML(clusterFlags.name, clusterFlags.environment, facts)
+	if err != nil {
+		return fmt.Errorf("failed to generate ClusterPersona: %w", err)
+	}
 
 	if clusterFlags.dryRun {
 		fmt.Println(clusterPersonaYAML)
@@ -256,11 +288,7 @@ func runClusterInit(cmd *cobra.Command, args []string) error {
 
 	// Apply via kubectl
 	output.Info("Creating ClusterPersona...")
-	kubectlCmd := exec.Command("kubectl", "apply", "-f", "-")
-	kubectlCmd.Stdin = bytes.NewBufferString(clusterPersonaYAML)
-	kubectlCmd.Stdout = os.Stdout
-	kubectlCmd.Stderr = os.Stderr
-	if err := kubectlCmd.Run(); err != nil {
+	if _, err := client.RunWithInput(ctx, []byte(clusterPersonaYAML), "apply", "-f", "-"); err != nil {
 		return fmt.Errorf("kubectl apply failed: %w", err)
 	}
 
@@ -269,25 +297,51 @@ func runClusterInit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func generateClusterPersonaYAML(name, environment string) string {
-	return fmt.Sprintf(`apiVersion: dorgu.io/v1
-kind: ClusterPersona
-metadata:
-  name: %s
-spec:
-  name: %s
-  description: "Kubernetes cluster managed by Dorgu"
-  environment: %s
-  policies:
-    security:
-      enforceNonRoot: true
-      disallowPrivileged: true
-      podSecurityStandard: baseline
-  conventions:
-    requiredLabels:
-      - app.kubernetes.io/name
-      - app.kubernetes.io/version
-  defaults:
-    namespace: default
-`, name, name, environment)
+func printClusterFacts(facts *types.ClusterFacts) {
+	platform := facts.Platform
+	if platform == "" {
+		platform = "unknown"
+	}
+	fmt.Printf("  Kubernetes Version: %s\n", facts.KubernetesVersion)
+	fmt.Printf("  Platform:           %s\n", platform)
+	fmt.Printf("  Nodes:              %d\n", facts.NodeCount)
+	if len(facts.Addons) > 0 {
+		names := make([]string, 0, len(facts.Addons))
+		for _, addon := range facts.Addons {
+			names = append(names, addon.Name)
+		}
+		fmt.Printf("  Add-ons:            %s\n", strings.Join(names, ", "))
+	}
+	fmt.Println()
+}
+
+func generateClusterPersonaYAML(name, environment string, facts *types.ClusterFacts) (string, error) {
+	persona := types.ClusterPersona{
+		APIVersion: types.PersonaAPIVersion,
+		Kind:       types.ClusterPersonaKind,
+		Metadata:   types.PersonaMetadata{Name: name},
+		Spec: types.ClusterPersonaSpec{
+			Name:        name,
+			Description: "Kubernetes cluster managed by Dorgu",
+			Environment: environment,
+			Discovered:  facts,
+			Policies: &types.ClusterPolicies{
+				Security: &types.ClusterSecurityPolicy{
+					EnforceNonRoot:      true,
+					DisallowPrivileged:  true,
+					PodSecurityStandard: "baseline",
+				},
+			},
+			Conventions: &types.ClusterConventions{
+				RequiredLabels: []string{"app.kubernetes.io/name", "app.kubernetes.io/version"},
+			},
+			Defaults: &types.ClusterPersonaDefault{Namespace: "default"},
+		},
+	}
+
+	data, err := yaml.Marshal(persona)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
 }
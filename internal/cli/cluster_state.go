@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/kube"
+)
+
+// loadClusterState reads what generate --against-cluster validates the
+// manifests for namespace against from the current kubeconfig context's
+// cluster
+func loadClusterState(ctx context.Context, namespace string) (*generator.ClusterState, error) {
+	client, err := kube.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("%w; required for --against-cluster", err)
+	}
+	state := &generator.ClusterState{CertManager: true}
+	if state.APIVersions, err = client.APIVersions(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list the cluster's API versions: %w", err)
+	}
+	if state.NamespaceExists, err = client.NamespaceExists(ctx, namespace); err != nil {
+		return nil, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	classes, err := client.ListStorageClasses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list StorageClasses: %w", err)
+	}
+	for _, c := range classes {
+		state.StorageClasses = append(state.StorageClasses, c.Name)
+		if c.Default {
+			state.DefaultStorageClass = c.Name
+		}
+	}
+	if state.IngressClasses, err = client.ListIngressClasses(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list IngressClasses: %w", err)
+	}
+	issuers, err := client.ListClusterIssuers(ctx)
+	switch {
+	case errors.Is(err, kube.ErrCRDNotInstalled):
+		state.CertManager = false
+	case err != nil:
+		return nil, fmt.Errorf("failed to list ClusterIssuers: %w", err)
+	}
+	state.ClusterIssuers = issuers
+	return state, nil
+}
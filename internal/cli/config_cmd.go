@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -69,11 +70,17 @@ func init() {
 }
 
 func runConfigList(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat, "", "json", "yaml"); err != nil {
+		return err
+	}
 	cfg, err := config.LoadGlobalConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 	entries := cfg.ListAll()
+	if handled, err := printStructured(entries, outputFormat); handled {
+		return err
+	}
 	fmt.Println("Dorgu Configuration")
 	fmt.Println("====================")
 	fmt.Printf("Config file: %s\n\n", config.GlobalConfigPath())
@@ -129,6 +136,9 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 	if args[0] == "llm.api_key" && len(args[1]) > 8 {
 		displayVal = args[1][:4] + "****" + args[1][len(args[1])-4:]
 	}
+	if strings.HasPrefix(args[0], "git.") {
+		displayVal, _ = cfg.Get(args[0])
+	}
 	output.Success(fmt.Sprintf("Set %s = %s", args[0], displayVal))
 	return nil
 }
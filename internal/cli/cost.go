@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var costFlags struct {
+	name        string
+	llmProvider string
+	cloud       string
+}
+
+var costCmd = &cobra.Command{
+	Use:   "cost [path]",
+	Short: "Estimate the monthly cost of an application",
+	Long: `Estimate the monthly compute cost of an application from its resource
+requests and limits, replicas, and HPA bounds.
+
+For each environment, three figures are shown:
+  BASELINE  requests × min replicas (what the scheduler reserves at rest)
+  PEAK      requests × HPA max replicas
+  CEILING   limits × HPA max replicas
+
+Prices default to approximate on-demand list prices for aws, gcp, or azure.
+Set cost.cloud, cost.cpu_per_hour, cost.gib_per_hour, and cost.environments in
+.dorgu.yaml to match your contract and environments. The estimate is also
+added to the Resource Profile section of PERSONA.md.
+
+Examples:
+  dorgu cost .
+  dorgu cost ./my-app --cloud gcp
+  dorgu cost ./my-app -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCost,
+}
+
+func init() {
+	costCmd.Flags().StringVar(&costFlags.name, "name", "", "override application name")
+	costCmd.Flags().StringVar(&costFlags.llmProvider, "llm-provider", "", "LLM provider: openai, anthropic, gemini, ollama (default from config)")
+	costCmd.Flags().StringVar(&costFlags.cloud, "cloud", "", "price table: aws, gcp, azure (overrides cost.cloud)")
+}
+
+func runCost(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat, "", "json", "yaml"); err != nil {
+		return err
+	}
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("path does not exist: %s", absPath)
+	}
+
+	gen, err := generateApp(cmd.Context(), absPath, generateOptions{
+		name:           costFlags.name,
+		llmProvider:    costFlags.llmProvider,
+		skipPersona:    true,
+		skipValidation: true,
+	})
+	if err != nil {
+		return err
+	}
+	if costFlags.cloud != "" {
+		gen.config.Cost.Cloud = costFlags.cloud
+	}
+	est, err := generator.EstimateCost(gen.analysis, gen.config)
+	if err != nil {
+		return err
+	}
+
+	if handled, err := printStructured(est, outputFormat); handled {
+		return err
+	}
+
+	output.Header(fmt.Sprintf("Estimated monthly cost: %s", est.App))
+	printField("Per pod", fmt.Sprintf("requests %.2f CPU / %.2f GiB, limits %.2f CPU / %.2f GiB",
+		est.Requests.CPU, est.Requests.Memory, est.Limits.CPU, est.Limits.Memory))
+	printField("Prices", fmt.Sprintf("$%.4f per vCPU-hour, $%.4f per GiB-hour (%s)",
+		est.Prices.CPUPerHour, est.Prices.GiBPerHour, est.Cloud))
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ENVIRONMENT\tREPLICAS\tBASELINE\tPEAK\tCEILING")
+	for _, env := range est.Environments {
+		replicas := fmt.Sprintf("%d", env.MinReplicas)
+		if env.MaxReplicas != env.MinReplicas {
+			replicas = fmt.Sprintf("%d-%d", env.MinReplicas, env.MaxReplicas)
+		}
+		fmt.Fprintf(w, "%s\t%s\t$%.2f\t$%.2f\t$%.2f\n", env.Name, replicas, env.Baseline, env.Peak, env.Ceiling)
+	}
+	w.Flush()
+
+	fmt.Println()
+	output.Dim("Compute only; excludes storage, egress, load balancers, and node overhead")
+	return nil
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/kube"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var crdFlags struct {
+	dryRun bool
+	yes    bool
+}
+
+var crdCmd = &cobra.Command{
+	Use:   "crd",
+	Short: "Install and inspect the Dorgu CRDs",
+	Long: `Manage the dorgu.io CustomResourceDefinitions (ApplicationPersona and
+ClusterPersona) bundled with this CLI.
+
+Examples:
+  # Install or upgrade the CRDs on the current cluster
+  dorgu crd install
+
+  # Check which CRDs are installed
+  dorgu crd status`,
+}
+
+var crdInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install or upgrade the bundled CRDs",
+	Long: `Apply the ApplicationPersona and ClusterPersona CRDs bundled with this
+CLI to the current cluster and wait until they are established.
+
+Examples:
+  dorgu crd install
+  dorgu crd install --dry-run > dorgu-crds.yaml`,
+	Args: cobra.NoArgs,
+	RunE: runCRDInstall,
+}
+
+var crdStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the Dorgu CRDs are installed",
+	Args:  cobra.NoArgs,
+	RunE:  runCRDStatus,
+}
+
+var crdUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the Dorgu CRDs from the cluster",
+	Long: `Delete the dorgu.io CRDs. This also deletes every ApplicationPersona and
+ClusterPersona on the cluster.
+
+Examples:
+  dorgu crd uninstall
+  dorgu crd uninstall --yes`,
+	Args: cobra.NoArgs,
+	RunE: runCRDUninstall,
+}
+
+func init() {
+	crdInstallCmd.Flags().BoolVar(&crdFlags.dryRun, "dry-run", false, "print the CRD manifests without applying")
+	crdUninstallCmd.Flags().BoolVarP(&crdFlags.yes, "yes", "y", false, "uninstall without asking for confirmation")
+
+	crdCmd.AddCommand(crdInstallCmd)
+	crdCmd.AddCommand(crdStatusCmd)
+	crdCmd.AddCommand(crdUninstallCmd)
+}
+
+func runCRDInstall(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	crds, err := kube.BundledCRDs()
+	if err != nil {
+		return fmt.Errorf("failed to load bundled CRDs: %w", err)
+	}
+
+	if crdFlags.dryRun {
+		for i, crd := range crds {
+			if i > 0 {
+				fmt.Println("---")
+			}
+			fmt.Print(string(crd.Manifest))
+		}
+		return nil
+	}
+
+	client, err := kube.NewClient()
+	if err != nil {
+		return fmt.Errorf("%w; required for crd install", err)
+	}
+
+	for _, crd := range crds {
+		output.Info(fmt.Sprintf("Installing %s...", crd.Name))
+		if err := client.InstallCRD(ctx, crd); err != nil {
+			return fmt.Errorf("failed to install %s: %w", crd.Name, err)
+		}
+	}
+
+	output.Success("Dorgu CRDs installed")
+	return nil
+}
+
+func runCRDStatus(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	crds, err := kube.BundledCRDs()
+	if err != nil {
+		return fmt.Errorf("failed to load bundled CRDs: %w", err)
+	}
+
+	client, err := kube.NewClient()
+	if err != nil {
+		return fmt.Errorf("%w; required for crd status", err)
+	}
+
+	output.Header("Dorgu CRDs")
+	missing := 0
+	for _, crd := range crds {
+		status, err := client.GetCRDStatus(ctx, crd.Name)
+		if err != nil {
+			return fmt.Errorf("failed to get %s: %w", crd.Name, err)
+		}
+		switch {
+		case !status.Installed:
+			missing++
+			fmt.Printf("  %s  %s\n", output.Red("✗"), crd.Name)
+		case !status.Established:
+			fmt.Printf("  %s  %s (not established yet)\n", output.Yellow("…"), crd.Name)
+		default:
+			fmt.Printf("  %s  %s (stored versions: %s)\n", output.Green("✓"), crd.Name, strings.Join(status.StoredVersions, ", "))
+		}
+	}
+
+	if missing > 0 {
+		fmt.Println()
+		output.Dim("Install missing CRDs with: dorgu crd install")
+	}
+	return nil
+}
+
+func runCRDUninstall(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	crds, err := kube.BundledCRDs()
+	if err != nil {
+		return fmt.Errorf("failed to load bundled CRDs: %w", err)
+	}
+
+	client, err := kube.NewClient()
+	if err != nil {
+		return fmt.Errorf("%w; required for crd uninstall", err)
+	}
+
+	if !crdFlags.yes {
+		output.Warn("This deletes every ApplicationPersona and ClusterPersona on the cluster.")
+		ok, err := confirm("Uninstall the Dorgu CRDs?")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			output.Warn("Uninstall cancelled")
+			return nil
+		}
+	}
+
+	for _, crd := range crds {
+		status, err := client.GetCRDStatus(ctx, crd.Name)
+		if err != nil {
+			return fmt.Errorf("failed to get %s: %w", crd.Name, err)
+		}
+		if !status.Installed {
+			output.Dim(fmt.Sprintf("  %s is not installed", crd.Name))
+			continue
+		}
+		if err := client.UninstallCRD(ctx, crd.Name); err != nil {
+			return fmt.Errorf("failed to uninstall %s: %w", crd.Name, err)
+		}
+		output.Success(fmt.Sprintf("Removed %s", crd.Name))
+	}
+	return nil
+}
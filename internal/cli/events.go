@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/kube"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var eventsFlags struct {
+	namespace   string
+	kubeContext string
+	watch       bool
+	warnings    bool
+}
+
+var eventsCmd = &cobra.Command{
+	Use:   "events <app>",
+	Short: "Show and stream the Kubernetes events of an app",
+	Long: `Show the recent events about an app's objects, then stream new ones.
+
+The app's objects are the ones named after it (Deployment, Service, HPA,
+Ingress, ...) and the ones named with it as a prefix (its ReplicaSets,
+pods, and Jobs), so scheduling failures, image pull errors, OOM kills, and
+probe failures show up without building a field selector.
+
+Examples:
+  dorgu events orders -n production
+  dorgu events orders -n production --warnings --watch=false`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEvents,
+}
+
+func init() {
+	f := eventsCmd.Flags()
+	f.StringVarP(&eventsFlags.namespace, "namespace", "n", "default", "namespace of the app")
+	f.StringVar(&eventsFlags.kubeContext, "context", "", "kubeconfig context (default: the current context)")
+	f.BoolVarP(&eventsFlags.watch, "watch", "w", true, "keep streaming new events until Ctrl+C")
+	f.BoolVar(&eventsFlags.warnings, "warnings", false, "only Warning events")
+}
+
+func runEvents(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	name := args[0]
+	client, err := kube.NewClientForContext(eventsFlags.kubeContext)
+	if err != nil {
+		return fmt.Errorf("%w; required for events", err)
+	}
+	events, err := client.ListEvents(ctx, eventsFlags.namespace)
+	if err != nil {
+		return fmt.Errorf("failed to list events in %s: %w", eventsFlags.namespace, err)
+	}
+
+	// Fixed columns, since watched events arrive one at a time
+	fmt.Printf("%-6s %-8s %-24s %-40s %s\n", "AGE", "TYPE", "REASON", "OBJECT", "MESSAGE")
+	show := func(e kube.Event) {
+		if !e.IsAbout(name) || eventsFlags.warnings && e.Type != "Warning" {
+			return
+		}
+		fmt.Printf("%-6s %s %-24s %-40s %s\n", formatAge(e.Time().Format(time.RFC3339)), colorEventType(e.Type),
+			e.Reason, e.InvolvedObject.Kind+"/"+e.InvolvedObject.Name, e.Message)
+	}
+	for _, e := range events {
+		show(e)
+	}
+	if !eventsFlags.watch {
+		return nil
+	}
+	return client.WatchEvents(ctx, eventsFlags.namespace, show)
+}
+
+// colorEventType pads the event type to its column and highlights Warning
+// events
+func colorEventType(t string) string {
+	padded := fmt.Sprintf("%-8s", t)
+	if t == "Warning" {
+		return output.Yellow(padded)
+	}
+	return padded
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/kube"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var execFlags struct {
+	namespace   string
+	kubeContext string
+	container   string
+}
+
+var execCmd = &cobra.Command{
+	Use:   "exec <app> [-- command...]",
+	Short: "Run a command in a ready pod of an app, using its persona",
+	Long: `Run a command, or a shell by default, in one of an app's ready pods.
+
+The pod is picked by the labels of the app's ApplicationPersona, and the
+command runs in the app's own container rather than a sidecar unless -c
+names another one. A terminal is attached when stdin is one.
+
+Examples:
+  dorgu exec orders -n production
+  dorgu exec orders -n production -- env
+  dorgu exec orders -c envoy -- wget -qO- localhost:9901/ready`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runExec,
+}
+
+func init() {
+	f := execCmd.Flags()
+	f.StringVarP(&execFlags.namespace, "namespace", "n", "default", "namespace of the app and its persona")
+	f.StringVar(&execFlags.kubeContext, "context", "", "kubeconfig context (default: the current context)")
+	f.StringVarP(&execFlags.container, "container", "c", "", "container to run in (default: the app's)")
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	command := args[1:]
+	if len(command) == 0 {
+		command = []string{"sh"}
+	}
+	client, err := kube.NewClientForContext(execFlags.kubeContext)
+	if err != nil {
+		return fmt.Errorf("%w; required for exec", err)
+	}
+	persona, pod, err := personaPod(cmd.Context(), client, execFlags.namespace, args[0])
+	if err != nil {
+		return err
+	}
+	container := execFlags.container
+	if container == "" && slices.Contains(pod.Containers, persona.Spec.Name) {
+		container = persona.Spec.Name
+	}
+	if container != "" && !slices.Contains(pod.Containers, container) {
+		return fmt.Errorf("pod %s has no container %q (it has %v)", pod.Name, container, pod.Containers)
+	}
+
+	output.Dim(fmt.Sprintf("Running in %s", pod.Name))
+	err = client.Exec(cmd.Context(), execFlags.namespace, pod.Name, container, isInteractive(), command)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("%s exited with status %d", command[0], exitErr.ExitCode())
+	}
+	return err
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var explainRuleCmd = &cobra.Command{
+	Use:   "explain-rule [id]",
+	Short: "Explain a validation rule, or list them all",
+	Long: `Explain what a validation rule checks, why its findings matter, and how
+to fix them. Without an ID, list every rule.
+
+Rule IDs appear in generate's validation report, and .dorgu-suppressions.yaml
+refers to them.
+
+Examples:
+  dorgu explain-rule DORGU-ING-003
+  dorgu explain-rule
+  dorgu explain-rule -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExplainRule,
+}
+
+func runExplainRule(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat, "", "json", "yaml"); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		rules := generator.Rules()
+		if handled, err := printStructured(rules, outputFormat); handled {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSEVERITY\tCATEGORY\tTITLE")
+		for _, r := range rules {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.ID, r.Severity, r.Category, r.Title)
+		}
+		return w.Flush()
+	}
+
+	rule, ok := generator.LookupRule(args[0])
+	if !ok {
+		return fmt.Errorf("unknown rule %q; run dorgu explain-rule to list them", args[0])
+	}
+	if handled, err := printStructured(rule, outputFormat); handled {
+		return err
+	}
+	output.Header(fmt.Sprintf("%s: %s", rule.ID, rule.Title))
+	printField("Severity", colorSeverity(string(rule.Severity)))
+	printField("Category", rule.Category)
+	printField("Docs", rule.DocURL())
+	fmt.Println()
+	fmt.Println(wrap(rule.Description, 76, "  "))
+	return nil
+}
+
+// wrap breaks text into lines of at most width characters, each prefixed
+// with indent
+func wrap(text string, width int, indent string) string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, indent+line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, indent+line)
+	}
+	return strings.Join(lines, "\n")
+}
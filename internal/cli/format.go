@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+// printStructured prints v as YAML or JSON. It reports false for any other
+// format so callers can fall back to their human-readable rendering.
+func printStructured(v interface{}, format string) (bool, error) {
+	var data []byte
+	var err error
+	switch format {
+	case "yaml":
+		data, err = yaml.Marshal(v)
+	case "json":
+		data, err = json.MarshalIndent(v, "", "  ")
+		data = append(data, '\n')
+	default:
+		return false, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("failed to render %s: %w", format, err)
+	}
+	fmt.Print(string(data))
+	return true, nil
+}
+
+// validateOutputFormat checks a -o value against the supported formats
+func validateOutputFormat(format string, supported ...string) error {
+	for _, s := range supported {
+		if format == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported output format %q (supported: %v)", format, supported)
+}
+
+// isStructuredOutput reports whether the global -o flag asks for JSON or YAML
+func isStructuredOutput() bool {
+	return outputFormat == "json" || outputFormat == "yaml"
+}
+
+// legacyOutputDir supports the older usage where -o/--output named the
+// output directory of generate commands. Values that are not an output
+// format are taken as the directory, with a deprecation warning.
+func legacyOutputDir(dir string) string {
+	switch outputFormat {
+	case "", "json", "yaml":
+		return dir
+	}
+	output.Warn("Passing a directory to -o/--output is deprecated; use --output-dir")
+	dir, outputFormat = outputFormat, ""
+	return dir
+}
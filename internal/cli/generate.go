@@ -1,60 +1,361 @@
 package cli
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
+	neturl "net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"sigs.k8s.io/yaml"
 
 	"github.com/dorgu-ai/dorgu/internal/analyzer"
 	"github.com/dorgu-ai/dorgu/internal/config"
 	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/llm"
 	"github.com/dorgu-ai/dorgu/internal/output"
+	"github.com/dorgu-ai/dorgu/internal/owners"
+	"github.com/dorgu-ai/dorgu/internal/progress"
+	"github.com/dorgu-ai/dorgu/internal/types"
+	"github.com/dorgu-ai/dorgu/internal/vcs"
 )
 
-var generateFlags struct {
-	output         string
+// printDryRun writes the Kubernetes manifests among files to stdout as one
+// multi-document YAML stream (or the program's main file with a program
+// --format), and the file names and remaining files (docs, CI workflow,
+// ArgoCD Application, kustomization) to stderr
+func printDryRun(stdout, stderr io.Writer, files []generator.GeneratedFile, format string) {
+	for _, f := range files {
+		content := strings.TrimSuffix(f.Content, "\n") + "\n"
+		if f.Path == generator.ProgramFile(format) {
+			fmt.Fprintf(stderr, "# %s\n", f.Path)
+			fmt.Fprint(stdout, content)
+			continue
+		}
+		if generator.IsManifest(f) {
+			fmt.Fprintf(stderr, "# %s\n", f.Path)
+			fmt.Fprint(stdout, "---\n"+content)
+			continue
+		}
+		fmt.Fprintf(stderr, "--- %s ---\n%s\n", f.Path, content)
+	}
+}
+
+// appSource names the app in file headers: its path relative to the git
+// repository root, or its directory name outside a repository
+func appSource(ctx context.Context, absPath string) string {
+	if repo, err := vcs.Open(ctx, absPath); err == nil {
+		if rel, err := filepath.Rel(repo.Root, absPath); err == nil && rel != "." && filepath.IsLocal(rel) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.Base(absPath)
+}
+
+// isGitURL reports whether a generate target is a repository URL rather than
+// a path
+func isGitURL(target string) bool {
+	for _, prefix := range []string{"https://", "http://", "ssh://", "git@"} {
+		if strings.HasPrefix(target, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneTarget shallow-clones a git URL target into a temporary directory
+// named after the repository, so the app keeps its name. A ?ref= query
+// selects the branch or tag. cleanup removes the clone.
+func cloneTarget(ctx context.Context, target string) (dir string, cleanup func(), err error) {
+	url, ref := target, ""
+	if i := strings.LastIndex(target, "?"); i >= 0 {
+		query, err := neturl.ParseQuery(target[i+1:])
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid query in %s: %w", target, err)
+		}
+		url, ref = target[:i], query.Get("ref")
+	}
+
+	tmp, err := os.MkdirTemp("", "dorgu-clone-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup = func() { os.RemoveAll(tmp) }
+	dir = filepath.Join(tmp, vcs.RepoName(url))
+
+	s := newSpinner(" Cloning " + url + "...")
+	s.Start()
+	err = vcs.Clone(ctx, url, ref, dir)
+	s.Stop()
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return dir, cleanup, nil
+}
+
+// outputRelPath returns path, given relative to the working directory, as a
+// slash-separated path relative to outputDir ("" stays "")
+func outputRelPath(outputDir, path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	absOut, err := filepath.Abs(outputDir)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absOut, absPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// consentOutsideWrites returns the generated files to write. Files outside
+// outputDir need consent: destinations given with --ci-output or
+// --persona-output count as consent, --yes or a confirmation covers the
+// rest, and otherwise they are skipped with a warning.
+func consentOutsideWrites(outputDir string, gen *generation, opts generateOptions) ([]generator.GeneratedFile, error) {
+	explicit := map[string]bool{}
+	if opts.ciOutput != "" {
+		explicit[gen.opts.CIPath] = true
+	}
+	if opts.personaOutput != "" {
+		explicit[gen.opts.PersonaPath] = true
+	}
+	var unasked []string
+	for _, path := range output.OutsideDir(gen.files) {
+		if !explicit[path] {
+			unasked = append(unasked, path)
+		}
+	}
+	if len(unasked) == 0 || opts.yes {
+		return gen.files, nil
+	}
+
+	if isInteractive() && !isStructuredOutput() {
+		output.Info(fmt.Sprintf("These files are written outside %s:", outputDir))
+		for _, path := range unasked {
+			fmt.Printf("  %s\n", filepath.Join(outputDir, path))
+		}
+		ok, err := confirm("Write them?")
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return gen.files, nil
+		}
+	}
+
+	skip := map[string]bool{}
+	for _, path := range unasked {
+		skip[path] = true
+		output.Warn(fmt.Sprintf("Skipping %s: outside %s (pass --yes, or choose a destination with --ci-output/--persona-output)", filepath.Join(outputDir, path), outputDir))
+	}
+	var files []generator.GeneratedFile
+	for _, f := range gen.files {
+		if !skip[f.Path] {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// confirmOverwrites asks before overwriting files that differ from the
+// generated ones, unless force is set. Files dorgu wrote and nobody edited
+// since (per dorgu.lock) are overwritten without asking. Without a terminal
+// it fails instead, so scripts must pass --force.
+func confirmOverwrites(outputDir string, files []generator.GeneratedFile, force bool) (bool, error) {
+	if force {
+		return true, nil
+	}
+	paths, err := output.Overwrites(outputDir, files)
+	if err != nil {
+		return false, err
+	}
+	if len(paths) == 0 {
+		return true, nil
+	}
+	if !isInteractive() || isStructuredOutput() {
+		return false, fmt.Errorf("%d existing files differ from the generated ones (%s); pass --force to overwrite them",
+			len(paths), strings.Join(paths, ", "))
+	}
+	output.Info("These existing files differ from the generated ones:")
+	for _, path := range paths {
+		fmt.Printf("  %s\n", filepath.Join(outputDir, path))
+	}
+	return confirm("Overwrite them?")
+}
+
+// printWriteSummary lists each written file with whether it was created,
+// updated, or left unchanged
+func printWriteSummary(outputDir string, files []generator.GeneratedFile, summary output.WriteSummary) {
+	for _, f := range files {
+		path := filepath.Join(outputDir, f.Path)
+		switch summary.Status(f.Path) {
+		case "created":
+			fmt.Printf("  %s %s\n", output.Green("created  "), path)
+		case "updated":
+			fmt.Printf("  %s %s\n", output.Yellow("updated  "), path)
+		default:
+			fmt.Printf("  %s %s\n", "unchanged", path)
+		}
+	}
+}
+
+// validateSingleFile checks that --single-file names a YAML file inside the
+// output directory
+func validateSingleFile(path string) error {
+	if path == "" {
+		return nil
+	}
+	ext := filepath.Ext(path)
+	if ext != ".yaml" && ext != ".yml" {
+		return fmt.Errorf("--single-file must be a .yaml or .yml file: %s", path)
+	}
+	if filepath.IsAbs(path) || !filepath.IsLocal(path) {
+		return fmt.Errorf("--single-file must be a path inside the output directory: %s", path)
+	}
+	return nil
+}
+
+// validateFormat checks --format, which cannot be combined with
+// --single-file since the program formats are not YAML
+func validateFormat(format, singleFile string) error {
+	switch format {
+	case generator.FormatYAML:
+		return nil
+	case generator.FormatTerraform, generator.FormatCDK8s, generator.FormatPulumi:
+		if singleFile != "" {
+			return fmt.Errorf("--single-file cannot be used with --format %s", format)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported --format %q (use yaml, terraform, cdk8s, or pulumi)", format)
+}
+
+// generateOptions are the inputs of a generate run
+type generateOptions struct {
+	outputDir      string
 	name           string
 	namespace      string
 	dryRun         bool
 	skipArgoCD     bool
 	skipCI         bool
 	skipPersona    bool
+	skipReadme     bool
+	polishReadme   bool
+	skipPlugins    bool
 	llmProvider    string
 	skipValidation bool
+	againstCluster bool
+	strict         bool
+	createPR       bool
+	notify         bool
+	reviewAnalysis bool
+	saveAnalysis   bool
+	prBase         string
+	deterministic  bool
+	singleFile     string
+	format         string
+	ciOutput       string
+	personaOutput  string
+	devEnv         []string
+	smokeTest      bool
+	yes            bool
+	force          bool
+	backup         bool
+	timings        bool
+	all            bool
+	full           bool
+	// sourceHash is recorded in dorgu.lock by --all runs
+	sourceHash string
 }
 
+var generateFlags generateOptions
+
 var generateCmd = &cobra.Command{
-	Use:   "generate [path]",
+	Use:   "generate [path|git-url]",
 	Short: "Generate Kubernetes manifests for an application",
 	Long: `Analyze a containerized application and generate production-ready
 Kubernetes manifests, ArgoCD configuration, CI/CD pipelines, and documentation.
 
-The path should point to a directory containing a Dockerfile or docker-compose.yml.
+The path should point to a directory containing a Dockerfile or docker-compose.yml,
+or be a git URL (https://, ssh://, or git@), which is shallow-cloned into a
+temporary directory; append ?ref=<branch> to clone a branch or tag.
 
 Examples:
   dorgu generate .
   dorgu generate ./my-app
-  dorgu generate ./my-app --output ./manifests
-  dorgu generate ./my-app --dry-run
-  dorgu generate ./my-app --skip-validation`,
+  dorgu generate ./my-app --output-dir ./manifests
+  dorgu generate https://github.com/org/service.git?ref=main
+  dorgu generate ./my-app --dry-run | kubectl apply -f -
+  dorgu generate ./my-app --single-file manifests.yaml
+  dorgu generate ./my-app --format terraform
+  dorgu generate ./my-app --format pulumi
+  dorgu generate ./my-app --ci-output .github/workflows/k8s.yaml --persona-output docs/PERSONA.md
+  dorgu generate ./my-app --force --backup
+  dorgu generate ./my-app --dev-env devcontainer,devfile
+  dorgu generate ./my-app --dev-env skaffold
+  dorgu generate ./my-app --skip-validation
+  dorgu generate ./my-app --against-cluster
+  dorgu generate ./my-app --strict
+  dorgu generate ./my-app --create-pr
+  dorgu generate ./my-app --create-pr --notify
+  dorgu generate ./my-app --deterministic
+  dorgu generate ./my-app --timings
+  dorgu generate ./services --all
+  dorgu generate ./my-app --review-analysis --save-analysis
+  dorgu generate ./my-app -o json`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runGenerate,
 }
 
 func init() {
-	generateCmd.Flags().StringVarP(&generateFlags.output, "output", "o", "./k8s", "output directory for generated files")
+	generateCmd.Flags().StringVar(&generateFlags.outputDir, "output-dir", "./k8s", "output directory for generated files")
 	generateCmd.Flags().StringVarP(&generateFlags.name, "name", "n", "", "override application name")
 	generateCmd.Flags().StringVar(&generateFlags.namespace, "namespace", "", "target Kubernetes namespace (overrides config)")
-	generateCmd.Flags().BoolVar(&generateFlags.dryRun, "dry-run", false, "print to stdout without writing files")
+	generateCmd.Flags().BoolVar(&generateFlags.dryRun, "dry-run", false, "print the manifests to stdout as multi-document YAML without writing files")
+	generateCmd.Flags().StringVar(&generateFlags.ciOutput, "ci-output", "", "path of the CI workflow (default: .github/workflows/deploy.yaml next to the output directory)")
+	generateCmd.Flags().StringVar(&generateFlags.personaOutput, "persona-output", "", "path of PERSONA.md (default: next to the output directory)")
+	generateCmd.Flags().StringSliceVar(&generateFlags.devEnv, "dev-env", nil, "also generate development environment files: devcontainer, devfile, skaffold, tilt (default: dev.files in .dorgu.yaml)")
+	generateCmd.Flags().BoolVar(&generateFlags.smokeTest, "smoke-test", false, "also generate jobs/smoke-test.yaml, a post-deploy Job requesting the readiness path and key routes (default: smoke_test.enabled in .dorgu.yaml)")
+	generateCmd.Flags().BoolVarP(&generateFlags.yes, "yes", "y", false, "write files outside the output directory without asking for confirmation")
+	generateCmd.Flags().BoolVar(&generateFlags.force, "force", false, "overwrite files that differ from the generated ones without asking for confirmation")
+	generateCmd.Flags().BoolVar(&generateFlags.backup, "backup", false, "keep the previous content of overwritten files in <file>.bak")
+	generateCmd.Flags().StringVar(&generateFlags.format, "format", generator.FormatYAML, "manifest format: yaml, or a terraform, cdk8s, or pulumi program declaring the manifests (no kustomization, ArgoCD, or CI workflow)")
+	generateCmd.Flags().StringVar(&generateFlags.singleFile, "single-file", "", "write the Kubernetes manifests to one multi-document file in the output directory (e.g. manifests.yaml)")
 	generateCmd.Flags().BoolVar(&generateFlags.skipArgoCD, "skip-argocd", false, "skip ArgoCD Application generation")
 	generateCmd.Flags().BoolVar(&generateFlags.skipCI, "skip-ci", false, "skip CI/CD workflow generation")
 	generateCmd.Flags().BoolVar(&generateFlags.skipPersona, "skip-persona", false, "skip persona document generation")
+	generateCmd.Flags().BoolVar(&generateFlags.skipReadme, "skip-readme", false, "skip the README.md documenting the output directory")
+	generateCmd.Flags().BoolVar(&generateFlags.polishReadme, "polish-readme", false, "have the LLM improve the wording of the generated README.md")
+	generateCmd.Flags().BoolVar(&generateFlags.skipPlugins, "skip-plugins", false, "skip the plugins configured in .dorgu.yaml")
 	generateCmd.Flags().StringVar(&generateFlags.llmProvider, "llm-provider", "", "LLM provider: openai, anthropic, gemini, ollama (default from config)")
 	generateCmd.Flags().BoolVar(&generateFlags.skipValidation, "skip-validation", false, "skip post-generation validation checks")
+	generateCmd.Flags().BoolVar(&generateFlags.againstCluster, "against-cluster", false, "also validate against the current kubeconfig context's cluster: its API versions, the namespace, storage and ingress classes, and the TLS ClusterIssuer")
+	generateCmd.Flags().BoolVar(&generateFlags.strict, "strict", false, "exit non-zero when validation finds warnings or errors (default: validation.fail_on in .dorgu.yaml)")
+	generateCmd.Flags().BoolVar(&generateFlags.createPR, "create-pr", false, "commit the generated files to a new branch and open a pull request")
+	generateCmd.Flags().BoolVar(&generateFlags.notify, "notify", false, "post a summary to the Slack/Teams webhooks under notifications in .dorgu.yaml")
+	generateCmd.Flags().BoolVar(&generateFlags.reviewAnalysis, "review-analysis", false, "show what the LLM changed in the analysis and accept or reject each field before generating")
+	generateCmd.Flags().BoolVar(&generateFlags.saveAnalysis, "save-analysis", false, "pin the analysis in "+analyzer.AnalysisFile+" so later runs reuse it instead of the LLM")
+	generateCmd.Flags().BoolVar(&generateFlags.deterministic, "deterministic", false, "byte-identical output for identical inputs: no timestamp unless SOURCE_DATE_EPOCH is set, LLM temperature 0, cached LLM responses")
+	generateCmd.Flags().BoolVar(&generateFlags.all, "all", false, "generate every app (directory with a Dockerfile or docker-compose file) under path into its own --output-dir, skipping apps whose inputs are unchanged since the last run")
+	generateCmd.Flags().BoolVar(&generateFlags.full, "full", false, "with --all, regenerate every app even when its inputs are unchanged")
+	generateCmd.Flags().BoolVar(&generateFlags.timings, "timings", false, "print how long each analysis, LLM, generation, and validation step took")
+	generateCmd.Flags().StringVar(&generateFlags.prBase, "pr-base", "", "branch the pull request targets (default: pull_request.base or the current branch)")
 }
 
 func runGenerate(cmd *cobra.Command, args []string) error {
@@ -62,6 +363,17 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	if len(args) > 0 {
 		targetPath = args[0]
 	}
+	if isGitURL(targetPath) {
+		if generateFlags.saveAnalysis {
+			return fmt.Errorf("--save-analysis cannot be used with a git URL")
+		}
+		dir, cleanup, err := cloneTarget(cmd.Context(), targetPath)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		targetPath = dir
+	}
 	absPath, err := filepath.Abs(targetPath)
 	if err != nil {
 		return fmt.Errorf("failed to resolve path: %w", err)
@@ -69,7 +381,167 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	if _, err := os.Stat(absPath); os.IsNotExist(err) {
 		return fmt.Errorf("path does not exist: %s", absPath)
 	}
+	outputDir := legacyOutputDir(generateFlags.outputDir)
+	if err := validateOutputFormat(outputFormat, "", "json", "yaml"); err != nil {
+		return err
+	}
+	if generateFlags.full && !generateFlags.all {
+		return fmt.Errorf("--full requires --all")
+	}
+	if (generateFlags.againstCluster || generateFlags.strict) && generateFlags.skipValidation {
+		return fmt.Errorf("--against-cluster and --strict cannot be used with --skip-validation")
+	}
+	if generateFlags.all {
+		opts := generateFlags
+		opts.outputDir = outputDir
+		if err := validateGenerateAll(opts); err != nil {
+			return err
+		}
+		if err := validateSingleFile(opts.singleFile); err != nil {
+			return err
+		}
+		if err := validateFormat(opts.format, opts.singleFile); err != nil {
+			return err
+		}
+		return runGenerateAll(cmd.Context(), absPath, opts)
+	}
+	if generateFlags.createPR && generateFlags.dryRun {
+		return fmt.Errorf("--create-pr cannot be used with --dry-run")
+	}
+	if generateFlags.notify && generateFlags.dryRun {
+		return fmt.Errorf("--notify cannot be used with --dry-run")
+	}
+	if generateFlags.saveAnalysis && generateFlags.dryRun {
+		return fmt.Errorf("--save-analysis cannot be used with --dry-run")
+	}
+	if generateFlags.reviewAnalysis {
+		if generateFlags.dryRun || isStructuredOutput() {
+			return fmt.Errorf("--review-analysis cannot be used with --dry-run or -o")
+		}
+		if !isInteractive() {
+			return fmt.Errorf("--review-analysis needs an interactive terminal")
+		}
+	}
+	if err := validateSingleFile(generateFlags.singleFile); err != nil {
+		return err
+	}
+	if err := validateFormat(generateFlags.format, generateFlags.singleFile); err != nil {
+		return err
+	}
+	// Keep stdout a valid YAML stream for kubectl
+	if generateFlags.dryRun {
+		output.SetMessageWriter(os.Stderr)
+	}
+
+	opts := generateFlags
+	opts.outputDir = outputDir
+	gen, err := generateApp(cmd.Context(), absPath, opts)
+	if err != nil {
+		return err
+	}
+	if !generateFlags.dryRun {
+		if gen.files, err = consentOutsideWrites(outputDir, gen, opts); err != nil {
+			return err
+		}
+		ok, err := confirmOverwrites(outputDir, gen.files, opts.force)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			output.Warn("Nothing written")
+			return nil
+		}
+	}
+	files, validation := gen.files, gen.validation
+	gateErr := validationGate(gen, opts.strict)
+
+	if isStructuredOutput() {
+		if err := printGenerateResult(cmd.Context(), absPath, outputDir, gen, gateErr == nil); err != nil {
+			return err
+		}
+		return gateErr
+	}
+
+	// Post-generation validation
+	if validation != nil {
+		report := os.Stdout
+		if generateFlags.dryRun {
+			report = os.Stderr
+		}
+		fmt.Fprintln(report)
+		if validation.Passed {
+			output.Success("Validation passed")
+		} else {
+			output.Warn("Validation found issues")
+		}
+		fmt.Fprintln(report, generator.FormatValidationReport(validation))
+		if output.InGitHubActions() {
+			output.WriteGitHubAnnotations(report, validation, annotationPaths(absPath, outputDir))
+		}
+	}
+
+	if generateFlags.dryRun {
+		printDryRun(os.Stdout, os.Stderr, files, generateFlags.format)
+	} else {
+		summary, err := output.WriteFiles(outputDir, files, output.WriteOptions{AllowOutside: true, Backup: generateFlags.backup})
+		if err != nil {
+			return fmt.Errorf("failed to write files: %w", err)
+		}
+		output.Success(fmt.Sprintf("Generated manifests successfully! %d created, %d updated, %d unchanged",
+			len(summary.Created), len(summary.Updated), len(summary.Unchanged)))
+		fmt.Println()
+		printWriteSummary(outputDir, files, summary)
+		if err := savePinnedAnalysis(absPath, gen); err != nil {
+			return err
+		}
+		// A failing gate stops before publishing the files
+		if gateErr != nil {
+			return gateErr
+		}
+		var prURL string
+		if generateFlags.createPR {
+			fmt.Println()
+			if prURL, err = createGeneratePR(cmd.Context(), absPath, outputDir, gen); err != nil {
+				return err
+			}
+			output.Success("Opened pull request: " + prURL)
+		}
+		if generateFlags.notify {
+			notifyGenerate(cmd.Context(), gen, prURL)
+		}
+	}
+
+	return gateErr
+}
+
+// generation is the outcome of analyzing and generating one application
+type generation struct {
+	analysis   *types.AppAnalysis
+	namespace  string
+	config     *config.Config
+	opts       generator.Options
+	files      []generator.GeneratedFile
+	validation *generator.ValidationResult
+	// pinned is the AnalysisFile to write, with --save-analysis
+	pinned []byte
+}
+
+// savePinnedAnalysis writes the AnalysisFile rendered with --save-analysis
+func savePinnedAnalysis(absPath string, gen *generation) error {
+	if gen.pinned == nil {
+		return nil
+	}
+	path := filepath.Join(absPath, analyzer.AnalysisFile)
+	if err := os.WriteFile(path, gen.pinned, 0o644); err != nil {
+		return fmt.Errorf("failed to save analysis: %w", err)
+	}
+	output.Info("Pinned the analysis in " + path)
+	return nil
+}
 
+// generateApp analyzes the application at absPath and runs the generators
+// and post-generation validation. Nothing is written to disk.
+func generateApp(ctx context.Context, absPath string, opts generateOptions) (*generation, error) {
 	// Config merge order: CLI flags > App .dorgu.yaml > Workspace .dorgu.yaml > Global > Defaults
 	globalCfg, err := config.LoadGlobalConfig()
 	if err != nil {
@@ -90,9 +562,15 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	if cfg.Org.Name == "" && globalCfg.Defaults.OrgName != "" {
 		cfg.Org.Name = globalCfg.Defaults.OrgName
 	}
+	if len(opts.devEnv) > 0 {
+		cfg.Dev.Files = opts.devEnv
+	}
+	if opts.smokeTest {
+		cfg.SmokeTest.Enabled = true
+	}
 
 	// CLI flag > global config > workspace config > default
-	effectiveProvider := globalCfg.GetEffectiveProvider(generateFlags.llmProvider)
+	effectiveProvider := globalCfg.GetEffectiveProvider(opts.llmProvider)
 	if effectiveProvider == "" {
 		effectiveProvider = cfg.LLM.Provider
 	}
@@ -100,7 +578,7 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		effectiveProvider = "openai"
 	}
 
-	effectiveNamespace := generateFlags.namespace
+	effectiveNamespace := opts.namespace
 	if effectiveNamespace == "" {
 		effectiveNamespace = globalCfg.Defaults.Namespace
 	}
@@ -108,74 +586,338 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		effectiveNamespace = "default"
 	}
 
-	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
-	s.Suffix = " Analyzing application..."
+	if opts.deterministic {
+		if err := llm.SetDeterministic(cfg.LLM.CacheDir); err != nil {
+			return nil, err
+		}
+	}
+
+	s := newSpinner(" Analyzing application...")
+	steps := newStepReporter(s)
+	ctx = progress.WithReporter(ctx, steps)
+	start := time.Now()
 	s.Start()
 
-	analysis, err := analyzer.Analyze(absPath, effectiveProvider)
+	// Analysis enhancement and persona generation run concurrently
+	pipeline, err := analyzer.RunPipeline(ctx, absPath, analyzer.PipelineOptions{
+		LLMProvider:       effectiveProvider,
+		Name:              opts.name,
+		Persona:           !opts.skipPersona,
+		DisabledAnalyzers: cfg.Analyzers.Disabled,
+		// Review compares against the analysis without the LLM
+		KeepDeterministic: opts.reviewAnalysis,
+	})
 	if err != nil {
 		s.Stop()
-		return fmt.Errorf("analysis failed: %w", err)
+		return nil, fmt.Errorf("analysis failed: %w", err)
 	}
-
-	// Git repo auto-detect: if repository not set, try git remote
-	if analysis.Repository == "" {
-		if gitURL := analyzer.DetectGitRemoteURL(absPath); gitURL != "" {
-			analysis.Repository = gitURL
+	analysis := pipeline.Analysis
+	if opts.reviewAnalysis {
+		s.Stop()
+		reviewAnalysis(pipeline)
+		confirmSchedules(analysis)
+		s.Start()
+	}
+	// Rendered now, before generation adjusts the analysis
+	var pinned []byte
+	if opts.saveAnalysis {
+		if pinned, err = analyzer.MarshalPinnedAnalysis(absPath, analysis); err != nil {
+			s.Stop()
+			return nil, err
 		}
 	}
 
-	if generateFlags.name != "" {
-		analysis.Name = generateFlags.name
+	ciPath, err := outputRelPath(opts.outputDir, opts.ciOutput)
+	if err != nil {
+		s.Stop()
+		return nil, fmt.Errorf("--ci-output: %w", err)
+	}
+	personaPath, err := outputRelPath(opts.outputDir, opts.personaOutput)
+	if err != nil {
+		s.Stop()
+		return nil, fmt.Errorf("--persona-output: %w", err)
 	}
-
-	s.Suffix = " Generating manifests..."
 
 	genOpts := generator.Options{
 		Namespace:   effectiveNamespace,
-		SkipArgoCD:  generateFlags.skipArgoCD,
-		SkipCI:      generateFlags.skipCI,
-		SkipPersona: generateFlags.skipPersona,
+		SkipArgoCD:  opts.skipArgoCD,
+		SkipCI:      opts.skipCI,
+		SkipPersona: opts.skipPersona,
+		SkipPlugins: opts.skipPlugins,
 		Config:      cfg,
+		Persona:     pipeline.Persona,
+		// Don't retry the persona LLM call sequentially if it already failed
+		NoLLMPersona: pipeline.PersonaErr != nil,
+		Provenance:   newProvenance(analysis, pipeline, effectiveProvider, opts.deterministic),
+		SourceHash:   opts.sourceHash,
+		SingleFile:   opts.singleFile,
+		Format:       opts.format,
+		CIPath:       ciPath,
+		PersonaPath:  personaPath,
+		Source:       appSource(ctx, absPath),
+		ManifestsDir: filepath.Base(filepath.Clean(opts.outputDir)),
+		SkipReadme:   opts.skipReadme,
+		PolishReadme: opts.polishReadme,
+	}
+	if cfg.Owners.Source != "" && !opts.skipValidation {
+		dir, err := owners.Load(ctx, cfg.Owners.Source, os.Getenv(cfg.Owners.TokenEnv))
+		if err != nil {
+			s.Stop()
+			return nil, err
+		}
+		genOpts.Owners = dir
+	}
+	if !opts.skipValidation {
+		if genOpts.Suppressions, err = generator.LoadSuppressions(absPath); err != nil {
+			s.Stop()
+			return nil, err
+		}
+	}
+	if opts.againstCluster && !opts.skipValidation {
+		state, err := loadClusterState(ctx, effectiveNamespace)
+		if err != nil {
+			s.Stop()
+			return nil, err
+		}
+		genOpts.Cluster = state
 	}
 
-	files, err := generator.Generate(analysis, genOpts)
+	files, err := generator.Generate(ctx, analysis, genOpts)
 	if err != nil {
 		s.Stop()
-		return fmt.Errorf("generation failed: %w", err)
+		return nil, fmt.Errorf("generation failed: %w", err)
 	}
 
 	s.Stop()
 
-	// Post-generation validation
-	if !generateFlags.skipValidation {
-		validation := generator.ValidateGenerated(analysis, files, genOpts)
-		fmt.Println()
-		if validation.Passed {
-			output.Success("Validation passed")
-		} else {
-			output.Warn("Validation found issues")
+	gen := &generation{analysis: analysis, namespace: effectiveNamespace, config: cfg, opts: genOpts, files: files, pinned: pinned}
+	if !opts.skipValidation {
+		done := progress.Start(ctx, "validate")
+		gen.validation = generator.ValidateGenerated(ctx, analysis, files, genOpts)
+		done()
+	}
+	if opts.timings {
+		printTimings(os.Stderr, steps.Slowest(), time.Since(start))
+	}
+	return gen, nil
+}
+
+// reviewAnalysis shows the fields the LLM changed next to their
+// deterministic values and reverts the ones the user rejects
+func reviewAnalysis(pipeline *analyzer.PipelineResult) {
+	if pipeline.EnhanceErr != nil {
+		output.Warn("LLM enhancement failed; using the deterministic analysis")
+		return
+	}
+	changes := analyzer.DiffAnalysis(pipeline.Deterministic, pipeline.Analysis)
+	if len(changes) == 0 {
+		output.Info("The LLM did not change the deterministic analysis")
+		return
+	}
+
+	output.Header("LLM changes to the analysis")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tDETERMINISTIC\tLLM")
+	for _, c := range changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.Field, orNone(c.Deterministic), orNone(c.Enhanced))
+	}
+	w.Flush()
+	fmt.Println()
+
+	reader := bufio.NewReader(os.Stdin)
+	var rejected []string
+	for _, c := range changes {
+		answer := prompt(reader, fmt.Sprintf("Use the LLM value for %s (y/n)", c.Field), "y")
+		if strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes") {
+			continue
 		}
-		fmt.Println(generator.FormatValidationReport(validation))
+		// Field names come from DiffAnalysis, so CopyField cannot fail
+		_ = analyzer.CopyField(pipeline.Analysis, pipeline.Deterministic, c.Field)
+		rejected = append(rejected, c.Field)
+	}
+	if len(rejected) > 0 {
+		output.Info("Kept the deterministic " + strings.Join(rejected, ", "))
 	}
+	fmt.Println()
+}
 
-	if generateFlags.dryRun {
-		for _, f := range files {
-			fmt.Printf("--- %s ---\n", f.Path)
-			fmt.Println(f.Content)
-			fmt.Println()
+// confirmSchedules asks, for each cron schedule the analysis found that no
+// job runs, whether to run it as a CronJob. Confirmed ones are added to the
+// app's jobs for this run, and the .dorgu.yaml entries that keep them are
+// printed.
+func confirmSchedules(analysis *types.AppAnalysis) {
+	hints := generator.UnconfiguredSchedules(analysis)
+	if len(hints) == 0 {
+		return
+	}
+	output.Header("Cron schedules found")
+	reader := bufio.NewReader(os.Stdin)
+	var jobs []types.JobContext
+	for _, h := range hints {
+		fmt.Printf("%s: %q in %s\n", h.Name, h.Schedule, h.Source)
+		answer := prompt(reader, "Run it as a CronJob (y/n)", "n")
+		if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+			continue
+		}
+		command := h.Command
+		if len(command) == 0 {
+			// The app schedules the task in-process
+			line := prompt(reader, "Command that runs the task once (empty to skip)", "")
+			if line == "" {
+				continue
+			}
+			command = []string{"/bin/sh", "-c", line}
+		}
+		job := types.JobContext{Name: h.Name, Command: command, Schedule: h.Schedule}
+		if err := generator.ValidateJob(job.Name, job.Command, job.Hook, job.Schedule); err != nil {
+			output.Warn(fmt.Sprintf("Skipping %s: %v", h.Name, err))
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	fmt.Println()
+	if len(jobs) == 0 {
+		return
+	}
+
+	if analysis.AppConfig == nil {
+		analysis.AppConfig = &types.AppConfigContext{}
+	}
+	analysis.AppConfig.Jobs = append(analysis.AppConfig.Jobs, jobs...)
+	entries := make([]map[string]interface{}, 0, len(jobs))
+	for _, j := range jobs {
+		entries = append(entries, map[string]interface{}{"name": j.Name, "schedule": j.Schedule, "command": j.Command})
+	}
+	snippet, _ := yaml.Marshal(map[string]interface{}{"jobs": entries})
+	output.Info("Generating CronJobs for this run; add them to .dorgu.yaml to keep them:\n" + string(snippet))
+}
+
+// newProvenance describes this run for the annotations and lock file; the
+// model is recorded only when the LLM contributed to the analysis or persona.
+// Deterministic runs take the time from SOURCE_DATE_EPOCH or leave it out.
+func newProvenance(analysis *types.AppAnalysis, pipeline *analyzer.PipelineResult, provider string, deterministic bool) *generator.Provenance {
+	prov := &generator.Provenance{
+		Version:      versionInfo.Version,
+		AnalysisHash: generator.HashAnalysis(analysis),
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	if deterministic {
+		if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+			now = time.Unix(epoch, 0).UTC()
+			prov.GeneratedAt = &now
 		}
 	} else {
-		if err := output.WriteFiles(generateFlags.output, files); err != nil {
+		prov.GeneratedAt = &now
+	}
+	if pipeline.EnhanceErr == nil || pipeline.Persona != "" {
+		prov.LLMModel = provider + "/" + llm.ModelName(provider)
+	}
+	return prov
+}
+
+// generateResult is the -o json|yaml document of generate
+type generateResult struct {
+	Name       string                      `json:"name"`
+	Namespace  string                      `json:"namespace"`
+	OutputDir  string                      `json:"outputDir,omitempty"`
+	DryRun     bool                        `json:"dryRun"`
+	Files      []generatedFileResult       `json:"files"`
+	Validation *generator.ValidationResult `json:"validation,omitempty"`
+	// PullRequest is the URL of the pull request opened by --create-pr
+	PullRequest string `json:"pullRequest,omitempty"`
+}
+
+type generatedFileResult struct {
+	Path    string `json:"path"`
+	Content string `json:"content,omitempty"`
+	// Status is created, updated, or unchanged for written files
+	Status string `json:"status,omitempty"`
+}
+
+// printGenerateResult writes files (unless --dry-run, in which case their
+// content is embedded) and prints the result document. publish allows the
+// pull request and notifications.
+func printGenerateResult(ctx context.Context, absPath, outputDir string, gen *generation, publish bool) error {
+	result := generateResult{
+		Name:       gen.analysis.Name,
+		Namespace:  gen.namespace,
+		DryRun:     generateFlags.dryRun,
+		Validation: gen.validation,
+	}
+	var summary output.WriteSummary
+	if !generateFlags.dryRun {
+		var err error
+		summary, err = output.WriteFiles(outputDir, gen.files, output.WriteOptions{AllowOutside: true, Backup: generateFlags.backup})
+		if err != nil {
 			return fmt.Errorf("failed to write files: %w", err)
 		}
-		output.Success("Generated manifests successfully!")
-		fmt.Println()
-		fmt.Println("Files created:")
-		for _, f := range files {
-			fmt.Printf("  %s\n", filepath.Join(generateFlags.output, f.Path))
+		result.OutputDir = outputDir
+		if err := savePinnedAnalysis(absPath, gen); err != nil {
+			return err
 		}
 	}
+	for _, f := range gen.files {
+		file := generatedFileResult{Path: f.Path}
+		if generateFlags.dryRun {
+			file.Content = f.Content
+		} else {
+			file.Path = filepath.Join(outputDir, f.Path)
+			file.Status = summary.Status(f.Path)
+		}
+		result.Files = append(result.Files, file)
+	}
+	if generateFlags.createPR && publish {
+		url, err := createGeneratePR(ctx, absPath, outputDir, gen)
+		if err != nil {
+			return err
+		}
+		result.PullRequest = url
+	}
+	if generateFlags.notify && publish {
+		notifyGenerate(ctx, gen, result.PullRequest)
+	}
+	_, err := printStructured(result, outputFormat)
+	return err
+}
 
-	return nil
+// annotationPaths locates the files validation issues of the app at absPath
+// refer to, relative to the GitHub Actions workspace
+func annotationPaths(absPath, outputDir string) output.AnnotationPaths {
+	root := os.Getenv("GITHUB_WORKSPACE")
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	configs := []string{filepath.Join(absPath, ".dorgu.yaml")}
+	if used := viper.ConfigFileUsed(); used != "" {
+		configs = append(configs, used)
+	}
+	return output.AnnotationPaths{Root: root, OutputDir: outputDir, AppDir: absPath, ConfigFiles: configs}
+}
+
+// validationGate returns an error when validation found issues of the
+// severity --strict (warning) or validation.fail_on sets, or worse
+func validationGate(gen *generation, strict bool) error {
+	failOn := gen.config.Validation.FailOn
+	if strict {
+		failOn = config.FailOnWarning
+	}
+	if gen.validation == nil || !gen.validation.Fails(failOn) {
+		return nil
+	}
+	return fmt.Errorf("validation failed on %ss or worse: %s", failOn, gen.validation.Summary)
+}
+
+// createGeneratePR commits the written files to a new timestamped branch and
+// opens a pull request for them
+func createGeneratePR(ctx context.Context, absPath, outputDir string, gen *generation) (string, error) {
+	paths := make([]string, 0, len(gen.files))
+	for _, f := range gen.files {
+		paths = append(paths, filepath.Join(outputDir, f.Path))
+	}
+	head := gen.config.PullRequest.BranchPrefix + gen.analysis.Name + "-" + time.Now().Format("20060102-150405")
+	pr, err := newPullRequest(gen, paths, head, generateFlags.prBase)
+	if err != nil {
+		return "", err
+	}
+	return openPullRequest(ctx, absPath, paths, pr)
 }
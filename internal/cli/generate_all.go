@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/dorgu-ai/dorgu/internal/analyzer"
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+// validateGenerateAll rejects the flags that name a single app or a single
+// destination, which --all cannot apply to every app
+func validateGenerateAll(opts generateOptions) error {
+	switch {
+	case opts.name != "":
+		return fmt.Errorf("--name cannot be used with --all")
+	case opts.dryRun || isStructuredOutput():
+		return fmt.Errorf("--all cannot be used with --dry-run or -o")
+	case opts.createPR || opts.reviewAnalysis:
+		return fmt.Errorf("--all cannot be used with --create-pr or --review-analysis")
+	case opts.ciOutput != "" || opts.personaOutput != "":
+		return fmt.Errorf("--all cannot be used with --ci-output or --persona-output")
+	case filepath.IsAbs(opts.outputDir):
+		return fmt.Errorf("--output-dir must be relative to each app with --all")
+	}
+	return nil
+}
+
+// runGenerateAll generates every app under root into its own output
+// directory. Apps whose inputs match the sourceHash in their dorgu.lock are
+// skipped unless opts.full is set.
+func runGenerateAll(ctx context.Context, root string, opts generateOptions) error {
+	apps, err := analyzer.FindApps(root)
+	if err != nil {
+		return fmt.Errorf("failed to find apps: %w", err)
+	}
+	if len(apps) == 0 {
+		return fmt.Errorf("no Dockerfile or docker-compose.yml found under %s", root)
+	}
+
+	var regenerated, unchanged int
+	var failed []string
+	for _, app := range apps {
+		rel, _ := filepath.Rel(root, app)
+		appOpts := opts
+		appOpts.outputDir = filepath.Join(app, opts.outputDir)
+
+		lock, err := generator.ReadLock(appOpts.outputDir)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		if appOpts.sourceHash, err = appInputHash(app, appOpts.outputDir, lock, opts); err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		if !opts.full && lock != nil && lock.Inputs.SourceHash == appOpts.sourceHash {
+			output.Dim(fmt.Sprintf("  %s: inputs unchanged, skipped", rel))
+			unchanged++
+			continue
+		}
+
+		gated, err := generateAndWrite(ctx, app, appOpts)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		if gated {
+			failed = append(failed, rel)
+		}
+		regenerated++
+	}
+
+	output.Success(fmt.Sprintf("Regenerated %d of %d apps (%d unchanged)", regenerated, len(apps), unchanged))
+	if len(failed) > 0 {
+		return fmt.Errorf("validation failed for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// generateAndWrite generates one app of a --all run and writes its files.
+// gated reports whether its validation fails the --strict or
+// validation.fail_on gate.
+func generateAndWrite(ctx context.Context, app string, opts generateOptions) (gated bool, err error) {
+	gen, err := generateApp(ctx, app, opts)
+	if err != nil {
+		return false, err
+	}
+	if gen.files, err = consentOutsideWrites(opts.outputDir, gen, opts); err != nil {
+		return false, err
+	}
+	ok, err := confirmOverwrites(opts.outputDir, gen.files, opts.force)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		output.Warn("Nothing written for " + app)
+		return false, nil
+	}
+	if gen.validation != nil && !gen.validation.Passed {
+		output.Warn(fmt.Sprintf("Validation found issues in %s; run dorgu generate %s for the report", app, app))
+	}
+	if gen.validation != nil && output.InGitHubActions() {
+		output.WriteGitHubAnnotations(os.Stdout, gen.validation, annotationPaths(app, opts.outputDir))
+	}
+	summary, err := output.WriteFiles(opts.outputDir, gen.files, output.WriteOptions{AllowOutside: true, Backup: opts.backup})
+	if err != nil {
+		return false, fmt.Errorf("failed to write files: %w", err)
+	}
+	output.Success(fmt.Sprintf("%s: %d created, %d updated, %d unchanged",
+		opts.outputDir, len(summary.Created), len(summary.Updated), len(summary.Unchanged)))
+	return validationGate(gen, opts.strict) != nil, savePinnedAnalysis(app, gen)
+}
+
+// appInputHash digests everything an app's generated files depend on: its
+// source files without the output of earlier runs, the workspace and global
+// config, the generate flags, and the dorgu version
+func appInputHash(app, outputDir string, lock *generator.Lock, opts generateOptions) (string, error) {
+	generated := map[string]bool{}
+	mark := func(path string) {
+		if rel, err := filepath.Rel(app, filepath.Join(outputDir, path)); err == nil {
+			generated[filepath.ToSlash(rel)] = true
+		}
+	}
+	mark(".")
+	mark(generator.DefaultCIPath)
+	mark(generator.DefaultPersonaPath)
+	if lock != nil {
+		for path := range lock.Files {
+			mark(path)
+		}
+	}
+	sources, err := analyzer.HashSources(app, func(rel string) bool { return generated[rel] })
+	if err != nil {
+		return "", err
+	}
+
+	// Flags that don't change the generated files
+	settings := opts
+	settings.outputDir, settings.sourceHash = "", ""
+	settings.yes, settings.force, settings.backup, settings.timings = false, false, false, false
+	settings.notify, settings.saveAnalysis, settings.all, settings.full = false, false, false, false
+	settings.strict = false
+
+	h := sha256.New()
+	fmt.Fprintf(h, "version=%s\nsources=%s\nsettings=%+v\n", versionInfo.Version, sources, settings)
+	for _, path := range []string{viper.ConfigFileUsed(), config.GlobalConfigPath()} {
+		if path == "" {
+			continue
+		}
+		if data, err := os.ReadFile(path); err == nil {
+			fmt.Fprintf(h, "%s=", path)
+			h.Write(data)
+		}
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
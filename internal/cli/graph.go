@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/graph"
+	"github.com/dorgu-ai/dorgu/internal/kube"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var graphFlags struct {
+	cluster       bool
+	namespace     string
+	allNamespaces bool
+	blastRadius   string
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [path]",
+	Short: "Show the dependency graph of onboarded apps",
+	Long: `Aggregate the dependencies of every app into a service dependency graph.
+
+By default the workspace under path (default: current directory) is searched
+for app .dorgu.yaml files (their dependencies) and docker-compose files (the
+depends_on of each service). With --cluster, the ApplicationPersonas on the
+cluster are used instead.
+
+--blast-radius limits the graph to an app or dependency and everything that
+depends on it, directly or transitively: what is affected when it goes down.
+
+Output formats (-o): dot (Graphviz), mermaid, json, yaml. Without -o, each
+app's dependencies and dependents are listed.
+
+Examples:
+  dorgu graph
+  dorgu graph ./services -o mermaid
+  dorgu graph -o dot | dot -Tsvg > deps.svg
+  dorgu graph --cluster --all-namespaces -o json
+  dorgu graph --blast-radius postgres`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runGraph,
+}
+
+func init() {
+	graphCmd.Flags().BoolVar(&graphFlags.cluster, "cluster", false, "build the graph from ApplicationPersonas on the cluster")
+	graphCmd.Flags().StringVarP(&graphFlags.namespace, "namespace", "n", "default", "Kubernetes namespace (with --cluster)")
+	graphCmd.Flags().BoolVarP(&graphFlags.allNamespaces, "all-namespaces", "A", false, "use personas across all namespaces (with --cluster)")
+	graphCmd.Flags().StringVar(&graphFlags.blastRadius, "blast-radius", "", "only show this node and everything that depends on it")
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat, "", "dot", "mermaid", "json", "yaml"); err != nil {
+		return err
+	}
+
+	var g *graph.Graph
+	if graphFlags.cluster {
+		if len(args) > 0 {
+			return fmt.Errorf("--cluster does not take a path")
+		}
+		client, err := kube.NewAPIClient("")
+		if err != nil {
+			return err
+		}
+		namespace := graphFlags.namespace
+		if graphFlags.allNamespaces {
+			namespace = ""
+		}
+		personas, err := client.ListPersonas(cmd.Context(), namespace)
+		if err != nil {
+			return personaKubeError(err, "")
+		}
+		g = graph.FromPersonas(personas)
+	} else {
+		targetPath := "."
+		if len(args) > 0 {
+			targetPath = args[0]
+		}
+		absPath, err := filepath.Abs(targetPath)
+		if err != nil {
+			return fmt.Errorf("failed to resolve path: %w", err)
+		}
+		if _, err := os.Stat(absPath); os.IsNotExist(err) {
+			return fmt.Errorf("path does not exist: %s", absPath)
+		}
+		if g, err = graph.FromWorkspace(absPath); err != nil {
+			return err
+		}
+	}
+
+	if name := graphFlags.blastRadius; name != "" {
+		if !g.Has(name) {
+			return fmt.Errorf("%q is not an app or dependency in the graph", name)
+		}
+		g = g.BlastRadius(name)
+	}
+
+	switch outputFormat {
+	case "dot":
+		fmt.Print(g.DOT())
+		return nil
+	case "mermaid":
+		fmt.Print(g.Mermaid())
+		return nil
+	}
+	if handled, err := printStructured(g, outputFormat); handled {
+		return err
+	}
+
+	if len(g.Nodes) == 0 {
+		output.Info("No dependencies found. Declare them under dependencies: in each app's .dorgu.yaml")
+		return nil
+	}
+	for _, n := range g.Nodes {
+		if !n.App && len(g.Dependents(n.Name)) > 0 {
+			continue
+		}
+		fmt.Println(output.Blue(n.Name))
+		var deps []string
+		for _, e := range g.Edges {
+			if e.From == n.Name {
+				dep := e.To
+				if !e.Required {
+					dep += " (optional)"
+				}
+				deps = append(deps, dep)
+			}
+		}
+		if len(deps) > 0 {
+			fmt.Printf("  depends on: %s\n", strings.Join(deps, ", "))
+		}
+		if users := g.Dependents(n.Name); len(users) > 0 {
+			fmt.Printf("  used by:    %s\n", strings.Join(users, ", "))
+		}
+	}
+	var shared []string
+	for _, n := range g.Nodes {
+		if !n.App {
+			if users := g.Dependents(n.Name); len(users) > 0 {
+				shared = append(shared, fmt.Sprintf("%s (%d)", n.Name, len(users)))
+			}
+		}
+	}
+	if len(shared) > 0 {
+		fmt.Printf("\nDependencies (number of dependents): %s\n", strings.Join(shared, ", "))
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -27,7 +28,11 @@ Examples:
   dorgu init ./my-app            # Initialize app config in specified directory
   dorgu init --global            # Set up global config (~/.config/dorgu/config.yaml)
   dorgu init --minimal           # Create minimal app config
-  dorgu init --full              # Create full app config with all options`,
+  dorgu init --full              # Create full app config with all options
+
+  # Scripted (no prompts; also implied by CI=true or a non-terminal stdin)
+  dorgu init --non-interactive --name orders --team commerce --type api
+  dorgu init --global --non-interactive --provider openai --namespace apps`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInit,
 }
@@ -37,6 +42,23 @@ var (
 	initFull    bool
 	initForce   bool
 	initGlobal  bool
+
+	// Answers to the app init prompts
+	initName        string
+	initDescription string
+	initTeam        string
+	initOwner       string
+	initType        string
+	initRepo        string
+	initEnv         string
+
+	// Answers to the global init prompts
+	initProvider  string
+	initAPIKey    string
+	initModel     string
+	initNamespace string
+	initRegistry  string
+	initOrg       string
 )
 
 func init() {
@@ -44,13 +66,28 @@ func init() {
 	initCmd.Flags().BoolVar(&initFull, "full", false, "Create full configuration with all options")
 	initCmd.Flags().BoolVar(&initForce, "force", false, "Overwrite existing configuration")
 	initCmd.Flags().BoolVar(&initGlobal, "global", false, "Initialize global configuration (~/.config/dorgu/config.yaml)")
+
+	initCmd.Flags().StringVar(&initName, "name", "", "Application name (default: directory name)")
+	initCmd.Flags().StringVar(&initDescription, "description", "", "Application description")
+	initCmd.Flags().StringVar(&initTeam, "team", "", "Team name")
+	initCmd.Flags().StringVar(&initOwner, "owner", "", "Owner email")
+	initCmd.Flags().StringVar(&initType, "type", "", "Application type: api, web, worker, cron (default: guessed)")
+	initCmd.Flags().StringVar(&initRepo, "repo", "", "Repository URL (default: git remote)")
+	initCmd.Flags().StringVar(&initEnv, "env", "", "Environment: production, staging, development (default \"production\")")
+
+	initCmd.Flags().StringVar(&initProvider, "provider", "", "With --global: default LLM provider (default \"gemini\")")
+	initCmd.Flags().StringVar(&initAPIKey, "api-key", "", "With --global: LLM API key (prefer the provider's env var)")
+	initCmd.Flags().StringVar(&initModel, "model", "", "With --global: model override")
+	initCmd.Flags().StringVar(&initNamespace, "namespace", "", "With --global: default Kubernetes namespace (default \"default\")")
+	initCmd.Flags().StringVar(&initRegistry, "registry", "", "With --global: default container registry")
+	initCmd.Flags().StringVar(&initOrg, "org", "", "With --global: organization name")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
 	if initGlobal {
 		return runGlobalInit()
 	}
-	return runAppInit(args)
+	return runAppInit(cmd.Context(), args)
 }
 
 func runGlobalInit() error {
@@ -62,30 +99,37 @@ func runGlobalInit() error {
 	}
 
 	reader := bufio.NewReader(os.Stdin)
-	fmt.Println()
-	fmt.Println("Dorgu Global Configuration Setup")
-	fmt.Println("==================================")
-	fmt.Println("This sets default LLM provider and API keys. Overridable by env or app config.")
-	fmt.Println()
+	if isInteractive() {
+		fmt.Println()
+		fmt.Println("Dorgu Global Configuration Setup")
+		fmt.Println("==================================")
+		fmt.Println("This sets default LLM provider and API keys. Overridable by env or app config.")
+		fmt.Println()
+	}
 
-	provider := prompt(reader, "Default LLM provider (openai, anthropic, gemini, ollama)", "gemini")
+	provider := promptFlag(reader, initProvider, "Default LLM provider (openai, anthropic, gemini, ollama)", "gemini")
 	provider = strings.ToLower(strings.TrimSpace(provider))
 	valid := map[string]bool{"openai": true, "anthropic": true, "gemini": true, "ollama": true}
 	if !valid[provider] {
+		if initProvider != "" {
+			return fmt.Errorf("unknown LLM provider %q (supported: openai, anthropic, gemini, ollama)", initProvider)
+		}
 		provider = "gemini"
 	}
 
-	apiKey := ""
-	if provider != "ollama" {
+	apiKey := initAPIKey
+	if provider != "ollama" && apiKey == "" && isInteractive() {
 		fmt.Println()
 		apiKey = prompt(reader, "API Key (leave empty to use env var)", "")
 	}
 
-	fmt.Println()
-	model := prompt(reader, "Model override (leave empty for provider default)", "")
-	namespace := prompt(reader, "Default Kubernetes namespace", "default")
-	registry := prompt(reader, "Default container registry (e.g. ghcr.io/my-org)", "")
-	orgName := prompt(reader, "Organization name", "")
+	if isInteractive() {
+		fmt.Println()
+	}
+	model := promptFlag(reader, initModel, "Model override (leave empty for provider default)", "")
+	namespace := promptFlag(reader, initNamespace, "Default Kubernetes namespace", "default")
+	registry := promptFlag(reader, initRegistry, "Default container registry (e.g. ghcr.io/my-org)", "")
+	orgName := promptFlag(reader, initOrg, "Organization name", "")
 
 	cfg := &config.GlobalConfig{
 		Version: "1",
@@ -109,7 +153,7 @@ func runGlobalInit() error {
 	return nil
 }
 
-func runAppInit(args []string) error {
+func runAppInit(ctx context.Context, args []string) error {
 	targetPath := "."
 	if len(args) > 0 {
 		targetPath = args[0]
@@ -129,11 +173,11 @@ func runAppInit(args []string) error {
 
 	var configContent string
 	if initMinimal {
-		configContent = generateMinimalConfig(absPath)
+		configContent = generateMinimalConfig(ctx, absPath)
 	} else if initFull {
-		configContent = generateFullConfig(absPath)
+		configContent = generateFullConfig(ctx, absPath)
 	} else {
-		configContent, err = interactiveAppInit(absPath)
+		configContent, err = interactiveAppInit(ctx, absPath)
 		if err != nil {
 			return err
 		}
@@ -148,15 +192,18 @@ func runAppInit(args []string) error {
 	return nil
 }
 
-func interactiveAppInit(appPath string) (string, error) {
+func interactiveAppInit(ctx context.Context, appPath string) (string, error) {
 	reader := bufio.NewReader(os.Stdin)
-	fmt.Println()
-	fmt.Println("Dorgu Application Configuration")
-	fmt.Println("=================================")
-	fmt.Println()
+	interactive := isInteractive()
+	if interactive {
+		fmt.Println()
+		fmt.Println("Dorgu Application Configuration")
+		fmt.Println("=================================")
+		fmt.Println()
+	}
 
 	dirName := filepath.Base(appPath)
-	detectedRepo := analyzer.DetectGitRemoteURL(appPath)
+	detectedRepo := analyzer.DetectGitRemoteURL(ctx, appPath)
 	detectedLang := detectLanguageHint(appPath)
 	if detectedRepo != "" {
 		output.Info("Detected git remote: " + detectedRepo)
@@ -164,15 +211,17 @@ func interactiveAppInit(appPath string) (string, error) {
 	if detectedLang != "" {
 		output.Info("Detected language: " + detectedLang)
 	}
-	fmt.Println()
+	if interactive {
+		fmt.Println()
+	}
 
-	appName := prompt(reader, "Application name", dirName)
-	description := prompt(reader, "Description", "")
-	team := prompt(reader, "Team name", "")
-	owner := prompt(reader, "Owner email", "")
-	appType := prompt(reader, "Application type (api/web/worker/cron)", guessAppType(appPath, detectedLang))
-	repo := prompt(reader, "Repository URL", detectedRepo)
-	env := prompt(reader, "Environment (production/staging/development)", "production")
+	appName := promptFlag(reader, initName, "Application name", dirName)
+	description := promptFlag(reader, initDescription, "Description", "")
+	team := promptFlag(reader, initTeam, "Team name", "")
+	owner := promptFlag(reader, initOwner, "Owner email", "")
+	appType := promptFlag(reader, initType, "Application type (api/web/worker/cron)", guessAppType(appPath, detectedLang))
+	repo := promptFlag(reader, initRepo, "Repository URL", detectedRepo)
+	env := promptFlag(reader, initEnv, "Environment (production/staging/development)", "production")
 
 	var sb strings.Builder
 	sb.WriteString("# Dorgu Application Configuration\n")
@@ -216,9 +265,9 @@ func interactiveAppInit(appPath string) (string, error) {
 	return sb.String(), nil
 }
 
-func generateMinimalConfig(appPath string) string {
+func generateMinimalConfig(ctx context.Context, appPath string) string {
 	dirName := filepath.Base(appPath)
-	repo := analyzer.DetectGitRemoteURL(appPath)
+	repo := analyzer.DetectGitRemoteURL(ctx, appPath)
 	appType := guessAppType(appPath, "")
 	var sb strings.Builder
 	sb.WriteString("# Dorgu Application Configuration (Minimal)\n")
@@ -236,9 +285,9 @@ func generateMinimalConfig(appPath string) string {
 	return sb.String()
 }
 
-func generateFullConfig(appPath string) string {
+func generateFullConfig(ctx context.Context, appPath string) string {
 	dirName := filepath.Base(appPath)
-	repo := analyzer.DetectGitRemoteURL(appPath)
+	repo := analyzer.DetectGitRemoteURL(ctx, appPath)
 	repoVal := "\"https://github.com/company/my-service\""
 	if repo != "" {
 		repoVal = fmt.Sprintf("\"%s\"", repo)
@@ -274,6 +323,38 @@ scaling:
   max_replicas: 10
   target_cpu: 70
   target_memory: 80
+  # HPA behavior preset: aggressive, balanced, or conservative
+  # behavior: balanced
+  # scale_down:
+  #   stabilization_window_seconds: 600
+  #   policies:
+  #     - type: Pods
+  #       value: 1
+  #       period_seconds: 60
+  # Custom metrics (e.g. from prometheus-adapter) to scale on besides CPU/memory
+  # metrics:
+  #   - type: pods
+  #     name: http_requests_per_second
+  #     average_value: "100"
+  #   - type: external
+  #     name: queue_messages_ready
+  #     selector: { queue: "orders" }
+  #     value: "30"
+
+# Additional containers in the pod; their ports are added to the Service
+# containers:
+#   - name: nginx
+#     image: "nginx:1.27"
+#     ports:
+#       - port: 80
+#     health:
+#       readiness: { path: "/healthz" }
+
+# Jobs run with the app's image and environment (e.g. migrations)
+# jobs:
+#   - name: migrate
+#     command: ["npm", "run", "migrate"]
+#     hook: pre-deploy   # pre-deploy, post-deploy, or omit for a one-off Job
 
 labels:
   "app.kubernetes.io/component": "backend"
@@ -283,12 +364,44 @@ annotations:
   "prometheus.io/port": "8080"
   "prometheus.io/path": "/metrics"
 
+# public (default) gets an Ingress; internal and headless stay in-cluster,
+# headless with a clusterIP: None Service for client-side discovery
+networking:
+  expose: "public"
+  # DNS tuning: a lower ndots avoids walking every search domain for
+  # external names; host_aliases become /etc/hosts entries
+  # dns:
+  #   ndots: 2
+  #   searches: ["corp.company.com"]
+  #   host_aliases:
+  #     - ip: "10.0.0.10"
+  #       hostnames: ["legacy-db.internal"]
+
+# Service type: ClusterIP (default), NodePort, or LoadBalancer. presets are
+# annotation sets from the org config (built-in: internal, and nlb on AWS).
+# service:
+#   type: "LoadBalancer"
+#   presets: ["internal"]
+#   static_ip: "10.0.0.50"
+#   annotations: {}
+
 ingress:
   enabled: true
   host: "api.company.com"
+  # class: "alb"            # default: the org ingress class
+  # annotations:            # Ingress only, applied after the class preset
+  #   nginx.ingress.kubernetes.io/proxy-body-size: "50m"
+  # More hosts (wildcards allowed); tls_secret overrides tls.secret_name
+  # hosts:
+  #   - "legacy-api.company.com"
+  #   - host: "*.api.company.com"
+  #     tls_secret: "wildcard-api-tls"
   paths:
     - path: "/api/v1"
       path_type: "Prefix"
+    # Route a path to another port (or another Service with service:)
+    # - path: "/admin"
+    #   port: 9000
   tls:
     enabled: true
     secret_name: "api-tls-secret"
@@ -304,6 +417,24 @@ health:
     port: 8080
     initial_delay: 5
     period: 5
+    timeout: 3
+    failure_threshold: 3
+
+# Override the org secret provider (kubernetes, vault-agent, csi) or the
+# Vault role and secret path for this app
+# secrets:
+#   provider: "vault-agent"
+#   role: "my-service"
+#   path: "secret/data/my-team/my-service"
+
+# Override the org runtime class and seccomp/AppArmor profiles
+# security:
+#   runtime_class: "gvisor"
+#   seccomp_profile:
+#     type: "Localhost"
+#     localhost_profile: "profiles/my-service.json"
+#   apparmor_profile: "localhost/my-service"
+#   pod_security_standard: "restricted"
 
 dependencies:
   - name: postgresql
@@ -320,6 +451,19 @@ operations:
     - "ServiceErrorRate"
   maintenance_window: "Sundays 02:00-04:00 UTC"
   on_call: "oncall@company.com"
+
+# Service level objectives, documented in the persona. With format (or the
+# org slo.format) set to sloth or openslo, SLO manifests are generated too.
+# slo:
+#   availability: 99.9          # percent of requests that succeed
+#   latency:
+#     threshold: "300ms"
+#     target: 99                # percent of requests within the threshold
+#   window: "30d"
+#   error_budget_policy: "Freeze feature releases until the budget recovers"
+#   format: "sloth"
+#   metrics:                    # defaults: http_requests_total, http_request_duration_seconds, job="<app>"
+#     selector: 'service="my-service"'
 `, dirName, repoVal)
 }
 
@@ -351,20 +495,6 @@ func guessAppType(path string, lang string) string {
 	return "api"
 }
 
-func prompt(reader *bufio.Reader, label, defaultVal string) string {
-	if defaultVal != "" {
-		fmt.Printf("%s [%s]: ", label, defaultVal)
-	} else {
-		fmt.Printf("%s: ", label)
-	}
-	input, _ := reader.ReadString('\n')
-	input = strings.TrimSpace(input)
-	if input == "" {
-		return defaultVal
-	}
-	return input
-}
-
 func mustGetwd() string {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/mattn/go-isatty"
+)
+
+var (
+	// Global --quiet flag: no spinner, info, or hint messages
+	quiet bool
+
+	// Global --non-interactive flag: never prompt, use flags and defaults
+	nonInteractive bool
+)
+
+// ciEnvironment reports whether the CI variable is set, as it is by GitHub
+// Actions, GitLab CI, CircleCI, Jenkins, and most other CI systems
+func ciEnvironment() bool {
+	switch strings.ToLower(os.Getenv("CI")) {
+	case "", "0", "false":
+		return false
+	}
+	return true
+}
+
+// isInteractive reports whether prompts may be shown: not disabled by flag or
+// CI, and stdin is a terminal
+func isInteractive() bool {
+	return !nonInteractive && !ciEnvironment() && isatty.IsTerminal(os.Stdin.Fd())
+}
+
+// newSpinner returns a spinner on stderr that stays hidden with --quiet or in CI
+func newSpinner(suffix string) *spinner.Spinner {
+	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(os.Stderr))
+	s.Suffix = suffix
+	if quiet || ciEnvironment() {
+		s.Disable()
+	}
+	return s
+}
+
+// prompt asks for a value on stdin. In non-interactive mode it returns
+// defaultVal without asking.
+func prompt(reader *bufio.Reader, label, defaultVal string) string {
+	if !isInteractive() {
+		return defaultVal
+	}
+	if defaultVal != "" {
+		fmt.Printf("%s [%s]: ", label, defaultVal)
+	} else {
+		fmt.Printf("%s: ", label)
+	}
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return defaultVal
+	}
+	return input
+}
+
+// promptFlag returns flagValue when the corresponding flag was given and
+// prompts otherwise
+func promptFlag(reader *bufio.Reader, flagValue, label, defaultVal string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return prompt(reader, label, defaultVal)
+}
+
+// confirm asks a yes/no question. Without a terminal it fails instead of
+// assuming consent, so scripts must pass --yes explicitly.
+func confirm(question string) (bool, error) {
+	if !isInteractive() {
+		return false, fmt.Errorf("cannot confirm %q in non-interactive mode; pass --yes", question)
+	}
+	answer := prompt(bufio.NewReader(os.Stdin), question+" (y/N)", "")
+	return strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes"), nil
+}
@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/lint"
+	"github.com/dorgu-ai/dorgu/internal/output"
+	"github.com/dorgu-ai/dorgu/internal/vcs"
+)
+
+var lintFlags struct {
+	staged      bool
+	installHook bool
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [path...]",
+	Short: "Check .dorgu.yaml files for semantic mistakes",
+	Long: `Check .dorgu.yaml files for mistakes that parse fine but produce broken or
+surprising manifests:
+
+  - scaling.min_replicas greater than max_replicas
+  - resource requests greater than limits, or invalid quantities
+  - ingress hosts with a scheme, port, path, or invalid DNS name
+  - a missing or malformed app.owner email
+  - app labels that override labels required or set by the org config
+  - app names that are not valid DNS-1123 names, or that collide with
+    another linted app once normalized (e.g. My_App and my-app)
+
+Paths may be app directories or config files. Without paths, ./.dorgu.yaml and
+the workspace config in use are linted. App files are checked against the
+workspace config.
+
+With --staged, the staged content of every .dorgu.yaml in the git index is
+linted, which is what the pre-commit hook runs. Install it with --install-hook,
+or use the dorgu-lint hook from the pre-commit framework.
+
+Examples:
+  dorgu lint
+  dorgu lint ./apps/orders ./apps/billing/.dorgu.yaml
+  dorgu lint --staged
+  dorgu lint --install-hook`,
+	RunE: runLint,
+}
+
+func init() {
+	lintCmd.Flags().BoolVar(&lintFlags.staged, "staged", false, "lint the staged content of .dorgu.yaml files (pre-commit mode)")
+	lintCmd.Flags().BoolVar(&lintFlags.installHook, "install-hook", false, "install a git pre-commit hook that runs 'dorgu lint --staged'")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat, "", "json", "yaml"); err != nil {
+		return err
+	}
+	if lintFlags.installHook {
+		return installLintHook(cmd.Context())
+	}
+
+	org, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var issues []lint.Issue
+	files := 0
+	contents := map[string][]byte{}
+	if lintFlags.staged {
+		if len(args) > 0 {
+			return fmt.Errorf("--staged does not take paths")
+		}
+		repo, err := vcs.Open(cmd.Context(), ".")
+		if err != nil {
+			return err
+		}
+		staged, err := repo.StagedFiles(cmd.Context())
+		if err != nil {
+			return err
+		}
+		for _, path := range staged {
+			if !isDorguConfig(path) {
+				continue
+			}
+			data, err := repo.StagedContent(cmd.Context(), path)
+			if err != nil {
+				return err
+			}
+			files++
+			contents[path] = data
+			issues = append(issues, lint.File(path, data, org)...)
+		}
+	} else {
+		paths, err := lintTargets(args)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			files++
+			contents[path] = data
+			issues = append(issues, lint.File(path, data, org)...)
+		}
+	}
+	issues = append(issues, lint.Collisions(contents)...)
+
+	if issues == nil {
+		issues = []lint.Issue{}
+	}
+	if handled, err := printStructured(issues, outputFormat); handled {
+		if err != nil {
+			return err
+		}
+		return lintResult(issues)
+	}
+
+	errs := 0
+	for _, i := range issues {
+		sev := output.Yellow(string(i.Severity))
+		if i.Severity == lint.SeverityError {
+			sev = output.Red(string(i.Severity))
+			errs++
+		}
+		if i.Field != "" {
+			fmt.Printf("%s: %s %s: %s\n", i.File, sev, i.Field, i.Message)
+		} else {
+			fmt.Printf("%s: %s %s\n", i.File, sev, i.Message)
+		}
+	}
+	switch {
+	case files == 0:
+		output.Dim("No .dorgu.yaml files to lint")
+	case len(issues) == 0:
+		output.Success(fmt.Sprintf("%d file(s) linted, no issues", files))
+	default:
+		fmt.Println()
+		output.Info(fmt.Sprintf("%d file(s) linted: %d error(s), %d warning(s)", files, errs, len(issues)-errs))
+	}
+	return lintResult(issues)
+}
+
+func lintResult(issues []lint.Issue) error {
+	errs := 0
+	for _, i := range issues {
+		if i.Severity == lint.SeverityError {
+			errs++
+		}
+	}
+	if errs > 0 {
+		return fmt.Errorf("lint found %d error(s)", errs)
+	}
+	return nil
+}
+
+// lintTargets resolves args to config files; directories stand for their
+// .dorgu.yaml
+func lintTargets(args []string) ([]string, error) {
+	if len(args) == 0 {
+		var paths []string
+		seen := map[string]bool{}
+		for _, p := range []string{".dorgu.yaml", viper.ConfigFileUsed()} {
+			if p == "" {
+				continue
+			}
+			abs, _ := filepath.Abs(p)
+			if seen[abs] {
+				continue
+			}
+			if _, err := os.Stat(p); err == nil {
+				seen[abs] = true
+				paths = append(paths, p)
+			}
+		}
+		return paths, nil
+	}
+
+	paths := make([]string, 0, len(args))
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, fmt.Errorf("path does not exist: %s", arg)
+		}
+		if info.IsDir() {
+			arg = filepath.Join(arg, ".dorgu.yaml")
+			if _, err := os.Stat(arg); err != nil {
+				return nil, fmt.Errorf("no .dorgu.yaml in %s", filepath.Dir(arg))
+			}
+		}
+		paths = append(paths, arg)
+	}
+	return paths, nil
+}
+
+func isDorguConfig(path string) bool {
+	base := filepath.Base(path)
+	return base == ".dorgu.yaml" || base == ".dorgu.yml"
+}
+
+const lintHookMarker = "# installed by dorgu lint --install-hook"
+
+const lintHook = `#!/bin/sh
+` + lintHookMarker + `
+exec dorgu lint --staged
+`
+
+// installLintHook writes a pre-commit hook, refusing to replace one that
+// dorgu did not install
+func installLintHook(ctx context.Context) error {
+	repo, err := vcs.Open(ctx, ".")
+	if err != nil {
+		return err
+	}
+	dir, err := repo.HooksDir(ctx)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, "pre-commit")
+	if existing, err := os.ReadFile(path); err == nil && !strings.Contains(string(existing), lintHookMarker) {
+		return fmt.Errorf("%s already exists; add 'dorgu lint --staged' to it manually", path)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(lintHook), 0755); err != nil {
+		return fmt.Errorf("failed to write hook: %w", err)
+	}
+	output.Success(fmt.Sprintf("Installed pre-commit hook: %s", path))
+	return nil
+}
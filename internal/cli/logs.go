@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/kube"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var logsFlags struct {
+	namespace   string
+	kubeContext string
+	selector    string
+	container   string
+	follow      bool
+	tail        int
+	since       time.Duration
+	previous    bool
+}
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <app>",
+	Short: "Stream the logs of all of an app's pods",
+	Long: `Stream the logs of every pod of an app, each line prefixed with its
+pod's name in the pod's own color. The pods are the ones dorgu's manifests
+label app.kubernetes.io/name=<app> and app.kubernetes.io/managed-by=dorgu,
+so no kubectl selector is needed; --selector overrides it for other apps.
+
+Pods started after the command are not followed; run it again after a
+rollout.
+
+Examples:
+  dorgu logs orders -n production
+  dorgu logs orders -n production --since 10m --follow=false
+  dorgu logs orders -n production --previous`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogs,
+}
+
+func init() {
+	f := logsCmd.Flags()
+	f.StringVarP(&logsFlags.namespace, "namespace", "n", "default", "namespace of the app")
+	f.StringVar(&logsFlags.kubeContext, "context", "", "kubeconfig context (default: the current context)")
+	f.StringVarP(&logsFlags.selector, "selector", "l", "", "label selector of the pods (default: the app's dorgu labels)")
+	f.StringVarP(&logsFlags.container, "container", "c", "", "only this container (default: all of the pod's)")
+	f.BoolVarP(&logsFlags.follow, "follow", "f", true, "keep streaming new lines until Ctrl+C")
+	f.IntVar(&logsFlags.tail, "tail", 50, "recent lines to show per pod; -1 for all")
+	f.DurationVar(&logsFlags.since, "since", 0, "only lines newer than this, e.g. 10m")
+	f.BoolVarP(&logsFlags.previous, "previous", "p", false, "logs of the previous container instance, e.g. after a crash")
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	name := args[0]
+	client, err := kube.NewClientForContext(logsFlags.kubeContext)
+	if err != nil {
+		return fmt.Errorf("%w; required for logs", err)
+	}
+	pods, err := appPods(ctx, client, logsFlags.namespace, name, logsFlags.selector)
+	if err != nil {
+		return err
+	}
+
+	width := 0
+	for _, p := range pods {
+		width = max(width, len(p.Name))
+	}
+	opts := kube.LogOptions{
+		Follow:    logsFlags.follow && !logsFlags.previous,
+		Tail:      logsFlags.tail,
+		Since:     logsFlags.since,
+		Container: logsFlags.container,
+		Previous:  logsFlags.previous,
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, pod := range pods {
+		prefix := output.Stream(i, fmt.Sprintf("%-*s", width, pod.Name))
+		wg.Add(1)
+		go func(pod kube.Pod) {
+			defer wg.Done()
+			err := client.StreamLogs(ctx, logsFlags.namespace, pod.Name, opts, func(line string) {
+				mu.Lock()
+				defer mu.Unlock()
+				fmt.Printf("%s │ %s\n", prefix, line)
+			})
+			if err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				output.Warn(fmt.Sprintf("%s: %v", pod.Name, err))
+			}
+		}(pod)
+	}
+	wg.Wait()
+	return nil
+}
+
+// appPods returns the pods of app, selected by selector or the app's dorgu
+// labels, failing when there are none
+func appPods(ctx context.Context, client *kube.Client, namespace, app, selector string) ([]kube.Pod, error) {
+	if selector == "" {
+		selector = kube.AppSelector(app)
+	}
+	pods, err := client.ListPods(ctx, namespace, selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the pods of %s: %w", app, err)
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no pods of %s in namespace %s (selector %s)", app, namespace, selector)
+	}
+	return pods, nil
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/notify"
+	"github.com/dorgu-ai/dorgu/internal/output"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// notifyGenerate posts a generate run to the configured webhooks
+func notifyGenerate(ctx context.Context, gen *generation, pullRequest string) {
+	if len(gen.config.Notifications) == 0 {
+		output.Warn("--notify: no notifications configured in .dorgu.yaml")
+		return
+	}
+	e := notify.Event{
+		Action:      "generated manifests",
+		App:         gen.analysis.Name,
+		Namespace:   gen.namespace,
+		Environment: gen.analysis.Environment,
+		PullRequest: pullRequest,
+	}
+	if v := gen.validation; v != nil {
+		e.Validation, e.Failed = v.Summary, !v.Passed
+	}
+	sendNotification(ctx, gen.config.Notifications, e)
+}
+
+// notifyApply posts an applied persona to the configured webhooks, if any.
+// validation is the operator's, when it validated the persona.
+func notifyApply(ctx context.Context, persona *types.ApplicationPersona, validation *types.PersonaValidation) {
+	cfg, err := config.Load()
+	if err != nil || len(cfg.Notifications) == 0 {
+		return
+	}
+	e := notify.Event{
+		Action:    "applied persona",
+		App:       persona.Spec.Name,
+		Namespace: persona.Metadata.Namespace,
+	}
+	if validation != nil {
+		e.Validation, e.Failed = "failed", !validation.Passed
+		if validation.Passed {
+			e.Validation = "passed"
+		}
+		if n := len(validation.Issues); n > 0 {
+			e.Validation += fmt.Sprintf(" (%d issue(s))", n)
+		}
+	}
+	sendNotification(ctx, cfg.Notifications, e)
+}
+
+// sendNotification posts e; failures are reported but don't fail the command
+func sendNotification(ctx context.Context, targets []config.NotificationConfig, e notify.Event) {
+	if err := notify.Send(ctx, targets, e); err != nil {
+		output.Warn(fmt.Sprintf("Notification failed: %v", err))
+		return
+	}
+	output.Info(fmt.Sprintf("Notified %d channel(s)", len(targets)))
+}
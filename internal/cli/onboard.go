@@ -0,0 +1,348 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/kube"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var onboardFlags struct {
+	generate    generateOptions
+	yes         bool
+	diff        bool
+	pr          bool
+	baseBranch  string
+	apply       bool
+	viaOperator bool
+	operatorURL string
+}
+
+var onboardCmd = &cobra.Command{
+	Use:   "onboard [path]",
+	Short: "Onboard a new service end to end",
+	Long: `Walk a new service through the whole Dorgu journey in one command:
+
+  1. Create .dorgu.yaml if the app does not have one
+  2. Analyze the app and generate manifests
+  3. Validate the generated manifests
+  4. Show what changes in the output directory and write the files
+  5. Optionally open a pull request with the manifests (GitHub or GitLab)
+  6. Optionally apply the ApplicationPersona to the cluster
+
+Interactive runs ask before each optional step. In CI or with
+--non-interactive, only the steps enabled by flags run, and --yes is required
+to write files.
+
+The pull request token is read from GITHUB_TOKEN/GH_TOKEN or GITLAB_TOKEN, or
+from git.github_token/git.gitlab_token in the global config.
+
+Examples:
+  dorgu onboard .
+  dorgu onboard ./my-app --pr --apply
+  dorgu onboard ./my-app --yes --pr --base main`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runOnboard,
+}
+
+func init() {
+	f := onboardCmd.Flags()
+	f.StringVar(&onboardFlags.generate.outputDir, "output-dir", "./k8s", "output directory for generated files")
+	f.StringVarP(&onboardFlags.generate.name, "name", "n", "", "override application name")
+	f.StringVar(&onboardFlags.generate.namespace, "namespace", "", "target Kubernetes namespace (overrides config)")
+	f.StringVar(&onboardFlags.generate.llmProvider, "llm-provider", "", "LLM provider: openai, anthropic, gemini, ollama (default from config)")
+	f.StringVar(&onboardFlags.generate.ciOutput, "ci-output", "", "path of the CI workflow (default: .github/workflows/deploy.yaml next to the output directory)")
+	f.StringVar(&onboardFlags.generate.personaOutput, "persona-output", "", "path of PERSONA.md (default: next to the output directory)")
+	f.BoolVarP(&onboardFlags.yes, "yes", "y", false, "write files and apply without asking for confirmation")
+	f.BoolVar(&onboardFlags.generate.backup, "backup", false, "keep the previous content of overwritten files in <file>.bak")
+	f.BoolVar(&onboardFlags.diff, "diff", false, "show line changes for files that already exist")
+	f.BoolVar(&onboardFlags.pr, "pr", false, "commit the manifests to a new branch and open a pull request")
+	f.StringVar(&onboardFlags.baseBranch, "base", "", "branch the pull request targets (default: pull_request.base or the current branch)")
+	f.BoolVar(&onboardFlags.apply, "apply", false, "apply the ApplicationPersona to the cluster")
+	f.BoolVar(&onboardFlags.viaOperator, "via-operator", false, "apply through the Dorgu Operator instead of kubectl")
+	f.StringVar(&onboardFlags.operatorURL, "operator-url", "", operatorURLUsage)
+}
+
+func runOnboard(cmd *cobra.Command, args []string) error {
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("path does not exist: %s", absPath)
+	}
+	if err := validateOutputFormat(outputFormat, ""); err != nil {
+		return err
+	}
+	outputDir := legacyOutputDir(onboardFlags.generate.outputDir)
+
+	// 1. Configure
+	output.Header("Step 1/6: Configure")
+	createdConfig := ""
+	if config.HasAppConfig(absPath) {
+		output.Info("Using existing .dorgu.yaml")
+	} else {
+		content, err := interactiveAppInit(cmd.Context(), absPath)
+		if err != nil {
+			return err
+		}
+		configPath := filepath.Join(absPath, ".dorgu.yaml")
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write config: %w", err)
+		}
+		output.Success(fmt.Sprintf("Created %s", configPath))
+		createdConfig = configPath
+	}
+
+	// 2. Analyze and generate
+	output.Header("Step 2/6: Analyze and generate")
+	opts := onboardFlags.generate
+	opts.outputDir = outputDir
+	gen, err := generateApp(cmd.Context(), absPath, opts)
+	if err != nil {
+		return err
+	}
+	output.Success(fmt.Sprintf("Generated %d files for %s", len(gen.files), gen.analysis.Name))
+
+	// 3. Validate
+	output.Header("Step 3/6: Validate")
+	if gen.validation.Passed {
+		output.Success("Validation passed")
+	} else {
+		output.Warn("Validation found issues")
+	}
+	fmt.Println(generator.FormatValidationReport(gen.validation))
+
+	// 4. Review and write
+	output.Header("Step 4/6: Review changes")
+	changes := compareWithDisk(outputDir, gen.files)
+	printFileChanges(outputDir, changes, onboardFlags.diff)
+	if !changes.any() {
+		output.Success("Manifests are up to date")
+	} else {
+		write := onboardFlags.yes
+		if !write {
+			if write, err = confirm(fmt.Sprintf("Write %d files to %s?", len(gen.files), outputDir)); err != nil {
+				return err
+			}
+		}
+		if !write {
+			output.Warn("Onboarding stopped before writing files")
+			return nil
+		}
+		// The confirmation covered every listed file, including those
+		// outside the output directory
+		summary, err := output.WriteFiles(outputDir, gen.files, output.WriteOptions{AllowOutside: true, Backup: opts.backup})
+		if err != nil {
+			return fmt.Errorf("failed to write files: %w", err)
+		}
+		output.Success(fmt.Sprintf("Wrote manifests to %s (%d created, %d updated)", outputDir, len(summary.Created), len(summary.Updated)))
+	}
+
+	// 5. Pull request
+	output.Header("Step 5/6: Pull request")
+	prURL := ""
+	if wantStep(onboardFlags.pr, "Open a pull request with the manifests?") {
+		paths := make([]string, 0, len(gen.files))
+		for _, f := range gen.files {
+			paths = append(paths, filepath.Join(outputDir, f.Path))
+		}
+		if createdConfig != "" {
+			paths = append(paths, createdConfig)
+		}
+		pr, err := newPullRequest(gen, paths, gen.config.PullRequest.BranchPrefix+"onboard-"+gen.analysis.Name, onboardFlags.baseBranch)
+		if err != nil {
+			return err
+		}
+		if prURL, err = openPullRequest(cmd.Context(), absPath, paths, pr); err != nil {
+			return err
+		}
+		output.Success("Opened " + prURL)
+	} else {
+		output.Dim("Skipped (use --pr to open one)")
+	}
+
+	// 6. Apply persona
+	output.Header("Step 6/6: Apply ApplicationPersona")
+	applied := false
+	if wantStep(onboardFlags.apply, "Apply the ApplicationPersona to the cluster?") {
+		persona, err := generator.BuildPersona(gen.analysis, gen.namespace, gen.config)
+		if err != nil {
+			return fmt.Errorf("persona generation failed: %w", err)
+		}
+		personaFlags.yes = onboardFlags.yes
+		personaFlags.operatorURL = onboardFlags.operatorURL
+		if onboardFlags.viaOperator {
+			err = applyPersonaViaOperator(cmd.Context(), persona)
+		} else {
+			var client *kube.Client
+			if client, err = kube.NewClient(); err != nil {
+				return fmt.Errorf("%w; required to apply the persona", err)
+			}
+			err = applyPersonaWithKubectl(cmd.Context(), client, persona)
+		}
+		if err != nil {
+			return err
+		}
+		applied = true
+	} else {
+		output.Dim("Skipped (use --apply to apply it)")
+	}
+
+	fmt.Println()
+	output.Success(fmt.Sprintf("%s onboarded", gen.analysis.Name))
+	printField("Namespace", gen.namespace)
+	printField("Manifests", outputDir)
+	if prURL != "" {
+		printField("Pull request", prURL)
+	}
+	if applied {
+		printField("Persona", "applied")
+	}
+	return nil
+}
+
+// wantStep reports whether an optional step should run: always when its flag
+// is set, after asking in interactive mode, and never otherwise
+func wantStep(flag bool, question string) bool {
+	if flag {
+		return true
+	}
+	if !isInteractive() {
+		return false
+	}
+	ok, _ := confirm(question)
+	return ok
+}
+
+// fileChange compares one generated file with what is on disk
+type fileChange struct {
+	path    string
+	status  string // new, changed, unchanged
+	added   int
+	removed int
+	diff    []string
+	// edited is set when the file on disk is not what dorgu last wrote,
+	// according to the lock file
+	edited bool
+}
+
+type fileChanges []fileChange
+
+func (c fileChanges) any() bool {
+	for _, ch := range c {
+		if ch.status != "unchanged" {
+			return true
+		}
+	}
+	return false
+}
+
+// compareWithDisk classifies each generated file against the output directory
+func compareWithDisk(outputDir string, files []generator.GeneratedFile) fileChanges {
+	lock, err := generator.ReadLock(outputDir)
+	if err != nil {
+		output.Warn(err.Error())
+	}
+	changes := make(fileChanges, 0, len(files))
+	for _, f := range files {
+		ch := fileChange{path: f.Path}
+		existing, err := os.ReadFile(filepath.Join(outputDir, f.Path))
+		switch {
+		case err != nil:
+			ch.status = "new"
+			ch.added = len(splitLines(f.Content))
+		case string(existing) == f.Content:
+			ch.status = "unchanged"
+		default:
+			ch.status = "changed"
+			ch.edited = lock != nil && lock.Edited(f.Path, existing)
+			ch.diff = lineDiff(splitLines(string(existing)), splitLines(f.Content))
+			for _, l := range ch.diff {
+				if strings.HasPrefix(l, "+") {
+					ch.added++
+				} else {
+					ch.removed++
+				}
+			}
+		}
+		changes = append(changes, ch)
+	}
+	return changes
+}
+
+func printFileChanges(outputDir string, changes fileChanges, showDiff bool) {
+	for _, ch := range changes {
+		path := filepath.Join(outputDir, ch.path)
+		switch ch.status {
+		case "new":
+			fmt.Printf("  %s %s (+%d)\n", output.Green("new      "), path, ch.added)
+		case "changed":
+			note := ""
+			if ch.edited {
+				note = ", " + output.Red("hand-edited since last generate")
+			}
+			fmt.Printf("  %s %s (+%d -%d%s)\n", output.Yellow("changed  "), path, ch.added, ch.removed, note)
+			if showDiff {
+				for _, l := range ch.diff {
+					fmt.Printf("      %s\n", l)
+				}
+			}
+		default:
+			fmt.Printf("  %s %s\n", "unchanged", path)
+		}
+	}
+	fmt.Println()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// lineDiff returns the removed ("-") and added ("+") lines between a and b,
+// in order, using the longest common subsequence of lines
+func lineDiff(a, b []string) []string {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var out []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			out = append(out, "-"+a[i])
+			i++
+		default:
+			out = append(out, "+"+b[j])
+			j++
+		}
+	}
+	return out
+}
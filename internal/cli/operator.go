@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/kube"
+	"github.com/dorgu-ai/dorgu/internal/output"
+	"github.com/dorgu-ai/dorgu/internal/ws"
+)
+
+// defaultOperatorURL is used when discovery is not possible
+const defaultOperatorURL = "ws://localhost:9090/ws"
+
+// operatorURLUsage is the help text shared by all --operator-url flags
+const operatorURLUsage = "WebSocket URL of the Dorgu Operator (default: discover in cluster and port-forward)"
+
+// newOperatorClient returns a ws client using the operator settings from the
+// global config
+func newOperatorClient(url string) *ws.Client {
+	client := ws.NewClient(url)
+	if cfg, err := config.LoadGlobalConfig(); err == nil {
+		client.SetRequestTimeout(cfg.RequestTimeout())
+	}
+	return client
+}
+
+// resolveOperatorURL returns the operator URL to connect to. An explicit
+// flag value wins; otherwise the operator Service is located in the current
+// cluster and reached through a port-forward, which stops with ctx. The
+// returned stop function must be called when done and is never nil.
+func resolveOperatorURL(ctx context.Context, flagURL string) (string, func()) {
+	if flagURL != "" {
+		return flagURL, func() {}
+	}
+
+	kc, err := kube.NewAPIClient("")
+	if err != nil {
+		return defaultOperatorURL, func() {}
+	}
+	svc, err := kc.FindOperatorService(ctx)
+	if err != nil {
+		output.Dim(fmt.Sprintf("Operator discovery failed (%v); using %s", err, defaultOperatorURL))
+		return defaultOperatorURL, func() {}
+	}
+	pf, err := kc.PortForward(ctx, svc.Namespace, "svc/"+svc.Name, svc.Port)
+	if err != nil {
+		output.Warn(fmt.Sprintf("Could not port-forward to %s/%s: %v", svc.Namespace, svc.Name, err))
+		return defaultOperatorURL, func() {}
+	}
+
+	output.Dim(fmt.Sprintf("Port-forwarding to %s/%s:%d via localhost:%d", svc.Namespace, svc.Name, svc.Port, pf.LocalPort))
+	return fmt.Sprintf("ws://127.0.0.1:%d/ws", pf.LocalPort), pf.Stop
+}
+
+// connectOperator resolves the operator URL and connects to it. The returned
+// cleanup closes the connection and any port-forward.
+func connectOperator(ctx context.Context, flagURL string) (*ws.Client, func(), error) {
+	operatorURL, stopForward := resolveOperatorURL(ctx, flagURL)
+	client := newOperatorClient(operatorURL)
+	if err := client.Connect(ctx); err != nil {
+		stopForward()
+		return nil, nil, fmt.Errorf("failed to connect to operator: %w", err)
+	}
+	return client, func() {
+		client.Close()
+		stopForward()
+	}, nil
+}
@@ -1,29 +1,36 @@
 package cli
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
-	"time"
 
-	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
 
 	"github.com/dorgu-ai/dorgu/internal/analyzer"
 	"github.com/dorgu-ai/dorgu/internal/config"
 	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/kube"
 	"github.com/dorgu-ai/dorgu/internal/output"
+	"github.com/dorgu-ai/dorgu/internal/types"
+	"github.com/dorgu-ai/dorgu/internal/ws"
 )
 
 var personaFlags struct {
-	namespace   string
-	outputDir   string
-	dryRun      bool
-	llmProvider string
-	name        string
+	namespace     string
+	outputDir     string
+	dryRun        bool
+	llmProvider   string
+	name          string
+	format        string
+	exportFormat  string
+	allNamespaces bool
+	yes           bool
+	live          bool
+	operatorURL   string
+	viaOperator   bool
 }
 
 var personaCmd = &cobra.Command{
@@ -40,7 +47,18 @@ Examples:
   dorgu persona apply ./my-app --namespace commerce
 
   # Check persona status on cluster
-  dorgu persona status order-service -n commerce`,
+  dorgu persona status order-service -n commerce
+
+  # List, inspect, and delete personas
+  dorgu persona list -n commerce
+  dorgu persona get order-service -n commerce -o json
+  dorgu persona delete order-service -n commerce
+
+  # Regenerate PERSONA.md, keeping hand-written sections
+  dorgu persona refresh ./my-app
+
+  # Export the workload as a Score spec
+  dorgu persona export ./my-app --format score`,
 }
 
 var personaGenerateCmd = &cobra.Command{
@@ -54,7 +72,8 @@ Examples:
   dorgu persona generate .
   dorgu persona generate ./my-app --namespace production
   dorgu persona generate ./my-app --dry-run
-  dorgu persona generate ./my-app -o ./manifests`,
+  dorgu persona generate ./my-app --output-dir ./manifests
+  dorgu persona generate ./my-app --format json --dry-run | jq .spec`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runPersonaGenerate,
 }
@@ -65,13 +84,24 @@ var personaApplyCmd = &cobra.Command{
 	Long: `Analyze an application, generate the ApplicationPersona CRD YAML,
 and apply it to the current Kubernetes cluster using kubectl.
 
+When the persona already exists, a server-side dry run is performed first and
+the spec fields that would change are shown for confirmation. Use --yes to
+skip the confirmation (e.g. in CI).
+
+With --via-operator the persona is sent to the Dorgu Operator over WebSocket
+instead of applied with kubectl. The operator validates it, records who
+submitted it, and applies it with its own permissions, so developers do not
+need RBAC to create ApplicationPersonas directly.
+
 Requires:
   - kubectl configured and accessible
   - ApplicationPersona CRD installed on the cluster (via Dorgu Operator)
 
 Examples:
   dorgu persona apply ./my-app --namespace commerce
-  dorgu persona apply ./my-app -n default`,
+  dorgu persona apply ./my-app -n default
+  dorgu persona apply ./my-app -n commerce --yes
+  dorgu persona apply ./my-app -n commerce --via-operator`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runPersonaApply,
 }
@@ -83,33 +113,96 @@ var personaStatusCmd = &cobra.Command{
 from the Kubernetes cluster, including validation results, health status,
 learned patterns, and recommendations.
 
+With --live the status is read from the Dorgu Operator over WebSocket
+instead of from the persona's status subresource via kubectl.
+
 Examples:
   dorgu persona status order-service -n commerce
-  dorgu persona status my-app`,
+  dorgu persona status my-app -o wide
+  dorgu persona status my-app -o json
+  dorgu persona status my-app --live`,
 	Args: cobra.ExactArgs(1),
 	RunE: runPersonaStatus,
 }
 
+var personaRefreshCmd = &cobra.Command{
+	Use:   "refresh [path]",
+	Short: "Regenerate PERSONA.md while preserving human-edited sections",
+	Long: `Re-analyze an application and regenerate its PERSONA.md, merging the
+new analysis-driven sections into the existing document.
+
+Sections containing the marker comment
+  <!-- dorgu:keep -->
+are kept verbatim, and sections that dorgu does not generate (notes added by
+operators) are carried over unchanged.
+
+Examples:
+  dorgu persona refresh .
+  dorgu persona refresh ./my-app --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPersonaRefresh,
+}
+
+var personaExportCmd = &cobra.Command{
+	Use:   "export [path]",
+	Short: "Export the application's workload spec for other platforms",
+	Long: `Analyze an application and export its workload in another
+specification, for platforms that consume it instead of Kubernetes manifests.
+
+Formats:
+  score  A Score (score.dev) workload in score.yaml: containers with their
+         image, variables, resources, and probes, the service ports, and the
+         dependencies as resources (postgres, redis, amqp, ...). Secret
+         variables are read from the environment resource.
+
+Examples:
+  dorgu persona export . --format score
+  dorgu persona export ./my-app --format score --dry-run
+  dorgu persona export ./my-app --format score --output-dir ./deploy`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPersonaExport,
+}
+
 func init() {
 	// Generate flags
 	personaGenerateCmd.Flags().StringVarP(&personaFlags.namespace, "namespace", "n", "default", "target Kubernetes namespace")
-	personaGenerateCmd.Flags().StringVarP(&personaFlags.outputDir, "output", "o", ".", "output directory for persona.yaml")
+	personaGenerateCmd.Flags().StringVar(&personaFlags.outputDir, "output-dir", ".", "output directory for persona.yaml")
 	personaGenerateCmd.Flags().BoolVar(&personaFlags.dryRun, "dry-run", false, "print to stdout without writing files")
 	personaGenerateCmd.Flags().StringVar(&personaFlags.llmProvider, "llm-provider", "", "LLM provider for analysis")
 	personaGenerateCmd.Flags().StringVar(&personaFlags.name, "name", "", "override application name")
+	personaGenerateCmd.Flags().StringVar(&personaFlags.format, "format", "yaml", "output format: yaml or json")
 
 	// Apply flags
 	personaApplyCmd.Flags().StringVarP(&personaFlags.namespace, "namespace", "n", "default", "target Kubernetes namespace")
 	personaApplyCmd.Flags().StringVar(&personaFlags.llmProvider, "llm-provider", "", "LLM provider for analysis")
 	personaApplyCmd.Flags().StringVar(&personaFlags.name, "name", "", "override application name")
+	personaApplyCmd.Flags().BoolVarP(&personaFlags.yes, "yes", "y", false, "apply without asking for confirmation")
+	personaApplyCmd.Flags().BoolVar(&personaFlags.viaOperator, "via-operator", false, "apply through the Dorgu Operator instead of kubectl")
+	personaApplyCmd.Flags().StringVar(&personaFlags.operatorURL, "operator-url", "", operatorURLUsage)
 
 	// Status flags
 	personaStatusCmd.Flags().StringVarP(&personaFlags.namespace, "namespace", "n", "default", "Kubernetes namespace")
+	personaStatusCmd.Flags().BoolVar(&personaFlags.live, "live", false, "read status from the Dorgu Operator instead of kubectl")
+	personaStatusCmd.Flags().StringVar(&personaFlags.operatorURL, "operator-url", "", operatorURLUsage)
+
+	// Refresh flags
+	personaRefreshCmd.Flags().BoolVar(&personaFlags.dryRun, "dry-run", false, "print the merged PERSONA.md without writing it")
+	personaRefreshCmd.Flags().StringVar(&personaFlags.llmProvider, "llm-provider", "", "LLM provider for analysis")
+	personaRefreshCmd.Flags().StringVar(&personaFlags.name, "name", "", "override application name")
+
+	// Export flags
+	personaExportCmd.Flags().StringVar(&personaFlags.exportFormat, "format", "score", "export format: score")
+	personaExportCmd.Flags().StringVar(&personaFlags.outputDir, "output-dir", ".", "output directory for the exported file")
+	personaExportCmd.Flags().BoolVar(&personaFlags.dryRun, "dry-run", false, "print to stdout without writing files")
+	personaExportCmd.Flags().StringVar(&personaFlags.llmProvider, "llm-provider", "", "LLM provider for analysis")
+	personaExportCmd.Flags().StringVar(&personaFlags.name, "name", "", "override application name")
 
 	// Register subcommands
 	personaCmd.AddCommand(personaGenerateCmd)
 	personaCmd.AddCommand(personaApplyCmd)
 	personaCmd.AddCommand(personaStatusCmd)
+	personaCmd.AddCommand(personaRefreshCmd)
+	personaCmd.AddCommand(personaExportCmd)
 }
 
 func runPersonaGenerate(cmd *cobra.Command, args []string) error {
@@ -118,23 +211,37 @@ func runPersonaGenerate(cmd *cobra.Command, args []string) error {
 		targetPath = args[0]
 	}
 
-	personaYAML, err := generatePersonaFromPath(targetPath)
+	outputDir := legacyOutputDir(personaFlags.outputDir)
+	if err := validateOutputFormat(outputFormat, "", "json", "yaml"); err != nil {
+		return err
+	}
+	// With -o the persona itself is the structured output
+	format := personaFlags.format
+	if isStructuredOutput() {
+		format = outputFormat
+	}
+	if format != "yaml" && format != "json" {
+		return fmt.Errorf("unsupported format %q (supported: yaml, json)", format)
+	}
+
+	content, err := generatePersonaFromPath(cmd.Context(), targetPath, format)
 	if err != nil {
 		return err
 	}
 
-	if personaFlags.dryRun {
-		fmt.Println(personaYAML)
+	if personaFlags.dryRun || isStructuredOutput() {
+		fmt.Print(content)
 		return nil
 	}
 
 	// Write to file
-	outputPath := filepath.Join(personaFlags.outputDir, "persona.yaml")
-	if err := os.MkdirAll(personaFlags.outputDir, 0o755); err != nil {
+	fileName := "persona." + format
+	outputPath := filepath.Join(outputDir, fileName)
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
-	if err := os.WriteFile(outputPath, []byte(personaYAML), 0o644); err != nil {
-		return fmt.Errorf("failed to write persona.yaml: %w", err)
+	if err := os.WriteFile(outputPath, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", fileName, err)
 	}
 
 	output.Success(fmt.Sprintf("Generated persona: %s", outputPath))
@@ -147,66 +254,352 @@ func runPersonaApply(cmd *cobra.Command, args []string) error {
 		targetPath = args[0]
 	}
 
-	// Check kubectl availability
-	if _, err := exec.LookPath("kubectl"); err != nil {
-		return fmt.Errorf("kubectl not found in PATH; required for persona apply")
+	if personaFlags.viaOperator {
+		persona, err := buildPersonaFromPath(cmd.Context(), targetPath)
+		if err != nil {
+			return err
+		}
+		return applyPersonaViaOperator(cmd.Context(), persona)
 	}
 
-	personaYAML, err := generatePersonaFromPath(targetPath)
+	client, err := kube.NewClient()
+	if err != nil {
+		return fmt.Errorf("%w; required for persona apply", err)
+	}
+
+	persona, err := buildPersonaFromPath(cmd.Context(), targetPath)
 	if err != nil {
 		return err
 	}
+	return applyPersonaWithKubectl(cmd.Context(), client, persona)
+}
+
+// applyPersonaWithKubectl shows the server-side diff for a persona and, once
+// confirmed, applies it with kubectl
+func applyPersonaWithKubectl(ctx context.Context, client *kube.Client, persona *types.ApplicationPersona) error {
+	personaYAML, err := generator.MarshalPersona(persona, "yaml")
+	if err != nil {
+		return fmt.Errorf("persona generation failed: %w", err)
+	}
+	namespace := persona.Metadata.Namespace
+	name := persona.Metadata.Name
+
+	existing, err := client.GetPersona(ctx, namespace, name)
+	switch {
+	case errors.Is(err, kube.ErrNotFound):
+		output.Info(fmt.Sprintf("ApplicationPersona '%s' does not exist in namespace '%s' and will be created", name, namespace))
+	case err != nil:
+		return personaKubeError(err, name)
+	default:
+		// Diff against what the server would actually persist (defaults applied)
+		planned, err := client.ApplyPersona(ctx, namespace, []byte(personaYAML), true)
+		if err != nil {
+			return fmt.Errorf("server-side dry run failed: %w", err)
+		}
+		changes, err := kube.Diff(existing.Spec, planned.Spec)
+		if err != nil {
+			return fmt.Errorf("failed to diff persona: %w", err)
+		}
+		if len(changes) == 0 {
+			output.Success(fmt.Sprintf("ApplicationPersona '%s' is up to date", name))
+			return nil
+		}
+		output.Header(fmt.Sprintf("Changes to ApplicationPersona %s/%s", namespace, name))
+		fmt.Print(kube.FormatDiff(changes))
+		fmt.Println()
+	}
+
+	ok, err := confirmApply()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		output.Warn("Apply cancelled")
+		return nil
+	}
 
-	// Apply via kubectl
 	output.Info("Applying ApplicationPersona to cluster...")
-	kubectlCmd := exec.Command("kubectl", "apply", "-f", "-", "-n", personaFlags.namespace)
-	kubectlCmd.Stdin = bytes.NewBufferString(personaYAML)
-	kubectlCmd.Stdout = os.Stdout
-	kubectlCmd.Stderr = os.Stderr
-	if err := kubectlCmd.Run(); err != nil {
+	if _, err := client.ApplyPersona(ctx, namespace, []byte(personaYAML), false); err != nil {
 		return fmt.Errorf("kubectl apply failed: %w", err)
 	}
 
 	output.Success("ApplicationPersona applied successfully")
+	notifyApply(ctx, persona, nil)
+	return nil
+}
+
+// applyPersonaViaOperator mirrors the kubectl apply flow, with the operator
+// performing the dry run, validation, and the apply itself
+func applyPersonaViaOperator(ctx context.Context, persona *types.ApplicationPersona) error {
+	client, cleanup, err := connectOperator(ctx, personaFlags.operatorURL)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	namespace := persona.Metadata.Namespace
+	name := persona.Metadata.Name
+
+	existing, err := client.GetPersona(ctx, namespace, name)
+	if err != nil && !ws.IsNotFound(err) {
+		return fmt.Errorf("failed to get persona %s from operator: %w", name, err)
+	}
+
+	planned, err := client.ApplyPersona(ctx, persona, true)
+	if err != nil {
+		return fmt.Errorf("operator dry run failed: %w", err)
+	}
+	printOperatorValidation(planned.Validation)
+
+	if existing == nil {
+		output.Info(fmt.Sprintf("ApplicationPersona '%s' does not exist in namespace '%s' and will be created", name, namespace))
+	} else if planned.Persona != nil {
+		changes, err := kube.Diff(existing.Spec, planned.Persona.Spec)
+		if err != nil {
+			return fmt.Errorf("failed to diff persona: %w", err)
+		}
+		if len(changes) == 0 {
+			output.Success(fmt.Sprintf("ApplicationPersona '%s' is up to date", name))
+			return nil
+		}
+		output.Header(fmt.Sprintf("Changes to ApplicationPersona %s/%s", namespace, name))
+		fmt.Print(kube.FormatDiff(changes))
+		fmt.Println()
+	}
+
+	ok, err := confirmApply()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		output.Warn("Apply cancelled")
+		return nil
+	}
+
+	output.Info("Sending ApplicationPersona to the Dorgu Operator...")
+	result, err := client.ApplyPersona(ctx, persona, false)
+	if err != nil {
+		return fmt.Errorf("operator apply failed: %w", err)
+	}
+
+	output.Success(fmt.Sprintf("ApplicationPersona %s/%s %s by the operator", namespace, name, orNone(result.Result)))
+	notifyApply(ctx, persona, planned.Validation)
 	return nil
 }
 
+// printOperatorValidation shows the issues the operator found in a persona
+func printOperatorValidation(v *types.PersonaValidation) {
+	if v == nil || len(v.Issues) == 0 {
+		return
+	}
+	output.Info("Operator validation")
+	for _, issue := range v.Issues {
+		field := ""
+		if issue.Field != "" {
+			field = issue.Field + ": "
+		}
+		fmt.Printf("  [%s] %s%s\n", colorSeverity(issue.Severity), field, issue.Message)
+	}
+	fmt.Println()
+}
+
+// confirmApply asks for confirmation unless --yes was given
+func confirmApply() (bool, error) {
+	if personaFlags.yes {
+		return true, nil
+	}
+	return confirm("Apply these changes?")
+}
+
 func runPersonaStatus(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
-	// Check kubectl availability
-	if _, err := exec.LookPath("kubectl"); err != nil {
-		return fmt.Errorf("kubectl not found in PATH; required for persona status")
+	if err := validateOutputFormat(outputFormat, "", "wide", "yaml", "json"); err != nil {
+		return err
 	}
 
-	// Get the persona resource
-	kubectlCmd := exec.Command("kubectl", "get", "applicationpersona", name,
-		"-n", personaFlags.namespace, "-o", "yaml")
-	rawOutput, err := kubectlCmd.CombinedOutput()
-	if err != nil {
-		outputStr := strings.TrimSpace(string(rawOutput))
-		if strings.Contains(outputStr, "not found") {
-			return fmt.Errorf("ApplicationPersona '%s' not found in namespace '%s'", name, personaFlags.namespace)
+	var persona *types.ApplicationPersona
+	if personaFlags.live {
+		client, cleanup, err := connectOperator(cmd.Context(), personaFlags.operatorURL)
+		if err != nil {
+			return err
 		}
-		if strings.Contains(outputStr, "the server doesn't have a resource type") {
-			return fmt.Errorf("ApplicationPersona CRD is not installed on this cluster. Install the Dorgu Operator first")
+		defer cleanup()
+
+		persona, err = client.GetPersona(cmd.Context(), personaFlags.namespace, name)
+		if err != nil {
+			return fmt.Errorf("failed to get persona %s from operator: %w", name, err)
+		}
+	} else {
+		client, err := kube.NewAPIClient("")
+		if err != nil {
+			return err
+		}
+
+		persona, err = client.GetPersona(cmd.Context(), personaFlags.namespace, name)
+		if err != nil {
+			return personaKubeError(err, name)
 		}
-		return fmt.Errorf("failed to get persona: %s", outputStr)
 	}
 
-	// Parse and display in a human-friendly format
-	displayPersonaStatus(name, string(rawOutput))
+	if handled, err := printStructured(persona.Status, outputFormat); handled {
+		return err
+	}
+
+	displayPersonaStatus(persona, outputFormat == "wide")
 	return nil
 }
 
-// generatePersonaFromPath runs the analysis pipeline and generates persona YAML.
-func generatePersonaFromPath(targetPath string) (string, error) {
+func runPersonaRefresh(cmd *cobra.Command, args []string) error {
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
 	absPath, err := filepath.Abs(targetPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to resolve path: %w", err)
+		return fmt.Errorf("failed to resolve path: %w", err)
 	}
 	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("path does not exist: %s", absPath)
+		return fmt.Errorf("path does not exist: %s", absPath)
+	}
+
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		globalCfg = config.DefaultGlobalConfig()
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.Default()
+	}
+
+	effectiveProvider := globalCfg.GetEffectiveProvider(personaFlags.llmProvider)
+	if effectiveProvider == "" {
+		effectiveProvider = cfg.LLM.Provider
+	}
+
+	personaPath := filepath.Join(absPath, "PERSONA.md")
+	existing, err := os.ReadFile(personaPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", personaPath, err)
+	}
+
+	s := newSpinner(" Analyzing application...")
+	s.Start()
+
+	pipeline, err := analyzer.RunPipeline(cmd.Context(), absPath, analyzer.PipelineOptions{
+		LLMProvider: effectiveProvider,
+		Name:        personaFlags.name,
+		Persona:     true,
+		// Same analyzers as generate
+		DisabledAnalyzers: cfg.Analyzers.Disabled,
+	})
+	if err != nil {
+		s.Stop()
+		return fmt.Errorf("analysis failed: %w", err)
+	}
+
+	generated := generator.RenderPersonaMarkdown(cmd.Context(), pipeline.Analysis, generator.Options{
+		Config:       cfg,
+		Persona:      pipeline.Persona,
+		NoLLMPersona: pipeline.PersonaErr != nil,
+	})
+	s.Stop()
+
+	merged := generator.MergePersonaMarkdown(string(existing), generated)
+
+	if personaFlags.dryRun {
+		fmt.Print(merged)
+		return nil
+	}
+
+	if string(existing) == merged {
+		output.Info("PERSONA.md is already up to date")
+		return nil
+	}
+	if err := os.WriteFile(personaPath, []byte(merged), 0o644); err != nil {
+		return fmt.Errorf("failed to write PERSONA.md: %w", err)
+	}
+
+	if len(existing) == 0 {
+		output.Success(fmt.Sprintf("Created %s", personaPath))
+	} else {
+		output.Success(fmt.Sprintf("Refreshed %s", personaPath))
+	}
+	return nil
+}
+
+func runPersonaExport(cmd *cobra.Command, args []string) error {
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	if personaFlags.exportFormat != "score" {
+		return fmt.Errorf("unsupported export format %q (supported: score)", personaFlags.exportFormat)
+	}
+
+	analysis, cfg, err := analyzePersonaPath(cmd.Context(), targetPath)
+	if err != nil {
+		return err
+	}
+	content, err := generator.GenerateScoreSpec(analysis, cfg)
+	if err != nil {
+		return fmt.Errorf("score export failed: %w", err)
+	}
+
+	if personaFlags.dryRun {
+		fmt.Print(content)
+		return nil
+	}
+
+	outputDir := legacyOutputDir(personaFlags.outputDir)
+	outputPath := filepath.Join(outputDir, generator.ScoreFile)
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", generator.ScoreFile, err)
+	}
+
+	output.Success(fmt.Sprintf("Exported Score workload: %s", outputPath))
+	return nil
+}
+
+// generatePersonaFromPath runs the analysis pipeline and renders the persona
+// in the given format (yaml or json).
+func generatePersonaFromPath(ctx context.Context, targetPath, format string) (string, error) {
+	persona, err := buildPersonaFromPath(ctx, targetPath)
+	if err != nil {
+		return "", err
+	}
+	content, err := generator.MarshalPersona(persona, format)
+	if err != nil {
+		return "", fmt.Errorf("persona generation failed: %w", err)
+	}
+	return content, nil
+}
+
+// buildPersonaFromPath runs the analysis pipeline and builds the persona.
+func buildPersonaFromPath(ctx context.Context, targetPath string) (*types.ApplicationPersona, error) {
+	analysis, cfg, err := analyzePersonaPath(ctx, targetPath)
+	if err != nil {
+		return nil, err
+	}
+	persona, err := generator.BuildPersona(analysis, personaFlags.namespace, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("persona generation failed: %w", err)
+	}
+	return persona, nil
+}
+
+// analyzePersonaPath runs the analysis behind persona generate, apply, and
+// export, with the config chain loaded and --name applied.
+func analyzePersonaPath(ctx context.Context, targetPath string) (*types.AppAnalysis, *config.Config, error) {
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("path does not exist: %s", absPath)
 	}
 
 	// Load config chain
@@ -227,19 +620,18 @@ func generatePersonaFromPath(targetPath string) (string, error) {
 		effectiveProvider = cfg.LLM.Provider
 	}
 
-	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
-	s.Suffix = " Analyzing application..."
+	s := newSpinner(" Analyzing application...")
 	s.Start()
 
-	analysis, err := analyzer.Analyze(absPath, effectiveProvider)
+	analysis, err := analyzer.Analyze(ctx, absPath, effectiveProvider, cfg.Analyzers.Disabled)
 	if err != nil {
 		s.Stop()
-		return "", fmt.Errorf("analysis failed: %w", err)
+		return nil, nil, fmt.Errorf("analysis failed: %w", err)
 	}
 
 	// Git repo auto-detect
 	if analysis.Repository == "" {
-		if gitURL := analyzer.DetectGitRemoteURL(absPath); gitURL != "" {
+		if gitURL := analyzer.DetectGitRemoteURL(ctx, absPath); gitURL != "" {
 			analysis.Repository = gitURL
 		}
 	}
@@ -248,48 +640,129 @@ func generatePersonaFromPath(targetPath string) (string, error) {
 		analysis.Name = personaFlags.name
 	}
 
-	s.Suffix = " Generating persona..."
-
-	personaYAML, err := generator.GeneratePersonaYAML(analysis, personaFlags.namespace, cfg)
 	s.Stop()
-	if err != nil {
-		return "", fmt.Errorf("persona generation failed: %w", err)
-	}
-
-	return personaYAML, nil
+	return analysis, cfg, nil
 }
 
-// displayPersonaStatus formats and prints persona status information.
-func displayPersonaStatus(name string, rawYAML string) {
-	output.Header(fmt.Sprintf("ApplicationPersona: %s", name))
+// displayPersonaStatus prints persona status in a human-friendly format.
+// wide adds deployment history, learned patterns, and suggested actions.
+func displayPersonaStatus(persona *types.ApplicationPersona, wide bool) {
+	output.Header(fmt.Sprintf("ApplicationPersona: %s", persona.Metadata.Name))
+
+	status := persona.Status
+	if status == nil || status.Phase == "" && status.Health == nil && status.Validation == nil {
+		output.Dim("  No status available yet. The Dorgu Operator may not have reconciled this persona.")
+		return
+	}
 
-	// Simple line-based parsing for status display
-	lines := strings.Split(rawYAML, "\n")
-	inStatus := false
-	indent := 0
+	fmt.Printf("  Phase:        %s\n", colorPersonaPhase(status.Phase))
+	if status.Health != nil {
+		health := status.Health.Status
+		if health == "" {
+			health = "Unknown"
+		}
+		fmt.Printf("  Health:       %s\n", colorHealth(health))
+		if status.Health.Message != "" {
+			fmt.Printf("  Message:      %s\n", status.Health.Message)
+		}
+	}
+	if status.LastUpdated != "" {
+		fmt.Printf("  Last Updated: %s\n", status.LastUpdated)
+	}
 
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
+	if d := status.Deployments; d != nil {
+		fmt.Println()
+		output.Info("Deployments")
+		printField("Current", d.Current)
+		printField("Last Successful", d.LastSuccessful)
+		printField("Last Failed", d.LastFailed)
+		if wide {
+			for _, h := range d.History {
+				fmt.Printf("    %s  %-10s %s (by %s)\n", h.Timestamp, h.Status, h.Version, h.TriggeredBy)
+			}
+		}
+	}
 
-		if trimmed == "status:" {
-			inStatus = true
-			indent = len(line) - len(strings.TrimLeft(line, " "))
-			continue
+	if v := status.Validation; v != nil {
+		fmt.Println()
+		if v.Passed {
+			output.Info("Validation: " + output.Green("passed"))
+		} else {
+			output.Info("Validation: " + output.Red("failed"))
 		}
+		for _, issue := range v.Issues {
+			field := ""
+			if issue.Field != "" {
+				field = issue.Field + ": "
+			}
+			fmt.Printf("  [%s] %s%s\n", colorSeverity(issue.Severity), field, issue.Message)
+			if wide && issue.Suggestion != "" {
+				output.Dim("      → " + issue.Suggestion)
+			}
+		}
+	}
 
-		if inStatus {
-			currentIndent := len(line) - len(strings.TrimLeft(line, " "))
-			// Stop when we leave the status block
-			if currentIndent <= indent && trimmed != "" && !strings.HasPrefix(trimmed, "#") {
-				break
+	if l := status.Learned; l != nil {
+		fmt.Println()
+		output.Info("Learned Patterns")
+		if b := l.ResourceBaseline; b != nil {
+			fmt.Printf("  CPU:       avg %s, peak %s\n", b.AvgCPU, b.PeakCPU)
+			fmt.Printf("  Memory:    avg %s, peak %s\n", b.AvgMemory, b.PeakMemory)
+		}
+		fmt.Printf("  Incidents: %d\n", l.IncidentCount)
+		if wide {
+			printField("Last Incident", l.LastIncident)
+			for _, p := range l.Patterns {
+				fmt.Printf("  • %s: %s (confidence %.0f%%)\n", p.Type, p.Description, p.Confidence*100)
 			}
-			if trimmed != "" {
-				fmt.Println("  " + trimmed)
+		}
+	}
+
+	if len(status.Recommendations) > 0 {
+		fmt.Println()
+		output.Info("Recommendations")
+		for _, r := range status.Recommendations {
+			fmt.Printf("  [%s] %s: %s\n", r.Priority, r.Type, r.Message)
+			if wide && r.Action != "" {
+				output.Dim("      → " + r.Action)
 			}
 		}
 	}
 
-	if !inStatus {
-		output.Dim("  No status available yet. The Dorgu Operator may not have reconciled this persona.")
+	if !wide {
+		fmt.Println()
+		output.Dim("Use -o wide for history and suggestions, or -o yaml|json for the raw status")
+	}
+}
+
+func printField(label, value string) {
+	if value != "" {
+		fmt.Printf("  %-16s %s\n", label+":", value)
+	}
+}
+
+func colorPersonaPhase(phase string) string {
+	switch phase {
+	case "Active":
+		return output.Green(phase)
+	case "Degraded", "Pending":
+		return output.Yellow(phase)
+	case "Failed":
+		return output.Red(phase)
+	case "":
+		return "Unknown"
+	default:
+		return phase
+	}
+}
+
+func colorSeverity(severity string) string {
+	switch severity {
+	case "error":
+		return output.Red(severity)
+	case "warning":
+		return output.Yellow(severity)
+	default:
+		return output.Blue(severity)
 	}
 }
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/kube"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var personaListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List ApplicationPersonas on the cluster",
+	Long: `List ApplicationPersona resources with their type, tier, phase, and health.
+
+Examples:
+  dorgu persona list
+  dorgu persona list -n commerce
+  dorgu persona list --all-namespaces
+  dorgu persona list -o json`,
+	Args: cobra.NoArgs,
+	RunE: runPersonaList,
+}
+
+var personaGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print an ApplicationPersona as YAML or JSON",
+	Long: `Fetch an ApplicationPersona from the cluster, including its status.
+
+Examples:
+  dorgu persona get order-service -n commerce
+  dorgu persona get order-service -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPersonaGet,
+}
+
+var personaDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete an ApplicationPersona from the cluster",
+	Long: `Delete an ApplicationPersona resource. The application's workloads are
+not affected; only the persona record is removed.
+
+Examples:
+  dorgu persona delete order-service -n commerce`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPersonaDelete,
+}
+
+func init() {
+	personaListCmd.Flags().StringVarP(&personaFlags.namespace, "namespace", "n", "default", "Kubernetes namespace")
+	personaListCmd.Flags().BoolVarP(&personaFlags.allNamespaces, "all-namespaces", "A", false, "list personas across all namespaces")
+
+	personaGetCmd.Flags().StringVarP(&personaFlags.namespace, "namespace", "n", "default", "Kubernetes namespace")
+
+	personaDeleteCmd.Flags().StringVarP(&personaFlags.namespace, "namespace", "n", "default", "Kubernetes namespace")
+
+	personaCmd.AddCommand(personaListCmd)
+	personaCmd.AddCommand(personaGetCmd)
+	personaCmd.AddCommand(personaDeleteCmd)
+}
+
+func runPersonaList(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat, "", "yaml", "json"); err != nil {
+		return err
+	}
+
+	client, err := kube.NewAPIClient("")
+	if err != nil {
+		return err
+	}
+
+	namespace := personaFlags.namespace
+	if personaFlags.allNamespaces {
+		namespace = ""
+	}
+
+	personas, err := client.ListPersonas(cmd.Context(), namespace)
+	if err != nil {
+		return personaKubeError(err, "")
+	}
+	if handled, err := printStructured(personas, outputFormat); handled {
+		return err
+	}
+	if len(personas) == 0 {
+		if namespace == "" {
+			output.Info("No ApplicationPersonas found. Create one with: dorgu persona apply <path>")
+		} else {
+			output.Info(fmt.Sprintf("No ApplicationPersonas found in namespace '%s'. Create one with: dorgu persona apply <path>", namespace))
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	if personaFlags.allNamespaces {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tTYPE\tTIER\tPHASE\tHEALTH\tAGE")
+	for _, p := range personas {
+		if personaFlags.allNamespaces {
+			fmt.Fprintf(w, "%s\t", p.Metadata.Namespace)
+		}
+		phase, health := "<none>", "<none>"
+		if p.Status != nil {
+			if p.Status.Phase != "" {
+				phase = p.Status.Phase
+			}
+			if p.Status.Health != nil && p.Status.Health.Status != "" {
+				health = p.Status.Health.Status
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			p.Metadata.Name, p.Spec.Type, p.Spec.Tier, phase, health, formatAge(p.Metadata.CreationTimestamp))
+	}
+	return w.Flush()
+}
+
+func runPersonaGet(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	format := outputFormat
+	if format == "" {
+		format = "yaml"
+	}
+	if err := validateOutputFormat(format, "yaml", "json"); err != nil {
+		return err
+	}
+
+	client, err := kube.NewAPIClient("")
+	if err != nil {
+		return err
+	}
+
+	persona, err := client.GetPersona(cmd.Context(), personaFlags.namespace, name)
+	if err != nil {
+		return personaKubeError(err, name)
+	}
+	// kubectl bookkeeping is noise for humans and downstream tooling
+	delete(persona.Metadata.Annotations, kube.LastAppliedAnnotation)
+
+	_, err = printStructured(persona, format)
+	return err
+}
+
+func runPersonaDelete(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	client, err := kube.NewAPIClient("")
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeletePersona(cmd.Context(), personaFlags.namespace, name); err != nil {
+		return personaKubeError(err, name)
+	}
+
+	output.Success(fmt.Sprintf("Deleted ApplicationPersona '%s' from namespace '%s'", name, personaFlags.namespace))
+	return nil
+}
+
+// personaKubeError turns kube client errors into user-facing messages
+func personaKubeError(err error, name string) error {
+	switch {
+	case errors.Is(err, kube.ErrCRDNotInstalled):
+		return fmt.Errorf("ApplicationPersona CRD is not installed on this cluster. Install it with: dorgu crd install")
+	case errors.Is(err, kube.ErrNotFound) && name != "":
+		return fmt.Errorf("ApplicationPersona '%s' not found in namespace '%s'", name, personaFlags.namespace)
+	default:
+		return fmt.Errorf("cluster request failed: %w", err)
+	}
+}
+
+// formatAge renders an RFC 3339 timestamp as a kubectl-style age (e.g. 5d, 3h)
+func formatAge(timestamp string) string {
+	created, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return "<unknown>"
+	}
+	age := time.Since(created)
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
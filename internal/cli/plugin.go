@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Inspect generator plugins",
+	Long: `Plugins are executables that run after the built-in generators and can add
+or replace manifests (e.g. company CRDs or Vault annotations).
+
+Enable a plugin in .dorgu.yaml:
+
+  plugins:
+    - name: vault-annotations      # runs dorgu-vault-annotations from PATH
+      config:
+        role: apps
+    - name: company-crds
+      path: ./tools/dorgu-company-crds
+
+A plugin reads a JSON request (apiVersion, analysis, namespace, files, config)
+on stdin and writes {"files": [{"path": ..., "content": ...}], "warnings": [...]}
+on stdout. Files with an existing path replace it; new paths are added.`,
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured plugins and dorgu-* executables on PATH",
+	Args:  cobra.NoArgs,
+	RunE:  runPluginList,
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+}
+
+// pluginEntry is one row of plugin list
+type pluginEntry struct {
+	Name    string `json:"name"`
+	Path    string `json:"path,omitempty"`
+	Enabled bool   `json:"enabled"`
+	Error   string `json:"error,omitempty"`
+}
+
+func runPluginList(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat, "", "json", "yaml"); err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.Default()
+	}
+
+	var entries []pluginEntry
+	seen := map[string]bool{}
+	for _, p := range cfg.Plugins {
+		entry := pluginEntry{Name: p.Name, Enabled: true}
+		if entry.Name == "" {
+			entry.Name = filepath.Base(p.Path)
+		}
+		if bin, err := generator.ResolvePlugin(p); err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.Path = bin
+			seen[bin] = true
+		}
+		entries = append(entries, entry)
+	}
+	for _, bin := range discoverPlugins() {
+		if !seen[bin] {
+			name := strings.TrimPrefix(filepath.Base(bin), generator.PluginPrefix)
+			entries = append(entries, pluginEntry{Name: strings.TrimSuffix(name, ".exe"), Path: bin})
+		}
+	}
+
+	if handled, err := printStructured(entries, outputFormat); handled {
+		return err
+	}
+	if len(entries) == 0 {
+		output.Dim("No plugins configured or found on PATH")
+		return nil
+	}
+	fmt.Printf("%-24s %-10s %s\n", "NAME", "STATUS", "PATH")
+	for _, e := range entries {
+		status := "available"
+		switch {
+		case e.Error != "":
+			status = output.Red("missing")
+		case e.Enabled:
+			status = output.Green("enabled")
+		}
+		path := e.Path
+		if e.Error != "" {
+			path = e.Error
+		}
+		fmt.Printf("%-24s %-10s %s\n", truncate(e.Name, 24), status, path)
+	}
+	return nil
+}
+
+// discoverPlugins returns the dorgu-* executables on PATH, first match per name
+func discoverPlugins() []string {
+	byName := map[string]string{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		matches, _ := filepath.Glob(filepath.Join(dir, generator.PluginPrefix+"*"))
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+				continue
+			}
+			if _, ok := byName[filepath.Base(m)]; !ok {
+				byName[filepath.Base(m)] = m
+			}
+		}
+	}
+	paths := make([]string, 0, len(byName))
+	for _, p := range byName {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
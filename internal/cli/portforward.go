@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/kube"
+	"github.com/dorgu-ai/dorgu/internal/output"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+var portForwardFlags struct {
+	namespace   string
+	kubeContext string
+	localPort   int
+}
+
+var portForwardCmd = &cobra.Command{
+	Use:   "port-forward <app> [port]",
+	Short: "Forward a local port to a ready pod of an app, using its persona",
+	Long: `Forward a local port to one of an app's ready pods until Ctrl+C.
+
+The app's ApplicationPersona says which ports it serves and what they are
+for: without [port], the first one that is not for metrics is forwarded.
+The pod is picked by the app's dorgu labels.
+
+Examples:
+  dorgu port-forward orders -n production
+  dorgu port-forward orders 9090 -n production
+  dorgu port-forward orders --local-port 18080`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runPortForward,
+}
+
+func init() {
+	f := portForwardCmd.Flags()
+	f.StringVarP(&portForwardFlags.namespace, "namespace", "n", "default", "namespace of the app and its persona")
+	f.StringVar(&portForwardFlags.kubeContext, "context", "", "kubeconfig context (default: the current context)")
+	f.IntVar(&portForwardFlags.localPort, "local-port", 0, "local port (default: the same as the pod's)")
+}
+
+func runPortForward(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	requested := 0
+	if len(args) > 1 {
+		p, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid port %q", args[1])
+		}
+		requested = p
+	}
+	client, err := kube.NewClientForContext(portForwardFlags.kubeContext)
+	if err != nil {
+		return fmt.Errorf("%w; required for port-forward", err)
+	}
+	persona, pod, err := personaPod(ctx, client, portForwardFlags.namespace, args[0])
+	if err != nil {
+		return err
+	}
+	port, err := personaPort(persona, requested)
+	if err != nil {
+		return err
+	}
+
+	local := portForwardFlags.localPort
+	if local == 0 {
+		local = port.Port
+	}
+	pf, err := client.PortForwardFrom(ctx, portForwardFlags.namespace, "pod/"+pod.Name, local, port.Port)
+	if err != nil {
+		return err
+	}
+	defer pf.Stop()
+	purpose := ""
+	if port.Purpose != "" {
+		purpose = " (" + port.Purpose + ")"
+	}
+	output.Success(fmt.Sprintf("Forwarding 127.0.0.1:%d -> %s:%d%s", pf.LocalPort, pod.Name, port.Port, purpose))
+	output.Dim("Press Ctrl+C to stop")
+	<-ctx.Done()
+	fmt.Println()
+	return nil
+}
+
+// personaPod returns the app's ApplicationPersona and one of its pods,
+// preferring ready ones
+func personaPod(ctx context.Context, client *kube.Client, namespace, app string) (*types.ApplicationPersona, kube.Pod, error) {
+	persona, err := client.GetPersona(ctx, namespace, app)
+	switch {
+	case errors.Is(err, kube.ErrNotFound):
+		return nil, kube.Pod{}, fmt.Errorf("ApplicationPersona '%s' not found in namespace '%s'; apply it with 'dorgu persona apply'", app, namespace)
+	case err != nil:
+		return nil, kube.Pod{}, fmt.Errorf("failed to get the persona of %s: %w", app, err)
+	}
+	name := persona.Spec.Name
+	if name == "" {
+		name = app
+	}
+	pods, err := appPods(ctx, client, namespace, name, "")
+	if err != nil {
+		return nil, kube.Pod{}, err
+	}
+	for _, p := range pods {
+		if p.Ready {
+			return persona, p, nil
+		}
+	}
+	output.Warn(fmt.Sprintf("No pod of %s is ready; using %s (%s)", name, pods[0].Name, pods[0].Phase))
+	return persona, pods[0], nil
+}
+
+// personaPort returns the persona's port requested, or the first one not
+// for metrics when requested is 0
+func personaPort(persona *types.ApplicationPersona, requested int) (types.PersonaPort, error) {
+	var ports []types.PersonaPort
+	if persona.Spec.Networking != nil {
+		ports = persona.Spec.Networking.Ports
+	}
+	if len(ports) == 0 {
+		return types.PersonaPort{}, fmt.Errorf("the persona of %s records no ports", persona.Metadata.Name)
+	}
+	var available []string
+	for _, p := range ports {
+		if requested != 0 && p.Port == requested {
+			return p, nil
+		}
+		available = append(available, strconv.Itoa(p.Port))
+	}
+	if requested != 0 {
+		return types.PersonaPort{}, fmt.Errorf("%s does not serve port %d; its persona records %s", persona.Metadata.Name, requested, strings.Join(available, ", "))
+	}
+	for _, p := range ports {
+		if !strings.Contains(strings.ToLower(p.Purpose), "metric") {
+			return p, nil
+		}
+	}
+	return ports[0], nil
+}
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/kube"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var previewFlags struct {
+	runtime     string
+	cluster     string
+	namespace   string
+	image       string
+	name        string
+	llmProvider string
+	wait        time.Duration
+	portForward bool
+	keep        bool
+}
+
+var previewCmd = &cobra.Command{
+	Use:   "preview [path]",
+	Short: "Run the generated manifests on a local kind or minikube cluster",
+	Long: `Check that an application actually runs with the manifests dorgu generates:
+
+  1. Generate the manifests (nothing is written to disk)
+  2. Create the local cluster, unless it already exists
+  3. Build the app's Dockerfile and load the image into the cluster
+  4. Apply the manifests into a temporary namespace
+  5. Wait for the Deployment to become ready
+  6. Port-forward the Service's ports until Ctrl+C
+  7. Delete the namespace, and the cluster if preview created it
+
+Custom resources whose CRDs the cluster lacks (ServiceMonitor,
+SecretProviderClass, ...) are skipped. Use --image to preview an image that
+is already built, --port-forward=false to stop once the app is ready (e.g. in
+CI), and --keep to leave the namespace and cluster running.
+
+Requires docker, kubectl, and kind or minikube.
+
+Examples:
+  dorgu preview .
+  dorgu preview ./my-app --runtime minikube
+  dorgu preview ./my-app --image orders:dev
+  dorgu preview ./my-app --port-forward=false --wait 5m`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPreview,
+}
+
+func init() {
+	f := previewCmd.Flags()
+	f.StringVar(&previewFlags.runtime, "runtime", kube.RuntimeKind, "local cluster runtime: kind or minikube")
+	f.StringVar(&previewFlags.cluster, "cluster", "dorgu-preview", "name of the kind cluster or minikube profile")
+	f.StringVar(&previewFlags.namespace, "namespace", "", "namespace to deploy into (default: a new dorgu-preview-<id> namespace)")
+	f.StringVar(&previewFlags.image, "image", "", "local image to preview instead of building the Dockerfile")
+	f.StringVarP(&previewFlags.name, "name", "n", "", "override application name")
+	f.StringVar(&previewFlags.llmProvider, "llm-provider", "", "LLM provider: openai, anthropic, gemini, ollama (default from config)")
+	f.DurationVar(&previewFlags.wait, "wait", 3*time.Minute, "how long to wait for the Deployment to become ready")
+	f.BoolVar(&previewFlags.portForward, "port-forward", true, "port-forward the Service until Ctrl+C; otherwise tear down once ready")
+	f.BoolVar(&previewFlags.keep, "keep", false, "leave the namespace and cluster running")
+}
+
+func runPreview(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("path does not exist: %s", absPath)
+	}
+	if previewFlags.runtime != kube.RuntimeKind && previewFlags.runtime != kube.RuntimeMinikube {
+		return fmt.Errorf("unsupported --runtime %q (use kind or minikube)", previewFlags.runtime)
+	}
+	namespace := previewFlags.namespace
+	if namespace == "" {
+		namespace = "dorgu-preview-" + strconv.FormatInt(time.Now().Unix(), 36)
+	}
+
+	gen, err := generateApp(ctx, absPath, generateOptions{
+		outputDir:      "k8s",
+		name:           previewFlags.name,
+		namespace:      namespace,
+		llmProvider:    previewFlags.llmProvider,
+		skipArgoCD:     true,
+		skipCI:         true,
+		skipPersona:    true,
+		skipReadme:     true,
+		skipValidation: true,
+	})
+	if err != nil {
+		return err
+	}
+	name := gen.analysis.Name
+
+	cluster := kube.LocalCluster{Runtime: previewFlags.runtime, Name: previewFlags.cluster}
+	exists, err := cluster.Exists(ctx)
+	if err != nil {
+		return err
+	}
+	// Teardown runs after Ctrl+C, so it gets its own context
+	teardown := context.WithoutCancel(ctx)
+	if !exists {
+		output.Info(fmt.Sprintf("Creating %s cluster %s...", cluster.Runtime, cluster.Name))
+		if err := cluster.Create(ctx); err != nil {
+			return err
+		}
+		if !previewFlags.keep {
+			defer func() {
+				output.Info(fmt.Sprintf("Deleting %s cluster %s", cluster.Runtime, cluster.Name))
+				if err := cluster.Delete(teardown); err != nil {
+					output.Warn(err.Error())
+				}
+			}()
+		}
+	}
+
+	client, err := kube.NewClientForContext(cluster.Context())
+	if err != nil {
+		return fmt.Errorf("%w; required for preview", err)
+	}
+
+	image := previewFlags.image
+	if image == "" {
+		image = name + ":dorgu-preview"
+		output.Info(fmt.Sprintf("Building %s...", image))
+		if err := dockerBuild(ctx, absPath, image); err != nil {
+			return err
+		}
+	}
+	output.Info(fmt.Sprintf("Loading %s into the cluster...", image))
+	if err := cluster.LoadImage(ctx, image); err != nil {
+		return err
+	}
+
+	if _, err := client.Run(ctx, "create", "namespace", namespace); err != nil {
+		return fmt.Errorf("failed to create namespace %s: %w", namespace, err)
+	}
+	if !previewFlags.keep {
+		defer func() {
+			output.Info("Deleting namespace " + namespace)
+			if _, err := client.Run(teardown, "delete", "namespace", namespace, "--wait=false"); err != nil {
+				output.Warn(fmt.Sprintf("Failed to delete namespace %s: %v", namespace, err))
+			}
+		}()
+	}
+
+	files := generator.ReplaceImage(gen.files, gen.analysis, gen.config, image)
+	for _, f := range files {
+		if !generator.IsManifest(f) {
+			continue
+		}
+		if _, err := client.RunWithInput(ctx, []byte(f.Content), "apply", "-n", namespace, "-f", "-"); err != nil {
+			if errors.Is(err, kube.ErrCRDNotInstalled) {
+				output.Warn(fmt.Sprintf("Skipped %s: its CRD is not installed in the preview cluster", f.Path))
+				continue
+			}
+			return fmt.Errorf("failed to apply %s: %w", f.Path, err)
+		}
+		output.Dim("  applied " + f.Path)
+	}
+
+	s := newSpinner(fmt.Sprintf(" Waiting for deployment/%s to become ready...", name))
+	s.Start()
+	_, err = client.Run(ctx, "rollout", "status", "deployment/"+name, "-n", namespace, "--timeout", previewFlags.wait.String())
+	s.Stop()
+	if err != nil {
+		printPreviewPods(ctx, client, namespace, name)
+		return fmt.Errorf("deployment/%s did not become ready: %w", name, err)
+	}
+	output.Success(fmt.Sprintf("%s is running in %s/%s", name, cluster.Context(), namespace))
+
+	if !previewFlags.portForward {
+		return nil
+	}
+	var svc struct {
+		Spec struct {
+			Ports []struct {
+				Port int `json:"port"`
+			} `json:"ports"`
+		} `json:"spec"`
+	}
+	switch err := client.GetJSON(ctx, &svc, "service", name, "-n", namespace); {
+	case errors.Is(err, kube.ErrNotFound):
+		// No ports to forward
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to read service/%s: %w", name, err)
+	}
+	for _, p := range svc.Spec.Ports {
+		pf, err := client.PortForward(ctx, namespace, "svc/"+name, p.Port)
+		if err != nil {
+			output.Warn(err.Error())
+			continue
+		}
+		defer pf.Stop()
+		fmt.Printf("  http://127.0.0.1:%d -> %s:%d\n", pf.LocalPort, name, p.Port)
+	}
+	output.Dim("Press Ctrl+C to stop the preview")
+	<-ctx.Done()
+	fmt.Println()
+	return nil
+}
+
+// dockerBuild builds the Dockerfile in dir as image
+func dockerBuild(ctx context.Context, dir, image string) error {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return fmt.Errorf("docker not found in PATH; build the image yourself and pass --image")
+	}
+	build := exec.CommandContext(ctx, "docker", "build", "-t", image, dir)
+	var out bytes.Buffer
+	build.Stdout = &out
+	build.Stderr = &out
+	if err := build.Run(); err != nil {
+		return fmt.Errorf("docker build failed: %w\n%s", err, strings.TrimSpace(out.String()))
+	}
+	return nil
+}
+
+// printPreviewPods shows the app's pods and recent events, to explain why
+// the Deployment is not ready
+func printPreviewPods(ctx context.Context, client *kube.Client, namespace, name string) {
+	if out, err := client.Run(ctx, "get", "pods", "-n", namespace, "-l", "app.kubernetes.io/name="+name); err == nil {
+		fmt.Print(string(out))
+	}
+	if out, err := client.Run(ctx, "get", "events", "-n", namespace, "--sort-by", ".lastTimestamp"); err == nil {
+		fmt.Print(string(out))
+	}
+}
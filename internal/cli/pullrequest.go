@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/output"
+	"github.com/dorgu-ai/dorgu/internal/vcs"
+)
+
+// newPullRequest renders the pull request title and body configured under
+// pull_request in .dorgu.yaml (or the defaults) for the written paths
+func newPullRequest(gen *generation, paths []string, head, base string) (vcs.PullRequest, error) {
+	cfg := gen.config.PullRequest
+	data := generator.PullRequestData{
+		Analysis:   gen.analysis,
+		Namespace:  gen.namespace,
+		Validation: gen.validation,
+	}
+	for _, p := range paths {
+		data.Files = append(data.Files, filepath.ToSlash(p))
+	}
+	persona, err := generator.BuildPersona(gen.analysis, gen.namespace, gen.config)
+	if err != nil {
+		slog.Warn("pull request body will not include the persona", "err", err)
+	}
+	data.Persona = persona
+
+	titleTmpl := cfg.Title
+	if titleTmpl == "" {
+		titleTmpl = generator.DefaultPullRequestTitle
+	}
+	bodyTmpl := generator.DefaultPullRequestBody
+	if cfg.BodyTemplate != "" {
+		src, err := os.ReadFile(cfg.BodyTemplate)
+		if err != nil {
+			return vcs.PullRequest{}, fmt.Errorf("failed to read pull_request.body_template: %w", err)
+		}
+		bodyTmpl = string(src)
+	}
+
+	title, err := generator.RenderPullRequest("title", titleTmpl, data)
+	if err != nil {
+		return vcs.PullRequest{}, err
+	}
+	body, err := generator.RenderPullRequest("body", bodyTmpl, data)
+	if err != nil {
+		return vcs.PullRequest{}, err
+	}
+	if base == "" {
+		base = cfg.Base
+	}
+	return vcs.PullRequest{Title: title, Body: body, Head: head, Base: base}, nil
+}
+
+// openPullRequest commits paths to pr.Head, pushes it to origin, and opens a
+// pull request against pr.Base (the current branch when empty). The working
+// tree is left on the new branch.
+func openPullRequest(ctx context.Context, dir string, paths []string, pr vcs.PullRequest) (string, error) {
+	repo, err := vcs.Open(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+	remoteURL, err := repo.RemoteURL(ctx, "origin")
+	if err != nil {
+		return "", fmt.Errorf("no origin remote: %w", err)
+	}
+	remote, err := vcs.ParseRemote(remoteURL)
+	if err != nil {
+		return "", err
+	}
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		output.Warn(fmt.Sprintf("Failed to load global config: %v", err))
+		globalCfg = config.DefaultGlobalConfig()
+	}
+	provider, err := vcs.NewProvider(remote, globalCfg.GetGitToken(remote.Kind()))
+	if err != nil {
+		return "", err
+	}
+	if pr.Base == "" {
+		if pr.Base, err = repo.CurrentBranch(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	if err := repo.CreateBranch(ctx, pr.Head); err != nil {
+		return "", err
+	}
+	if err := repo.CommitPaths(ctx, pr.Title, paths...); err != nil {
+		return "", err
+	}
+	output.Info(fmt.Sprintf("Pushing %s to origin...", pr.Head))
+	if err := repo.Push(ctx, "origin", pr.Head); err != nil {
+		return "", err
+	}
+	return provider.CreatePullRequest(ctx, pr)
+}
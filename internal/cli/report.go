@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/kube"
+	"github.com/dorgu-ai/dorgu/internal/output"
+	"github.com/dorgu-ai/dorgu/internal/report"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+var reportFlags struct {
+	cluster       bool
+	namespace     string
+	allNamespaces bool
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report [path]",
+	Short: "Report on all apps from their personas",
+	Long: `Aggregate ApplicationPersonas into an org-wide inventory:
+
+  - apps per team
+  - apps missing an owner or a runbook
+  - apps without health probes
+  - CPU and memory requests and limits, totalled at minimum replicas
+
+By default the persona files (e.g. k8s/persona.yaml) under path (default:
+current directory) are read. With --cluster, the ApplicationPersonas on the
+cluster are used instead.
+
+Output formats (-o): markdown (default), csv, json, yaml.
+
+Examples:
+  dorgu report
+  dorgu report ./services -o csv > inventory.csv
+  dorgu report --cluster --all-namespaces > inventory.md`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().BoolVar(&reportFlags.cluster, "cluster", false, "read ApplicationPersonas from the cluster")
+	reportCmd.Flags().StringVarP(&reportFlags.namespace, "namespace", "n", "default", "Kubernetes namespace (with --cluster)")
+	reportCmd.Flags().BoolVarP(&reportFlags.allNamespaces, "all-namespaces", "A", false, "report on personas across all namespaces (with --cluster)")
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat, "", "markdown", "csv", "json", "yaml"); err != nil {
+		return err
+	}
+
+	var personas []types.ApplicationPersona
+	var sources []string
+	if reportFlags.cluster {
+		if len(args) > 0 {
+			return fmt.Errorf("--cluster does not take a path")
+		}
+		client, err := kube.NewAPIClient("")
+		if err != nil {
+			return err
+		}
+		namespace := reportFlags.namespace
+		if reportFlags.allNamespaces {
+			namespace = ""
+		}
+		if personas, err = client.ListPersonas(cmd.Context(), namespace); err != nil {
+			return personaKubeError(err, "")
+		}
+	} else {
+		targetPath := "."
+		if len(args) > 0 {
+			targetPath = args[0]
+		}
+		absPath, err := filepath.Abs(targetPath)
+		if err != nil {
+			return fmt.Errorf("failed to resolve path: %w", err)
+		}
+		if _, err := os.Stat(absPath); os.IsNotExist(err) {
+			return fmt.Errorf("path does not exist: %s", absPath)
+		}
+		if personas, sources, err = report.LoadFiles(absPath); err != nil {
+			return err
+		}
+	}
+	if len(personas) == 0 {
+		output.Info("No ApplicationPersonas found. Generate them with: dorgu generate <path>")
+		return nil
+	}
+
+	r := report.Build(personas, sources)
+	switch outputFormat {
+	case "", "markdown":
+		fmt.Print(r.Markdown())
+		return nil
+	case "csv":
+		out, err := r.CSV()
+		if err != nil {
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	}
+	_, err := printStructured(r, outputFormat)
+	return err
+}
@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/kube"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var rollbackFlags struct {
+	namespace       string
+	kubeContext     string
+	toRevision      int
+	argocd          bool
+	argocdNamespace string
+	argocdServer    string
+	list            bool
+	yes             bool
+	wait            time.Duration
+}
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <app>",
+	Short: "Show an app's rollout history and roll back to an earlier revision",
+	Long: `Show the revisions of an app's Deployment with their images, and roll
+back to the previous one (or --to-revision) after confirmation.
+
+With --argocd the history comes from the app's ArgoCD Application instead,
+and the rollback redeploys an earlier sync through the ArgoCD API server
+(--argocd-server or ARGOCD_SERVER) with the token in ARGOCD_AUTH_TOKEN. Use
+it for apps ArgoCD syncs automatically: ArgoCD would revert a Deployment
+rollback on its next sync.
+
+Examples:
+  dorgu rollback orders -n production --list
+  dorgu rollback orders -n production
+  dorgu rollback orders -n production --to-revision 12 --yes
+  dorgu rollback orders --argocd`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRollback,
+}
+
+func init() {
+	f := rollbackCmd.Flags()
+	f.StringVarP(&rollbackFlags.namespace, "namespace", "n", "default", "namespace of the app's Deployment")
+	f.StringVar(&rollbackFlags.kubeContext, "context", "", "kubeconfig context (default: the current context)")
+	f.IntVar(&rollbackFlags.toRevision, "to-revision", 0, "revision (or ArgoCD history ID) to roll back to (default: the previous one)")
+	f.BoolVar(&rollbackFlags.argocd, "argocd", false, "roll back the app's ArgoCD Application instead of its Deployment")
+	f.StringVar(&rollbackFlags.argocdNamespace, "argocd-namespace", "argocd", "namespace of the ArgoCD Application (with --argocd)")
+	f.StringVar(&rollbackFlags.argocdServer, "argocd-server", "", "ArgoCD API server address (with --argocd; default: $ARGOCD_SERVER)")
+	f.BoolVar(&rollbackFlags.list, "list", false, "only show the history")
+	f.BoolVarP(&rollbackFlags.yes, "yes", "y", false, "roll back without asking for confirmation")
+	f.DurationVar(&rollbackFlags.wait, "wait", 2*time.Minute, "how long to wait for the rolled-back Deployment to become ready; 0 to skip")
+}
+
+func runRollback(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	name := args[0]
+	client, err := kube.NewClientForContext(rollbackFlags.kubeContext)
+	if err != nil {
+		return fmt.Errorf("%w; required for rollback", err)
+	}
+	if rollbackFlags.argocd {
+		return rollbackArgoCD(ctx, client, name)
+	}
+
+	history, err := client.DeploymentHistory(ctx, rollbackFlags.namespace, name)
+	if err != nil {
+		return fmt.Errorf("failed to read the history of deployment/%s: %w", name, err)
+	}
+	output.Header(fmt.Sprintf("deployment/%s in %s", name, rollbackFlags.namespace))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tAGE\tIMAGES\tCHANGE-CAUSE")
+	current := 0
+	for _, r := range history {
+		number := fmt.Sprint(r.Number)
+		if r.Current {
+			number += " (current)"
+			current = r.Number
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", number, formatAge(r.Created.Format(time.RFC3339)), strings.Join(r.Images, ", "), r.ChangeCause)
+	}
+	w.Flush()
+	fmt.Println()
+	if rollbackFlags.list {
+		return nil
+	}
+
+	var numbers []int
+	for _, r := range history {
+		numbers = append(numbers, r.Number)
+	}
+	target, err := rollbackTarget(rollbackFlags.toRevision, current, numbers)
+	if err != nil {
+		return err
+	}
+
+	// An automatically synced ArgoCD app would put the current revision back
+	if app, err := client.GetArgoCDApp(ctx, rollbackFlags.argocdNamespace, name); err == nil && app.AutoSync() {
+		output.Warn(fmt.Sprintf("ArgoCD syncs %s automatically and will revert this rollback; use --argocd, or revert the change in git", name))
+	}
+
+	ok, err := confirmRollback(fmt.Sprintf("Roll back deployment/%s from revision %d to %d?", name, current, target))
+	if err != nil || !ok {
+		return err
+	}
+	if err := client.RollbackDeployment(ctx, rollbackFlags.namespace, name, target); err != nil {
+		return fmt.Errorf("rollback failed: %w", err)
+	}
+	if rollbackFlags.wait > 0 {
+		s := newSpinner(fmt.Sprintf(" Waiting for deployment/%s to become ready...", name))
+		s.Start()
+		_, err := client.Run(ctx, "rollout", "status", "deployment/"+name, "-n", rollbackFlags.namespace, "--timeout", rollbackFlags.wait.String())
+		s.Stop()
+		if err != nil {
+			return fmt.Errorf("deployment/%s did not become ready after the rollback: %w", name, err)
+		}
+	}
+	output.Success(fmt.Sprintf("Rolled back deployment/%s to revision %d", name, target))
+	return nil
+}
+
+// rollbackArgoCD shows the sync history of the app's ArgoCD Application and
+// redeploys an earlier sync
+func rollbackArgoCD(ctx context.Context, client *kube.Client, name string) error {
+	app, err := client.GetArgoCDApp(ctx, rollbackFlags.argocdNamespace, name)
+	if err != nil {
+		return fmt.Errorf("failed to read application/%s in %s: %w", name, rollbackFlags.argocdNamespace, err)
+	}
+	output.Header(fmt.Sprintf("ArgoCD application/%s", name))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tAGE\tREVISION")
+	current := 0
+	for i, h := range app.Status.History {
+		id := fmt.Sprint(h.ID)
+		if i == 0 {
+			id += " (current)"
+			current = h.ID
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", id, formatAge(h.DeployedAt.Format(time.RFC3339)), h.Revision)
+	}
+	w.Flush()
+	fmt.Println()
+	if rollbackFlags.list {
+		return nil
+	}
+
+	var ids []int
+	for _, h := range app.Status.History {
+		ids = append(ids, h.ID)
+	}
+	target, err := rollbackTarget(rollbackFlags.toRevision, current, ids)
+	if err != nil {
+		return err
+	}
+	server, err := argoCDServer()
+	if err != nil {
+		return err
+	}
+	if app.AutoSync() {
+		return fmt.Errorf("ArgoCD does not roll back apps with automated sync; disable it (argocd app set %s --sync-policy none) or revert the change in git", name)
+	}
+	ok, err := confirmRollback(fmt.Sprintf("Redeploy ArgoCD history %d of %s?", target, name))
+	if err != nil || !ok {
+		return err
+	}
+	if err := server.RollbackApp(ctx, rollbackFlags.argocdNamespace, name, target); err != nil {
+		return err
+	}
+	output.Success(fmt.Sprintf("Rolled back %s to ArgoCD history %d", name, target))
+	return nil
+}
+
+// argoCDServer returns the ArgoCD API server from --argocd-server or
+// ARGOCD_SERVER, authenticated with ARGOCD_AUTH_TOKEN like the argocd CLI
+func argoCDServer() (*kube.ArgoCDServer, error) {
+	addr := rollbackFlags.argocdServer
+	if addr == "" {
+		addr = os.Getenv("ARGOCD_SERVER")
+	}
+	if addr == "" {
+		return nil, fmt.Errorf("ArgoCD API server not set; use --argocd-server or ARGOCD_SERVER")
+	}
+	token := os.Getenv("ARGOCD_AUTH_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("ARGOCD_AUTH_TOKEN is not set; create one with `argocd account generate-token`")
+	}
+	return kube.NewArgoCDServer(addr, token), nil
+}
+
+// rollbackTarget returns the revision to roll back to: requested when set,
+// which must be in history, otherwise the newest one before current
+func rollbackTarget(requested, current int, history []int) (int, error) {
+	target := 0
+	for _, n := range history {
+		switch {
+		case requested != 0:
+			if n == requested {
+				target = n
+			}
+		case n < current && n > target:
+			target = n
+		}
+	}
+	switch {
+	case requested == current && requested != 0:
+		return 0, fmt.Errorf("revision %d is already the current one", requested)
+	case target == 0 && requested != 0:
+		return 0, fmt.Errorf("revision %d is not in the history", requested)
+	case target == 0:
+		return 0, fmt.Errorf("no earlier revision to roll back to")
+	}
+	return target, nil
+}
+
+// confirmRollback asks for confirmation unless --yes was given
+func confirmRollback(question string) (bool, error) {
+	if rollbackFlags.yes {
+		return true, nil
+	}
+	ok, err := confirm(question)
+	if err == nil && !ok {
+		output.Info("Rollback cancelled")
+	}
+	return ok, err
+}
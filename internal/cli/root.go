@@ -1,16 +1,36 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/dorgu-ai/dorgu/internal/logging"
+	"github.com/dorgu-ai/dorgu/internal/output"
 )
 
 var (
 	// Config file path
 	cfgFile string
+
+	// Global -o flag; empty means human-readable output
+	outputFormat string
+
+	// Logging flags
+	verbosity int
+	debugLogs bool
+	logFormat string
+
+	// Global --timeout; zero means no limit
+	timeout       time.Duration
+	cancelTimeout context.CancelFunc = func() {}
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -25,36 +45,103 @@ Examples:
   dorgu generate ./my-app
 
   # Generate with custom output directory
-  dorgu generate ./my-app --output ./manifests
+  dorgu generate ./my-app --output-dir ./manifests
 
   # Initialize org standards config
-  dorgu init`,
+  dorgu init
+
+  # Machine-readable output for scripting
+  dorgu generate ./my-app --dry-run -o json`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateOutputFormat(logFormat, "text", "json"); err != nil {
+			return fmt.Errorf("--log-format: %w", err)
+		}
+
+		// Keep stdout clean for the structured document
+		if isStructuredOutput() {
+			output.SetMessageWriter(os.Stderr)
+		}
+		if quiet {
+			output.SetQuiet(true)
+		}
+		if viper.GetBool("no-color") || os.Getenv("NO_COLOR") != "" || ciEnvironment() {
+			output.DisableColor()
+		}
+		if timeout < 0 {
+			return fmt.Errorf("--timeout must not be negative")
+		}
+		if timeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			cmd.SetContext(ctx)
+			cancelTimeout = cancel
+		}
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
+// Commands run with a context cancelled on Ctrl+C, SIGTERM, or --timeout.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer func() { cancelTimeout() }()
+	return rootCmd.ExecuteContext(ctx)
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(initLogging, initConfig)
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is .dorgu.yaml)")
 	rootCmd.PersistentFlags().Bool("no-color", false, "disable colored output")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "output format: json or yaml (some commands also support wide)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "hide spinners and informational messages")
+	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "log more detail to stderr (-v info, -vv debug)")
+	rootCmd.PersistentFlags().BoolVar(&debugLogs, "debug", false, "log debug detail to stderr (same as -vv)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "log format: text or json")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "abort analysis, LLM calls, and generation after this long, e.g. 2m (0 means no limit)")
+	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "never prompt; use flags and defaults (implied when CI is set or stdin is not a terminal)")
 
 	// Bind to viper
 	viper.BindPFlag("no-color", rootCmd.PersistentFlags().Lookup("no-color"))
 
 	// Add subcommands
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(upgradeCmd)
 	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(onboardCmd)
+	rootCmd.AddCommand(scoreCmd)
+	rootCmd.AddCommand(costCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(explainRuleCmd)
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(personaCmd)
 	rootCmd.AddCommand(clusterCmd)
+	rootCmd.AddCommand(crdCmd)
+	rootCmd.AddCommand(pluginCmd)
 	rootCmd.AddCommand(watchCmd)
 	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(previewCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(rollbackCmd)
+	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(eventsCmd)
+	rootCmd.AddCommand(portForwardCmd)
+	rootCmd.AddCommand(execCmd)
+}
+
+// initLogging installs the slog logger selected by -v, --debug, and --log-format
+func initLogging() {
+	logging.Setup(os.Stderr, logging.Options{
+		Level: logging.LevelFromFlags(verbosity, debugLogs),
+		JSON:  logFormat == "json",
+	})
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -75,6 +162,6 @@ func initConfig() {
 
 	// If a config file is found, read it in
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+		slog.Info("using config file", "path", viper.ConfigFileUsed())
 	}
 }
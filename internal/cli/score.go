@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var scoreFlags struct {
+	name        string
+	namespace   string
+	llmProvider string
+	minScore    int
+}
+
+var scoreCmd = &cobra.Command{
+	Use:   "score [path]",
+	Short: "Score an application's production readiness",
+	Long: `Analyze an application, generate its manifests in memory, and grade them
+against production-readiness checks: probes, resource requests and limits,
+PodDisruptionBudget, security context, owner metadata, runbook, alerts,
+pinned image, and TLS. Failed checks come with remediation steps.
+
+Use -o json for org-wide dashboards and --min-score to gate CI.
+
+Examples:
+  dorgu score .
+  dorgu score ./my-app -o json
+  dorgu score ./my-app --min-score 80`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runScore,
+}
+
+func init() {
+	scoreCmd.Flags().StringVar(&scoreFlags.name, "name", "", "override application name")
+	scoreCmd.Flags().StringVar(&scoreFlags.namespace, "namespace", "", "target Kubernetes namespace (overrides config)")
+	scoreCmd.Flags().StringVar(&scoreFlags.llmProvider, "llm-provider", "", "LLM provider: openai, anthropic, gemini, ollama (default from config)")
+	scoreCmd.Flags().IntVar(&scoreFlags.minScore, "min-score", 0, "exit with an error when the score is below this value")
+}
+
+func runScore(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat, "", "json", "yaml"); err != nil {
+		return err
+	}
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("path does not exist: %s", absPath)
+	}
+
+	gen, err := generateApp(cmd.Context(), absPath, generateOptions{
+		name:           scoreFlags.name,
+		namespace:      scoreFlags.namespace,
+		llmProvider:    scoreFlags.llmProvider,
+		skipPersona:    true,
+		skipValidation: true,
+	})
+	if err != nil {
+		return err
+	}
+	card, err := generator.Score(gen.analysis, gen.files)
+	if err != nil {
+		return err
+	}
+
+	if handled, err := printStructured(card, outputFormat); handled {
+		if err != nil {
+			return err
+		}
+		return checkMinScore(card.Score)
+	}
+
+	output.Header(fmt.Sprintf("Production readiness: %s", card.App))
+	fmt.Printf("  Score: %d/100  Grade: %s\n\n", card.Score, colorGrade(card.Grade))
+	for _, c := range card.Checks {
+		mark := output.Green("✓")
+		if !c.Passed {
+			mark = output.Red("✗")
+		}
+		fmt.Printf("  %s %-36s %3d", mark, c.Name, c.Weight)
+		if c.Detail != "" {
+			fmt.Printf("  (%s)", c.Detail)
+		}
+		fmt.Println()
+	}
+
+	var fixes []generator.ScoreCheck
+	for _, c := range card.Checks {
+		if !c.Passed {
+			fixes = append(fixes, c)
+		}
+	}
+	if len(fixes) > 0 {
+		fmt.Println()
+		output.Info("Remediation")
+		for _, c := range fixes {
+			fmt.Printf("  - %s: %s\n", c.Name, c.Remediation)
+		}
+	}
+	return checkMinScore(card.Score)
+}
+
+func checkMinScore(score int) error {
+	if scoreFlags.minScore > 0 && score < scoreFlags.minScore {
+		return fmt.Errorf("readiness score %d is below --min-score %d", score, scoreFlags.minScore)
+	}
+	return nil
+}
+
+func colorGrade(grade string) string {
+	switch grade {
+	case "A", "B":
+		return output.Green(grade)
+	case "C", "D":
+		return output.Yellow(grade)
+	default:
+		return output.Red(grade)
+	}
+}
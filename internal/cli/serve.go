@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/output"
+	"github.com/dorgu-ai/dorgu/internal/server"
+)
+
+var serveFlags struct {
+	addr           string
+	llmProvider    string
+	llmProviders   []string
+	noLLM          bool
+	maxUploadMB    int64
+	requestTimeout time.Duration
+}
+
+// serveTokenEnv holds the bearer token requests must send, if any
+const serveTokenEnv = "DORGU_SERVE_TOKEN"
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve analyze, generate, and validate over HTTP",
+	Long: `Run dorgu as a long-running HTTP server, so developer portals can analyze
+and generate applications without shelling out to the CLI per request.
+
+Endpoints (POST, multipart or URL-encoded form, JSON responses):
+  /v1/analyze   the application analysis
+  /v1/generate  the generated files and validation report
+  /v1/validate  the validation report only
+  /healthz      liveness (GET)
+
+The app is the "archive" field (.tar.gz or .zip of its directory) or is
+cloned from "git_url" (https only; "ref" and "path" select a branch and a
+subdirectory; the clone uses none of the server's git credentials).
+Optional fields: name, namespace, llm_provider (one of --allow-llm-provider),
+skip_argocd, skip_ci, skip_persona. The .dorgu.yaml in the working directory
+applies to every request; plugins never run.
+
+The server listens on localhost only by default. Before exposing it with
+--addr, set DORGU_SERVE_TOKEN: requests must then send it as an
+"Authorization: Bearer" header.
+
+Examples:
+  dorgu serve
+  DORGU_SERVE_TOKEN=s3cret dorgu serve --addr :8080 --allow-llm-provider ollama
+  curl -F archive=@orders.tar.gz -F namespace=commerce localhost:8080/v1/generate
+  curl -d git_url=https://github.com/acme/shop -d path=services/orders localhost:8080/v1/analyze`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveFlags.addr, "addr", "127.0.0.1:8080", "address to listen on")
+	serveCmd.Flags().StringVar(&serveFlags.llmProvider, "llm-provider", "", "LLM provider: openai, anthropic, gemini, ollama (default from config)")
+	serveCmd.Flags().StringSliceVar(&serveFlags.llmProviders, "allow-llm-provider", nil, "other LLM providers requests may choose with llm_provider (repeatable)")
+	serveCmd.Flags().BoolVar(&serveFlags.noLLM, "no-llm", false, "analyze and write personas without an LLM unless a request sets an allowed llm_provider")
+	serveCmd.Flags().Int64Var(&serveFlags.maxUploadMB, "max-upload-mb", server.DefaultMaxUploadBytes>>20, "maximum request body, extracted app, and clone size in MiB")
+	serveCmd.Flags().DurationVar(&serveFlags.requestTimeout, "request-timeout", server.DefaultRequestTimeout, "maximum time to fetch, analyze, and generate an app per request")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	provider := ""
+	if !serveFlags.noLLM {
+		globalCfg, err := config.LoadGlobalConfig()
+		if err != nil {
+			globalCfg = config.DefaultGlobalConfig()
+		}
+		if provider = globalCfg.GetEffectiveProvider(serveFlags.llmProvider); provider == "" {
+			provider = cfg.LLM.Provider
+		}
+	}
+
+	token := os.Getenv(serveTokenEnv)
+	if token == "" && !loopbackAddr(serveFlags.addr) {
+		output.Warn(fmt.Sprintf("%s is not set: anyone who can reach %s can use the server", serveTokenEnv, serveFlags.addr))
+	}
+
+	srv := &http.Server{
+		Addr: serveFlags.addr,
+		Handler: server.New(server.Options{
+			Config:         cfg,
+			LLMProvider:    provider,
+			LLMProviders:   serveFlags.llmProviders,
+			Token:          token,
+			MaxUploadBytes: serveFlags.maxUploadMB << 20,
+			RequestTimeout: serveFlags.requestTimeout,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+		// Requests time out on their own; this also cuts off slow clients
+		WriteTimeout: serveFlags.requestTimeout + 30*time.Second,
+	}
+
+	// Stop accepting requests on Ctrl+C or --timeout and let running ones finish
+	ctx := cmd.Context()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdown); err != nil {
+			slog.Warn("shutdown failed", "err", err)
+		}
+	}()
+
+	llmInfo := "without an LLM"
+	if provider != "" {
+		llmInfo = "with LLM provider " + provider
+	}
+	output.Info(fmt.Sprintf("Serving on %s %s (Ctrl+C to stop)", serveFlags.addr, llmInfo))
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	output.Info("Server stopped")
+	return nil
+}
+
+// loopbackAddr reports whether addr listens on a loopback address only
+func loopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
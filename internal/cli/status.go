@@ -0,0 +1,396 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/output"
+	"github.com/dorgu-ai/dorgu/internal/ws"
+)
+
+// maxDashboardEvents bounds the recent events and findings panels
+const maxDashboardEvents = 10
+
+var statusFlags struct {
+	operatorURL string
+	namespace   string
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Interactive dashboard of personas, cluster state, and events",
+	Long: `Open a live terminal dashboard backed by the Dorgu Operator.
+
+Shows ApplicationPersonas, the cluster summary, recent events, and
+validation findings in one view, updating in real time over WebSocket.
+This replaces running 'watch', 'sync status', and 'persona status'
+separately.
+
+Keys:
+  r        refresh personas and cluster summary
+  q        quit
+
+Examples:
+  dorgu status
+  dorgu status -n production
+  dorgu status --operator-url ws://localhost:9090/ws`,
+	RunE: runStatus,
+}
+
+func init() {
+	statusCmd.Flags().StringVar(&statusFlags.operatorURL, "operator-url", "", operatorURLUsage)
+	statusCmd.Flags().StringVarP(&statusFlags.namespace, "namespace", "n", "",
+		"Filter by namespace (optional)")
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	operatorURL, stopForward := resolveOperatorURL(ctx, statusFlags.operatorURL)
+	defer stopForward()
+
+	client := newOperatorClient(operatorURL)
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect to operator: %w", err)
+	}
+	defer client.Close()
+
+	m := newDashboardModel(ctx, client, operatorURL, statusFlags.namespace)
+	p := tea.NewProgram(m, tea.WithAltScreen())
+
+	// Operator events are forwarded into the program as messages
+	subscriptions := map[ws.Topic]func(*ws.Message){
+		ws.TopicPersonas: func(msg *ws.Message) {
+			var event ws.PersonaEvent
+			if err := json.Unmarshal(msg.Payload, &event); err == nil {
+				p.Send(personaEventMsg{at: msg.Timestamp, event: event})
+			}
+		},
+		ws.TopicCluster: func(msg *ws.Message) {
+			var event ws.ClusterEvent
+			if err := json.Unmarshal(msg.Payload, &event); err == nil {
+				p.Send(clusterEventMsg{at: msg.Timestamp, event: event})
+			}
+		},
+		ws.TopicEvents: func(msg *ws.Message) {
+			var event validationEvent
+			if err := json.Unmarshal(msg.Payload, &event); err != nil {
+				event.Message = string(msg.Payload)
+			}
+			p.Send(validationEventMsg{at: msg.Timestamp, event: event})
+		},
+	}
+	for topic, handler := range subscriptions {
+		filter := ws.SubscribeFilter{Namespace: statusFlags.namespace}
+		if topic == ws.TopicCluster {
+			// Cluster state is not namespaced
+			filter = ws.SubscribeFilter{}
+		}
+		if err := client.SubscribeWithFilter(ctx, topic, filter, handler); err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+		}
+	}
+
+	_, err := p.Run()
+	return err
+}
+
+// validationEvent is the payload of an events-topic message. Payloads that
+// do not match are shown verbatim.
+type validationEvent struct {
+	EventType string `json:"eventType"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Severity  string `json:"severity,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+type (
+	personaEventMsg struct {
+		at    time.Time
+		event ws.PersonaEvent
+	}
+	clusterEventMsg struct {
+		at    time.Time
+		event ws.ClusterEvent
+	}
+	validationEventMsg struct {
+		at    time.Time
+		event validationEvent
+	}
+	snapshotMsg struct {
+		personas []ws.PersonaSummary
+		cluster  *ws.ClusterResponse
+		err      error
+	}
+	tickMsg time.Time
+)
+
+// dashboardLine is one entry in the events or findings panel
+type dashboardLine struct {
+	at   time.Time
+	text string
+}
+
+type dashboardModel struct {
+	ctx         context.Context
+	client      *ws.Client
+	operatorURL string
+	namespace   string
+
+	personas  map[string]ws.PersonaSummary
+	cluster   *ws.ClusterResponse
+	events    []dashboardLine
+	findings  []dashboardLine
+	lastError string
+	updated   time.Time
+	connected bool
+	width     int
+}
+
+func newDashboardModel(ctx context.Context, client *ws.Client, operatorURL, namespace string) dashboardModel {
+	return dashboardModel{
+		ctx:         ctx,
+		client:      client,
+		operatorURL: operatorURL,
+		namespace:   namespace,
+		personas:    map[string]ws.PersonaSummary{},
+		connected:   true,
+	}
+}
+
+func (m dashboardModel) Init() tea.Cmd {
+	return tea.Batch(m.fetchSnapshot(), tick())
+}
+
+// fetchSnapshot loads the full persona list and cluster summary
+func (m dashboardModel) fetchSnapshot() tea.Cmd {
+	return func() tea.Msg {
+		var snap snapshotMsg
+		list, err := m.client.ListPersonas(m.ctx, m.namespace)
+		if err != nil {
+			snap.err = err
+			return snap
+		}
+		snap.personas = list.Personas
+		// A missing ClusterPersona is not fatal for the dashboard
+		snap.cluster, _ = m.client.GetCluster(m.ctx, "")
+		return snap
+	}
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "r":
+			return m, m.fetchSnapshot()
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+
+	case tickMsg:
+		m.connected = m.client.IsConnected()
+		return m, tick()
+
+	case snapshotMsg:
+		if msg.err != nil {
+			m.lastError = msg.err.Error()
+			return m, nil
+		}
+		m.lastError = ""
+		m.personas = map[string]ws.PersonaSummary{}
+		for _, p := range msg.personas {
+			m.personas[p.Namespace+"/"+p.Name] = p
+		}
+		m.cluster = msg.cluster
+		m.updated = time.Now()
+
+	case personaEventMsg:
+		e := msg.event
+		if m.namespace != "" && e.Namespace != m.namespace {
+			return m, nil
+		}
+		key := e.Namespace + "/" + e.Name
+		switch e.EventType {
+		case "deleted":
+			delete(m.personas, key)
+		default:
+			p := m.personas[key]
+			p.Namespace, p.Name = e.Namespace, e.Name
+			if e.Phase != "" {
+				p.Phase = e.Phase
+			}
+			if e.Health != "" {
+				p.Health = e.Health
+			}
+			m.personas[key] = p
+		}
+		m.events = pushLine(m.events, msg.at, fmt.Sprintf("persona %s %s", key, e.EventType))
+		m.updated = time.Now()
+
+	case clusterEventMsg:
+		e := msg.event
+		if m.cluster == nil {
+			m.cluster = &ws.ClusterResponse{Name: e.Name}
+		}
+		if e.Phase != "" {
+			m.cluster.Phase = e.Phase
+		}
+		if e.NodeCount > 0 {
+			m.cluster.NodeCount = e.NodeCount
+		}
+		if e.ApplicationCount > 0 {
+			m.cluster.ApplicationCount = e.ApplicationCount
+		}
+		m.events = pushLine(m.events, msg.at, fmt.Sprintf("cluster %s %s", e.Name, e.EventType))
+		m.updated = time.Now()
+
+	case validationEventMsg:
+		e := msg.event
+		if m.namespace != "" && e.Namespace != "" && e.Namespace != m.namespace {
+			return m, nil
+		}
+		text := e.Message
+		if e.Name != "" {
+			text = fmt.Sprintf("%s/%s: %s", e.Namespace, e.Name, e.Message)
+		}
+		if e.Severity != "" {
+			m.findings = pushLine(m.findings, msg.at, colorSeverity(e.Severity)+" "+text)
+		} else {
+			m.events = pushLine(m.events, msg.at, text)
+		}
+		m.updated = time.Now()
+	}
+	return m, nil
+}
+
+// pushLine prepends an entry, keeping at most maxDashboardEvents
+func pushLine(lines []dashboardLine, at time.Time, text string) []dashboardLine {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	lines = append([]dashboardLine{{at: at, text: text}}, lines...)
+	if len(lines) > maxDashboardEvents {
+		lines = lines[:maxDashboardEvents]
+	}
+	return lines
+}
+
+var (
+	panelTitleStyle = lipgloss.NewStyle().Bold(true)
+	panelStyle      = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("240")).
+			Padding(0, 1)
+)
+
+func (m dashboardModel) View() string {
+	var b strings.Builder
+
+	conn := output.Green("● connected")
+	if !m.connected {
+		conn = output.Red("● disconnected")
+	}
+	scope := "all namespaces"
+	if m.namespace != "" {
+		scope = "namespace " + m.namespace
+	}
+	b.WriteString(panelTitleStyle.Render("Dorgu Status") + "  " + conn + "  " +
+		output.Blue(m.operatorURL) + "  (" + scope + ")\n")
+	if m.lastError != "" {
+		b.WriteString(output.Red("Error: "+m.lastError) + "\n")
+	}
+
+	width := m.width - 2
+	if width < 40 {
+		width = 80
+	}
+	half := width/2 - 1
+
+	top := lipgloss.JoinHorizontal(lipgloss.Top,
+		m.panel("Cluster", m.clusterView(), half),
+		m.panel("Validation Findings", linesView(m.findings, "No findings"), half),
+	)
+	b.WriteString(top + "\n")
+	b.WriteString(m.panel(fmt.Sprintf("Personas (%d)", len(m.personas)), m.personasView(), width) + "\n")
+	b.WriteString(m.panel("Recent Events", linesView(m.events, "Waiting for events..."), width) + "\n")
+
+	updated := "never"
+	if !m.updated.IsZero() {
+		updated = m.updated.Format("15:04:05")
+	}
+	b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).
+		Render(fmt.Sprintf("Last update %s · r refresh · q quit", updated)))
+	return b.String()
+}
+
+func (m dashboardModel) panel(title, body string, width int) string {
+	return panelStyle.Width(width - 2).Render(panelTitleStyle.Render(title) + "\n" + body)
+}
+
+func (m dashboardModel) clusterView() string {
+	c := m.cluster
+	if c == nil {
+		return "No ClusterPersona reported"
+	}
+	rows := []string{
+		"Name:        " + c.Name,
+		"Environment: " + orNone(c.Environment),
+		"Phase:       " + colorPersonaPhase(orNone(c.Phase)),
+		"Version:     " + orNone(c.KubernetesVer),
+		"Platform:    " + orNone(c.Platform),
+		fmt.Sprintf("Nodes:       %d", c.NodeCount),
+		fmt.Sprintf("Apps:        %d", c.ApplicationCount),
+	}
+	if len(c.Addons) > 0 {
+		rows = append(rows, "Add-ons:     "+strings.Join(c.Addons, ", "))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m dashboardModel) personasView() string {
+	if len(m.personas) == 0 {
+		return "No ApplicationPersonas found"
+	}
+	keys := make([]string, 0, len(m.personas))
+	for k := range m.personas {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	rows := []string{fmt.Sprintf("%-30s %-10s %-10s %-10s %s", "NAME", "TYPE", "TIER", "PHASE", "HEALTH")}
+	for _, k := range keys {
+		p := m.personas[k]
+		rows = append(rows, fmt.Sprintf("%-30s %-10s %-10s %-10s %s",
+			k, orNone(p.Type), orNone(p.Tier), orNone(p.Phase), colorHealth(orNone(p.Health))))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func linesView(lines []dashboardLine, empty string) string {
+	if len(lines) == 0 {
+		return empty
+	}
+	rows := make([]string, 0, len(lines))
+	for _, l := range lines {
+		rows = append(rows, fmt.Sprintf("[%s] %s", l.at.Format("15:04:05"), l.text))
+	}
+	return strings.Join(rows, "\n")
+}
@@ -3,17 +3,25 @@ package cli
 import (
 	"context"
 	"fmt"
-	"time"
+	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/kube"
 	"github.com/dorgu-ai/dorgu/internal/output"
+	"github.com/dorgu-ai/dorgu/internal/types"
 	"github.com/dorgu-ai/dorgu/internal/ws"
 )
 
 var syncFlags struct {
 	operatorURL string
 	namespace   string
+	write       bool
+	diff        bool
+	dir         string
 }
 
 var syncCmd = &cobra.Command{
@@ -30,7 +38,11 @@ Examples:
   dorgu sync status
 
   # Pull latest persona states
-  dorgu sync pull`,
+  dorgu sync pull
+
+  # Show validation results and recommendations
+  dorgu sync validations -n production
+  dorgu sync recommendations order-service -n production`,
 }
 
 var syncStatusCmd = &cobra.Command{
@@ -51,33 +63,101 @@ var syncPullCmd = &cobra.Command{
 	Long: `Connect to the Dorgu Operator and pull the latest state
 for all personas and cluster information.
 
+With --write each ApplicationPersona is saved to <dir>/<namespace>/<name>.yaml
+(status and server-managed metadata stripped), so the directory can be
+committed as a Git mirror of the cluster. With --diff the remote specs are
+compared against those files without writing them.
+
 Examples:
   dorgu sync pull
-  dorgu sync pull -n production`,
+  dorgu sync pull -n production
+  dorgu sync pull --write
+  dorgu sync pull --diff --dir ./personas`,
 	RunE: runSyncPull,
 }
 
+var syncValidationsCmd = &cobra.Command{
+	Use:   "validations",
+	Short: "Show the operator's latest validation results",
+	Long: `Fetch the latest validation result for every ApplicationPersona
+from the Dorgu Operator and list the issues found.
+
+Examples:
+  dorgu sync validations
+  dorgu sync validations -n production`,
+	RunE: runSyncValidations,
+}
+
+var syncRecommendationsCmd = &cobra.Command{
+	Use:   "recommendations [name]",
+	Short: "Show the operator's recommendations for a persona",
+	Long: `Fetch the recommendations the Dorgu Operator has made for an
+ApplicationPersona, such as resource, scaling, or cost changes.
+
+Examples:
+  dorgu sync recommendations order-service -n production`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSyncRecommendations,
+}
+
 func init() {
 	// Common flags
-	syncCmd.PersistentFlags().StringVar(&syncFlags.operatorURL, "operator-url", "ws://localhost:9090/ws",
-		"WebSocket URL of the Dorgu Operator")
+	syncCmd.PersistentFlags().StringVar(&syncFlags.operatorURL, "operator-url", "", operatorURLUsage)
 
 	// Pull flags
 	syncPullCmd.Flags().StringVarP(&syncFlags.namespace, "namespace", "n", "",
 		"Filter by namespace (optional)")
+	syncPullCmd.Flags().BoolVar(&syncFlags.write, "write", false,
+		"write each persona to <dir>/<namespace>/<name>.yaml")
+	syncPullCmd.Flags().BoolVar(&syncFlags.diff, "diff", false,
+		"show differences between remote personas and local files")
+	syncPullCmd.Flags().StringVar(&syncFlags.dir, "dir", "personas",
+		"directory for --write and --diff")
+
+	// Validations flags
+	syncValidationsCmd.Flags().StringVarP(&syncFlags.namespace, "namespace", "n", "",
+		"Filter by namespace (optional)")
+
+	// Recommendations flags
+	syncRecommendationsCmd.Flags().StringVarP(&syncFlags.namespace, "namespace", "n", "",
+		"Kubernetes namespace (default \"default\")")
 
 	// Register subcommands
 	syncCmd.AddCommand(syncStatusCmd)
 	syncCmd.AddCommand(syncPullCmd)
+	syncCmd.AddCommand(syncValidationsCmd)
+	syncCmd.AddCommand(syncRecommendationsCmd)
+}
+
+// syncStatusResult is the -o json|yaml document for sync status
+type syncStatusResult struct {
+	Cluster  *ws.ClusterResponse `json:"cluster,omitempty"`
+	Personas struct {
+		Total   int            `json:"total"`
+		ByPhase map[string]int `json:"byPhase"`
+	} `json:"personas"`
+}
+
+// syncPullResult is the -o json|yaml document for sync pull
+type syncPullResult struct {
+	Cluster  *ws.ClusterResponse `json:"cluster,omitempty"`
+	Personas []ws.PersonaSummary `json:"personas"`
 }
 
 func runSyncStatus(cmd *cobra.Command, args []string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := validateOutputFormat(outputFormat, "", "json", "yaml"); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(cmd.Context())
 	defer cancel()
 
-	output.Info(fmt.Sprintf("Connecting to operator at %s...", syncFlags.operatorURL))
+	operatorURL, stopForward := resolveOperatorURL(ctx, syncFlags.operatorURL)
+	defer stopForward()
+
+	output.Info(fmt.Sprintf("Connecting to operator at %s...", operatorURL))
 
-	client := ws.NewClient(syncFlags.operatorURL)
+	client := newOperatorClient(operatorURL)
 	if err := client.Connect(ctx); err != nil {
 		output.Error(fmt.Sprintf("Connection failed: %v", err))
 		return nil
@@ -85,6 +165,26 @@ func runSyncStatus(cmd *cobra.Command, args []string) error {
 	defer client.Close()
 
 	output.Success("Connected to Dorgu Operator")
+
+	if isStructuredOutput() {
+		var result syncStatusResult
+		cluster, err := client.GetCluster(ctx, "")
+		if err != nil {
+			output.Warn(fmt.Sprintf("Could not get cluster info: %v", err))
+		}
+		result.Cluster = cluster
+		personas, err := client.ListPersonas(ctx, "")
+		if err != nil {
+			return fmt.Errorf("failed to list personas: %w", err)
+		}
+		result.Personas.Total = len(personas.Personas)
+		result.Personas.ByPhase = make(map[string]int)
+		for _, p := range personas.Personas {
+			result.Personas.ByPhase[p.Phase]++
+		}
+		_, err = printStructured(result, outputFormat)
+		return err
+	}
 	fmt.Println()
 
 	// Get cluster info
@@ -132,19 +232,25 @@ func runSyncStatus(cmd *cobra.Command, args []string) error {
 }
 
 func runSyncPull(cmd *cobra.Command, args []string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := validateOutputFormat(outputFormat, "", "json", "yaml"); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(cmd.Context())
 	defer cancel()
 
-	output.Info(fmt.Sprintf("Connecting to operator at %s...", syncFlags.operatorURL))
+	operatorURL, stopForward := resolveOperatorURL(ctx, syncFlags.operatorURL)
+	defer stopForward()
+
+	output.Info(fmt.Sprintf("Connecting to operator at %s...", operatorURL))
 
-	client := ws.NewClient(syncFlags.operatorURL)
+	client := newOperatorClient(operatorURL)
 	if err := client.Connect(ctx); err != nil {
 		return fmt.Errorf("failed to connect to operator: %w", err)
 	}
 	defer client.Close()
 
 	output.Success("Connected to Dorgu Operator")
-	fmt.Println()
 
 	// Pull personas
 	output.Info("Pulling ApplicationPersonas...")
@@ -153,6 +259,23 @@ func runSyncPull(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list personas: %w", err)
 	}
 
+	if isStructuredOutput() {
+		if syncFlags.write || syncFlags.diff {
+			if err := mirrorPersonas(ctx, client, personas.Personas); err != nil {
+				return err
+			}
+		}
+		result := syncPullResult{Personas: personas.Personas}
+		cluster, err := client.GetCluster(ctx, "")
+		if err != nil {
+			output.Warn(fmt.Sprintf("Could not get cluster info: %v", err))
+		}
+		result.Cluster = cluster
+		_, err = printStructured(result, outputFormat)
+		return err
+	}
+
+	fmt.Println()
 	if len(personas.Personas) == 0 {
 		output.Dim("No ApplicationPersonas found")
 	} else {
@@ -176,6 +299,12 @@ func runSyncPull(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if syncFlags.write || syncFlags.diff {
+		if err := mirrorPersonas(ctx, client, personas.Personas); err != nil {
+			return err
+		}
+	}
+
 	// Pull cluster info
 	fmt.Println()
 	output.Info("Pulling ClusterPersona...")
@@ -198,6 +327,166 @@ func runSyncPull(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runSyncValidations(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	client, cleanup, err := connectOperator(ctx, syncFlags.operatorURL)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	results, err := client.ListValidationResults(ctx, syncFlags.namespace)
+	if err != nil {
+		return fmt.Errorf("failed to list validation results: %w", err)
+	}
+	if len(results.Results) == 0 {
+		output.Dim("No validation results found")
+		return nil
+	}
+
+	failed := 0
+	for _, r := range results.Results {
+		status := output.Green("passed")
+		if !r.Passed {
+			status = output.Red("failed")
+			failed++
+		}
+		fmt.Printf("%s/%s: %s", r.Namespace, r.Name, status)
+		if r.LastChecked != "" {
+			fmt.Printf(" (checked %s)", r.LastChecked)
+		}
+		fmt.Println()
+		for _, issue := range r.Issues {
+			field := ""
+			if issue.Field != "" {
+				field = issue.Field + ": "
+			}
+			fmt.Printf("  [%s] %s%s\n", colorSeverity(issue.Severity), field, issue.Message)
+			if issue.Suggestion != "" {
+				output.Dim("      → " + issue.Suggestion)
+			}
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		output.Warn(fmt.Sprintf("%d of %d personas failed validation", failed, len(results.Results)))
+	} else {
+		output.Success(fmt.Sprintf("All %d personas passed validation", len(results.Results)))
+	}
+	return nil
+}
+
+func runSyncRecommendations(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	namespace := syncFlags.namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	ctx := cmd.Context()
+	client, cleanup, err := connectOperator(ctx, syncFlags.operatorURL)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	recs, err := client.GetRecommendations(ctx, namespace, name)
+	if err != nil {
+		return fmt.Errorf("failed to get recommendations for %s: %w", name, err)
+	}
+	if len(recs.Recommendations) == 0 {
+		output.Success(fmt.Sprintf("No recommendations for %s/%s", namespace, name))
+		return nil
+	}
+
+	output.Header(fmt.Sprintf("Recommendations for %s/%s", namespace, name))
+	for _, rec := range recs.Recommendations {
+		fmt.Printf("  [%s] %s: %s\n", rec.Priority, rec.Type, rec.Message)
+		if rec.Action != "" {
+			output.Dim("      → " + rec.Action)
+		}
+	}
+	return nil
+}
+
+// mirrorPersonas fetches each persona in full and writes and/or diffs it,
+// metadata included, against <dir>/<namespace>/<name>.yaml. Server-managed
+// metadata is left out of both.
+func mirrorPersonas(ctx context.Context, client *ws.Client, summaries []ws.PersonaSummary) error {
+	var created, changed, unchanged int
+	// With -o json|yaml stdout carries the document, so report files on stderr
+	out := os.Stdout
+	if isStructuredOutput() {
+		out = os.Stderr
+	}
+	fmt.Fprintln(out)
+	for _, summary := range summaries {
+		remote, err := client.GetPersona(ctx, summary.Namespace, summary.Name)
+		if err != nil {
+			return fmt.Errorf("failed to get persona %s/%s: %w", summary.Namespace, summary.Name, err)
+		}
+		// Only the desired state belongs in Git
+		kube.DesiredPersona(remote)
+
+		path := filepath.Join(syncFlags.dir, summary.Namespace, summary.Name+".yaml")
+		var changes []kube.FieldChange
+		exists := false
+		if data, err := os.ReadFile(path); err == nil {
+			exists = true
+			var local types.ApplicationPersona
+			if err := yaml.Unmarshal(data, &local); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			kube.DesiredPersona(&local)
+			if changes, err = kube.Diff(local, *remote); err != nil {
+				return fmt.Errorf("failed to diff %s: %w", path, err)
+			}
+		}
+
+		switch {
+		case !exists:
+			created++
+			fmt.Fprintf(out, "%s %s\n", output.Green("+"), path)
+		case len(changes) > 0:
+			changed++
+			fmt.Fprintf(out, "%s %s\n", output.Yellow("~"), path)
+			if syncFlags.diff {
+				fmt.Fprint(out, kube.FormatDiff(changes))
+			}
+		default:
+			unchanged++
+			if syncFlags.diff {
+				output.Dim("  " + path + " (unchanged)")
+			}
+			continue
+		}
+
+		if !syncFlags.write {
+			continue
+		}
+		data, err := generator.MarshalPersona(remote, "yaml")
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", path, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	fmt.Fprintln(out)
+	summary := fmt.Sprintf("%d new, %d changed, %d unchanged in %s", created, changed, unchanged, syncFlags.dir)
+	if syncFlags.write {
+		output.Success("Wrote personas: " + summary)
+	} else {
+		output.Info("Compared personas: " + summary)
+	}
+	return nil
+}
+
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
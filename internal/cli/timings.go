@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/briandowns/spinner"
+
+	"github.com/dorgu-ai/dorgu/internal/progress"
+)
+
+// stepVerbs label the spinner for each step group
+var stepVerbs = map[string]string{
+	"analyze":  "Analyzing",
+	"generate": "Generating",
+	"validate": "Validating",
+}
+
+// stepReporter shows the current step on the spinner, logs each finished step
+// with its duration at info level (-v), and records them for --timings
+type stepReporter struct {
+	progress.Recorder
+	spinner *spinner.Spinner
+}
+
+func newStepReporter(s *spinner.Spinner) *stepReporter {
+	return &stepReporter{spinner: s}
+}
+
+// StepStarted implements progress.Reporter
+func (r *stepReporter) StepStarted(name string) {
+	group, detail, _ := strings.Cut(name, "/")
+	suffix := " " + stepVerbs[group]
+	if detail != "" {
+		suffix += " " + detail
+	}
+	r.spinner.Lock()
+	r.spinner.Suffix = suffix + "..."
+	r.spinner.Unlock()
+}
+
+// StepDone implements progress.Reporter
+func (r *stepReporter) StepDone(step progress.Step) {
+	r.Recorder.StepDone(step)
+	slog.Info("step finished", "step", step.Name, "duration", step.Duration.Round(time.Millisecond))
+}
+
+// printTimings writes the --timings summary: the steps slowest first with
+// their share of the run's wall-clock time. Concurrent steps (the analysis and
+// persona LLM calls) overlap, so shares can add up to more than 100%.
+func printTimings(w io.Writer, steps []progress.Step, total time.Duration) {
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STEP\tDURATION\tSHARE")
+	for _, s := range steps {
+		share := 0.0
+		if total > 0 {
+			share = 100 * float64(s.Duration) / float64(total)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%.0f%%\n", s.Name, s.Duration.Round(100*time.Microsecond), share)
+	}
+	fmt.Fprintf(tw, "total\t%s\t\n", total.Round(time.Millisecond))
+	tw.Flush()
+}
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/output"
+	"github.com/dorgu-ai/dorgu/internal/update"
+)
+
+var upgradeFlags struct {
+	check bool
+	force bool
+	yes   bool
+}
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade dorgu to the latest release",
+	Long: `Check GitHub for the latest dorgu release and replace the running binary
+with it. The release archive is verified against the release's published
+SHA-256 checksums before anything is installed.
+
+Local builds (version "dev") are never replaced unless --force is given.
+Set GITHUB_TOKEN to avoid API rate limits.
+
+Examples:
+  dorgu upgrade --check
+  dorgu upgrade
+  dorgu upgrade --yes`,
+	Args: cobra.NoArgs,
+	RunE: runUpgrade,
+}
+
+func init() {
+	upgradeCmd.Flags().BoolVar(&upgradeFlags.check, "check", false, "only report whether a newer release is available")
+	upgradeCmd.Flags().BoolVar(&upgradeFlags.force, "force", false, "install the latest release even if it is not newer (or this is a dev build)")
+	upgradeCmd.Flags().BoolVarP(&upgradeFlags.yes, "yes", "y", false, "upgrade without asking for confirmation")
+}
+
+// upgradeCheck is the -o json|yaml document for upgrade --check
+type upgradeCheck struct {
+	Current         string `json:"current"`
+	Commit          string `json:"commit"`
+	Latest          string `json:"latest"`
+	UpdateAvailable bool   `json:"updateAvailable"`
+	ReleaseURL      string `json:"releaseURL,omitempty"`
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	if err := validateOutputFormat(outputFormat, "", "json", "yaml"); err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	client := update.NewClient()
+
+	s := newSpinner(" Checking for updates...")
+	s.Start()
+	release, err := client.Latest(ctx)
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	current := versionInfo.Version
+	isRelease := update.IsRelease(current)
+	newer := !isRelease || update.CompareVersions(current, release.Version()) < 0
+	check := upgradeCheck{
+		Current:         current,
+		Commit:          versionInfo.Commit,
+		Latest:          release.Version(),
+		UpdateAvailable: isRelease && newer,
+		ReleaseURL:      release.HTMLURL,
+	}
+
+	if upgradeFlags.check {
+		if handled, err := printStructured(check, outputFormat); handled {
+			return err
+		}
+		printField("Current", fmt.Sprintf("%s (commit %s)", current, versionInfo.Commit))
+		printField("Latest", release.Version())
+		switch {
+		case !isRelease:
+			output.Info("This is a local build; run 'dorgu upgrade --force' to install the latest release")
+		case check.UpdateAvailable:
+			output.Info(fmt.Sprintf("dorgu %s is available: run 'dorgu upgrade'", release.Version()))
+			printField("Release notes", release.HTMLURL)
+		default:
+			output.Success("dorgu is up to date")
+		}
+		return nil
+	}
+
+	if !upgradeFlags.force {
+		if !isRelease {
+			return fmt.Errorf("this is a local build (%s); use --force to replace it with %s", current, release.Version())
+		}
+		if !newer {
+			output.Success(fmt.Sprintf("dorgu %s is up to date", current))
+			return nil
+		}
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot locate the running binary: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("cannot locate the running binary: %w", err)
+	}
+
+	if !upgradeFlags.yes {
+		ok, err := confirm(fmt.Sprintf("Replace %s (%s) with dorgu %s?", exe, current, release.Version()))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			output.Warn("Upgrade cancelled")
+			return nil
+		}
+	}
+
+	archiveName := update.ArchiveName(release.Version(), runtime.GOOS, runtime.GOARCH)
+	archiveAsset, err := release.Asset(archiveName)
+	if err != nil {
+		return err
+	}
+	checksumsAsset, err := release.Asset(update.ChecksumsFile)
+	if err != nil {
+		return fmt.Errorf("%w; refusing to install an unverified binary", err)
+	}
+
+	s = newSpinner(" Downloading " + archiveName + "...")
+	s.Start()
+	checksums, err := client.Download(ctx, checksumsAsset)
+	if err != nil {
+		s.Stop()
+		return err
+	}
+	archive, err := client.Download(ctx, archiveAsset)
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	if err := update.VerifyChecksum(archive, archiveName, checksums); err != nil {
+		return err
+	}
+	output.Success("Checksum verified")
+
+	binaryName := "dorgu"
+	if runtime.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+	binary, err := update.ExtractBinary(archive, archiveName, binaryName)
+	if err != nil {
+		return err
+	}
+	if err := update.ReplaceExecutable(exe, binary); err != nil {
+		return err
+	}
+
+	output.Success(fmt.Sprintf("Upgraded dorgu %s → %s", current, release.Version()))
+	return nil
+}
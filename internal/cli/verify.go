@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/kube"
+	"github.com/dorgu-ai/dorgu/internal/output"
+)
+
+var verifyFlags struct {
+	namespace   string
+	kubeContext string
+	name        string
+	llmProvider string
+	wait        time.Duration
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [path]",
+	Short: "Run the smoke test checks against an app deployed in a cluster",
+	Long: `Check that a deployed application serves its routes, with the same
+requests as the jobs/smoke-test.yaml Job that generate --smoke-test adds:
+
+  1. Wait for the app's Deployment to finish rolling out
+  2. Port-forward the app's Service
+  3. GET the readiness path, the first routes found in the code, and
+     smoke_test.routes from .dorgu.yaml
+
+Exits non-zero when a request fails or returns an error status, so it can
+gate a pipeline after deploy.
+
+Examples:
+  dorgu verify .
+  dorgu verify ./my-app --namespace staging
+  dorgu verify ./my-app --context prod-eu --wait 0`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runVerify,
+}
+
+func init() {
+	f := verifyCmd.Flags()
+	f.StringVar(&verifyFlags.namespace, "namespace", "", "namespace the app is deployed in (default from config)")
+	f.StringVar(&verifyFlags.kubeContext, "context", "", "kubeconfig context (default: the current context)")
+	f.StringVarP(&verifyFlags.name, "name", "n", "", "override application name")
+	f.StringVar(&verifyFlags.llmProvider, "llm-provider", "", "LLM provider: openai, anthropic, gemini, ollama (default from config)")
+	f.DurationVar(&verifyFlags.wait, "wait", 2*time.Minute, "how long to wait for the rollout to finish; 0 to skip")
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("path does not exist: %s", absPath)
+	}
+
+	gen, err := generateApp(ctx, absPath, generateOptions{
+		outputDir:      "k8s",
+		name:           verifyFlags.name,
+		namespace:      verifyFlags.namespace,
+		llmProvider:    verifyFlags.llmProvider,
+		skipArgoCD:     true,
+		skipCI:         true,
+		skipPersona:    true,
+		skipReadme:     true,
+		skipValidation: true,
+	})
+	if err != nil {
+		return err
+	}
+	name := gen.analysis.Name
+	namespace := gen.namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	checks := generator.SmokeChecks(gen.analysis, gen.config)
+	if len(checks) == 0 {
+		return fmt.Errorf("%s exposes no HTTP path to check", name)
+	}
+
+	client, err := kube.NewClientForContext(verifyFlags.kubeContext)
+	if err != nil {
+		return fmt.Errorf("%w; required for verify", err)
+	}
+
+	if verifyFlags.wait > 0 {
+		s := newSpinner(fmt.Sprintf(" Waiting for deployment/%s to roll out...", name))
+		s.Start()
+		_, err := client.Run(ctx, "rollout", "status", "deployment/"+name, "-n", namespace, "--timeout", verifyFlags.wait.String())
+		s.Stop()
+		if errors.Is(err, kube.ErrNotFound) {
+			return fmt.Errorf("deployment/%s not found in namespace %s", name, namespace)
+		}
+		if err != nil {
+			return fmt.Errorf("deployment/%s did not finish rolling out: %w", name, err)
+		}
+	}
+
+	output.Header(fmt.Sprintf("Verifying %s in %s", name, namespace))
+	forwards := map[int]*kube.PortForward{}
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	failed := 0
+	for _, c := range checks {
+		pf, ok := forwards[c.Port]
+		if !ok {
+			pf, err = client.PortForward(ctx, namespace, "svc/"+name, c.Port)
+			if err != nil {
+				return err
+			}
+			defer pf.Stop()
+			forwards[c.Port] = pf
+		}
+		target := fmt.Sprintf("GET :%d%s", c.Port, c.Path)
+		resp, err := httpClient.Get(fmt.Sprintf("http://127.0.0.1:%d%s", pf.LocalPort, c.Path))
+		if err != nil {
+			output.Error(fmt.Sprintf("%s: %v", target, err))
+			failed++
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusBadRequest {
+			output.Error(fmt.Sprintf("%s: %s", target, resp.Status))
+			failed++
+			continue
+		}
+		output.Success(fmt.Sprintf("%s: %s", target, resp.Status))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
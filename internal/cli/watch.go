@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -17,6 +19,8 @@ import (
 var watchFlags struct {
 	operatorURL string
 	namespace   string
+	selector    string
+	since       time.Duration
 }
 
 var watchCmd = &cobra.Command{
@@ -26,7 +30,8 @@ var watchCmd = &cobra.Command{
 real-time updates about personas, cluster state, and events.
 
 Requires the Dorgu Operator to be running with WebSocket enabled
-(--enable-websocket flag).
+(--enable-websocket flag). Without --operator-url, the dorgu-operator
+Service is found in the current cluster and reached via port-forward.
 
 Examples:
   # Watch all persona updates
@@ -36,7 +41,10 @@ Examples:
   dorgu watch cluster
 
   # Watch validation events
-  dorgu watch events`,
+  dorgu watch events
+
+  # Replay the last hour before streaming live updates
+  dorgu watch personas --since 1h`,
 }
 
 var watchPersonasCmd = &cobra.Command{
@@ -48,6 +56,7 @@ including phase transitions, health status changes, and validation results.
 Examples:
   dorgu watch personas
   dorgu watch personas -n production
+  dorgu watch personas -l tier=backend
   dorgu watch personas --operator-url ws://localhost:9090/ws`,
 	RunE: runWatchPersonas,
 }
@@ -72,22 +81,28 @@ including deployment validations and policy violations.
 
 Examples:
   dorgu watch events
-  dorgu watch events -n production`,
+  dorgu watch events -n production
+  dorgu watch events -l app=orders`,
 	RunE: runWatchEvents,
 }
 
 func init() {
 	// Common flags
-	watchCmd.PersistentFlags().StringVar(&watchFlags.operatorURL, "operator-url", "ws://localhost:9090/ws",
-		"WebSocket URL of the Dorgu Operator")
+	watchCmd.PersistentFlags().StringVar(&watchFlags.operatorURL, "operator-url", "", operatorURLUsage)
+	watchCmd.PersistentFlags().DurationVar(&watchFlags.since, "since", 0,
+		"replay buffered events from this long ago (e.g. 30m, 1h) before streaming")
 
 	// Personas flags
 	watchPersonasCmd.Flags().StringVarP(&watchFlags.namespace, "namespace", "n", "",
 		"Filter by namespace (optional)")
+	watchPersonasCmd.Flags().StringVarP(&watchFlags.selector, "selector", "l", "",
+		"Filter by label selector (optional)")
 
 	// Events flags
 	watchEventsCmd.Flags().StringVarP(&watchFlags.namespace, "namespace", "n", "",
 		"Filter by namespace (optional)")
+	watchEventsCmd.Flags().StringVarP(&watchFlags.selector, "selector", "l", "",
+		"Filter by label selector (optional)")
 
 	// Register subcommands
 	watchCmd.AddCommand(watchPersonasCmd)
@@ -96,7 +111,7 @@ func init() {
 }
 
 func runWatchPersonas(cmd *cobra.Command, args []string) error {
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(cmd.Context())
 	defer cancel()
 
 	// Handle interrupt
@@ -108,7 +123,10 @@ func runWatchPersonas(cmd *cobra.Command, args []string) error {
 		cancel()
 	}()
 
-	client := ws.NewClient(watchFlags.operatorURL)
+	operatorURL, stopForward := resolveOperatorURL(ctx, watchFlags.operatorURL)
+	defer stopForward()
+
+	client := newOperatorClient(operatorURL)
 	if err := client.Connect(ctx); err != nil {
 		return fmt.Errorf("failed to connect to operator: %w", err)
 	}
@@ -118,14 +136,14 @@ func runWatchPersonas(cmd *cobra.Command, args []string) error {
 	output.Info("Watching ApplicationPersona updates... (Ctrl+C to stop)")
 	fmt.Println()
 
-	// Subscribe to personas topic
-	err := client.Subscribe(ctx, ws.TopicPersonas, func(msg *ws.Message) {
+	// Subscribe to personas topic; the operator applies the filter
+	err := watchTopic(ctx, client, ws.TopicPersonas, watchFilter(), func(msg *ws.Message) {
 		var event ws.PersonaEvent
 		if err := json.Unmarshal(msg.Payload, &event); err != nil {
 			return
 		}
 
-		// Filter by namespace if specified
+		// Operators that predate subscription filters send everything
 		if watchFlags.namespace != "" && event.Namespace != watchFlags.namespace {
 			return
 		}
@@ -151,13 +169,11 @@ func runWatchPersonas(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to subscribe: %w", err)
 	}
 
-	// Wait for context cancellation
-	<-ctx.Done()
-	return nil
+	return waitForWatch(ctx, client, operatorURL)
 }
 
 func runWatchCluster(cmd *cobra.Command, args []string) error {
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(cmd.Context())
 	defer cancel()
 
 	// Handle interrupt
@@ -169,7 +185,10 @@ func runWatchCluster(cmd *cobra.Command, args []string) error {
 		cancel()
 	}()
 
-	client := ws.NewClient(watchFlags.operatorURL)
+	operatorURL, stopForward := resolveOperatorURL(ctx, watchFlags.operatorURL)
+	defer stopForward()
+
+	client := newOperatorClient(operatorURL)
 	if err := client.Connect(ctx); err != nil {
 		return fmt.Errorf("failed to connect to operator: %w", err)
 	}
@@ -180,7 +199,7 @@ func runWatchCluster(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	// Subscribe to cluster topic
-	err := client.Subscribe(ctx, ws.TopicCluster, func(msg *ws.Message) {
+	err := watchTopic(ctx, client, ws.TopicCluster, ws.SubscribeFilter{}, func(msg *ws.Message) {
 		var event ws.ClusterEvent
 		if err := json.Unmarshal(msg.Payload, &event); err != nil {
 			return
@@ -206,12 +225,11 @@ func runWatchCluster(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to subscribe: %w", err)
 	}
 
-	<-ctx.Done()
-	return nil
+	return waitForWatch(ctx, client, operatorURL)
 }
 
 func runWatchEvents(cmd *cobra.Command, args []string) error {
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(cmd.Context())
 	defer cancel()
 
 	// Handle interrupt
@@ -223,7 +241,10 @@ func runWatchEvents(cmd *cobra.Command, args []string) error {
 		cancel()
 	}()
 
-	client := ws.NewClient(watchFlags.operatorURL)
+	operatorURL, stopForward := resolveOperatorURL(ctx, watchFlags.operatorURL)
+	defer stopForward()
+
+	client := newOperatorClient(operatorURL)
 	if err := client.Connect(ctx); err != nil {
 		return fmt.Errorf("failed to connect to operator: %w", err)
 	}
@@ -234,7 +255,7 @@ func runWatchEvents(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	// Subscribe to events topic
-	err := client.Subscribe(ctx, ws.TopicEvents, func(msg *ws.Message) {
+	err := watchTopic(ctx, client, ws.TopicEvents, watchFilter(), func(msg *ws.Message) {
 		timestamp := msg.Timestamp.Format("15:04:05")
 		fmt.Printf("[%s] %s\n", timestamp, string(msg.Payload))
 	})
@@ -242,10 +263,100 @@ func runWatchEvents(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to subscribe: %w", err)
 	}
 
-	<-ctx.Done()
+	return waitForWatch(ctx, client, operatorURL)
+}
+
+// watchTopic subscribes to topic and, with --since, first replays the
+// operator's buffered events. Live events that arrive during the replay are
+// queued and delivered afterwards, skipping any that were replayed.
+func watchTopic(ctx context.Context, client *ws.Client, topic ws.Topic, filter ws.SubscribeFilter, handler func(*ws.Message)) error {
+	if watchFlags.since <= 0 {
+		return client.SubscribeWithFilter(ctx, topic, filter, handler)
+	}
+
+	// The queue is unbounded so the subscription never blocks while the
+	// history is paged
+	var (
+		mu        sync.Mutex
+		replaying = true
+		queued    []*ws.Message
+		replayed  = map[string]bool{}
+	)
+	err := client.SubscribeWithFilter(ctx, topic, filter, func(msg *ws.Message) {
+		mu.Lock()
+		defer mu.Unlock()
+		if replaying {
+			queued = append(queued, msg)
+			return
+		}
+		if !replayed[eventKey(msg)] {
+			handler(msg)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	since := time.Now().Add(-watchFlags.since)
+	output.Dim(fmt.Sprintf("── history since %s ──", since.Format("15:04:05")))
+	req := ws.HistoryRequest{SubscribeFilter: filter, Since: since, Limit: 100}
+	for {
+		page, err := client.EventHistory(ctx, topic, req)
+		if err != nil {
+			return fmt.Errorf("failed to fetch event history: %w", err)
+		}
+		for i := range page.Events {
+			handler(&page.Events[i])
+			replayed[eventKey(&page.Events[i])] = true
+		}
+		if page.Continue == "" {
+			break
+		}
+		req.Continue = page.Continue
+	}
+	output.Dim("── live ──")
+
+	mu.Lock()
+	defer mu.Unlock()
+	replaying = false
+	for _, msg := range queued {
+		if !replayed[eventKey(msg)] {
+			handler(msg)
+		}
+	}
+	queued = nil
 	return nil
 }
 
+// eventKey identifies an event so its replayed and live copies match: its
+// ID, or its timestamp and payload when the operator sent none. Timestamps
+// alone are not unique, as several events can share one.
+func eventKey(msg *ws.Message) string {
+	if msg.RequestID != "" {
+		return msg.RequestID
+	}
+	return msg.Timestamp.Format(time.RFC3339Nano) + " " + string(msg.Payload)
+}
+
+// watchFilter builds the subscription filter from the watch flags
+func watchFilter() ws.SubscribeFilter {
+	return ws.SubscribeFilter{
+		Namespace:     watchFlags.namespace,
+		LabelSelector: watchFlags.selector,
+	}
+}
+
+// waitForWatch blocks until the watch is interrupted or the operator
+// connection is lost
+func waitForWatch(ctx context.Context, client *ws.Client, operatorURL string) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-client.Disconnected():
+		return fmt.Errorf("lost connection to operator at %s", operatorURL)
+	}
+}
+
 func colorHealth(health string) string {
 	switch health {
 	case "Healthy":
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
@@ -41,6 +43,72 @@ type Config struct {
 
 	// LLM configuration
 	LLM LLMConfig `mapstructure:"llm"`
+
+	// Plugins are external generators run after the built-in ones
+	Plugins []PluginConfig `mapstructure:"plugins"`
+
+	// Templates overrides built-in generators with Go templates
+	Templates TemplatesConfig `mapstructure:"templates"`
+
+	// PullRequest configures generate --create-pr
+	PullRequest PullRequestConfig `mapstructure:"pull_request"`
+
+	// Cost configures dorgu cost and the persona cost estimate
+	Cost CostConfig `mapstructure:"cost"`
+
+	// HPA sets policy for apps that get a HorizontalPodAutoscaler
+	HPA HPAConfig `mapstructure:"hpa"`
+
+	// Metrics configures the metrics port and how Prometheus finds it
+	Metrics MetricsConfig `mapstructure:"metrics"`
+
+	// Service sets the cloud and annotation presets for exposed Services
+	Service ServiceConfig `mapstructure:"service"`
+
+	// Env adds environment variables to every app
+	Env EnvConfig `mapstructure:"env"`
+
+	// Secrets selects where secret env vars come from
+	Secrets SecretsConfig `mapstructure:"secrets"`
+
+	// Header sets the comment at the top of generated files
+	Header HeaderConfig `mapstructure:"header"`
+
+	// SLO sets the tooling app SLOs are generated for
+	SLO SLOConfig `mapstructure:"slo"`
+
+	// Owners is the directory app.team and app.owner are validated against
+	Owners OwnersConfig `mapstructure:"owners"`
+
+	// Analyzers configures the static analysis
+	Analyzers AnalyzersConfig `mapstructure:"analyzers"`
+
+	// Notifications are the chat webhooks generate --notify and persona
+	// apply post to
+	Notifications []NotificationConfig `mapstructure:"notifications"`
+
+	// Dev selects the local development environment files generate adds
+	Dev DevConfig `mapstructure:"dev"`
+
+	// SmokeTest adds a post-deploy Job checking the app answers over HTTP
+	SmokeTest SmokeTestConfig `mapstructure:"smoke_test"`
+
+	// Migrations adds a pre-deploy Job applying the migrations the analysis
+	// found
+	Migrations MigrationsConfig `mapstructure:"migrations"`
+
+	// Validation enables the optional checks of the generated manifests
+	Validation ValidationConfig `mapstructure:"validation"`
+}
+
+// HeaderConfig controls the comment at the top of generated manifests, CI
+// workflows, and PERSONA.md
+type HeaderConfig struct {
+	// Enabled adds the header (default true)
+	Enabled bool `mapstructure:"enabled"`
+	// Text is added below the standard notice, e.g. who owns the files; it
+	// may use {app}, {team}, and {source}
+	Text string `mapstructure:"text"`
 }
 
 // OrgConfig contains organization information
@@ -51,7 +119,9 @@ type OrgConfig struct {
 // NamingConfig contains naming conventions
 type NamingConfig struct {
 	Pattern string `mapstructure:"pattern"`
-	DNSSafe bool   `mapstructure:"dns_safe"`
+	// DNSSafe normalizes app names into valid DNS-1123 resource names
+	// (default true)
+	DNSSafe bool `mapstructure:"dns_safe"`
 }
 
 // ResourceConfig contains resource defaults
@@ -87,8 +157,23 @@ type AnnotationConfig struct {
 type SecurityConfig struct {
 	PodSecurityContext       PodSecurityContext       `mapstructure:"pod_security_context"`
 	ContainerSecurityContext ContainerSecurityContext `mapstructure:"container_security_context"`
+	// RuntimeClass is the RuntimeClass pods run with, e.g. gvisor or kata
+	RuntimeClass string `mapstructure:"runtime_class"`
+	// AppArmorProfile is runtime/default, unconfined, or localhost/<profile>
+	AppArmorProfile string `mapstructure:"apparmor_profile"`
+	// PodSecurityStandard is the Pod Security Standard level (privileged,
+	// baseline, restricted) the app's namespace enforces when ArgoCD
+	// creates it
+	PodSecurityStandard string `mapstructure:"pod_security_standard"`
 }
 
+// Pod Security Standard levels
+const (
+	PSSPrivileged = "privileged"
+	PSSBaseline   = "baseline"
+	PSSRestricted = "restricted"
+)
+
 // PodSecurityContext contains pod-level security settings
 type PodSecurityContext struct {
 	RunAsNonRoot   bool            `mapstructure:"run_as_non_root"`
@@ -97,7 +182,10 @@ type PodSecurityContext struct {
 
 // SeccompProfile contains seccomp profile settings
 type SeccompProfile struct {
-	Type string `mapstructure:"type"`
+	Type string `mapstructure:"type" yaml:"type"` // RuntimeDefault, Localhost, Unconfined
+	// LocalhostProfile is the profile's path on the node, relative to the
+	// kubelet's seccomp directory; required with type Localhost
+	LocalhostProfile string `mapstructure:"localhost_profile" yaml:"localhost_profile"`
 }
 
 // ContainerSecurityContext contains container-level security settings
@@ -118,6 +206,9 @@ type IngressConfig struct {
 	Class        string    `mapstructure:"class"`
 	DomainSuffix string    `mapstructure:"domain_suffix"`
 	TLS          TLSConfig `mapstructure:"tls"`
+	// Presets are annotations added to every Ingress of a class, keyed by
+	// class name; they extend and override dorgu's built-in presets
+	Presets map[string]map[string]string `mapstructure:"presets"`
 }
 
 // TLSConfig contains TLS settings
@@ -154,30 +245,400 @@ type AutomatedConfig struct {
 type CIConfig struct {
 	Provider string `mapstructure:"provider"`
 	Registry string `mapstructure:"registry"`
+	// GitHub shapes the GitHub Actions workflow
+	GitHub GitHubCIConfig `mapstructure:"github"`
+	// DependencyUpdates is renovate, dependabot, or none (default): the bot
+	// configured to open update PRs for manifest images and action versions
+	DependencyUpdates string `mapstructure:"dependency_updates"`
+}
+
+// Values of CIConfig.DependencyUpdates
+const (
+	DependencyUpdatesRenovate   = "renovate"
+	DependencyUpdatesDependabot = "dependabot"
+	DependencyUpdatesNone       = "none"
+)
+
+// Values of GitHubCIConfig.Mode
+const (
+	// CIModeFull generates the complete build and deploy workflow
+	CIModeFull = "full"
+	// CIModeReusable generates a caller of a shared reusable workflow
+	CIModeReusable = "reusable"
+)
+
+// GitHubCIConfig configures the generated GitHub Actions workflow
+type GitHubCIConfig struct {
+	// Mode is full (default) or reusable
+	Mode string `mapstructure:"mode"`
+	// Workflow is the reusable workflow reusable mode calls, as
+	// owner/repo/.github/workflows/file.yml@ref (default: the deploy.yml
+	// workflow in the org.name/.github repository, at main)
+	Workflow string `mapstructure:"workflow"`
 }
 
 // LLMConfig contains LLM settings
 type LLMConfig struct {
 	Provider string `mapstructure:"provider"`
 	Model    string `mapstructure:"model"`
+	// RedactPatterns are regular expressions masked in every prompt sent to the LLM
+	RedactPatterns []string `mapstructure:"redact_patterns"`
+	// CacheDir holds LLM responses reused by generate --deterministic
+	// (default: the user cache directory)
+	CacheDir string `mapstructure:"cache_dir"`
+}
+
+// PluginConfig enables an exec plugin. The plugin binary is dorgu-<name> on
+// PATH unless Path is set.
+type PluginConfig struct {
+	Name string `mapstructure:"name"`
+	Path string `mapstructure:"path"`
+	// Config is passed to the plugin unchanged
+	Config map[string]interface{} `mapstructure:"config"`
+}
+
+// TemplatesConfig points at a directory of Go templates named after the file
+// they replace (deployment.yaml.tmpl, argocd/application.yaml.tmpl,
+// .github/workflows/deploy.yaml.tmpl, PERSONA.md.tmpl)
+type TemplatesConfig struct {
+	// Dir is resolved relative to the config file
+	Dir string `mapstructure:"dir"`
+}
+
+// PullRequestConfig configures the pull requests opened by generate --create-pr
+type PullRequestConfig struct {
+	// Base is the target branch (default: the current branch)
+	Base string `mapstructure:"base"`
+	// BranchPrefix is prepended to the new branch name (default "dorgu/")
+	BranchPrefix string `mapstructure:"branch_prefix"`
+	// Title is a Go template; see generator.PullRequestData
+	Title string `mapstructure:"title"`
+	// BodyTemplate is a Go template file for the description, resolved
+	// relative to the config file
+	BodyTemplate string `mapstructure:"body_template"`
+}
+
+// Values of HPAConfig.Replicas
+const (
+	// HPAReplicasOmit leaves spec.replicas out of the Deployment
+	HPAReplicasOmit = "omit"
+	// HPAReplicasIgnore keeps spec.replicas and has ArgoCD ignore drift on it
+	HPAReplicasIgnore = "ignore"
+	// HPAReplicasKeep keeps spec.replicas as is
+	HPAReplicasKeep = "keep"
+)
+
+// HPAConfig sets policy for autoscaled apps
+type HPAConfig struct {
+	// Replicas decides what happens to the Deployment's spec.replicas when
+	// an HPA manages the replica count: omit (default), ignore, or keep
+	Replicas string `mapstructure:"replicas"`
+}
+
+// Values of MetricsConfig.Scrape
+const (
+	// MetricsScrapeAnnotations adds prometheus.io/* annotations to the pods
+	// and Service
+	MetricsScrapeAnnotations = "annotations"
+	// MetricsScrapeServiceMonitor generates a Prometheus Operator ServiceMonitor
+	MetricsScrapeServiceMonitor = "servicemonitor"
+)
+
+// MetricsConfig sets how metrics endpoints are exposed
+type MetricsConfig struct {
+	// Port is the port a metrics path found in the code is served on; 0,
+	// the default, means the app's HTTP port
+	Port int `mapstructure:"port"`
+	// Scrape is how Prometheus discovers the endpoint: annotations,
+	// servicemonitor, or empty for neither
+	Scrape string `mapstructure:"scrape"`
+}
+
+// ServiceConfig contains Service settings
+type ServiceConfig struct {
+	// Cloud selects dorgu's built-in Service presets: aws, gcp, or azure
+	// (default: cost.cloud)
+	Cloud string `mapstructure:"cloud"`
+	// Presets are named sets of Service annotations that apps select with
+	// service.presets; they extend and override the cloud's built-in ones
+	Presets map[string]map[string]string `mapstructure:"presets"`
+}
+
+// EnvConfig contains org-wide environment variables
+type EnvConfig struct {
+	Standard StandardEnvConfig `mapstructure:"standard"`
+}
+
+// StandardEnvConfig is injected into every app container. Variables the app
+// already defines are left alone.
+type StandardEnvConfig struct {
+	// DownwardAPI adds POD_NAME, POD_NAMESPACE, and NODE_NAME from fieldRefs
+	DownwardAPI bool `mapstructure:"downward_api"`
+	// Vars are plain variables; values may use {app}, {namespace}, {env},
+	// and {team}
+	Vars []StandardEnvVar `mapstructure:"vars"`
+}
+
+// StandardEnvVar is one org-wide variable
+type StandardEnvVar struct {
+	Name  string `mapstructure:"name"`
+	Value string `mapstructure:"value"`
+}
+
+// Values of SecretsConfig.Provider
+const (
+	// SecretsKubernetes reads secret env vars from the <app>-secrets Secret
+	SecretsKubernetes = "kubernetes"
+	// SecretsVaultAgent has the Vault Agent injector render them to a file
+	SecretsVaultAgent = "vault-agent"
+	// SecretsCSI syncs them from Vault into <app>-secrets with the Secrets
+	// Store CSI driver
+	SecretsCSI = "csi"
+)
+
+// SecretsConfig selects the secret provider for all apps
+type SecretsConfig struct {
+	// Provider is kubernetes (default), vault-agent, or csi
+	Provider string      `mapstructure:"provider"`
+	Vault    VaultConfig `mapstructure:"vault"`
+}
+
+// VaultConfig locates app secrets in Vault. Role and Path may use {app},
+// {env}, and {team}.
+type VaultConfig struct {
+	// Address is the Vault server, required by the CSI provider
+	Address string `mapstructure:"address"`
+	// Role is the Kubernetes auth role (default "{app}")
+	Role string `mapstructure:"role"`
+	// Path is the KV v2 secret holding the app's keys (default
+	// "secret/data/{app}")
+	Path string `mapstructure:"path"`
+}
+
+// CostConfig sets the prices used for cost estimates
+type CostConfig struct {
+	// Cloud selects built-in on-demand prices: aws (default), gcp, or azure
+	Cloud string `mapstructure:"cloud"`
+	// CPUPerHour and GiBPerHour override the cloud's price per vCPU-hour and
+	// per GiB-hour of memory
+	CPUPerHour float64 `mapstructure:"cpu_per_hour"`
+	GiBPerHour float64 `mapstructure:"gib_per_hour"`
+	// Environments to estimate, keyed by name. When empty, the app's own
+	// environment is estimated with its scaling settings.
+	Environments map[string]CostEnvironment `mapstructure:"environments"`
+}
+
+// CostEnvironment overrides the replica counts of one environment
+type CostEnvironment struct {
+	MinReplicas int `mapstructure:"min_replicas"`
+	MaxReplicas int `mapstructure:"max_replicas"`
+}
+
+// Values of SLOConfig.Format and AppSLO.Format
+const (
+	// SLOFormatSloth generates a Sloth PrometheusServiceLevel
+	SLOFormatSloth = "sloth"
+	// SLOFormatOpenSLO generates OpenSLO SLO documents
+	SLOFormatOpenSLO = "openslo"
+)
+
+// SLOConfig sets how app SLOs are turned into manifests
+type SLOConfig struct {
+	// Format is sloth, openslo, or empty to only document SLOs in the persona
+	Format string `mapstructure:"format"`
+}
+
+// Values of DevConfig.Files
+const (
+	// DevFileDevcontainer generates .devcontainer/devcontainer.json
+	DevFileDevcontainer = "devcontainer"
+	// DevFileDevfile generates a devfile.yaml for odo and cloud IDEs
+	DevFileDevfile = "devfile"
+	// DevFileSkaffold generates a skaffold.yaml deploying the manifests
+	DevFileSkaffold = "skaffold"
+	// DevFileTilt generates a Tiltfile deploying the manifests
+	DevFileTilt = "tilt"
+)
+
+// DevConfig selects the development environment files written next to the
+// output directory, so onboarding covers the inner loop too
+type DevConfig struct {
+	// Files are devcontainer, devfile, skaffold, and tilt; none by default
+	Files []string `mapstructure:"files"`
+}
+
+// SmokeTestConfig controls the post-deploy smoke test Job, which requests
+// the app's readiness path and routes through its Service
+type SmokeTestConfig struct {
+	// Enabled generates jobs/smoke-test.yaml (default false)
+	Enabled bool `mapstructure:"enabled"`
+	// Image runs the checks; it needs sh and curl (default curlimages/curl)
+	Image string `mapstructure:"image"`
+	// Routes are checked besides the readiness path and the routes found in
+	// the code, e.g. /api/status
+	Routes []string `mapstructure:"routes"`
+}
+
+// MigrationsConfig controls the migration Job generated from the migration
+// tool the analysis found, for apps that do not migrate as they start and
+// have no migration job in their app config
+type MigrationsConfig struct {
+	// Job generates jobs/migrate.yaml, a pre-deploy hook that blocks each
+	// rollout until the migrations succeed (default false)
+	Job bool `mapstructure:"job"`
+	// Image runs the Job instead of the app's image; required for tools
+	// whose CLI the app's image lacks, such as Flyway and golang-migrate
+	Image string `mapstructure:"image"`
+}
+
+// Values of ValidationConfig.FailOn
+const (
+	FailOnError   = "error"
+	FailOnWarning = "warning"
+)
+
+// ValidationConfig enables the validation checks that reach outside the
+// generated files
+type ValidationConfig struct {
+	// FailOn makes generate exit non-zero when validation finds issues of
+	// this severity or worse: error or warning. Issues never fail it when
+	// empty.
+	FailOn string `mapstructure:"fail_on"`
+	// ImageExists checks the manifests' images were pushed to the registry
+	ImageExists ImageExistsConfig `mapstructure:"image_exists"`
+	// Ingress sets the policy ingress hosts are checked against
+	Ingress IngressValidationConfig `mapstructure:"ingress"`
+}
+
+// IngressValidationConfig controls the checks of the app's ingress hosts
+type IngressValidationConfig struct {
+	// DomainSuffixes are the domains ingress hosts must be under, e.g.
+	// .apps.example.com; any host is allowed when empty
+	DomainSuffixes []string `mapstructure:"domain_suffixes"`
+	// ResolveHosts looks each host up in DNS and warns when it has no
+	// record (default false)
+	ResolveHosts bool `mapstructure:"resolve_hosts"`
+}
+
+// ImageExistsConfig controls the check that the images in ci.registry the
+// manifests reference exist
+type ImageExistsConfig struct {
+	// Enabled checks each image with a HEAD request (default false)
+	Enabled bool `mapstructure:"enabled"`
+	// Environments are the app environments where a missing image is an
+	// error rather than a warning (default: production)
+	Environments []string `mapstructure:"environments"`
+	// UsernameEnv and PasswordEnv name the environment variables holding
+	// the registry credentials; the Docker config is used when unset
+	UsernameEnv string `mapstructure:"username_env"`
+	PasswordEnv string `mapstructure:"password_env"`
+}
+
+// AnalyzersConfig selects the analyzers that inspect an app
+type AnalyzersConfig struct {
+	// Disabled are analyzers to skip: appconfig, dockerfile, compose, code,
+	// git, k8smanifest
+	Disabled []string `mapstructure:"disabled"`
+}
+
+// OwnersConfig points validation at a directory of teams and owners
+type OwnersConfig struct {
+	// Source is a YAML or JSON file (resolved relative to the config file),
+	// an http(s) URL serving one, or a SCIM 2.0 base URL prefixed with
+	// scim+, e.g. scim+https://idp.example.com/scim/v2
+	Source string `mapstructure:"source"`
+	// TokenEnv names the environment variable holding a bearer token for
+	// URL and SCIM sources
+	TokenEnv string `mapstructure:"token_env"`
+}
+
+// Values of NotificationConfig.Type
+const (
+	NotificationSlack = "slack"
+	NotificationTeams = "teams"
+)
+
+// NotificationConfig is a Slack or Teams incoming webhook
+type NotificationConfig struct {
+	// Type is slack or teams
+	Type string `mapstructure:"type"`
+	// WebhookURL is the incoming webhook; prefer WebhookURLEnv, which names
+	// the environment variable holding it, to keep it out of the repository
+	WebhookURL    string `mapstructure:"webhook_url"`
+	WebhookURLEnv string `mapstructure:"webhook_url_env"`
+	// Channel overrides the Slack webhook's channel; Teams webhooks post to
+	// the channel they were created for
+	Channel string `mapstructure:"channel"`
 }
 
 // Load loads the configuration from the config file
 func Load() (*Config, error) {
-	var cfg Config
-	if err := viper.Unmarshal(&cfg); err != nil {
-		return nil, err
+	return LoadFile(viper.ConfigFileUsed())
+}
+
+// LoadFile loads the configuration from the config file at path, resolving
+// relative paths in it against its directory. An empty path returns the
+// defaults.
+func LoadFile(path string) (*Config, error) {
+	// Fields the file leaves out keep these values
+	cfg := Config{Naming: NamingConfig{DNSSafe: true}, Header: HeaderConfig{Enabled: true}}
+	if path != "" {
+		v := newViper()
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, err
+		}
+		if err := v.Unmarshal(&cfg); err != nil {
+			return nil, err
+		}
 	}
 
 	// Apply defaults for missing values
 	applyDefaults(&cfg)
 
+	cfg.Templates.Dir = relativeToConfig(path, cfg.Templates.Dir)
+	cfg.PullRequest.BodyTemplate = relativeToConfig(path, cfg.PullRequest.BodyTemplate)
+	cfg.LLM.CacheDir = relativeToConfig(path, cfg.LLM.CacheDir)
+	if !strings.Contains(cfg.Owners.Source, "://") {
+		cfg.Owners.Source = relativeToConfig(path, cfg.Owners.Source)
+	}
+
+	return &cfg, nil
+}
+
+// Parse decodes workspace config from YAML without applying defaults, so
+// callers see only what the file sets
+func Parse(data []byte) (*Config, error) {
+	v := newViper()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, err
+	}
 	return &cfg, nil
 }
 
+// newViper returns a viper that keeps dotted map keys such as
+// prometheus.io/scrape intact instead of splitting them into nested keys
+func newViper() *viper.Viper {
+	return viper.NewWithOptions(viper.KeyDelimiter("::"))
+}
+
+// relativeToConfig resolves a relative path against the directory of the
+// config file at configPath
+func relativeToConfig(configPath, path string) string {
+	if path == "" || filepath.IsAbs(path) || configPath == "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(configPath), path)
+}
+
 // Default returns the default configuration
 func Default() *Config {
-	cfg := &Config{}
+	cfg := &Config{Naming: NamingConfig{DNSSafe: true}, Header: HeaderConfig{Enabled: true}}
 	applyDefaults(cfg)
 	return cfg
 }
@@ -191,7 +652,6 @@ func applyDefaults(cfg *Config) {
 	if cfg.Naming.Pattern == "" {
 		cfg.Naming.Pattern = "{app}"
 	}
-	cfg.Naming.DNSSafe = true
 
 	if cfg.Resources.Defaults.Requests.CPU == "" {
 		cfg.Resources.Defaults.Requests.CPU = "100m"
@@ -248,6 +708,14 @@ func applyDefaults(cfg *Config) {
 	if cfg.LLM.Model == "" {
 		cfg.LLM.Model = "gpt-4"
 	}
+
+	if cfg.PullRequest.BranchPrefix == "" {
+		cfg.PullRequest.BranchPrefix = "dorgu/"
+	}
+
+	if cfg.HPA.Replicas == "" {
+		cfg.HPA.Replicas = HPAReplicasOmit
+	}
 }
 
 // GetResourcesForProfile returns resource spec for a given profile
@@ -294,6 +762,47 @@ type AppConfig struct {
 
 	// Deployment strategy
 	DeploymentPolicy *AppDeploymentPolicy `yaml:"deployment_policy"`
+
+	// Networking sets how the app is reached
+	Networking *AppNetworking `yaml:"networking"`
+
+	// Service overrides the generated Service
+	Service *AppService `yaml:"service"`
+
+	// Secrets overrides the org secret provider settings
+	Secrets *AppSecrets `yaml:"secrets"`
+
+	// Security overrides the org runtime class and seccomp/AppArmor profiles
+	Security *AppSecurity `yaml:"security"`
+
+	// Jobs are one-off tasks, such as database migrations, run with the app
+	Jobs []AppJob `yaml:"jobs"`
+
+	// Containers are additional primary containers in the app's pod, e.g.
+	// an nginx serving the static frontend
+	Containers []AppContainer `yaml:"containers"`
+
+	// SLO defines the app's service level objectives
+	SLO *AppSLO `yaml:"slo"`
+}
+
+// AppContainer is an additional container in the app's pod. Its ports are
+// exposed through the app's Service.
+type AppContainer struct {
+	Name      string             `yaml:"name"`
+	Image     string             `yaml:"image"`
+	Command   []string           `yaml:"command"`
+	Args      []string           `yaml:"args"`
+	Ports     []AppContainerPort `yaml:"ports"`
+	Env       map[string]string  `yaml:"env"`
+	Health    *AppHealth         `yaml:"health"`
+	Resources *AppResources      `yaml:"resources"`
+}
+
+// AppContainerPort is a port an additional container listens on
+type AppContainerPort struct {
+	Name string `yaml:"name"`
+	Port int    `yaml:"port"`
 }
 
 // AppMetadata contains application metadata
@@ -321,20 +830,92 @@ type AppScaling struct {
 	TargetCPU    int    `yaml:"target_cpu"`
 	TargetMemory int    `yaml:"target_memory"`
 	Behavior     string `yaml:"behavior"` // conservative, balanced, aggressive
+	// ScaleUp and ScaleDown override the rules of the behavior preset
+	ScaleUp   *AppScalingRules `yaml:"scale_up"`
+	ScaleDown *AppScalingRules `yaml:"scale_down"`
+	// Metrics are custom (pods) and external metrics to scale on in addition
+	// to CPU and memory
+	Metrics []AppScalingMetric `yaml:"metrics"`
+}
+
+// AppScalingMetric is a pods or external HPA metric, e.g. requests per second
+// served through prometheus-adapter or a queue's depth
+type AppScalingMetric struct {
+	Type     string            `yaml:"type"` // pods, external
+	Name     string            `yaml:"name"`
+	Selector map[string]string `yaml:"selector"`
+	// AverageValue is the target per pod; Value (external only) the target
+	// for the metric as a whole
+	AverageValue string `yaml:"average_value"`
+	Value        string `yaml:"value"`
+}
+
+// Scaling metric types
+const (
+	MetricPods     = "pods"
+	MetricExternal = "external"
+)
+
+// Scaling behavior presets
+const (
+	BehaviorAggressive   = "aggressive"
+	BehaviorBalanced     = "balanced"
+	BehaviorConservative = "conservative"
+)
+
+// AppScalingRules are the HPA scaling rules for one direction. Set fields
+// replace those of the preset; policies replace the preset's policies.
+type AppScalingRules struct {
+	StabilizationWindowSeconds *int               `yaml:"stabilization_window_seconds"`
+	SelectPolicy               string             `yaml:"select_policy"` // Max, Min, Disabled
+	Policies                   []AppScalingPolicy `yaml:"policies"`
+}
+
+// AppScalingPolicy limits how much the HPA may scale within a period
+type AppScalingPolicy struct {
+	Type          string `yaml:"type"` // Pods, Percent
+	Value         int    `yaml:"value"`
+	PeriodSeconds int    `yaml:"period_seconds"`
 }
 
 // AppIngress contains app-specific ingress configuration
 type AppIngress struct {
-	Enabled bool          `yaml:"enabled"`
-	Host    string        `yaml:"host"`
-	Paths   []IngressPath `yaml:"paths"`
-	TLS     *AppTLS       `yaml:"tls"`
+	Enabled bool   `yaml:"enabled"`
+	Host    string `yaml:"host"`
+	// Class overrides the org ingress class
+	Class string `yaml:"class"`
+	// Annotations are added to the Ingress only, after the class preset
+	Annotations map[string]string `yaml:"annotations"`
+	// Hosts serves the app on more host names, including wildcards
+	// (*.example.com). Entries are host names or {host, tls_secret}.
+	Hosts []IngressHost `yaml:"hosts"`
+	Paths []IngressPath `yaml:"paths"`
+	TLS   *AppTLS       `yaml:"tls"`
+}
+
+// IngressHost is one host an app is served on. TLSSecret overrides the
+// shared tls.secret_name for this host.
+type IngressHost struct {
+	Host      string `yaml:"host"`
+	TLSSecret string `yaml:"tls_secret"`
+}
+
+// UnmarshalYAML accepts a bare host name as well as a mapping
+func (h *IngressHost) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&h.Host)
+	}
+	type plain IngressHost
+	return node.Decode((*plain)(h))
 }
 
-// IngressPath defines an ingress path
+// IngressPath defines an ingress path. Port and Service pick the backend;
+// by default paths route to the app's HTTP port on its own Service.
 type IngressPath struct {
 	Path     string `yaml:"path"`
 	PathType string `yaml:"path_type"`
+	Port     int    `yaml:"port"`
+	Service  string `yaml:"service"`
 }
 
 // AppTLS contains TLS configuration for ingress
@@ -350,12 +931,17 @@ type AppHealth struct {
 	StartupGracePeriod string       `yaml:"startup_grace_period"` // e.g., "30s", "60s"
 }
 
-// HealthProbe defines a health check probe
+// HealthProbe defines a health check probe. Unset fields fall back to what
+// code analysis detected, then to defaults for the probe type.
 type HealthProbe struct {
-	Path         string `yaml:"path"`
-	Port         int    `yaml:"port"`
-	InitialDelay int    `yaml:"initial_delay"`
-	Period       int    `yaml:"period"`
+	Path             string `yaml:"path"`
+	Port             int    `yaml:"port"`
+	InitialDelay     int    `yaml:"initial_delay"`
+	Period           int    `yaml:"period"`
+	Timeout          int    `yaml:"timeout"`
+	FailureThreshold int    `yaml:"failure_threshold"`
+	// SuccessThreshold applies to readiness only; Kubernetes requires 1 for liveness
+	SuccessThreshold int `yaml:"success_threshold"`
 }
 
 // AppDependency describes an application dependency
@@ -382,6 +968,127 @@ type AppDeploymentPolicy struct {
 	MaxUnavailable string `yaml:"max_unavailable"` // e.g., "25%"
 }
 
+// Values of AppNetworking.Expose
+const (
+	// ExposePublic serves the app through an Ingress (default)
+	ExposePublic = "public"
+	// ExposeInternal keeps the app in-cluster: a ClusterIP Service, no Ingress
+	ExposeInternal = "internal"
+	// ExposeHeadless is internal with a headless Service for client-side discovery
+	ExposeHeadless = "headless"
+)
+
+// AppNetworking contains app networking configuration
+type AppNetworking struct {
+	// Expose is public (default), internal, or headless
+	Expose string `yaml:"expose"`
+	// DNS tunes name resolution in the pod
+	DNS *AppDNS `yaml:"dns"`
+}
+
+// AppDNS is rendered into the pod's dnsConfig and hostAliases
+type AppDNS struct {
+	// Ndots lowers the cluster default of 5, so external names resolve
+	// without walking every search domain first
+	Ndots *int `yaml:"ndots"`
+	// Searches are appended to the cluster search domains
+	Searches []string `yaml:"searches"`
+	// HostAliases are /etc/hosts entries
+	HostAliases []AppHostAlias `yaml:"host_aliases"`
+}
+
+// AppHostAlias maps an IP to host names in /etc/hosts
+type AppHostAlias struct {
+	IP        string   `yaml:"ip"`
+	Hostnames []string `yaml:"hostnames"`
+}
+
+// AppService contains app Service configuration
+type AppService struct {
+	// Type is ClusterIP (default), NodePort, or LoadBalancer
+	Type string `yaml:"type"`
+	// Presets names org or built-in annotation presets, e.g. internal, nlb
+	Presets []string `yaml:"presets"`
+	// StaticIP requests a fixed load balancer address
+	StaticIP string `yaml:"static_ip"`
+	// Annotations are added to the Service only, after the presets
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// AppSecrets overrides where this app's secret env vars come from
+type AppSecrets struct {
+	Provider string `yaml:"provider"`
+	// Role and Path override the org Vault role and secret path
+	Role string `yaml:"role"`
+	Path string `yaml:"path"`
+}
+
+// AppSecurity overrides the org's hardened runtime settings for one app
+type AppSecurity struct {
+	RuntimeClass        string          `yaml:"runtime_class"`
+	SeccompProfile      *SeccompProfile `yaml:"seccomp_profile"`
+	AppArmorProfile     string          `yaml:"apparmor_profile"`
+	PodSecurityStandard string          `yaml:"pod_security_standard"`
+}
+
+// AppJob is a Job run with the app's image and environment
+type AppJob struct {
+	Name    string   `yaml:"name"`
+	Command []string `yaml:"command"`
+	// Image overrides the app's image
+	Image string `yaml:"image"`
+	// Hook runs the Job before or after each rollout; without one the Job
+	// runs once, when first applied
+	Hook string `yaml:"hook"` // pre-deploy, post-deploy
+	// Schedule runs the Job as a CronJob on a cron schedule, e.g.
+	// "0 3 * * *" or "@hourly"; not with a hook
+	Schedule              string `yaml:"schedule"`
+	BackoffLimit          *int   `yaml:"backoff_limit"`
+	ActiveDeadlineSeconds int    `yaml:"active_deadline_seconds"`
+}
+
+// Job hooks
+const (
+	HookPreDeploy  = "pre-deploy"
+	HookPostDeploy = "post-deploy"
+)
+
+// AppSLO defines an app's service level objectives
+type AppSLO struct {
+	// Availability is the percentage of requests that must succeed, e.g. 99.9
+	Availability float64 `yaml:"availability"`
+	// Latency is the objective for request duration
+	Latency *AppLatencySLO `yaml:"latency"`
+	// Window is the rolling period objectives are measured over (default 30d)
+	Window string `yaml:"window"`
+	// ErrorBudgetPolicy says what the team does when the error budget is spent
+	ErrorBudgetPolicy string `yaml:"error_budget_policy"`
+	// Format overrides the org slo.format
+	Format string `yaml:"format"`
+	// Metrics names the Prometheus metrics the SLIs are computed from
+	Metrics *AppSLOMetrics `yaml:"metrics"`
+}
+
+// AppLatencySLO is a latency objective: Target percent of requests finish
+// within Threshold
+type AppLatencySLO struct {
+	Threshold string  `yaml:"threshold"` // e.g. 300ms
+	Target    float64 `yaml:"target"`    // e.g. 99
+}
+
+// AppSLOMetrics names the metrics behind the SLIs
+type AppSLOMetrics struct {
+	// Requests is a counter of requests with a code label (default
+	// http_requests_total)
+	Requests string `yaml:"requests"`
+	// Duration is a histogram of request duration in seconds (default
+	// http_request_duration_seconds)
+	Duration string `yaml:"duration"`
+	// Selector are the label matchers picking the app's series (default
+	// job="<app>")
+	Selector string `yaml:"selector"`
+}
+
 // LoadAppConfig loads the application-specific .dorgu.yaml from the given path
 func LoadAppConfig(appPath string) (*AppConfig, error) {
 	configPath := filepath.Join(appPath, ".dorgu.yaml")
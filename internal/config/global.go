@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -19,6 +20,12 @@ type GlobalConfig struct {
 
 	// Default values for generation
 	Defaults GlobalDefaults `yaml:"defaults"`
+
+	// Dorgu Operator connection settings
+	Operator GlobalOperatorConfig `yaml:"operator,omitempty"`
+
+	// Git hosting tokens for generate --create-pr
+	Git GlobalGitConfig `yaml:"git,omitempty"`
 }
 
 // GlobalLLMConfig contains LLM provider settings
@@ -35,6 +42,27 @@ type GlobalDefaults struct {
 	OrgName   string `yaml:"org_name"`  // organization name
 }
 
+// GlobalOperatorConfig contains Dorgu Operator connection settings
+type GlobalOperatorConfig struct {
+	RequestTimeout string `yaml:"request_timeout,omitempty"` // Go duration, e.g. 30s, 2m
+}
+
+// GlobalGitConfig contains Git hosting API tokens
+type GlobalGitConfig struct {
+	GitHubToken string `yaml:"github_token,omitempty"` // env GITHUB_TOKEN/GH_TOKEN takes precedence
+	GitLabToken string `yaml:"gitlab_token,omitempty"` // env GITLAB_TOKEN takes precedence
+}
+
+// RequestTimeout returns the configured operator request timeout, or zero
+// when unset so callers fall back to their own default
+func (c *GlobalConfig) RequestTimeout() time.Duration {
+	d, err := time.ParseDuration(c.Operator.RequestTimeout)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
 // GlobalConfigDir returns the path to the dorgu config directory
 func GlobalConfigDir() string {
 	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
@@ -125,8 +153,20 @@ func (c *GlobalConfig) Set(key, value string) error {
 		c.Defaults.Registry = value
 	case "defaults.org_name":
 		c.Defaults.OrgName = value
+	case "operator.request_timeout":
+		if value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("invalid operator.request_timeout: %s (use a duration such as 30s or 2m)", value)
+			}
+		}
+		c.Operator.RequestTimeout = value
+	case "git.github_token":
+		c.Git.GitHubToken = value
+	case "git.gitlab_token":
+		c.Git.GitLabToken = value
 	default:
-		return fmt.Errorf("unknown config key: %s\n\nValid keys:\n  llm.provider\n  llm.api_key\n  llm.model\n  defaults.namespace\n  defaults.registry\n  defaults.org_name", key)
+		return fmt.Errorf("unknown config key: %s\n\nValid keys:\n  llm.provider\n  llm.api_key\n  llm.model\n  defaults.namespace\n  defaults.registry\n  defaults.org_name\n  operator.request_timeout\n  git.github_token\n  git.gitlab_token", key)
 	}
 	return nil
 }
@@ -149,6 +189,12 @@ func (c *GlobalConfig) Get(key string) (string, error) {
 		return c.Defaults.Registry, nil
 	case "defaults.org_name":
 		return c.Defaults.OrgName, nil
+	case "operator.request_timeout":
+		return c.Operator.RequestTimeout, nil
+	case "git.github_token":
+		return maskToken(c.Git.GitHubToken), nil
+	case "git.gitlab_token":
+		return maskToken(c.Git.GitLabToken), nil
 	default:
 		return "", fmt.Errorf("unknown config key: %s", key)
 	}
@@ -177,6 +223,21 @@ func (c *GlobalConfig) GetAPIKey(provider string) string {
 	return c.LLM.APIKey
 }
 
+// GetGitToken returns the API token for a Git host kind ("github" or
+// "gitlab"). Priority: env var > global config
+func (c *GlobalConfig) GetGitToken(kind string) string {
+	if env := envKeyForGitToken("git." + kind + "_token"); env != "" {
+		return os.Getenv(env)
+	}
+	switch kind {
+	case "github":
+		return c.Git.GitHubToken
+	case "gitlab":
+		return c.Git.GitLabToken
+	}
+	return ""
+}
+
 // GetEffectiveProvider returns the LLM provider to use (flag > global > empty)
 func (c *GlobalConfig) GetEffectiveProvider(flagValue string) string {
 	if flagValue != "" {
@@ -187,9 +248,9 @@ func (c *GlobalConfig) GetEffectiveProvider(flagValue string) string {
 
 // ConfigEntry represents a single config key-value with its source
 type ConfigEntry struct {
-	Key    string
-	Value  string
-	Source string
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
 }
 
 // ListAll returns all config values for display
@@ -201,8 +262,15 @@ func (c *GlobalConfig) ListAll() []ConfigEntry {
 		{Key: "defaults.namespace", Value: c.Defaults.Namespace, Source: "global"},
 		{Key: "defaults.registry", Value: c.Defaults.Registry, Source: "global"},
 		{Key: "defaults.org_name", Value: c.Defaults.OrgName, Source: "global"},
+		{Key: "operator.request_timeout", Value: c.Operator.RequestTimeout, Source: "global"},
+		{Key: "git.github_token", Value: maskKey(c.Git.GitHubToken), Source: "global"},
+		{Key: "git.gitlab_token", Value: maskKey(c.Git.GitLabToken), Source: "global"},
 	}
 	for i := range entries {
+		if env := envKeyForGitToken(entries[i].Key); env != "" {
+			entries[i].Value = maskKey(os.Getenv(env))
+			entries[i].Source = "env:" + env
+		}
 		if entries[i].Key == "llm.api_key" {
 			envKey := envKeyForProvider(c.LLM.Provider)
 			if envKey != "" && os.Getenv(envKey) != "" {
@@ -214,6 +282,14 @@ func (c *GlobalConfig) ListAll() []ConfigEntry {
 	return entries
 }
 
+// maskToken masks a set token and leaves an unset one empty
+func maskToken(token string) string {
+	if token == "" {
+		return ""
+	}
+	return maskKey(token)
+}
+
 func maskKey(key string) string {
 	if key == "" {
 		return "(not set)"
@@ -236,3 +312,21 @@ func envKeyForProvider(provider string) string {
 		return ""
 	}
 }
+
+// envKeyForGitToken returns the set environment variable that overrides a
+// git.* token key, if any
+func envKeyForGitToken(key string) string {
+	var envs []string
+	switch key {
+	case "git.github_token":
+		envs = []string{"GITHUB_TOKEN", "GH_TOKEN"}
+	case "git.gitlab_token":
+		envs = []string{"GITLAB_TOKEN"}
+	}
+	for _, env := range envs {
+		if os.Getenv(env) != "" {
+			return env
+		}
+	}
+	return ""
+}
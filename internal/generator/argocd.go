@@ -15,10 +15,19 @@ type ArgoCDApplication struct {
 
 // ArgoCDAppSpec represents the ArgoCD Application spec
 type ArgoCDAppSpec struct {
-	Project     string            `json:"project"`
-	Source      ArgoCDSource      `json:"source"`
-	Destination ArgoCDDest        `json:"destination"`
-	SyncPolicy  *ArgoCDSyncPolicy `json:"syncPolicy,omitempty"`
+	Project           string                   `json:"project"`
+	Source            ArgoCDSource             `json:"source"`
+	Destination       ArgoCDDest               `json:"destination"`
+	SyncPolicy        *ArgoCDSyncPolicy        `json:"syncPolicy,omitempty"`
+	IgnoreDifferences []ArgoCDIgnoreDifference `json:"ignoreDifferences,omitempty"`
+}
+
+// ArgoCDIgnoreDifference excludes fields of a resource from drift detection
+type ArgoCDIgnoreDifference struct {
+	Group        string   `json:"group"`
+	Kind         string   `json:"kind"`
+	Name         string   `json:"name,omitempty"`
+	JSONPointers []string `json:"jsonPointers"`
 }
 
 // ArgoCDSource represents the source configuration
@@ -36,8 +45,15 @@ type ArgoCDDest struct {
 
 // ArgoCDSyncPolicy represents sync policy configuration
 type ArgoCDSyncPolicy struct {
-	Automated   *ArgoCDAutomated `json:"automated,omitempty"`
-	SyncOptions []string         `json:"syncOptions,omitempty"`
+	Automated                *ArgoCDAutomated         `json:"automated,omitempty"`
+	SyncOptions              []string                 `json:"syncOptions,omitempty"`
+	ManagedNamespaceMetadata *ArgoCDNamespaceMetadata `json:"managedNamespaceMetadata,omitempty"`
+}
+
+// ArgoCDNamespaceMetadata is the metadata ArgoCD sets on the namespace it
+// creates
+type ArgoCDNamespaceMetadata struct {
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // ArgoCDAutomated represents automated sync settings
@@ -89,5 +105,21 @@ func GenerateArgoCD(analysis *types.AppAnalysis, namespace string, cfg *config.C
 		},
 	}
 
+	// Enforce the app's Pod Security Standard on the namespace ArgoCD creates
+	if labels := podSecurityLabels(podSecurityStandard(analysis, cfg)); labels != nil {
+		app.Spec.SyncPolicy.ManagedNamespaceMetadata = &ArgoCDNamespaceMetadata{Labels: labels}
+	}
+
+	// Keep ArgoCD from resetting the replica count the HPA scaled to
+	if hasHPA(analysis) && hpaReplicasPolicy(cfg) == config.HPAReplicasIgnore {
+		app.Spec.IgnoreDifferences = []ArgoCDIgnoreDifference{{
+			Group:        "apps",
+			Kind:         "Deployment",
+			Name:         analysis.Name,
+			JSONPointers: []string{"/spec/replicas"},
+		}}
+		app.Spec.SyncPolicy.SyncOptions = append(app.Spec.SyncPolicy.SyncOptions, "RespectIgnoreDifferences=true")
+	}
+
 	return toYAML(app)
 }
@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// appContainers returns the additional containers from the app config
+func appContainers(analysis *types.AppAnalysis) []types.ContainerContext {
+	if analysis.AppConfig == nil {
+		return nil
+	}
+	return analysis.AppConfig.Containers
+}
+
+// extraContainerPorts returns the named ports of the i-th additional
+// container. Ports without a name are numbered after the ports before them,
+// so names stay unique within the pod.
+func extraContainerPorts(analysis *types.AppAnalysis, cfg *config.Config, index int) []namedPort {
+	containers := appContainers(analysis)
+	next := len(appPorts(analysis, cfg))
+	for _, other := range containers[:index] {
+		next += len(other.Ports)
+	}
+	var ports []namedPort
+	for i, p := range containers[index].Ports {
+		name := p.Name
+		if name == "" {
+			name = fmt.Sprintf("port-%d", next+i)
+		}
+		ports = append(ports, namedPort{Name: name, Port: p.Port})
+	}
+	return ports
+}
+
+// podPorts returns the ports of every container in the app's pod, the app's
+// own first; the Service exposes all of them
+func podPorts(analysis *types.AppAnalysis, cfg *config.Config) []namedPort {
+	ports := appPorts(analysis, cfg)
+	for i := range appContainers(analysis) {
+		ports = append(ports, extraContainerPorts(analysis, cfg, i)...)
+	}
+	return ports
+}
+
+// additionalContainers builds the pod's containers besides the app's. They
+// get the org default resources unless they set their own, and probes only
+// when their health config asks for them.
+func additionalContainers(analysis *types.AppAnalysis, cfg *config.Config) []Container {
+	var containers []Container
+	for i, c := range appContainers(analysis) {
+		var ports []ContainerPort
+		var probePorts []types.Port
+		for _, p := range extraContainerPorts(analysis, cfg, i) {
+			ports = append(ports, ContainerPort{Name: p.Name, ContainerPort: p.Port, Protocol: "TCP"})
+			probePorts = append(probePorts, types.Port{Port: p.Port})
+		}
+
+		var env []EnvVar
+		for _, k := range sortedMapKeys(c.Env) {
+			env = append(env, EnvVar{Name: k, Value: c.Env[k]})
+		}
+
+		var liveness, readiness *types.HealthCheck
+		if c.Health != nil {
+			liveness = resolveProbe("liveness", c.Health.Liveness, nil, c.Health.Readiness, probePorts)
+			readiness = resolveProbe("readiness", c.Health.Readiness, nil, c.Health.Liveness, probePorts)
+			if liveness != nil {
+				liveness.SuccessThreshold = 0 // must be 1, the Kubernetes default
+			}
+		}
+
+		_, securityContext := securityContexts(analysis, cfg)
+		containers = append(containers, Container{
+			Name:            c.Name,
+			Image:           c.Image,
+			Command:         c.Command,
+			Args:            c.Args,
+			Ports:           ports,
+			Env:             env,
+			Resources:       resourceRequirements(overrideResources(cfg.Resources.Defaults, c.Resources)),
+			LivenessProbe:   toProbe(liveness),
+			ReadinessProbe:  toProbe(readiness),
+			SecurityContext: securityContext,
+		})
+	}
+	return containers
+}
+
+// checkContainers validates the additional containers: unique valid names,
+// an image, and ports that are unique within the pod
+func checkContainers(analysis *types.AppAnalysis, cfg *config.Config) error {
+	var errs []error
+	names := map[string]bool{analysis.Name: true}
+	portNames := map[string]bool{}
+	portNumbers := map[int]bool{}
+	for _, p := range appPorts(analysis, cfg) {
+		portNames[p.Name] = true
+		portNumbers[p.Port] = true
+	}
+	for i, c := range appContainers(analysis) {
+		field := fmt.Sprintf("containers[%d]", i)
+		switch {
+		case !IsResourceName(c.Name):
+			errs = append(errs, fmt.Errorf("%s.name: %q must be lowercase alphanumerics and '-'", field, c.Name))
+		case names[c.Name]:
+			errs = append(errs, fmt.Errorf("%s.name: %q is already used by another container", field, c.Name))
+		}
+		names[c.Name] = true
+		if strings.TrimSpace(c.Image) == "" {
+			errs = append(errs, fmt.Errorf("%s.image: is required", field))
+		}
+		for j, p := range extraContainerPorts(analysis, cfg, i) {
+			pf := fmt.Sprintf("%s.ports[%d]", field, j)
+			if msgs := validation.IsValidPortNum(p.Port); len(msgs) > 0 {
+				errs = append(errs, fmt.Errorf("%s.port: %s", pf, strings.Join(msgs, "; ")))
+			} else if portNumbers[p.Port] {
+				errs = append(errs, fmt.Errorf("%s.port: %d is already used in the pod", pf, p.Port))
+			}
+			if msgs := validation.IsValidPortName(p.Name); len(msgs) > 0 {
+				errs = append(errs, fmt.Errorf("%s.name: %q %s", pf, p.Name, strings.Join(msgs, "; ")))
+			} else if portNames[p.Name] {
+				errs = append(errs, fmt.Errorf("%s.name: %q is already used in the pod", pf, p.Name))
+			}
+			portNumbers[p.Port] = true
+			portNames[p.Name] = true
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid containers in the app .dorgu.yaml:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestAdditionalContainers(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:  "shop",
+		Ports: []types.Port{{Port: 8080}},
+		AppConfig: &types.AppConfigContext{
+			Containers: []types.ContainerContext{{
+				Name:      "nginx",
+				Image:     "nginx:1.27",
+				Ports:     []types.ContainerPort{{Port: 80, Name: "http"}, {Port: 9113}},
+				Env:       map[string]string{"NGINX_PORT": "80"},
+				Health:    &types.HealthContext{Readiness: &types.HealthCheck{Path: "/healthz"}},
+				Resources: &types.ResourceOverrides{LimitsMemory: "64Mi"},
+			}},
+		},
+	}
+	cfg := config.Default()
+
+	deployment, err := GenerateDeployment(analysis, "default", cfg.Resources.Defaults, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"name: nginx", "image: nginx:1.27", "containerPort: 80", "name: http", "name: port-2", "path: /healthz", "memory: 64Mi", "name: NGINX_PORT"} {
+		if !strings.Contains(deployment, want) {
+			t.Errorf("Deployment missing %q:\n%s", want, deployment)
+		}
+	}
+
+	service, err := GenerateService(analysis, "default", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"port: 8080", "port: 80\n", "port: 9113"} {
+		if !strings.Contains(service, want) {
+			t.Errorf("Service missing %q:\n%s", want, service)
+		}
+	}
+}
+
+func TestCheckContainers(t *testing.T) {
+	tests := []struct {
+		name      string
+		container types.ContainerContext
+		wantErr   string
+	}{
+		{"valid", types.ContainerContext{Name: "nginx", Image: "nginx", Ports: []types.ContainerPort{{Port: 80}}}, ""},
+		{"same name as app", types.ContainerContext{Name: "shop", Image: "nginx"}, "containers[0].name"},
+		{"no image", types.ContainerContext{Name: "nginx"}, "containers[0].image"},
+		{"port taken by app", types.ContainerContext{Name: "nginx", Image: "nginx", Ports: []types.ContainerPort{{Port: 8080}}}, "containers[0].ports[0].port"},
+		{"port name too long", types.ContainerContext{Name: "nginx", Image: "nginx", Ports: []types.ContainerPort{{Port: 80, Name: "static-frontend-http"}}}, "containers[0].ports[0].name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := &types.AppAnalysis{
+				Name:      "shop",
+				Ports:     []types.Port{{Port: 8080}},
+				AppConfig: &types.AppConfigContext{Containers: []types.ContainerContext{tt.container}},
+			}
+			err := checkContainers(analysis, config.Default())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkContainers() error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkContainers() error = %v, want one about %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// HoursPerMonth is the average number of hours in a month used by cloud
+// pricing pages
+const HoursPerMonth = 730
+
+// PriceTable is the hourly on-demand price of compute, in USD
+type PriceTable struct {
+	CPUPerHour float64 `json:"cpuPerHour"`
+	GiBPerHour float64 `json:"gibPerHour"`
+}
+
+// CloudPrices are approximate on-demand list prices of general-purpose nodes,
+// split per vCPU and per GiB of memory
+var CloudPrices = map[string]PriceTable{
+	"aws":   {CPUPerHour: 0.031611, GiBPerHour: 0.004237},
+	"gcp":   {CPUPerHour: 0.021811, GiBPerHour: 0.002923},
+	"azure": {CPUPerHour: 0.033000, GiBPerHour: 0.004400},
+}
+
+// CostEstimate is the estimated monthly cost of an application
+type CostEstimate struct {
+	App          string            `json:"app"`
+	Cloud        string            `json:"cloud"`
+	Prices       PriceTable        `json:"prices"`
+	Requests     ResourceAmount    `json:"requests"`
+	Limits       ResourceAmount    `json:"limits"`
+	Environments []EnvironmentCost `json:"environments"`
+}
+
+// ResourceAmount is the CPU and memory of one pod
+type ResourceAmount struct {
+	CPU    float64 `json:"cpu"`    // cores
+	Memory float64 `json:"memory"` // GiB
+}
+
+// EnvironmentCost is the monthly cost of one environment in USD
+type EnvironmentCost struct {
+	Name        string `json:"name"`
+	MinReplicas int    `json:"minReplicas"`
+	MaxReplicas int    `json:"maxReplicas"`
+	// Baseline is requests × min replicas, what the scheduler reserves at rest
+	Baseline float64 `json:"baseline"`
+	// Peak is requests × max replicas, when the HPA is fully scaled out
+	Peak float64 `json:"peak"`
+	// Ceiling is limits × max replicas, the most the app can consume
+	Ceiling float64 `json:"ceiling"`
+}
+
+// ResolvePrices returns the configured price table and the name of its source
+func ResolvePrices(cfg config.CostConfig) (PriceTable, string, error) {
+	cloud := strings.ToLower(cfg.Cloud)
+	if cloud == "" {
+		cloud = "aws"
+	}
+	prices, ok := CloudPrices[cloud]
+	if !ok {
+		if cfg.CPUPerHour == 0 || cfg.GiBPerHour == 0 {
+			return PriceTable{}, "", fmt.Errorf("unknown cost.cloud %q (built-in: aws, gcp, azure); set cost.cpu_per_hour and cost.gib_per_hour for custom prices", cfg.Cloud)
+		}
+	}
+	if cfg.CPUPerHour > 0 {
+		prices.CPUPerHour = cfg.CPUPerHour
+	}
+	if cfg.GiBPerHour > 0 {
+		prices.GiBPerHour = cfg.GiBPerHour
+	}
+	if cfg.CPUPerHour > 0 || cfg.GiBPerHour > 0 {
+		cloud += " (custom)"
+	}
+	return prices, cloud, nil
+}
+
+// EstimateCost estimates the monthly cost of the app's pods from its resource
+// requests and limits, replicas, and HPA bounds
+func EstimateCost(analysis *types.AppAnalysis, cfg *config.Config) (*CostEstimate, error) {
+	prices, cloud, err := ResolvePrices(cfg.Cost)
+	if err != nil {
+		return nil, err
+	}
+	res := buildPersonaResources(analysis, cfg)
+	est := &CostEstimate{
+		App:      analysis.Name,
+		Cloud:    cloud,
+		Prices:   prices,
+		Requests: ResourceAmount{CPU: float64(parseCPUMillis(res.Requests.CPU)) / 1000, Memory: gib(res.Requests.Memory)},
+		Limits:   ResourceAmount{CPU: float64(parseCPUMillis(res.Limits.CPU)) / 1000, Memory: gib(res.Limits.Memory)},
+	}
+
+	minReplicas, maxReplicas := defaultReplicaRange(analysis)
+	envs := cfg.Cost.Environments
+	if len(envs) == 0 {
+		name := analysis.Environment
+		if name == "" {
+			name = "production"
+		}
+		envs = map[string]config.CostEnvironment{name: {}}
+	}
+	names := make([]string, 0, len(envs))
+	for name := range envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		env := EnvironmentCost{Name: name, MinReplicas: minReplicas, MaxReplicas: maxReplicas}
+		if o := envs[name]; o.MinReplicas > 0 {
+			env.MinReplicas = o.MinReplicas
+			if env.MaxReplicas < o.MinReplicas {
+				env.MaxReplicas = o.MinReplicas
+			}
+		}
+		if o := envs[name]; o.MaxReplicas > 0 {
+			env.MaxReplicas = o.MaxReplicas
+		}
+		env.Baseline = monthly(est.Requests, env.MinReplicas, prices)
+		env.Peak = monthly(est.Requests, env.MaxReplicas, prices)
+		env.Ceiling = monthly(est.Limits, env.MaxReplicas, prices)
+		est.Environments = append(est.Environments, env)
+	}
+	return est, nil
+}
+
+// defaultReplicaRange mirrors the Deployment's replicas and the HPA bounds;
+// without autoscaling the range is the fixed replica count
+func defaultReplicaRange(analysis *types.AppAnalysis) (int, int) {
+	scaling := buildPersonaScaling(analysis)
+	if scaling == nil {
+		return 2, 2
+	}
+	minReplicas, maxReplicas := scaling.MinReplicas, scaling.MaxReplicas
+	if minReplicas <= 0 {
+		minReplicas = 2
+	}
+	if maxReplicas < minReplicas {
+		maxReplicas = minReplicas
+	}
+	return minReplicas, maxReplicas
+}
+
+func monthly(r ResourceAmount, replicas int, prices PriceTable) float64 {
+	return (r.CPU*prices.CPUPerHour + r.Memory*prices.GiBPerHour) * float64(replicas) * HoursPerMonth
+}
+
+func gib(memory string) float64 {
+	return float64(parseMemoryBytes(memory)) / (1 << 30)
+}
+
+// FormatCostLine is the one-line summary of an environment's cost
+func FormatCostLine(env EnvironmentCost) string {
+	if env.MinReplicas == env.MaxReplicas {
+		return fmt.Sprintf("$%.2f/month (%d replicas)", env.Baseline, env.MinReplicas)
+	}
+	return fmt.Sprintf("$%.2f–$%.2f/month (%d–%d replicas)", env.Baseline, env.Peak, env.MinReplicas, env.MaxReplicas)
+}
+
+// EmbedCostEstimate adds the estimate to the Resource Profile section of a
+// PERSONA.md document. Documents without that section are returned as is.
+func EmbedCostEstimate(markdown string, est *CostEstimate) string {
+	const heading = "## Resource Profile"
+	i := strings.Index(markdown, heading+"\n")
+	if i < 0 || est == nil || strings.Contains(markdown, "**Estimated cost") {
+		return markdown
+	}
+	var sb strings.Builder
+	sb.WriteString("\n")
+	for _, env := range est.Environments {
+		sb.WriteString(fmt.Sprintf("- **Estimated cost (%s):** %s, %s list prices\n", env.Name, FormatCostLine(env), est.Cloud))
+	}
+	// Insert right after the heading and its blank line
+	at := i + len(heading) + 1
+	rest := strings.TrimPrefix(markdown[at:], "\n")
+	if !strings.HasPrefix(rest, "- ") && !strings.HasPrefix(rest, "* ") {
+		sb.WriteString("\n")
+	}
+	return markdown[:at] + sb.String() + rest
+}
@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestEstimateCost(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:    "orders",
+		Scaling: &types.ScalingConfig{MinReplicas: 2, MaxReplicas: 6},
+		AppConfig: &types.AppConfigContext{Resources: &types.ResourceOverrides{
+			RequestsCPU: "500m", RequestsMemory: "1Gi", LimitsCPU: "1", LimitsMemory: "2Gi",
+		}},
+	}
+	cfg := config.Default()
+	cfg.Cost = config.CostConfig{CPUPerHour: 0.04, GiBPerHour: 0.01}
+
+	est, err := EstimateCost(analysis, cfg)
+	if err != nil {
+		t.Fatalf("EstimateCost() error: %v", err)
+	}
+	if len(est.Environments) != 1 || est.Environments[0].Name != "production" {
+		t.Fatalf("environments = %+v", est.Environments)
+	}
+	env := est.Environments[0]
+	// One pod at requests: 0.5*0.04 + 1*0.01 = 0.03/h
+	checks := map[string][2]float64{
+		"baseline": {env.Baseline, 0.03 * 2 * HoursPerMonth},
+		"peak":     {env.Peak, 0.03 * 6 * HoursPerMonth},
+		"ceiling":  {env.Ceiling, (0.04 + 0.02) * 6 * HoursPerMonth},
+	}
+	for name, c := range checks {
+		if math.Abs(c[0]-c[1]) > 0.001 {
+			t.Errorf("%s = %.3f, want %.3f", name, c[0], c[1])
+		}
+	}
+
+	cfg.Cost.Environments = map[string]config.CostEnvironment{
+		"staging":    {MinReplicas: 1, MaxReplicas: 1},
+		"production": {},
+	}
+	est, err = EstimateCost(analysis, cfg)
+	if err != nil {
+		t.Fatalf("EstimateCost() error: %v", err)
+	}
+	if got := est.Environments[1]; got.Name != "staging" || got.MinReplicas != 1 || got.Baseline != got.Peak {
+		t.Errorf("staging = %+v", got)
+	}
+}
+
+func TestResolvePrices(t *testing.T) {
+	prices, cloud, err := ResolvePrices(config.CostConfig{Cloud: "GCP"})
+	if err != nil || cloud != "gcp" || prices != CloudPrices["gcp"] {
+		t.Errorf("ResolvePrices(gcp) = %v, %q, %v", prices, cloud, err)
+	}
+	if _, _, err := ResolvePrices(config.CostConfig{Cloud: "onprem"}); err == nil {
+		t.Error("expected an error for an unknown cloud without prices")
+	}
+	prices, cloud, err = ResolvePrices(config.CostConfig{Cloud: "onprem", CPUPerHour: 0.02, GiBPerHour: 0.003})
+	if err != nil || cloud != "onprem (custom)" || prices.CPUPerHour != 0.02 {
+		t.Errorf("ResolvePrices(onprem) = %v, %q, %v", prices, cloud, err)
+	}
+}
+
+func TestEmbedCostEstimate(t *testing.T) {
+	est := &CostEstimate{Cloud: "aws", Environments: []EnvironmentCost{{Name: "production", MinReplicas: 2, MaxReplicas: 4, Baseline: 10, Peak: 20}}}
+	doc := "# orders\n\n## Resource Profile\n\n- **Profile:** api\n\n## Health\n"
+
+	got := EmbedCostEstimate(doc, est)
+	want := "# orders\n\n## Resource Profile\n\n- **Estimated cost (production):** $10.00–$20.00/month (2–4 replicas), aws list prices\n- **Profile:** api\n\n## Health\n"
+	if got != want {
+		t.Errorf("EmbedCostEstimate() =\n%s\nwant\n%s", got, want)
+	}
+	if again := EmbedCostEstimate(got, est); again != got {
+		t.Errorf("estimate embedded twice:\n%s", again)
+	}
+	if !strings.Contains(EmbedCostEstimate("## Resource Profile\nUses little CPU.\n", est), "aws list prices\n\nUses little CPU.") {
+		t.Error("expected a blank line before prose")
+	}
+	if EmbedCostEstimate("# no section\n", est) != "# no section\n" {
+		t.Error("document without a Resource Profile section changed")
+	}
+}
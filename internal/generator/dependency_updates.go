@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+)
+
+// Repository-level dependency update configs, relative to the output
+// directory
+const (
+	RenovateFile   = "../renovate.json"
+	DependabotFile = "../.github/dependabot.yml"
+)
+
+// ValidateDependencyUpdates checks ci.dependency_updates
+func ValidateDependencyUpdates(value string) error {
+	switch value {
+	case "", config.DependencyUpdatesNone, config.DependencyUpdatesRenovate, config.DependencyUpdatesDependabot:
+		return nil
+	}
+	return fmt.Errorf("%q is not one of renovate, dependabot, none", value)
+}
+
+// GenerateDependencyUpdates returns the Renovate or Dependabot config that
+// keeps the images in the generated manifests and the action versions in the
+// workflows up to date, per ci.dependency_updates
+func GenerateDependencyUpdates(opts Options) ([]GeneratedFile, error) {
+	mode := opts.Config.CI.DependencyUpdates
+	if err := ValidateDependencyUpdates(mode); err != nil {
+		return nil, fmt.Errorf("ci.dependency_updates: %w", err)
+	}
+	switch mode {
+	case config.DependencyUpdatesRenovate:
+		content, err := renovateConfig(opts.manifestsDir())
+		if err != nil {
+			return nil, err
+		}
+		return []GeneratedFile{{Path: RenovateFile, Content: content}}, nil
+	case config.DependencyUpdatesDependabot:
+		return []GeneratedFile{{Path: DependabotFile, Content: dependabotConfig(opts.manifestsDir())}}, nil
+	}
+	return nil, nil
+}
+
+// renovateConfig limits Renovate to the manifests' images and the workflows'
+// actions, grouping each into one PR
+func renovateConfig(manifestsDir string) (string, error) {
+	cfg := map[string]interface{}{
+		"$schema":         "https://docs.renovatebot.com/renovate-schema.json",
+		"extends":         []string{"config:recommended"},
+		"enabledManagers": []string{"kubernetes", "github-actions"},
+		"kubernetes": map[string]interface{}{
+			"fileMatch": []string{"^" + regexp.QuoteMeta(manifestsDir) + `/.+\.ya?ml$`},
+		},
+		"packageRules": []map[string]interface{}{
+			{"matchManagers": []string{"kubernetes"}, "groupName": "container images"},
+			{"matchManagers": []string{"github-actions"}, "groupName": "GitHub Actions"},
+		},
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// dependabotConfig has Dependabot update the images in the manifests (its
+// docker ecosystem reads Kubernetes manifests) and the workflows' actions
+func dependabotConfig(manifestsDir string) string {
+	return fmt.Sprintf(`version: 2
+updates:
+  - package-ecosystem: docker
+    directory: /%s
+    schedule:
+      interval: weekly
+    groups:
+      container-images:
+        patterns: ["*"]
+  - package-ecosystem: github-actions
+    directory: /
+    schedule:
+      interval: weekly
+    groups:
+      github-actions:
+        patterns: ["*"]
+`, manifestsDir)
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+)
+
+func TestGenerateDependencyUpdates(t *testing.T) {
+	tests := []struct {
+		mode     string
+		wantPath string
+		want     []string
+	}{
+		{"", "", nil},
+		{config.DependencyUpdatesNone, "", nil},
+		{config.DependencyUpdatesRenovate, RenovateFile, []string{`"enabledManagers": [`, `"^deploy/k8s/.+\\.ya?ml$"`}},
+		{config.DependencyUpdatesDependabot, DependabotFile, []string{"directory: /deploy/k8s", "package-ecosystem: github-actions"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.CI.DependencyUpdates = tt.mode
+			files, err := GenerateDependencyUpdates(Options{Config: cfg, ManifestsDir: "deploy/k8s"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantPath == "" {
+				if len(files) != 0 {
+					t.Errorf("files = %v, want none", files)
+				}
+				return
+			}
+			if len(files) != 1 || files[0].Path != tt.wantPath {
+				t.Fatalf("files = %v, want %s", files, tt.wantPath)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(files[0].Content, w) {
+					t.Errorf("%s missing %q:\n%s", tt.wantPath, w, files[0].Content)
+				}
+			}
+		})
+	}
+}
+
+func TestRenovateConfigIsJSON(t *testing.T) {
+	content, err := renovateConfig("k8s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &v); err != nil {
+		t.Errorf("renovate.json is not valid JSON: %v", err)
+	}
+}
+
+func TestGenerateDependencyUpdatesInvalid(t *testing.T) {
+	cfg := config.Default()
+	cfg.CI.DependencyUpdates = "snyk"
+	if _, err := GenerateDependencyUpdates(Options{Config: cfg}); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -29,7 +29,7 @@ type Metadata struct {
 
 // DeploymentSpec represents a Deployment spec
 type DeploymentSpec struct {
-	Replicas int             `json:"replicas"`
+	Replicas *int            `json:"replicas,omitempty"`
 	Selector LabelSelector   `json:"selector"`
 	Template PodTemplateSpec `json:"template"`
 }
@@ -47,9 +47,14 @@ type PodTemplateSpec struct {
 
 // PodSpec represents a pod spec
 type PodSpec struct {
+	RestartPolicy      string              `json:"restartPolicy,omitempty"`
+	RuntimeClassName   string              `json:"runtimeClassName,omitempty"`
 	Containers         []Container         `json:"containers"`
 	SecurityContext    *PodSecurityContext `json:"securityContext,omitempty"`
 	ServiceAccountName string              `json:"serviceAccountName,omitempty"`
+	DNSConfig          *PodDNSConfig       `json:"dnsConfig,omitempty"`
+	HostAliases        []HostAlias         `json:"hostAliases,omitempty"`
+	Volumes            []Volume            `json:"volumes,omitempty"`
 }
 
 // PodSecurityContext represents pod security context
@@ -60,19 +65,23 @@ type PodSecurityContext struct {
 
 // SeccompProfile represents seccomp profile
 type SeccompProfile struct {
-	Type string `json:"type"`
+	Type             string `json:"type"`
+	LocalhostProfile string `json:"localhostProfile,omitempty"`
 }
 
 // Container represents a container spec
 type Container struct {
 	Name            string                    `json:"name"`
 	Image           string                    `json:"image"`
+	Command         []string                  `json:"command,omitempty"`
+	Args            []string                  `json:"args,omitempty"`
 	Ports           []ContainerPort           `json:"ports,omitempty"`
 	Env             []EnvVar                  `json:"env,omitempty"`
 	Resources       ResourceRequirements      `json:"resources,omitempty"`
 	LivenessProbe   *Probe                    `json:"livenessProbe,omitempty"`
 	ReadinessProbe  *Probe                    `json:"readinessProbe,omitempty"`
 	SecurityContext *ContainerSecurityContext `json:"securityContext,omitempty"`
+	VolumeMounts    []VolumeMount             `json:"volumeMounts,omitempty"`
 }
 
 // ContainerPort represents a container port
@@ -93,6 +102,7 @@ type EnvVar struct {
 type EnvVarSource struct {
 	SecretKeyRef    *SecretKeySelector    `json:"secretKeyRef,omitempty"`
 	ConfigMapKeyRef *ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	FieldRef        *ObjectFieldSelector  `json:"fieldRef,omitempty"`
 }
 
 // SecretKeySelector selects a key from a secret
@@ -119,6 +129,7 @@ type Probe struct {
 	InitialDelaySeconds int            `json:"initialDelaySeconds,omitempty"`
 	PeriodSeconds       int            `json:"periodSeconds,omitempty"`
 	TimeoutSeconds      int            `json:"timeoutSeconds,omitempty"`
+	SuccessThreshold    int            `json:"successThreshold,omitempty"`
 	FailureThreshold    int            `json:"failureThreshold,omitempty"`
 }
 
@@ -133,6 +144,7 @@ type HTTPGetAction struct {
 type ContainerSecurityContext struct {
 	AllowPrivilegeEscalation *bool         `json:"allowPrivilegeEscalation,omitempty"`
 	ReadOnlyRootFilesystem   *bool         `json:"readOnlyRootFilesystem,omitempty"`
+	RunAsUser                *int64        `json:"runAsUser,omitempty"`
 	Capabilities             *Capabilities `json:"capabilities,omitempty"`
 }
 
@@ -150,147 +162,76 @@ func GenerateDeployment(analysis *types.AppAnalysis, namespace string, resources
 	// Build annotations from app config
 	annotations := buildAnnotationsWithAppConfig(analysis, cfg)
 
-	// Build container ports
+	// Build container ports, including a named metrics port when detected
 	var containerPorts []ContainerPort
-	for i, p := range analysis.Ports {
+	for _, p := range appPorts(analysis, cfg) {
 		containerPorts = append(containerPorts, ContainerPort{
-			Name:          fmt.Sprintf("port-%d", i),
+			Name:          p.Name,
 			ContainerPort: p.Port,
 			Protocol:      "TCP",
 		})
 	}
 
-	// Build environment variables
-	var envVars []EnvVar
-	for _, e := range analysis.EnvVars {
-		ev := EnvVar{Name: e.Name}
-		if e.Secret {
-			// Reference from secret
-			ev.ValueFrom = &EnvVarSource{
-				SecretKeyRef: &SecretKeySelector{
-					Name: strings.ToLower(analysis.Name) + "-secrets",
-					Key:  strings.ToLower(e.Name),
-				},
-			}
-		} else if e.Value != "" {
-			ev.Value = e.Value
-		}
-		envVars = append(envVars, ev)
-	}
+	envVars := containerEnv(analysis, namespace, cfg)
+	finalResources := appResources(analysis, resources)
 
-	// Override resources from app config if present
-	finalResources := resources
-	if analysis.AppConfig != nil && analysis.AppConfig.Resources != nil {
-		res := analysis.AppConfig.Resources
-		if res.RequestsCPU != "" {
-			finalResources.Requests.CPU = res.RequestsCPU
-		}
-		if res.RequestsMemory != "" {
-			finalResources.Requests.Memory = res.RequestsMemory
-		}
-		if res.LimitsCPU != "" {
-			finalResources.Limits.CPU = res.LimitsCPU
-		}
-		if res.LimitsMemory != "" {
-			finalResources.Limits.Memory = res.LimitsMemory
-		}
-	}
+	// Build probes - liveness and readiness are resolved independently
+	liveness, readiness := resolveProbes(analysis)
+	livenessProbe, readinessProbe := toProbe(liveness), toProbe(readiness)
 
-	// Build probes - use app config health settings if available
-	var livenessProbe, readinessProbe *Probe
-	if analysis.AppConfig != nil && analysis.AppConfig.Health != nil {
-		health := analysis.AppConfig.Health
-		if health.LivenessPath != "" {
-			livenessProbe = &Probe{
-				HTTPGet: &HTTPGetAction{
-					Path: health.LivenessPath,
-					Port: health.LivenessPort,
-				},
-				InitialDelaySeconds: health.InitialDelay,
-				PeriodSeconds:       health.Period,
-				TimeoutSeconds:      5,
-				FailureThreshold:    3,
-			}
-			if livenessProbe.InitialDelaySeconds == 0 {
-				livenessProbe.InitialDelaySeconds = 10
-			}
-			if livenessProbe.PeriodSeconds == 0 {
-				livenessProbe.PeriodSeconds = 10
-			}
-		}
-		if health.ReadinessPath != "" {
-			readinessProbe = &Probe{
-				HTTPGet: &HTTPGetAction{
-					Path: health.ReadinessPath,
-					Port: health.ReadinessPort,
-				},
-				InitialDelaySeconds: health.InitialDelay,
-				PeriodSeconds:       health.Period,
-				TimeoutSeconds:      5,
-				FailureThreshold:    3,
-			}
-			if readinessProbe.InitialDelaySeconds == 0 {
-				readinessProbe.InitialDelaySeconds = 5
-			}
-			if readinessProbe.PeriodSeconds == 0 {
-				readinessProbe.PeriodSeconds = 5
-			}
-		}
-	}
+	podSecurityContext, containerSecurityContext := securityContexts(analysis, cfg)
+	imageName := appImage(analysis, cfg)
 
-	// Fallback to analysis health check if app config didn't specify
-	if livenessProbe == nil && analysis.HealthCheck != nil {
-		probe := &Probe{
-			HTTPGet: &HTTPGetAction{
-				Path: analysis.HealthCheck.Path,
-				Port: analysis.HealthCheck.Port,
-			},
-			InitialDelaySeconds: 10,
-			PeriodSeconds:       10,
-			TimeoutSeconds:      5,
-			FailureThreshold:    3,
-		}
-		if analysis.HealthCheck.InitialDelay > 0 {
-			probe.InitialDelaySeconds = analysis.HealthCheck.InitialDelay
-		}
-		if analysis.HealthCheck.Period > 0 {
-			probe.PeriodSeconds = analysis.HealthCheck.Period
-		}
-		livenessProbe = probe
-		readinessProbe = probe
+	// Determine replicas - prefer app config scaling. With an HPA, the
+	// replica count is left to it unless org policy says otherwise.
+	replicas := 2
+	if analysis.AppConfig != nil && analysis.AppConfig.Scaling != nil && analysis.AppConfig.Scaling.MinReplicas > 0 {
+		replicas = analysis.AppConfig.Scaling.MinReplicas
+	} else if analysis.Scaling != nil && analysis.Scaling.MinReplicas > 0 {
+		replicas = analysis.Scaling.MinReplicas
 	}
-
-	// Build security contexts
-	trueVal := true
-	falseVal := false
-
-	podSecurityContext := &PodSecurityContext{
-		RunAsNonRoot: &trueVal,
-		SeccompProfile: &SeccompProfile{
-			Type: "RuntimeDefault",
-		},
+	replicasField := &replicas
+	if hasHPA(analysis) && hpaReplicasPolicy(cfg) == config.HPAReplicasOmit {
+		replicasField = nil
 	}
 
-	containerSecurityContext := &ContainerSecurityContext{
-		AllowPrivilegeEscalation: &falseVal,
-		ReadOnlyRootFilesystem:   &trueVal,
-		Capabilities: &Capabilities{
-			Drop: []corev1.Capability{"ALL"},
-		},
+	// Mount the Secrets Store CSI volume that syncs <app>-secrets
+	var volumes []Volume
+	var volumeMounts []VolumeMount
+	if volume, mount := secretsVolume(analysis, cfg); volume != nil {
+		volumes = append(volumes, *volume)
+		volumeMounts = append(volumeMounts, *mount)
 	}
-
-	// Determine image name
-	imageName := fmt.Sprintf("%s/%s:latest", cfg.CI.Registry, analysis.Name)
-	if cfg.CI.Registry == "" {
-		imageName = analysis.Name + ":latest"
+	// Serve a static site with the generated nginx.conf, as nginx's user
+	if hasStaticSite(analysis) {
+		nginxVols, nginxMounts := nginxVolumes(analysis)
+		volumes = append(volumes, nginxVols...)
+		volumeMounts = append(volumeMounts, nginxMounts...)
+		user := nginxUser
+		containerSecurityContext.RunAsUser = &user
 	}
 
-	// Determine replicas - prefer app config scaling
-	replicas := 2
-	if analysis.AppConfig != nil && analysis.AppConfig.Scaling != nil && analysis.AppConfig.Scaling.MinReplicas > 0 {
-		replicas = analysis.AppConfig.Scaling.MinReplicas
-	} else if analysis.Scaling != nil && analysis.Scaling.MinReplicas > 0 {
-		replicas = analysis.Scaling.MinReplicas
+	containers := append([]Container{
+		{
+			Name:            analysis.Name,
+			Image:           imageName,
+			Ports:           containerPorts,
+			Env:             envVars,
+			Resources:       resourceRequirements(finalResources),
+			LivenessProbe:   livenessProbe,
+			ReadinessProbe:  readinessProbe,
+			SecurityContext: containerSecurityContext,
+			VolumeMounts:    volumeMounts,
+		},
+	}, additionalContainers(analysis, cfg)...)
+	security := resolveSecurity(analysis, cfg)
+	podAnnotations := withVaultAgentAnnotations(withMetricsAnnotations(annotations, analysis, cfg), analysis, cfg)
+	if hasStaticSite(analysis) {
+		merged := map[string]string{"checksum/nginx-conf": nginxConfigChecksum(analysis.StaticSite)}
+		for k, v := range podAnnotations {
+			merged[k] = v
+		}
+		podAnnotations = merged
 	}
 
 	deployment := DeploymentManifest{
@@ -303,7 +244,7 @@ func GenerateDeployment(analysis *types.AppAnalysis, namespace string, resources
 			Annotations: annotations,
 		},
 		Spec: DeploymentSpec{
-			Replicas: replicas,
+			Replicas: replicasField,
 			Selector: LabelSelector{
 				MatchLabels: map[string]string{
 					"app.kubernetes.io/name": analysis.Name,
@@ -312,31 +253,15 @@ func GenerateDeployment(analysis *types.AppAnalysis, namespace string, resources
 			Template: PodTemplateSpec{
 				Metadata: Metadata{
 					Labels:      labels,
-					Annotations: annotations,
+					Annotations: withAppArmorAnnotations(podAnnotations, security.AppArmor, containers),
 				},
 				Spec: PodSpec{
-					SecurityContext: podSecurityContext,
-					Containers: []Container{
-						{
-							Name:  analysis.Name,
-							Image: imageName,
-							Ports: containerPorts,
-							Env:   envVars,
-							Resources: ResourceRequirements{
-								Requests: map[string]string{
-									"cpu":    finalResources.Requests.CPU,
-									"memory": finalResources.Requests.Memory,
-								},
-								Limits: map[string]string{
-									"cpu":    finalResources.Limits.CPU,
-									"memory": finalResources.Limits.Memory,
-								},
-							},
-							LivenessProbe:   livenessProbe,
-							ReadinessProbe:  readinessProbe,
-							SecurityContext: containerSecurityContext,
-						},
-					},
+					RuntimeClassName: security.RuntimeClass,
+					SecurityContext:  podSecurityContext,
+					DNSConfig:        podDNSConfig(analysis),
+					HostAliases:      hostAliases(analysis),
+					Volumes:          volumes,
+					Containers:       containers,
 				},
 			},
 		},
@@ -345,6 +270,119 @@ func GenerateDeployment(analysis *types.AppAnalysis, namespace string, resources
 	return toYAML(deployment)
 }
 
+// containerEnv builds the app container's environment; the app's own
+// variables come first, then org standards
+func containerEnv(analysis *types.AppAnalysis, namespace string, cfg *config.Config) []EnvVar {
+	var envVars []EnvVar
+	defined := make(map[string]bool)
+	vaultAgent := secretsProvider(analysis, cfg) == config.SecretsVaultAgent
+	for _, e := range analysis.EnvVars {
+		defined[e.Name] = true
+		ev := EnvVar{Name: e.Name}
+		if e.Secret && vaultAgent {
+			// Rendered to VaultSecretsFile by the Vault Agent injector
+			continue
+		} else if e.Secret {
+			// Reference from secret
+			ev.ValueFrom = &EnvVarSource{
+				SecretKeyRef: &SecretKeySelector{
+					Name: strings.ToLower(analysis.Name) + "-secrets",
+					Key:  strings.ToLower(e.Name),
+				},
+			}
+		} else if e.Value != "" {
+			ev.Value = e.Value
+		}
+		envVars = append(envVars, ev)
+	}
+	return append(envVars, standardEnv(analysis, namespace, cfg, defined)...)
+}
+
+// appResources applies the app config's resource overrides to resources
+func appResources(analysis *types.AppAnalysis, resources config.ResourceSpec) config.ResourceSpec {
+	if analysis.AppConfig == nil {
+		return resources
+	}
+	return overrideResources(resources, analysis.AppConfig.Resources)
+}
+
+// overrideResources returns resources with the values set in res
+func overrideResources(resources config.ResourceSpec, res *types.ResourceOverrides) config.ResourceSpec {
+	if res == nil {
+		return resources
+	}
+	if res.RequestsCPU != "" {
+		resources.Requests.CPU = res.RequestsCPU
+	}
+	if res.RequestsMemory != "" {
+		resources.Requests.Memory = res.RequestsMemory
+	}
+	if res.LimitsCPU != "" {
+		resources.Limits.CPU = res.LimitsCPU
+	}
+	if res.LimitsMemory != "" {
+		resources.Limits.Memory = res.LimitsMemory
+	}
+	return resources
+}
+
+// resourceRequirements converts a resource spec to a container's resources
+func resourceRequirements(r config.ResourceSpec) ResourceRequirements {
+	return ResourceRequirements{
+		Requests: map[string]string{
+			"cpu":    r.Requests.CPU,
+			"memory": r.Requests.Memory,
+		},
+		Limits: map[string]string{
+			"cpu":    r.Limits.CPU,
+			"memory": r.Limits.Memory,
+		},
+	}
+}
+
+// securityContexts returns the restricted pod and container security
+// contexts every generated workload runs with, using the app's seccomp
+// profile
+func securityContexts(analysis *types.AppAnalysis, cfg *config.Config) (*PodSecurityContext, *ContainerSecurityContext) {
+	trueVal := true
+	falseVal := false
+	seccomp := resolveSecurity(analysis, cfg).Seccomp
+	pod := &PodSecurityContext{
+		RunAsNonRoot:   &trueVal,
+		SeccompProfile: &seccomp,
+	}
+	container := &ContainerSecurityContext{
+		AllowPrivilegeEscalation: &falseVal,
+		ReadOnlyRootFilesystem:   &trueVal,
+		Capabilities: &Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+	return pod, container
+}
+
+// appImage returns the image the app's workloads run
+func appImage(analysis *types.AppAnalysis, cfg *config.Config) string {
+	if cfg.CI.Registry == "" {
+		return analysis.Name + ":latest"
+	}
+	return fmt.Sprintf("%s/%s:latest", cfg.CI.Registry, analysis.Name)
+}
+
+// ReplaceImage returns files with the app's image in the manifests replaced
+// by image, e.g. one built locally for a preview
+func ReplaceImage(files []GeneratedFile, analysis *types.AppAnalysis, cfg *config.Config, image string) []GeneratedFile {
+	from := "image: " + appImage(analysis, cfg) + "\n"
+	out := make([]GeneratedFile, len(files))
+	for i, f := range files {
+		out[i] = f
+		if IsManifest(f) {
+			out[i].Content = strings.ReplaceAll(f.Content, from, "image: "+image+"\n")
+		}
+	}
+	return out
+}
+
 // buildLabels creates standard Kubernetes labels
 func buildLabels(name string, cfg *config.Config) map[string]string {
 	labels := map[string]string{
@@ -353,7 +391,7 @@ func buildLabels(name string, cfg *config.Config) map[string]string {
 	}
 
 	// Add custom labels from config
-	for k, v := range cfg.Labels.Custom {
+	for k, v := range trimmedMetadata(cfg.Labels.Custom) {
 		labels[k] = v
 	}
 
@@ -368,23 +406,23 @@ func buildLabelsWithAppConfig(analysis *types.AppAnalysis, cfg *config.Config) m
 	}
 
 	// Add team label if available from app config
-	if analysis.Team != "" {
-		labels["app.kubernetes.io/team"] = analysis.Team
+	if team := labelValue(analysis.Team); team != "" {
+		labels["app.kubernetes.io/team"] = team
 	}
 
 	// Add environment label if available
-	if analysis.Environment != "" {
-		labels["app.kubernetes.io/environment"] = analysis.Environment
+	if env := labelValue(analysis.Environment); env != "" {
+		labels["app.kubernetes.io/environment"] = env
 	}
 
 	// Add custom labels from org config
-	for k, v := range cfg.Labels.Custom {
+	for k, v := range trimmedMetadata(cfg.Labels.Custom) {
 		labels[k] = v
 	}
 
 	// Add custom labels from app config (these override org config)
 	if analysis.AppConfig != nil {
-		for k, v := range analysis.AppConfig.Labels {
+		for k, v := range trimmedMetadata(analysis.AppConfig.Labels) {
 			labels[k] = v
 		}
 	}
@@ -397,13 +435,13 @@ func buildAnnotationsWithAppConfig(analysis *types.AppAnalysis, cfg *config.Conf
 	annotations := make(map[string]string)
 
 	// Add custom annotations from org config
-	for k, v := range cfg.Annotations.Custom {
+	for k, v := range trimmedMetadata(cfg.Annotations.Custom) {
 		annotations[k] = v
 	}
 
 	// Add custom annotations from app config (these override org config)
 	if analysis.AppConfig != nil {
-		for k, v := range analysis.AppConfig.Annotations {
+		for k, v := range trimmedMetadata(analysis.AppConfig.Annotations) {
 			annotations[k] = v
 		}
 	}
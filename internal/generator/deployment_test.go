@@ -0,0 +1,29 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestReplaceImage(t *testing.T) {
+	analysis := &types.AppAnalysis{Name: "orders"}
+	cfg := config.Default()
+	deployment, err := GenerateDeployment(analysis, "default", cfg.Resources.Defaults, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := ReplaceImage([]GeneratedFile{
+		{Path: "deployment.yaml", Content: deployment},
+		{Path: "README.md", Content: "image: orders:latest\n"},
+	}, analysis, cfg, "orders:dorgu-preview")
+
+	if !strings.Contains(files[0].Content, "image: orders:dorgu-preview\n") || strings.Contains(files[0].Content, "orders:latest") {
+		t.Errorf("deployment image not replaced:\n%s", files[0].Content)
+	}
+	if files[1].Content != "image: orders:latest\n" {
+		t.Errorf("non-manifest changed: %q", files[1].Content)
+	}
+}
@@ -0,0 +1,343 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// Development environment files, relative to the output directory
+const (
+	DevcontainerFile = "../.devcontainer/devcontainer.json"
+	DevfileFile      = "../devfile.yaml"
+	SkaffoldFile     = "../skaffold.yaml"
+	TiltFile         = "../Tiltfile"
+)
+
+// devTooling is the development setup of a language: the devcontainer and
+// devfile images with its toolchain, the VS Code extension for it, and the
+// commands that install dependencies and run the app from source
+type devTooling struct {
+	devcontainerImage string
+	devfileImage      string
+	extension         string
+	install           string
+	run               string
+}
+
+// devToolings are keyed by analysis.Language
+var devToolings = map[string]devTooling{
+	"go": {
+		devcontainerImage: "mcr.microsoft.com/devcontainers/go:1",
+		devfileImage:      "registry.access.redhat.com/ubi9/go-toolset:latest",
+		extension:         "golang.go",
+		install:           "go mod download",
+		run:               "go run .",
+	},
+	"javascript": {
+		devcontainerImage: "mcr.microsoft.com/devcontainers/typescript-node:1",
+		devfileImage:      "registry.access.redhat.com/ubi9/nodejs-20:latest",
+		extension:         "dbaeumer.vscode-eslint",
+		install:           "npm install",
+		run:               "npm start",
+	},
+	"python": {
+		devcontainerImage: "mcr.microsoft.com/devcontainers/python:3",
+		devfileImage:      "registry.access.redhat.com/ubi9/python-311:latest",
+		extension:         "ms-python.python",
+		install:           "pip install -r requirements.txt",
+	},
+	"java": {
+		devcontainerImage: "mcr.microsoft.com/devcontainers/java:17",
+		devfileImage:      "registry.access.redhat.com/ubi9/openjdk-17:latest",
+		extension:         "vscjava.vscode-java-pack",
+	},
+	"ruby": {
+		devcontainerImage: "mcr.microsoft.com/devcontainers/ruby:3",
+		devfileImage:      "registry.access.redhat.com/ubi9/ruby-31:latest",
+		extension:         "Shopify.ruby-lsp",
+		install:           "bundle install",
+	},
+	"rust": {
+		devcontainerImage: "mcr.microsoft.com/devcontainers/rust:1",
+		devfileImage:      "docker.io/library/rust:1",
+		extension:         "rust-lang.rust-analyzer",
+		install:           "cargo fetch",
+		run:               "cargo run",
+	},
+}
+
+// defaultDevTooling is used for languages without a known toolchain
+var defaultDevTooling = devTooling{
+	devcontainerImage: "mcr.microsoft.com/devcontainers/base:ubuntu",
+	devfileImage:      "registry.access.redhat.com/ubi9/ubi:latest",
+}
+
+// ValidateDevFiles checks dev.files
+func ValidateDevFiles(files []string) error {
+	for _, f := range files {
+		switch f {
+		case config.DevFileDevcontainer, config.DevFileDevfile, config.DevFileSkaffold, config.DevFileTilt:
+		default:
+			return fmt.Errorf("%q is not one of devcontainer, devfile, skaffold, tilt", f)
+		}
+	}
+	return nil
+}
+
+// GenerateDevEnvironment returns the development environment files listed
+// in dev.files, set up for the app's language with its ports forwarded.
+// Skaffold and Tilt deploy the kustomization, so they are left out when the
+// manifests are declared in a program.
+func GenerateDevEnvironment(analysis *types.AppAnalysis, opts Options) ([]GeneratedFile, error) {
+	if err := ValidateDevFiles(opts.Config.Dev.Files); err != nil {
+		return nil, fmt.Errorf("dev.files: %w", err)
+	}
+	var files []GeneratedFile
+	for _, f := range opts.Config.Dev.Files {
+		switch f {
+		case config.DevFileDevcontainer:
+			content, err := devcontainerJSON(analysis)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, GeneratedFile{Path: DevcontainerFile, Content: content})
+		case config.DevFileDevfile:
+			content, err := devfileYAML(analysis, opts)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, GeneratedFile{Path: DevfileFile, Content: content})
+		case config.DevFileSkaffold, config.DevFileTilt:
+			if !opts.gitOps() {
+				slog.Warn("dev.files: no dev loop config for a program --format", "file", f, "format", opts.Format)
+				continue
+			}
+			if f == config.DevFileTilt {
+				files = append(files, GeneratedFile{Path: TiltFile, Content: tiltfile(analysis, opts)})
+				continue
+			}
+			content, err := skaffoldYAML(analysis, opts)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, GeneratedFile{Path: SkaffoldFile, Content: content})
+		}
+	}
+	return files, nil
+}
+
+// imageRepository returns the app's image without its tag, which Skaffold
+// and Tilt match in the manifests and replace with the image they build
+func imageRepository(analysis *types.AppAnalysis, cfg *config.Config) string {
+	image := appImage(analysis, cfg)
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// devPorts returns the ports of the app's Service to forward locally
+func devPorts(analysis *types.AppAnalysis, cfg *config.Config) []int {
+	var ports []int
+	for _, p := range podPorts(analysis, cfg) {
+		ports = append(ports, p.Port)
+	}
+	return ports
+}
+
+// skaffoldYAML renders a skaffold.yaml that builds the Dockerfile without
+// pushing, deploys the kustomization, and forwards the Service's ports
+func skaffoldYAML(analysis *types.AppAnalysis, opts Options) (string, error) {
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	var forwards []map[string]interface{}
+	for _, port := range devPorts(analysis, opts.Config) {
+		forwards = append(forwards, map[string]interface{}{
+			"resourceType": "service",
+			"resourceName": analysis.Name,
+			"namespace":    namespace,
+			"port":         port,
+			"localPort":    port,
+		})
+	}
+	skaffold := map[string]interface{}{
+		"apiVersion": "skaffold/v4beta11",
+		"kind":       "Config",
+		"metadata":   map[string]interface{}{"name": analysis.Name},
+		"build": map[string]interface{}{
+			"artifacts": []map[string]interface{}{{
+				"image":  imageRepository(analysis, opts.Config),
+				"docker": map[string]interface{}{"dockerfile": "Dockerfile"},
+			}},
+			"local": map[string]interface{}{"push": false},
+		},
+		"manifests": map[string]interface{}{
+			"kustomize": map[string]interface{}{"paths": []string{opts.manifestsDir()}},
+		},
+		"deploy": map[string]interface{}{"kubectl": map[string]interface{}{}},
+	}
+	if len(forwards) > 0 {
+		skaffold["portForward"] = forwards
+	}
+	return toYAML(skaffold)
+}
+
+// tiltfile renders a Tiltfile that builds the Dockerfile, deploys the
+// kustomization, and forwards the Service's ports from the Deployment
+func tiltfile(analysis *types.AppAnalysis, opts Options) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "docker_build(%s, \".\", dockerfile=\"Dockerfile\")\n", strconv.Quote(imageRepository(analysis, opts.Config)))
+	fmt.Fprintf(&b, "k8s_yaml(kustomize(%s))\n", strconv.Quote(opts.manifestsDir()))
+	var forwards []string
+	for _, port := range devPorts(analysis, opts.Config) {
+		forwards = append(forwards, strconv.Quote(fmt.Sprintf("%d:%d", port, port)))
+	}
+	if len(forwards) > 0 {
+		fmt.Fprintf(&b, "k8s_resource(%s, port_forwards=[%s])\n", strconv.Quote(analysis.Name), strings.Join(forwards, ", "))
+	}
+	return b.String()
+}
+
+// appDevTooling returns the app's language tooling, with the run command from
+// the Dockerfile when the language has no standard one
+func appDevTooling(analysis *types.AppAnalysis) devTooling {
+	t, ok := devToolings[analysis.Language]
+	if !ok {
+		t = defaultDevTooling
+	}
+	if d := analysis.Dockerfile; t.run == "" && d != nil && len(d.Entrypoint)+len(d.Cmd) > 0 {
+		t.run = strings.Join(append(append([]string(nil), d.Entrypoint...), d.Cmd...), " ")
+	}
+	return t
+}
+
+// devEnv returns the app's variables with a literal value; secrets are left
+// for the developer to provide
+func devEnv(analysis *types.AppAnalysis) map[string]string {
+	env := map[string]string{}
+	for _, e := range analysis.EnvVars {
+		if !e.Secret && e.Value != "" {
+			env[e.Name] = e.Value
+		}
+	}
+	return env
+}
+
+// devcontainerJSON renders a devcontainer.json for VS Code and Codespaces
+func devcontainerJSON(analysis *types.AppAnalysis) (string, error) {
+	t := appDevTooling(analysis)
+	dc := map[string]interface{}{
+		"name":  analysis.Name,
+		"image": t.devcontainerImage,
+	}
+	var ports []int
+	for _, p := range analysis.Ports {
+		ports = append(ports, p.Port)
+	}
+	if len(ports) > 0 {
+		dc["forwardPorts"] = ports
+	}
+	if env := devEnv(analysis); len(env) > 0 {
+		dc["containerEnv"] = env
+	}
+	if t.install != "" {
+		dc["postCreateCommand"] = t.install
+	}
+	if t.extension != "" {
+		dc["customizations"] = map[string]interface{}{
+			"vscode": map[string]interface{}{"extensions": []string{t.extension}},
+		}
+	}
+	data, err := json.MarshalIndent(dc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// devfileYAML renders a devfile 2.2 with a development container running
+// the source, and an image component building the app's Dockerfile
+func devfileYAML(analysis *types.AppAnalysis, opts Options) (string, error) {
+	t := appDevTooling(analysis)
+	metadata := map[string]interface{}{"name": analysis.Name}
+	if analysis.Language != "" && analysis.Language != "unknown" {
+		metadata["language"] = analysis.Language
+	}
+	if analysis.Framework != "" {
+		metadata["projectType"] = analysis.Framework
+	}
+
+	resources := appResources(analysis, opts.Config.GetResourcesForProfile(analysis.ResourceProfile))
+	container := map[string]interface{}{
+		"image":        t.devfileImage,
+		"mountSources": true,
+		"command":      []string{"tail", "-f", "/dev/null"},
+		"memoryLimit":  resources.Limits.Memory,
+	}
+	var endpoints []map[string]interface{}
+	for _, p := range appPorts(analysis, opts.Config) {
+		endpoints = append(endpoints, map[string]interface{}{"name": p.Name, "targetPort": p.Port})
+	}
+	if len(endpoints) > 0 {
+		container["endpoints"] = endpoints
+	}
+	if env := devEnv(analysis); len(env) > 0 {
+		var vars []map[string]string
+		for _, k := range sortedMapKeys(env) {
+			vars = append(vars, map[string]string{"name": k, "value": env[k]})
+		}
+		container["env"] = vars
+	}
+
+	components := []map[string]interface{}{{"name": "dev", "container": container}}
+	var commands []map[string]interface{}
+	exec := func(id, kind, line string) {
+		commands = append(commands, map[string]interface{}{
+			"id": id,
+			"exec": map[string]interface{}{
+				"component":   "dev",
+				"commandLine": line,
+				"workingDir":  "${PROJECT_SOURCE}",
+				"group":       map[string]interface{}{"kind": kind, "isDefault": true},
+			},
+		})
+	}
+	if t.install != "" {
+		exec("install", "build", t.install)
+	}
+	if t.run != "" {
+		exec("run", "run", t.run)
+	}
+	if analysis.Dockerfile != nil {
+		components = append(components, map[string]interface{}{
+			"name": "image",
+			"image": map[string]interface{}{
+				"imageName":  appImage(analysis, opts.Config),
+				"autoBuild":  false,
+				"dockerfile": map[string]interface{}{"uri": "Dockerfile", "buildContext": "."},
+			},
+		})
+		commands = append(commands, map[string]interface{}{
+			"id":    "build-image",
+			"apply": map[string]interface{}{"component": "image"},
+		})
+	}
+
+	devfile := map[string]interface{}{
+		"schemaVersion": "2.2.0",
+		"metadata":      metadata,
+		"components":    components,
+	}
+	if len(commands) > 0 {
+		devfile["commands"] = commands
+	}
+	return toYAML(devfile)
+}
@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestGenerateDevEnvironment(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:       "orders",
+		Language:   "go",
+		Framework:  "gin",
+		Ports:      []types.Port{{Port: 8080}},
+		EnvVars:    []types.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "API_KEY", Value: "x", Secret: true}},
+		Dockerfile: &types.DockerfileAnalysis{Cmd: []string{"/app"}},
+	}
+	cfg := config.Default()
+	cfg.Dev.Files = []string{config.DevFileDevcontainer, config.DevFileDevfile}
+	files, err := GenerateDevEnvironment(analysis, Options{Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Path != DevcontainerFile || files[1].Path != DevfileFile {
+		t.Fatalf("files = %v", files)
+	}
+
+	var dc struct {
+		Image        string            `json:"image"`
+		ForwardPorts []int             `json:"forwardPorts"`
+		ContainerEnv map[string]string `json:"containerEnv"`
+		PostCreate   string            `json:"postCreateCommand"`
+	}
+	if err := json.Unmarshal([]byte(files[0].Content), &dc); err != nil {
+		t.Fatalf("devcontainer.json is not valid JSON: %v", err)
+	}
+	if dc.Image != "mcr.microsoft.com/devcontainers/go:1" || len(dc.ForwardPorts) != 1 || dc.ForwardPorts[0] != 8080 || dc.PostCreate != "go mod download" {
+		t.Errorf("devcontainer = %+v", dc)
+	}
+	if _, ok := dc.ContainerEnv["API_KEY"]; ok || dc.ContainerEnv["LOG_LEVEL"] != "debug" {
+		t.Errorf("containerEnv = %v, want LOG_LEVEL only", dc.ContainerEnv)
+	}
+
+	for _, want := range []string{
+		"schemaVersion: 2.2.0",
+		"projectType: gin",
+		"targetPort: 8080",
+		"commandLine: go run .",
+		"imageName: orders:latest",
+		"uri: Dockerfile",
+	} {
+		if !strings.Contains(files[1].Content, want) {
+			t.Errorf("devfile.yaml missing %q:\n%s", want, files[1].Content)
+		}
+	}
+}
+
+func TestAppDevToolingRunsDockerfileCommand(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Language:   "python",
+		Dockerfile: &types.DockerfileAnalysis{Entrypoint: []string{"gunicorn"}, Cmd: []string{"app:app"}},
+	}
+	if got := appDevTooling(analysis).run; got != "gunicorn app:app" {
+		t.Errorf("run = %q, want the Dockerfile's command", got)
+	}
+}
+
+func TestValidateDevFiles(t *testing.T) {
+	if err := ValidateDevFiles([]string{"devcontainer", "devfile"}); err != nil {
+		t.Error(err)
+	}
+	if err := ValidateDevFiles([]string{"vagrant"}); err == nil {
+		t.Error("vagrant accepted")
+	}
+}
+
+func TestGenerateDevLoop(t *testing.T) {
+	analysis := &types.AppAnalysis{Name: "orders", Ports: []types.Port{{Port: 8080}}}
+	cfg := config.Default()
+	cfg.CI.Registry = "registry.io:5000/shop"
+	cfg.Dev.Files = []string{config.DevFileSkaffold, config.DevFileTilt}
+	files, err := GenerateDevEnvironment(analysis, Options{Config: cfg, Namespace: "dev", ManifestsDir: "deploy/k8s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Path != SkaffoldFile || files[1].Path != TiltFile {
+		t.Fatalf("files = %v", files)
+	}
+	for _, want := range []string{
+		"apiVersion: skaffold/v4beta11",
+		"image: registry.io:5000/shop/orders\n",
+		"- deploy/k8s",
+		"resourceName: orders",
+		"namespace: dev",
+		"localPort: 8080",
+	} {
+		if !strings.Contains(files[0].Content, want) {
+			t.Errorf("skaffold.yaml missing %q:\n%s", want, files[0].Content)
+		}
+	}
+	want := `docker_build("registry.io:5000/shop/orders", ".", dockerfile="Dockerfile")
+k8s_yaml(kustomize("deploy/k8s"))
+k8s_resource("orders", port_forwards=["8080:8080"])
+`
+	if files[1].Content != want {
+		t.Errorf("Tiltfile =\n%s\nwant\n%s", files[1].Content, want)
+	}
+
+	files, err = GenerateDevEnvironment(analysis, Options{Config: cfg, Format: FormatTerraform})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("dev loop files with --format terraform: %v", files)
+	}
+}
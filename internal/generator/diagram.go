@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// datastoreTypes are dependency types drawn as a database
+var datastoreTypes = map[string]bool{"database": true, "cache": true, "storage": true}
+
+// diagramDependency is a dependency drawn in the architecture diagram
+type diagramDependency struct {
+	Name     string
+	Type     string
+	Required bool
+}
+
+// diagramDependencies returns the app's dependencies: those in the app
+// config, or else the docker-compose services the app's service depends on
+func diagramDependencies(analysis *types.AppAnalysis) []diagramDependency {
+	var deps []diagramDependency
+	if analysis.AppConfig != nil && len(analysis.AppConfig.Dependencies) > 0 {
+		for _, d := range analysis.AppConfig.Dependencies {
+			deps = append(deps, diagramDependency{Name: d.Name, Type: d.Type, Required: d.Required})
+		}
+		return deps
+	}
+	if analysis.Compose == nil {
+		return nil
+	}
+	var app *types.ComposeService
+	for i, svc := range analysis.Compose.Services {
+		if svc.Name == analysis.Name || (app == nil && svc.Build != "") {
+			app = &analysis.Compose.Services[i]
+		}
+	}
+	if app == nil {
+		return nil
+	}
+	for _, name := range app.DependsOn {
+		deps = append(deps, diagramDependency{Name: name, Required: true})
+	}
+	return deps
+}
+
+// ArchitectureDiagram returns a Mermaid flowchart of the app: the ingress
+// hosts that route to it, its ports, and its dependencies. Optional
+// dependencies are drawn with dotted edges.
+func ArchitectureDiagram(analysis *types.AppAnalysis, cfg *config.Config) string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	label := analysis.Name
+	if analysis.Type != "" {
+		label += " (" + analysis.Type + ")"
+	}
+	var ports []string
+	for _, p := range analysis.Ports {
+		port := fmt.Sprintf(":%d", p.Port)
+		if p.Purpose != "" {
+			port += " " + p.Purpose
+		}
+		ports = append(ports, port)
+	}
+	if len(ports) > 0 {
+		label += "<br/>" + strings.Join(ports, "<br/>")
+	}
+	fmt.Fprintf(&b, "    app[\"%s\"]\n", mermaidText(label))
+
+	if hasIngress(analysis) {
+		b.WriteString("    client((Clients))\n    ingress[Ingress]\n")
+		for _, h := range ingressHosts(analysis, cfg) {
+			fmt.Fprintf(&b, "    client -- \"%s\" --> ingress\n", mermaidText(h.Host))
+		}
+		b.WriteString("    ingress --> app\n")
+	}
+
+	for i, d := range diagramDependencies(analysis) {
+		label := d.Name
+		if d.Type != "" {
+			label += " (" + d.Type + ")"
+		}
+		shape := "[\"%s\"]"
+		if datastoreTypes[d.Type] {
+			shape = "[(\"%s\")]"
+		}
+		edge := "-->"
+		if !d.Required {
+			edge = "-.->"
+		}
+		fmt.Fprintf(&b, "    app %s dep%d"+shape+"\n", edge, i, mermaidText(label))
+	}
+	return b.String()
+}
+
+// mermaidText escapes text for a quoted Mermaid label
+func mermaidText(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+// EmbedArchitectureDiagram adds the diagram to the end of the Overview
+// section of a PERSONA.md document. Documents without that section, or that
+// already have a diagram, are returned as is.
+func EmbedArchitectureDiagram(markdown, diagram string) string {
+	const heading = "## Overview\n"
+	i := strings.Index(markdown, heading)
+	if i < 0 || diagram == "" || strings.Contains(markdown, "```mermaid") {
+		return markdown
+	}
+	at := len(markdown)
+	if next := strings.Index(markdown[i+len(heading):], "\n## "); next >= 0 {
+		at = i + len(heading) + next + 1
+	}
+	section := strings.TrimRight(markdown[:at], "\n")
+	block := "\n\n### Architecture\n\n```mermaid\n" + diagram + "```\n"
+	if at < len(markdown) {
+		block += "\n"
+	}
+	return section + block + markdown[at:]
+}
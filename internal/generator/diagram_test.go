@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestArchitectureDiagram(t *testing.T) {
+	cfg := config.Default()
+	cfg.Ingress.DomainSuffix = ".example.com"
+
+	tests := []struct {
+		name     string
+		analysis *types.AppAnalysis
+		want     []string
+		notWant  []string
+	}{
+		{
+			name: "app config dependencies and ingress",
+			analysis: &types.AppAnalysis{
+				Name:  "orders",
+				Type:  "api",
+				Ports: []types.Port{{Port: 8080, Purpose: "HTTP API"}},
+				AppConfig: &types.AppConfigContext{Dependencies: []types.DependencyContext{
+					{Name: "postgres", Type: "database", Required: true},
+					{Name: "billing", Type: "service"},
+				}},
+			},
+			want: []string{
+				`app["orders (api)<br/>:8080 HTTP API"]`,
+				`client -- "orders.example.com" --> ingress`,
+				"ingress --> app",
+				`app --> dep0[("postgres (database)")]`,
+				`app -.-> dep1["billing (service)"]`,
+			},
+		},
+		{
+			name: "compose depends_on",
+			analysis: &types.AppAnalysis{
+				Name: "worker",
+				Compose: &types.ComposeAnalysis{Services: []types.ComposeService{
+					{Name: "redis", Image: "redis"},
+					{Name: "worker", Build: ".", DependsOn: []string{"redis"}},
+				}},
+			},
+			want:    []string{`app["worker"]`, `app --> dep0["redis"]`},
+			notWant: []string{"ingress"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ArchitectureDiagram(tt.analysis, cfg)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("diagram missing %q:\n%s", w, got)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("diagram has %q:\n%s", w, got)
+				}
+			}
+		})
+	}
+}
+
+func TestEmbedArchitectureDiagram(t *testing.T) {
+	doc := "# orders\n\n## Overview\n\nTakes orders.\n\n## Technical Stack\n"
+	got := EmbedArchitectureDiagram(doc, "flowchart LR\n    app[orders]\n")
+	want := "# orders\n\n## Overview\n\nTakes orders.\n\n### Architecture\n\n```mermaid\nflowchart LR\n    app[orders]\n```\n\n## Technical Stack\n"
+	if got != want {
+		t.Errorf("EmbedArchitectureDiagram() =\n%s\nwant\n%s", got, want)
+	}
+	if again := EmbedArchitectureDiagram(got, "flowchart LR\n"); again != got {
+		t.Errorf("diagram embedded twice:\n%s", again)
+	}
+	if EmbedArchitectureDiagram("# no section\n", "flowchart LR\n") != "# no section\n" {
+		t.Error("document without an Overview section changed")
+	}
+}
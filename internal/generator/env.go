@@ -0,0 +1,50 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// ObjectFieldSelector selects a field of the pod
+type ObjectFieldSelector struct {
+	FieldPath string `json:"fieldPath"`
+}
+
+// downwardEnv are the env.standard.downward_api variables and their fields
+var downwardEnv = []struct{ name, field string }{
+	{"POD_NAME", "metadata.name"},
+	{"POD_NAMESPACE", "metadata.namespace"},
+	{"NODE_NAME", "spec.nodeName"},
+}
+
+// standardEnv returns the org's env.standard variables for the app, skipping
+// names in defined
+func standardEnv(analysis *types.AppAnalysis, namespace string, cfg *config.Config, defined map[string]bool) []EnvVar {
+	std := cfg.Env.Standard
+	var env []EnvVar
+	add := func(ev EnvVar) {
+		if !defined[ev.Name] {
+			defined[ev.Name] = true
+			env = append(env, ev)
+		}
+	}
+	if std.DownwardAPI {
+		for _, d := range downwardEnv {
+			add(EnvVar{Name: d.name, ValueFrom: &EnvVarSource{FieldRef: &ObjectFieldSelector{FieldPath: d.field}}})
+		}
+	}
+	expand := strings.NewReplacer(
+		"{app}", analysis.Name,
+		"{namespace}", namespace,
+		"{env}", analysis.Environment,
+		"{team}", analysis.Team,
+	)
+	for _, v := range std.Vars {
+		if v.Name != "" {
+			add(EnvVar{Name: v.Name, Value: expand.Replace(v.Value)})
+		}
+	}
+	return env
+}
@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestStandardEnv(t *testing.T) {
+	cfg := config.Default()
+	cfg.Env.Standard = config.StandardEnvConfig{
+		DownwardAPI: true,
+		Vars: []config.StandardEnvVar{
+			{Name: "OTEL_SERVICE_NAME", Value: "{app}"},
+			{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: "deployment.environment={env},k8s.namespace.name={namespace}"},
+			{Name: "LOG_LEVEL", Value: "info"},
+		},
+	}
+	analysis := &types.AppAnalysis{Name: "orders", Environment: "staging"}
+	got := standardEnv(analysis, "shop", cfg, map[string]bool{"LOG_LEVEL": true, "NODE_NAME": true})
+	want := []EnvVar{
+		{Name: "POD_NAME", ValueFrom: &EnvVarSource{FieldRef: &ObjectFieldSelector{FieldPath: "metadata.name"}}},
+		{Name: "POD_NAMESPACE", ValueFrom: &EnvVarSource{FieldRef: &ObjectFieldSelector{FieldPath: "metadata.namespace"}}},
+		{Name: "OTEL_SERVICE_NAME", Value: "orders"},
+		{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: "deployment.environment=staging,k8s.namespace.name=shop"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("standardEnv() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if env := standardEnv(analysis, "shop", config.Default(), map[string]bool{}); env != nil {
+		t.Errorf("standardEnv() without env.standard = %+v, want nil", env)
+	}
+}
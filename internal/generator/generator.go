@@ -1,10 +1,15 @@
 package generator
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"path/filepath"
 
 	"github.com/dorgu-ai/dorgu/internal/config"
 	"github.com/dorgu-ai/dorgu/internal/llm"
+	"github.com/dorgu-ai/dorgu/internal/owners"
+	"github.com/dorgu-ai/dorgu/internal/progress"
 	"github.com/dorgu-ai/dorgu/internal/types"
 )
 
@@ -15,6 +20,89 @@ type Options struct {
 	SkipCI      bool
 	SkipPersona bool
 	Config      *config.Config
+	// Persona is pre-generated PERSONA.md content (e.g. produced concurrently
+	// with analysis); when set, Generate does not call the LLM again
+	Persona string
+	// NoLLMPersona uses the basic persona template instead of calling the LLM
+	// (set when a concurrent persona request already failed)
+	NoLLMPersona bool
+	// SkipPlugins skips the plugins configured in Config.Plugins
+	SkipPlugins bool
+	// Provenance, when set, is stamped on every file and recorded with the
+	// run's inputs in LockFile
+	Provenance *Provenance
+	// SourceHash is the digest of the app's source files, recorded in
+	// LockFile so an incremental run can skip an unchanged app
+	SourceHash string
+	// SingleFile, when set, joins the Kubernetes manifests into one
+	// multi-document file at this path in the output directory
+	SingleFile string
+	// Format is FormatYAML (default), or FormatTerraform, FormatCDK8s, or
+	// FormatPulumi, which declare the manifests in a program instead of YAML
+	// files and leave out the GitOps files: the kustomization, the ArgoCD
+	// Application, and the CI workflow that commits image tags and its
+	// dependency updates
+	Format string
+	// CIPath and PersonaPath place the CI workflow and PERSONA.md, relative
+	// to the output directory (default DefaultCIPath and DefaultPersonaPath)
+	CIPath      string
+	PersonaPath string
+	// Source is the app's path shown in file headers, e.g. relative to the
+	// repository root
+	Source string
+	// SkipReadme skips the README.md documenting the output directory
+	SkipReadme bool
+	// PolishReadme has the LLM improve the README's wording
+	PolishReadme bool
+	// ManifestsDir is the output directory's path relative to the
+	// repository files written next to it (default "k8s")
+	ManifestsDir string
+	// Owners, when set, is the directory validation checks app.team and
+	// app.owner against
+	Owners *owners.Directory
+	// Cluster, when set, is the target cluster validation checks the
+	// manifests against (generate --against-cluster)
+	Cluster *ClusterState
+	// Suppressions acknowledge validation findings, read from the app's
+	// SuppressionsFile
+	Suppressions []Suppression
+}
+
+// Default destinations of the files written next to, not inside, the output
+// directory
+const (
+	DefaultCIPath      = "../.github/workflows/deploy.yaml"
+	DefaultPersonaPath = "../PERSONA.md"
+)
+
+// gitOps reports whether the manifests are written as YAML for kubectl and
+// ArgoCD rather than declared in a program
+func (o Options) gitOps() bool {
+	return o.Format == "" || o.Format == FormatYAML
+}
+
+// ciPath returns where the CI workflow is written
+func (o Options) ciPath() string {
+	if o.CIPath != "" {
+		return o.CIPath
+	}
+	return DefaultCIPath
+}
+
+// manifestsDir returns the output directory's path from the repository root
+func (o Options) manifestsDir() string {
+	if o.ManifestsDir != "" {
+		return o.ManifestsDir
+	}
+	return "k8s"
+}
+
+// personaPath returns where PERSONA.md is written
+func (o Options) personaPath() string {
+	if o.PersonaPath != "" {
+		return o.PersonaPath
+	}
+	return DefaultPersonaPath
 }
 
 // GeneratedFile represents a generated file
@@ -23,15 +111,35 @@ type GeneratedFile struct {
 	Content string
 }
 
-// Generate generates all manifests for an analyzed application
-func Generate(analysis *types.AppAnalysis, opts Options) ([]GeneratedFile, error) {
+// Generate generates all manifests for an analyzed application. ctx bounds the
+// LLM calls and plugins; generation fails once it is done.
+func Generate(ctx context.Context, analysis *types.AppAnalysis, opts Options) ([]GeneratedFile, error) {
 	var files []GeneratedFile
 
+	normalizeAppName(analysis, opts.Config)
+	if err := checkMetadata(analysis, opts.Config); err != nil {
+		return nil, fmt.Errorf("invalid labels or annotations:\n%w", err)
+	}
+	if err := checkQuantities(analysis, opts.Config); err != nil {
+		return nil, fmt.Errorf("invalid resource quantities:\n%w", err)
+	}
+	if err := checkContainers(analysis, opts.Config); err != nil {
+		return nil, err
+	}
+	if err := checkSecurity(analysis, opts.Config); err != nil {
+		return nil, fmt.Errorf("invalid security settings:\n%w", err)
+	}
+	if err := checkSLO(analysis, opts.Config); err != nil {
+		return nil, fmt.Errorf("invalid SLOs:\n%w", err)
+	}
+
 	// Get resource spec based on profile
 	resources := opts.Config.GetResourcesForProfile(analysis.ResourceProfile)
 
 	// Generate Deployment
+	done := progress.Start(ctx, "generate/deployment.yaml")
 	deployment, err := GenerateDeployment(analysis, opts.Namespace, resources, opts.Config)
+	done()
 	if err != nil {
 		return nil, err
 	}
@@ -40,9 +148,25 @@ func Generate(analysis *types.AppAnalysis, opts Options) ([]GeneratedFile, error
 		Content: deployment,
 	})
 
+	// Generate the nginx.conf ConfigMap (for static sites)
+	if hasStaticSite(analysis) {
+		done = progress.Start(ctx, "generate/"+NginxConfigMapFile)
+		configMap, err := GenerateNginxConfigMap(analysis, opts.Namespace, opts.Config)
+		done()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, GeneratedFile{
+			Path:    NginxConfigMapFile,
+			Content: configMap,
+		})
+	}
+
 	// Generate Service (only if ports are exposed)
-	if len(analysis.Ports) > 0 {
+	if len(podPorts(analysis, opts.Config)) > 0 {
+		done = progress.Start(ctx, "generate/service.yaml")
 		service, err := GenerateService(analysis, opts.Namespace, opts.Config)
+		done()
 		if err != nil {
 			return nil, err
 		}
@@ -51,9 +175,11 @@ func Generate(analysis *types.AppAnalysis, opts Options) ([]GeneratedFile, error
 			Content: service,
 		})
 
-		// Generate Ingress (only for HTTP services)
-		if hasHTTPPort(analysis.Ports) {
+		// Generate Ingress (only for publicly exposed HTTP services)
+		if hasIngress(analysis) {
+			done = progress.Start(ctx, "generate/ingress.yaml")
 			ingress, err := GenerateIngress(analysis, opts.Namespace, opts.Config)
+			done()
 			if err != nil {
 				return nil, err
 			}
@@ -62,11 +188,41 @@ func Generate(analysis *types.AppAnalysis, opts Options) ([]GeneratedFile, error
 				Content: ingress,
 			})
 		}
+
+		// Generate ServiceMonitor (if the org scrapes with the Prometheus Operator)
+		if hasServiceMonitor(analysis, opts.Config) {
+			done = progress.Start(ctx, "generate/servicemonitor.yaml")
+			monitor, err := GenerateServiceMonitor(analysis, opts.Namespace, opts.Config)
+			done()
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, GeneratedFile{
+				Path:    "servicemonitor.yaml",
+				Content: monitor,
+			})
+		}
+	}
+
+	// Generate SecretProviderClass (if secrets come from the CSI driver)
+	if hasSecretProviderClass(analysis, opts.Config) {
+		done = progress.Start(ctx, "generate/secretproviderclass.yaml")
+		spc, err := GenerateSecretProviderClass(analysis, opts.Namespace, opts.Config)
+		done()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, GeneratedFile{
+			Path:    "secretproviderclass.yaml",
+			Content: spc,
+		})
 	}
 
 	// Generate HPA (if scaling config present)
-	if analysis.Scaling != nil {
+	if hasHPA(analysis) {
+		done = progress.Start(ctx, "generate/hpa.yaml")
 		hpa, err := GenerateHPA(analysis, opts.Namespace, opts.Config)
+		done()
 		if err != nil {
 			return nil, err
 		}
@@ -76,9 +232,40 @@ func Generate(analysis *types.AppAnalysis, opts Options) ([]GeneratedFile, error
 		})
 	}
 
+	// Generate Jobs (migrations, one-off and scheduled tasks)
+	done = progress.Start(ctx, "generate/jobs")
+	jobs, err := GenerateJobs(analysis, opts.Namespace, resources, opts.Config)
+	done()
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, jobs...)
+
+	// Generate the post-deploy smoke test (if smoke_test.enabled is set)
+	if opts.Config.SmokeTest.Enabled {
+		done = progress.Start(ctx, "generate/"+SmokeTestFile)
+		smoke, err := GenerateSmokeTest(analysis, opts.Namespace, opts.Config)
+		done()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, smoke...)
+	}
+
+	// Generate SLO manifests (if the app has SLOs and a format is set)
+	done = progress.Start(ctx, "generate/slo")
+	slos, err := GenerateSLO(analysis, opts.Namespace, opts.Config)
+	done()
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, slos...)
+
 	// Generate ArgoCD Application
-	if !opts.SkipArgoCD {
+	if !opts.SkipArgoCD && opts.gitOps() {
+		done = progress.Start(ctx, "generate/argocd/application.yaml")
 		argoApp, err := GenerateArgoCD(analysis, opts.Namespace, opts.Config)
+		done()
 		if err != nil {
 			return nil, err
 		}
@@ -89,34 +276,57 @@ func Generate(analysis *types.AppAnalysis, opts Options) ([]GeneratedFile, error
 	}
 
 	// Generate GitHub Actions workflow
-	if !opts.SkipCI {
+	if !opts.SkipCI && opts.gitOps() {
+		done = progress.Start(ctx, "generate/"+filepath.Base(opts.ciPath()))
 		workflow, err := GenerateGitHubActions(analysis, opts.Config)
+		done()
 		if err != nil {
 			return nil, err
 		}
 		files = append(files, GeneratedFile{
-			Path:    "../.github/workflows/deploy.yaml",
+			Path:    opts.ciPath(),
 			Content: workflow,
 		})
 	}
 
-	// Generate Persona document
-	if !opts.SkipPersona {
-		persona, err := generatePersona(analysis, opts.Config)
+	// Generate Renovate or Dependabot config
+	if !opts.SkipCI && opts.gitOps() {
+		done = progress.Start(ctx, "generate/dependency-updates")
+		updates, err := GenerateDependencyUpdates(opts)
+		done()
 		if err != nil {
-			// Non-fatal: use basic persona if LLM fails
-			persona = generateBasicPersona(analysis)
+			return nil, err
 		}
+		files = append(files, updates...)
+	}
+
+	// Generate devcontainer and devfile (if dev.files lists them)
+	if len(opts.Config.Dev.Files) > 0 {
+		done = progress.Start(ctx, "generate/dev-environment")
+		devFiles, err := GenerateDevEnvironment(analysis, opts)
+		done()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, devFiles...)
+	}
+
+	// Generate Persona document
+	if !opts.SkipPersona {
+		done = progress.Start(ctx, "generate/"+filepath.Base(opts.personaPath()))
 		files = append(files, GeneratedFile{
-			Path:    "../PERSONA.md",
-			Content: persona,
+			Path:    opts.personaPath(),
+			Content: RenderPersonaMarkdown(ctx, analysis, opts),
 		})
+		done()
 
 		// Generate structured Persona YAML (ApplicationPersona CRD format)
+		done = progress.Start(ctx, "generate/persona.yaml")
 		personaYAML, err := GeneratePersonaYAML(analysis, opts.Namespace, opts.Config)
+		done()
 		if err != nil {
 			// Non-fatal: skip persona YAML if generation fails
-			fmt.Printf("Warning: failed to generate persona YAML: %v\n", err)
+			slog.Warn("failed to generate persona YAML", "app", analysis.Name, "err", err)
 		} else {
 			files = append(files, GeneratedFile{
 				Path:    "persona.yaml",
@@ -125,6 +335,65 @@ func Generate(analysis *types.AppAnalysis, opts Options) ([]GeneratedFile, error
 		}
 	}
 
+	if files, err = ApplyTemplateOverrides(analysis, opts, resources, files); err != nil {
+		return nil, err
+	}
+
+	if !opts.SkipPlugins && len(opts.Config.Plugins) > 0 {
+		done = progress.Start(ctx, "generate/plugins")
+		files, err = RunPlugins(ctx, analysis, opts, files)
+		done()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case !opts.gitOps():
+		if files, err = renderProgram(analysis, opts, files); err != nil {
+			return nil, err
+		}
+	case opts.SingleFile != "":
+		files = joinManifests(files, opts.SingleFile)
+	}
+
+	if !opts.SkipReadme {
+		done = progress.Start(ctx, "generate/"+ReadmeFile)
+		readme := GenerateReadme(analysis, opts, files)
+		if opts.PolishReadme {
+			readme = polishReadme(ctx, readme, opts, files)
+		}
+		files = append(files, GeneratedFile{Path: ReadmeFile, Content: readme})
+		done()
+	}
+	// LLM failures fall back to templates, but not when cancelled
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if opts.gitOps() {
+		done = progress.Start(ctx, "generate/kustomization.yaml")
+		kustomization, err := GenerateKustomization(analysis, files, opts.Config)
+		done()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, GeneratedFile{Path: KustomizationFile, Content: kustomization})
+	}
+
+	if opts.Provenance != nil {
+		files = StampFiles(files, *opts.Provenance)
+	}
+	files = AddHeaders(analysis, opts, files)
+	if opts.Provenance != nil {
+		lock, err := NewLock(analysis, opts, *opts.Provenance, files).Render()
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", LockFile, err)
+		}
+		files = append(files, GeneratedFile{Path: LockFile, Content: lock})
+	}
+
+	slog.Debug("generated manifests", "app", analysis.Name, "files", len(files))
 	return files, nil
 }
 
@@ -139,14 +408,46 @@ func hasHTTPPort(ports []types.Port) bool {
 	return len(ports) > 0 // Assume HTTP if any port is exposed
 }
 
+// RenderPersonaMarkdown returns the PERSONA.md content for an analysis, using
+// opts.Persona when pre-generated, otherwise the LLM, falling back to the basic
+// template when the LLM is unavailable or opts.NoLLMPersona is set, with the
+// architecture diagram, SLOs, and cost estimate embedded.
+func RenderPersonaMarkdown(ctx context.Context, analysis *types.AppAnalysis, opts Options) string {
+	persona := EmbedArchitectureDiagram(renderPersonaMarkdown(ctx, analysis, opts), ArchitectureDiagram(analysis, opts.Config))
+	persona = EmbedMigrations(persona, analysis, opts.Config)
+	persona = EmbedSLO(persona, appSLO(analysis))
+	est, err := EstimateCost(analysis, opts.Config)
+	if err != nil {
+		slog.Warn("cost estimate left out of PERSONA.md", "err", err)
+		return persona
+	}
+	return EmbedCostEstimate(persona, est)
+}
+
+func renderPersonaMarkdown(ctx context.Context, analysis *types.AppAnalysis, opts Options) string {
+	if opts.Persona != "" {
+		return opts.Persona
+	}
+	if opts.NoLLMPersona {
+		return generateBasicPersona(analysis)
+	}
+	persona, err := generatePersona(ctx, analysis, opts.Config)
+	if err != nil {
+		// Non-fatal: use basic persona if LLM fails
+		slog.Warn("LLM persona generation failed, using basic template", "provider", opts.Config.LLM.Provider, "err", err)
+		return generateBasicPersona(analysis)
+	}
+	return persona
+}
+
 // generatePersona generates persona using LLM
-func generatePersona(analysis *types.AppAnalysis, cfg *config.Config) (string, error) {
+func generatePersona(ctx context.Context, analysis *types.AppAnalysis, cfg *config.Config) (string, error) {
 	client, err := llm.NewClient(cfg.LLM.Provider)
 	if err != nil {
 		return "", err
 	}
 
-	return client.GeneratePersona(analysis)
+	return client.GeneratePersona(ctx, analysis)
 }
 
 // generateBasicPersona generates a basic persona without LLM
@@ -249,7 +550,7 @@ func generateBasicPersona(analysis *types.AppAnalysis) string {
 
 ## Health & Monitoring
 
-` + formatHealthCheck(analysis.HealthCheck) + `
+` + formatHealthCheck(analysis) + `
 
 ## Ownership
 
@@ -259,6 +560,8 @@ func generateBasicPersona(analysis *types.AppAnalysis) string {
 
 ## Operational Notes
 
+` + PersonaKeepMarker + `
+
 ` + operationsNotes + `
 `
 }
@@ -310,9 +613,18 @@ func formatScalingDetails(analysis *types.AppAnalysis) string {
 	return result
 }
 
-func formatHealthCheck(hc *types.HealthCheck) string {
-	if hc == nil {
+func formatHealthCheck(analysis *types.AppAnalysis) string {
+	liveness, readiness := resolveProbes(analysis)
+	switch {
+	case liveness == nil && readiness == nil:
 		return "No health check configured."
+	case liveness == nil || readiness == nil || liveness.Path == readiness.Path:
+		hc := liveness
+		if hc == nil {
+			hc = readiness
+		}
+		return "- **Health endpoint:** " + hc.Path + "\n"
+	default:
+		return "- **Liveness endpoint:** " + liveness.Path + "\n- **Readiness endpoint:** " + readiness.Path + "\n"
 	}
-	return "- **Health endpoint:** " + hc.Path + "\n"
 }
@@ -2,12 +2,36 @@ package generator
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/dorgu-ai/dorgu/internal/config"
 	"github.com/dorgu-ai/dorgu/internal/types"
 )
 
-// GenerateGitHubActions generates a GitHub Actions workflow
+// reusableWorkflowRef matches owner/repo/.github/workflows/file.yml@ref
+var reusableWorkflowRef = regexp.MustCompile(`^[A-Za-z0-9-]+/[A-Za-z0-9._-]+/\.github/workflows/[^/@]+\.ya?ml@\S+$`)
+
+// githubLogin matches GitHub user and organization names
+var githubLogin = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?$`)
+
+// ReusableWorkflow returns the reusable workflow ci.github.mode reusable
+// calls: ci.github.workflow, or deploy.yml in the org's .github repository
+func ReusableWorkflow(cfg *config.Config) (string, error) {
+	if w := cfg.CI.GitHub.Workflow; w != "" {
+		if !reusableWorkflowRef.MatchString(w) {
+			return "", fmt.Errorf("ci.github.workflow %q is not owner/repo/.github/workflows/<file>.yml@<ref>", w)
+		}
+		return w, nil
+	}
+	if !githubLogin.MatchString(cfg.Org.Name) {
+		return "", fmt.Errorf("ci.github.workflow is required when ci.github.mode is reusable and org.name is not a GitHub organization")
+	}
+	return cfg.Org.Name + "/.github/.github/workflows/deploy.yml@main", nil
+}
+
+// GenerateGitHubActions generates a GitHub Actions workflow: the full build
+// and deploy pipeline, or with ci.github.mode reusable a thin caller of the
+// org's shared workflow
 func GenerateGitHubActions(analysis *types.AppAnalysis, cfg *config.Config) (string, error) {
 	registry := cfg.CI.Registry
 	if registry == "" {
@@ -16,6 +40,14 @@ func GenerateGitHubActions(analysis *types.AppAnalysis, cfg *config.Config) (str
 
 	imageName := fmt.Sprintf("%s/%s", registry, analysis.Name)
 
+	switch cfg.CI.GitHub.Mode {
+	case "", config.CIModeFull:
+	case config.CIModeReusable:
+		return generateReusableCaller(analysis, cfg, imageName)
+	default:
+		return "", fmt.Errorf("ci.github.mode %q is not one of full, reusable", cfg.CI.GitHub.Mode)
+	}
+
 	workflow := fmt.Sprintf(`name: Build and Deploy
 
 on:
@@ -100,3 +132,36 @@ jobs:
 
 	return workflow, nil
 }
+
+// generateReusableCaller generates a workflow that hands the build and deploy
+// to the org's reusable workflow
+func generateReusableCaller(analysis *types.AppAnalysis, cfg *config.Config, imageName string) (string, error) {
+	workflow, err := ReusableWorkflow(cfg)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`name: Build and Deploy
+
+on:
+  push:
+    branches:
+      - main
+      - master
+  pull_request:
+    branches:
+      - main
+      - master
+
+jobs:
+  deploy:
+    uses: %s
+    permissions:
+      contents: write
+      packages: write
+    with:
+      app-name: %s
+      image-name: %s
+      path: k8s
+    secrets: inherit
+`, workflow, analysis.Name, imageName), nil
+}
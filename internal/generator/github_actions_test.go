@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestGenerateGitHubActionsReusable(t *testing.T) {
+	analysis := &types.AppAnalysis{Name: "orders"}
+	cfg := config.Default()
+	cfg.Org.Name = "acme"
+	cfg.CI.Registry = "ghcr.io/acme"
+	cfg.CI.GitHub.Mode = config.CIModeReusable
+
+	workflow, err := GenerateGitHubActions(analysis, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"uses: acme/.github/.github/workflows/deploy.yml@main",
+		"image-name: ghcr.io/acme/orders",
+		"path: k8s",
+		"secrets: inherit",
+	} {
+		if !strings.Contains(workflow, want) {
+			t.Errorf("workflow missing %q:\n%s", want, workflow)
+		}
+	}
+	if strings.Contains(workflow, "docker/build-push-action") {
+		t.Error("reusable caller should not build the image itself")
+	}
+
+	cfg.CI.GitHub.Workflow = "acme/platform/.github/workflows/k8s.yaml@v2"
+	if workflow, _ = GenerateGitHubActions(analysis, cfg); !strings.Contains(workflow, "uses: acme/platform/.github/workflows/k8s.yaml@v2") {
+		t.Errorf("ci.github.workflow not used:\n%s", workflow)
+	}
+}
+
+func TestReusableWorkflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		org      string
+		workflow string
+		want     string
+		wantErr  bool
+	}{
+		{"org default", "acme", "", "acme/.github/.github/workflows/deploy.yml@main", false},
+		{"explicit", "", "acme/ci/.github/workflows/deploy.yml@v1", "acme/ci/.github/workflows/deploy.yml@v1", false},
+		{"org not a login", "Acme Corp", "", "", true},
+		{"no org", "", "", "", true},
+		{"missing ref", "acme", "acme/ci/.github/workflows/deploy.yml", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Org.Name = tt.org
+			cfg.CI.GitHub.Workflow = tt.workflow
+			got, err := ReusableWorkflow(cfg)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ReusableWorkflow() = %q, %v; want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateGitHubActionsUnknownMode(t *testing.T) {
+	cfg := config.Default()
+	cfg.CI.GitHub.Mode = "composite"
+	if _, err := GenerateGitHubActions(&types.AppAnalysis{Name: "orders"}, cfg); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// headerLines returns the notice at the top of a generated file: the tool,
+// version, and source path, and whether hand edits survive regeneration.
+// PERSONA.md keeps sections marked dorgu:keep; every other file is
+// rewritten as a whole.
+func headerLines(analysis *types.AppAnalysis, opts Options, path string) []string {
+	tool := "dorgu"
+	if opts.Provenance != nil && opts.Provenance.Version != "" {
+		tool += " " + opts.Provenance.Version
+	}
+	from := ""
+	if opts.Source != "" {
+		from = " from " + opts.Source
+	}
+
+	var lines []string
+	if path == opts.personaPath() {
+		lines = append(lines,
+			"Generated by "+tool+from+".",
+			"Edits are preserved in sections marked dorgu:keep by `dorgu persona refresh`.")
+	} else {
+		lines = append(lines,
+			"Code generated by "+tool+from+". DO NOT EDIT.",
+			"Changes are overwritten by `dorgu generate`; change .dorgu.yaml instead.")
+	}
+	if text := strings.TrimSpace(opts.Config.Header.Text); text != "" {
+		expand := strings.NewReplacer("{app}", analysis.Name, "{team}", analysis.Team, "{source}", opts.Source)
+		lines = append(lines, strings.Split(expand.Replace(text), "\n")...)
+	}
+	return lines
+}
+
+// AddHeaders puts the header comment at the top of the YAML, Terraform,
+// TypeScript, Tiltfile, and Markdown files, unless header.enabled is off in
+// the org config
+func AddHeaders(analysis *types.AppAnalysis, opts Options, files []GeneratedFile) []GeneratedFile {
+	if !opts.Config.Header.Enabled {
+		return files
+	}
+	out := make([]GeneratedFile, len(files))
+	for i, f := range files {
+		out[i] = f
+		lines := headerLines(analysis, opts, f.Path)
+		ext := strings.ToLower(filepath.Ext(f.Path))
+		if filepath.Base(f.Path) == "Tiltfile" {
+			ext = ".tf" // Starlark shares the # comments
+		}
+		switch ext {
+		case ".yaml", ".yml", ".tf":
+			out[i].Content = "# " + strings.Join(lines, "\n# ") + "\n" + f.Content
+		case ".ts":
+			out[i].Content = "// " + strings.Join(lines, "\n// ") + "\n" + f.Content
+		case ".md":
+			out[i].Content = "<!--\n" + strings.Join(lines, "\n") + "\n-->\n\n" + f.Content
+		}
+	}
+	return out
+}
@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestAddHeaders(t *testing.T) {
+	cfg := config.Default()
+	cfg.Header.Text = "Owned by {team}; ask in #platform"
+	analysis := &types.AppAnalysis{Name: "orders", Team: "payments"}
+	opts := Options{Config: cfg, Source: "apps/orders", Provenance: &Provenance{Version: "v1.2.0"}}
+	files := AddHeaders(analysis, opts, []GeneratedFile{
+		{Path: "deployment.yaml", Content: "kind: Deployment\n"},
+		{Path: DefaultPersonaPath, Content: "# orders\n"},
+		{Path: "notes.txt", Content: "x"},
+	})
+
+	wantYAML := "# Code generated by dorgu v1.2.0 from apps/orders. DO NOT EDIT.\n" +
+		"# Changes are overwritten by `dorgu generate`; change .dorgu.yaml instead.\n" +
+		"# Owned by payments; ask in #platform\n" +
+		"kind: Deployment\n"
+	if files[0].Content != wantYAML {
+		t.Errorf("deployment.yaml =\n%s\nwant\n%s", files[0].Content, wantYAML)
+	}
+	if persona := files[1].Content; !strings.HasPrefix(persona, "<!--\nGenerated by dorgu v1.2.0 from apps/orders.\nEdits are preserved") || !strings.HasSuffix(persona, "-->\n\n# orders\n") {
+		t.Errorf("PERSONA.md =\n%s", persona)
+	}
+	if files[2].Content != "x" {
+		t.Errorf("notes.txt got a header: %q", files[2].Content)
+	}
+
+	cfg.Header.Enabled = false
+	if got := AddHeaders(analysis, opts, []GeneratedFile{{Path: "hpa.yaml", Content: "kind: HPA\n"}}); got[0].Content != "kind: HPA\n" {
+		t.Errorf("header.enabled false still added a header: %q", got[0].Content)
+	}
+}
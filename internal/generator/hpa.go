@@ -1,6 +1,11 @@
 package generator
 
 import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
 	"github.com/dorgu-ai/dorgu/internal/config"
 	"github.com/dorgu-ai/dorgu/internal/types"
 )
@@ -19,6 +24,27 @@ type HPASpec struct {
 	MinReplicas    int            `json:"minReplicas"`
 	MaxReplicas    int            `json:"maxReplicas"`
 	Metrics        []MetricSpec   `json:"metrics"`
+	Behavior       *HPABehavior   `json:"behavior,omitempty"`
+}
+
+// HPABehavior configures how fast the HPA scales up and down
+type HPABehavior struct {
+	ScaleUp   *HPAScalingRules `json:"scaleUp,omitempty"`
+	ScaleDown *HPAScalingRules `json:"scaleDown,omitempty"`
+}
+
+// HPAScalingRules are the scaling rules for one direction
+type HPAScalingRules struct {
+	StabilizationWindowSeconds *int               `json:"stabilizationWindowSeconds,omitempty"`
+	SelectPolicy               string             `json:"selectPolicy,omitempty"`
+	Policies                   []HPAScalingPolicy `json:"policies,omitempty"`
+}
+
+// HPAScalingPolicy limits how much the HPA may scale within a period
+type HPAScalingPolicy struct {
+	Type          string `json:"type"`
+	Value         int    `json:"value"`
+	PeriodSeconds int    `json:"periodSeconds"`
 }
 
 // ScaleTargetRef represents the target to scale
@@ -32,6 +58,20 @@ type ScaleTargetRef struct {
 type MetricSpec struct {
 	Type     string          `json:"type"`
 	Resource *ResourceMetric `json:"resource,omitempty"`
+	Pods     *ObjectMetric   `json:"pods,omitempty"`
+	External *ObjectMetric   `json:"external,omitempty"`
+}
+
+// ObjectMetric represents a pods or external metric
+type ObjectMetric struct {
+	Metric MetricIdentifier `json:"metric"`
+	Target MetricTarget     `json:"target"`
+}
+
+// MetricIdentifier names a custom or external metric
+type MetricIdentifier struct {
+	Name     string         `json:"name"`
+	Selector *LabelSelector `json:"selector,omitempty"`
 }
 
 // ResourceMetric represents a resource-based metric
@@ -43,7 +83,176 @@ type ResourceMetric struct {
 // MetricTarget represents the target value
 type MetricTarget struct {
 	Type               string `json:"type"`
-	AverageUtilization int    `json:"averageUtilization"`
+	AverageUtilization int    `json:"averageUtilization,omitempty"`
+	AverageValue       string `json:"averageValue,omitempty"`
+	Value              string `json:"value,omitempty"`
+}
+
+// hasHPA reports whether Generate emits an HPA for the app
+func hasHPA(analysis *types.AppAnalysis) bool {
+	return analysis.Scaling != nil
+}
+
+// hpaReplicasPolicy returns the org's hpa.replicas policy, defaulting to omit
+func hpaReplicasPolicy(cfg *config.Config) string {
+	if cfg == nil || cfg.HPA.Replicas == "" {
+		return config.HPAReplicasOmit
+	}
+	return cfg.HPA.Replicas
+}
+
+// hpaBehaviorPresets are the scaling.behavior presets. balanced spells out
+// the Kubernetes defaults; aggressive reacts faster in both directions;
+// conservative adds pods slowly and removes them one at a time.
+var hpaBehaviorPresets = map[string]HPABehavior{
+	config.BehaviorAggressive: {
+		ScaleUp: &HPAScalingRules{
+			StabilizationWindowSeconds: intPtr(0),
+			SelectPolicy:               "Max",
+			Policies:                   []HPAScalingPolicy{{Type: "Percent", Value: 200, PeriodSeconds: 15}, {Type: "Pods", Value: 8, PeriodSeconds: 15}},
+		},
+		ScaleDown: &HPAScalingRules{
+			StabilizationWindowSeconds: intPtr(60),
+			Policies:                   []HPAScalingPolicy{{Type: "Percent", Value: 100, PeriodSeconds: 15}},
+		},
+	},
+	config.BehaviorBalanced: {
+		ScaleUp: &HPAScalingRules{
+			StabilizationWindowSeconds: intPtr(0),
+			SelectPolicy:               "Max",
+			Policies:                   []HPAScalingPolicy{{Type: "Percent", Value: 100, PeriodSeconds: 15}, {Type: "Pods", Value: 4, PeriodSeconds: 15}},
+		},
+		ScaleDown: &HPAScalingRules{
+			StabilizationWindowSeconds: intPtr(300),
+			Policies:                   []HPAScalingPolicy{{Type: "Percent", Value: 100, PeriodSeconds: 15}},
+		},
+	},
+	config.BehaviorConservative: {
+		ScaleUp: &HPAScalingRules{
+			StabilizationWindowSeconds: intPtr(60),
+			SelectPolicy:               "Min",
+			Policies:                   []HPAScalingPolicy{{Type: "Percent", Value: 50, PeriodSeconds: 60}, {Type: "Pods", Value: 2, PeriodSeconds: 60}},
+		},
+		ScaleDown: &HPAScalingRules{
+			StabilizationWindowSeconds: intPtr(600),
+			Policies:                   []HPAScalingPolicy{{Type: "Pods", Value: 1, PeriodSeconds: 120}},
+		},
+	},
+}
+
+// hpaBehavior returns the HPA behavior for the app's scaling.behavior preset
+// and scale_up/scale_down overrides, or nil to keep the Kubernetes defaults
+func hpaBehavior(scaling *types.ScalingConfig) (*HPABehavior, error) {
+	if scaling == nil || (scaling.Behavior == "" && scaling.ScaleUp == nil && scaling.ScaleDown == nil) {
+		return nil, nil
+	}
+	name := scaling.Behavior
+	if name == "" {
+		name = config.BehaviorBalanced
+	}
+	preset, ok := hpaBehaviorPresets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown scaling.behavior %q (use aggressive, balanced, or conservative)", name)
+	}
+	return &HPABehavior{
+		ScaleUp:   overrideScalingRules(preset.ScaleUp, scaling.ScaleUp),
+		ScaleDown: overrideScalingRules(preset.ScaleDown, scaling.ScaleDown),
+	}, nil
+}
+
+// overrideScalingRules returns a copy of base with the fields set in o
+func overrideScalingRules(base *HPAScalingRules, o *types.ScalingRules) *HPAScalingRules {
+	rules := *base
+	if o == nil {
+		return &rules
+	}
+	if o.StabilizationWindowSeconds != nil {
+		rules.StabilizationWindowSeconds = o.StabilizationWindowSeconds
+	}
+	if o.SelectPolicy != "" {
+		rules.SelectPolicy = o.SelectPolicy
+	}
+	if len(o.Policies) > 0 {
+		rules.Policies = nil
+		for _, p := range o.Policies {
+			rules.Policies = append(rules.Policies, HPAScalingPolicy{Type: p.Type, Value: p.Value, PeriodSeconds: p.PeriodSeconds})
+		}
+	}
+	return &rules
+}
+
+func intPtr(i int) *int { return &i }
+
+// hpaMetrics returns the app's pods and external metrics
+func hpaMetrics(scaling *types.ScalingConfig) ([]MetricSpec, error) {
+	if scaling == nil {
+		return nil, nil
+	}
+	var metrics []MetricSpec
+	for i, m := range scaling.Metrics {
+		spec, err := hpaMetric(m)
+		if err != nil {
+			return nil, fmt.Errorf("scaling.metrics[%d]: %w", i, err)
+		}
+		metrics = append(metrics, spec)
+	}
+	return metrics, nil
+}
+
+// hpaMetric converts one scaling metric, validating its target
+func hpaMetric(m types.ScalingMetric) (MetricSpec, error) {
+	if m.Name == "" {
+		return MetricSpec{}, fmt.Errorf("name is required")
+	}
+	if err := ValidateScalingTarget(m.Type, m.AverageValue, m.Value); err != nil {
+		return MetricSpec{}, err
+	}
+	target := MetricTarget{Type: "AverageValue", AverageValue: m.AverageValue}
+	if m.Value != "" {
+		target = MetricTarget{Type: "Value", Value: m.Value}
+	}
+	metric := &ObjectMetric{Metric: MetricIdentifier{Name: m.Name}, Target: target}
+	if len(m.Selector) > 0 {
+		metric.Metric.Selector = &LabelSelector{MatchLabels: m.Selector}
+	}
+	if strings.ToLower(m.Type) == config.MetricPods {
+		return MetricSpec{Type: "Pods", Pods: metric}, nil
+	}
+	return MetricSpec{Type: "External", External: metric}, nil
+}
+
+// ValidateScalingTarget checks a scaling metric's type and target: pods
+// metrics take an average_value, external metrics exactly one of
+// average_value and value, both positive quantities
+func ValidateScalingTarget(metricType, averageValue, value string) error {
+	switch strings.ToLower(metricType) {
+	case config.MetricPods:
+		if value != "" {
+			return fmt.Errorf("pods metrics take average_value, not value")
+		}
+		if averageValue == "" {
+			return fmt.Errorf("average_value is required")
+		}
+	case config.MetricExternal:
+		if (averageValue == "") == (value == "") {
+			return fmt.Errorf("set exactly one of average_value and value")
+		}
+	default:
+		return fmt.Errorf("type %q is not one of pods, external", metricType)
+	}
+	for _, v := range []string{averageValue, value} {
+		if v == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("%q is not a valid quantity (e.g. 100, 500m, or 1k)", v)
+		}
+		if q.Sign() <= 0 {
+			return fmt.Errorf("target %q must be positive", v)
+		}
+	}
+	return nil
 }
 
 // GenerateHPA generates a Kubernetes HorizontalPodAutoscaler manifest
@@ -56,8 +265,9 @@ func GenerateHPA(analysis *types.AppAnalysis, namespace string, cfg *config.Conf
 	targetMemory := 0
 
 	// Use app config scaling if available (already merged into analysis.Scaling by analyzer)
+	scaling := analysis.Scaling
 	if analysis.AppConfig != nil && analysis.AppConfig.Scaling != nil {
-		scaling := analysis.AppConfig.Scaling
+		scaling = analysis.AppConfig.Scaling
 		if scaling.MinReplicas > 0 {
 			minReplicas = scaling.MinReplicas
 		}
@@ -85,6 +295,15 @@ func GenerateHPA(analysis *types.AppAnalysis, namespace string, cfg *config.Conf
 		}
 	}
 
+	behavior, err := hpaBehavior(scaling)
+	if err != nil {
+		if analysis.AppConfig != nil && analysis.AppConfig.Scaling != nil {
+			return "", err
+		}
+		// A behavior guessed by the LLM is only a hint
+		behavior = nil
+	}
+
 	metrics := []MetricSpec{
 		{
 			Type: "Resource",
@@ -112,6 +331,15 @@ func GenerateHPA(analysis *types.AppAnalysis, namespace string, cfg *config.Conf
 		})
 	}
 
+	custom, err := hpaMetrics(scaling)
+	if err != nil {
+		if analysis.AppConfig != nil && analysis.AppConfig.Scaling != nil {
+			return "", err
+		}
+		custom = nil
+	}
+	metrics = append(metrics, custom...)
+
 	hpa := HPAManifest{
 		APIVersion: "autoscaling/v2",
 		Kind:       "HorizontalPodAutoscaler",
@@ -129,6 +357,7 @@ func GenerateHPA(analysis *types.AppAnalysis, namespace string, cfg *config.Conf
 			MinReplicas: minReplicas,
 			MaxReplicas: maxReplicas,
 			Metrics:     metrics,
+			Behavior:    behavior,
 		},
 	}
 
@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestHPAReplicasPolicy(t *testing.T) {
+	tests := []struct {
+		policy       string
+		scaling      bool
+		wantReplicas bool
+		wantIgnore   bool
+	}{
+		{config.HPAReplicasOmit, true, false, false},
+		{config.HPAReplicasIgnore, true, true, true},
+		{config.HPAReplicasKeep, true, true, false},
+		{config.HPAReplicasOmit, false, true, false},
+	}
+	for _, tt := range tests {
+		analysis := &types.AppAnalysis{Name: "orders", Ports: []types.Port{{Port: 8080}}}
+		if tt.scaling {
+			analysis.Scaling = &types.ScalingConfig{MinReplicas: 3, MaxReplicas: 6}
+		}
+		cfg := config.Default()
+		cfg.HPA.Replicas = tt.policy
+
+		deployment, err := GenerateDeployment(analysis, "default", cfg.Resources.Defaults, cfg)
+		if err != nil {
+			t.Fatalf("GenerateDeployment() error: %v", err)
+		}
+		if got := strings.Contains(deployment, "\n  replicas:"); got != tt.wantReplicas {
+			t.Errorf("%s (scaling %v): replicas present = %v, want %v", tt.policy, tt.scaling, got, tt.wantReplicas)
+		}
+
+		argo, err := GenerateArgoCD(analysis, "default", cfg)
+		if err != nil {
+			t.Fatalf("GenerateArgoCD() error: %v", err)
+		}
+		if got := strings.Contains(argo, "/spec/replicas") && strings.Contains(argo, "RespectIgnoreDifferences=true"); got != tt.wantIgnore {
+			t.Errorf("%s (scaling %v): ignoreDifferences = %v, want %v\n%s", tt.policy, tt.scaling, got, tt.wantIgnore, argo)
+		}
+	}
+}
+
+func TestHPABehavior(t *testing.T) {
+	zero := 0
+	tests := []struct {
+		name    string
+		scaling *types.ScalingConfig
+		want    []string
+		absent  []string
+		wantErr bool
+	}{
+		{"none", &types.ScalingConfig{}, nil, []string{"behavior:"}, false},
+		{"conservative", &types.ScalingConfig{Behavior: config.BehaviorConservative}, []string{"behavior:", "stabilizationWindowSeconds: 600", "selectPolicy: Min"}, nil, false},
+		{"override defaults to balanced", &types.ScalingConfig{ScaleDown: &types.ScalingRules{StabilizationWindowSeconds: &zero}}, []string{"stabilizationWindowSeconds: 0", "value: 4"}, []string{"stabilizationWindowSeconds: 300"}, false},
+		{"override policies", &types.ScalingConfig{Behavior: config.BehaviorAggressive, ScaleUp: &types.ScalingRules{Policies: []types.ScalingPolicy{{Type: "Pods", Value: 3, PeriodSeconds: 30}}}}, []string{"value: 3", "periodSeconds: 30"}, []string{"value: 200"}, false},
+		{"unknown preset", &types.ScalingConfig{Behavior: "fast"}, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := &types.AppAnalysis{Name: "orders", AppConfig: &types.AppConfigContext{Scaling: tt.scaling}}
+			hpa, err := GenerateHPA(analysis, "default", config.Default())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateHPA() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(hpa, s) {
+					t.Errorf("missing %q in:\n%s", s, hpa)
+				}
+			}
+			for _, s := range tt.absent {
+				if strings.Contains(hpa, s) {
+					t.Errorf("unexpected %q in:\n%s", s, hpa)
+				}
+			}
+		})
+	}
+}
+
+func TestHPAMetrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		metric  types.ScalingMetric
+		want    []string
+		wantErr bool
+	}{
+		{"pods", types.ScalingMetric{Type: "pods", Name: "http_requests_per_second", AverageValue: "100"}, []string{"type: Pods", "name: http_requests_per_second", "type: AverageValue", "averageValue: \"100\""}, false},
+		{"external value", types.ScalingMetric{Type: "External", Name: "sqs_messages_visible", Selector: map[string]string{"queue": "orders"}, Value: "30"}, []string{"type: External", "queue: orders", "type: Value", "value: \"30\""}, false},
+		{"pods with value", types.ScalingMetric{Type: "pods", Name: "rps", Value: "100"}, nil, true},
+		{"external both targets", types.ScalingMetric{Type: "external", Name: "depth", Value: "1", AverageValue: "1"}, nil, true},
+		{"bad quantity", types.ScalingMetric{Type: "pods", Name: "rps", AverageValue: "lots"}, nil, true},
+		{"unknown type", types.ScalingMetric{Type: "object", Name: "rps", AverageValue: "1"}, nil, true},
+		{"missing name", types.ScalingMetric{Type: "pods", AverageValue: "1"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := &types.AppAnalysis{Name: "orders", AppConfig: &types.AppConfigContext{
+				Scaling: &types.ScalingConfig{Metrics: []types.ScalingMetric{tt.metric}},
+			}}
+			hpa, err := GenerateHPA(analysis, "default", config.Default())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateHPA() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !strings.Contains(hpa, "name: cpu") {
+				t.Errorf("cpu metric dropped:\n%s", hpa)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(hpa, s) {
+					t.Errorf("missing %q in:\n%s", s, hpa)
+				}
+			}
+		})
+	}
+}
@@ -69,12 +69,9 @@ func GenerateIngress(analysis *types.AppAnalysis, namespace string, cfg *config.
 	}
 
 	// Determine TLS settings from app config or org config
-	tlsEnabled := cfg.Ingress.TLS.Enabled
+	tlsEnabled := ingressTLSEnabled(analysis, cfg)
 	tlsSecret := analysis.Name + "-tls"
 	if analysis.AppConfig != nil && analysis.AppConfig.Ingress != nil {
-		if analysis.AppConfig.Ingress.TLSEnabled {
-			tlsEnabled = true
-		}
 		if analysis.AppConfig.Ingress.TLSSecret != "" {
 			tlsSecret = analysis.AppConfig.Ingress.TLSSecret
 		}
@@ -85,63 +82,45 @@ func GenerateIngress(analysis *types.AppAnalysis, namespace string, cfg *config.
 		annotations["cert-manager.io/cluster-issuer"] = cfg.Ingress.TLS.ClusterIssuer
 	}
 
-	// Determine host from app config or generate from org config
-	host := analysis.Name + cfg.Ingress.DomainSuffix
-	if analysis.AppConfig != nil && analysis.AppConfig.Ingress != nil && analysis.AppConfig.Ingress.Host != "" {
-		host = analysis.AppConfig.Ingress.Host
+	// Controller-specific annotations: class preset, then the app's own
+	ingressClassName := ingressClass(analysis, cfg)
+	for k, v := range ingressPreset(ingressClassName, cfg) {
+		annotations[k] = v
 	}
-
-	// Find the HTTP port
-	httpPort := 80
-	for _, p := range analysis.Ports {
-		if p.Port == 80 || p.Port == 8080 || p.Port == 3000 || p.Port == 5000 || p.Port == 8000 {
-			httpPort = p.Port
-			break
+	if analysis.AppConfig != nil && analysis.AppConfig.Ingress != nil {
+		for k, v := range analysis.AppConfig.Ingress.Annotations {
+			annotations[k] = v
 		}
 	}
-	if len(analysis.Ports) > 0 && httpPort == 80 {
-		httpPort = analysis.Ports[0].Port
-	}
 
-	ingressClassName := cfg.Ingress.Class
+	hosts := ingressHosts(analysis, cfg)
+
+	httpPort := ingressHTTPPort(analysis)
 
 	// Build paths from app config or default to "/"
 	var ingressPaths []IngressPath
-	if analysis.AppConfig != nil && analysis.AppConfig.Ingress != nil && len(analysis.AppConfig.Ingress.Paths) > 0 {
-		for _, p := range analysis.AppConfig.Ingress.Paths {
-			pathType := p.PathType
-			if pathType == "" {
-				pathType = "Prefix"
-			}
-			ingressPaths = append(ingressPaths, IngressPath{
-				Path:     p.Path,
-				PathType: pathType,
-				Backend: IngressBackend{
-					Service: IngressServiceBackend{
-						Name: analysis.Name,
-						Port: ServiceBackendPort{
-							Number: httpPort,
-						},
-					},
-				},
-			})
+	for _, p := range ingressPathDefs(analysis) {
+		pathType := p.PathType
+		if pathType == "" {
+			pathType = "Prefix"
 		}
-	} else {
-		// Default path
-		ingressPaths = []IngressPath{
-			{
-				Path:     "/",
-				PathType: "Prefix",
-				Backend: IngressBackend{
-					Service: IngressServiceBackend{
-						Name: analysis.Name,
-						Port: ServiceBackendPort{
-							Number: httpPort,
-						},
-					},
-				},
+		backend := IngressServiceBackend{
+			Name: analysis.Name,
+			Port: ServiceBackendPort{
+				Number: httpPort,
 			},
 		}
+		if p.Service != "" {
+			backend.Name = p.Service
+		}
+		if p.Port > 0 {
+			backend.Port.Number = p.Port
+		}
+		ingressPaths = append(ingressPaths, IngressPath{
+			Path:     p.Path,
+			PathType: pathType,
+			Backend:  IngressBackend{Service: backend},
+		})
 	}
 
 	ingress := IngressManifest{
@@ -155,26 +134,139 @@ func GenerateIngress(analysis *types.AppAnalysis, namespace string, cfg *config.
 		},
 		Spec: IngressSpec{
 			IngressClassName: &ingressClassName,
-			Rules: []IngressRule{
-				{
-					Host: host,
-					HTTP: IngressRuleHTTP{
-						Paths: ingressPaths,
-					},
-				},
-			},
 		},
 	}
 
-	// Add TLS configuration
-	if tlsEnabled {
-		ingress.Spec.TLS = []IngressTLS{
-			{
-				Hosts:      []string{host},
-				SecretName: tlsSecret,
+	// One rule per host, all serving the same paths
+	for _, h := range hosts {
+		ingress.Spec.Rules = append(ingress.Spec.Rules, IngressRule{
+			Host: h.Host,
+			HTTP: IngressRuleHTTP{
+				Paths: ingressPaths,
 			},
-		}
+		})
+	}
+
+	// Add TLS configuration, grouping hosts that share a secret
+	if tlsEnabled {
+		ingress.Spec.TLS = ingressTLS(hosts, tlsSecret)
 	}
 
 	return toYAML(ingress)
 }
+
+// ingressHTTPPort returns the Service port that ingress paths route to
+// unless they set their own
+func ingressHTTPPort(analysis *types.AppAnalysis) int {
+	httpPort := 80
+	for _, p := range analysis.Ports {
+		if p.Port == 80 || p.Port == 8080 || p.Port == 3000 || p.Port == 5000 || p.Port == 8000 {
+			httpPort = p.Port
+			break
+		}
+	}
+	if len(analysis.Ports) > 0 && httpPort == 80 {
+		httpPort = analysis.Ports[0].Port
+	}
+	return httpPort
+}
+
+// ingressPathDefs returns the paths from app config, or "/" by default
+func ingressPathDefs(analysis *types.AppAnalysis) []types.IngressPathDef {
+	if analysis.AppConfig != nil && analysis.AppConfig.Ingress != nil && len(analysis.AppConfig.Ingress.Paths) > 0 {
+		return analysis.AppConfig.Ingress.Paths
+	}
+	return []types.IngressPathDef{{Path: "/", PathType: "Prefix"}}
+}
+
+// builtinIngressPresets are annotations dorgu adds for well-known ingress
+// classes. ALB needs ip targets to reach a ClusterIP Service.
+var builtinIngressPresets = map[string]map[string]string{
+	"alb": {
+		"alb.ingress.kubernetes.io/scheme":      "internet-facing",
+		"alb.ingress.kubernetes.io/target-type": "ip",
+	},
+}
+
+// ingressClass returns the app's ingress class, defaulting to the org's
+func ingressClass(analysis *types.AppAnalysis, cfg *config.Config) string {
+	if analysis.AppConfig != nil && analysis.AppConfig.Ingress != nil && analysis.AppConfig.Ingress.Class != "" {
+		return analysis.AppConfig.Ingress.Class
+	}
+	return cfg.Ingress.Class
+}
+
+// ingressPreset returns the annotations for an ingress class: the built-in
+// preset overlaid with the org's ingress.presets entry. An empty org value
+// drops the built-in annotation.
+func ingressPreset(class string, cfg *config.Config) map[string]string {
+	preset := make(map[string]string)
+	for k, v := range builtinIngressPresets[class] {
+		preset[k] = v
+	}
+	for k, v := range cfg.Ingress.Presets[class] {
+		if v == "" {
+			delete(preset, k)
+			continue
+		}
+		preset[k] = v
+	}
+	return preset
+}
+
+// ingressTLSEnabled reports whether TLS is on in org or app config
+func ingressTLSEnabled(analysis *types.AppAnalysis, cfg *config.Config) bool {
+	if analysis.AppConfig != nil && analysis.AppConfig.Ingress != nil && analysis.AppConfig.Ingress.TLSEnabled {
+		return true
+	}
+	return cfg.Ingress.TLS.Enabled
+}
+
+// ingressHosts returns the hosts the app is served on: ingress.host (or the
+// app name plus the org domain suffix when neither host nor hosts is set),
+// followed by ingress.hosts
+func ingressHosts(analysis *types.AppAnalysis, cfg *config.Config) []types.IngressHostDef {
+	var hosts []types.IngressHostDef
+	var extra []types.IngressHostDef
+	if app := analysis.AppConfig; app != nil && app.Ingress != nil {
+		if app.Ingress.Host != "" {
+			hosts = append(hosts, types.IngressHostDef{Host: app.Ingress.Host})
+		}
+		extra = app.Ingress.Hosts
+	}
+	if len(hosts) == 0 && len(extra) == 0 {
+		hosts = append(hosts, types.IngressHostDef{Host: analysis.Name + cfg.Ingress.DomainSuffix})
+	}
+	seen := make(map[string]bool)
+	for _, h := range hosts {
+		seen[h.Host] = true
+	}
+	for _, h := range extra {
+		if h.Host != "" && !seen[h.Host] {
+			seen[h.Host] = true
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts
+}
+
+// ingressTLS groups hosts by TLS secret, in order of first use. Hosts
+// without their own secret share sharedSecret.
+func ingressTLS(hosts []types.IngressHostDef, sharedSecret string) []IngressTLS {
+	var tls []IngressTLS
+	index := make(map[string]int)
+	for _, h := range hosts {
+		secret := h.TLSSecret
+		if secret == "" {
+			secret = sharedSecret
+		}
+		i, ok := index[secret]
+		if !ok {
+			i = len(tls)
+			index[secret] = i
+			tls = append(tls, IngressTLS{SecretName: secret})
+		}
+		tls[i].Hosts = append(tls[i].Hosts, h.Host)
+	}
+	return tls
+}
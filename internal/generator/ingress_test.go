@@ -0,0 +1,273 @@
+package generator
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestGenerateIngressPathBackends(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:  "api",
+		Ports: []types.Port{{Port: 8080}, {Port: 9000}},
+		AppConfig: &types.AppConfigContext{Ingress: &types.IngressContext{
+			Enabled: true,
+			Paths: []types.IngressPathDef{
+				{Path: "/"},
+				{Path: "/admin", Port: 9000},
+				{Path: "/docs", PathType: "Exact", Service: "docs", Port: 80},
+			},
+		}},
+	}
+	out, err := GenerateIngress(analysis, "default", config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ing IngressManifest
+	if err := yaml.Unmarshal([]byte(out), &ing); err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		path, pathType, service string
+		port                    int
+	}{
+		{"/", "Prefix", "api", 8080},
+		{"/admin", "Prefix", "api", 9000},
+		{"/docs", "Exact", "docs", 80},
+	}
+	paths := ing.Spec.Rules[0].HTTP.Paths
+	if len(paths) != len(want) {
+		t.Fatalf("got %d paths, want %d", len(paths), len(want))
+	}
+	for i, w := range want {
+		p := paths[i]
+		if p.Path != w.path || p.PathType != w.pathType || p.Backend.Service.Name != w.service || p.Backend.Service.Port.Number != w.port {
+			t.Errorf("path %d = %s %s -> %s:%d, want %s %s -> %s:%d", i,
+				p.Path, p.PathType, p.Backend.Service.Name, p.Backend.Service.Port.Number,
+				w.path, w.pathType, w.service, w.port)
+		}
+	}
+}
+
+func TestValidateIngressBackendPorts(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []types.IngressPathDef
+		want  int
+	}{
+		{"default port", []types.IngressPathDef{{Path: "/"}}, 0},
+		{"exposed port", []types.IngressPathDef{{Path: "/admin", Port: 9000}}, 0},
+		{"missing port", []types.IngressPathDef{{Path: "/admin", Port: 9001}}, 1},
+		{"other service", []types.IngressPathDef{{Path: "/docs", Service: "docs", Port: 9001}}, 0},
+		{"own service by name", []types.IngressPathDef{{Path: "/admin", Service: "api", Port: 9001}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := &types.AppAnalysis{
+				Name:      "api",
+				Ports:     []types.Port{{Port: 8080}, {Port: 9000}},
+				AppConfig: &types.AppConfigContext{Ingress: &types.IngressContext{Enabled: true, Paths: tt.paths}},
+			}
+			result := &ValidationResult{}
+			validateIngressBackendPorts(analysis, Options{Config: config.Default()}, result)
+			if len(result.Issues) != tt.want {
+				t.Errorf("got %d issues, want %d: %+v", len(result.Issues), tt.want, result.Issues)
+			}
+		})
+	}
+}
+
+func TestGenerateIngressHosts(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:  "api",
+		Ports: []types.Port{{Port: 8080}},
+		AppConfig: &types.AppConfigContext{Ingress: &types.IngressContext{
+			Enabled:    true,
+			Host:       "api.example.com",
+			TLSEnabled: true,
+			Hosts: []types.IngressHostDef{
+				{Host: "legacy.example.com"},
+				{Host: "*.api.example.com", TLSSecret: "wildcard-tls"},
+				{Host: "api.example.com"},
+			},
+		}},
+	}
+	out, err := GenerateIngress(analysis, "default", config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ing IngressManifest
+	if err := yaml.Unmarshal([]byte(out), &ing); err != nil {
+		t.Fatal(err)
+	}
+	var hosts []string
+	for _, r := range ing.Spec.Rules {
+		hosts = append(hosts, r.Host)
+	}
+	if want := []string{"api.example.com", "legacy.example.com", "*.api.example.com"}; !reflect.DeepEqual(hosts, want) {
+		t.Errorf("rule hosts = %v, want %v", hosts, want)
+	}
+	wantTLS := []IngressTLS{
+		{Hosts: []string{"api.example.com", "legacy.example.com"}, SecretName: "api-tls"},
+		{Hosts: []string{"*.api.example.com"}, SecretName: "wildcard-tls"},
+	}
+	if !reflect.DeepEqual(ing.Spec.TLS, wantTLS) {
+		t.Errorf("tls = %+v, want %+v", ing.Spec.TLS, wantTLS)
+	}
+}
+
+func TestIngressHostsDefault(t *testing.T) {
+	cfg := config.Default()
+	cfg.Ingress.DomainSuffix = ".apps.example.com"
+	got := ingressHosts(&types.AppAnalysis{Name: "api"}, cfg)
+	if len(got) != 1 || got[0].Host != "api.apps.example.com" {
+		t.Errorf("ingressHosts() = %+v", got)
+	}
+}
+
+func TestIngressAnnotations(t *testing.T) {
+	cfg := config.Default()
+	cfg.Ingress.Presets = map[string]map[string]string{
+		"alb":   {"alb.ingress.kubernetes.io/scheme": "internal", "alb.ingress.kubernetes.io/target-type": ""},
+		"nginx": {"nginx.ingress.kubernetes.io/proxy-body-size": "10m"},
+	}
+	tests := []struct {
+		name  string
+		class string
+		app   map[string]string
+		want  map[string]string
+	}{
+		{
+			name: "org class preset",
+			want: map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "10m"},
+		},
+		{
+			name: "app overrides preset",
+			app:  map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "50m"},
+			want: map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "50m"},
+		},
+		{
+			name:  "app class with built-in preset",
+			class: "alb",
+			want:  map[string]string{"alb.ingress.kubernetes.io/scheme": "internal"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := &types.AppAnalysis{
+				Name:  "api",
+				Ports: []types.Port{{Port: 8080}},
+				AppConfig: &types.AppConfigContext{Ingress: &types.IngressContext{
+					Enabled: true, Class: tt.class, Annotations: tt.app,
+				}},
+			}
+			out, err := GenerateIngress(analysis, "default", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ing IngressManifest
+			if err := yaml.Unmarshal([]byte(out), &ing); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(ing.Metadata.Annotations, tt.want) {
+				t.Errorf("annotations = %v, want %v", ing.Metadata.Annotations, tt.want)
+			}
+			wantClass := tt.class
+			if wantClass == "" {
+				wantClass = "nginx"
+			}
+			if *ing.Spec.IngressClassName != wantClass {
+				t.Errorf("class = %s, want %s", *ing.Spec.IngressClassName, wantClass)
+			}
+		})
+	}
+}
+
+func TestValidateIngressHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		suffixes []string
+		resolve  bool
+		want     ValidationSeverity
+	}{
+		{name: "valid", host: "api.example.com"},
+		{name: "wildcard", host: "*.example.com"},
+		{name: "single label", host: "api", want: SeverityError},
+		{name: "uppercase", host: "API.example.com", want: SeverityError},
+		{name: "allowed domain", host: "api.apps.example.com", suffixes: []string{".apps.example.com"}},
+		{name: "allowed domain without dot", host: "api.apps.example.com", suffixes: []string{"apps.example.com"}},
+		{name: "other domain", host: "api.example.org", suffixes: []string{".apps.example.com"}, want: SeverityError},
+		{name: "suffix is not a label boundary", host: "api.myapps.example.com", suffixes: []string{"apps.example.com"}, want: SeverityError},
+		{name: "resolves", host: "api.example.com", resolve: true},
+		{name: "does not resolve", host: "missing.example.com", resolve: true, want: SeverityWarning},
+		{name: "wildcard is not resolved", host: "*.missing.example.com", resolve: true},
+	}
+	defer func(orig func(context.Context, string) ([]string, error)) { lookupHost = orig }(lookupHost)
+	lookupHost = func(_ context.Context, host string) ([]string, error) {
+		if strings.HasPrefix(host, "missing.") {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		return []string{"192.0.2.10"}, nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Validation.Ingress.DomainSuffixes = tt.suffixes
+			cfg.Validation.Ingress.ResolveHosts = tt.resolve
+			analysis := &types.AppAnalysis{
+				Name:      "api",
+				Ports:     []types.Port{{Port: 8080}},
+				AppConfig: &types.AppConfigContext{Ingress: &types.IngressContext{Enabled: true, Host: tt.host}},
+			}
+			result := &ValidationResult{}
+			validateIngressHost(context.Background(), analysis, Options{Config: cfg}, result)
+			var got ValidationSeverity
+			for _, issue := range result.Issues {
+				got = issue.Severity
+			}
+			if len(result.Issues) > 1 || got != tt.want {
+				t.Errorf("issues %+v, want one %q", result.Issues, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateClusterIssuer(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster *ClusterState
+		issuer  string
+		want    int
+	}{
+		{name: "no cluster checks", issuer: "letsencrypt"},
+		{name: "issuer exists", cluster: &ClusterState{CertManager: true, ClusterIssuers: []string{"letsencrypt"}}, issuer: "letsencrypt"},
+		{name: "issuer missing", cluster: &ClusterState{CertManager: true, ClusterIssuers: []string{"letsencrypt-staging"}}, issuer: "letsencrypt", want: 1},
+		{name: "no cert-manager", cluster: &ClusterState{}, issuer: "letsencrypt", want: 1},
+		{name: "no issuer configured", cluster: &ClusterState{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Ingress.TLS.Enabled = true
+			cfg.Ingress.TLS.ClusterIssuer = tt.issuer
+			analysis := &types.AppAnalysis{
+				Name:      "api",
+				Ports:     []types.Port{{Port: 8080}},
+				AppConfig: &types.AppConfigContext{Ingress: &types.IngressContext{Enabled: true, Host: "api.example.com"}},
+			}
+			result := &ValidationResult{}
+			validateAgainstCluster(analysis, nil, Options{Config: cfg, Cluster: tt.cluster}, result)
+			if len(result.Issues) != tt.want {
+				t.Errorf("got %d issues, want %d: %+v", len(result.Issues), tt.want, result.Issues)
+			}
+		})
+	}
+}
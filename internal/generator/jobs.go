@@ -0,0 +1,329 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// JobManifest represents a Kubernetes Job
+type JobManifest struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Metadata   Metadata `json:"metadata"`
+	Spec       JobSpec  `json:"spec"`
+}
+
+// JobSpec represents a Job spec
+type JobSpec struct {
+	BackoffLimit          *int            `json:"backoffLimit,omitempty"`
+	ActiveDeadlineSeconds int             `json:"activeDeadlineSeconds,omitempty"`
+	Template              PodTemplateSpec `json:"template"`
+}
+
+// CronJobManifest represents a Kubernetes CronJob
+type CronJobManifest struct {
+	APIVersion string      `json:"apiVersion"`
+	Kind       string      `json:"kind"`
+	Metadata   Metadata    `json:"metadata"`
+	Spec       CronJobSpec `json:"spec"`
+}
+
+// CronJobSpec represents a CronJob spec
+type CronJobSpec struct {
+	Schedule          string          `json:"schedule"`
+	ConcurrencyPolicy string          `json:"concurrencyPolicy,omitempty"`
+	JobTemplate       JobTemplateSpec `json:"jobTemplate"`
+}
+
+// JobTemplateSpec represents a CronJob's job template
+type JobTemplateSpec struct {
+	Spec JobSpec `json:"spec"`
+}
+
+// jobHookAnnotations are the ArgoCD and Helm hook annotations of each hook.
+// Hook Jobs are recreated for every sync, so the previous run is deleted
+// first.
+var jobHookAnnotations = map[string]map[string]string{
+	config.HookPreDeploy: {
+		"argocd.argoproj.io/hook":               "PreSync",
+		"argocd.argoproj.io/hook-delete-policy": "BeforeHookCreation",
+		"helm.sh/hook":                          "pre-install,pre-upgrade",
+		"helm.sh/hook-delete-policy":            "before-hook-creation",
+	},
+	config.HookPostDeploy: {
+		"argocd.argoproj.io/hook":               "PostSync",
+		"argocd.argoproj.io/hook-delete-policy": "BeforeHookCreation",
+		"helm.sh/hook":                          "post-install,post-upgrade",
+		"helm.sh/hook-delete-policy":            "before-hook-creation",
+	},
+}
+
+// ValidateJob checks a job's name, command, hook, and schedule
+func ValidateJob(name string, command []string, hook, schedule string) error {
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !IsResourceName(name) {
+		return fmt.Errorf("name %q must be lowercase alphanumerics and '-'", name)
+	}
+	if len(command) == 0 {
+		return fmt.Errorf("command is required")
+	}
+	switch hook {
+	case "", config.HookPreDeploy, config.HookPostDeploy:
+	default:
+		return fmt.Errorf("hook %q is not one of pre-deploy, post-deploy", hook)
+	}
+	if schedule == "" {
+		return nil
+	}
+	if hook != "" {
+		return fmt.Errorf("a job with a schedule cannot have a hook")
+	}
+	return ValidateSchedule(schedule)
+}
+
+// scheduleMacros are the schedules CronJobs accept besides five fields
+var scheduleMacros = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+	"@daily": true, "@midnight": true, "@hourly": true,
+}
+
+// scheduleFields are the fields of a cron schedule, in order, with their
+// ranges and the names they accept
+var scheduleFields = []struct {
+	name     string
+	min, max int
+	names    []string
+}{
+	{"minute", 0, 59, nil},
+	{"hour", 0, 23, nil},
+	{"day of month", 1, 31, nil},
+	{"month", 1, 12, []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{"day of week", 0, 7, []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// ValidateSchedule checks a CronJob schedule: five fields (minute, hour,
+// day of month, month, day of week) or a macro such as @daily
+func ValidateSchedule(schedule string) error {
+	if scheduleMacros[schedule] {
+		return nil
+	}
+	fields := strings.Fields(schedule)
+	if len(fields) != len(scheduleFields) {
+		return fmt.Errorf("schedule %q must have 5 fields (minute hour day-of-month month day-of-week) or be a macro such as @daily", schedule)
+	}
+	for i, field := range fields {
+		f := scheduleFields[i]
+		for _, item := range strings.Split(field, ",") {
+			values, step, hasStep := strings.Cut(item, "/")
+			if hasStep {
+				if n, err := strconv.Atoi(step); err != nil || n < 1 {
+					return fmt.Errorf("schedule %q: %s step %q is not a positive number", schedule, f.name, step)
+				}
+			}
+			if values == "*" || (values == "?" && (i == 2 || i == 4)) {
+				continue
+			}
+			low, high, isRange := strings.Cut(values, "-")
+			bounds := []string{low}
+			if isRange {
+				bounds = append(bounds, high)
+			}
+			for _, b := range bounds {
+				if !scheduleValue(b, f.min, f.max, f.names) {
+					return fmt.Errorf("schedule %q: %s %q is not between %d and %d", schedule, f.name, b, f.min, f.max)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// scheduleValue reports whether s is a number within min and max, or one of
+// names, ignoring case
+func scheduleValue(s string, min, max int, names []string) bool {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n >= min && n <= max
+	}
+	return slices.Contains(names, strings.ToLower(s))
+}
+
+// GenerateJobs generates a Job manifest, jobs/<name>.yaml, for each job in
+// the app config, a CronJob for jobs with a schedule, and the migration Job
+// when migrations.job is set. Jobs run the app's image, environment, and
+// resources.
+func GenerateJobs(analysis *types.AppAnalysis, namespace string, resources config.ResourceSpec, cfg *config.Config) ([]GeneratedFile, error) {
+	var jobs []types.JobContext
+	if analysis.AppConfig != nil {
+		jobs = analysis.AppConfig.Jobs
+	}
+	seen := map[string]bool{}
+	for i, job := range jobs {
+		if err := ValidateJob(job.Name, job.Command, job.Hook, job.Schedule); err != nil {
+			return nil, fmt.Errorf("jobs[%d] in the app .dorgu.yaml: %w", i, err)
+		}
+		if seen[job.Name] {
+			return nil, fmt.Errorf("jobs[%d] in the app .dorgu.yaml: duplicate job name %q", i, job.Name)
+		}
+		seen[job.Name] = true
+	}
+	if job := MigrationJob(analysis, cfg); job != nil {
+		jobs = append(jobs, *job)
+	}
+
+	var files []GeneratedFile
+	for _, job := range jobs {
+		content, err := generateJob(analysis, job, namespace, resources, cfg)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, GeneratedFile{Path: "jobs/" + job.Name + ".yaml", Content: content})
+	}
+	return files, nil
+}
+
+func generateJob(analysis *types.AppAnalysis, job types.JobContext, namespace string, resources config.ResourceSpec, cfg *config.Config) (string, error) {
+	// The Job gets its own name label so the Service, which selects on the
+	// app's, does not send traffic to its pods
+	name := ResourceName(analysis.Name + "-" + job.Name)
+	labels := buildLabelsWithAppConfig(analysis, cfg)
+	labels["app.kubernetes.io/name"] = name
+	labels["app.kubernetes.io/part-of"] = analysis.Name
+	labels["app.kubernetes.io/component"] = "job"
+
+	annotations := map[string]string{}
+	for k, v := range buildAnnotationsWithAppConfig(analysis, cfg) {
+		annotations[k] = v
+	}
+	for k, v := range jobHookAnnotations[job.Hook] {
+		annotations[k] = v
+	}
+
+	image := job.Image
+	if image == "" {
+		image = appImage(analysis, cfg)
+	}
+
+	podAnnotations := withVaultAgentAnnotations(buildAnnotationsWithAppConfig(analysis, cfg), analysis, cfg)
+	if _, ok := podAnnotations["vault.hashicorp.com/agent-inject"]; ok {
+		// A Vault Agent sidecar would keep the Job's pod running
+		podAnnotations["vault.hashicorp.com/agent-pre-populate-only"] = "true"
+	}
+
+	var volumes []Volume
+	var volumeMounts []VolumeMount
+	if volume, mount := secretsVolume(analysis, cfg); volume != nil {
+		volumes = append(volumes, *volume)
+		volumeMounts = append(volumeMounts, *mount)
+	}
+
+	podSecurityContext, containerSecurityContext := securityContexts(analysis, cfg)
+	container := Container{
+		Name:            job.Name,
+		Image:           image,
+		Command:         job.Command,
+		Env:             containerEnv(analysis, namespace, cfg),
+		Resources:       resourceRequirements(appResources(analysis, resources)),
+		SecurityContext: containerSecurityContext,
+		VolumeMounts:    volumeMounts,
+	}
+	security := resolveSecurity(analysis, cfg)
+	podAnnotations = withAppArmorAnnotations(podAnnotations, security.AppArmor, []Container{container})
+
+	spec := JobSpec{
+		BackoffLimit:          job.BackoffLimit,
+		ActiveDeadlineSeconds: job.ActiveDeadlineSeconds,
+		Template: PodTemplateSpec{
+			Metadata: Metadata{
+				Labels:      labels,
+				Annotations: nonEmpty(podAnnotations),
+			},
+			Spec: PodSpec{
+				RestartPolicy:    "Never",
+				RuntimeClassName: security.RuntimeClass,
+				SecurityContext:  podSecurityContext,
+				DNSConfig:        podDNSConfig(analysis),
+				HostAliases:      hostAliases(analysis),
+				Volumes:          volumes,
+				Containers:       []Container{container},
+			},
+		},
+	}
+	metadata := Metadata{
+		Name:        name,
+		Namespace:   namespace,
+		Labels:      labels,
+		Annotations: nonEmpty(annotations),
+	}
+	if job.Schedule != "" {
+		// A run that overlaps the previous one is skipped, not started twice
+		return toYAML(CronJobManifest{
+			APIVersion: "batch/v1",
+			Kind:       "CronJob",
+			Metadata:   metadata,
+			Spec: CronJobSpec{
+				Schedule:          job.Schedule,
+				ConcurrencyPolicy: "Forbid",
+				JobTemplate:       JobTemplateSpec{Spec: spec},
+			},
+		})
+	}
+	return toYAML(JobManifest{
+		APIVersion: "batch/v1",
+		Kind:       "Job",
+		Metadata:   metadata,
+		Spec:       spec,
+	})
+}
+
+// UnconfiguredSchedules returns the cron schedules the analysis found that
+// no job in the app config runs
+func UnconfiguredSchedules(analysis *types.AppAnalysis) []types.ScheduleHint {
+	var jobs []types.JobContext
+	if analysis.AppConfig != nil {
+		jobs = analysis.AppConfig.Jobs
+	}
+	var hints []types.ScheduleHint
+	for _, hint := range analysis.Schedules {
+		if !slices.ContainsFunc(jobs, func(job types.JobContext) bool { return runsSchedule(job, hint) }) {
+			hints = append(hints, hint)
+		}
+	}
+	return hints
+}
+
+// runsSchedule reports whether job is taken to run a schedule the analysis
+// found: it has the schedule's name or command, or is scheduled at the same
+// time
+func runsSchedule(job types.JobContext, hint types.ScheduleHint) bool {
+	if job.Name == hint.Name {
+		return true
+	}
+	if len(hint.Command) > 0 && shellCommand(job.Command) == shellCommand(hint.Command) {
+		return true
+	}
+	return job.Schedule != "" && job.Schedule == hint.Schedule
+}
+
+// shellCommand returns the command line a job runs, without the sh -c that
+// crontab commands are wrapped in
+func shellCommand(command []string) string {
+	if len(command) == 3 && (command[0] == "/bin/sh" || command[0] == "sh") && command[1] == "-c" {
+		return strings.TrimSpace(command[2])
+	}
+	return strings.Join(command, " ")
+}
+
+// nonEmpty returns m, or nil when it is empty so it is left out of the YAML
+func nonEmpty(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
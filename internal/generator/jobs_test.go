@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestGenerateJobs(t *testing.T) {
+	tests := []struct {
+		name    string
+		job     types.JobContext
+		want    []string
+		absent  []string
+		wantErr bool
+	}{
+		{
+			name: "pre-deploy migration",
+			job:  types.JobContext{Name: "migrate", Command: []string{"npm", "run", "migrate"}, Hook: config.HookPreDeploy},
+			want: []string{"kind: Job", "name: orders-migrate", "argocd.argoproj.io/hook: PreSync", "helm.sh/hook: pre-install,pre-upgrade", "restartPolicy: Never", "image: orders:latest", "- migrate"},
+		},
+		{
+			name:   "one-off with image",
+			job:    types.JobContext{Name: "seed", Command: []string{"./seed"}, Image: "tools/seed:1.0"},
+			want:   []string{"image: tools/seed:1.0"},
+			absent: []string{"argocd.argoproj.io/hook"},
+		},
+		{
+			name:   "scheduled",
+			job:    types.JobContext{Name: "report", Command: []string{"./report"}, Schedule: "0 3 * * 1-5"},
+			want:   []string{"kind: CronJob", `schedule: 0 3 * * 1-5`, "concurrencyPolicy: Forbid", "jobTemplate:", "restartPolicy: Never"},
+			absent: []string{"kind: Job\n", "argocd.argoproj.io/hook"},
+		},
+		{name: "schedule with hook", job: types.JobContext{Name: "report", Command: []string{"x"}, Hook: config.HookPreDeploy, Schedule: "@daily"}, wantErr: true},
+		{name: "invalid schedule", job: types.JobContext{Name: "report", Command: []string{"x"}, Schedule: "0 25 * * *"}, wantErr: true},
+		{name: "no command", job: types.JobContext{Name: "migrate", Hook: config.HookPreDeploy}, wantErr: true},
+		{name: "unknown hook", job: types.JobContext{Name: "migrate", Command: []string{"x"}, Hook: "pre-sync"}, wantErr: true},
+		{name: "invalid name", job: types.JobContext{Name: "Migrate_DB", Command: []string{"x"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := &types.AppAnalysis{Name: "orders", AppConfig: &types.AppConfigContext{Jobs: []types.JobContext{tt.job}}}
+			cfg := config.Default()
+			files, err := GenerateJobs(analysis, "default", cfg.Resources.Defaults, cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateJobs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(files) != 1 || files[0].Path != "jobs/"+tt.job.Name+".yaml" {
+				t.Fatalf("GenerateJobs() files = %+v", files)
+			}
+			content := files[0].Content
+			// The Service selects on the app's name label
+			if strings.Contains(content, "app.kubernetes.io/name: orders\n") {
+				t.Errorf("Job pods carry the app's name label:\n%s", content)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(content, s) {
+					t.Errorf("missing %q in:\n%s", s, content)
+				}
+			}
+			for _, s := range tt.absent {
+				if strings.Contains(content, s) {
+					t.Errorf("unexpected %q in:\n%s", s, content)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		schedule string
+		wantErr  bool
+	}{
+		{"*/15 * * * *", false},
+		{"0 3 * * mon-fri", false},
+		{"30 2 1,15 * ?", false},
+		{"0 0 1 JAN *", false},
+		{"@daily", false},
+		{"", true},
+		{"@every 5m", true},
+		{"0 0 3 * * *", true},
+		{"60 * * * *", true},
+		{"0 * 0 * *", true},
+		{"*/0 * * * *", true},
+		{"0 0 * * someday", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateSchedule(tt.schedule); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateSchedule(%q) error = %v, wantErr %v", tt.schedule, err, tt.wantErr)
+		}
+	}
+}
+
+func TestUnconfiguredSchedules(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Schedules: []types.ScheduleHint{
+			{Name: "backup", Schedule: "0 2 * * *", Source: "crontab:1"},
+			{Name: "report", Schedule: "@daily", Source: "app.py"},
+			{Name: "cleanup", Schedule: "*/15 * * * *", Command: []string{"/bin/sh", "-c", "./cleanup.sh --old"}, Source: "crontab:2"},
+			{Name: "digest", Schedule: "0 8 * * 1", Source: "mailer.js"},
+			{Name: "sync", Schedule: "0 * * * *", Command: []string{"/bin/sh", "-c", "./sync.sh"}, Source: "crontab:3"},
+		},
+		AppConfig: &types.AppConfigContext{Jobs: []types.JobContext{
+			{Name: "nightly-backup", Command: []string{"./backup"}, Schedule: "0 2 * * *"},
+			// Rescheduled, matched by command
+			{Name: "purge", Command: []string{"./cleanup.sh", "--old"}, Schedule: "0 * * * 0"},
+			// Rescheduled, matched by name
+			{Name: "digest", Command: []string{"node", "mailer.js"}, Schedule: "0 9 * * 1"},
+		}},
+	}
+	var names []string
+	for _, hint := range UnconfiguredSchedules(analysis) {
+		names = append(names, hint.Name)
+	}
+	if want := []string{"report", "sync"}; !slices.Equal(names, want) {
+		t.Errorf("UnconfiguredSchedules() = %v, want %v", names, want)
+	}
+}
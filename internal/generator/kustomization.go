@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// KustomizationFile indexes the generated resources for kubectl apply -k
+const KustomizationFile = "kustomization.yaml"
+
+// Kustomization represents a kustomize Kustomization
+type Kustomization struct {
+	APIVersion string               `json:"apiVersion"`
+	Kind       string               `json:"kind"`
+	Resources  []string             `json:"resources"`
+	Labels     []KustomizationLabel `json:"labels,omitempty"`
+}
+
+// KustomizationLabel adds labels to every resource. Selectors are left
+// alone, since they are immutable on existing Deployments.
+type KustomizationLabel struct {
+	Pairs            map[string]string `json:"pairs"`
+	IncludeSelectors bool              `json:"includeSelectors"`
+}
+
+// GenerateKustomization lists the Kubernetes objects among files. Files
+// outside the output directory and the ArgoCD Application, which points at
+// this directory, are left out.
+func GenerateKustomization(analysis *types.AppAnalysis, files []GeneratedFile, cfg *config.Config) (string, error) {
+	var resources []string
+	for _, f := range files {
+		if IsManifest(f) {
+			resources = append(resources, f.Path)
+		}
+	}
+	sort.Strings(resources)
+	k := Kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Resources:  resources,
+		Labels:     []KustomizationLabel{{Pairs: buildLabelsWithAppConfig(analysis, cfg)}},
+	}
+	return toYAML(k)
+}
+
+// IsManifest reports whether f holds Kubernetes objects to apply with the
+// app: YAML with a kind, in the output directory, other than the ArgoCD
+// Application, OpenSLO documents, and the kustomization itself
+func IsManifest(f GeneratedFile) bool {
+	switch {
+	case strings.HasPrefix(f.Path, "../"), strings.HasPrefix(f.Path, "argocd/"), f.Path == OpenSLOFile, f.Path == KustomizationFile:
+		return false
+	}
+	if ext := strings.ToLower(filepath.Ext(f.Path)); ext != ".yaml" && ext != ".yml" {
+		return false
+	}
+	return strings.HasPrefix(f.Content, "kind:") || strings.Contains(f.Content, "\nkind:")
+}
+
+// joinManifests replaces the manifests among files with one multi-document
+// file at path, placed where the first manifest was
+func joinManifests(files []GeneratedFile, path string) []GeneratedFile {
+	var joined []GeneratedFile
+	var docs []string
+	at := -1
+	for _, f := range files {
+		if !IsManifest(f) {
+			joined = append(joined, f)
+			continue
+		}
+		if at < 0 {
+			at = len(joined)
+			joined = append(joined, GeneratedFile{Path: path})
+		}
+		docs = append(docs, strings.TrimSuffix(f.Content, "\n")+"\n")
+	}
+	if at >= 0 {
+		joined[at].Content = strings.Join(docs, "---\n")
+	}
+	return joined
+}
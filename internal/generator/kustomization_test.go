@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestGenerateKustomization(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:    "orders",
+		Ports:   []types.Port{{Port: 8080}},
+		Scaling: &types.ScalingConfig{MinReplicas: 2, MaxReplicas: 4, TargetCPU: 70},
+	}
+	files, err := Generate(context.Background(), analysis, Options{Namespace: "default", Config: config.Default(), NoLLMPersona: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var k Kustomization
+	if err := yaml.Unmarshal([]byte(files[len(files)-1].Content), &k); err != nil {
+		t.Fatal(err)
+	}
+	if files[len(files)-1].Path != KustomizationFile {
+		t.Fatalf("last file = %s, want %s", files[len(files)-1].Path, KustomizationFile)
+	}
+	want := []string{"deployment.yaml", "hpa.yaml", "ingress.yaml", "persona.yaml", "service.yaml"}
+	if !reflect.DeepEqual(k.Resources, want) {
+		t.Errorf("resources = %v, want %v", k.Resources, want)
+	}
+	if len(k.Labels) != 1 || k.Labels[0].IncludeSelectors || k.Labels[0].Pairs["app.kubernetes.io/name"] != "orders" {
+		t.Errorf("labels = %+v", k.Labels)
+	}
+}
+
+func TestIsManifest(t *testing.T) {
+	for _, tt := range []struct {
+		file GeneratedFile
+		want bool
+	}{
+		{GeneratedFile{Path: "deployment.yaml", Content: "apiVersion: apps/v1\nkind: Deployment\n"}, true},
+		{GeneratedFile{Path: "extra/crd.yml", Content: "kind: Thing\n"}, true},
+		{GeneratedFile{Path: "argocd/application.yaml", Content: "kind: Application\n"}, false},
+		{GeneratedFile{Path: "../.github/workflows/deploy.yaml", Content: "name: deploy\n"}, false},
+		{GeneratedFile{Path: "values.yaml", Content: "replicas: 2\n"}, false},
+		{GeneratedFile{Path: "NOTES.md", Content: "kind: none\n"}, false},
+	} {
+		if got := IsManifest(tt.file); got != tt.want {
+			t.Errorf("IsManifest(%s) = %v, want %v", tt.file.Path, got, tt.want)
+		}
+	}
+}
+
+func TestJoinManifests(t *testing.T) {
+	files := []GeneratedFile{
+		{Path: "deployment.yaml", Content: "kind: Deployment\n"},
+		{Path: "../PERSONA.md", Content: "# orders\n"},
+		{Path: "service.yaml", Content: "kind: Service"},
+		{Path: "argocd/application.yaml", Content: "kind: Application\n"},
+	}
+	want := []GeneratedFile{
+		{Path: "manifests.yaml", Content: "kind: Deployment\n---\nkind: Service\n"},
+		{Path: "../PERSONA.md", Content: "# orders\n"},
+		{Path: "argocd/application.yaml", Content: "kind: Application\n"},
+	}
+	if got := joinManifests(files, "manifests.yaml"); !reflect.DeepEqual(got, want) {
+		t.Errorf("joinManifests() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// ValidateLabel checks a label key and value against the Kubernetes rules:
+// an optional DNS subdomain prefix, a name of at most 63 characters, and a
+// value of at most 63 alphanumerics, '-', '_' or '.'
+func ValidateLabel(key, value string) error {
+	if err := ValidateMetadataKey(key); err != nil {
+		return err
+	}
+	if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		return fmt.Errorf("invalid value %q: %s", value, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ValidateMetadataKey checks a label or annotation key
+func ValidateMetadataKey(key string) error {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("invalid key %q: %s", key, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// invalidLabelChars matches runs of characters not allowed in label values
+var invalidLabelChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// labelValue turns a free-form value (a team or environment name) into a
+// valid label value: invalid characters become '-', and the result is cut
+// to 63 characters and trimmed to start and end with an alphanumeric
+func labelValue(s string) string {
+	v := invalidLabelChars.ReplaceAllString(strings.TrimSpace(s), "-")
+	if len(v) > validation.LabelValueMaxLength {
+		v = v[:validation.LabelValueMaxLength]
+	}
+	return strings.Trim(v, "-_.")
+}
+
+// checkMetadata validates the custom labels and annotations of the org and
+// app config, naming the config entry of each invalid one
+func checkMetadata(analysis *types.AppAnalysis, cfg *config.Config) error {
+	var errs []error
+	labels := func(source, prefix string, m map[string]string) {
+		m = trimmedMetadata(m)
+		for _, k := range sortedMapKeys(m) {
+			if err := ValidateLabel(k, m[k]); err != nil {
+				errs = append(errs, fmt.Errorf("%s%s in the %s: %w", prefix, k, source, err))
+			}
+		}
+	}
+	annotations := func(source, prefix string, m map[string]string) {
+		m = trimmedMetadata(m)
+		for _, k := range sortedMapKeys(m) {
+			if err := ValidateMetadataKey(k); err != nil {
+				errs = append(errs, fmt.Errorf("%s%s in the %s: %w", prefix, k, source, err))
+			}
+		}
+	}
+	labels("org .dorgu.yaml", "labels.custom.", cfg.Labels.Custom)
+	annotations("org .dorgu.yaml", "annotations.custom.", cfg.Annotations.Custom)
+	if analysis.AppConfig != nil {
+		labels("app .dorgu.yaml", "labels.", analysis.AppConfig.Labels)
+		annotations("app .dorgu.yaml", "annotations.", analysis.AppConfig.Annotations)
+	}
+	return errors.Join(errs...)
+}
+
+// trimmedMetadata returns m with surrounding whitespace removed from keys
+// and values
+func trimmedMetadata(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return out
+}
+
+func sortedMapKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestValidateLabel(t *testing.T) {
+	tests := []struct {
+		key, value string
+		wantErr    bool
+	}{
+		{"app.kubernetes.io/name", "orders", false},
+		{"company.com/team", "", false},
+		{"cost center", "eng", true},
+		{strings.Repeat("a", 64), "x", true},
+		{"Bad_Prefix/team", "x", true},
+		{"team", "front end", true},
+		{"team", strings.Repeat("a", 64), true},
+		{"team", "-platform", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateLabel(tt.key, tt.value); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateLabel(%q, %q) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLabelValue(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"platform", "platform"},
+		{"Platform Team", "Platform-Team"},
+		{" payments/core ", "payments-core"},
+		{"_eng_", "eng"},
+		{strings.Repeat("a", 70), strings.Repeat("a", 63)},
+	}
+	for _, tt := range tests {
+		if got := labelValue(tt.in); got != tt.want {
+			t.Errorf("labelValue(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCheckMetadata(t *testing.T) {
+	cfg := config.Default()
+	cfg.Labels.Custom = map[string]string{" company.com/tier ": " gold ", "cost center": "eng"}
+	cfg.Annotations.Custom = map[string]string{"prometheus.io/scrape": "true"}
+	analysis := &types.AppAnalysis{Name: "orders", AppConfig: &types.AppConfigContext{
+		Labels:      map[string]string{"tier": "front end"},
+		Annotations: map[string]string{"-bad": "x"},
+	}}
+
+	err := checkMetadata(analysis, cfg)
+	if err == nil {
+		t.Fatal("checkMetadata() = nil, want errors")
+	}
+	msg := err.Error()
+	for _, want := range []string{"labels.custom.cost center in the org .dorgu.yaml", "labels.tier in the app .dorgu.yaml", "annotations.-bad in the app .dorgu.yaml"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error %q does not mention %q", msg, want)
+		}
+	}
+	if strings.Contains(msg, "company.com/tier") {
+		t.Errorf("surrounding whitespace should be trimmed, got %q", msg)
+	}
+
+	labels := buildLabelsWithAppConfig(&types.AppAnalysis{Name: "orders", Team: "Platform Team"}, cfg)
+	if labels["company.com/tier"] != "gold" || labels["app.kubernetes.io/team"] != "Platform-Team" {
+		t.Errorf("labels = %v, want trimmed custom label and normalized team", labels)
+	}
+}
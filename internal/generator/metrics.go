@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// metricsPortName names the metrics port on the container and Service
+const metricsPortName = "metrics"
+
+// ServiceMonitorManifest represents a Prometheus Operator ServiceMonitor
+type ServiceMonitorManifest struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Metadata   Metadata           `json:"metadata"`
+	Spec       ServiceMonitorSpec `json:"spec"`
+}
+
+// ServiceMonitorSpec represents a ServiceMonitor spec
+type ServiceMonitorSpec struct {
+	Selector  LabelSelector            `json:"selector"`
+	Endpoints []ServiceMonitorEndpoint `json:"endpoints"`
+}
+
+// ServiceMonitorEndpoint represents a scraped Service port
+type ServiceMonitorEndpoint struct {
+	Port string `json:"port"`
+	Path string `json:"path,omitempty"`
+}
+
+// metricsEndpoint is where the app serves Prometheus metrics
+type metricsEndpoint struct {
+	Path string
+	Port int
+	// Shared is set when the metrics are served on the app's HTTP port,
+	// which keeps its name
+	Shared bool
+}
+
+// resolveMetrics returns the app's metrics endpoint, or nil when none was
+// detected. A port whose purpose mentions metrics wins; otherwise a metrics
+// path detected in the code is served on the org's metrics.port when set,
+// else on the app's HTTP port, where the code serves it.
+func resolveMetrics(analysis *types.AppAnalysis, cfg *config.Config) *metricsEndpoint {
+	m := &metricsEndpoint{}
+	if analysis.Code != nil {
+		m.Path = analysis.Code.MetricsPath
+	}
+	for _, p := range analysis.Ports {
+		if strings.Contains(strings.ToLower(p.Purpose), "metric") {
+			m.Port = p.Port
+			break
+		}
+	}
+	if m.Path == "" && m.Port == 0 {
+		return nil
+	}
+	if m.Path == "" {
+		m.Path = "/metrics"
+	}
+	switch {
+	case m.Port > 0:
+	case cfg != nil && cfg.Metrics.Port > 0:
+		m.Port = cfg.Metrics.Port
+	case len(analysis.Ports) > 0:
+		m.Port, m.Shared = ingressHTTPPort(analysis), true
+	default:
+		return nil
+	}
+	return m
+}
+
+// namedPort is a container port with its name on the container and Service
+type namedPort struct {
+	Name string
+	Port int
+}
+
+// appPorts returns the ports exposed by the container and Service: the
+// analyzed ports, with a separate metrics port named "metrics" and appended
+// when the app does not already expose it
+func appPorts(analysis *types.AppAnalysis, cfg *config.Config) []namedPort {
+	metrics := resolveMetrics(analysis, cfg)
+	if metrics != nil && metrics.Shared {
+		metrics = nil
+	}
+	var ports []namedPort
+	found := false
+	for i, p := range analysis.Ports {
+		name := fmt.Sprintf("port-%d", i)
+		if metrics != nil && p.Port == metrics.Port && !found {
+			name = metricsPortName
+			found = true
+		}
+		ports = append(ports, namedPort{Name: name, Port: p.Port})
+	}
+	if metrics != nil && !found {
+		ports = append(ports, namedPort{Name: metricsPortName, Port: metrics.Port})
+	}
+	return ports
+}
+
+// withMetricsAnnotations returns annotations plus the prometheus.io scrape
+// annotations when the org scrapes by annotation. Keys already set by org or
+// app config are kept.
+func withMetricsAnnotations(annotations map[string]string, analysis *types.AppAnalysis, cfg *config.Config) map[string]string {
+	metrics := resolveMetrics(analysis, cfg)
+	if metrics == nil || cfg == nil || cfg.Metrics.Scrape != config.MetricsScrapeAnnotations {
+		return annotations
+	}
+	merged := map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   strconv.Itoa(metrics.Port),
+		"prometheus.io/path":   metrics.Path,
+	}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	return merged
+}
+
+// hasServiceMonitor reports whether Generate emits a ServiceMonitor
+func hasServiceMonitor(analysis *types.AppAnalysis, cfg *config.Config) bool {
+	return cfg != nil && cfg.Metrics.Scrape == config.MetricsScrapeServiceMonitor && resolveMetrics(analysis, cfg) != nil
+}
+
+// GenerateServiceMonitor generates a ServiceMonitor scraping the metrics port
+func GenerateServiceMonitor(analysis *types.AppAnalysis, namespace string, cfg *config.Config) (string, error) {
+	metrics := resolveMetrics(analysis, cfg)
+	if metrics == nil {
+		return "", fmt.Errorf("no metrics endpoint detected for %s", analysis.Name)
+	}
+	monitor := ServiceMonitorManifest{
+		APIVersion: "monitoring.coreos.com/v1",
+		Kind:       "ServiceMonitor",
+		Metadata: Metadata{
+			Name:      analysis.Name,
+			Namespace: namespace,
+			Labels:    buildLabelsWithAppConfig(analysis, cfg),
+		},
+		Spec: ServiceMonitorSpec{
+			Selector: LabelSelector{
+				MatchLabels: map[string]string{
+					"app.kubernetes.io/name": analysis.Name,
+				},
+			},
+			Endpoints: []ServiceMonitorEndpoint{{Port: metricsServicePort(analysis, cfg, metrics), Path: metrics.Path}},
+		},
+	}
+	return toYAML(monitor)
+}
+
+// metricsServicePort returns the name of the Service port metrics are
+// scraped from: the HTTP port's when they share it
+func metricsServicePort(analysis *types.AppAnalysis, cfg *config.Config, metrics *metricsEndpoint) string {
+	if metrics.Shared {
+		for _, p := range appPorts(analysis, cfg) {
+			if p.Port == metrics.Port {
+				return p.Name
+			}
+		}
+	}
+	return metricsPortName
+}
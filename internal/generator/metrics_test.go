@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestAppPorts(t *testing.T) {
+	tests := []struct {
+		name    string
+		ports   []types.Port
+		metrics string
+		// orgPort is the org's metrics.port
+		orgPort int
+		want    []namedPort
+	}{
+		{
+			name:  "no metrics endpoint",
+			ports: []types.Port{{Port: 8080}},
+			want:  []namedPort{{Name: "port-0", Port: 8080}},
+		},
+		{
+			name:    "detected path is served on the HTTP port",
+			ports:   []types.Port{{Port: 8080}},
+			metrics: "/metrics",
+			want:    []namedPort{{Name: "port-0", Port: 8080}},
+		},
+		{
+			name:    "detected path adds the org's port",
+			ports:   []types.Port{{Port: 8080}},
+			metrics: "/metrics",
+			orgPort: 9090,
+			want:    []namedPort{{Name: "port-0", Port: 8080}, {Name: "metrics", Port: 9090}},
+		},
+		{
+			name:  "metrics purpose port is renamed",
+			ports: []types.Port{{Port: 8080}, {Port: 9100, Purpose: "Prometheus metrics"}},
+			want:  []namedPort{{Name: "port-0", Port: 8080}, {Name: "metrics", Port: 9100}},
+		},
+		{
+			name:    "exposed org port is reused",
+			ports:   []types.Port{{Port: 9090}},
+			metrics: "/metrics",
+			orgPort: 9090,
+			want:    []namedPort{{Name: "metrics", Port: 9090}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := &types.AppAnalysis{Ports: tt.ports, Code: &types.CodeAnalysis{MetricsPath: tt.metrics}}
+			cfg := config.Default()
+			cfg.Metrics.Port = tt.orgPort
+			if got := appPorts(analysis, cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("appPorts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithMetricsAnnotations(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Ports: []types.Port{{Port: 8080}},
+		Code:  &types.CodeAnalysis{MetricsPath: "/metrics"},
+	}
+	cfg := config.Default()
+	if got := withMetricsAnnotations(nil, analysis, cfg); got != nil {
+		t.Errorf("annotations without scrape = %v, want nil", got)
+	}
+
+	cfg.Metrics.Scrape = config.MetricsScrapeAnnotations
+	if got := withMetricsAnnotations(nil, analysis, cfg); got["prometheus.io/port"] != "8080" {
+		t.Errorf("annotations = %v, want the HTTP port 8080", got)
+	}
+
+	cfg.Metrics.Scrape = config.MetricsScrapeAnnotations
+	cfg.Metrics.Port = 9102
+	got := withMetricsAnnotations(map[string]string{"prometheus.io/scrape": "false"}, analysis, cfg)
+	want := map[string]string{
+		"prometheus.io/scrape": "false",
+		"prometheus.io/port":   "9102",
+		"prometheus.io/path":   "/metrics",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("annotations = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateServiceMonitor(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:  "api",
+		Ports: []types.Port{{Port: 8080}},
+		Code:  &types.CodeAnalysis{MetricsPath: "/metrics"},
+	}
+	cfg := config.Default()
+	cfg.Metrics.Scrape = config.MetricsScrapeServiceMonitor
+	files, err := Generate(context.Background(), analysis, Options{Namespace: "default", Config: cfg, SkipArgoCD: true, SkipCI: true, SkipPersona: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var monitor string
+	for _, f := range files {
+		if f.Path == "servicemonitor.yaml" {
+			monitor = f.Content
+		}
+	}
+	for _, want := range []string{"kind: ServiceMonitor", "port: port-0", "path: /metrics", "app.kubernetes.io/name: api"} {
+		if !strings.Contains(monitor, want) {
+			t.Errorf("servicemonitor.yaml missing %q:\n%s", want, monitor)
+		}
+	}
+
+	// A metrics port in the org config gets its own Service port
+	cfg.Metrics.Port = 9090
+	monitor, err = GenerateServiceMonitor(analysis, "default", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(monitor, "port: metrics") {
+		t.Errorf("servicemonitor.yaml does not scrape the metrics port:\n%s", monitor)
+	}
+}
@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// migrationJobName names the job that applies migrations
+const migrationJobName = "migrate"
+
+// envRefPattern matches the $(VAR) references Kubernetes expands in a
+// container's command from its environment
+var envRefPattern = regexp.MustCompile(`\$\((\w+)\)`)
+
+// UnconfiguredMigrations returns the analysis's migrations when nothing
+// applies them: the app does not as it starts, the app config has no job
+// taken to, and cfg does not generate one, or nil
+func UnconfiguredMigrations(analysis *types.AppAnalysis, cfg *config.Config) *types.Migrations {
+	if MigrationJob(analysis, cfg) != nil {
+		return nil
+	}
+	return pendingMigrations(analysis)
+}
+
+// MigrationJob returns the pre-deploy Job applying the analysis's
+// migrations with the tool's command, or nil. Only migrations.job in cfg
+// generates it, as the hook blocks every rollout until it succeeds, and
+// tools whose CLI the app's image lacks also need migrations.image.
+func MigrationJob(analysis *types.AppAnalysis, cfg *config.Config) *types.JobContext {
+	m := pendingMigrations(analysis)
+	if m == nil || cfg == nil || !cfg.Migrations.Job || m.Image != "" && cfg.Migrations.Image == "" {
+		return nil
+	}
+	return &types.JobContext{Name: migrationJobName, Command: m.Command, Image: cfg.Migrations.Image, Hook: config.HookPreDeploy}
+}
+
+// pendingMigrations returns the analysis's migrations when the app does not
+// apply them as it starts and the app config has no job taken to, or nil
+func pendingMigrations(analysis *types.AppAnalysis) *types.Migrations {
+	m := analysis.Migrations
+	if m == nil || m.OnStartup || len(m.Command) == 0 || configuredMigrationJob(analysis) != nil {
+		return nil
+	}
+	return m
+}
+
+// configuredMigrationJob returns the app config's job taken to run the
+// migrations: the one named migrate, else the first pre-deploy job
+func configuredMigrationJob(analysis *types.AppAnalysis) *types.JobContext {
+	if analysis.AppConfig == nil {
+		return nil
+	}
+	var preDeploy *types.JobContext
+	for i, job := range analysis.AppConfig.Jobs {
+		if job.Name == migrationJobName {
+			return &analysis.AppConfig.Jobs[i]
+		}
+		if job.Hook == config.HookPreDeploy && preDeploy == nil {
+			preDeploy = &analysis.AppConfig.Jobs[i]
+		}
+	}
+	return preDeploy
+}
+
+// EmbedMigrations adds a Database Migrations section before the Health &
+// Monitoring section of a PERSONA.md document, or at its end, saying when
+// the app's migrations run relative to its pods starting. Documents that
+// already have the section are returned as is.
+func EmbedMigrations(markdown string, analysis *types.AppAnalysis, cfg *config.Config) string {
+	const heading = "## Database Migrations"
+	m := analysis.Migrations
+	if m == nil || strings.Contains(markdown, heading+"\n") {
+		return markdown
+	}
+	var b strings.Builder
+	b.WriteString(heading + "\n\n")
+	fmt.Fprintf(&b, "- **Tool:** %s (%s)\n", m.Tool, m.Source)
+
+	job := configuredMigrationJob(analysis)
+	if job == nil {
+		job = MigrationJob(analysis, cfg)
+	}
+	switch {
+	case m.OnStartup:
+		b.WriteString("- **When:** as the app starts, before it serves\n")
+		fmt.Fprintf(&b, "- **Startup ordering:** each new pod applies pending migrations before it becomes ready. Replicas starting together wait on %s's lock, so a rollout after a long migration is slow; keep the liveness probe's initial delay above the longest migration or pods are restarted mid-migration. Old pods serve against the migrated schema until they are replaced, so migrations must stay backward compatible for one release.\n", m.Tool)
+	case job != nil && job.Hook == config.HookPreDeploy:
+		fmt.Fprintf(&b, "- **When:** before each rollout, in the pre-deploy Job `jobs/%s.yaml` (`%s`)\n", job.Name, strings.Join(job.Command, " "))
+		b.WriteString("- **Startup ordering:** ArgoCD (PreSync) and Helm (pre-install/pre-upgrade hooks) run the Job to completion before the new pods start, and a failed migration stops the rollout. `kubectl apply` applies the Job and the Deployment together, so apply the Job and wait for it first. Old pods serve against the migrated schema until they are replaced, so migrations must stay backward compatible for one release.\n")
+	case job != nil:
+		fmt.Fprintf(&b, "- **When:** once, when `jobs/%s.yaml` is first applied (`%s`)\n", job.Name, strings.Join(job.Command, " "))
+		b.WriteString("- **Startup ordering:** the Job does not run again on later rollouts, and nothing holds the pods back until it finishes. Set `hook: pre-deploy` on it in `.dorgu.yaml` to migrate before each rollout.\n")
+	default:
+		fmt.Fprintf(&b, "- **When:** by hand, with `%s`; no job runs them yet\n", strings.Join(m.Command, " "))
+		b.WriteString("- **Startup ordering:** nothing holds the new pods back until the schema is migrated. To migrate before each rollout, set `migrations.job: true` in the workspace `.dorgu.yaml`, or add a pre-deploy job to the app's `.dorgu.yaml`:\n\n")
+		b.WriteString("```yaml\n" + migrationJobSnippet(m) + "```\n")
+	}
+	section := b.String()
+
+	if i := strings.Index(markdown, "## Health & Monitoring\n"); i >= 0 {
+		return markdown[:i] + section + "\n" + markdown[i:]
+	}
+	return strings.TrimRight(markdown, "\n") + "\n\n" + section
+}
+
+// migrationJobSnippet returns the .dorgu.yaml jobs entry that applies m
+// before each rollout. Tools whose CLI the app's image lacks get an image
+// placeholder, and environment variables the command reads are noted.
+func migrationJobSnippet(m *types.Migrations) string {
+	var b strings.Builder
+	b.WriteString("jobs:\n")
+	fmt.Fprintf(&b, "  - name: %s\n", migrationJobName)
+	fmt.Fprintf(&b, "    hook: %s\n", config.HookPreDeploy)
+	if m.Image != "" {
+		fmt.Fprintf(&b, "    image: <built FROM %s with the migrations>\n", m.Image)
+	}
+	fmt.Fprintf(&b, "    command: %s\n", quotedCommand(m.Command))
+	for _, ref := range envRefPattern.FindAllStringSubmatch(strings.Join(m.Command, " "), -1) {
+		fmt.Fprintf(&b, "    # %s must be set in the app's env or secrets\n", ref[1])
+	}
+	return b.String()
+}
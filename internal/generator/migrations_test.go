@@ -0,0 +1,161 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestUnconfiguredMigrations(t *testing.T) {
+	alembic := &types.Migrations{Tool: "alembic", Source: "alembic.ini", Command: []string{"alembic", "upgrade", "head"}}
+	tests := []struct {
+		name       string
+		migrations *types.Migrations
+		jobs       []types.JobContext
+		want       bool
+	}{
+		{"detected", alembic, nil, true},
+		{"none", nil, nil, false},
+		{"on startup", &types.Migrations{Tool: "flyway", Source: "pom.xml", OnStartup: true}, nil, false},
+		{"configured migrate job", alembic, []types.JobContext{{Name: "migrate", Command: []string{"make", "migrate"}}}, false},
+		{"configured pre-deploy job", alembic, []types.JobContext{{Name: "db-upgrade", Command: []string{"x"}, Hook: config.HookPreDeploy}}, false},
+		{"other jobs", alembic, []types.JobContext{{Name: "seed", Command: []string{"./seed"}}}, true},
+	}
+	for _, tt := range tests {
+		analysis := &types.AppAnalysis{Name: "orders", Migrations: tt.migrations, AppConfig: &types.AppConfigContext{Jobs: tt.jobs}}
+		if got := UnconfiguredMigrations(analysis, config.Default()); (got != nil) != tt.want {
+			t.Errorf("%s: UnconfiguredMigrations() = %+v, want migrations %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Detection alone generates no Job
+	analysis := &types.AppAnalysis{Name: "orders", Migrations: alembic}
+	cfg := config.Default()
+	files, err := GenerateJobs(analysis, "default", cfg.Resources.Defaults, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("GenerateJobs() files = %+v, want none", files)
+	}
+}
+
+func TestMigrationJob(t *testing.T) {
+	alembic := &types.Migrations{Tool: "alembic", Source: "alembic.ini", Command: []string{"alembic", "upgrade", "head"}}
+	flyway := &types.Migrations{Tool: "flyway", Source: "flyway.conf", Command: []string{"flyway", "migrate"}, Image: "flyway/flyway"}
+	tests := []struct {
+		name       string
+		migrations *types.Migrations
+		migrate    config.MigrationsConfig
+		want       bool
+	}{
+		{"not enabled", alembic, config.MigrationsConfig{}, false},
+		{"enabled", alembic, config.MigrationsConfig{Job: true}, true},
+		{"tool missing from the app's image", flyway, config.MigrationsConfig{Job: true}, false},
+		{"tool image set", flyway, config.MigrationsConfig{Job: true, Image: "ghcr.io/acme/orders-migrations:1.0"}, true},
+	}
+	for _, tt := range tests {
+		cfg := config.Default()
+		cfg.Migrations = tt.migrate
+		analysis := &types.AppAnalysis{Name: "orders", Migrations: tt.migrations}
+		if got := MigrationJob(analysis, cfg); (got != nil) != tt.want {
+			t.Errorf("%s: MigrationJob() = %+v, want job %v", tt.name, got, tt.want)
+		}
+		if got := UnconfiguredMigrations(analysis, cfg); (got != nil) == tt.want {
+			t.Errorf("%s: UnconfiguredMigrations() = %+v with job %v", tt.name, got, tt.want)
+		}
+	}
+
+	cfg := config.Default()
+	cfg.Migrations = config.MigrationsConfig{Job: true, Image: "ghcr.io/acme/orders-migrations:1.0"}
+	analysis := &types.AppAnalysis{Name: "orders", Migrations: flyway}
+	files, err := GenerateJobs(analysis, "default", cfg.Resources.Defaults, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Path != "jobs/migrate.yaml" {
+		t.Fatalf("GenerateJobs() files = %+v, want jobs/migrate.yaml", files)
+	}
+	for _, s := range []string{"argocd.argoproj.io/hook: PreSync", "image: ghcr.io/acme/orders-migrations:1.0", "- flyway", "- migrate"} {
+		if !strings.Contains(files[0].Content, s) {
+			t.Errorf("jobs/migrate.yaml lacks %q:\n%s", s, files[0].Content)
+		}
+	}
+}
+
+func TestValidateMigrations(t *testing.T) {
+	analysis := &types.AppAnalysis{Name: "orders", Migrations: &types.Migrations{
+		Tool: "golang-migrate", Source: "db/migrations/1_init.up.sql", Image: "migrate/migrate",
+		Command: []string{"migrate", "-path", "db/migrations", "-database", "$(DATABASE_URL)", "up"},
+	}}
+	result := &ValidationResult{}
+	validateMigrations(analysis, config.Default(), result)
+	if len(result.Issues) != 1 {
+		t.Fatalf("validateMigrations() issues = %+v, want 1", result.Issues)
+	}
+	issue := result.Issues[0]
+	if issue.Rule != RuleMigrationsUnconfigured || issue.Severity != SeverityInfo || !strings.Contains(issue.Message, "db/migrations/1_init.up.sql") {
+		t.Errorf("issue = %+v", issue)
+	}
+	for _, s := range []string{`with ["migrate", "-path", "db/migrations", "-database", "$(DATABASE_URL)", "up"], set migrations.job: true`, "migrations.image to an image built FROM migrate/migrate", "name migrate, hook pre-deploy", "reads DATABASE_URL"} {
+		if !strings.Contains(issue.Suggestion, s) {
+			t.Errorf("suggestion lacks %q: %s", s, issue.Suggestion)
+		}
+	}
+
+	analysis.AppConfig = &types.AppConfigContext{Jobs: []types.JobContext{{Name: "migrate", Command: []string{"make", "migrate"}, Hook: config.HookPreDeploy}}}
+	result = &ValidationResult{}
+	validateMigrations(analysis, config.Default(), result)
+	if len(result.Issues) != 0 {
+		t.Errorf("validateMigrations() issues = %+v with a migrate job", result.Issues)
+	}
+}
+
+func TestEmbedMigrations(t *testing.T) {
+	const doc = "# orders\n\n## Health & Monitoring\n\nProbes.\n"
+	tests := []struct {
+		name       string
+		migrations *types.Migrations
+		jobs       []types.JobContext
+		job        bool
+		want       []string
+	}{
+		{"no job", &types.Migrations{Tool: "alembic", Source: "alembic.ini", Command: []string{"alembic", "upgrade", "head"}}, nil, false,
+			[]string{"set `migrations.job: true`", "- **Tool:** alembic (alembic.ini)", "by hand, with `alembic upgrade head`", "```yaml\njobs:\n  - name: migrate\n    hook: pre-deploy\n    command: [\"alembic\", \"upgrade\", \"head\"]\n```"}},
+		{"no job, official image", &types.Migrations{Tool: "flyway", Source: "flyway.conf", Command: []string{"flyway", "migrate"}, Image: "flyway/flyway"}, nil, false,
+			[]string{"    image: <built FROM flyway/flyway with the migrations>\n"}},
+		{"pre-deploy job", &types.Migrations{Tool: "alembic", Source: "alembic.ini", Command: []string{"alembic", "upgrade", "head"}},
+			[]types.JobContext{{Name: "db-upgrade", Command: []string{"alembic", "upgrade", "head"}, Hook: config.HookPreDeploy}}, false,
+			[]string{"pre-deploy Job `jobs/db-upgrade.yaml` (`alembic upgrade head`)", "before the new pods start"}},
+		{"generated job", &types.Migrations{Tool: "alembic", Source: "alembic.ini", Command: []string{"alembic", "upgrade", "head"}}, nil, true,
+			[]string{"pre-deploy Job `jobs/migrate.yaml` (`alembic upgrade head`)"}},
+		{"on startup", &types.Migrations{Tool: "flyway", Source: "pom.xml", OnStartup: true}, nil, false,
+			[]string{"as the app starts", "wait on flyway's lock", "liveness probe"}},
+		{"configured one-off job", &types.Migrations{Tool: "prisma", Source: "prisma/migrations/migration_lock.toml", Command: []string{"npx", "prisma", "migrate", "deploy"}},
+			[]types.JobContext{{Name: "migrate", Command: []string{"npm", "run", "migrate"}}}, true,
+			[]string{"once, when `jobs/migrate.yaml` is first applied (`npm run migrate`)", "hook: pre-deploy"}},
+	}
+	for _, tt := range tests {
+		analysis := &types.AppAnalysis{Name: "orders", Migrations: tt.migrations, AppConfig: &types.AppConfigContext{Jobs: tt.jobs}}
+		cfg := config.Default()
+		cfg.Migrations.Job = tt.job
+		got := EmbedMigrations(doc, analysis, cfg)
+		if !strings.Contains(got, "## Database Migrations\n\n") || strings.Index(got, "## Database Migrations") > strings.Index(got, "## Health & Monitoring") {
+			t.Errorf("%s: section missing or misplaced:\n%s", tt.name, got)
+		}
+		for _, s := range tt.want {
+			if !strings.Contains(got, s) {
+				t.Errorf("%s: missing %q in:\n%s", tt.name, s, got)
+			}
+		}
+		if again := EmbedMigrations(got, analysis, cfg); again != got {
+			t.Errorf("%s: section added twice:\n%s", tt.name, again)
+		}
+	}
+
+	if got := EmbedMigrations(doc, &types.AppAnalysis{Name: "orders"}, config.Default()); got != doc {
+		t.Errorf("section added without migrations:\n%s", got)
+	}
+}
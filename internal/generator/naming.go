@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// invalidNameChars matches runs of characters not allowed in DNS-1123 labels
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// nameHashLen is the length of the hash suffix of truncated names
+const nameHashLen = 6
+
+// ResourceName turns an app name into a valid DNS-1123 label, the strictest
+// name rule among the resources dorgu generates (Services): lowercase
+// alphanumerics and '-', at most 63 characters. Longer names are truncated
+// and suffixed with a hash of the original so distinct names stay distinct.
+func ResourceName(name string) string {
+	n := invalidNameChars.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "-")
+	n = strings.Trim(n, "-")
+	if len(n) > validation.DNS1123LabelMaxLength {
+		sum := sha256.Sum256([]byte(name))
+		n = strings.TrimRight(n[:validation.DNS1123LabelMaxLength-nameHashLen-1], "-") + "-" + hex.EncodeToString(sum[:])[:nameHashLen]
+	}
+	if n == "" {
+		return "app"
+	}
+	return n
+}
+
+// IsResourceName reports whether name is a valid DNS-1123 label
+func IsResourceName(name string) bool {
+	return len(validation.IsDNS1123Label(name)) == 0
+}
+
+// normalizeAppName renames the app to its DNS-safe resource name when
+// naming.dns_safe is on, keeping the name it had in OriginalName
+func normalizeAppName(analysis *types.AppAnalysis, cfg *config.Config) {
+	if cfg == nil || !cfg.Naming.DNSSafe || analysis.Name == "" || IsResourceName(analysis.Name) {
+		return
+	}
+	analysis.OriginalName = analysis.Name
+	analysis.Name = ResourceName(analysis.Name)
+}
+
+// NameCollisions groups apps, keyed by where they come from (e.g. their
+// config file), by resource name, returning the names that more than one app
+// would generate, e.g. My_App and my-app
+func NameCollisions(apps map[string]string) map[string][]string {
+	byName := map[string][]string{}
+	for source, name := range apps {
+		n := ResourceName(name)
+		byName[n] = append(byName[n], source)
+	}
+	for n, sources := range byName {
+		if len(sources) < 2 {
+			delete(byName, n)
+			continue
+		}
+		sort.Strings(sources)
+	}
+	return byName
+}
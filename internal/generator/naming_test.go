@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestResourceName(t *testing.T) {
+	long := strings.Repeat("service-", 10)
+	tests := []struct{ in, want string }{
+		{"orders", "orders"},
+		{"My_App", "my-app"},
+		{" Billing API ", "billing-api"},
+		{"--x--", "x"},
+		{"___", "app"},
+	}
+	for _, tt := range tests {
+		if got := ResourceName(tt.in); got != tt.want {
+			t.Errorf("ResourceName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	got := ResourceName(long)
+	if len(got) > 63 || !IsResourceName(got) {
+		t.Errorf("ResourceName(long) = %q (%d chars), want a valid name of at most 63", got, len(got))
+	}
+	if other := ResourceName(long + "x"); other == got {
+		t.Errorf("truncated names of different apps collide: %q", got)
+	}
+}
+
+func TestNormalizeAppName(t *testing.T) {
+	cfg := config.Default()
+	analysis := &types.AppAnalysis{Name: "My_App"}
+	normalizeAppName(analysis, cfg)
+	if analysis.Name != "my-app" || analysis.OriginalName != "My_App" {
+		t.Errorf("Name, OriginalName = %q, %q; want my-app, My_App", analysis.Name, analysis.OriginalName)
+	}
+	result := &ValidationResult{}
+	validateAppName(analysis, result)
+	if len(result.Issues) != 1 || result.Issues[0].Severity != SeverityError {
+		t.Errorf("issues = %+v, want one error for the renamed app", result.Issues)
+	}
+
+	cfg.Naming.DNSSafe = false
+	analysis = &types.AppAnalysis{Name: "My_App"}
+	normalizeAppName(analysis, cfg)
+	if analysis.Name != "My_App" || analysis.OriginalName != "" {
+		t.Errorf("dns_safe false: Name, OriginalName = %q, %q; want My_App unchanged", analysis.Name, analysis.OriginalName)
+	}
+}
+
+func TestNameCollisions(t *testing.T) {
+	got := NameCollisions(map[string]string{
+		"apps/a/.dorgu.yaml": "My_App",
+		"apps/b/.dorgu.yaml": "my-app",
+		"apps/c/.dorgu.yaml": "orders",
+	})
+	want := map[string][]string{"my-app": {"apps/a/.dorgu.yaml", "apps/b/.dorgu.yaml"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NameCollisions() = %v, want %v", got, want)
+	}
+}
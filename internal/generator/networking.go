@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"strconv"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// exposure returns the app's networking.expose setting, defaulting to
+// public, or to internal for workers unless their app config enables the
+// ingress: workers serve at most health and metrics
+func exposure(analysis *types.AppAnalysis) string {
+	if analysis.AppConfig != nil && analysis.AppConfig.Networking != nil && analysis.AppConfig.Networking.Expose != "" {
+		return analysis.AppConfig.Networking.Expose
+	}
+	if analysis.Type == "worker" && (analysis.AppConfig == nil || analysis.AppConfig.Ingress == nil || !analysis.AppConfig.Ingress.Enabled) {
+		return config.ExposeInternal
+	}
+	return config.ExposePublic
+}
+
+// hasIngress reports whether Generate emits an Ingress for the app: only
+// publicly exposed apps with an HTTP port get one
+func hasIngress(analysis *types.AppAnalysis) bool {
+	return exposure(analysis) == config.ExposePublic && len(analysis.Ports) > 0 && hasHTTPPort(analysis.Ports)
+}
+
+// PodDNSConfig represents a pod's dnsConfig
+type PodDNSConfig struct {
+	Searches []string             `json:"searches,omitempty"`
+	Options  []PodDNSConfigOption `json:"options,omitempty"`
+}
+
+// PodDNSConfigOption represents a resolver option
+type PodDNSConfigOption struct {
+	Name  string  `json:"name"`
+	Value *string `json:"value,omitempty"`
+}
+
+// HostAlias represents an /etc/hosts entry
+type HostAlias struct {
+	IP        string   `json:"ip"`
+	Hostnames []string `json:"hostnames"`
+}
+
+// appDNS returns the app's networking.dns settings, or nil
+func appDNS(analysis *types.AppAnalysis) *types.DNSContext {
+	if analysis.AppConfig == nil || analysis.AppConfig.Networking == nil {
+		return nil
+	}
+	return analysis.AppConfig.Networking.DNS
+}
+
+// podDNSConfig returns the pod dnsConfig for networking.dns ndots and
+// searches; they are merged with the cluster's settings under the default
+// ClusterFirst DNS policy
+func podDNSConfig(analysis *types.AppAnalysis) *PodDNSConfig {
+	dns := appDNS(analysis)
+	if dns == nil || (dns.Ndots == nil && len(dns.Searches) == 0) {
+		return nil
+	}
+	cfg := &PodDNSConfig{Searches: dns.Searches}
+	if dns.Ndots != nil {
+		ndots := strconv.Itoa(*dns.Ndots)
+		cfg.Options = append(cfg.Options, PodDNSConfigOption{Name: "ndots", Value: &ndots})
+	}
+	return cfg
+}
+
+// hostAliases returns the pod hostAliases for networking.dns.host_aliases
+func hostAliases(analysis *types.AppAnalysis) []HostAlias {
+	dns := appDNS(analysis)
+	if dns == nil {
+		return nil
+	}
+	var aliases []HostAlias
+	for _, a := range dns.HostAliases {
+		aliases = append(aliases, HostAlias{IP: a.IP, Hostnames: a.Hostnames})
+	}
+	return aliases
+}
@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestGenerateExposure(t *testing.T) {
+	tests := []struct {
+		expose      string
+		wantIngress bool
+		wantNone    bool
+	}{
+		{"", true, false},
+		{config.ExposePublic, true, false},
+		{config.ExposeInternal, false, false},
+		{config.ExposeHeadless, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expose, func(t *testing.T) {
+			analysis := &types.AppAnalysis{
+				Name:      "api",
+				Ports:     []types.Port{{Port: 8080}},
+				AppConfig: &types.AppConfigContext{Networking: &types.NetworkingContext{Expose: tt.expose}},
+			}
+			files, err := Generate(context.Background(), analysis, Options{Namespace: "default", Config: config.Default(), SkipArgoCD: true, SkipCI: true, SkipPersona: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var gotIngress bool
+			var service string
+			for _, f := range files {
+				switch f.Path {
+				case "ingress.yaml":
+					gotIngress = true
+				case "service.yaml":
+					service = f.Content
+				}
+			}
+			if gotIngress != tt.wantIngress {
+				t.Errorf("ingress generated = %v, want %v", gotIngress, tt.wantIngress)
+			}
+			if got := strings.Contains(service, "clusterIP: None"); got != tt.wantNone {
+				t.Errorf("headless service = %v, want %v:\n%s", got, tt.wantNone, service)
+			}
+		})
+	}
+}
+
+func TestWorkerExposure(t *testing.T) {
+	tests := []struct {
+		name      string
+		appConfig *types.AppConfigContext
+		want      string
+	}{
+		{"default", nil, config.ExposeInternal},
+		{"ingress enabled", &types.AppConfigContext{Ingress: &types.IngressContext{Enabled: true}}, config.ExposePublic},
+		{"expose set", &types.AppConfigContext{Networking: &types.NetworkingContext{Expose: config.ExposePublic}}, config.ExposePublic},
+	}
+	for _, tt := range tests {
+		analysis := &types.AppAnalysis{Name: "jobs", Type: "worker", Ports: []types.Port{{Port: 8080}}, AppConfig: tt.appConfig}
+		if got := exposure(analysis); got != tt.want {
+			t.Errorf("%s: exposure = %q, want %q", tt.name, got, tt.want)
+		}
+		if got := hasIngress(analysis); got != (tt.want == config.ExposePublic) {
+			t.Errorf("%s: hasIngress = %v", tt.name, got)
+		}
+	}
+}
+
+func TestPodDNS(t *testing.T) {
+	ndots := 2
+	analysis := &types.AppAnalysis{
+		Name:  "api",
+		Ports: []types.Port{{Port: 8080}},
+		AppConfig: &types.AppConfigContext{Networking: &types.NetworkingContext{DNS: &types.DNSContext{
+			Ndots:       &ndots,
+			Searches:    []string{"corp.example.com"},
+			HostAliases: []types.HostAliasContext{{IP: "10.0.0.9", Hostnames: []string{"legacy-db"}}},
+		}}},
+	}
+	out, err := GenerateDeployment(analysis, "default", config.Default().Resources.Defaults, config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"dnsConfig:\n        options:\n        - name: ndots\n          value: \"2\"\n        searches:\n        - corp.example.com",
+		"hostAliases:\n      - hostnames:\n        - legacy-db\n        ip: 10.0.0.9",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("deployment missing %q:\n%s", want, out)
+		}
+	}
+
+	if podDNSConfig(&types.AppAnalysis{}) != nil || hostAliases(&types.AppAnalysis{}) != nil {
+		t.Error("expected no DNS settings without networking.dns")
+	}
+}
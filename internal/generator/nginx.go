@@ -0,0 +1,143 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// NginxConfigMapFile holds the nginx.conf static sites are served with
+const NginxConfigMapFile = "nginx-configmap.yaml"
+
+// nginxUser is the nginx user of the official and unprivileged images
+const nginxUser int64 = 101
+
+// nginxCacheDir is where nginx writes its pid and temp files, an emptyDir
+// so the root filesystem stays read-only
+const nginxCacheDir = "/var/cache/nginx"
+
+// nginxRoot is where the nginx images serve files from
+const nginxRoot = "/usr/share/nginx/html"
+
+// ConfigMapManifest represents a Kubernetes ConfigMap
+type ConfigMapManifest struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   Metadata          `json:"metadata"`
+	Data       map[string]string `json:"data"`
+}
+
+// hasStaticSite reports whether the app is served with the generated
+// nginx.conf: a static site, unless its type was set to something other
+// than web or its Dockerfile copies its own nginx configuration
+func hasStaticSite(analysis *types.AppAnalysis) bool {
+	return analysis.StaticSite != nil && analysis.StaticSite.NginxConfig == "" && analysis.Type == "web"
+}
+
+// nginxConfigMapName names the ConfigMap holding the app's nginx.conf
+func nginxConfigMapName(analysis *types.AppAnalysis) string {
+	return ResourceName(analysis.Name + "-nginx")
+}
+
+// nginxConfig renders the nginx.conf for a static site. It listens on
+// StaticSitePort as a non-root user, keeps its pid and temp files in
+// nginxCacheDir, answers health checks, caches hashed assets for a year
+// but never index.html, and falls back to index.html for client-side
+// routes.
+func nginxConfig(site *types.StaticSite) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `worker_processes auto;
+pid %[1]s/nginx.pid;
+error_log /dev/stderr warn;
+
+events {
+    worker_connections 1024;
+}
+
+http {
+    include /etc/nginx/mime.types;
+    default_type application/octet-stream;
+    access_log /dev/stdout;
+    sendfile on;
+    server_tokens off;
+
+    client_body_temp_path %[1]s/client_temp;
+    proxy_temp_path %[1]s/proxy_temp;
+    fastcgi_temp_path %[1]s/fastcgi_temp;
+    uwsgi_temp_path %[1]s/uwsgi_temp;
+    scgi_temp_path %[1]s/scgi_temp;
+
+    gzip on;
+    gzip_types text/css application/javascript application/json image/svg+xml;
+
+    server {
+        listen %[2]d;
+        root %[3]s;
+
+        location = %[4]s {
+            access_log off;
+            default_type text/plain;
+            return 200 "ok\n";
+        }
+
+        location = /index.html {
+            add_header Cache-Control "no-cache";
+        }
+`, nginxCacheDir, types.StaticSitePort, nginxRoot, types.StaticSiteHealthPath)
+	if site.AssetsDir != "" {
+		fmt.Fprintf(&b, `
+        location /%s/ {
+            add_header Cache-Control "public, max-age=31536000, immutable";
+        }
+`, site.AssetsDir)
+	}
+	b.WriteString(`
+        location / {
+            try_files $uri $uri/ /index.html;
+        }
+    }
+}
+`)
+	return b.String()
+}
+
+// GenerateNginxConfigMap generates the ConfigMap holding a static site's
+// nginx.conf
+func GenerateNginxConfigMap(analysis *types.AppAnalysis, namespace string, cfg *config.Config) (string, error) {
+	return toYAML(ConfigMapManifest{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Metadata: Metadata{
+			Name:      nginxConfigMapName(analysis),
+			Namespace: namespace,
+			Labels:    buildLabelsWithAppConfig(analysis, cfg),
+		},
+		Data: map[string]string{"nginx.conf": nginxConfig(analysis.StaticSite)},
+	})
+}
+
+// nginxVolumes returns a static site's volumes: the nginx.conf ConfigMap,
+// mounted over the image's, and the emptyDir nginx writes to
+func nginxVolumes(analysis *types.AppAnalysis) ([]Volume, []VolumeMount) {
+	volumes := []Volume{
+		{Name: "nginx-conf", ConfigMap: &ConfigMapVolumeSource{Name: nginxConfigMapName(analysis)}},
+		{Name: "nginx-cache", EmptyDir: &EmptyDirVolumeSource{}},
+	}
+	mounts := []VolumeMount{
+		{Name: "nginx-conf", MountPath: "/etc/nginx/nginx.conf", SubPath: "nginx.conf", ReadOnly: true},
+		{Name: "nginx-cache", MountPath: nginxCacheDir},
+	}
+	return volumes, mounts
+}
+
+// nginxConfigChecksum returns the checksum of a static site's nginx.conf.
+// Files mounted with subPath are not updated in running pods, so the pod
+// template carries it to roll them when the config changes.
+func nginxConfigChecksum(site *types.StaticSite) string {
+	sum := sha256.Sum256([]byte(nginxConfig(site)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestGenerateStaticSite(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:        "storefront",
+		Type:        "web",
+		Ports:       []types.Port{{Port: types.StaticSitePort, Protocol: "TCP", Purpose: "HTTP"}},
+		HealthCheck: &types.HealthCheck{Path: types.StaticSiteHealthPath, Port: types.StaticSitePort},
+		StaticSite:  &types.StaticSite{BuildTool: "vite", AssetsDir: "assets"},
+	}
+	files, err := Generate(context.Background(), analysis, Options{Namespace: "default", Config: config.Default(), SkipArgoCD: true, SkipCI: true, SkipPersona: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := map[string]string{}
+	for _, f := range files {
+		contents[f.Path] = f.Content
+	}
+
+	configMap, ok := contents[NginxConfigMapFile]
+	if !ok {
+		t.Fatalf("no %s in %v", NginxConfigMapFile, files)
+	}
+	for _, s := range []string{"kind: ConfigMap", "name: storefront-nginx", "listen 8080;", "pid /var/cache/nginx/nginx.pid;", "location = /healthz", "location /assets/", "try_files $uri $uri/ /index.html;"} {
+		if !strings.Contains(configMap, s) {
+			t.Errorf("missing %q in:\n%s", s, configMap)
+		}
+	}
+
+	deployment := contents["deployment.yaml"]
+	for _, s := range []string{
+		"checksum/nginx-conf: ", "containerPort: 8080", "path: /healthz",
+		"readOnlyRootFilesystem: true", "runAsUser: 101",
+		"mountPath: /etc/nginx/nginx.conf", "subPath: nginx.conf", "mountPath: /var/cache/nginx",
+		"configMap:\n          name: storefront-nginx", "emptyDir: {}",
+	} {
+		if !strings.Contains(deployment, s) {
+			t.Errorf("missing %q in:\n%s", s, deployment)
+		}
+	}
+	if v := PodSecurityViolations(files, config.PSSRestricted); len(v) != 0 {
+		t.Errorf("static site violates the restricted standard: %+v", v)
+	}
+
+	// An app whose type was set to something else is not served with it
+	analysis.Type = "api"
+	files, err = Generate(context.Background(), analysis, Options{Namespace: "default", Config: config.Default(), SkipArgoCD: true, SkipCI: true, SkipPersona: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if f.Path == NginxConfigMapFile || strings.Contains(f.Content, "nginx-conf") {
+			t.Errorf("%s serves the api with nginx.conf:\n%s", f.Path, f.Content)
+		}
+	}
+}
+
+func TestStaticSiteOwnNginxConfig(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:       "storefront",
+		Type:       "web",
+		Ports:      []types.Port{{Port: 80, Protocol: "TCP", Purpose: "HTTP"}},
+		StaticSite: &types.StaticSite{BuildTool: "vite", AssetsDir: "assets", NginxConfig: "/etc/nginx/conf.d/default.conf"},
+	}
+	files, err := Generate(context.Background(), analysis, Options{Namespace: "default", Config: config.Default(), SkipArgoCD: true, SkipCI: true, SkipPersona: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if f.Path == NginxConfigMapFile || strings.Contains(f.Content, "nginx-conf") || strings.Contains(f.Content, "runAsUser: 101") {
+			t.Errorf("%s replaces the image's nginx configuration:\n%s", f.Path, f.Content)
+		}
+		if f.Path == "deployment.yaml" && !strings.Contains(f.Content, "containerPort: 80\n") {
+			t.Errorf("deployment.yaml does not keep the app's port:\n%s", f.Content)
+		}
+	}
+
+	result := &ValidationResult{}
+	validateStaticSite(analysis, result)
+	if len(result.Issues) != 1 || result.Issues[0].Rule != RuleNginxConfigCopied || result.Issues[0].Severity != SeverityWarning {
+		t.Fatalf("validateStaticSite() issues = %+v, want one %s warning", result.Issues, RuleNginxConfigCopied)
+	}
+	if !strings.Contains(result.Issues[0].Message, "/etc/nginx/conf.d/default.conf") {
+		t.Errorf("issue does not name the copied configuration: %+v", result.Issues[0])
+	}
+
+	analysis.StaticSite.NginxConfig = ""
+	result = &ValidationResult{}
+	validateStaticSite(analysis, result)
+	if len(result.Issues) != 0 {
+		t.Errorf("validateStaticSite() issues = %+v for the generated nginx.conf", result.Issues)
+	}
+}
+
+func TestNginxConfigAssets(t *testing.T) {
+	// Angular emits hashed assets into the root
+	conf := nginxConfig(&types.StaticSite{BuildTool: "angular"})
+	if strings.Contains(conf, "immutable") {
+		t.Errorf("assets cached without an assets dir:\n%s", conf)
+	}
+	if nginxConfigChecksum(&types.StaticSite{BuildTool: "angular"}) == nginxConfigChecksum(&types.StaticSite{BuildTool: "vite", AssetsDir: "assets"}) {
+		t.Error("checksum does not change with the config")
+	}
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"strings"
+)
+
+// PersonaKeepMarker marks a PERSONA.md section as human-maintained. Sections
+// containing the marker are carried over verbatim by MergePersonaMarkdown.
+const PersonaKeepMarker = "<!-- dorgu:keep -->"
+
+// personaSection is a level-2 section of a persona document. The preamble
+// before the first "## " heading has an empty heading.
+type personaSection struct {
+	heading string
+	content string
+}
+
+func (s personaSection) key() string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(s.heading, "##")))
+}
+
+func (s personaSection) kept() bool {
+	return strings.Contains(s.content, PersonaKeepMarker)
+}
+
+// MergePersonaMarkdown merges a freshly generated persona document into an
+// existing one. Generated sections replace their previous versions unless the
+// existing section carries PersonaKeepMarker. Sections that only exist in the
+// existing document (notes added by operators) are appended in their original
+// order, so nothing written by hand is lost on refresh.
+func MergePersonaMarkdown(existing, generated string) string {
+	if strings.TrimSpace(existing) == "" {
+		return generated
+	}
+
+	oldSections := splitPersonaSections(existing)
+	newSections := splitPersonaSections(generated)
+
+	oldByKey := make(map[string]personaSection, len(oldSections))
+	for _, s := range oldSections {
+		oldByKey[s.key()] = s
+	}
+	newKeys := make(map[string]bool, len(newSections))
+	for _, s := range newSections {
+		newKeys[s.key()] = true
+	}
+
+	var merged []string
+	for _, s := range newSections {
+		if old, ok := oldByKey[s.key()]; ok && old.kept() {
+			merged = append(merged, old.content)
+			continue
+		}
+		merged = append(merged, s.content)
+	}
+	for _, s := range oldSections {
+		if !newKeys[s.key()] {
+			merged = append(merged, s.content)
+		}
+	}
+
+	var sb strings.Builder
+	for i, content := range merged {
+		content = strings.TrimRight(content, "\n")
+		if content == "" {
+			continue
+		}
+		if i > 0 && sb.Len() > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(content)
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// splitPersonaSections splits markdown on "## " headings, ignoring headings
+// inside fenced code blocks.
+func splitPersonaSections(doc string) []personaSection {
+	var sections []personaSection
+	current := personaSection{}
+	var body strings.Builder
+	inFence := false
+
+	flush := func() {
+		current.content = body.String()
+		if current.heading != "" || strings.TrimSpace(current.content) != "" {
+			sections = append(sections, current)
+		}
+		body.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(doc, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(line, "## ") {
+			flush()
+			current = personaSection{heading: trimmed}
+		}
+		body.WriteString(line)
+	}
+	flush()
+
+	return sections
+}
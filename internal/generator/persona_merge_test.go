@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergePersonaMarkdown(t *testing.T) {
+	existing := `# api
+
+## Overview
+
+Old overview.
+
+## Operational Notes
+
+<!-- dorgu:keep -->
+
+Restart the worker after a DB failover.
+
+## Incident History
+
+- 2024-03: queue backlog
+`
+	generated := `# api
+
+## Overview
+
+New overview.
+
+## Operational Notes
+
+*Add operational notes here after deploying the application.*
+`
+
+	merged := MergePersonaMarkdown(existing, generated)
+
+	tests := []struct {
+		name    string
+		want    string
+		present bool
+	}{
+		{"regenerated section replaced", "New overview.", true},
+		{"stale content dropped", "Old overview.", false},
+		{"kept section preserved", "Restart the worker after a DB failover.", true},
+		{"kept section not overwritten", "*Add operational notes here", false},
+		{"operator-added section carried over", "## Incident History", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Contains(merged, tt.want); got != tt.present {
+				t.Errorf("merged contains %q = %v, want %v\n%s", tt.want, got, tt.present, merged)
+			}
+		})
+	}
+}
+
+func TestMergePersonaMarkdownNoExisting(t *testing.T) {
+	generated := "# api\n\n## Overview\n\nNew.\n"
+	if got := MergePersonaMarkdown("", generated); got != generated {
+		t.Errorf("MergePersonaMarkdown() = %q, want %q", got, generated)
+	}
+}
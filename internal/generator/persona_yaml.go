@@ -1,8 +1,8 @@
 package generator
 
 import (
+	"encoding/json"
 	"fmt"
-	"strings"
 
 	"github.com/dorgu-ai/dorgu/internal/config"
 	"github.com/dorgu-ai/dorgu/internal/types"
@@ -11,84 +11,86 @@ import (
 // GeneratePersonaYAML generates an ApplicationPersona CRD YAML from analysis results.
 // This is the bridge between CLI analysis and the cluster-resident CRD.
 func GeneratePersonaYAML(analysis *types.AppAnalysis, namespace string, cfg *config.Config) (string, error) {
+	persona, err := BuildPersona(analysis, namespace, cfg)
+	if err != nil {
+		return "", err
+	}
+	return MarshalPersona(persona, "yaml")
+}
+
+// BuildPersona builds a typed ApplicationPersona from analysis results.
+func BuildPersona(analysis *types.AppAnalysis, namespace string, cfg *config.Config) (*types.ApplicationPersona, error) {
 	if analysis.Name == "" {
-		return "", fmt.Errorf("application name is required for persona generation")
+		return nil, fmt.Errorf("application name is required for persona generation")
 	}
 
 	if namespace == "" {
 		namespace = "default"
 	}
 
-	var sb strings.Builder
-
-	// Header
-	sb.WriteString("apiVersion: dorgu.io/v1\n")
-	sb.WriteString("kind: ApplicationPersona\n")
-	sb.WriteString("metadata:\n")
-	sb.WriteString(fmt.Sprintf("  name: %s\n", analysis.Name))
-	sb.WriteString(fmt.Sprintf("  namespace: %s\n", namespace))
-	sb.WriteString("  labels:\n")
-	sb.WriteString("    app.kubernetes.io/managed-by: dorgu\n")
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by": "dorgu",
+	}
 	if analysis.Team != "" {
-		sb.WriteString(fmt.Sprintf("    dorgu.io/team: %s\n", analysis.Team))
+		labels["dorgu.io/team"] = analysis.Team
 	}
 
-	// Spec
-	sb.WriteString("spec:\n")
-	sb.WriteString(fmt.Sprintf("  name: %s\n", analysis.Name))
-	sb.WriteString("  version: \"1\"\n")
-
-	// Type
 	appType := analysis.Type
 	if appType == "" {
 		appType = "api"
 	}
-	sb.WriteString(fmt.Sprintf("  type: %s\n", appType))
-
-	// Tier
 	tier := "standard"
 	if analysis.AppConfig != nil && analysis.AppConfig.Tier != "" {
 		tier = analysis.AppConfig.Tier
 	}
-	sb.WriteString(fmt.Sprintf("  tier: %s\n", tier))
-
-	// Technical
-	sb.WriteString("  technical:\n")
-	if analysis.Language != "" {
-		sb.WriteString(fmt.Sprintf("    language: %s\n", analysis.Language))
-	}
-	if analysis.Framework != "" {
-		sb.WriteString(fmt.Sprintf("    framework: %s\n", analysis.Framework))
-	}
-	if analysis.Description != "" {
-		sb.WriteString(fmt.Sprintf("    description: |\n      %s\n", strings.ReplaceAll(analysis.Description, "\n", "\n      ")))
-	}
-
-	// Resources
-	writeResources(&sb, analysis, cfg)
-
-	// Scaling
-	writeScaling(&sb, analysis)
-
-	// Health
-	writeHealth(&sb, analysis)
-
-	// Dependencies
-	writeDependencies(&sb, analysis)
-
-	// Networking
-	writeNetworking(&sb, analysis, cfg)
 
-	// Ownership
-	writeOwnership(&sb, analysis)
-
-	// Policies
-	writePolicies(&sb, analysis, cfg)
+	return &types.ApplicationPersona{
+		APIVersion: types.PersonaAPIVersion,
+		Kind:       types.PersonaKind,
+		Metadata: types.PersonaMetadata{
+			Name:      analysis.Name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: types.PersonaSpec{
+			Name:    analysis.Name,
+			Version: "1",
+			Type:    appType,
+			Tier:    tier,
+			Technical: &types.PersonaTechnical{
+				Language:    analysis.Language,
+				Framework:   analysis.Framework,
+				Description: analysis.Description,
+			},
+			Resources:    buildPersonaResources(analysis, cfg),
+			Scaling:      buildPersonaScaling(analysis),
+			Health:       buildPersonaHealth(analysis),
+			Dependencies: buildPersonaDependencies(analysis),
+			Networking:   buildPersonaNetworking(analysis, cfg),
+			Ownership:    buildPersonaOwnership(analysis),
+			Policies:     buildPersonaPolicies(analysis, cfg),
+			SLO:          buildPersonaSLO(analysis),
+		},
+	}, nil
+}
 
-	return sb.String(), nil
+// MarshalPersona renders a persona as "yaml" or "json"
+func MarshalPersona(persona *types.ApplicationPersona, format string) (string, error) {
+	switch format {
+	case "", "yaml":
+		return toYAML(persona)
+	case "json":
+		data, err := json.MarshalIndent(persona, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data) + "\n", nil
+	default:
+		return "", fmt.Errorf("unsupported persona format %q (supported: yaml, json)", format)
+	}
 }
 
-func writeResources(sb *strings.Builder, analysis *types.AppAnalysis, cfg *config.Config) {
+func buildPersonaResources(analysis *types.AppAnalysis, cfg *config.Config) *types.PersonaResources {
 	resources := cfg.GetResourcesForProfile(analysis.ResourceProfile)
 
 	// Apply app config overrides
@@ -108,225 +110,188 @@ func writeResources(sb *strings.Builder, analysis *types.AppAnalysis, cfg *confi
 		}
 	}
 
-	sb.WriteString("  resources:\n")
-	sb.WriteString("    requests:\n")
-	sb.WriteString(fmt.Sprintf("      cpu: \"%s\"\n", resources.Requests.CPU))
-	sb.WriteString(fmt.Sprintf("      memory: \"%s\"\n", resources.Requests.Memory))
-	sb.WriteString("    limits:\n")
-	sb.WriteString(fmt.Sprintf("      cpu: \"%s\"\n", resources.Limits.CPU))
-	sb.WriteString(fmt.Sprintf("      memory: \"%s\"\n", resources.Limits.Memory))
-
 	profile := analysis.ResourceProfile
 	if profile == "" {
 		profile = "standard"
 	}
-	sb.WriteString(fmt.Sprintf("    profile: %s\n", profile))
+
+	return &types.PersonaResources{
+		Requests: types.PersonaResourceValues{CPU: resources.Requests.CPU, Memory: resources.Requests.Memory},
+		Limits:   types.PersonaResourceValues{CPU: resources.Limits.CPU, Memory: resources.Limits.Memory},
+		Profile:  profile,
+	}
 }
 
-func writeScaling(sb *strings.Builder, analysis *types.AppAnalysis) {
+func buildPersonaScaling(analysis *types.AppAnalysis) *types.PersonaScaling {
 	scaling := analysis.Scaling
 	if analysis.AppConfig != nil && analysis.AppConfig.Scaling != nil {
 		scaling = analysis.AppConfig.Scaling
 	}
 	if scaling == nil {
-		return
-	}
-
-	sb.WriteString("  scaling:\n")
-	sb.WriteString(fmt.Sprintf("    minReplicas: %d\n", scaling.MinReplicas))
-	sb.WriteString(fmt.Sprintf("    maxReplicas: %d\n", scaling.MaxReplicas))
-	if scaling.TargetCPU > 0 {
-		sb.WriteString(fmt.Sprintf("    targetCPU: %d\n", scaling.TargetCPU))
-	}
-	if scaling.TargetMemory > 0 {
-		sb.WriteString(fmt.Sprintf("    targetMemory: %d\n", scaling.TargetMemory))
+		return nil
 	}
 
 	behavior := scaling.Behavior
 	if behavior == "" {
 		behavior = "balanced"
 	}
-	sb.WriteString(fmt.Sprintf("    behavior: %s\n", behavior))
+	return &types.PersonaScaling{
+		MinReplicas:  scaling.MinReplicas,
+		MaxReplicas:  scaling.MaxReplicas,
+		TargetCPU:    scaling.TargetCPU,
+		TargetMemory: scaling.TargetMemory,
+		Behavior:     behavior,
+	}
 }
 
-func writeHealth(sb *strings.Builder, analysis *types.AppAnalysis) {
-	// Prefer app config health, fall back to analysis health check
-	var livenessPath, readinessPath string
-	var healthPort int
-	var startupGracePeriod string
-
-	if analysis.AppConfig != nil && analysis.AppConfig.Health != nil {
-		h := analysis.AppConfig.Health
-		livenessPath = h.LivenessPath
-		readinessPath = h.ReadinessPath
-		if h.LivenessPort > 0 {
-			healthPort = h.LivenessPort
-		} else if h.ReadinessPort > 0 {
-			healthPort = h.ReadinessPort
+func buildPersonaHealth(analysis *types.AppAnalysis) *types.PersonaHealth {
+	health := &types.PersonaHealth{}
+	liveness, readiness := resolveProbes(analysis)
+	if liveness != nil {
+		health.LivenessPath = liveness.Path
+		health.Port = liveness.Port
+	}
+	if readiness != nil {
+		health.ReadinessPath = readiness.Path
+		if health.Port == 0 {
+			health.Port = readiness.Port
 		}
-		startupGracePeriod = h.StartupGracePeriod
-	}
-
-	// Fall back to basic health check
-	if livenessPath == "" && analysis.HealthCheck != nil {
-		livenessPath = analysis.HealthCheck.Path
-		healthPort = analysis.HealthCheck.Port
-	}
-	if readinessPath == "" {
-		readinessPath = livenessPath
 	}
-
-	if livenessPath == "" && readinessPath == "" {
-		return
-	}
-
-	sb.WriteString("  health:\n")
-	if livenessPath != "" {
-		sb.WriteString(fmt.Sprintf("    livenessPath: %s\n", livenessPath))
-	}
-	if readinessPath != "" {
-		sb.WriteString(fmt.Sprintf("    readinessPath: %s\n", readinessPath))
+	if analysis.AppConfig != nil && analysis.AppConfig.Health != nil {
+		health.StartupGracePeriod = analysis.AppConfig.Health.StartupGracePeriod
 	}
-	if healthPort > 0 {
-		sb.WriteString(fmt.Sprintf("    port: %d\n", healthPort))
+	if health.LivenessPath == "" && health.ReadinessPath == "" {
+		return nil
 	}
-	if startupGracePeriod == "" {
-		startupGracePeriod = "30s"
+	if health.StartupGracePeriod == "" {
+		health.StartupGracePeriod = "30s"
 	}
-	sb.WriteString(fmt.Sprintf("    startupGracePeriod: \"%s\"\n", startupGracePeriod))
+	return health
 }
 
-func writeDependencies(sb *strings.Builder, analysis *types.AppAnalysis) {
+func buildPersonaDependencies(analysis *types.AppAnalysis) []types.PersonaDependency {
 	if analysis.AppConfig == nil || len(analysis.AppConfig.Dependencies) == 0 {
-		return
+		return nil
 	}
 
-	sb.WriteString("  dependencies:\n")
+	deps := make([]types.PersonaDependency, 0, len(analysis.AppConfig.Dependencies))
 	for _, dep := range analysis.AppConfig.Dependencies {
-		sb.WriteString(fmt.Sprintf("    - name: %s\n", dep.Name))
-		if dep.Type != "" {
-			sb.WriteString(fmt.Sprintf("      type: %s\n", dep.Type))
-		}
-		sb.WriteString(fmt.Sprintf("      required: %t\n", dep.Required))
-		if dep.HealthCheck != "" {
-			sb.WriteString(fmt.Sprintf("      healthCheck: \"%s\"\n", dep.HealthCheck))
-		}
+		deps = append(deps, types.PersonaDependency{
+			Name:        dep.Name,
+			Type:        dep.Type,
+			Required:    dep.Required,
+			HealthCheck: dep.HealthCheck,
+		})
+	}
+	return deps
+}
+
+func buildPersonaSLO(analysis *types.AppAnalysis) *types.PersonaSLO {
+	slo := appSLO(analysis)
+	if slo == nil {
+		return nil
+	}
+	persona := &types.PersonaSLO{
+		Availability:      slo.Availability,
+		Window:            sloWindow(slo),
+		ErrorBudgetPolicy: slo.ErrorBudgetPolicy,
 	}
+	if slo.LatencyThreshold != "" {
+		persona.Latency = &types.PersonaLatencySLO{Threshold: slo.LatencyThreshold, Target: slo.LatencyTarget}
+	}
+	return persona
 }
 
-func writeNetworking(sb *strings.Builder, analysis *types.AppAnalysis, cfg *config.Config) {
+func buildPersonaNetworking(analysis *types.AppAnalysis, cfg *config.Config) *types.PersonaNetworking {
 	if len(analysis.Ports) == 0 {
-		return
+		return nil
 	}
 
-	sb.WriteString("  networking:\n")
-	sb.WriteString("    ports:\n")
+	networking := &types.PersonaNetworking{}
 	for _, p := range analysis.Ports {
-		sb.WriteString(fmt.Sprintf("      - port: %d\n", p.Port))
 		protocol := p.Protocol
 		if protocol == "" {
 			protocol = "TCP"
 		}
-		sb.WriteString(fmt.Sprintf("        protocol: %s\n", protocol))
-		if p.Purpose != "" {
-			sb.WriteString(fmt.Sprintf("        purpose: %s\n", p.Purpose))
-		}
+		networking.Ports = append(networking.Ports, types.PersonaPort{
+			Port:     p.Port,
+			Protocol: protocol,
+			Purpose:  p.Purpose,
+		})
 	}
 
-	// Ingress
-	if analysis.AppConfig != nil && analysis.AppConfig.Ingress != nil && analysis.AppConfig.Ingress.Enabled {
+	if analysis.AppConfig != nil && analysis.AppConfig.Ingress != nil && analysis.AppConfig.Ingress.Enabled && exposure(analysis) == config.ExposePublic {
 		ing := analysis.AppConfig.Ingress
-		sb.WriteString("    ingress:\n")
-		sb.WriteString("      enabled: true\n")
-		if ing.Host != "" {
-			sb.WriteString(fmt.Sprintf("      host: %s\n", ing.Host))
-		} else if analysis.Name != "" {
-			sb.WriteString(fmt.Sprintf("      host: %s%s\n", analysis.Name, cfg.Ingress.DomainSuffix))
+		ingress := &types.PersonaIngress{
+			Enabled:    true,
+			TLSEnabled: ing.TLSEnabled,
 		}
-		if len(ing.Paths) > 0 {
-			sb.WriteString("      paths:\n")
-			for _, p := range ing.Paths {
-				sb.WriteString(fmt.Sprintf("        - %s\n", p.Path))
-			}
+		// The persona records the primary host only
+		if ing.Host != "" || len(ing.Hosts) > 0 || analysis.Name != "" {
+			ingress.Host = ingressHosts(analysis, cfg)[0].Host
 		}
-		sb.WriteString(fmt.Sprintf("      tlsEnabled: %t\n", ing.TLSEnabled))
-	}
-}
-
-func writeOwnership(sb *strings.Builder, analysis *types.AppAnalysis) {
-	hasOwnership := analysis.Team != "" || analysis.Owner != "" || analysis.Repository != ""
-	if analysis.AppConfig != nil && analysis.AppConfig.Operations != nil {
-		ops := analysis.AppConfig.Operations
-		if ops.OnCall != "" || ops.Runbook != "" {
-			hasOwnership = true
+		for _, p := range ing.Paths {
+			ingress.Paths = append(ingress.Paths, p.Path)
 		}
+		networking.Ingress = ingress
 	}
-	if !hasOwnership {
-		return
-	}
+	return networking
+}
 
-	sb.WriteString("  ownership:\n")
-	if analysis.Team != "" {
-		sb.WriteString(fmt.Sprintf("    team: %s\n", analysis.Team))
-	}
-	if analysis.Owner != "" {
-		sb.WriteString(fmt.Sprintf("    owner: %s\n", analysis.Owner))
-	}
-	if analysis.Repository != "" {
-		sb.WriteString(fmt.Sprintf("    repository: %s\n", analysis.Repository))
+func buildPersonaOwnership(analysis *types.AppAnalysis) *types.PersonaOwnership {
+	ownership := &types.PersonaOwnership{
+		Team:       analysis.Team,
+		Owner:      analysis.Owner,
+		Repository: analysis.Repository,
 	}
 	if analysis.AppConfig != nil && analysis.AppConfig.Operations != nil {
-		ops := analysis.AppConfig.Operations
-		if ops.OnCall != "" {
-			sb.WriteString(fmt.Sprintf("    oncall: %s\n", ops.OnCall))
-		}
-		if ops.Runbook != "" {
-			sb.WriteString(fmt.Sprintf("    runbook: %s\n", ops.Runbook))
-		}
+		ownership.Oncall = analysis.AppConfig.Operations.OnCall
+		ownership.Runbook = analysis.AppConfig.Operations.Runbook
+	}
+	if *ownership == (types.PersonaOwnership{}) {
+		return nil
 	}
+	return ownership
 }
 
-func writePolicies(sb *strings.Builder, analysis *types.AppAnalysis, cfg *config.Config) {
-	sb.WriteString("  policies:\n")
-
-	// Security from org config
-	sb.WriteString("    security:\n")
-	sb.WriteString(fmt.Sprintf("      runAsNonRoot: %t\n", cfg.Security.PodSecurityContext.RunAsNonRoot))
-	sb.WriteString(fmt.Sprintf("      readOnlyRootFilesystem: %t\n", cfg.Security.ContainerSecurityContext.ReadOnlyRootFilesystem))
-	sb.WriteString(fmt.Sprintf("      allowPrivilegeEscalation: %t\n", cfg.Security.ContainerSecurityContext.AllowPrivilegeEscalation))
-
-	// Deployment policy
-	strategy := "RollingUpdate"
-	maxSurge := "25%"
-	maxUnavailable := "25%"
+func buildPersonaPolicies(analysis *types.AppAnalysis, cfg *config.Config) *types.PersonaPolicies {
+	deployment := &types.PersonaDeploymentPolicy{
+		Strategy:       "RollingUpdate",
+		MaxSurge:       "25%",
+		MaxUnavailable: "25%",
+	}
 	if analysis.AppConfig != nil && analysis.AppConfig.DeploymentPolicy != nil {
 		dp := analysis.AppConfig.DeploymentPolicy
 		if dp.Strategy != "" {
-			strategy = dp.Strategy
+			deployment.Strategy = dp.Strategy
 		}
 		if dp.MaxSurge != "" {
-			maxSurge = dp.MaxSurge
+			deployment.MaxSurge = dp.MaxSurge
 		}
 		if dp.MaxUnavailable != "" {
-			maxUnavailable = dp.MaxUnavailable
+			deployment.MaxUnavailable = dp.MaxUnavailable
 		}
 	}
-	sb.WriteString("    deployment:\n")
-	sb.WriteString(fmt.Sprintf("      strategy: %s\n", strategy))
-	sb.WriteString(fmt.Sprintf("      maxSurge: \"%s\"\n", maxSurge))
-	sb.WriteString(fmt.Sprintf("      maxUnavailable: \"%s\"\n", maxUnavailable))
 
-	// Maintenance
-	maintenanceWindow := ""
-	autoRestart := false
+	maintenance := &types.PersonaMaintenancePolicy{}
 	if analysis.AppConfig != nil && analysis.AppConfig.Operations != nil {
-		ops := analysis.AppConfig.Operations
-		maintenanceWindow = ops.MaintenanceWindow
-		autoRestart = ops.AutoRestart
+		maintenance.Window = analysis.AppConfig.Operations.MaintenanceWindow
+		maintenance.AutoRestart = analysis.AppConfig.Operations.AutoRestart
 	}
-	sb.WriteString("    maintenance:\n")
-	if maintenanceWindow != "" {
-		sb.WriteString(fmt.Sprintf("      window: \"%s\"\n", maintenanceWindow))
+
+	security := &types.PersonaSecurityPolicy{
+		RunAsNonRoot:             cfg.Security.PodSecurityContext.RunAsNonRoot,
+		ReadOnlyRootFilesystem:   cfg.Security.ContainerSecurityContext.ReadOnlyRootFilesystem,
+		AllowPrivilegeEscalation: cfg.Security.ContainerSecurityContext.AllowPrivilegeEscalation,
+	}
+	// Only claim a Pod Security Standard the generated pods meet
+	if level := podSecurityStandard(analysis, cfg); level != "" && compliesWith(analysis, cfg, level) {
+		security.PodSecurityStandard = level
+	}
+
+	return &types.PersonaPolicies{
+		Security:    security,
+		Deployment:  deployment,
+		Maintenance: maintenance,
 	}
-	sb.WriteString(fmt.Sprintf("      autoRestart: %t\n", autoRestart))
 }
@@ -0,0 +1,220 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/kube"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func personaTestAnalysis() *types.AppAnalysis {
+	return &types.AppAnalysis{
+		Name:        "order-service",
+		Type:        "api",
+		Language:    "java",
+		Framework:   "spring",
+		Description: "Orders: create, update & cancel.\nHandles \"fulfillment\" workflow: 'v2' #primary",
+		Ports: []types.Port{
+			{Port: 8080, Protocol: "TCP", Purpose: "HTTP: public API"},
+		},
+		HealthCheck:     &types.HealthCheck{Path: "/actuator/health", Port: 8080},
+		ResourceProfile: "standard",
+		Scaling:         &types.ScalingConfig{MinReplicas: 2, MaxReplicas: 10, TargetCPU: 70},
+		Team:            "commerce: backend",
+		Owner:           "orders@example.com",
+		Repository:      "https://github.com/example/orders",
+		AppConfig: &types.AppConfigContext{
+			Tier: "critical",
+			Dependencies: []types.DependencyContext{
+				{Name: "mysql", Type: "database", Required: true, HealthCheck: "tcp://mysql:3306"},
+			},
+			Ingress: &types.IngressContext{
+				Enabled:    true,
+				Host:       "orders.example.com",
+				Paths:      []types.IngressPathDef{{Path: "/api"}},
+				TLSEnabled: true,
+			},
+			Operations: &types.OperationsContext{
+				OnCall:            "#orders-oncall",
+				MaintenanceWindow: "Sun 02:00-04:00 UTC",
+			},
+			SLO: &types.SLOContext{
+				Availability:      99.9,
+				LatencyThreshold:  "300ms",
+				LatencyTarget:     99,
+				ErrorBudgetPolicy: "Freeze releases until the budget recovers",
+			},
+		},
+	}
+}
+
+func TestGeneratePersonaYAMLRoundTrip(t *testing.T) {
+	analysis := personaTestAnalysis()
+
+	want, err := BuildPersona(analysis, "commerce", config.Default())
+	if err != nil {
+		t.Fatalf("BuildPersona() error = %v", err)
+	}
+	out, err := GeneratePersonaYAML(analysis, "commerce", config.Default())
+	if err != nil {
+		t.Fatalf("GeneratePersonaYAML() error = %v", err)
+	}
+
+	var got types.ApplicationPersona
+	if err := yaml.UnmarshalStrict([]byte(out), &got); err != nil {
+		t.Fatalf("generated YAML does not parse: %v\n%s", err, out)
+	}
+	if !reflect.DeepEqual(&got, want) {
+		t.Errorf("round trip mismatch\ngot:  %+v\nwant: %+v", got.Spec, want.Spec)
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"description with colons and quotes", got.Spec.Technical.Description, analysis.Description},
+		{"team label with colon", got.Metadata.Labels["dorgu.io/team"], analysis.Team},
+		{"port purpose with colon", got.Spec.Networking.Ports[0].Purpose, "HTTP: public API"},
+		{"oncall with hash", got.Spec.Ownership.Oncall, "#orders-oncall"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratePersonaYAMLMatchesCRDSchema(t *testing.T) {
+	schema := loadPersonaCRDSchema(t)
+
+	out, err := GeneratePersonaYAML(personaTestAnalysis(), "commerce", config.Default())
+	if err != nil {
+		t.Fatalf("GeneratePersonaYAML() error = %v", err)
+	}
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("generated YAML does not parse: %v", err)
+	}
+
+	spec, ok := doc["spec"].(map[string]interface{})
+	if !ok {
+		t.Fatal("generated persona has no spec")
+	}
+	specSchema := schemaProperty(t, schema, "spec")
+	for _, field := range stringSlice(specSchema["required"]) {
+		if _, ok := spec[field]; !ok {
+			t.Errorf("spec.%s is required by the CRD but missing", field)
+		}
+	}
+	checkAgainstSchema(t, "spec", spec, specSchema)
+}
+
+// loadPersonaCRDSchema reads the openAPIV3Schema from the bundled CRD
+func loadPersonaCRDSchema(t *testing.T) map[string]interface{} {
+	t.Helper()
+
+	crds, err := kube.BundledCRDs()
+	if err != nil {
+		t.Fatalf("failed to load bundled CRDs: %v", err)
+	}
+	var manifest []byte
+	for _, crd := range crds {
+		if crd.Name == "applicationpersonas.dorgu.io" {
+			manifest = crd.Manifest
+		}
+	}
+	if manifest == nil {
+		t.Fatal("ApplicationPersona CRD is not bundled")
+	}
+
+	var crd struct {
+		Spec struct {
+			Versions []struct {
+				Schema struct {
+					OpenAPIV3Schema map[string]interface{} `json:"openAPIV3Schema"`
+				} `json:"schema"`
+			} `json:"versions"`
+		} `json:"spec"`
+	}
+	if err := yaml.Unmarshal(manifest, &crd); err != nil {
+		t.Fatalf("failed to parse CRD: %v", err)
+	}
+	if len(crd.Spec.Versions) == 0 {
+		t.Fatal("CRD has no versions")
+	}
+	return crd.Spec.Versions[0].Schema.OpenAPIV3Schema
+}
+
+func schemaProperty(t *testing.T, schema map[string]interface{}, name string) map[string]interface{} {
+	t.Helper()
+	props, _ := schema["properties"].(map[string]interface{})
+	prop, ok := props[name].(map[string]interface{})
+	if !ok {
+		t.Fatalf("schema has no property %q", name)
+	}
+	return prop
+}
+
+// checkAgainstSchema verifies that every generated field is declared in the
+// schema with a matching type.
+func checkAgainstSchema(t *testing.T, path string, value interface{}, schema map[string]interface{}) {
+	t.Helper()
+
+	switch want := schema["type"]; want {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			t.Errorf("%s: expected object, got %T", path, value)
+			return
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		for key, v := range obj {
+			propSchema, ok := props[key].(map[string]interface{})
+			if !ok {
+				t.Errorf("%s.%s is not declared in the CRD schema", path, key)
+				continue
+			}
+			checkAgainstSchema(t, path+"."+key, v, propSchema)
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			t.Errorf("%s: expected array, got %T", path, value)
+			return
+		}
+		items, _ := schema["items"].(map[string]interface{})
+		for _, item := range arr {
+			checkAgainstSchema(t, path+"[]", item, items)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			t.Errorf("%s: expected string, got %T", path, value)
+		}
+	case "integer", "number":
+		if _, ok := value.(float64); !ok {
+			t.Errorf("%s: expected %s, got %T", path, want, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			t.Errorf("%s: expected boolean, got %T", path, value)
+		}
+	}
+}
+
+func stringSlice(v interface{}) []string {
+	items, _ := v.([]interface{})
+	var out []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,165 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+const (
+	// PluginAPIVersion identifies the stdin/stdout protocol spoken with plugins
+	PluginAPIVersion = "dorgu.ai/plugin/v1"
+	// PluginPrefix is prepended to a plugin's name to find its binary on PATH
+	PluginPrefix = "dorgu-"
+
+	pluginTimeout = time.Minute
+)
+
+// PluginRequest is written as JSON to a plugin's stdin
+type PluginRequest struct {
+	APIVersion string                 `json:"apiVersion"`
+	Analysis   *types.AppAnalysis     `json:"analysis"`
+	Namespace  string                 `json:"namespace"`
+	Files      []PluginFile           `json:"files"`
+	Config     map[string]interface{} `json:"config,omitempty"`
+}
+
+// PluginResponse is read as JSON from a plugin's stdout
+type PluginResponse struct {
+	// Files are added to the output, replacing any file with the same path
+	Files    []PluginFile `json:"files"`
+	Warnings []string     `json:"warnings,omitempty"`
+}
+
+// PluginFile is a generated file exchanged with plugins. Paths are relative
+// to the output directory, as in GeneratedFile.
+type PluginFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// ResolvePlugin returns the executable for a configured plugin
+func ResolvePlugin(p config.PluginConfig) (string, error) {
+	if p.Path != "" {
+		return exec.LookPath(p.Path)
+	}
+	if p.Name == "" {
+		return "", fmt.Errorf("plugin entry needs a name or path")
+	}
+	return exec.LookPath(PluginPrefix + p.Name)
+}
+
+// pluginName is the name used in messages for a configured plugin
+func pluginName(p config.PluginConfig) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return filepath.Base(p.Path)
+}
+
+// RunPlugins passes the generated files through every configured plugin in
+// order. Each plugin sees the output of the previous one. A failing plugin
+// fails generation so org-mandated resources are never silently dropped.
+func RunPlugins(ctx context.Context, analysis *types.AppAnalysis, opts Options, files []GeneratedFile) ([]GeneratedFile, error) {
+	for _, p := range opts.Config.Plugins {
+		name := pluginName(p)
+		bin, err := ResolvePlugin(p)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", name, err)
+		}
+
+		req := PluginRequest{
+			APIVersion: PluginAPIVersion,
+			Analysis:   analysis,
+			Namespace:  opts.Namespace,
+			Config:     p.Config,
+		}
+		for _, f := range files {
+			req.Files = append(req.Files, PluginFile{Path: f.Path, Content: f.Content})
+		}
+
+		start := time.Now()
+		resp, err := runPlugin(ctx, bin, &req)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", name, err)
+		}
+		slog.Info("plugin finished", "plugin", name, "files", len(resp.Files), "duration", time.Since(start).Round(time.Millisecond))
+		for _, w := range resp.Warnings {
+			slog.Warn(w, "plugin", name)
+		}
+
+		if files, err = mergePluginFiles(files, resp.Files); err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", name, err)
+		}
+	}
+	return files, nil
+}
+
+// runPlugin executes bin with req on stdin. The plugin's stderr is included
+// in the error when it fails.
+func runPlugin(ctx context.Context, bin string, req *PluginRequest) (*PluginResponse, error) {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, pluginTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, bin)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if parent.Err() != nil {
+			return nil, parent.Err()
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timed out after %s", pluginTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	var resp PluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	return &resp, nil
+}
+
+// mergePluginFiles replaces files with matching paths and appends new ones.
+// New files must stay inside the output directory; existing paths outside it
+// (e.g. ../PERSONA.md) may be replaced.
+func mergePluginFiles(files []GeneratedFile, out []PluginFile) ([]GeneratedFile, error) {
+	index := make(map[string]int, len(files))
+	for i, f := range files {
+		index[f.Path] = i
+	}
+	for _, pf := range out {
+		if i, ok := index[pf.Path]; ok {
+			files[i].Content = pf.Content
+			continue
+		}
+		clean := filepath.Clean(pf.Path)
+		if pf.Path == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("file path %q must be relative to the output directory", pf.Path)
+		}
+		index[pf.Path] = len(files)
+		files = append(files, GeneratedFile{Path: pf.Path, Content: pf.Content})
+	}
+	return files, nil
+}
@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// writePlugin creates a shell-script plugin that prints response
+func writePlugin(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell-script plugins need a Unix shell")
+	}
+	path := filepath.Join(t.TempDir(), "dorgu-test")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunPlugins(t *testing.T) {
+	// The plugin checks it received the request, then replaces service.yaml
+	// and adds a new file
+	plugin := writePlugin(t, `input=$(cat)
+case "$input" in
+  *'"apiVersion":"dorgu.ai/plugin/v1"'*'"role":"apps"'*) ;;
+  *) echo "bad request" >&2; exit 1 ;;
+esac
+echo '{"files":[{"path":"service.yaml","content":"replaced"},{"path":"vault.yaml","content":"kind: VaultRole"}],"warnings":["check role"]}'
+`)
+	opts := Options{
+		Namespace: "default",
+		Config: &config.Config{Plugins: []config.PluginConfig{
+			{Name: "vault", Path: plugin, Config: map[string]interface{}{"role": "apps"}},
+		}},
+	}
+	files := []GeneratedFile{{Path: "deployment.yaml", Content: "d"}, {Path: "service.yaml", Content: "s"}}
+
+	got, err := RunPlugins(context.Background(), &types.AppAnalysis{Name: "orders"}, opts, files)
+	if err != nil {
+		t.Fatalf("RunPlugins() error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d files, want 3", len(got))
+	}
+	if got[1].Content != "replaced" {
+		t.Errorf("service.yaml = %q, want replaced", got[1].Content)
+	}
+	if got[2].Path != "vault.yaml" || got[2].Content != "kind: VaultRole" {
+		t.Errorf("added file = %+v", got[2])
+	}
+}
+
+func TestRunPlugins_Failure(t *testing.T) {
+	plugin := writePlugin(t, "cat >/dev/null\necho 'vault unreachable' >&2\nexit 3\n")
+	opts := Options{Config: &config.Config{Plugins: []config.PluginConfig{{Name: "vault", Path: plugin}}}}
+
+	_, err := RunPlugins(context.Background(), &types.AppAnalysis{}, opts, nil)
+	if err == nil || !strings.Contains(err.Error(), "vault unreachable") {
+		t.Errorf("RunPlugins() error = %v, want plugin stderr", err)
+	}
+}
+
+func TestRunPlugins_NotFound(t *testing.T) {
+	opts := Options{Config: &config.Config{Plugins: []config.PluginConfig{{Name: "does-not-exist-anywhere"}}}}
+	if _, err := RunPlugins(context.Background(), &types.AppAnalysis{}, opts, nil); err == nil {
+		t.Error("RunPlugins() succeeded without the plugin binary")
+	}
+}
+
+func TestMergePluginFiles_RejectsEscapingPaths(t *testing.T) {
+	files := []GeneratedFile{{Path: "../PERSONA.md", Content: "old"}}
+
+	got, err := mergePluginFiles(files, []PluginFile{{Path: "../PERSONA.md", Content: "new"}})
+	if err != nil || got[0].Content != "new" {
+		t.Errorf("replacing an existing file: got %+v, %v", got, err)
+	}
+	for _, path := range []string{"../../etc/passwd", "/tmp/x.yaml", ""} {
+		if _, err := mergePluginFiles(files, []PluginFile{{Path: path}}); err == nil {
+			t.Errorf("mergePluginFiles() accepted %q", path)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// probeDefaults are used for timings no other source sets
+var probeDefaults = map[string]types.HealthCheck{
+	"liveness":  {InitialDelay: 10, Period: 10, Timeout: 5, FailureThreshold: 3},
+	"readiness": {InitialDelay: 5, Period: 5, Timeout: 5, FailureThreshold: 3},
+}
+
+// resolveProbes returns the liveness and readiness checks for the app, each
+// assembled independently. Every field comes from the first source that sets
+// it: the probe's own app config, then the health check found by code
+// analysis, then defaults. When only one probe has a path anywhere, the other
+// reuses its endpoint (not its timings). A nil result means no probe.
+func resolveProbes(analysis *types.AppAnalysis) (liveness, readiness *types.HealthCheck) {
+	var appLiveness, appReadiness *types.HealthCheck
+	if analysis.AppConfig != nil && analysis.AppConfig.Health != nil {
+		appLiveness = analysis.AppConfig.Health.Liveness
+		appReadiness = analysis.AppConfig.Health.Readiness
+	}
+	liveness = resolveProbe("liveness", appLiveness, analysis.HealthCheck, appReadiness, analysis.Ports)
+	readiness = resolveProbe("readiness", appReadiness, analysis.HealthCheck, appLiveness, analysis.Ports)
+	if liveness != nil {
+		liveness.SuccessThreshold = 0 // must be 1, the Kubernetes default
+	}
+	return liveness, readiness
+}
+
+func resolveProbe(kind string, app, detected, sibling *types.HealthCheck, ports []types.Port) *types.HealthCheck {
+	layers := []*types.HealthCheck{app, detected}
+	defaults := probeDefaults[kind]
+
+	probe := &types.HealthCheck{}
+	for _, l := range layers {
+		if l == nil {
+			continue
+		}
+		if probe.Path == "" && l.Path != "" {
+			probe.Path = l.Path
+			probe.Port = l.Port
+		}
+		if probe.Port == 0 {
+			probe.Port = l.Port
+		}
+		probe.InitialDelay = firstSet(probe.InitialDelay, l.InitialDelay)
+		probe.Period = firstSet(probe.Period, l.Period)
+		probe.Timeout = firstSet(probe.Timeout, l.Timeout)
+		probe.SuccessThreshold = firstSet(probe.SuccessThreshold, l.SuccessThreshold)
+		probe.FailureThreshold = firstSet(probe.FailureThreshold, l.FailureThreshold)
+	}
+	if probe.Path == "" && sibling != nil && sibling.Path != "" {
+		probe.Path = sibling.Path
+		probe.Port = firstSet(probe.Port, sibling.Port)
+	}
+	if probe.Path == "" {
+		return nil
+	}
+
+	if probe.Port == 0 {
+		probe.Port = 8080
+		if len(ports) > 0 {
+			probe.Port = ports[0].Port
+		}
+	}
+	probe.InitialDelay = firstSet(probe.InitialDelay, defaults.InitialDelay)
+	probe.Period = firstSet(probe.Period, defaults.Period)
+	probe.Timeout = firstSet(probe.Timeout, defaults.Timeout)
+	probe.FailureThreshold = firstSet(probe.FailureThreshold, defaults.FailureThreshold)
+	return probe
+}
+
+func firstSet(values ...int) int {
+	for _, v := range values {
+		if v != 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// toProbe converts a resolved check to an HTTP GET probe
+func toProbe(hc *types.HealthCheck) *Probe {
+	if hc == nil {
+		return nil
+	}
+	return &Probe{
+		HTTPGet:             &HTTPGetAction{Path: hc.Path, Port: hc.Port},
+		InitialDelaySeconds: hc.InitialDelay,
+		PeriodSeconds:       hc.Period,
+		TimeoutSeconds:      hc.Timeout,
+		SuccessThreshold:    hc.SuccessThreshold,
+		FailureThreshold:    hc.FailureThreshold,
+	}
+}
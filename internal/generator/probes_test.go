@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestResolveProbes(t *testing.T) {
+	ports := []types.Port{{Port: 3000}}
+	tests := []struct {
+		name          string
+		app           *types.HealthContext
+		detected      *types.HealthCheck
+		wantLiveness  *types.HealthCheck
+		wantReadiness *types.HealthCheck
+	}{
+		{
+			name: "nothing configured",
+		},
+		{
+			name:          "detected only uses type defaults",
+			detected:      &types.HealthCheck{Path: "/health"},
+			wantLiveness:  &types.HealthCheck{Path: "/health", Port: 3000, InitialDelay: 10, Period: 10, Timeout: 5, FailureThreshold: 3},
+			wantReadiness: &types.HealthCheck{Path: "/health", Port: 3000, InitialDelay: 5, Period: 5, Timeout: 5, FailureThreshold: 3},
+		},
+		{
+			name: "independent probes",
+			app: &types.HealthContext{
+				Liveness:  &types.HealthCheck{Path: "/live", Port: 9000, InitialDelay: 30},
+				Readiness: &types.HealthCheck{Path: "/ready", Port: 9001, Period: 2, SuccessThreshold: 2, FailureThreshold: 6},
+			},
+			detected:      &types.HealthCheck{Path: "/health", Port: 3000, Timeout: 2},
+			wantLiveness:  &types.HealthCheck{Path: "/live", Port: 9000, InitialDelay: 30, Period: 10, Timeout: 2, FailureThreshold: 3},
+			wantReadiness: &types.HealthCheck{Path: "/ready", Port: 9001, InitialDelay: 5, Period: 2, Timeout: 2, SuccessThreshold: 2, FailureThreshold: 6},
+		},
+		{
+			name: "readiness timing without path uses detected endpoint",
+			app: &types.HealthContext{
+				Liveness:  &types.HealthCheck{Path: "/live"},
+				Readiness: &types.HealthCheck{InitialDelay: 1},
+			},
+			detected:      &types.HealthCheck{Path: "/health", Port: 3000},
+			wantLiveness:  &types.HealthCheck{Path: "/live", Port: 3000, InitialDelay: 10, Period: 10, Timeout: 5, FailureThreshold: 3},
+			wantReadiness: &types.HealthCheck{Path: "/health", Port: 3000, InitialDelay: 1, Period: 5, Timeout: 5, FailureThreshold: 3},
+		},
+		{
+			name:          "liveness only is reused for readiness endpoint",
+			app:           &types.HealthContext{Liveness: &types.HealthCheck{Path: "/live", Port: 9000, InitialDelay: 30}},
+			wantLiveness:  &types.HealthCheck{Path: "/live", Port: 9000, InitialDelay: 30, Period: 10, Timeout: 5, FailureThreshold: 3},
+			wantReadiness: &types.HealthCheck{Path: "/live", Port: 9000, InitialDelay: 5, Period: 5, Timeout: 5, FailureThreshold: 3},
+		},
+		{
+			name:          "liveness success threshold dropped",
+			app:           &types.HealthContext{Liveness: &types.HealthCheck{Path: "/live", Port: 9000, SuccessThreshold: 3}},
+			wantLiveness:  &types.HealthCheck{Path: "/live", Port: 9000, InitialDelay: 10, Period: 10, Timeout: 5, FailureThreshold: 3},
+			wantReadiness: &types.HealthCheck{Path: "/live", Port: 9000, InitialDelay: 5, Period: 5, Timeout: 5, FailureThreshold: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := &types.AppAnalysis{Ports: ports, HealthCheck: tt.detected}
+			if tt.app != nil {
+				analysis.AppConfig = &types.AppConfigContext{Health: tt.app}
+			}
+			liveness, readiness := resolveProbes(analysis)
+			checkProbe(t, "liveness", liveness, tt.wantLiveness)
+			checkProbe(t, "readiness", readiness, tt.wantReadiness)
+			if liveness != nil && liveness == readiness {
+				t.Error("liveness and readiness share a pointer")
+			}
+		})
+	}
+}
+
+func checkProbe(t *testing.T, kind string, got, want *types.HealthCheck) {
+	t.Helper()
+	switch {
+	case got == nil && want == nil:
+	case got == nil || want == nil:
+		t.Errorf("%s = %+v, want %+v", kind, got, want)
+	case *got != *want:
+		t.Errorf("%s = %+v, want %+v", kind, *got, *want)
+	}
+}
@@ -0,0 +1,210 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// Output formats of the Kubernetes manifests: YAML files, or a Terraform,
+// cdk8s, or Pulumi program declaring the same objects
+const (
+	FormatYAML      = "yaml"
+	FormatTerraform = "terraform"
+	FormatCDK8s     = "cdk8s"
+	FormatPulumi    = "pulumi"
+)
+
+// ProgramFile returns the file of a format holding the objects: main.tf,
+// main.ts, or index.ts, and "" for YAML
+func ProgramFile(format string) string {
+	switch format {
+	case FormatTerraform:
+		return TerraformFile
+	case FormatCDK8s:
+		return CDK8sFile
+	case FormatPulumi:
+		return PulumiFile
+	}
+	return ""
+}
+
+// renderProgram replaces the manifests among files with the program of
+// opts.Format, placed where the first manifest was. Objects are stamped
+// with provenance first, as StampFiles only annotates YAML.
+func renderProgram(analysis *types.AppAnalysis, opts Options, files []GeneratedFile) ([]GeneratedFile, error) {
+	var kept []GeneratedFile
+	var objects []map[string]interface{}
+	at := -1
+	for _, f := range files {
+		if !IsManifest(f) {
+			kept = append(kept, f)
+			continue
+		}
+		if at < 0 {
+			at = len(kept)
+		}
+		content := f.Content
+		if opts.Provenance != nil {
+			content = stampYAML(content, *opts.Provenance)
+		}
+		for _, doc := range strings.Split(content, "\n---\n") {
+			var obj map[string]interface{}
+			if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+				return nil, fmt.Errorf("failed to convert %s to %s: %w", f.Path, opts.Format, err)
+			}
+			if len(obj) > 0 {
+				objects = append(objects, obj)
+			}
+		}
+	}
+	if at < 0 {
+		return files, nil
+	}
+
+	image := appImage(analysis, opts.Config)
+	var program []GeneratedFile
+	switch opts.Format {
+	case FormatTerraform:
+		program = []GeneratedFile{{Path: TerraformFile, Content: renderTerraform(objects, image)}}
+	case FormatCDK8s:
+		program = renderCDK8s(analysis.Name, objects, image)
+	case FormatPulumi:
+		program = renderPulumi(analysis.Name, objects, image)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", opts.Format)
+	}
+	return append(kept[:at], append(program, kept[at:]...)...), nil
+}
+
+// objectName returns <kind>-<name> of an object, lowercase
+func objectName(obj map[string]interface{}) string {
+	kind, _ := obj["kind"].(string)
+	var name string
+	if meta, ok := obj["metadata"].(map[string]interface{}); ok {
+		name, _ = meta["name"].(string)
+	}
+	return strings.ToLower(kind + "-" + name)
+}
+
+// literalWriter renders decoded YAML values as HCL or TypeScript literals,
+// replacing the app's image with a variable the program takes as input
+type literalWriter struct {
+	// ident matches keys written without quotes
+	ident *regexp.Regexp
+	quote func(string) string
+	// assign separates keys from values, and comma follows each attribute
+	assign string
+	comma  bool
+	// align pads the keys of consecutive single-line attributes to one
+	// column, like terraform fmt
+	align bool
+
+	image     string
+	imageRef  string
+	usedImage bool
+}
+
+func (w *literalWriter) value(b *strings.Builder, v interface{}, depth int) {
+	switch v := v.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case float64:
+		b.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+	case string:
+		if w.image != "" && v == w.image {
+			w.usedImage = true
+			b.WriteString(w.imageRef)
+			return
+		}
+		b.WriteString(w.quote(v))
+	case []interface{}:
+		w.list(b, v, depth)
+	case map[string]interface{}:
+		w.object(b, v, depth)
+	default:
+		b.WriteString(w.quote(fmt.Sprint(v)))
+	}
+}
+
+func (w *literalWriter) list(b *strings.Builder, items []interface{}, depth int) {
+	if len(items) == 0 {
+		b.WriteString("[]")
+		return
+	}
+	rendered := make([]string, len(items))
+	inline := true
+	for i, item := range items {
+		var ib strings.Builder
+		w.value(&ib, item, depth+1)
+		rendered[i] = ib.String()
+		if strings.Contains(rendered[i], "\n") {
+			inline = false
+		}
+	}
+	if inline {
+		b.WriteString("[" + strings.Join(rendered, ", ") + "]")
+		return
+	}
+	indent := strings.Repeat("  ", depth+1)
+	b.WriteString("[\n")
+	for _, r := range rendered {
+		b.WriteString(indent + r + ",\n")
+	}
+	b.WriteString(strings.Repeat("  ", depth) + "]")
+}
+
+func (w *literalWriter) object(b *strings.Builder, obj map[string]interface{}, depth int) {
+	if len(obj) == 0 {
+		b.WriteString("{}")
+		return
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	type attr struct{ key, value string }
+	attrs := make([]attr, len(keys))
+	for i, k := range keys {
+		var vb strings.Builder
+		w.value(&vb, obj[k], depth+1)
+		key := k
+		if !w.ident.MatchString(k) {
+			key = w.quote(k)
+		}
+		attrs[i] = attr{key, vb.String()}
+	}
+
+	indent := strings.Repeat("  ", depth+1)
+	comma := ""
+	if w.comma {
+		comma = ","
+	}
+	b.WriteString("{\n")
+	for i := 0; i < len(attrs); {
+		end, width := i+1, 0
+		if w.align {
+			// A run of single-line attributes shares one column
+			end = i
+			for end < len(attrs) && !strings.Contains(attrs[end].value, "\n") {
+				width = max(width, len(attrs[end].key))
+				end++
+			}
+			end = max(end, i+1)
+		}
+		for ; i < end; i++ {
+			fmt.Fprintf(b, "%s%-*s%s%s%s\n", indent, width, attrs[i].key, w.assign, attrs[i].value, comma)
+		}
+	}
+	b.WriteString(strings.Repeat("  ", depth) + "}")
+}
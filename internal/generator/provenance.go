@@ -0,0 +1,313 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// Annotations stamped on every generated Kubernetes object
+const (
+	AnnotationVersion      = "dorgu.io/version"
+	AnnotationAnalysisHash = "dorgu.io/analysis-hash"
+	AnnotationGeneratedAt  = "dorgu.io/generated-at"
+	AnnotationLLMModel     = "dorgu.io/llm-model"
+)
+
+// LockFile is written next to the generated manifests
+const LockFile = "dorgu.lock"
+
+// Provenance records which dorgu, analysis, and LLM produced a set of files
+type Provenance struct {
+	Version      string `json:"version"`
+	AnalysisHash string `json:"analysisHash"`
+	// GeneratedAt is left out by deterministic runs without SOURCE_DATE_EPOCH
+	GeneratedAt *time.Time `json:"generatedAt,omitempty"`
+	// LLMModel is provider/model, empty when no LLM contributed
+	LLMModel string `json:"llmModel,omitempty"`
+}
+
+// Lock captures the exact inputs and outputs of a generate run, so a later
+// run can tell files regenerated by a newer dorgu from hand-edited ones
+type Lock struct {
+	Provenance `json:",inline"`
+	Inputs     LockInputs `json:"inputs"`
+	// Files maps each generated path, relative to the output directory, to
+	// the sha256 of the content dorgu wrote
+	Files map[string]string `json:"files"`
+}
+
+// LockInputs are the settings a generate run depended on
+type LockInputs struct {
+	App        string   `json:"app"`
+	Namespace  string   `json:"namespace"`
+	ConfigHash string   `json:"configHash"`
+	Skipped    []string `json:"skipped,omitempty"`
+	// SourceHash digests the app's source files and generate settings, when
+	// known (see generate --all)
+	SourceHash string `json:"sourceHash,omitempty"`
+}
+
+// HashAnalysis returns a stable digest of an analysis
+func HashAnalysis(analysis *types.AppAnalysis) string {
+	data, _ := json.Marshal(analysis)
+	return hashBytes(data)
+}
+
+func hashConfig(cfg *config.Config) string {
+	data, _ := json.Marshal(cfg)
+	return hashBytes(data)
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// annotations returns the provenance annotations in a fixed order
+func (p Provenance) annotations() [][2]string {
+	ann := [][2]string{{AnnotationAnalysisHash, p.AnalysisHash}}
+	if p.GeneratedAt != nil {
+		ann = append(ann, [2]string{AnnotationGeneratedAt, p.GeneratedAt.UTC().Format(time.RFC3339)})
+	}
+	if p.LLMModel != "" {
+		ann = append(ann, [2]string{AnnotationLLMModel, p.LLMModel})
+	}
+	return append(ann, [2]string{AnnotationVersion, p.Version})
+}
+
+// header is the one-line provenance comment for files that are not
+// Kubernetes objects
+func (p Provenance) header() string {
+	parts := make([]string, 0, 4)
+	for _, a := range p.annotations() {
+		parts = append(parts, a[0]+"="+a[1])
+	}
+	return "Generated by dorgu: " + strings.Join(parts, " ")
+}
+
+// StampFiles adds provenance to generated files: annotations on Kubernetes
+// objects, and a leading comment on other YAML and Markdown files
+func StampFiles(files []GeneratedFile, p Provenance) []GeneratedFile {
+	stamped := make([]GeneratedFile, len(files))
+	for i, f := range files {
+		stamped[i] = f
+		switch strings.ToLower(filepath.Ext(f.Path)) {
+		case ".yaml", ".yml":
+			stamped[i].Content = stampYAML(f.Content, p)
+		case ".md":
+			stamped[i].Content = "<!-- " + p.header() + " -->\n\n" + f.Content
+		}
+	}
+	return stamped
+}
+
+func stampYAML(content string, p Provenance) string {
+	docs := strings.Split(content, "\n---\n")
+	annotated := false
+	for i, doc := range docs {
+		if stamped, ok := annotateDocument(doc, p.annotations()); ok {
+			docs[i] = stamped
+			annotated = true
+		}
+	}
+	if !annotated {
+		return "# " + p.header() + "\n" + content
+	}
+	return strings.Join(docs, "\n---\n")
+}
+
+// annotateDocument adds ann to metadata.annotations of one YAML document,
+// replacing earlier values of the same keys. Documents without a top-level
+// metadata block are left alone.
+func annotateDocument(doc string, ann [][2]string) (string, bool) {
+	lines := strings.Split(doc, "\n")
+	meta := -1
+	for i, l := range lines {
+		if strings.TrimRight(l, " ") == "metadata:" {
+			meta = i
+			break
+		}
+	}
+	if meta < 0 {
+		return doc, false
+	}
+
+	end := meta + 1
+	for end < len(lines) && (strings.TrimSpace(lines[end]) == "" || strings.HasPrefix(lines[end], " ")) {
+		end++
+	}
+	indent := "  "
+	for _, l := range lines[meta+1 : end] {
+		if strings.TrimSpace(l) != "" {
+			indent = l[:len(l)-len(strings.TrimLeft(l, " "))]
+			break
+		}
+	}
+
+	at := -1
+	for i := meta + 1; i < end; i++ {
+		if strings.TrimRight(lines[i], " ") == indent+"annotations:" {
+			at = i
+			break
+		}
+	}
+	entryIndent := indent + "  "
+	var head []string
+	if at < 0 {
+		at = meta
+		head = append(head, lines[:meta+1]...)
+		head = append(head, indent+"annotations:")
+	} else {
+		head = append(head, lines[:at+1]...)
+	}
+
+	// Drop stale provenance entries from an existing annotations block
+	rest := lines[at+1:]
+	keys := map[string]bool{}
+	for _, a := range ann {
+		keys[a[0]] = true
+	}
+	var kept []string
+	inBlock := at != meta
+	for i, l := range rest {
+		if inBlock && !strings.HasPrefix(l, indent+" ") {
+			inBlock = false
+		}
+		if inBlock {
+			if i == 0 {
+				entryIndent = l[:len(l)-len(strings.TrimLeft(l, " "))]
+			}
+			key := strings.TrimSpace(strings.SplitN(l, ":", 2)[0])
+			if keys[key] || keys[strings.Trim(key, `"'`)] {
+				continue
+			}
+		}
+		kept = append(kept, l)
+	}
+
+	for _, a := range ann {
+		head = append(head, entryIndent+a[0]+": "+strconv.Quote(a[1]))
+	}
+	return strings.Join(append(head, kept...), "\n"), true
+}
+
+// NewLock records the inputs and stamped files of a generate run
+func NewLock(analysis *types.AppAnalysis, opts Options, p Provenance, files []GeneratedFile) *Lock {
+	lock := &Lock{
+		Provenance: p,
+		Inputs: LockInputs{
+			App:        analysis.Name,
+			Namespace:  opts.Namespace,
+			ConfigHash: hashConfig(opts.Config),
+			SourceHash: opts.SourceHash,
+		},
+		Files: make(map[string]string, len(files)),
+	}
+	for name, skipped := range map[string]bool{
+		"argocd": opts.SkipArgoCD, "ci": opts.SkipCI, "persona": opts.SkipPersona, "plugins": opts.SkipPlugins,
+	} {
+		if skipped {
+			lock.Inputs.Skipped = append(lock.Inputs.Skipped, name)
+		}
+	}
+	sort.Strings(lock.Inputs.Skipped)
+	for _, f := range files {
+		lock.Files[f.Path] = hashBytes([]byte(f.Content))
+	}
+	return lock
+}
+
+// Render returns the lock file content
+func (l *Lock) Render() (string, error) {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return "", err
+	}
+	return "# Generated by dorgu. Do not edit; records how the files in this directory were generated.\n" + string(data), nil
+}
+
+// ReadLock reads the lock file in dir. A missing lock returns nil, nil.
+func ReadLock(dir string) (*Lock, error) {
+	data, err := os.ReadFile(filepath.Join(dir, LockFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var lock Lock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", LockFile, err)
+	}
+	return &lock, nil
+}
+
+// Edited reports whether content on disk differs from what dorgu last wrote
+// to path. Paths the lock does not know are not considered edited.
+func (l *Lock) Edited(path string, content []byte) bool {
+	want, ok := l.Files[path]
+	return ok && want != hashBytes(content)
+}
+
+// KeepGeneratedAt returns files restamped with the generatedAt of the
+// previous run's lock when nothing else changed: the restamped lock, with
+// its analysis hash, inputs, and file checksums, is the previous one. A
+// rerun then leaves the files unchanged. Otherwise files is returned as is.
+func KeepGeneratedAt(files []GeneratedFile, previous *Lock) []GeneratedFile {
+	if previous == nil || previous.GeneratedAt == nil {
+		return files
+	}
+	lockAt := -1
+	var current Lock
+	for i, f := range files {
+		if f.Path == LockFile && yaml.Unmarshal([]byte(f.Content), &current) == nil {
+			lockAt = i
+		}
+	}
+	if lockAt < 0 || current.GeneratedAt == nil || current.GeneratedAt.Equal(*previous.GeneratedAt) {
+		return files
+	}
+
+	from := current.GeneratedAt.UTC().Format(time.RFC3339)
+	to := previous.GeneratedAt.UTC().Format(time.RFC3339)
+	restamped := make([]GeneratedFile, len(files))
+	hashes := make(map[string]string, len(current.Files))
+	for path, hash := range current.Files {
+		hashes[path] = hash
+	}
+	for i, f := range files {
+		restamped[i] = f
+		if i == lockAt {
+			continue
+		}
+		restamped[i].Content = strings.ReplaceAll(f.Content, from, to)
+		if _, ok := hashes[f.Path]; ok {
+			hashes[f.Path] = hashBytes([]byte(restamped[i].Content))
+		}
+	}
+	current.GeneratedAt = previous.GeneratedAt
+	current.Files = hashes
+	if !reflect.DeepEqual(current, *previous) {
+		return files
+	}
+	lock, err := current.Render()
+	if err != nil {
+		return files
+	}
+	restamped[lockAt].Content = lock
+	return restamped
+}
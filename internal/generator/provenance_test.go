@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+var testGeneratedAt = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+var testProvenance = Provenance{
+	Version:      "v1.2.3",
+	AnalysisHash: "sha256:abc",
+	GeneratedAt:  &testGeneratedAt,
+	LLMModel:     "openai/gpt-4",
+}
+
+func TestStampFiles(t *testing.T) {
+	files := []GeneratedFile{
+		{Path: "service.yaml", Content: "apiVersion: v1\nkind: Service\nmetadata:\n  labels:\n    app: orders\n  name: orders\nspec:\n  ports:\n  - port: 80\n"},
+		{Path: "deployment.yaml", Content: "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  annotations:\n    team: payments\n    dorgu.io/version: v0.1.0\n  name: orders\n"},
+		{Path: "../.github/workflows/deploy.yaml", Content: "name: Deploy\non: push\n"},
+		{Path: "../PERSONA.md", Content: "# orders\n"},
+	}
+	got := StampFiles(files, testProvenance)
+
+	want := "apiVersion: v1\nkind: Service\nmetadata:\n  annotations:\n" +
+		"    dorgu.io/analysis-hash: \"sha256:abc\"\n" +
+		"    dorgu.io/generated-at: \"2026-01-02T03:04:05Z\"\n" +
+		"    dorgu.io/llm-model: \"openai/gpt-4\"\n" +
+		"    dorgu.io/version: \"v1.2.3\"\n" +
+		"  labels:\n    app: orders\n  name: orders\nspec:\n  ports:\n  - port: 80\n"
+	if got[0].Content != want {
+		t.Errorf("service =\n%s\nwant\n%s", got[0].Content, want)
+	}
+
+	var obj struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}
+	if err := yaml.Unmarshal([]byte(got[1].Content), &obj); err != nil {
+		t.Fatalf("stamped deployment is not valid YAML: %v\n%s", err, got[1].Content)
+	}
+	if ann := obj.Metadata.Annotations; ann["team"] != "payments" || ann[AnnotationVersion] != "v1.2.3" || len(ann) != 5 {
+		t.Errorf("deployment annotations = %v", ann)
+	}
+
+	if !strings.HasPrefix(got[2].Content, "# Generated by dorgu: dorgu.io/analysis-hash=sha256:abc ") {
+		t.Errorf("workflow = %q", got[2].Content)
+	}
+	if !strings.HasPrefix(got[3].Content, "<!-- Generated by dorgu: ") || !strings.HasSuffix(got[3].Content, "-->\n\n# orders\n") {
+		t.Errorf("persona = %q", got[3].Content)
+	}
+	if files[0].Content == got[0].Content {
+		t.Error("StampFiles modified its input")
+	}
+
+	undated := testProvenance
+	undated.GeneratedAt = nil
+	if got := StampFiles(files[:1], undated); strings.Contains(got[0].Content, AnnotationGeneratedAt) {
+		t.Errorf("undated provenance stamped a timestamp:\n%s", got[0].Content)
+	}
+}
+
+func TestLock(t *testing.T) {
+	files := []GeneratedFile{{Path: "deployment.yaml", Content: "kind: Deployment\n"}}
+	lock := &Lock{Provenance: testProvenance, Files: map[string]string{"deployment.yaml": hashBytes([]byte(files[0].Content))}}
+
+	content, err := lock.Render()
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	if !strings.Contains(content, "\nversion: v1.2.3\n") {
+		t.Errorf("lock does not inline provenance:\n%s", content)
+	}
+	var parsed Lock
+	if err := yaml.Unmarshal([]byte(content), &parsed); err != nil {
+		t.Fatalf("lock does not parse: %v", err)
+	}
+	if parsed.GeneratedAt == nil || !parsed.GeneratedAt.Equal(testGeneratedAt) || parsed.LLMModel != "openai/gpt-4" {
+		t.Errorf("parsed = %+v", parsed.Provenance)
+	}
+
+	if parsed.Edited("deployment.yaml", []byte(files[0].Content)) {
+		t.Error("unchanged file reported as edited")
+	}
+	if !parsed.Edited("deployment.yaml", []byte("kind: Deployment\nspec: {}\n")) {
+		t.Error("edited file not reported")
+	}
+	if parsed.Edited("service.yaml", []byte("anything")) {
+		t.Error("unknown file reported as edited")
+	}
+}
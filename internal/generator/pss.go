@@ -0,0 +1,220 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// baselineCapabilities are the capabilities the baseline standard lets a
+// container add
+var baselineCapabilities = map[corev1.Capability]bool{
+	"AUDIT_WRITE": true, "CHOWN": true, "DAC_OVERRIDE": true, "FOWNER": true, "FSETID": true,
+	"KILL": true, "MKNOD": true, "NET_BIND_SERVICE": true, "SETFCAP": true, "SETGID": true,
+	"SETPCAP": true, "SETUID": true, "SYS_CHROOT": true,
+}
+
+// podSecurityStandard returns the Pod Security Standard level declared for
+// the app; app config overrides the org's
+func podSecurityStandard(analysis *types.AppAnalysis, cfg *config.Config) string {
+	if analysis.AppConfig != nil && analysis.AppConfig.Security != nil && analysis.AppConfig.Security.PodSecurityStandard != "" {
+		return analysis.AppConfig.Security.PodSecurityStandard
+	}
+	if cfg == nil {
+		return ""
+	}
+	return cfg.Security.PodSecurityStandard
+}
+
+// ValidatePodSecurityStandard checks a Pod Security Standard level
+func ValidatePodSecurityStandard(level string) error {
+	switch level {
+	case "", config.PSSPrivileged, config.PSSBaseline, config.PSSRestricted:
+		return nil
+	}
+	return fmt.Errorf("%q is not one of privileged, baseline, restricted", level)
+}
+
+// podSecurityLabels are the namespace labels that enforce, audit, and warn
+// on a Pod Security Standard level
+func podSecurityLabels(level string) map[string]string {
+	if level == "" {
+		return nil
+	}
+	return map[string]string{
+		"pod-security.kubernetes.io/enforce": level,
+		"pod-security.kubernetes.io/audit":   level,
+		"pod-security.kubernetes.io/warn":    level,
+	}
+}
+
+// PodSecurityViolation is a way a generated pod breaks a Pod Security
+// Standard
+type PodSecurityViolation struct {
+	File    string
+	Message string
+}
+
+// PodSecurityViolations checks the pod templates of the Deployments and Jobs
+// among files against a Pod Security Standard level
+func PodSecurityViolations(files []GeneratedFile, level string) []PodSecurityViolation {
+	if level == "" || level == config.PSSPrivileged {
+		return nil
+	}
+	var violations []PodSecurityViolation
+	for _, f := range files {
+		if !IsManifest(f) {
+			continue
+		}
+		for _, doc := range strings.Split(f.Content, "\n---\n") {
+			var obj struct {
+				Kind string `json:"kind"`
+				Spec struct {
+					Template    corev1.PodTemplateSpec `json:"template"`
+					JobTemplate struct {
+						Spec struct {
+							Template corev1.PodTemplateSpec `json:"template"`
+						} `json:"spec"`
+					} `json:"jobTemplate"`
+				} `json:"spec"`
+			}
+			if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+				continue
+			}
+			template := obj.Spec.Template
+			switch obj.Kind {
+			case "Deployment", "Job":
+			case "CronJob":
+				template = obj.Spec.JobTemplate.Spec.Template
+			default:
+				continue
+			}
+			for _, v := range podViolations(template, level) {
+				violations = append(violations, PodSecurityViolation{File: f.Path, Message: v})
+			}
+		}
+	}
+	return violations
+}
+
+// podViolations checks one pod template against the baseline standard and,
+// for restricted, the restricted one
+func podViolations(pod corev1.PodTemplateSpec, level string) []string {
+	var v []string
+	spec := pod.Spec
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		v = append(v, "host namespaces must not be shared")
+	}
+	for _, vol := range spec.Volumes {
+		if vol.HostPath != nil {
+			v = append(v, fmt.Sprintf("volume %s must not be a hostPath", vol.Name))
+		} else if level == config.PSSRestricted && !restrictedVolume(vol) {
+			v = append(v, fmt.Sprintf("volume %s has a type the restricted standard does not allow", vol.Name))
+		}
+	}
+	for _, k := range sortedMapKeys(pod.Annotations) {
+		if strings.HasPrefix(k, appArmorAnnotationPrefix) && pod.Annotations[k] == "unconfined" {
+			v = append(v, fmt.Sprintf("container %s must not be AppArmor unconfined", strings.TrimPrefix(k, appArmorAnnotationPrefix)))
+		}
+	}
+
+	podNonRoot, podSeccomp := false, ""
+	if sc := spec.SecurityContext; sc != nil {
+		podNonRoot = sc.RunAsNonRoot != nil && *sc.RunAsNonRoot
+		if sc.SeccompProfile != nil {
+			podSeccomp = string(sc.SeccompProfile.Type)
+		}
+	}
+	if podSeccomp == SeccompUnconfined {
+		v = append(v, "pod seccomp profile must not be Unconfined")
+	}
+
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		prefix := "container " + c.Name + ": "
+		for _, p := range c.Ports {
+			if p.HostPort != 0 {
+				v = append(v, prefix+"hostPort must not be set")
+			}
+		}
+		sc := c.SecurityContext
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			v = append(v, prefix+"must not be privileged")
+		}
+		seccomp := podSeccomp
+		if sc.SeccompProfile != nil {
+			seccomp = string(sc.SeccompProfile.Type)
+			if seccomp == SeccompUnconfined {
+				v = append(v, prefix+"seccomp profile must not be Unconfined")
+			}
+		}
+		var add []corev1.Capability
+		dropsAll := false
+		if sc.Capabilities != nil {
+			add = sc.Capabilities.Add
+			for _, d := range sc.Capabilities.Drop {
+				dropsAll = dropsAll || d == "ALL"
+			}
+		}
+		for _, c := range add {
+			if !baselineCapabilities[c] || (level == config.PSSRestricted && c != "NET_BIND_SERVICE") {
+				v = append(v, fmt.Sprintf("%smust not add capability %s", prefix, c))
+			}
+		}
+
+		if level != config.PSSRestricted {
+			continue
+		}
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			v = append(v, prefix+"allowPrivilegeEscalation must be false")
+		}
+		if !dropsAll {
+			v = append(v, prefix+"capabilities must drop ALL")
+		}
+		nonRoot := podNonRoot
+		if sc.RunAsNonRoot != nil {
+			nonRoot = *sc.RunAsNonRoot
+		}
+		if !nonRoot {
+			v = append(v, prefix+"runAsNonRoot must be true")
+		}
+		if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			v = append(v, prefix+"must not run as user 0")
+		}
+		if seccomp != SeccompRuntimeDefault && seccomp != SeccompLocalhost {
+			v = append(v, prefix+"seccomp profile must be RuntimeDefault or Localhost")
+		}
+	}
+	return v
+}
+
+// restrictedVolume reports whether the restricted standard allows a volume's
+// type
+func restrictedVolume(vol corev1.Volume) bool {
+	s := vol.VolumeSource
+	return s.ConfigMap != nil || s.CSI != nil || s.DownwardAPI != nil || s.EmptyDir != nil ||
+		s.Ephemeral != nil || s.PersistentVolumeClaim != nil || s.Projected != nil || s.Secret != nil
+}
+
+// compliesWith reports whether the app's generated workloads comply with a
+// Pod Security Standard level
+func compliesWith(analysis *types.AppAnalysis, cfg *config.Config, level string) bool {
+	deployment, err := GenerateDeployment(analysis, "", cfg.GetResourcesForProfile(analysis.ResourceProfile), cfg)
+	if err != nil {
+		return false
+	}
+	files := []GeneratedFile{{Path: "deployment.yaml", Content: deployment}}
+	jobs, err := GenerateJobs(analysis, "", cfg.GetResourcesForProfile(analysis.ResourceProfile), cfg)
+	if err != nil {
+		return false
+	}
+	return len(PodSecurityViolations(append(files, jobs...), level)) == 0
+}
@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestPodSecurityViolations(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    string
+		security *types.SecurityContext
+		want     string
+	}{
+		{"defaults are restricted", config.PSSRestricted, nil, ""},
+		{"unconfined seccomp breaks baseline", config.PSSBaseline, &types.SecurityContext{SeccompType: SeccompUnconfined}, "seccomp profile must not be Unconfined"},
+		{"unconfined apparmor breaks baseline", config.PSSBaseline, &types.SecurityContext{AppArmorProfile: "unconfined"}, "AppArmor unconfined"},
+		{"localhost seccomp is restricted", config.PSSRestricted, &types.SecurityContext{SeccompType: SeccompLocalhost, SeccompLocalhostProfile: "profiles/a.json"}, ""},
+		{"privileged allows anything", config.PSSPrivileged, &types.SecurityContext{SeccompType: SeccompUnconfined}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := &types.AppAnalysis{Name: "orders", AppConfig: &types.AppConfigContext{Security: tt.security}}
+			cfg := config.Default()
+			deployment, err := GenerateDeployment(analysis, "default", cfg.Resources.Defaults, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			violations := PodSecurityViolations([]GeneratedFile{{Path: "deployment.yaml", Content: deployment}}, tt.level)
+			if tt.want == "" {
+				if len(violations) > 0 {
+					t.Errorf("unexpected violations: %+v", violations)
+				}
+				return
+			}
+			found := false
+			for _, v := range violations {
+				found = found || strings.Contains(v.Message, tt.want)
+			}
+			if !found {
+				t.Errorf("violations %+v do not include %q", violations, tt.want)
+			}
+		})
+	}
+}
+
+func TestPodSecurityStandardClaims(t *testing.T) {
+	cfg := config.Default()
+	cfg.Security.PodSecurityStandard = config.PSSRestricted
+
+	compliant := &types.AppAnalysis{Name: "orders"}
+	argo, err := GenerateArgoCD(compliant, "orders", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(argo, "pod-security.kubernetes.io/enforce: restricted") {
+		t.Errorf("ArgoCD Application does not label the namespace:\n%s", argo)
+	}
+	if policies := buildPersonaPolicies(compliant, cfg); policies.Security.PodSecurityStandard != config.PSSRestricted {
+		t.Errorf("persona claims %q, want restricted", policies.Security.PodSecurityStandard)
+	}
+
+	unconfined := &types.AppAnalysis{Name: "orders", AppConfig: &types.AppConfigContext{Security: &types.SecurityContext{SeccompType: SeccompUnconfined}}}
+	if policies := buildPersonaPolicies(unconfined, cfg); policies.Security.PodSecurityStandard != "" {
+		t.Errorf("persona claims %q for non-compliant pods", policies.Security.PodSecurityStandard)
+	}
+	result := ValidateGenerated(context.Background(), unconfined, []GeneratedFile{{Path: "deployment.yaml", Content: mustDeployment(t, unconfined, cfg)}}, Options{Config: cfg})
+	if result.Passed {
+		t.Errorf("validation passed for pods that violate the restricted standard")
+	}
+}
+
+func mustDeployment(t *testing.T, analysis *types.AppAnalysis, cfg *config.Config) string {
+	t.Helper()
+	deployment, err := GenerateDeployment(analysis, "default", cfg.Resources.Defaults, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return deployment
+}
@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// PullRequestData is the data passed to pull request title and body templates
+type PullRequestData struct {
+	Analysis  *types.AppAnalysis
+	Namespace string
+	// Files are the written paths, relative to the repository
+	Files      []string
+	Validation *ValidationResult
+	// ValidationReport is Validation formatted as in the terminal
+	ValidationReport string
+	// Persona is nil when it could not be built
+	Persona *types.ApplicationPersona
+}
+
+// DefaultPullRequestTitle is used when pull_request.title is not set
+const DefaultPullRequestTitle = "Deploy {{ .Analysis.Name }} to Kubernetes"
+
+// DefaultPullRequestBody is used when pull_request.body_template is not set
+const DefaultPullRequestBody = `Kubernetes manifests for **{{ .Analysis.Name }}** generated by Dorgu.
+
+- Namespace: ` + "`{{ .Namespace }}`" + `
+- Type: {{ default "unknown" .Analysis.Type }}
+{{- with .Persona }}{{ with .Spec }}
+
+### Persona
+
+| | |
+|---|---|
+{{- if .Tier }}
+| Tier | {{ .Tier }} |
+{{- end }}
+{{- with .Technical }}
+| Stack | {{ .Language }}{{ if .Framework }} / {{ .Framework }}{{ end }} |
+{{- end }}
+{{- with .Ownership }}
+| Team | {{ default "-" .Team }} |
+| Owner | {{ default "-" .Owner }} |
+{{- end }}
+{{- with .Resources }}
+| Resources | requests {{ .Requests.CPU }} / {{ .Requests.Memory }}, limits {{ .Limits.CPU }} / {{ .Limits.Memory }} |
+{{- end }}
+{{- with .Scaling }}
+| Scaling | {{ .MinReplicas }}-{{ .MaxReplicas }} replicas{{ if .TargetCPU }} at {{ .TargetCPU }}% CPU{{ end }} |
+{{- end }}
+{{- with .Health }}
+| Health | liveness {{ default "-" .LivenessPath }}, readiness {{ default "-" .ReadinessPath }} |
+{{- end }}
+{{- with .Networking }}{{ with .Ingress }}{{ if .Enabled }}
+| Ingress | {{ .Host }}{{ if .TLSEnabled }} (TLS){{ end }} |
+{{- end }}{{ end }}{{ end }}
+{{- end }}{{ end }}
+
+### Files
+{{ range .Files }}
+- ` + "`{{ . }}`" + `
+{{- end }}
+
+### Validation
+{{ if .Validation }}
+{{ if .Validation.Passed }}Passed{{ else }}Found issues{{ end }}{{ with .Validation.Summary }}: {{ . }}{{ end }}
+
+` + "```" + `
+{{ .ValidationReport }}
+` + "```" + `
+{{- else }}
+Skipped
+{{- end }}
+`
+
+// RenderPullRequest renders a pull request title or body template
+func RenderPullRequest(name, text string, data PullRequestData) (string, error) {
+	if data.Validation != nil && data.ValidationReport == "" {
+		data.ValidationReport = FormatValidationReport(data.Validation)
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse pull request %s template: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render pull request %s template: %w", name, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestRenderPullRequest(t *testing.T) {
+	data := PullRequestData{
+		Analysis:  &types.AppAnalysis{Name: "orders", Type: "api"},
+		Namespace: "commerce",
+		Files:     []string{"k8s/deployment.yaml", "k8s/service.yaml"},
+		Validation: &ValidationResult{
+			Passed:  false,
+			Summary: "1 warning",
+			Issues:  []ValidationIssue{{Severity: SeverityWarning, Category: "security", Message: "runs as root"}},
+		},
+		Persona: &types.ApplicationPersona{Spec: types.PersonaSpec{
+			Tier:      "critical",
+			Ownership: &types.PersonaOwnership{Team: "payments"},
+			Scaling:   &types.PersonaScaling{MinReplicas: 2, MaxReplicas: 6, TargetCPU: 70},
+		}},
+	}
+
+	title, err := RenderPullRequest("title", DefaultPullRequestTitle, data)
+	if err != nil {
+		t.Fatalf("RenderPullRequest(title) error: %v", err)
+	}
+	if title != "Deploy orders to Kubernetes" {
+		t.Errorf("title = %q", title)
+	}
+
+	body, err := RenderPullRequest("body", DefaultPullRequestBody, data)
+	if err != nil {
+		t.Fatalf("RenderPullRequest(body) error: %v", err)
+	}
+	for _, want := range []string{
+		"**orders**",
+		"`commerce`",
+		"| Tier | critical |",
+		"| Team | payments |",
+		"| Owner | - |",
+		"| Scaling | 2-6 replicas at 70% CPU |",
+		"- `k8s/service.yaml`",
+		"Found issues: 1 warning",
+		"[security] runs as root",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+
+	// Without persona and validation
+	body, err = RenderPullRequest("body", DefaultPullRequestBody, PullRequestData{Analysis: &types.AppAnalysis{Name: "orders"}})
+	if err != nil {
+		t.Fatalf("RenderPullRequest(body) error: %v", err)
+	}
+	if strings.Contains(body, "### Persona") || !strings.Contains(body, "Skipped") {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+
+	if _, err := RenderPullRequest("body", "{{ .Nope }}", data); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
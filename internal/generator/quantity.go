@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// binarySuffix maps decimal memory suffixes to their binary counterparts
+var binarySuffix = map[string]string{"k": "Ki", "M": "Mi", "G": "Gi", "T": "Ti", "P": "Pi", "E": "Ei"}
+
+// ParseQuantity parses a cpu or memory quantity the way the API server does,
+// with an error that suggests the right spelling
+func ParseQuantity(name, value string) (resource.Quantity, error) {
+	q, err := resource.ParseQuantity(strings.TrimSpace(value))
+	if err != nil {
+		hint := "e.g. 500m, 0.5, or 2"
+		if name == "memory" {
+			hint = "e.g. 512Mi, 1Gi, or 500M"
+			if trimmed := strings.TrimRight(value, "Bb"); trimmed != value {
+				if _, err := resource.ParseQuantity(trimmed); err == nil {
+					hint = fmt.Sprintf("drop the B: %q", trimmed)
+					if binary, ok := binarySuffix[trimmed[len(trimmed)-1:]]; ok {
+						hint += fmt.Sprintf(", or %q for binary units", trimmed[:len(trimmed)-1]+binary)
+					}
+				}
+			}
+		}
+		return q, fmt.Errorf("%q is not a valid %s quantity (%s)", value, name, hint)
+	}
+	if q.Sign() < 0 {
+		return q, fmt.Errorf("%q is negative", value)
+	}
+	return q, nil
+}
+
+// parseCPUMillis returns a CPU quantity in millicores, or 0 when empty or
+// invalid
+func parseCPUMillis(cpu string) int64 {
+	q, err := ParseQuantity("cpu", cpu)
+	if cpu == "" || err != nil {
+		return 0
+	}
+	return q.MilliValue()
+}
+
+// parseMemoryBytes returns a memory quantity in bytes, or 0 when empty or
+// invalid
+func parseMemoryBytes(mem string) int64 {
+	q, err := ParseQuantity("memory", mem)
+	if mem == "" || err != nil {
+		return 0
+	}
+	return q.Value()
+}
+
+// checkQuantities validates the resource quantities the app uses: the org
+// defaults, the app's profile, and the app's overrides, naming the config
+// entry of each invalid one
+func checkQuantities(analysis *types.AppAnalysis, cfg *config.Config) error {
+	var errs []error
+	check := func(source, field, name, value string) {
+		if value == "" {
+			return
+		}
+		if _, err := ParseQuantity(name, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s in the %s: %w", field, source, err))
+		}
+	}
+	spec := func(source, field string, s config.ResourceSpec) {
+		check(source, field+".requests.cpu", "cpu", s.Requests.CPU)
+		check(source, field+".requests.memory", "memory", s.Requests.Memory)
+		check(source, field+".limits.cpu", "cpu", s.Limits.CPU)
+		check(source, field+".limits.memory", "memory", s.Limits.Memory)
+	}
+
+	spec("org .dorgu.yaml", "resources.defaults", cfg.Resources.Defaults)
+	if p, ok := cfg.Resources.Profiles[analysis.ResourceProfile]; ok {
+		spec("org .dorgu.yaml", "resources.profiles."+analysis.ResourceProfile, p)
+	}
+	overrides := func(field string, r *types.ResourceOverrides) {
+		if r == nil {
+			return
+		}
+		spec("app .dorgu.yaml", field, config.ResourceSpec{
+			Requests: config.ResourceValues{CPU: r.RequestsCPU, Memory: r.RequestsMemory},
+			Limits:   config.ResourceValues{CPU: r.LimitsCPU, Memory: r.LimitsMemory},
+		})
+	}
+	if analysis.AppConfig != nil {
+		overrides("resources", analysis.AppConfig.Resources)
+		for i, c := range analysis.AppConfig.Containers {
+			overrides(fmt.Sprintf("containers[%d].resources", i), c.Resources)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestParseQuantities(t *testing.T) {
+	cpu := []struct {
+		in   string
+		want int64
+	}{
+		{"500m", 500},
+		{"0.5", 500},
+		{"2", 2000},
+		{"", 0},
+		{"lots", 0},
+	}
+	for _, tt := range cpu {
+		if got := parseCPUMillis(tt.in); got != tt.want {
+			t.Errorf("parseCPUMillis(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+	memory := []struct {
+		in   string
+		want int64
+	}{
+		{"512Mi", 512 << 20},
+		{"512M", 512_000_000},
+		{"1Gi", 1 << 30},
+		{"1.5Gi", 3 << 29},
+		{"128974848", 128974848},
+		{"512MB", 0},
+	}
+	for _, tt := range memory {
+		if got := parseMemoryBytes(tt.in); got != tt.want {
+			t.Errorf("parseMemoryBytes(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseQuantityErrors(t *testing.T) {
+	tests := []struct{ name, value, want string }{
+		{"memory", "512MB", `drop the B: "512M", or "512Mi" for binary units`},
+		{"memory", "1GiB", `drop the B: "1Gi"`},
+		{"memory", "lots", "e.g. 512Mi"},
+		{"cpu", "half", "e.g. 500m"},
+		{"cpu", "-1", "negative"},
+	}
+	for _, tt := range tests {
+		_, err := ParseQuantity(tt.name, tt.value)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseQuantity(%s, %q) error = %v, want it to mention %q", tt.name, tt.value, err, tt.want)
+		}
+	}
+}
+
+func TestCheckQuantities(t *testing.T) {
+	cfg := config.Default()
+	cfg.Resources.Defaults.Limits.Memory = "1GB"
+	analysis := &types.AppAnalysis{Name: "orders", AppConfig: &types.AppConfigContext{
+		Resources: &types.ResourceOverrides{RequestsCPU: "half"},
+	}}
+	err := checkQuantities(analysis, cfg)
+	if err == nil {
+		t.Fatal("checkQuantities() = nil, want errors")
+	}
+	for _, want := range []string{"resources.defaults.limits.memory in the org .dorgu.yaml", "resources.requests.cpu in the app .dorgu.yaml"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	if _, err := Generate(context.Background(), analysis, Options{Config: cfg, SkipPersona: true}); err == nil {
+		t.Error("Generate(context.Background(), ) accepted invalid quantities")
+	}
+}
@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/llm"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// ReadmeFile is the README generated into the output directory
+const ReadmeFile = "README.md"
+
+// fileDescriptions describe the files Generate names itself
+var fileDescriptions = map[string]string{
+	"deployment.yaml":          "Deployment running the application's pods",
+	NginxConfigMapFile:         "ConfigMap holding the nginx.conf the static site is served with",
+	"service.yaml":             "Service giving the pods a stable in-cluster address",
+	"ingress.yaml":             "Ingress routing external HTTP(S) traffic to the Service",
+	"hpa.yaml":                 "HorizontalPodAutoscaler scaling the Deployment with load",
+	"servicemonitor.yaml":      "ServiceMonitor telling Prometheus to scrape the metrics endpoint",
+	"secretproviderclass.yaml": "SecretProviderClass syncing secrets from Vault through the Secrets Store CSI driver",
+	SlothFile:                  "Sloth PrometheusServiceLevel generating the SLO recording and alerting rules",
+	OpenSLOFile:                "OpenSLO definitions of the app's SLOs, for your SLO tooling",
+	"persona.yaml":             "ApplicationPersona describing the app to the dorgu operator",
+	KustomizationFile:          "Kustomization listing the manifests, for `kubectl apply -k`",
+	TerraformFile:              "Terraform configuration declaring the manifests as `kubernetes_manifest` resources",
+	CDK8sFile:                  "cdk8s chart declaring the manifests",
+	"cdk8s.yaml":               "cdk8s CLI config",
+	PulumiFile:                 "Pulumi program declaring the manifests",
+	"Pulumi.yaml":              "Pulumi project",
+	"package.json":             "npm dependencies of the program",
+	"tsconfig.json":            "TypeScript compiler options",
+	"argocd/application.yaml":  "ArgoCD Application syncing this directory to the cluster",
+	LockFile:                   "Inputs and checksums of the last `dorgu generate` run",
+	RenovateFile:               "Renovate config opening update PRs for the manifests' images and the workflows' actions",
+	DependabotFile:             "Dependabot config opening update PRs for the manifests' images and the workflows' actions",
+	DevcontainerFile:           "Dev container for VS Code and Codespaces with the app's toolchain and ports forwarded",
+	DevfileFile:                "Devfile for odo and cloud IDEs running the app from source",
+	SkaffoldFile:               "Skaffold config for `skaffold dev`: builds the image, deploys these manifests, forwards the ports",
+	TiltFile:                   "Tiltfile for `tilt up`: builds the image, deploys these manifests, forwards the ports",
+	SmokeTestFile:              "Post-deploy hook Job requesting the readiness path and key routes through the Service",
+}
+
+// programDocs tell how to deploy each program format and set its image
+var programDocs = map[string]struct{ deploy, setImage string }{
+	FormatTerraform: {
+		deploy: "Configure the `hashicorp/kubernetes` provider for your cluster, then apply [`" + TerraformFile + "`](" + TerraformFile + "):\n\n" +
+			"```bash\nterraform init\nterraform apply\n```\n\n" +
+			"Custom resources such as a ServiceMonitor need their CRDs installed before `terraform plan`.\n",
+		setImage: "terraform apply -var image=<image>:<tag>",
+	},
+	FormatCDK8s: {
+		deploy:   "Synthesize [`" + CDK8sFile + "`](" + CDK8sFile + ") into `dist/` and apply the result:\n\n```bash\nnpm install\nnpx cdk8s synth\nkubectl apply -f dist/\n```\n",
+		setImage: "IMAGE=<image>:<tag> npx cdk8s synth",
+	},
+	FormatPulumi: {
+		deploy:   "Deploy [`" + PulumiFile + "`](" + PulumiFile + ") to the cluster of your kubeconfig:\n\n```bash\nnpm install\npulumi up\n```\n",
+		setImage: "pulumi config set image <image>:<tag>",
+	},
+}
+
+// kindPattern finds the kind of a manifest
+var kindPattern = regexp.MustCompile(`(?m)^kind:\s*(\S+)`)
+
+// describeFile returns what a generated file is for
+func describeFile(f GeneratedFile, opts Options) string {
+	if d, ok := fileDescriptions[f.Path]; ok {
+		return d
+	}
+	switch {
+	case f.Path == opts.ciPath():
+		return "GitHub Actions workflow building the image and updating its tag here"
+	case f.Path == opts.personaPath():
+		return "Persona: what the app is, who owns it, and how to operate it"
+	case f.Path == opts.SingleFile:
+		return "All Kubernetes manifests as one multi-document file"
+	case strings.HasPrefix(f.Path, "jobs/"):
+		return "Job " + strings.TrimSuffix(strings.TrimPrefix(f.Path, "jobs/"), ".yaml")
+	}
+	if m := kindPattern.FindStringSubmatch(f.Content); m != nil {
+		return m[1]
+	}
+	return "Generated file"
+}
+
+// GenerateReadme documents the generated files, plus the kustomization (in
+// YAML format) and lock file written after it: what each is for, how to deploy them, how the
+// image tag is updated, and where the persona and runbook are
+func GenerateReadme(analysis *types.AppAnalysis, opts Options, files []GeneratedFile) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s Kubernetes manifests\n\n", analysis.Name)
+	fmt.Fprintf(&b, "Manifests for deploying `%s` to the `%s` namespace, generated by dorgu.\n", analysis.Name, opts.Namespace)
+	b.WriteString("Regenerate them with `dorgu generate` rather than editing by hand.\n\n")
+
+	b.WriteString("## Files\n\n| File | Purpose |\n|------|---------|\n")
+	var listed []GeneratedFile
+	if opts.gitOps() {
+		listed = append(listed, GeneratedFile{Path: KustomizationFile})
+	}
+	listed = append(listed, files...)
+	if opts.Provenance != nil {
+		listed = append(listed, GeneratedFile{Path: LockFile})
+	}
+	sort.SliceStable(listed, func(i, j int) bool { return listed[i].Path < listed[j].Path })
+	for _, f := range listed {
+		fmt.Fprintf(&b, "| [`%s`](%s) | %s |\n", f.Path, f.Path, describeFile(f, opts))
+	}
+
+	b.WriteString("\n## Deploying\n\n")
+	switch {
+	case !opts.gitOps():
+		b.WriteString(programDocs[opts.Format].deploy)
+	case hasFile(files, "argocd/application.yaml"):
+		b.WriteString("With ArgoCD, register the Application once; ArgoCD then syncs every change to this directory:\n\n")
+		b.WriteString("```bash\nkubectl apply -f argocd/application.yaml\n```\n\n")
+		b.WriteString("Without ArgoCD, apply the manifests directly:\n\n")
+		fmt.Fprintf(&b, "```bash\nkubectl apply -k . -n %s\n```\n", opts.Namespace)
+	default:
+		b.WriteString("Apply the manifests with kubectl:\n\n")
+		fmt.Fprintf(&b, "```bash\nkubectl apply -k . -n %s\n```\n", opts.Namespace)
+	}
+	if analysis.AppConfig != nil && len(analysis.AppConfig.Jobs) > 0 {
+		b.WriteString("\nJobs with a pre-deploy or post-deploy hook run on every ArgoCD sync or Helm upgrade; with kubectl, apply them yourself in that order.\n")
+	}
+
+	b.WriteString("\n## Image updates\n\n")
+	if !opts.gitOps() {
+		fmt.Fprintf(&b, "The image defaults to `%s`; roll out a new tag with `%s`.\n", appImage(analysis, opts.Config), programDocs[opts.Format].setImage)
+	} else if hasFile(files, opts.ciPath()) {
+		fmt.Fprintf(&b, "The workflow in [`%s`](%s) builds the image on every push to main and commits the new tag to `deployment.yaml`", opts.ciPath(), opts.ciPath())
+		if hasFile(files, "argocd/application.yaml") {
+			b.WriteString(", which ArgoCD then rolls out")
+		}
+		b.WriteString(".\n")
+	} else {
+		fmt.Fprintf(&b, "Set the image tag in `deployment.yaml`, or run `kustomize edit set image %s=<image>:<tag>`.\n", appImage(analysis, opts.Config))
+	}
+
+	var links []string
+	if hasFile(files, opts.personaPath()) {
+		links = append(links, fmt.Sprintf("- [Persona](%s)", opts.personaPath()))
+	}
+	if analysis.AppConfig != nil && analysis.AppConfig.Operations != nil && analysis.AppConfig.Operations.Runbook != "" {
+		links = append(links, fmt.Sprintf("- [Runbook](%s)", analysis.AppConfig.Operations.Runbook))
+	}
+	if len(links) > 0 {
+		b.WriteString("\n## Operations\n\n" + strings.Join(links, "\n") + "\n")
+	}
+	return b.String()
+}
+
+// polishReadme asks the LLM to improve the README's prose, keeping the
+// templated README when the LLM fails or drops a file, command, or link
+func polishReadme(ctx context.Context, readme string, opts Options, files []GeneratedFile) string {
+	client, err := llm.NewClient(opts.Config.LLM.Provider)
+	if err != nil {
+		slog.Warn("README polishing skipped", "err", err)
+		return readme
+	}
+	prompt := "Improve the wording of this README for a Kubernetes manifests directory. " +
+		"Keep every heading, file name, link, and code block exactly as it is. " +
+		"Return only the Markdown.\n\n" + readme
+	polished, err := client.Complete(ctx, prompt)
+	if err != nil {
+		slog.Warn("README polishing failed, using the template", "err", err)
+		return readme
+	}
+	polished = strings.TrimSpace(polished)
+	if strings.HasPrefix(polished, "```") {
+		// Unwrap a response fenced as a whole
+		if i := strings.Index(polished, "\n"); i > 0 {
+			polished = strings.TrimSpace(strings.TrimSuffix(polished[i+1:], "```"))
+		}
+	}
+	polished += "\n"
+	for _, f := range files {
+		if !strings.Contains(polished, f.Path) {
+			slog.Warn("polished README dropped a file, using the template", "file", f.Path)
+			return readme
+		}
+	}
+	if strings.Count(polished, "```") != strings.Count(readme, "```") {
+		slog.Warn("polished README changed the code blocks, using the template")
+		return readme
+	}
+	return polished
+}
+
+// hasFile reports whether files include path
+func hasFile(files []GeneratedFile, path string) bool {
+	for _, f := range files {
+		if f.Path == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestGenerateReadme(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:  "orders",
+		Ports: []types.Port{{Port: 8080}},
+		AppConfig: &types.AppConfigContext{
+			Operations: &types.OperationsContext{Runbook: "https://wiki.example.com/orders"},
+			Jobs:       []types.JobContext{{Name: "migrate", Command: []string{"migrate"}, Hook: config.HookPreDeploy}},
+		},
+	}
+	files, err := Generate(context.Background(), analysis, Options{Namespace: "shop", Config: config.Default(), NoLLMPersona: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var readme string
+	for _, f := range files {
+		if f.Path == ReadmeFile {
+			readme = f.Content
+		}
+	}
+	for _, want := range []string{
+		"| [`deployment.yaml`](deployment.yaml) | Deployment",
+		"| [`jobs/migrate.yaml`](jobs/migrate.yaml) | Job migrate |",
+		"| [`kustomization.yaml`](kustomization.yaml) |",
+		"[`../.github/workflows/deploy.yaml`]",
+		"kubectl apply -f argocd/application.yaml",
+		"kubectl apply -k . -n shop",
+		"[Persona](../PERSONA.md)",
+		"[Runbook](https://wiki.example.com/orders)",
+	} {
+		if !strings.Contains(readme, want) {
+			t.Errorf("README missing %q:\n%s", want, readme)
+		}
+	}
+
+	files, err = Generate(context.Background(), analysis, Options{Namespace: "shop", Config: config.Default(), NoLLMPersona: true, SkipReadme: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasFile(files, ReadmeFile) {
+		t.Errorf("README generated with SkipReadme")
+	}
+}
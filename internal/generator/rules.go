@@ -0,0 +1,227 @@
+package generator
+
+import "strings"
+
+// RulesDocURL is the page documenting every validation rule; each rule's
+// section is anchored by its lowercase ID
+const RulesDocURL = "https://github.com/dorgu-ai/dorgu/blob/main/docs/validation-rules.md"
+
+// IDs of the validation rules. They are stable: suppressions, CI
+// annotations, and the docs refer to them.
+const (
+	RuleImagePlaceholder       = "DORGU-IMG-001"
+	RuleImageLatestTag         = "DORGU-IMG-002"
+	RuleImageMissing           = "DORGU-IMG-003"
+	RuleImageUnchecked         = "DORGU-IMG-004"
+	RuleCPURequestOverLimit    = "DORGU-RES-001"
+	RuleMemRequestOverLimit    = "DORGU-RES-002"
+	RuleProbePort              = "DORGU-PRT-001"
+	RuleListenPort             = "DORGU-PRT-002"
+	RuleHPAMinOverMax          = "DORGU-HPA-001"
+	RuleIngressHostEmpty       = "DORGU-ING-001"
+	RuleIngressHostInvalid     = "DORGU-ING-002"
+	RuleIngressHostDomain      = "DORGU-ING-003"
+	RuleIngressHostDNS         = "DORGU-ING-004"
+	RuleIngressBackendPort     = "DORGU-ING-005"
+	RuleIngressCertManager     = "DORGU-ING-006"
+	RuleIngressClusterIssuer   = "DORGU-ING-007"
+	RuleServiceStaticIP        = "DORGU-SVC-001"
+	RuleVaultAgentSecrets      = "DORGU-SEC-001"
+	RuleNoHealthProbes         = "DORGU-HLT-001"
+	RuleScheduleUnconfigured   = "DORGU-JOB-001"
+	RuleMigrationsUnconfigured = "DORGU-JOB-002"
+	RuleNginxConfigCopied      = "DORGU-WEB-001"
+	RuleAppNameMissing         = "DORGU-MET-001"
+	RuleRepositoryMissing      = "DORGU-MET-002"
+	RuleAppNameRenamed         = "DORGU-MET-003"
+	RuleAppNameInvalid         = "DORGU-MET-004"
+	RuleTeamUnknown            = "DORGU-OWN-001"
+	RuleOwnerUnknown           = "DORGU-OWN-002"
+	RuleOwnerNotInTeam         = "DORGU-OWN-003"
+	RulePodSecurity            = "DORGU-PSS-001"
+	RuleKubectlDryRunFailed    = "DORGU-KUB-001"
+	RuleKubectlDryRunPassed    = "DORGU-KUB-002"
+	RuleAPIVersion             = "DORGU-CLU-001"
+	RuleNamespaceCreated       = "DORGU-CLU-002"
+	RuleNamespaceMissing       = "DORGU-CLU-003"
+	RuleIngressClassMissing    = "DORGU-CLU-004"
+	RuleNoIngressClass         = "DORGU-CLU-005"
+	RuleNoDefaultStorage       = "DORGU-CLU-006"
+	RuleStorageClassMissing    = "DORGU-CLU-007"
+	RuleSuppressionExpired     = "DORGU-SUP-001"
+	RuleSuppressionUnused      = "DORGU-SUP-002"
+)
+
+// Rule is a validation check and what its findings mean
+type Rule struct {
+	ID       string             `json:"id"`
+	Category string             `json:"category"`
+	Severity ValidationSeverity `json:"severity"`
+	Title    string             `json:"title"`
+	// Description explains why the finding matters and how to fix it
+	Description string `json:"description"`
+}
+
+// DocURL links to the rule's section of RulesDocURL
+func (r Rule) DocURL() string {
+	return RulesDocURL + "#" + strings.ToLower(r.ID)
+}
+
+// rules is the catalog of validation rules, grouped by category
+var rules = []Rule{
+	{RuleImagePlaceholder, "image", SeverityWarning, "Container image is a placeholder",
+		"No ci.registry is configured, so the Deployment runs a placeholder image named after the app, which no cluster can pull. Set defaults.registry with 'dorgu config set' or ci.registry in .dorgu.yaml."},
+	{RuleImageLatestTag, "image", SeverityInfo, "Image uses the latest tag",
+		"A mutable tag makes rollouts unreproducible and rollbacks ineffective. The generated CI workflow commits immutable tags; pin one when deploying by hand."},
+	{RuleImageMissing, "image", SeverityError, "Image does not exist in the registry",
+		"With validation.image_exists enabled, the registry has no manifest for the referenced tag, so pods would stall in ImagePullBackOff. It is an error in the environments under validation.image_exists.environments and a warning elsewhere. Push the image before deploying."},
+	{RuleImageUnchecked, "image", SeverityWarning, "Image could not be checked",
+		"The registry could not be reached or refused the credentials, so validation.image_exists could not tell whether the image exists. Check username_env and password_env, or your Docker login."},
+	{RuleCPURequestOverLimit, "resources", SeverityError, "CPU request exceeds the limit",
+		"The API server rejects containers whose CPU request is above their limit. Lower resources.requests.cpu or raise resources.limits.cpu."},
+	{RuleMemRequestOverLimit, "resources", SeverityError, "Memory request exceeds the limit",
+		"The API server rejects containers whose memory request is above their limit. Lower resources.requests.memory or raise resources.limits.memory."},
+	{RuleProbePort, "ports", SeverityWarning, "Probe port is not a container port",
+		"A liveness or readiness probe targets a port the container does not declare, so it likely fails and restarts or withholds the pod. Align health.*.port with the app's ports."},
+	{RuleListenPort, "ports", SeverityWarning, "Listen port conflicts with the declared ports",
+		"The framework config or code binds a port (package.json start script, app.run(port=...), server.port, Run(\":8081\")) that the Dockerfile's EXPOSE or the container ports leave out. dorgu uses the listen port over EXPOSE; align EXPOSE with it, or pin ports in .dorgu-analysis.yaml."},
+	{RuleHPAMinOverMax, "scaling", SeverityError, "HPA minReplicas exceeds maxReplicas",
+		"The API server rejects such a HorizontalPodAutoscaler. Set scaling.min_replicas no higher than scaling.max_replicas."},
+	{RuleIngressHostEmpty, "ingress", SeverityWarning, "Ingress host is empty",
+		"An Ingress rule without a host matches every host the controller serves. Set ingress.host, or ingress.domain_suffix in the org config."},
+	{RuleIngressHostInvalid, "ingress", SeverityError, "Ingress host is not a fully qualified domain name",
+		"Hosts must be lowercase DNS names with at least two labels; a wildcard may only be the first label. The API server or the certificate issuer rejects anything else."},
+	{RuleIngressHostDomain, "ingress", SeverityError, "Ingress host is outside the allowed domains",
+		"validation.ingress.domain_suffixes lists the domains the platform serves and has certificates for. Pick a host under one of them."},
+	{RuleIngressHostDNS, "ingress", SeverityWarning, "Ingress host does not resolve",
+		"With validation.ingress.resolve_hosts, the host has no DNS record, so clients cannot reach it and HTTP-01 certificate challenges fail. Create the record, or let external-dns manage it."},
+	{RuleIngressBackendPort, "ingress", SeverityError, "Ingress path routes to a port the Service does not expose",
+		"The controller returns 503 for a backend port the Service lacks. Use one of the app's ports, or set ingress.paths[].service to route to another Service."},
+	{RuleIngressCertManager, "ingress", SeverityError, "cert-manager is not installed",
+		"With --against-cluster, TLS certificates are requested from a ClusterIssuer, but the cluster has no cert-manager CRDs, so no certificate is issued. Install cert-manager or provide the TLS secret another way."},
+	{RuleIngressClusterIssuer, "ingress", SeverityError, "ClusterIssuer does not exist",
+		"With --against-cluster, the ClusterIssuer in ingress.tls.cluster_issuer is not in the cluster, so cert-manager leaves the certificate pending. Create it or configure one that exists."},
+	{RuleServiceStaticIP, "service", SeverityWarning, "Static load balancer IP is ignored",
+		"AWS load balancers ignore spec.loadBalancerIP. Use an NLB with Elastic IP allocations through service.annotations."},
+	{RuleVaultAgentSecrets, "secrets", SeverityInfo, "Secrets are rendered to a file",
+		"The Vault Agent injector writes secrets to a file instead of environment variables. Source the file before starting the app."},
+	{RuleNoHealthProbes, "health", SeverityWarning, "No health probes",
+		"Without probes, Kubernetes routes traffic to pods that are not ready and never restarts hung ones. Set health.liveness and health.readiness, or serve a /health endpoint."},
+	{RuleScheduleUnconfigured, "jobs", SeverityInfo, "Cron schedule is not a CronJob",
+		"The analysis found a cron schedule (a crontab file, node-cron, APScheduler, a schedule comment, or a compose scheduler label) that no job in .dorgu.yaml runs. Add a job with that schedule and a command that runs the task once to generate a CronJob, or confirm it with generate --review-analysis."},
+	{RuleMigrationsUnconfigured, "jobs", SeverityInfo, "Database migrations are not run by a job",
+		"The analysis found a migration tool (Flyway, Liquibase, Alembic, golang-migrate, or Prisma Migrate) that the app does not run as it starts and no job in .dorgu.yaml runs. dorgu generates the job only when migrations.job is set in the workspace .dorgu.yaml, since a pre-deploy hook blocks every rollout until it succeeds. Set it, with migrations.image when the app's image lacks the tool's CLI, or add a pre-deploy job named migrate to the app's .dorgu.yaml; either way the command needs the database settings it reads."},
+	{RuleNginxConfigCopied, "static", SeverityWarning, "Static site brings its own nginx configuration",
+		"The Dockerfile copies a configuration into /etc/nginx, so the generated nginx.conf, which serves the site on 8080 as a non-root user, is not used and the app is deployed as a generic web app on its own port. That Deployment runs as non-root with a read-only root filesystem, which the official nginx image's configuration does not support. Remove the COPY, or make the configuration listen above 1024 and keep its pid and temp files on a writable volume."},
+	{RuleAppNameMissing, "metadata", SeverityError, "Application name is missing",
+		"Every resource is named after the app. Set app.name in .dorgu.yaml or pass --name."},
+	{RuleRepositoryMissing, "metadata", SeverityInfo, "Repository URL is not set",
+		"The ArgoCD Application needs the repository to sync from. Set app.repository in .dorgu.yaml or configure the git remote origin."},
+	{RuleAppNameRenamed, "metadata", SeverityError, "App name was renamed to a valid resource name",
+		"naming.dns_safe changed the app's name to a DNS-1123 name, so its resources differ from what the name suggests. Set app.name to the new name to accept it."},
+	{RuleAppNameInvalid, "metadata", SeverityError, "App name is not a valid resource name",
+		"Resource names must be lowercase DNS-1123 labels, or the API server rejects them. Set app.name, or enable naming.dns_safe."},
+	{RuleTeamUnknown, "ownership", SeverityError, "Team is not in the owners directory",
+		"app.team names a team the owners directory in owners.source does not know, so the persona would carry a dead team."},
+	{RuleOwnerUnknown, "ownership", SeverityError, "Owner is not in the owners directory",
+		"app.owner names a person or mailbox the owners directory in owners.source does not know."},
+	{RuleOwnerNotInTeam, "ownership", SeverityWarning, "Owner is not a member of the team",
+		"app.owner is in the owners directory but not in app.team. Check both are current."},
+	{RulePodSecurity, "security", SeverityError, "Pod violates the Pod Security Standard",
+		"The namespace enforces the app's security.pod_security_standard and would reject the pods. Fix the security settings or declare a lower standard."},
+	{RuleKubectlDryRunFailed, "kubectl", SeverityError, "kubectl dry run failed",
+		"kubectl apply --dry-run=client rejected the core manifests. The message has kubectl's reason."},
+	{RuleKubectlDryRunPassed, "kubectl", SeverityInfo, "kubectl dry run passed",
+		"kubectl apply --dry-run=client accepted the core manifests."},
+	{RuleAPIVersion, "cluster", SeverityError, "Cluster does not serve an API version",
+		"With --against-cluster, an object uses an API version the cluster lacks, from an older Kubernetes or a missing CRD, and would be rejected. A missing ArgoCD API is a warning, as the Application is often applied to another cluster."},
+	{RuleNamespaceCreated, "cluster", SeverityInfo, "Namespace is created by ArgoCD",
+		"With --against-cluster, the namespace does not exist yet; the ArgoCD Application creates it on the first sync."},
+	{RuleNamespaceMissing, "cluster", SeverityError, "Namespace does not exist",
+		"With --against-cluster, the namespace does not exist and nothing creates it, so applying the manifests fails. Create it or generate for an existing one."},
+	{RuleIngressClassMissing, "cluster", SeverityError, "IngressClass does not exist",
+		"With --against-cluster, no controller serves the Ingress's class. Set ingress.class to an installed one."},
+	{RuleNoIngressClass, "cluster", SeverityWarning, "Cluster has no IngressClass",
+		"With --against-cluster, the cluster has no ingress controller registered, so the Ingress may never be served."},
+	{RuleNoDefaultStorage, "cluster", SeverityWarning, "No default StorageClass",
+		"With --against-cluster, a claim has no storage class and the cluster has no default one, so it stays Pending."},
+	{RuleStorageClassMissing, "cluster", SeverityError, "StorageClass does not exist",
+		"With --against-cluster, a claim names a storage class the cluster lacks, so it stays Pending."},
+	{RuleSuppressionExpired, "suppressions", SeverityWarning, "Suppression expired",
+		"A suppression in .dorgu-suppressions.yaml is past its expires date, so the findings it covered are reported again. Fix them or renew the suppression with a fresh justification."},
+	{RuleSuppressionUnused, "suppressions", SeverityInfo, "Suppression matches no finding",
+		"A suppression in .dorgu-suppressions.yaml covers nothing; remove it so the file stays accurate."},
+}
+
+// ruleConfigKeys are the .dorgu.yaml settings the findings of rules usually
+// come from
+var ruleConfigKeys = map[string]string{
+	RuleImagePlaceholder:       "ci.registry",
+	RuleImageMissing:           "validation.image_exists",
+	RuleImageUnchecked:         "validation.image_exists",
+	RuleCPURequestOverLimit:    "resources.requests.cpu",
+	RuleMemRequestOverLimit:    "resources.requests.memory",
+	RuleProbePort:              "health",
+	RuleHPAMinOverMax:          "scaling.min_replicas",
+	RuleIngressHostEmpty:       "ingress.host",
+	RuleIngressHostInvalid:     "ingress.host",
+	RuleIngressHostDomain:      "ingress.host",
+	RuleIngressHostDNS:         "ingress.host",
+	RuleIngressBackendPort:     "ingress.paths",
+	RuleIngressCertManager:     "ingress.tls.cluster_issuer",
+	RuleIngressClusterIssuer:   "ingress.tls.cluster_issuer",
+	RuleServiceStaticIP:        "service.static_ip",
+	RuleVaultAgentSecrets:      "secrets",
+	RuleNoHealthProbes:         "health",
+	RuleScheduleUnconfigured:   "jobs",
+	RuleMigrationsUnconfigured: "jobs",
+	RuleAppNameMissing:         "app.name",
+	RuleRepositoryMissing:      "app.repository",
+	RuleAppNameRenamed:         "app.name",
+	RuleAppNameInvalid:         "app.name",
+	RuleTeamUnknown:            "app.team",
+	RuleOwnerUnknown:           "app.owner",
+	RuleOwnerNotInTeam:         "app.owner",
+	RulePodSecurity:            "security",
+	RuleIngressClassMissing:    "ingress.class",
+}
+
+// describeIssue fills in the doc link and, unless the check set it, the
+// config key of issue's rule
+func describeIssue(issue *ValidationIssue) {
+	issue.DocURL = Rule{ID: issue.Rule}.DocURL()
+	if issue.ConfigKey == "" {
+		issue.ConfigKey = ruleConfigKeys[issue.Rule]
+	}
+}
+
+// Rules returns the catalog of validation rules, grouped by category
+func Rules() []Rule {
+	return append([]Rule(nil), rules...)
+}
+
+// LookupRule returns the rule with id, ignoring case
+func LookupRule(id string) (Rule, bool) {
+	for _, r := range rules {
+		if strings.EqualFold(r.ID, id) {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// isRuleID reports whether id is the ID of a rule, ignoring case
+func isRuleID(id string) bool {
+	_, ok := LookupRule(id)
+	return ok
+}
+
+// isRuleCategory reports whether category is the category of any rule
+func isRuleCategory(category string) bool {
+	for _, r := range rules {
+		if r.Category == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestRulesCatalog(t *testing.T) {
+	docs, err := os.ReadFile("../../docs/validation-rules.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	idPattern := regexp.MustCompile(`^DORGU-[A-Z]{3}-\d{3}$`)
+	seen := map[string]bool{}
+	for _, r := range rules {
+		if !idPattern.MatchString(r.ID) {
+			t.Errorf("rule %q: ID does not match %s", r.ID, idPattern)
+		}
+		if seen[r.ID] {
+			t.Errorf("rule %s is listed twice", r.ID)
+		}
+		seen[r.ID] = true
+		if r.Category == "" || r.Title == "" || r.Description == "" {
+			t.Errorf("rule %s: category, title, and description are required", r.ID)
+		}
+		if !strings.Contains(string(docs), "### "+r.ID+"\n") {
+			t.Errorf("rule %s has no section in docs/validation-rules.md", r.ID)
+		}
+	}
+	for id := range ruleConfigKeys {
+		if !seen[id] {
+			t.Errorf("ruleConfigKeys maps unknown rule %s", id)
+		}
+	}
+}
+
+func TestValidateGeneratedRules(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:      "Orders_API",
+		Ports:     []types.Port{{Port: 8080}},
+		AppConfig: &types.AppConfigContext{Ingress: &types.IngressContext{Enabled: true, Host: "orders"}},
+	}
+	opts := Options{Namespace: "default", Config: config.Default(), Suppressions: []Suppression{{Rule: "DORGU-IMG-002", Justification: "tags are pinned by CI"}}}
+	result := ValidateGenerated(context.Background(), analysis, nil, opts)
+	if len(result.Issues) == 0 || len(result.Suppressed) != 1 {
+		t.Fatalf("issues %+v, suppressed %+v", result.Issues, result.Suppressed)
+	}
+	for _, issue := range append(result.Issues, result.Suppressed[0].ValidationIssue) {
+		rule, ok := LookupRule(issue.Rule)
+		if !ok {
+			t.Errorf("issue %q has unknown rule %q", issue.Message, issue.Rule)
+			continue
+		}
+		if rule.Category != issue.Category {
+			t.Errorf("issue %q has category %s, its rule %s", issue.Message, issue.Category, rule.Category)
+		}
+		if issue.DocURL != RulesDocURL+"#"+strings.ToLower(rule.ID) {
+			t.Errorf("issue %q links to %q", issue.Message, issue.DocURL)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// ScoreCheck is one production-readiness check
+type ScoreCheck struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Weight      int    `json:"weight"`
+	Passed      bool   `json:"passed"`
+	Detail      string `json:"detail,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// Scorecard is the production-readiness report of an application
+type Scorecard struct {
+	App string `json:"app"`
+	// Score is the sum of the weights of the passed checks, out of 100
+	Score  int          `json:"score"`
+	Grade  string       `json:"grade"`
+	Checks []ScoreCheck `json:"checks"`
+}
+
+// manifests are the Kubernetes objects dorgu scores, parsed from the
+// generated files
+type manifests struct {
+	deployments []appsv1.Deployment
+	ingresses   []networkingv1.Ingress
+	kinds       map[string]bool
+}
+
+// parseManifests decodes every YAML document in files, skipping anything
+// that is not a Kubernetes object (PERSONA.md, workflows)
+func parseManifests(files []GeneratedFile) (*manifests, error) {
+	m := &manifests{kinds: map[string]bool{}}
+	for _, f := range files {
+		if !strings.HasSuffix(f.Path, ".yaml") && !strings.HasSuffix(f.Path, ".yml") {
+			continue
+		}
+		for _, doc := range strings.Split(f.Content, "\n---") {
+			var meta struct {
+				APIVersion string `json:"apiVersion"`
+				Kind       string `json:"kind"`
+			}
+			if err := yaml.Unmarshal([]byte(doc), &meta); err != nil || meta.APIVersion == "" || meta.Kind == "" {
+				continue
+			}
+			m.kinds[meta.Kind] = true
+			switch meta.Kind {
+			case "Deployment":
+				var d appsv1.Deployment
+				if err := yaml.Unmarshal([]byte(doc), &d); err != nil {
+					return nil, fmt.Errorf("failed to parse %s: %w", f.Path, err)
+				}
+				m.deployments = append(m.deployments, d)
+			case "Ingress":
+				var ing networkingv1.Ingress
+				if err := yaml.Unmarshal([]byte(doc), &ing); err != nil {
+					return nil, fmt.Errorf("failed to parse %s: %w", f.Path, err)
+				}
+				m.ingresses = append(m.ingresses, ing)
+			}
+		}
+	}
+	return m, nil
+}
+
+// containers returns the containers of all parsed Deployments
+func (m *manifests) containers() []corev1.Container {
+	var out []corev1.Container
+	for _, d := range m.deployments {
+		out = append(out, d.Spec.Template.Spec.Containers...)
+	}
+	return out
+}
+
+// Score computes the production-readiness scorecard from the analysis and the
+// generated manifests
+func Score(analysis *types.AppAnalysis, files []GeneratedFile) (*Scorecard, error) {
+	m, err := parseManifests(files)
+	if err != nil {
+		return nil, err
+	}
+	card := &Scorecard{App: analysis.Name}
+	card.Checks = []ScoreCheck{
+		scoreProbes(m),
+		scoreResources(m),
+		scorePDB(m),
+		scoreSecurityContext(m),
+		scoreOwnership(analysis),
+		scoreRunbook(analysis),
+		scoreAlerts(analysis),
+		scorePinnedImage(m),
+		scoreTLS(m),
+	}
+	for _, c := range card.Checks {
+		if c.Passed {
+			card.Score += c.Weight
+		}
+	}
+	card.Grade = grade(card.Score)
+	return card, nil
+}
+
+func grade(score int) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	default:
+		return "F"
+	}
+}
+
+func scoreProbes(m *manifests) ScoreCheck {
+	c := ScoreCheck{ID: "probes", Name: "Liveness and readiness probes", Weight: 15,
+		Remediation: "Add health.liveness and health.readiness in .dorgu.yaml or expose a /health endpoint"}
+	var missing []string
+	for _, ctr := range m.containers() {
+		if ctr.LivenessProbe == nil {
+			missing = append(missing, ctr.Name+" liveness")
+		}
+		if ctr.ReadinessProbe == nil {
+			missing = append(missing, ctr.Name+" readiness")
+		}
+	}
+	return finishCheck(c, len(m.containers()) > 0 && len(missing) == 0, missing)
+}
+
+func scoreResources(m *manifests) ScoreCheck {
+	c := ScoreCheck{ID: "resources", Name: "CPU and memory requests and limits", Weight: 15,
+		Remediation: "Set resources.requests and resources.limits in .dorgu.yaml"}
+	var missing []string
+	for _, ctr := range m.containers() {
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if _, ok := ctr.Resources.Requests[name]; !ok {
+				missing = append(missing, fmt.Sprintf("%s %s request", ctr.Name, name))
+			}
+			if _, ok := ctr.Resources.Limits[name]; !ok {
+				missing = append(missing, fmt.Sprintf("%s %s limit", ctr.Name, name))
+			}
+		}
+	}
+	return finishCheck(c, len(m.containers()) > 0 && len(missing) == 0, missing)
+}
+
+func scorePDB(m *manifests) ScoreCheck {
+	c := ScoreCheck{ID: "pdb", Name: "PodDisruptionBudget", Weight: 10,
+		Remediation: "Add a PodDisruptionBudget with a template override or plugin so node drains keep the app available"}
+	return finishCheck(c, m.kinds["PodDisruptionBudget"], []string{"no PodDisruptionBudget generated"})
+}
+
+func scoreSecurityContext(m *manifests) ScoreCheck {
+	c := ScoreCheck{ID: "security_context", Name: "Restricted security context", Weight: 15,
+		Remediation: "Run as non-root with allowPrivilegeEscalation: false, readOnlyRootFilesystem: true, and all capabilities dropped"}
+	var missing []string
+	for _, d := range m.deployments {
+		pod := d.Spec.Template.Spec.SecurityContext
+		podNonRoot := pod != nil && pod.RunAsNonRoot != nil && *pod.RunAsNonRoot
+		for _, ctr := range d.Spec.Template.Spec.Containers {
+			sc := ctr.SecurityContext
+			if !podNonRoot && (sc == nil || sc.RunAsNonRoot == nil || !*sc.RunAsNonRoot) {
+				missing = append(missing, ctr.Name+" runAsNonRoot")
+			}
+			if sc == nil || sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+				missing = append(missing, ctr.Name+" allowPrivilegeEscalation")
+			}
+			if sc == nil || sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
+				missing = append(missing, ctr.Name+" readOnlyRootFilesystem")
+			}
+			if sc == nil || sc.Capabilities == nil || !dropsAll(sc.Capabilities.Drop) {
+				missing = append(missing, ctr.Name+" capabilities.drop ALL")
+			}
+		}
+	}
+	return finishCheck(c, len(m.deployments) > 0 && len(missing) == 0, missing)
+}
+
+func dropsAll(caps []corev1.Capability) bool {
+	for _, c := range caps {
+		if c == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
+func scoreOwnership(analysis *types.AppAnalysis) ScoreCheck {
+	c := ScoreCheck{ID: "owner", Name: "Owner metadata", Weight: 10,
+		Remediation: "Set app.team and app.owner in .dorgu.yaml"}
+	var missing []string
+	if analysis.Team == "" {
+		missing = append(missing, "team")
+	}
+	if analysis.Owner == "" {
+		missing = append(missing, "owner")
+	}
+	return finishCheck(c, len(missing) == 0, missing)
+}
+
+func scoreRunbook(analysis *types.AppAnalysis) ScoreCheck {
+	c := ScoreCheck{ID: "runbook", Name: "Runbook", Weight: 10,
+		Remediation: "Set operations.runbook in .dorgu.yaml"}
+	ops := operations(analysis)
+	return finishCheck(c, ops != nil && ops.Runbook != "", []string{"no runbook link"})
+}
+
+func scoreAlerts(analysis *types.AppAnalysis) ScoreCheck {
+	c := ScoreCheck{ID: "alerts", Name: "Alerts", Weight: 10,
+		Remediation: "List the app's alerts under operations.alerts in .dorgu.yaml"}
+	ops := operations(analysis)
+	return finishCheck(c, ops != nil && len(ops.Alerts) > 0, []string{"no alerts configured"})
+}
+
+func operations(analysis *types.AppAnalysis) *types.OperationsContext {
+	if analysis.AppConfig == nil {
+		return nil
+	}
+	return analysis.AppConfig.Operations
+}
+
+func scorePinnedImage(m *manifests) ScoreCheck {
+	c := ScoreCheck{ID: "pinned_image", Name: "Pinned image", Weight: 10,
+		Remediation: "Deploy a version tag or digest instead of :latest (the generated CI workflow sets the tag on release)"}
+	var missing []string
+	for _, ctr := range m.containers() {
+		if !imagePinned(ctr.Image) {
+			missing = append(missing, ctr.Image)
+		}
+	}
+	return finishCheck(c, len(m.containers()) > 0 && len(missing) == 0, missing)
+}
+
+// imagePinned reports whether an image reference has a digest or a tag other
+// than latest
+func imagePinned(image string) bool {
+	if strings.Contains(image, "@sha256:") {
+		return true
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(name, ":")
+	return i >= 0 && name[i+1:] != "latest"
+}
+
+func scoreTLS(m *manifests) ScoreCheck {
+	c := ScoreCheck{ID: "tls", Name: "TLS on ingress", Weight: 5,
+		Remediation: "Set ingress.tls.enabled in the app or workspace .dorgu.yaml"}
+	if len(m.ingresses) == 0 {
+		c.Passed = true
+		c.Detail = "no ingress"
+		c.Remediation = ""
+		return c
+	}
+	var missing []string
+	for _, ing := range m.ingresses {
+		if len(ing.Spec.TLS) == 0 {
+			missing = append(missing, ing.Name)
+		}
+	}
+	return finishCheck(c, len(missing) == 0, missing)
+}
+
+// finishCheck records the outcome; remediation is only kept for failures
+func finishCheck(c ScoreCheck, passed bool, missing []string) ScoreCheck {
+	c.Passed = passed
+	if passed {
+		c.Remediation = ""
+		return c
+	}
+	if len(missing) > 0 {
+		c.Detail = strings.Join(missing, ", ")
+	} else {
+		c.Detail = "no Deployment generated"
+	}
+	return c
+}
@@ -0,0 +1,203 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// ScoreFile is where `dorgu persona export --format score` writes the
+// workload spec
+const ScoreFile = "score.yaml"
+
+// ScoreWorkload is a Score (score.dev/v1b1) workload specification
+type ScoreWorkload struct {
+	APIVersion string                    `json:"apiVersion"`
+	Metadata   ScoreMetadata             `json:"metadata"`
+	Containers map[string]ScoreContainer `json:"containers"`
+	Service    *ScoreService             `json:"service,omitempty"`
+	Resources  map[string]ScoreResource  `json:"resources,omitempty"`
+}
+
+// ScoreMetadata names the workload
+type ScoreMetadata struct {
+	Name        string            `json:"name"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ScoreContainer is one container of a Score workload
+type ScoreContainer struct {
+	Image          string                `json:"image"`
+	Command        []string              `json:"command,omitempty"`
+	Args           []string              `json:"args,omitempty"`
+	Variables      map[string]string     `json:"variables,omitempty"`
+	Resources      *ResourceRequirements `json:"resources,omitempty"`
+	LivenessProbe  *ScoreProbe           `json:"livenessProbe,omitempty"`
+	ReadinessProbe *ScoreProbe           `json:"readinessProbe,omitempty"`
+}
+
+// ScoreProbe is an HTTP probe of a Score container
+type ScoreProbe struct {
+	HTTPGet ScoreHTTPGet `json:"httpGet"`
+}
+
+// ScoreHTTPGet is the request a Score probe makes
+type ScoreHTTPGet struct {
+	Path string `json:"path"`
+	Port int    `json:"port"`
+}
+
+// ScoreService holds the ports the workload exposes
+type ScoreService struct {
+	Ports map[string]ScorePort `json:"ports"`
+}
+
+// ScorePort is an exposed port of a Score workload
+type ScorePort struct {
+	Port       int    `json:"port"`
+	TargetPort int    `json:"targetPort,omitempty"`
+	Protocol   string `json:"protocol,omitempty"`
+}
+
+// ScoreResource is a dependency the platform provisions for the workload
+type ScoreResource struct {
+	Type string `json:"type"`
+}
+
+// scoreEnvResource is the environment resource that secret and required
+// variables without a value are read from
+const scoreEnvResource = "env"
+
+// scoreResourceTypes maps detected dependencies to Score resource types
+var scoreResourceTypes = map[string]string{
+	"postgresql":    "postgres",
+	"postgres":      "postgres",
+	"mysql":         "mysql",
+	"mongodb":       "mongodb",
+	"redis":         "redis",
+	"rabbitmq":      "amqp",
+	"kafka":         "kafka-topic",
+	"elasticsearch": "elasticsearch",
+	"s3":            "s3",
+}
+
+// GenerateScoreSpec maps the analysis to a Score workload: the app's and
+// additional containers with their images, variables, resources, and probes,
+// the service ports, and the dependencies Score has a resource type for.
+// Secret variables and required ones without a value are read from the
+// environment resource, so they never end up in the file.
+func GenerateScoreSpec(analysis *types.AppAnalysis, cfg *config.Config) (string, error) {
+	if analysis.Name == "" {
+		return "", fmt.Errorf("application name is required for Score export")
+	}
+
+	workload := ScoreWorkload{
+		APIVersion: "score.dev/v1b1",
+		Metadata:   ScoreMetadata{Name: analysis.Name},
+		Containers: map[string]ScoreContainer{},
+		Resources:  map[string]ScoreResource{},
+	}
+	if analysis.Description != "" {
+		workload.Metadata.Annotations = map[string]string{"dorgu.io/description": analysis.Description}
+	}
+
+	app := ScoreContainer{
+		Image:     appImage(analysis, cfg),
+		Variables: map[string]string{},
+	}
+	for _, e := range analysis.EnvVars {
+		switch {
+		case e.Secret, e.Value == "" && e.Required:
+			app.Variables[e.Name] = fmt.Sprintf("${resources.%s.%s}", scoreEnvResource, e.Name)
+			workload.Resources[scoreEnvResource] = ScoreResource{Type: "environment"}
+		case e.Value != "":
+			app.Variables[e.Name] = scoreEscape(e.Value)
+		}
+	}
+	resources := resourceRequirements(appResources(analysis, cfg.GetResourcesForProfile(analysis.ResourceProfile)))
+	app.Resources = &resources
+	liveness, readiness := resolveProbes(analysis)
+	app.LivenessProbe = scoreProbe(liveness)
+	app.ReadinessProbe = scoreProbe(readiness)
+	workload.Containers[analysis.Name] = app
+
+	for _, c := range appContainers(analysis) {
+		sc := ScoreContainer{Image: c.Image, Command: c.Command, Args: c.Args}
+		for k, v := range c.Env {
+			if sc.Variables == nil {
+				sc.Variables = map[string]string{}
+			}
+			sc.Variables[k] = scoreEscape(v)
+		}
+		r := resourceRequirements(overrideResources(cfg.Resources.Defaults, c.Resources))
+		sc.Resources = &r
+		if c.Health != nil {
+			sc.LivenessProbe = scoreProbe(c.Health.Liveness)
+			sc.ReadinessProbe = scoreProbe(c.Health.Readiness)
+		}
+		workload.Containers[c.Name] = sc
+	}
+
+	if ports := podPorts(analysis, cfg); len(ports) > 0 {
+		workload.Service = &ScoreService{Ports: map[string]ScorePort{}}
+		for _, p := range ports {
+			workload.Service.Ports[p.Name] = ScorePort{Port: p.Port, TargetPort: p.Port, Protocol: "TCP"}
+		}
+	}
+
+	for name, typ := range scoreDependencies(analysis) {
+		workload.Resources[name] = ScoreResource{Type: typ}
+	}
+	if len(workload.Resources) == 0 {
+		workload.Resources = nil
+	}
+
+	return toYAML(workload)
+}
+
+// scoreDependencies returns the Score resources of the app's dependencies by
+// name: the app config's first, then the detected ones not already declared
+func scoreDependencies(analysis *types.AppAnalysis) map[string]string {
+	deps := map[string]string{}
+	declared := map[string]bool{}
+	if analysis.AppConfig != nil {
+		for _, d := range analysis.AppConfig.Dependencies {
+			typ, ok := scoreResourceTypes[strings.ToLower(d.Name)]
+			if !ok {
+				typ, ok = scoreResourceTypes[strings.ToLower(d.Type)]
+			}
+			if ok && IsResourceName(d.Name) {
+				deps[d.Name] = typ
+				declared[typ] = true
+			}
+		}
+	}
+	detected := append([]string(nil), analysis.Dependencies...)
+	sort.Strings(detected)
+	for _, d := range detected {
+		typ, ok := scoreResourceTypes[strings.ToLower(d)]
+		if !ok || declared[typ] {
+			continue
+		}
+		deps[strings.ToLower(d)] = typ
+		declared[typ] = true
+	}
+	return deps
+}
+
+// scoreProbe converts an HTTP health check to a Score probe
+func scoreProbe(hc *types.HealthCheck) *ScoreProbe {
+	if hc == nil || hc.Path == "" {
+		return nil
+	}
+	return &ScoreProbe{HTTPGet: ScoreHTTPGet{Path: hc.Path, Port: hc.Port}}
+}
+
+// scoreEscape keeps "${" in a literal value from being read as a Score
+// placeholder
+func scoreEscape(s string) string {
+	return strings.ReplaceAll(s, "${", "$${")
+}
@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestGenerateScoreSpec(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:         "orders",
+		Ports:        []types.Port{{Port: 8080}},
+		HealthCheck:  &types.HealthCheck{Path: "/health", Port: 8080},
+		Dependencies: []string{"redis", "postgresql", "left-pad"},
+		EnvVars: []types.EnvVar{
+			{Name: "LOG_LEVEL", Value: "info"},
+			{Name: "TEMPLATE", Value: "${HOME}/x"},
+			{Name: "DB_PASSWORD", Secret: true},
+			{Name: "OPTIONAL"},
+		},
+		AppConfig: &types.AppConfigContext{
+			Dependencies: []types.DependencyContext{{Name: "orders-db", Type: "postgres"}},
+			Containers:   []types.ContainerContext{{Name: "proxy", Image: "envoy:v1.29", Ports: []types.ContainerPort{{Name: "admin", Port: 9901}}}},
+		},
+	}
+	out, err := GenerateScoreSpec(analysis, config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got ScoreWorkload
+	if err := yaml.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, out)
+	}
+
+	if got.APIVersion != "score.dev/v1b1" || got.Metadata.Name != "orders" {
+		t.Errorf("header = %s %s", got.APIVersion, got.Metadata.Name)
+	}
+	app := got.Containers["orders"]
+	if app.Image != "orders:latest" {
+		t.Errorf("image = %q", app.Image)
+	}
+	for name, want := range map[string]string{
+		"LOG_LEVEL":   "info",
+		"TEMPLATE":    "$${HOME}/x",
+		"DB_PASSWORD": "${resources.env.DB_PASSWORD}",
+	} {
+		if app.Variables[name] != want {
+			t.Errorf("variables[%s] = %q, want %q", name, app.Variables[name], want)
+		}
+	}
+	if _, ok := app.Variables["OPTIONAL"]; ok {
+		t.Error("optional variable without a value exported")
+	}
+	if app.Resources == nil || app.Resources.Requests["cpu"] == "" {
+		t.Errorf("resources = %+v", app.Resources)
+	}
+	if app.LivenessProbe == nil || app.LivenessProbe.HTTPGet.Path != "/health" {
+		t.Errorf("livenessProbe = %+v", app.LivenessProbe)
+	}
+	if got.Containers["proxy"].Image != "envoy:v1.29" {
+		t.Errorf("proxy container = %+v", got.Containers["proxy"])
+	}
+	if p := got.Service.Ports["port-0"]; p.Port != 8080 {
+		t.Errorf("service port-0 = %+v", p)
+	}
+	if p := got.Service.Ports["admin"]; p.Port != 9901 {
+		t.Errorf("service admin = %+v", p)
+	}
+
+	want := map[string]string{"orders-db": "postgres", "redis": "redis", "env": "environment"}
+	if len(got.Resources) != len(want) {
+		t.Errorf("resources = %v, want %v", got.Resources, want)
+	}
+	for name, typ := range want {
+		if got.Resources[name].Type != typ {
+			t.Errorf("resources[%s] = %q, want %q", name, got.Resources[name].Type, typ)
+		}
+	}
+}
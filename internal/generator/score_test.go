@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+const scoreDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: orders
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsNonRoot: true
+      containers:
+        - name: orders
+          image: registry.io/orders:1.4.2
+          resources:
+            requests: {cpu: 100m, memory: 128Mi}
+            limits: {cpu: 500m, memory: 512Mi}
+          livenessProbe:
+            httpGet: {path: /health, port: 8080}
+          readinessProbe:
+            httpGet: {path: /ready, port: 8080}
+          securityContext:
+            allowPrivilegeEscalation: false
+            readOnlyRootFilesystem: true
+            capabilities:
+              drop: [ALL]
+`
+
+const scorePDBManifest = `apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: orders
+spec:
+  minAvailable: 1
+`
+
+func TestScore(t *testing.T) {
+	ready := &types.AppAnalysis{
+		Name:  "orders",
+		Team:  "commerce",
+		Owner: "orders@acme.io",
+		AppConfig: &types.AppConfigContext{Operations: &types.OperationsContext{
+			Runbook: "https://runbooks.acme.io/orders",
+			Alerts:  []string{"HighErrorRate"},
+		}},
+	}
+	card, err := Score(ready, []GeneratedFile{
+		{Path: "deployment.yaml", Content: scoreDeployment + "---\n" + scorePDBManifest},
+		{Path: "ingress.yaml", Content: "apiVersion: networking.k8s.io/v1\nkind: Ingress\nmetadata:\n  name: orders\nspec:\n  tls:\n    - hosts: [orders.acme.io]\n"},
+		{Path: "../PERSONA.md", Content: "# orders"},
+	})
+	if err != nil {
+		t.Fatalf("Score() error: %v", err)
+	}
+	if card.Score != 100 || card.Grade != "A" {
+		for _, c := range card.Checks {
+			if !c.Passed {
+				t.Errorf("check %s failed: %s", c.ID, c.Detail)
+			}
+		}
+		t.Fatalf("Score() = %d (%s), want 100 (A)", card.Score, card.Grade)
+	}
+
+	// Same deployment, :latest image, no PDB, no ownership or operations
+	card, err = Score(&types.AppAnalysis{Name: "orders"}, []GeneratedFile{
+		{Path: "deployment.yaml", Content: strings.Replace(scoreDeployment, "registry.io/orders:1.4.2", "orders:latest", 1)},
+	})
+	if err != nil {
+		t.Fatalf("Score() error: %v", err)
+	}
+	failed := map[string]bool{}
+	for _, c := range card.Checks {
+		if !c.Passed {
+			failed[c.ID] = true
+			if c.Remediation == "" {
+				t.Errorf("check %s failed without remediation", c.ID)
+			}
+		}
+	}
+	for _, id := range []string{"pdb", "owner", "runbook", "alerts", "pinned_image"} {
+		if !failed[id] {
+			t.Errorf("expected %s to fail", id)
+		}
+	}
+	if failed["tls"] || failed["probes"] {
+		t.Errorf("unexpected failures: %v", failed)
+	}
+	if card.Score != 50 || card.Grade != "F" {
+		t.Errorf("Score() = %d (%s), want 50 (F)", card.Score, card.Grade)
+	}
+}
+
+func TestImagePinned(t *testing.T) {
+	tests := map[string]bool{
+		"orders":                        false,
+		"orders:latest":                 false,
+		"registry:5000/orders":          false,
+		"registry:5000/orders:1.2.3":    true,
+		"ghcr.io/acme/orders@sha256:ab": true,
+	}
+	for image, want := range tests {
+		if got := imagePinned(image); got != want {
+			t.Errorf("imagePinned(%q) = %v, want %v", image, got, want)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// VaultSecretsFile is where the Vault Agent injector renders secret env vars
+// as export lines
+const VaultSecretsFile = "/vault/secrets/env"
+
+// csiMountPath is where the Secrets Store CSI volume is mounted
+const csiMountPath = "/mnt/secrets-store"
+
+// SecretProviderClassManifest represents a Secrets Store CSI SecretProviderClass
+type SecretProviderClassManifest struct {
+	APIVersion string                  `json:"apiVersion"`
+	Kind       string                  `json:"kind"`
+	Metadata   Metadata                `json:"metadata"`
+	Spec       SecretProviderClassSpec `json:"spec"`
+}
+
+// SecretProviderClassSpec represents a SecretProviderClass spec
+type SecretProviderClassSpec struct {
+	Provider      string            `json:"provider"`
+	Parameters    map[string]string `json:"parameters"`
+	SecretObjects []SecretObject    `json:"secretObjects,omitempty"`
+}
+
+// SecretObject syncs mounted objects into a Kubernetes Secret
+type SecretObject struct {
+	SecretName string             `json:"secretName"`
+	Type       string             `json:"type"`
+	Data       []SecretObjectData `json:"data"`
+}
+
+// SecretObjectData maps a mounted object to a Secret key
+type SecretObjectData struct {
+	ObjectName string `json:"objectName"`
+	Key        string `json:"key"`
+}
+
+// Volume represents a pod volume
+type Volume struct {
+	Name      string                 `json:"name"`
+	CSI       *CSIVolumeSource       `json:"csi,omitempty"`
+	ConfigMap *ConfigMapVolumeSource `json:"configMap,omitempty"`
+	EmptyDir  *EmptyDirVolumeSource  `json:"emptyDir,omitempty"`
+}
+
+// CSIVolumeSource represents a CSI volume
+type CSIVolumeSource struct {
+	Driver           string            `json:"driver"`
+	ReadOnly         bool              `json:"readOnly,omitempty"`
+	VolumeAttributes map[string]string `json:"volumeAttributes,omitempty"`
+}
+
+// ConfigMapVolumeSource represents a ConfigMap volume
+type ConfigMapVolumeSource struct {
+	Name string `json:"name"`
+}
+
+// EmptyDirVolumeSource represents an emptyDir volume
+type EmptyDirVolumeSource struct {
+	SizeLimit string `json:"sizeLimit,omitempty"`
+}
+
+// VolumeMount represents a container volume mount
+type VolumeMount struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mountPath"`
+	SubPath   string `json:"subPath,omitempty"`
+	ReadOnly  bool   `json:"readOnly,omitempty"`
+}
+
+// secretsProvider returns the app's secret provider, defaulting to kubernetes
+func secretsProvider(analysis *types.AppAnalysis, cfg *config.Config) string {
+	if s := analysis.AppConfig; s != nil && s.Secrets != nil && s.Secrets.Provider != "" {
+		return s.Secrets.Provider
+	}
+	if cfg.Secrets.Provider != "" {
+		return cfg.Secrets.Provider
+	}
+	return config.SecretsKubernetes
+}
+
+// secretEnvVars returns the app's env vars that hold secrets
+func secretEnvVars(analysis *types.AppAnalysis) []types.EnvVar {
+	var secrets []types.EnvVar
+	for _, e := range analysis.EnvVars {
+		if e.Secret {
+			secrets = append(secrets, e)
+		}
+	}
+	return secrets
+}
+
+// secretKey is the key of an env var in the app's Secret and Vault path
+func secretKey(name string) string {
+	return strings.ToLower(name)
+}
+
+// vaultRoleAndPath returns the Vault role and KV v2 path for the app
+func vaultRoleAndPath(analysis *types.AppAnalysis, cfg *config.Config) (role, path string) {
+	role, path = cfg.Secrets.Vault.Role, cfg.Secrets.Vault.Path
+	if s := analysis.AppConfig; s != nil && s.Secrets != nil {
+		if s.Secrets.Role != "" {
+			role = s.Secrets.Role
+		}
+		if s.Secrets.Path != "" {
+			path = s.Secrets.Path
+		}
+	}
+	if role == "" {
+		role = "{app}"
+	}
+	if path == "" {
+		path = "secret/data/{app}"
+	}
+	expand := strings.NewReplacer("{app}", analysis.Name, "{env}", analysis.Environment, "{team}", analysis.Team)
+	return expand.Replace(role), expand.Replace(path)
+}
+
+// withVaultAgentAnnotations adds the Vault Agent injector annotations that
+// render the app's secret env vars to VaultSecretsFile
+func withVaultAgentAnnotations(annotations map[string]string, analysis *types.AppAnalysis, cfg *config.Config) map[string]string {
+	secrets := secretEnvVars(analysis)
+	if secretsProvider(analysis, cfg) != config.SecretsVaultAgent || len(secrets) == 0 {
+		return annotations
+	}
+	role, path := vaultRoleAndPath(analysis, cfg)
+	var tmpl strings.Builder
+	fmt.Fprintf(&tmpl, "{{- with secret %q }}\n", path)
+	for _, e := range secrets {
+		fmt.Fprintf(&tmpl, "export %s=\"{{ .Data.data.%s }}\"\n", e.Name, secretKey(e.Name))
+	}
+	tmpl.WriteString("{{- end }}\n")
+
+	merged := map[string]string{
+		"vault.hashicorp.com/agent-inject":              "true",
+		"vault.hashicorp.com/role":                      role,
+		"vault.hashicorp.com/agent-inject-secret-env":   path,
+		"vault.hashicorp.com/agent-inject-template-env": tmpl.String(),
+	}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	return merged
+}
+
+// hasSecretProviderClass reports whether Generate emits a SecretProviderClass
+func hasSecretProviderClass(analysis *types.AppAnalysis, cfg *config.Config) bool {
+	return secretsProvider(analysis, cfg) == config.SecretsCSI && len(secretEnvVars(analysis)) > 0
+}
+
+// secretsVolume returns the Secrets Store CSI volume and mount for the app,
+// or nils when secrets do not come from the CSI driver
+func secretsVolume(analysis *types.AppAnalysis, cfg *config.Config) (*Volume, *VolumeMount) {
+	if !hasSecretProviderClass(analysis, cfg) {
+		return nil, nil
+	}
+	volume := &Volume{
+		Name: "secrets-store",
+		CSI: &CSIVolumeSource{
+			Driver:           "secrets-store.csi.k8s.io",
+			ReadOnly:         true,
+			VolumeAttributes: map[string]string{"secretProviderClass": analysis.Name},
+		},
+	}
+	return volume, &VolumeMount{Name: volume.Name, MountPath: csiMountPath, ReadOnly: true}
+}
+
+// GenerateSecretProviderClass generates a SecretProviderClass that reads the
+// app's secret env vars from Vault and syncs them into the <app>-secrets
+// Secret the Deployment references
+func GenerateSecretProviderClass(analysis *types.AppAnalysis, namespace string, cfg *config.Config) (string, error) {
+	if cfg.Secrets.Vault.Address == "" {
+		return "", fmt.Errorf("secrets.provider csi needs secrets.vault.address in the org .dorgu.yaml")
+	}
+	role, path := vaultRoleAndPath(analysis, cfg)
+	secretName := strings.ToLower(analysis.Name) + "-secrets"
+
+	var objects strings.Builder
+	sync := SecretObject{SecretName: secretName, Type: "Opaque"}
+	for _, e := range secretEnvVars(analysis) {
+		key := secretKey(e.Name)
+		fmt.Fprintf(&objects, "- objectName: %q\n  secretPath: %q\n  secretKey: %q\n", key, path, key)
+		sync.Data = append(sync.Data, SecretObjectData{ObjectName: key, Key: key})
+	}
+
+	spc := SecretProviderClassManifest{
+		APIVersion: "secrets-store.csi.x-k8s.io/v1",
+		Kind:       "SecretProviderClass",
+		Metadata: Metadata{
+			Name:      analysis.Name,
+			Namespace: namespace,
+			Labels:    buildLabelsWithAppConfig(analysis, cfg),
+		},
+		Spec: SecretProviderClassSpec{
+			Provider: "vault",
+			Parameters: map[string]string{
+				"vaultAddress": cfg.Secrets.Vault.Address,
+				"roleName":     role,
+				"objects":      objects.String(),
+			},
+			SecretObjects: []SecretObject{sync},
+		},
+	}
+	return toYAML(spc)
+}
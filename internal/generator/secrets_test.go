@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func secretsAnalysis() *types.AppAnalysis {
+	return &types.AppAnalysis{
+		Name:  "orders",
+		Team:  "shop",
+		Ports: []types.Port{{Port: 8080}},
+		EnvVars: []types.EnvVar{
+			{Name: "PORT", Value: "8080"},
+			{Name: "DB_PASSWORD", Secret: true},
+		},
+	}
+}
+
+func generatedFile(t *testing.T, files []GeneratedFile, path string) string {
+	t.Helper()
+	for _, f := range files {
+		if f.Path == path {
+			return f.Content
+		}
+	}
+	return ""
+}
+
+func TestGenerateVaultAgentSecrets(t *testing.T) {
+	cfg := config.Default()
+	cfg.Secrets.Provider = config.SecretsVaultAgent
+	cfg.Secrets.Vault.Path = "secret/data/{team}/{app}"
+	files, err := Generate(context.Background(), secretsAnalysis(), Options{Namespace: "default", Config: cfg, SkipArgoCD: true, SkipCI: true, SkipPersona: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployment := generatedFile(t, files, "deployment.yaml")
+	for _, want := range []string{
+		`vault.hashicorp.com/agent-inject: "true"`,
+		"vault.hashicorp.com/role: orders",
+		"vault.hashicorp.com/agent-inject-secret-env: secret/data/shop/orders",
+		`export DB_PASSWORD="{{ .Data.data.db_password }}"`,
+	} {
+		if !strings.Contains(deployment, want) {
+			t.Errorf("deployment missing %q:\n%s", want, deployment)
+		}
+	}
+	if strings.Contains(deployment, "secretKeyRef") {
+		t.Error("vault-agent deployment should not reference a Kubernetes Secret")
+	}
+	if generatedFile(t, files, "secretproviderclass.yaml") != "" {
+		t.Error("unexpected SecretProviderClass")
+	}
+}
+
+func TestGenerateCSISecrets(t *testing.T) {
+	cfg := config.Default()
+	cfg.Secrets.Provider = config.SecretsCSI
+	cfg.Secrets.Vault.Address = "https://vault:8200"
+	analysis := secretsAnalysis()
+	analysis.AppConfig = &types.AppConfigContext{Secrets: &types.SecretsContext{Role: "orders-reader"}}
+	files, err := Generate(context.Background(), analysis, Options{Namespace: "default", Config: cfg, SkipArgoCD: true, SkipCI: true, SkipPersona: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spc := generatedFile(t, files, "secretproviderclass.yaml")
+	for _, want := range []string{
+		"kind: SecretProviderClass",
+		"provider: vault",
+		"roleName: orders-reader",
+		"vaultAddress: https://vault:8200",
+		`secretPath: "secret/data/orders"`,
+		"secretName: orders-secrets",
+		"objectName: db_password",
+	} {
+		if !strings.Contains(spc, want) {
+			t.Errorf("secretproviderclass.yaml missing %q:\n%s", want, spc)
+		}
+	}
+	deployment := generatedFile(t, files, "deployment.yaml")
+	for _, want := range []string{"secretKeyRef", "driver: secrets-store.csi.k8s.io", "secretProviderClass: orders", "mountPath: /mnt/secrets-store"} {
+		if !strings.Contains(deployment, want) {
+			t.Errorf("deployment missing %q:\n%s", want, deployment)
+		}
+	}
+
+	cfg.Secrets.Vault.Address = ""
+	if _, err := Generate(context.Background(), analysis, Options{Namespace: "default", Config: cfg, SkipArgoCD: true, SkipCI: true, SkipPersona: true}); err == nil {
+		t.Error("expected an error without secrets.vault.address")
+	}
+}
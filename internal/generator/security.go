@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// Seccomp profile types
+const (
+	SeccompRuntimeDefault = "RuntimeDefault"
+	SeccompLocalhost      = "Localhost"
+	SeccompUnconfined     = "Unconfined"
+)
+
+// appArmorAnnotationPrefix is followed by the container name
+const appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+// runtimeSecurity is the runtime class and profiles an app's pods run with
+type runtimeSecurity struct {
+	RuntimeClass string
+	Seccomp      SeccompProfile
+	AppArmor     string
+}
+
+// resolveSecurity returns the app's runtime security: app config overrides
+// the org config, and seccomp defaults to the runtime's profile
+func resolveSecurity(analysis *types.AppAnalysis, cfg *config.Config) runtimeSecurity {
+	s := runtimeSecurity{Seccomp: SeccompProfile{Type: SeccompRuntimeDefault}}
+	if cfg != nil {
+		s.RuntimeClass = cfg.Security.RuntimeClass
+		s.AppArmor = cfg.Security.AppArmorProfile
+		if sp := cfg.Security.PodSecurityContext.SeccompProfile; sp != nil && sp.Type != "" {
+			s.Seccomp = SeccompProfile{Type: sp.Type, LocalhostProfile: sp.LocalhostProfile}
+		}
+	}
+	if analysis.AppConfig != nil && analysis.AppConfig.Security != nil {
+		app := analysis.AppConfig.Security
+		if app.RuntimeClass != "" {
+			s.RuntimeClass = app.RuntimeClass
+		}
+		if app.AppArmorProfile != "" {
+			s.AppArmor = app.AppArmorProfile
+		}
+		if app.SeccompType != "" {
+			s.Seccomp = SeccompProfile{Type: app.SeccompType, LocalhostProfile: app.SeccompLocalhostProfile}
+		}
+	}
+	if s.Seccomp.Type != SeccompLocalhost {
+		s.Seccomp.LocalhostProfile = ""
+	}
+	return s
+}
+
+// ValidateSeccompProfile checks a seccomp profile type, and that Localhost
+// profiles name their file
+func ValidateSeccompProfile(profileType, localhostProfile string) error {
+	switch profileType {
+	case "", SeccompRuntimeDefault, SeccompUnconfined:
+		return nil
+	case SeccompLocalhost:
+		if localhostProfile == "" {
+			return fmt.Errorf("type Localhost needs a localhost_profile, e.g. profiles/audit.json")
+		}
+		if strings.HasPrefix(localhostProfile, "/") || strings.Contains(localhostProfile, "..") {
+			return fmt.Errorf("localhost_profile %q must be relative to the kubelet's seccomp directory", localhostProfile)
+		}
+		return nil
+	}
+	return fmt.Errorf("type %q is not one of RuntimeDefault, Localhost, Unconfined", profileType)
+}
+
+// ValidateAppArmorProfile checks an AppArmor profile: runtime/default,
+// unconfined, or localhost/<profile>
+func ValidateAppArmorProfile(profile string) error {
+	switch {
+	case profile == "", profile == "runtime/default", profile == "unconfined":
+		return nil
+	case strings.HasPrefix(profile, "localhost/") && len(profile) > len("localhost/"):
+		return nil
+	}
+	return fmt.Errorf("%q is not one of runtime/default, unconfined, localhost/<profile>", profile)
+}
+
+// ValidateRuntimeClass checks a RuntimeClass name
+func ValidateRuntimeClass(name string) error {
+	if name == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("invalid runtime class %q: %s", name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// checkSecurity validates the runtime class and profiles of the org and app
+// config, naming the config entry of each invalid one
+func checkSecurity(analysis *types.AppAnalysis, cfg *config.Config) error {
+	var errs []error
+	check := func(source, field string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s in the %s: %w", field, source, err))
+		}
+	}
+	org := cfg.Security
+	check("org .dorgu.yaml", "security.runtime_class", ValidateRuntimeClass(org.RuntimeClass))
+	check("org .dorgu.yaml", "security.apparmor_profile", ValidateAppArmorProfile(org.AppArmorProfile))
+	check("org .dorgu.yaml", "security.pod_security_standard", ValidatePodSecurityStandard(org.PodSecurityStandard))
+	if sp := org.PodSecurityContext.SeccompProfile; sp != nil {
+		check("org .dorgu.yaml", "security.pod_security_context.seccomp_profile", ValidateSeccompProfile(sp.Type, sp.LocalhostProfile))
+	}
+	if analysis.AppConfig != nil && analysis.AppConfig.Security != nil {
+		app := analysis.AppConfig.Security
+		check("app .dorgu.yaml", "security.runtime_class", ValidateRuntimeClass(app.RuntimeClass))
+		check("app .dorgu.yaml", "security.apparmor_profile", ValidateAppArmorProfile(app.AppArmorProfile))
+		check("app .dorgu.yaml", "security.pod_security_standard", ValidatePodSecurityStandard(app.PodSecurityStandard))
+		check("app .dorgu.yaml", "security.seccomp_profile", ValidateSeccompProfile(app.SeccompType, app.SeccompLocalhostProfile))
+	}
+	return errors.Join(errs...)
+}
+
+// withAppArmorAnnotations returns annotations plus the AppArmor profile
+// annotation of each container, when a profile is set
+func withAppArmorAnnotations(annotations map[string]string, profile string, containers []Container) map[string]string {
+	if profile == "" {
+		return annotations
+	}
+	merged := map[string]string{}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	for _, c := range containers {
+		merged[appArmorAnnotationPrefix+c.Name] = profile
+	}
+	return merged
+}
@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestRuntimeSecurity(t *testing.T) {
+	tests := []struct {
+		name   string
+		org    func(*config.Config)
+		app    *types.SecurityContext
+		want   []string
+		absent []string
+	}{
+		{
+			name:   "defaults",
+			want:   []string{"type: RuntimeDefault"},
+			absent: []string{"runtimeClassName", "apparmor"},
+		},
+		{
+			name: "org runtime class and apparmor",
+			org: func(c *config.Config) {
+				c.Security.RuntimeClass = "gvisor"
+				c.Security.AppArmorProfile = "runtime/default"
+			},
+			want: []string{"runtimeClassName: gvisor", "container.apparmor.security.beta.kubernetes.io/orders: runtime/default"},
+		},
+		{
+			name: "app overrides org",
+			org: func(c *config.Config) {
+				c.Security.RuntimeClass = "gvisor"
+				c.Security.PodSecurityContext.SeccompProfile = &config.SeccompProfile{Type: SeccompUnconfined}
+			},
+			app:    &types.SecurityContext{RuntimeClass: "kata", SeccompType: SeccompLocalhost, SeccompLocalhostProfile: "profiles/orders.json"},
+			want:   []string{"runtimeClassName: kata", "type: Localhost", "localhostProfile: profiles/orders.json"},
+			absent: []string{"gvisor", "Unconfined"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			if tt.org != nil {
+				tt.org(cfg)
+			}
+			analysis := &types.AppAnalysis{Name: "orders", AppConfig: &types.AppConfigContext{Security: tt.app}}
+			deployment, err := GenerateDeployment(analysis, "default", cfg.Resources.Defaults, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(deployment, s) {
+					t.Errorf("missing %q in:\n%s", s, deployment)
+				}
+			}
+			for _, s := range tt.absent {
+				if strings.Contains(deployment, s) {
+					t.Errorf("unexpected %q in:\n%s", s, deployment)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckSecurity(t *testing.T) {
+	tests := []struct {
+		name    string
+		app     *types.SecurityContext
+		wantErr bool
+	}{
+		{"valid", &types.SecurityContext{RuntimeClass: "gvisor", AppArmorProfile: "localhost/deny-write"}, false},
+		{"localhost without profile", &types.SecurityContext{SeccompType: SeccompLocalhost}, true},
+		{"absolute profile", &types.SecurityContext{SeccompType: SeccompLocalhost, SeccompLocalhostProfile: "/etc/seccomp.json"}, true},
+		{"unknown seccomp type", &types.SecurityContext{SeccompType: "runtime/default"}, true},
+		{"bad apparmor", &types.SecurityContext{AppArmorProfile: "deny-write"}, true},
+		{"bad runtime class", &types.SecurityContext{RuntimeClass: "gVisor"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := &types.AppAnalysis{Name: "orders", AppConfig: &types.AppConfigContext{Security: tt.app}}
+			if err := checkSecurity(analysis, config.Default()); (err != nil) != tt.wantErr {
+				t.Errorf("checkSecurity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
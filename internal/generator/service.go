@@ -2,6 +2,7 @@ package generator
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/dorgu-ai/dorgu/internal/config"
 	"github.com/dorgu-ai/dorgu/internal/types"
@@ -17,9 +18,11 @@ type ServiceManifest struct {
 
 // ServiceSpec represents a Service spec
 type ServiceSpec struct {
-	Type     string            `json:"type,omitempty"`
-	Selector map[string]string `json:"selector"`
-	Ports    []ServicePort     `json:"ports"`
+	Type           string            `json:"type,omitempty"`
+	ClusterIP      string            `json:"clusterIP,omitempty"`
+	LoadBalancerIP string            `json:"loadBalancerIP,omitempty"`
+	Selector       map[string]string `json:"selector"`
+	Ports          []ServicePort     `json:"ports"`
 }
 
 // ServicePort represents a service port
@@ -33,12 +36,16 @@ type ServicePort struct {
 // GenerateService generates a Kubernetes Service manifest
 func GenerateService(analysis *types.AppAnalysis, namespace string, cfg *config.Config) (string, error) {
 	labels := buildLabelsWithAppConfig(analysis, cfg)
-	annotations := buildAnnotationsWithAppConfig(analysis, cfg)
+	annotations := withMetricsAnnotations(buildAnnotationsWithAppConfig(analysis, cfg), analysis, cfg)
+	annotations, err := withServiceAnnotations(annotations, analysis, cfg)
+	if err != nil {
+		return "", err
+	}
 
 	var servicePorts []ServicePort
-	for i, p := range analysis.Ports {
+	for _, p := range podPorts(analysis, cfg) {
 		servicePorts = append(servicePorts, ServicePort{
-			Name:       fmt.Sprintf("port-%d", i),
+			Name:       p.Name,
 			Port:       p.Port,
 			TargetPort: p.Port,
 			Protocol:   "TCP",
@@ -55,7 +62,7 @@ func GenerateService(analysis *types.AppAnalysis, namespace string, cfg *config.
 			Annotations: annotations,
 		},
 		Spec: ServiceSpec{
-			Type: "ClusterIP",
+			Type: serviceType(analysis),
 			Selector: map[string]string{
 				"app.kubernetes.io/name": analysis.Name,
 			},
@@ -63,5 +70,108 @@ func GenerateService(analysis *types.AppAnalysis, namespace string, cfg *config.
 		},
 	}
 
+	// Headless: DNS returns the pod IPs for client-side discovery
+	if exposure(analysis) == config.ExposeHeadless {
+		service.Spec.ClusterIP = "None"
+	}
+	if svc := analysis.AppConfig; svc != nil && svc.Service != nil && svc.Service.StaticIP != "" {
+		service.Spec.LoadBalancerIP = svc.Service.StaticIP
+	}
+
 	return toYAML(service)
 }
+
+// builtinServicePresets are Service annotation presets per cloud
+var builtinServicePresets = map[string]map[string]map[string]string{
+	"aws": {
+		"internal": {
+			"service.beta.kubernetes.io/aws-load-balancer-scheme": "internal",
+		},
+		"nlb": {
+			"service.beta.kubernetes.io/aws-load-balancer-type":            "external",
+			"service.beta.kubernetes.io/aws-load-balancer-nlb-target-type": "ip",
+			"service.beta.kubernetes.io/aws-load-balancer-scheme":          "internet-facing",
+		},
+	},
+	"gcp": {
+		"internal": {
+			"networking.gke.io/load-balancer-type": "Internal",
+		},
+	},
+	"azure": {
+		"internal": {
+			"service.beta.kubernetes.io/azure-load-balancer-internal": "true",
+		},
+	},
+}
+
+// serviceType returns the app's Service type, defaulting to ClusterIP
+func serviceType(analysis *types.AppAnalysis) string {
+	if analysis.AppConfig != nil && analysis.AppConfig.Service != nil && analysis.AppConfig.Service.Type != "" {
+		return analysis.AppConfig.Service.Type
+	}
+	return "ClusterIP"
+}
+
+// serviceCloud returns the cloud whose built-in presets apply
+func serviceCloud(cfg *config.Config) string {
+	if cfg.Service.Cloud != "" {
+		return strings.ToLower(cfg.Service.Cloud)
+	}
+	return strings.ToLower(cfg.Cost.Cloud)
+}
+
+// ServicePreset returns the annotations of a named Service preset: the
+// cloud's built-in preset overlaid with the org's service.presets entry. An
+// empty org value drops the built-in annotation.
+func ServicePreset(name string, cfg *config.Config) (map[string]string, bool) {
+	builtin, hasBuiltin := builtinServicePresets[serviceCloud(cfg)][name]
+	org, hasOrg := cfg.Service.Presets[name]
+	if !hasBuiltin && !hasOrg {
+		return nil, false
+	}
+	preset := make(map[string]string)
+	for k, v := range builtin {
+		preset[k] = v
+	}
+	for k, v := range org {
+		if v == "" {
+			delete(preset, k)
+			continue
+		}
+		preset[k] = v
+	}
+	return preset, true
+}
+
+// withServiceAnnotations adds the app's Service presets, the static IP
+// annotation Azure needs, and the app's Service annotations
+func withServiceAnnotations(annotations map[string]string, analysis *types.AppAnalysis, cfg *config.Config) (map[string]string, error) {
+	if analysis.AppConfig == nil || analysis.AppConfig.Service == nil {
+		return annotations, nil
+	}
+	svc := analysis.AppConfig.Service
+	merged := make(map[string]string)
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	for _, name := range svc.Presets {
+		preset, ok := ServicePreset(name, cfg)
+		if !ok {
+			return nil, fmt.Errorf("unknown service preset %q; define it under service.presets in the org .dorgu.yaml", name)
+		}
+		for k, v := range preset {
+			merged[k] = v
+		}
+	}
+	if svc.StaticIP != "" && serviceCloud(cfg) == "azure" {
+		merged["service.beta.kubernetes.io/azure-load-balancer-ipv4"] = svc.StaticIP
+	}
+	for k, v := range svc.Annotations {
+		merged[k] = v
+	}
+	if len(merged) == 0 {
+		return nil, nil
+	}
+	return merged, nil
+}
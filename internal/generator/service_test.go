@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestGenerateServiceType(t *testing.T) {
+	tests := []struct {
+		name      string
+		cloud     string
+		presets   map[string]map[string]string
+		svc       *types.ServiceContext
+		wantType  string
+		wantIP    string
+		wantAnn   map[string]string
+		wantError bool
+	}{
+		{
+			name:     "default ClusterIP",
+			wantType: "ClusterIP",
+		},
+		{
+			name:     "aws nlb preset",
+			cloud:    "aws",
+			svc:      &types.ServiceContext{Type: "LoadBalancer", Presets: []string{"nlb"}},
+			wantType: "LoadBalancer",
+			wantAnn: map[string]string{
+				"service.beta.kubernetes.io/aws-load-balancer-type":            "external",
+				"service.beta.kubernetes.io/aws-load-balancer-nlb-target-type": "ip",
+				"service.beta.kubernetes.io/aws-load-balancer-scheme":          "internet-facing",
+			},
+		},
+		{
+			name:     "org preset overrides built-in and app annotations win",
+			cloud:    "gcp",
+			presets:  map[string]map[string]string{"internal": {"networking.gke.io/internal-load-balancer-allow-global-access": "true"}},
+			svc:      &types.ServiceContext{Type: "LoadBalancer", Presets: []string{"internal"}, Annotations: map[string]string{"networking.gke.io/load-balancer-type": "External"}},
+			wantType: "LoadBalancer",
+			wantAnn: map[string]string{
+				"networking.gke.io/load-balancer-type":                         "External",
+				"networking.gke.io/internal-load-balancer-allow-global-access": "true",
+			},
+		},
+		{
+			name:     "azure static ip",
+			cloud:    "azure",
+			svc:      &types.ServiceContext{Type: "LoadBalancer", StaticIP: "20.1.2.3"},
+			wantType: "LoadBalancer",
+			wantIP:   "20.1.2.3",
+			wantAnn:  map[string]string{"service.beta.kubernetes.io/azure-load-balancer-ipv4": "20.1.2.3"},
+		},
+		{
+			name:     "node port",
+			svc:      &types.ServiceContext{Type: "NodePort"},
+			wantType: "NodePort",
+		},
+		{
+			name:      "unknown preset",
+			cloud:     "gcp",
+			svc:       &types.ServiceContext{Type: "LoadBalancer", Presets: []string{"nlb"}},
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Service.Cloud = tt.cloud
+			cfg.Service.Presets = tt.presets
+			analysis := &types.AppAnalysis{
+				Name:      "api",
+				Ports:     []types.Port{{Port: 8080}},
+				AppConfig: &types.AppConfigContext{Service: tt.svc},
+			}
+			out, err := GenerateService(analysis, "default", cfg)
+			if tt.wantError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var svc ServiceManifest
+			if err := yaml.Unmarshal([]byte(out), &svc); err != nil {
+				t.Fatal(err)
+			}
+			if svc.Spec.Type != tt.wantType || svc.Spec.LoadBalancerIP != tt.wantIP {
+				t.Errorf("type, ip = %s, %q; want %s, %q", svc.Spec.Type, svc.Spec.LoadBalancerIP, tt.wantType, tt.wantIP)
+			}
+			if !reflect.DeepEqual(svc.Metadata.Annotations, tt.wantAnn) {
+				t.Errorf("annotations = %v, want %v", svc.Metadata.Annotations, tt.wantAnn)
+			}
+		})
+	}
+}
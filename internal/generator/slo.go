@@ -0,0 +1,455 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// SLO defaults
+const (
+	DefaultSLOWindow      = "30d"
+	DefaultRequestsMetric = "http_requests_total"
+	DefaultDurationMetric = "http_request_duration_seconds"
+)
+
+// Paths of the generated SLO manifests. OpenSLO documents are not
+// Kubernetes objects, so they live apart from the manifests.
+const (
+	SlothFile   = "slo.yaml"
+	OpenSLOFile = "openslo/slo.yaml"
+)
+
+// sloWindowPattern matches the windows both Sloth and OpenSLO understand
+var sloWindowPattern = regexp.MustCompile(`^([1-9][0-9]*)([hdw])$`)
+
+// sloWindowUnits are the lengths of the window units
+var sloWindowUnits = map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+
+// ParseSLOWindow parses an SLO window such as 30d, 4w, or 24h
+func ParseSLOWindow(window string) (time.Duration, error) {
+	m := sloWindowPattern.FindStringSubmatch(window)
+	if m == nil {
+		return 0, fmt.Errorf("%q is not a window such as 30d, 4w, or 24h", window)
+	}
+	n, _ := strconv.Atoi(m[1])
+	return time.Duration(n) * sloWindowUnits[m[2]], nil
+}
+
+// ValidateSLOTarget checks an objective given as a percentage
+func ValidateSLOTarget(percent float64) error {
+	if percent <= 0 || percent >= 100 {
+		return fmt.Errorf("%g must be a percentage between 0 and 100, e.g. 99.9", percent)
+	}
+	return nil
+}
+
+// ValidateLatencyThreshold checks a latency objective's threshold
+func ValidateLatencyThreshold(threshold string) error {
+	d, err := time.ParseDuration(threshold)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("%q is not a positive duration such as 300ms", threshold)
+	}
+	return nil
+}
+
+// ValidateSLOFormat checks the tooling SLO manifests are generated for
+func ValidateSLOFormat(format string) error {
+	switch format {
+	case "", config.SLOFormatSloth, config.SLOFormatOpenSLO:
+		return nil
+	}
+	return fmt.Errorf("%q is not one of sloth, openslo", format)
+}
+
+// appSLO returns the app's SLOs, or nil when it declares none
+func appSLO(analysis *types.AppAnalysis) *types.SLOContext {
+	if analysis.AppConfig == nil {
+		return nil
+	}
+	return analysis.AppConfig.SLO
+}
+
+// sloWindow returns the app's SLO window
+func sloWindow(slo *types.SLOContext) string {
+	if slo.Window != "" {
+		return slo.Window
+	}
+	return DefaultSLOWindow
+}
+
+// sloFormat returns the tooling the app's SLOs are generated for; app config
+// overrides the org's
+func sloFormat(slo *types.SLOContext, cfg *config.Config) string {
+	if slo.Format != "" {
+		return slo.Format
+	}
+	return cfg.SLO.Format
+}
+
+// checkSLO validates the app's SLOs
+func checkSLO(analysis *types.AppAnalysis, cfg *config.Config) error {
+	slo := appSLO(analysis)
+	if slo == nil {
+		return nil
+	}
+	var errs []error
+	if slo.Availability == 0 && slo.LatencyThreshold == "" {
+		errs = append(errs, fmt.Errorf("slo: needs an availability or a latency objective"))
+	}
+	if slo.Availability != 0 {
+		if err := ValidateSLOTarget(slo.Availability); err != nil {
+			errs = append(errs, fmt.Errorf("slo.availability: %w", err))
+		}
+	}
+	if slo.LatencyThreshold != "" || slo.LatencyTarget != 0 {
+		if err := ValidateLatencyThreshold(slo.LatencyThreshold); err != nil {
+			errs = append(errs, fmt.Errorf("slo.latency.threshold: %w", err))
+		}
+		if err := ValidateSLOTarget(slo.LatencyTarget); err != nil {
+			errs = append(errs, fmt.Errorf("slo.latency.target: %w", err))
+		}
+	}
+	if _, err := ParseSLOWindow(sloWindow(slo)); err != nil {
+		errs = append(errs, fmt.Errorf("slo.window: %w", err))
+	}
+	if err := ValidateSLOFormat(slo.Format); err != nil {
+		errs = append(errs, fmt.Errorf("slo.format: %w", err))
+	}
+	if err := ValidateSLOFormat(cfg.SLO.Format); err != nil {
+		errs = append(errs, fmt.Errorf("slo.format in the org .dorgu.yaml: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// sloQuery is the PromQL of an SLI: error (or good) events and all events
+type sloQuery struct {
+	Name  string
+	Kind  string // availability, latency
+	Bad   string
+	Good  string
+	Total string
+}
+
+// sloQueries returns the PromQL of each of the app's SLIs. With rate, the
+// queries are Sloth's, with a {{.window}} placeholder; without, they are
+// the raw counters OpenSLO takes.
+func sloQueries(analysis *types.AppAnalysis, slo *types.SLOContext, rate bool) []sloQuery {
+	requests, duration, selector := slo.RequestsMetric, slo.DurationMetric, slo.Selector
+	if requests == "" {
+		requests = DefaultRequestsMetric
+	}
+	if duration == "" {
+		duration = DefaultDurationMetric
+	}
+	if selector == "" {
+		selector = fmt.Sprintf("job=%q", analysis.Name)
+	}
+	q := func(metric, extra string) string {
+		matchers := selector
+		if extra != "" {
+			matchers += "," + extra
+		}
+		series := fmt.Sprintf("%s{%s}", metric, matchers)
+		if rate {
+			return fmt.Sprintf("sum(rate(%s[{{.window}}]))", series)
+		}
+		return series
+	}
+
+	var queries []sloQuery
+	if slo.Availability != 0 {
+		queries = append(queries, sloQuery{
+			Name:  "availability",
+			Kind:  "availability",
+			Bad:   q(requests, `code=~"5.."`),
+			Total: q(requests, ""),
+		})
+	}
+	if slo.LatencyThreshold != "" {
+		d, _ := time.ParseDuration(slo.LatencyThreshold)
+		le := fmt.Sprintf("le=%q", strconv.FormatFloat(d.Seconds(), 'f', -1, 64))
+		query := sloQuery{
+			Name:  "latency",
+			Kind:  "latency",
+			Good:  q(duration+"_bucket", le),
+			Total: q(duration+"_count", ""),
+		}
+		query.Bad = query.Total + " - " + query.Good
+		queries = append(queries, query)
+	}
+	return queries
+}
+
+// sloTarget returns the objective, in percent, of an SLI
+func sloTarget(slo *types.SLOContext, q sloQuery) float64 {
+	if q.Kind == "latency" {
+		return slo.LatencyTarget
+	}
+	return slo.Availability
+}
+
+// sloDescription describes an SLI's objective in words
+func sloDescription(slo *types.SLOContext, q sloQuery) string {
+	if q.Kind == "latency" {
+		return fmt.Sprintf("%g%% of requests finish within %s", slo.LatencyTarget, slo.LatencyThreshold)
+	}
+	return fmt.Sprintf("%g%% of requests succeed", slo.Availability)
+}
+
+// SlothServiceLevel represents a Sloth PrometheusServiceLevel
+type SlothServiceLevel struct {
+	APIVersion string                `json:"apiVersion"`
+	Kind       string                `json:"kind"`
+	Metadata   Metadata              `json:"metadata"`
+	Spec       SlothServiceLevelSpec `json:"spec"`
+}
+
+// SlothServiceLevelSpec represents a PrometheusServiceLevel spec
+type SlothServiceLevelSpec struct {
+	Service string     `json:"service"`
+	SLOs    []SlothSLO `json:"slos"`
+}
+
+// SlothSLO is one objective of a PrometheusServiceLevel
+type SlothSLO struct {
+	Name        string        `json:"name"`
+	Objective   float64       `json:"objective"`
+	Description string        `json:"description,omitempty"`
+	SLI         SlothSLI      `json:"sli"`
+	Alerting    SlothAlerting `json:"alerting"`
+}
+
+// SlothSLI is an SLI given as error and total event queries
+type SlothSLI struct {
+	Events SlothEvents `json:"events"`
+}
+
+// SlothEvents are the error and total event queries of an SLI
+type SlothEvents struct {
+	ErrorQuery string `json:"errorQuery"`
+	TotalQuery string `json:"totalQuery"`
+}
+
+// SlothAlerting names the burn rate alerts of an objective
+type SlothAlerting struct {
+	Name        string     `json:"name"`
+	PageAlert   SlothAlert `json:"pageAlert"`
+	TicketAlert SlothAlert `json:"ticketAlert"`
+}
+
+// SlothAlert labels a burn rate alert
+type SlothAlert struct {
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// OpenSLO represents an OpenSLO v1 SLO
+type OpenSLO struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Metadata   OpenSLOMetadata `json:"metadata"`
+	Spec       OpenSLOSpec     `json:"spec"`
+}
+
+// OpenSLOMetadata names an OpenSLO document
+type OpenSLOMetadata struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// OpenSLOSpec represents an SLO spec
+type OpenSLOSpec struct {
+	Description     string              `json:"description,omitempty"`
+	Service         string              `json:"service"`
+	Indicator       OpenSLOIndicator    `json:"indicator"`
+	TimeWindow      []OpenSLOTimeWindow `json:"timeWindow"`
+	BudgetingMethod string              `json:"budgetingMethod"`
+	Objectives      []OpenSLOObjective  `json:"objectives"`
+}
+
+// OpenSLOIndicator is an inline SLI
+type OpenSLOIndicator struct {
+	Metadata OpenSLOMetadata      `json:"metadata"`
+	Spec     OpenSLOIndicatorSpec `json:"spec"`
+}
+
+// OpenSLOIndicatorSpec is an SLI given as a ratio
+type OpenSLOIndicatorSpec struct {
+	RatioMetric OpenSLORatioMetric `json:"ratioMetric"`
+}
+
+// OpenSLORatioMetric is good or bad events over total events
+type OpenSLORatioMetric struct {
+	Counter bool           `json:"counter"`
+	Good    *OpenSLOMetric `json:"good,omitempty"`
+	Bad     *OpenSLOMetric `json:"bad,omitempty"`
+	Total   OpenSLOMetric  `json:"total"`
+}
+
+// OpenSLOMetric is a query against a metric source
+type OpenSLOMetric struct {
+	MetricSource OpenSLOMetricSource `json:"metricSource"`
+}
+
+// OpenSLOMetricSource is a Prometheus query
+type OpenSLOMetricSource struct {
+	Type string            `json:"type"`
+	Spec map[string]string `json:"spec"`
+}
+
+// OpenSLOTimeWindow is the window an SLO is measured over
+type OpenSLOTimeWindow struct {
+	Duration  string `json:"duration"`
+	IsRolling bool   `json:"isRolling"`
+}
+
+// OpenSLOObjective is the target ratio of an SLO
+type OpenSLOObjective struct {
+	DisplayName string  `json:"displayName,omitempty"`
+	Target      float64 `json:"target"`
+}
+
+// GenerateSLO generates the app's SLOs for the tooling in slo.format: a
+// Sloth PrometheusServiceLevel or OpenSLO documents. It returns nothing when
+// the app has no SLOs or no format is set.
+func GenerateSLO(analysis *types.AppAnalysis, namespace string, cfg *config.Config) ([]GeneratedFile, error) {
+	slo := appSLO(analysis)
+	if slo == nil {
+		return nil, nil
+	}
+	switch sloFormat(slo, cfg) {
+	case config.SLOFormatSloth:
+		content, err := generateSloth(analysis, slo, namespace, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return []GeneratedFile{{Path: SlothFile, Content: content}}, nil
+	case config.SLOFormatOpenSLO:
+		content, err := generateOpenSLO(analysis, slo)
+		if err != nil {
+			return nil, err
+		}
+		return []GeneratedFile{{Path: OpenSLOFile, Content: content}}, nil
+	}
+	return nil, nil
+}
+
+func generateSloth(analysis *types.AppAnalysis, slo *types.SLOContext, namespace string, cfg *config.Config) (string, error) {
+	var slos []SlothSLO
+	for _, q := range sloQueries(analysis, slo, true) {
+		slos = append(slos, SlothSLO{
+			Name:        "requests-" + q.Name,
+			Objective:   sloTarget(slo, q),
+			Description: sloDescription(slo, q),
+			SLI:         SlothSLI{Events: SlothEvents{ErrorQuery: q.Bad, TotalQuery: q.Total}},
+			Alerting: SlothAlerting{
+				Name:        pascalCase(analysis.Name) + pascalCase(q.Name),
+				PageAlert:   SlothAlert{Labels: map[string]string{"severity": "critical"}},
+				TicketAlert: SlothAlert{Labels: map[string]string{"severity": "warning"}},
+			},
+		})
+	}
+	manifest := SlothServiceLevel{
+		APIVersion: "sloth.slok.dev/v1",
+		Kind:       "PrometheusServiceLevel",
+		Metadata: Metadata{
+			Name:      analysis.Name,
+			Namespace: namespace,
+			Labels:    buildLabelsWithAppConfig(analysis, cfg),
+		},
+		Spec: SlothServiceLevelSpec{Service: analysis.Name, SLOs: slos},
+	}
+	return toYAML(manifest)
+}
+
+func generateOpenSLO(analysis *types.AppAnalysis, slo *types.SLOContext) (string, error) {
+	var docs []string
+	for _, q := range sloQueries(analysis, slo, false) {
+		metric := func(query string) *OpenSLOMetric {
+			return &OpenSLOMetric{MetricSource: OpenSLOMetricSource{Type: "Prometheus", Spec: map[string]string{"query": query}}}
+		}
+		ratio := OpenSLORatioMetric{Counter: true, Total: *metric(q.Total)}
+		if q.Good != "" {
+			ratio.Good = metric(q.Good)
+		} else {
+			ratio.Bad = metric(q.Bad)
+		}
+		name := analysis.Name + "-" + q.Name
+		doc, err := toYAML(OpenSLO{
+			APIVersion: "openslo/v1",
+			Kind:       "SLO",
+			Metadata:   OpenSLOMetadata{Name: name},
+			Spec: OpenSLOSpec{
+				Description:     sloDescription(slo, q),
+				Service:         analysis.Name,
+				Indicator:       OpenSLOIndicator{Metadata: OpenSLOMetadata{Name: name}, Spec: OpenSLOIndicatorSpec{RatioMetric: ratio}},
+				TimeWindow:      []OpenSLOTimeWindow{{Duration: sloWindow(slo), IsRolling: true}},
+				BudgetingMethod: "Occurrences",
+				Objectives:      []OpenSLOObjective{{DisplayName: sloDescription(slo, q), Target: sloTarget(slo, q) / 100}},
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+		docs = append(docs, doc)
+	}
+	return strings.Join(docs, "---\n"), nil
+}
+
+// pascalCase turns a resource name into an alert name part, e.g.
+// order-api into OrderApi
+func pascalCase(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '.' || r == '_' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// errorBudget returns how long the service may fail over the window without
+// breaking an objective, e.g. 43m for 99.9% over 30d
+func errorBudget(percent float64, window string) string {
+	w, err := ParseSLOWindow(window)
+	if err != nil {
+		return ""
+	}
+	budget := time.Duration(float64(w) * (100 - percent) / 100)
+	if budget < time.Minute {
+		return budget.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(budget.Round(time.Minute).String(), "0s")
+}
+
+// EmbedSLO adds a Service Level Objectives section before the Health &
+// Monitoring section of a PERSONA.md document, or at its end. Documents that
+// already have the section are returned as is.
+func EmbedSLO(markdown string, slo *types.SLOContext) string {
+	const heading = "## Service Level Objectives"
+	if slo == nil || strings.Contains(markdown, heading+"\n") {
+		return markdown
+	}
+	window := sloWindow(slo)
+	var b strings.Builder
+	b.WriteString(heading + "\n\n")
+	if slo.Availability != 0 {
+		fmt.Fprintf(&b, "- **Availability:** %g%% of requests succeed over %s (error budget: about %s of failures)\n", slo.Availability, window, errorBudget(slo.Availability, window))
+	}
+	if slo.LatencyThreshold != "" {
+		fmt.Fprintf(&b, "- **Latency:** %g%% of requests finish within %s over %s\n", slo.LatencyTarget, slo.LatencyThreshold, window)
+	}
+	if slo.ErrorBudgetPolicy != "" {
+		fmt.Fprintf(&b, "- **Error budget policy:** %s\n", slo.ErrorBudgetPolicy)
+	}
+	section := b.String()
+
+	if i := strings.Index(markdown, "## Health & Monitoring\n"); i >= 0 {
+		return markdown[:i] + section + "\n" + markdown[i:]
+	}
+	return strings.TrimRight(markdown, "\n") + "\n\n" + section
+}
@@ -0,0 +1,166 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func sloTestAnalysis(slo *types.SLOContext) *types.AppAnalysis {
+	return &types.AppAnalysis{
+		Name:      "orders",
+		Ports:     []types.Port{{Port: 8080}},
+		AppConfig: &types.AppConfigContext{SLO: slo},
+	}
+}
+
+func TestCheckSLO(t *testing.T) {
+	tests := []struct {
+		name    string
+		slo     *types.SLOContext
+		wantErr string
+	}{
+		{"none", nil, ""},
+		{"availability and latency", &types.SLOContext{Availability: 99.9, LatencyThreshold: "300ms", LatencyTarget: 99, Window: "4w"}, ""},
+		{"no objective", &types.SLOContext{Window: "30d"}, "needs an availability or a latency objective"},
+		{"availability fraction of 100", &types.SLOContext{Availability: 100}, "slo.availability"},
+		{"latency without threshold", &types.SLOContext{LatencyTarget: 99}, "slo.latency.threshold"},
+		{"window", &types.SLOContext{Availability: 99, Window: "1 month"}, "slo.window"},
+		{"format", &types.SLOContext{Availability: 99, Format: "prometheus"}, "slo.format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSLO(sloTestAnalysis(tt.slo), config.Default())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkSLO() = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkSLO() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateSLO(t *testing.T) {
+	slo := &types.SLOContext{Availability: 99.9, LatencyThreshold: "300ms", LatencyTarget: 99}
+
+	tests := []struct {
+		name    string
+		format  string
+		path    string
+		want    []string
+		notWant []string
+	}{
+		{"no format", "", "", nil, nil},
+		{
+			name:   "sloth",
+			format: config.SLOFormatSloth,
+			path:   SlothFile,
+			want: []string{
+				"kind: PrometheusServiceLevel",
+				"objective: 99.9",
+				`errorQuery: sum(rate(http_requests_total{job="orders",code=~"5.."}[{{.window}}]))`,
+				`totalQuery: sum(rate(http_requests_total{job="orders"}[{{.window}}]))`,
+				`errorQuery: sum(rate(http_request_duration_seconds_count{job="orders"}[{{.window}}]))`,
+				`- sum(rate(http_request_duration_seconds_bucket{job="orders",le="0.3"}[{{.window}}]))`,
+				"name: OrdersAvailability",
+			},
+		},
+		{
+			name:   "openslo",
+			format: config.SLOFormatOpenSLO,
+			path:   OpenSLOFile,
+			want: []string{
+				"apiVersion: openslo/v1",
+				"name: orders-availability",
+				`query: http_requests_total{job="orders",code=~"5.."}`,
+				`query: http_request_duration_seconds_bucket{job="orders",le="0.3"}`,
+				"duration: 30d",
+				"target: 0.999",
+			},
+			notWant: []string{"{{.window}}"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.SLO.Format = tt.format
+			files, err := GenerateSLO(sloTestAnalysis(slo), "shop", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.path == "" {
+				if len(files) != 0 {
+					t.Errorf("GenerateSLO() = %v, want no files", files)
+				}
+				return
+			}
+			if len(files) != 1 || files[0].Path != tt.path {
+				t.Fatalf("GenerateSLO() = %v, want %s", files, tt.path)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(files[0].Content, w) {
+					t.Errorf("%s missing %q:\n%s", tt.path, w, files[0].Content)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(files[0].Content, w) {
+					t.Errorf("%s has %q:\n%s", tt.path, w, files[0].Content)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateSLOManifests(t *testing.T) {
+	cfg := config.Default()
+	cfg.SLO.Format = config.SLOFormatOpenSLO
+	files, err := Generate(context.Background(), sloTestAnalysis(&types.SLOContext{Availability: 99.5, Format: config.SLOFormatSloth}), Options{Namespace: "shop", Config: cfg, NoLLMPersona: true, SkipCI: true, SkipArgoCD: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kustomization string
+	for _, f := range files {
+		if f.Path == KustomizationFile {
+			kustomization = f.Content
+		}
+		if f.Path == OpenSLOFile {
+			t.Errorf("app format sloth did not override the org's openslo")
+		}
+	}
+	if !strings.Contains(kustomization, "- "+SlothFile) {
+		t.Errorf("kustomization does not list %s:\n%s", SlothFile, kustomization)
+	}
+	if IsManifest(GeneratedFile{Path: OpenSLOFile, Content: "kind: SLO\n"}) {
+		t.Errorf("OpenSLO documents treated as a Kubernetes manifest")
+	}
+}
+
+func TestEmbedSLO(t *testing.T) {
+	slo := &types.SLOContext{Availability: 99.9, LatencyThreshold: "300ms", LatencyTarget: 99, ErrorBudgetPolicy: "Freeze releases"}
+	doc := "# orders\n\n## Resource Profile\n\n- api\n\n## Health & Monitoring\n\n- /health\n"
+
+	got := EmbedSLO(doc, slo)
+	want := "# orders\n\n## Resource Profile\n\n- api\n\n## Service Level Objectives\n\n" +
+		"- **Availability:** 99.9% of requests succeed over 30d (error budget: about 43m of failures)\n" +
+		"- **Latency:** 99% of requests finish within 300ms over 30d\n" +
+		"- **Error budget policy:** Freeze releases\n\n## Health & Monitoring\n\n- /health\n"
+	if got != want {
+		t.Errorf("EmbedSLO() =\n%s\nwant\n%s", got, want)
+	}
+	if again := EmbedSLO(got, slo); again != got {
+		t.Errorf("SLOs embedded twice:\n%s", again)
+	}
+	if !strings.HasSuffix(EmbedSLO("# orders\n", slo), "\n\n## Service Level Objectives\n\n- **Availability:** 99.9% of requests succeed over 30d (error budget: about 43m of failures)\n- **Latency:** 99% of requests finish within 300ms over 30d\n- **Error budget policy:** Freeze releases\n") {
+		t.Errorf("section not appended to a document without Health & Monitoring")
+	}
+	if EmbedSLO(doc, nil) != doc {
+		t.Error("document changed without SLOs")
+	}
+}
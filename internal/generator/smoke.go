@@ -0,0 +1,184 @@
+package generator
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// SmokeTestFile is the post-deploy smoke test Job, relative to the output
+// directory
+const SmokeTestFile = "jobs/smoke-test.yaml"
+
+// defaultSmokeTestImage runs the smoke test unless smoke_test.image is set
+const defaultSmokeTestImage = "curlimages/curl:8.10.1"
+
+// maxSmokeTestCodeRoutes caps the routes from the code the smoke test
+// requests, so an app with a large API is not crawled on every deploy
+const maxSmokeTestCodeRoutes = 5
+
+// smokeTestScript requests each URL in its arguments, retrying while the
+// Service's endpoints come up, and fails when any request does
+const smokeTestScript = `failed=0
+for url in "$@"; do
+  if curl -fsS -o /dev/null --retry 5 --retry-all-errors --retry-delay 3 --max-time 10 "$url"; then
+    echo "ok   $url"
+  else
+    echo "FAIL $url"
+    failed=1
+  fi
+done
+exit $failed`
+
+// SmokeCheck is an HTTP GET that must succeed once the app is deployed
+type SmokeCheck struct {
+	Path string
+	// Port is the Service port the request goes to
+	Port int
+}
+
+// SmokeChecks returns the requests that verify a deployed app: its readiness
+// path on the probe's port, then the first routes found in the code and
+// smoke_test.routes on the app's first port. Only ports the Service exposes
+// are checked.
+func SmokeChecks(analysis *types.AppAnalysis, cfg *config.Config) []SmokeCheck {
+	exposed := map[int]bool{}
+	routePort := 0
+	for _, p := range appPorts(analysis, cfg) {
+		exposed[p.Port] = true
+		if routePort == 0 && p.Name != metricsPortName {
+			routePort = p.Port
+		}
+	}
+
+	var checks []SmokeCheck
+	seen := map[SmokeCheck]bool{}
+	add := func(c SmokeCheck) {
+		if c.Path == "" || !exposed[c.Port] || seen[c] {
+			return
+		}
+		seen[c] = true
+		checks = append(checks, c)
+	}
+	if _, readiness := resolveProbes(analysis); readiness != nil {
+		add(SmokeCheck{Path: readiness.Path, Port: readiness.Port})
+	}
+	var routes []string
+	if analysis.Code != nil {
+		routes = analysis.Code.Routes
+		if len(routes) > maxSmokeTestCodeRoutes {
+			routes = routes[:maxSmokeTestCodeRoutes]
+		}
+	}
+	if cfg != nil {
+		routes = append(append([]string(nil), routes...), cfg.SmokeTest.Routes...)
+	}
+	for _, r := range routes {
+		add(SmokeCheck{Path: r, Port: routePort})
+	}
+	return checks
+}
+
+// ValidateSmokeTestRoutes checks smoke_test.routes
+func ValidateSmokeTestRoutes(routes []string) error {
+	for _, r := range routes {
+		if !strings.HasPrefix(r, "/") {
+			return fmt.Errorf("%q must start with /", r)
+		}
+	}
+	return nil
+}
+
+// GenerateSmokeTest generates SmokeTestFile, a Job that runs after every
+// deploy (as an ArgoCD PostSync or Helm post-install hook) and requests
+// SmokeChecks through the app's Service, so a rollout that passes its probes
+// but does not serve its routes fails the sync. It is generated only when
+// smoke_test.enabled is set and the app serves HTTP.
+func GenerateSmokeTest(analysis *types.AppAnalysis, namespace string, cfg *config.Config) ([]GeneratedFile, error) {
+	if !cfg.SmokeTest.Enabled {
+		return nil, nil
+	}
+	if err := ValidateSmokeTestRoutes(cfg.SmokeTest.Routes); err != nil {
+		return nil, fmt.Errorf("smoke_test.routes: %w", err)
+	}
+	checks := SmokeChecks(analysis, cfg)
+	if len(checks) == 0 {
+		slog.Warn("smoke_test: the app exposes no HTTP path to check", "app", analysis.Name)
+		return nil, nil
+	}
+	if analysis.AppConfig != nil {
+		for _, job := range analysis.AppConfig.Jobs {
+			if "jobs/"+job.Name+".yaml" == SmokeTestFile {
+				slog.Warn("smoke_test: the app config already has a smoke-test job", "app", analysis.Name)
+				return nil, nil
+			}
+		}
+	}
+
+	var urls []string
+	for _, c := range checks {
+		urls = append(urls, fmt.Sprintf("http://%s:%d%s", analysis.Name, c.Port, c.Path))
+	}
+
+	name := ResourceName(analysis.Name + "-smoke-test")
+	labels := buildLabelsWithAppConfig(analysis, cfg)
+	labels["app.kubernetes.io/name"] = name
+	labels["app.kubernetes.io/part-of"] = analysis.Name
+	labels["app.kubernetes.io/component"] = "smoke-test"
+
+	annotations := map[string]string{}
+	for k, v := range buildAnnotationsWithAppConfig(analysis, cfg) {
+		annotations[k] = v
+	}
+	for k, v := range jobHookAnnotations[config.HookPostDeploy] {
+		annotations[k] = v
+	}
+
+	image := cfg.SmokeTest.Image
+	if image == "" {
+		image = defaultSmokeTestImage
+	}
+	podSecurityContext, containerSecurityContext := securityContexts(analysis, cfg)
+	// curl retries each request, so a failed pod is not retried
+	backoffLimit := 0
+	manifest := JobManifest{
+		APIVersion: "batch/v1",
+		Kind:       "Job",
+		Metadata: Metadata{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: nonEmpty(annotations),
+		},
+		Spec: JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: 300,
+			Template: PodTemplateSpec{
+				Metadata: Metadata{Labels: labels},
+				Spec: PodSpec{
+					RestartPolicy:   "Never",
+					SecurityContext: podSecurityContext,
+					Containers: []Container{{
+						Name:    "smoke-test",
+						Image:   image,
+						Command: []string{"sh", "-c", smokeTestScript, "smoke-test"},
+						Args:    urls,
+						Resources: ResourceRequirements{
+							Requests: map[string]string{"cpu": "10m", "memory": "16Mi"},
+							Limits:   map[string]string{"cpu": "100m", "memory": "64Mi"},
+						},
+						SecurityContext: containerSecurityContext,
+					}},
+				},
+			},
+		},
+	}
+	content, err := toYAML(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return []GeneratedFile{{Path: SmokeTestFile, Content: content}}, nil
+}
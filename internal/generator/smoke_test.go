@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestSmokeChecks(t *testing.T) {
+	tests := []struct {
+		name     string
+		analysis *types.AppAnalysis
+		routes   []string
+		want     []SmokeCheck
+	}{
+		{
+			name: "readiness then routes",
+			analysis: &types.AppAnalysis{
+				Ports:       []types.Port{{Port: 8080}},
+				HealthCheck: &types.HealthCheck{Path: "/ready", Port: 8080},
+				Code:        &types.CodeAnalysis{Routes: []string{"/api/orders", "/ready"}},
+			},
+			routes: []string{"/api/status"},
+			want:   []SmokeCheck{{"/ready", 8080}, {"/api/orders", 8080}, {"/api/status", 8080}},
+		},
+		{
+			name: "code routes capped",
+			analysis: &types.AppAnalysis{
+				Ports: []types.Port{{Port: 3000}},
+				Code:  &types.CodeAnalysis{Routes: []string{"/a", "/b", "/c", "/d", "/e", "/f"}},
+			},
+			want: []SmokeCheck{{"/a", 3000}, {"/b", 3000}, {"/c", 3000}, {"/d", 3000}, {"/e", 3000}},
+		},
+		{
+			name:     "no ports",
+			analysis: &types.AppAnalysis{Code: &types.CodeAnalysis{Routes: []string{"/a"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.SmokeTest.Routes = tt.routes
+			if got := SmokeChecks(tt.analysis, cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SmokeChecks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSmokeTest(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:        "orders",
+		Ports:       []types.Port{{Port: 8080}},
+		HealthCheck: &types.HealthCheck{Path: "/ready", Port: 8080},
+		Code:        &types.CodeAnalysis{Routes: []string{"/api/orders"}},
+	}
+	cfg := config.Default()
+
+	files, err := GenerateSmokeTest(analysis, "shop", cfg)
+	if err != nil || files != nil {
+		t.Fatalf("GenerateSmokeTest() disabled = %v, %v; want nothing", files, err)
+	}
+
+	cfg.SmokeTest.Enabled = true
+	files, err = GenerateSmokeTest(analysis, "shop", cfg)
+	if err != nil {
+		t.Fatalf("GenerateSmokeTest() error = %v", err)
+	}
+	if len(files) != 1 || files[0].Path != SmokeTestFile {
+		t.Fatalf("GenerateSmokeTest() files = %v, want %s", files, SmokeTestFile)
+	}
+	for _, want := range []string{
+		"kind: Job",
+		"name: orders-smoke-test",
+		"namespace: shop",
+		"argocd.argoproj.io/hook: PostSync",
+		"helm.sh/hook: post-install,post-upgrade",
+		"image: " + defaultSmokeTestImage,
+		"- http://orders:8080/ready",
+		"- http://orders:8080/api/orders",
+		"backoffLimit: 0",
+	} {
+		if !strings.Contains(files[0].Content, want) {
+			t.Errorf("smoke test missing %q:\n%s", want, files[0].Content)
+		}
+	}
+
+	cfg.SmokeTest.Routes = []string{"api/status"}
+	if _, err := GenerateSmokeTest(analysis, "shop", cfg); err == nil {
+		t.Error("GenerateSmokeTest() accepted a route without a leading /")
+	}
+}
@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// SuppressionsFile, in an app's directory, acknowledges validation findings
+// the team accepts
+const SuppressionsFile = ".dorgu-suppressions.yaml"
+
+// suppressionDateLayout is the layout of Suppression.Expires
+const suppressionDateLayout = "2006-01-02"
+
+// Suppression acknowledges the validation findings of a rule
+type Suppression struct {
+	// Rule is the ID of the rule, e.g. DORGU-IMG-001, or a category to
+	// acknowledge the findings of all its rules, e.g. image
+	Rule string `json:"rule"`
+	// File limits the suppression to the findings in one generated file,
+	// e.g. deployment.yaml
+	File string `json:"file,omitempty"`
+	// Justification says why the findings are acceptable
+	Justification string `json:"justification"`
+	// Expires is the last day (YYYY-MM-DD) the suppression applies; it
+	// never expires when empty
+	Expires string `json:"expires,omitempty"`
+}
+
+// SuppressedIssue is a finding a suppression acknowledged
+type SuppressedIssue struct {
+	ValidationIssue
+	Justification string `json:"justification"`
+	Expires       string `json:"expires,omitempty"`
+}
+
+// LoadSuppressions reads SuppressionsFile in dir; there are none when it
+// does not exist
+func LoadSuppressions(dir string) ([]Suppression, error) {
+	data, err := os.ReadFile(filepath.Join(dir, SuppressionsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Suppressions []Suppression `json:"suppressions"`
+	}
+	if err := yaml.UnmarshalStrict(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", SuppressionsFile, err)
+	}
+	for i, s := range doc.Suppressions {
+		switch {
+		case s.Rule == "":
+			return nil, fmt.Errorf("invalid %s: suppressions[%d] has no rule", SuppressionsFile, i)
+		case !isRuleCategory(s.Rule) && !isRuleID(s.Rule):
+			return nil, fmt.Errorf("invalid %s: suppressions[%d] rule %q is neither a rule ID nor a category; see dorgu explain-rule", SuppressionsFile, i, s.Rule)
+		case s.Justification == "":
+			return nil, fmt.Errorf("invalid %s: suppressions[%d] (%s) has no justification", SuppressionsFile, i, s.Rule)
+		}
+		if s.Expires != "" {
+			if _, err := time.Parse(suppressionDateLayout, s.Expires); err != nil {
+				return nil, fmt.Errorf("invalid %s: suppressions[%d] (%s) expires %q is not a YYYY-MM-DD date", SuppressionsFile, i, s.Rule, s.Expires)
+			}
+		}
+	}
+	return doc.Suppressions, nil
+}
+
+// matches reports whether s covers issue, expired or not
+func (s Suppression) matches(issue ValidationIssue) bool {
+	return (strings.EqualFold(s.Rule, issue.Rule) || s.Rule == issue.Category) && (s.File == "" || s.File == issue.File)
+}
+
+// findings describes the findings s covers
+func (s Suppression) findings() string {
+	if s.File == "" {
+		return s.Rule + " findings"
+	}
+	return s.Rule + " findings in " + s.File
+}
+
+// expired reports whether s no longer applies at now
+func (s Suppression) expired(now time.Time) bool {
+	if s.Expires == "" {
+		return false
+	}
+	last, _ := time.ParseInLocation(suppressionDateLayout, s.Expires, now.Location())
+	return !now.Before(last.AddDate(0, 0, 1))
+}
+
+// applySuppressions moves the issues an unexpired suppression covers to
+// result.Suppressed. It reports expired suppressions that still match
+// findings, so they are revisited, and those that match none, so they are
+// removed.
+func applySuppressions(result *ValidationResult, suppressions []Suppression, now time.Time) {
+	if len(suppressions) == 0 {
+		return
+	}
+	used := make([]bool, len(suppressions))
+	var kept []ValidationIssue
+	for _, issue := range result.Issues {
+		suppressed := false
+		for i, s := range suppressions {
+			if !s.matches(issue) {
+				continue
+			}
+			used[i] = true
+			if !s.expired(now) {
+				result.Suppressed = append(result.Suppressed, SuppressedIssue{ValidationIssue: issue, Justification: s.Justification, Expires: s.Expires})
+				suppressed = true
+				break
+			}
+		}
+		if !suppressed {
+			kept = append(kept, issue)
+		}
+	}
+	for i, s := range suppressions {
+		switch {
+		case used[i] && s.expired(now):
+			kept = append(kept, ValidationIssue{
+				Rule:       RuleSuppressionExpired,
+				Severity:   SeverityWarning,
+				Category:   "suppressions",
+				File:       SuppressionsFile,
+				Message:    fmt.Sprintf("The suppression of %s expired on %s", s.findings(), s.Expires),
+				Suggestion: "Fix the findings, or extend the suppression with a fresh justification",
+			})
+		case !used[i]:
+			kept = append(kept, ValidationIssue{
+				Rule:       RuleSuppressionUnused,
+				Severity:   SeverityInfo,
+				Category:   "suppressions",
+				File:       SuppressionsFile,
+				Message:    fmt.Sprintf("The suppression of %s matches none", s.findings()),
+				Suggestion: "Remove it from " + SuppressionsFile,
+			})
+		}
+	}
+	result.Issues = kept
+}
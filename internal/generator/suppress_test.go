@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadSuppressions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		err     string
+	}{
+		{name: "valid", content: "suppressions:\n- rule: image\n  file: deployment.yaml\n  justification: tags are pinned by CI\n  expires: \"2026-12-31\"\n", want: 1},
+		{name: "no justification", content: "suppressions:\n- rule: image\n", err: "no justification"},
+		{name: "bad date", content: "suppressions:\n- rule: image\n  justification: pinned\n  expires: 31/12/2026\n", err: "YYYY-MM-DD"},
+		{name: "rule ID", content: "suppressions:\n- rule: DORGU-IMG-002\n  justification: pinned\n", want: 1},
+		{name: "unknown rule", content: "suppressions:\n- rule: DORGU-XYZ-001\n  justification: pinned\n", err: "neither a rule ID nor a category"},
+		{name: "unknown field", content: "suppressions:\n- rule: image\n  justification: pinned\n  until: \"2026-12-31\"\n", err: "until"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, SuppressionsFile), []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadSuppressions(dir)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want one mentioning %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil || len(got) != tt.want {
+				t.Fatalf("got %+v, %v; want %d suppressions", got, err, tt.want)
+			}
+		})
+	}
+
+	if got, err := LoadSuppressions(t.TempDir()); got != nil || err != nil {
+		t.Errorf("without the file: %+v, %v", got, err)
+	}
+}
+
+func TestApplySuppressions(t *testing.T) {
+	now := time.Date(2026, 6, 30, 18, 0, 0, 0, time.UTC)
+	issues := []ValidationIssue{
+		{Rule: RuleImagePlaceholder, Severity: SeverityWarning, Category: "image", File: "deployment.yaml", Message: "placeholder image"},
+		{Rule: RuleIngressHostInvalid, Severity: SeverityError, Category: "ingress", File: "ingress.yaml", Message: "bad host"},
+		{Rule: RuleNoHealthProbes, Severity: SeverityWarning, Category: "health", File: "deployment.yaml", Message: "no probes"},
+		{Rule: RuleImageLatestTag, Severity: SeverityInfo, Category: "image", File: "deployment.yaml", Message: "latest tag"},
+	}
+	suppressions := []Suppression{
+		{Rule: "dorgu-img-001", File: "deployment.yaml", Justification: "set by CI", Expires: "2026-06-30"},
+		{Rule: "ingress", Justification: "migrating hosts", Expires: "2026-06-29"},
+		{Rule: "health", File: "worker.yaml", Justification: "no such file"},
+	}
+	result := &ValidationResult{Issues: append([]ValidationIssue(nil), issues...)}
+	applySuppressions(result, suppressions, now)
+
+	if len(result.Suppressed) != 1 || result.Suppressed[0].Message != "placeholder image" || result.Suppressed[0].Justification != "set by CI" {
+		t.Errorf("suppressed = %+v, want the placeholder image finding", result.Suppressed)
+	}
+	var got []string
+	for _, issue := range result.Issues {
+		got = append(got, string(issue.Severity)+" "+issue.Message)
+	}
+	want := []string{
+		"error bad host",
+		"warning no probes",
+		"info latest tag",
+		"warning The suppression of ingress findings expired on 2026-06-29",
+		"info The suppression of health findings in worker.yaml matches none",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// TemplateSuffix is appended to a generated file's path to name its override
+const TemplateSuffix = ".tmpl"
+
+// TemplateData is the data passed to override templates
+type TemplateData struct {
+	Analysis  *types.AppAnalysis
+	Config    *config.Config
+	Namespace string
+	// Resources are the requests/limits chosen for the app's resource profile
+	Resources config.ResourceSpec
+	// Default is the content dorgu generated for this file, so a template can
+	// wrap or patch it instead of starting from scratch
+	Default string
+}
+
+// templateFuncs are the helpers available to override templates
+var templateFuncs = template.FuncMap{
+	"toYaml": func(v interface{}) (string, error) {
+		data, err := yaml.Marshal(v)
+		return strings.TrimSuffix(string(data), "\n"), err
+	},
+	"indent": func(n int, s string) string {
+		pad := strings.Repeat(" ", n)
+		return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+	},
+	"nindent": func(n int, s string) string {
+		pad := strings.Repeat(" ", n)
+		return "\n" + pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+	},
+	"quote": strconv.Quote,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"join":  strings.Join,
+	"default": func(def, v interface{}) interface{} {
+		if v == nil || v == "" || v == 0 {
+			return def
+		}
+		return v
+	},
+}
+
+// templatePath returns the override template for a generated file path;
+// files written outside the output directory (../PERSONA.md) are looked up
+// relative to the repository root
+func templatePath(dir, path string) string {
+	return filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(path, "../")+TemplateSuffix))
+}
+
+// ApplyTemplateOverrides replaces each generated file that has a matching
+// template in opts.Config.Templates.Dir with the rendered template
+func ApplyTemplateOverrides(analysis *types.AppAnalysis, opts Options, resources config.ResourceSpec, files []GeneratedFile) ([]GeneratedFile, error) {
+	dir := opts.Config.Templates.Dir
+	if dir == "" {
+		return files, nil
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("templates.dir %s is not a directory", dir)
+	}
+
+	for i, f := range files {
+		path := templatePath(dir, f.Path)
+		src, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Option("missingkey=error").Parse(string(src))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+		}
+		var buf bytes.Buffer
+		err = tmpl.Execute(&buf, TemplateData{
+			Analysis:  analysis,
+			Config:    opts.Config,
+			Namespace: opts.Namespace,
+			Resources: resources,
+			Default:   f.Content,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to render template %s: %w", path, err)
+		}
+		files[i].Content = buf.String()
+		slog.Info("applied template override", "file", f.Path, "template", path)
+	}
+	return files, nil
+}
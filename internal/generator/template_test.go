@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestApplyTemplateOverrides(t *testing.T) {
+	dir := t.TempDir()
+	deployment := `apiVersion: company.io/v1
+kind: Service
+metadata:
+  name: {{ .Analysis.Name }}
+  namespace: {{ .Namespace }}
+spec:
+  cpu: {{ .Resources.Limits.CPU | quote }}
+  ports:{{ range .Analysis.Ports }}
+    - {{ .Port }}{{ end }}
+`
+	if err := os.WriteFile(filepath.Join(dir, "deployment.yaml.tmpl"), []byte(deployment), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".github", "workflows"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".github", "workflows", "deploy.yaml.tmpl"), []byte("# company header\n{{ .Default }}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.Templates.Dir = dir
+	analysis := &types.AppAnalysis{Name: "orders", Ports: []types.Port{{Port: 8080}}}
+	files := []GeneratedFile{
+		{Path: "deployment.yaml", Content: "built-in"},
+		{Path: "service.yaml", Content: "built-in service"},
+		{Path: "../.github/workflows/deploy.yaml", Content: "name: deploy\n"},
+	}
+	resources := config.ResourceSpec{Limits: config.ResourceValues{CPU: "2"}}
+
+	got, err := ApplyTemplateOverrides(analysis, Options{Namespace: "commerce", Config: cfg}, resources, files)
+	if err != nil {
+		t.Fatalf("ApplyTemplateOverrides() error: %v", err)
+	}
+
+	for _, want := range []string{"kind: Service", "name: orders", "namespace: commerce", `cpu: "2"`, "- 8080"} {
+		if !strings.Contains(got[0].Content, want) {
+			t.Errorf("deployment.yaml missing %q:\n%s", want, got[0].Content)
+		}
+	}
+	if got[1].Content != "built-in service" {
+		t.Errorf("service.yaml without a template changed: %q", got[1].Content)
+	}
+	if got[2].Content != "# company header\nname: deploy\n" {
+		t.Errorf("workflow = %q", got[2].Content)
+	}
+}
+
+func TestApplyTemplateOverrides_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "hpa.yaml.tmpl"), []byte("{{ .Analysis.Nope }}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Default()
+	cfg.Templates.Dir = dir
+	files := []GeneratedFile{{Path: "hpa.yaml"}}
+
+	if _, err := ApplyTemplateOverrides(&types.AppAnalysis{}, Options{Config: cfg}, config.ResourceSpec{}, files); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+
+	cfg.Templates.Dir = filepath.Join(dir, "missing")
+	if _, err := ApplyTemplateOverrides(&types.AppAnalysis{}, Options{Config: cfg}, config.ResourceSpec{}, files); err == nil {
+		t.Error("expected an error for a missing templates directory")
+	}
+}
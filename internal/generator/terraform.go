@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TerraformFile holds the manifests as Terraform resources with
+// --format terraform
+const TerraformFile = "main.tf"
+
+// hclIdentifier matches object keys that need no quotes
+var hclIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// renderTerraform declares objects as kubernetes_manifest resources for the
+// hashicorp/kubernetes provider. The app's image becomes var.image, so
+// pipelines roll out a new tag with terraform apply -var.
+func renderTerraform(objects []map[string]interface{}, image string) string {
+	w := &literalWriter{ident: hclIdentifier, quote: hclString, assign: " = ", align: true, image: image, imageRef: "var.image"}
+	var body strings.Builder
+	names := map[string]int{}
+	for _, obj := range objects {
+		name := terraformName(obj)
+		names[name]++
+		if n := names[name]; n > 1 {
+			name = fmt.Sprintf("%s_%d", name, n)
+		}
+		fmt.Fprintf(&body, "\nresource \"kubernetes_manifest\" %q {\n  manifest = ", name)
+		w.value(&body, obj, 1)
+		body.WriteString("\n}\n")
+	}
+
+	var b strings.Builder
+	b.WriteString("terraform {\n  required_providers {\n    kubernetes = {\n      source = \"hashicorp/kubernetes\"\n    }\n  }\n}\n")
+	if w.usedImage {
+		fmt.Fprintf(&b, "\nvariable \"image\" {\n  description = \"Container image of the app\"\n  type        = string\n  default     = %s\n}\n", hclString(image))
+	}
+	b.WriteString(body.String())
+	return b.String()
+}
+
+// terraformName returns the resource name of an object, <kind>_<name>
+func terraformName(obj map[string]interface{}) string {
+	id := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, objectName(obj))
+	if id == "" || id[0] >= '0' && id[0] <= '9' {
+		id = "_" + id
+	}
+	return id
+}
+
+// hclString quotes s as an HCL string literal, escaping template sequences
+// so values such as "${HOME}" stay literal
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range s {
+		switch {
+		case r == '"':
+			b.WriteString(`\"`)
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		case (r == '$' || r == '%') && strings.HasPrefix(s[i+1:], "{"):
+			b.WriteRune(r)
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
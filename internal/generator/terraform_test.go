@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestGenerateTerraform(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:  "orders",
+		Ports: []types.Port{{Port: 8080}},
+	}
+	files, err := Generate(context.Background(), analysis, Options{
+		Namespace:    "default",
+		Config:       config.Default(),
+		NoLLMPersona: true,
+		Format:       FormatTerraform,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tf string
+	for _, f := range files {
+		switch {
+		case f.Path == TerraformFile:
+			tf = f.Content
+		case IsManifest(f), f.Path == KustomizationFile, f.Path == "argocd/application.yaml", f.Path == DefaultCIPath:
+			t.Errorf("unexpected file %s with --format terraform", f.Path)
+		}
+	}
+	for _, want := range []string{
+		"# Code generated by dorgu",
+		`source = "hashicorp/kubernetes"`,
+		`default     = "orders:latest"`,
+		`resource "kubernetes_manifest" "deployment_orders" {`,
+		`resource "kubernetes_manifest" "service_orders" {`,
+		"image = var.image",
+		`"app.kubernetes.io/name" = "orders"`,
+	} {
+		if !strings.Contains(tf, want) {
+			t.Errorf("%s missing %q:\n%s", TerraformFile, want, tf)
+		}
+	}
+}
+
+func TestRenderTerraform(t *testing.T) {
+	objects := []map[string]interface{}{
+		{"kind": "ConfigMap", "metadata": map[string]interface{}{"name": "app"}, "data": map[string]interface{}{"path": "${HOME}/x", "pct": "100%{", "quote": "a\"b\n"}},
+		{"kind": "ConfigMap", "metadata": map[string]interface{}{"name": "app"}, "list": []interface{}{float64(1), true, nil}},
+	}
+	got := renderTerraform(objects, "")
+	for _, want := range []string{
+		`path  = "$${HOME}/x"`,
+		`pct   = "100%%{"`,
+		`quote = "a\"b\n"`,
+		`"kubernetes_manifest" "configmap_app"`,
+		`"kubernetes_manifest" "configmap_app_2"`,
+		`list = [1, true, null]`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("renderTerraform missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "variable \"image\"") {
+		t.Errorf("image variable declared without an image:\n%s", got)
+	}
+}
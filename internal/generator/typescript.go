@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Entry points of the TypeScript programs scaffolded by --format cdk8s and
+// --format pulumi
+const (
+	CDK8sFile  = "main.ts"
+	PulumiFile = "index.ts"
+)
+
+// tsIdentifier matches object keys that need no quotes
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsString quotes s as a TypeScript string literal
+func tsString(s string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// tsWriter writes objects as TypeScript literals with the image replaced by
+// the program's image constant
+func tsWriter(image string) *literalWriter {
+	return &literalWriter{ident: tsIdentifier, quote: tsString, assign: ": ", comma: true, image: image, imageRef: "image"}
+}
+
+// tsClassName returns a PascalCase class name for an app, e.g. OrdersApi
+// for orders-api
+func tsClassName(app string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range app {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9':
+			if upper && r >= 'a' && r <= 'z' {
+				r -= 'a' - 'A'
+			}
+			b.WriteRune(r)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	name := b.String()
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "App" + name
+	}
+	return name
+}
+
+// packageJSON renders the npm manifest of a scaffolded program
+func packageJSON(name string, scripts, deps, devDeps map[string]string) string {
+	pkg := struct {
+		Name            string            `json:"name"`
+		Private         bool              `json:"private"`
+		Scripts         map[string]string `json:"scripts,omitempty"`
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}{Name: name, Private: true, Scripts: scripts, Dependencies: deps, DevDependencies: devDeps}
+	data, _ := json.MarshalIndent(pkg, "", "  ")
+	return string(data) + "\n"
+}
+
+// tsconfigJSON is the compiler config shared by the scaffolded programs
+const tsconfigJSON = `{
+  "compilerOptions": {
+    "target": "ES2020",
+    "module": "commonjs",
+    "strict": true,
+    "esModuleInterop": true,
+    "skipLibCheck": true
+  },
+  "include": ["*.ts"]
+}
+`
+
+// renderCDK8s scaffolds a cdk8s TypeScript app with one chart declaring
+// objects as ApiObjects. The image is read from $IMAGE at synth time.
+func renderCDK8s(app string, objects []map[string]interface{}, image string) []GeneratedFile {
+	w := tsWriter(image)
+	var body strings.Builder
+	for _, obj := range objects {
+		fmt.Fprintf(&body, "\n    new ApiObject(this, %s, ", tsString(objectName(obj)))
+		w.value(&body, obj, 2)
+		body.WriteString(");\n")
+	}
+	class := tsClassName(app) + "Chart"
+
+	var b strings.Builder
+	b.WriteString("import { ApiObject, App, Chart } from \"cdk8s\";\nimport { Construct } from \"constructs\";\n\n")
+	if w.usedImage {
+		fmt.Fprintf(&b, "const image = process.env.IMAGE ?? %s;\n\n", tsString(image))
+	}
+	fmt.Fprintf(&b, "export class %s extends Chart {\n  constructor(scope: Construct, id: string) {\n    super(scope, id);\n", class)
+	b.WriteString(body.String())
+	b.WriteString("  }\n}\n\n")
+	fmt.Fprintf(&b, "const app = new App();\nnew %s(app, %s);\napp.synth();\n", class, tsString(app))
+
+	return []GeneratedFile{
+		{Path: CDK8sFile, Content: b.String()},
+		{Path: "cdk8s.yaml", Content: "language: typescript\napp: npx ts-node " + CDK8sFile + "\n"},
+		{Path: "package.json", Content: packageJSON(app+"-cdk8s",
+			map[string]string{"synth": "cdk8s synth"},
+			map[string]string{"cdk8s": "^2.68.0", "constructs": "^10.3.0"},
+			map[string]string{"@types/node": "^20.0.0", "cdk8s-cli": "^2.198.0", "ts-node": "^10.9.2", "typescript": "^5.4.0"})},
+		{Path: "tsconfig.json", Content: tsconfigJSON},
+	}
+}
+
+// renderPulumi scaffolds a Pulumi TypeScript program declaring objects in
+// one ConfigGroup. The image is the program's image config value.
+func renderPulumi(app string, objects []map[string]interface{}, image string) []GeneratedFile {
+	w := tsWriter(image)
+	var objs strings.Builder
+	w.list(&objs, toInterfaces(objects), 1)
+
+	var b strings.Builder
+	b.WriteString("import * as k8s from \"@pulumi/kubernetes\";\nimport * as pulumi from \"@pulumi/pulumi\";\n\n")
+	if w.usedImage {
+		fmt.Fprintf(&b, "const config = new pulumi.Config();\nconst image = config.get(\"image\") ?? %s;\n\n", tsString(image))
+	}
+	fmt.Fprintf(&b, "export const resources = new k8s.yaml.ConfigGroup(%s, {\n  objs: %s,\n});\n", tsString(app), objs.String())
+
+	return []GeneratedFile{
+		{Path: PulumiFile, Content: b.String()},
+		{Path: "Pulumi.yaml", Content: fmt.Sprintf("name: %s\nruntime: nodejs\ndescription: Kubernetes resources of %s, generated by dorgu\n", app, app)},
+		{Path: "package.json", Content: packageJSON(app+"-pulumi", nil,
+			map[string]string{"@pulumi/kubernetes": "^4.11.0", "@pulumi/pulumi": "^3.113.0"},
+			map[string]string{"@types/node": "^20.0.0", "typescript": "^5.4.0"})},
+		{Path: "tsconfig.json", Content: tsconfigJSON},
+	}
+}
+
+// toInterfaces converts objects for literalWriter.list
+func toInterfaces(objects []map[string]interface{}) []interface{} {
+	items := make([]interface{}, len(objects))
+	for i, obj := range objects {
+		items[i] = obj
+	}
+	return items
+}
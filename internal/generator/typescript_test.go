@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestGenerateTypeScriptPrograms(t *testing.T) {
+	tests := []struct {
+		format string
+		want   map[string][]string
+	}{
+		{
+			format: FormatCDK8s,
+			want: map[string][]string{
+				CDK8sFile: {
+					"// Code generated by dorgu",
+					`import { ApiObject, App, Chart } from "cdk8s";`,
+					`const image = process.env.IMAGE ?? "orders-api:latest";`,
+					"export class OrdersApiChart extends Chart {",
+					`new ApiObject(this, "deployment-orders-api", {`,
+					"image: image,",
+					`"app.kubernetes.io/name": "orders-api",`,
+				},
+				"cdk8s.yaml":   {"app: npx ts-node main.ts"},
+				"package.json": {`"cdk8s":`},
+			},
+		},
+		{
+			format: FormatPulumi,
+			want: map[string][]string{
+				PulumiFile: {
+					`const image = config.get("image") ?? "orders-api:latest";`,
+					`new k8s.yaml.ConfigGroup("orders-api", {`,
+					`kind: "Service",`,
+				},
+				"Pulumi.yaml":   {"runtime: nodejs"},
+				"package.json":  {`"@pulumi/kubernetes":`},
+				"tsconfig.json": {`"strict": true`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			analysis := &types.AppAnalysis{
+				Name:  "orders-api",
+				Ports: []types.Port{{Port: 8080}},
+			}
+			files, err := Generate(context.Background(), analysis, Options{
+				Namespace:    "default",
+				Config:       config.Default(),
+				NoLLMPersona: true,
+				Format:       tt.format,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]string{}
+			for _, f := range files {
+				if IsManifest(f) || f.Path == KustomizationFile || f.Path == "argocd/application.yaml" || f.Path == DefaultCIPath {
+					t.Errorf("unexpected file %s with --format %s", f.Path, tt.format)
+				}
+				got[f.Path] = f.Content
+			}
+			for path, wants := range tt.want {
+				for _, want := range wants {
+					if !strings.Contains(got[path], want) {
+						t.Errorf("%s missing %q:\n%s", path, want, got[path])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTSClassName(t *testing.T) {
+	for in, want := range map[string]string{
+		"orders-api": "OrdersApi",
+		"my_app.v2":  "MyAppV2",
+		"2fa":        "App2fa",
+	} {
+		if got := tsClassName(in); got != want {
+			t.Errorf("tsClassName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -2,10 +2,18 @@ package generator
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"net"
 	"os/exec"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
 	"github.com/dorgu-ai/dorgu/internal/types"
 )
 
@@ -20,18 +28,41 @@ const (
 
 // ValidationIssue is a single validation finding
 type ValidationIssue struct {
-	Severity   ValidationSeverity
-	Category   string
-	File       string
-	Message    string
-	Suggestion string
+	// Rule is the ID of the rule that found the issue, e.g. DORGU-IMG-001
+	Rule       string             `json:"rule"`
+	Severity   ValidationSeverity `json:"severity"`
+	Category   string             `json:"category"`
+	File       string             `json:"file,omitempty"`
+	Message    string             `json:"message"`
+	Suggestion string             `json:"suggestion,omitempty"`
+	// ConfigKey is the .dorgu.yaml setting the issue comes from, if any,
+	// e.g. ingress.host
+	ConfigKey string `json:"config_key,omitempty"`
+	// DocURL links to the rule's documentation
+	DocURL string `json:"doc_url,omitempty"`
 }
 
 // ValidationResult is the full validation report
 type ValidationResult struct {
-	Issues  []ValidationIssue
-	Passed  bool
-	Summary string
+	Issues []ValidationIssue `json:"issues"`
+	// Suppressed are the findings SuppressionsFile acknowledges; they do not
+	// fail validation
+	Suppressed []SuppressedIssue `json:"suppressed,omitempty"`
+	Passed     bool              `json:"passed"`
+	Summary    string            `json:"summary"`
+}
+
+// Fails reports whether the result has issues of severity failOn or worse,
+// where failOn is error or warning; no issue fails it when failOn is empty
+func (r *ValidationResult) Fails(failOn string) bool {
+	for _, issue := range r.Issues {
+		switch {
+		case issue.Severity == SeverityError && failOn != "",
+			issue.Severity == SeverityWarning && failOn == config.FailOnWarning:
+			return true
+		}
+	}
+	return false
 }
 
 // K8s manifest file names we run through kubectl dry-run (core types only; no CRDs)
@@ -42,19 +73,40 @@ var kubectlManifestPaths = map[string]bool{
 	"hpa.yaml":        true,
 }
 
-// ValidateGenerated runs post-generation validation and returns a report
-func ValidateGenerated(analysis *types.AppAnalysis, files []GeneratedFile, opts Options) *ValidationResult {
+// ValidateGenerated runs post-generation validation and returns a report.
+// Canceling ctx stops the checks that run commands or go over the network.
+func ValidateGenerated(ctx context.Context, analysis *types.AppAnalysis, files []GeneratedFile, opts Options) *ValidationResult {
 	result := &ValidationResult{Passed: true}
 
 	validateImagePlaceholder(analysis, opts, result)
+	validateImageExists(ctx, analysis, files, opts, result)
 	validateResourceRequestsVsLimits(analysis, opts, result)
 	validateServicePortMatch(analysis, result)
+	validateListenPort(analysis, result)
 	validateHPAMinMax(result, analysis)
-	validateIngressHost(analysis, opts, result)
+	validateIngressHost(ctx, analysis, opts, result)
+	validateIngressBackendPorts(analysis, opts, result)
+	validateAgainstCluster(analysis, files, opts, result)
+	validateServiceStaticIP(analysis, opts, result)
+	validateVaultAgentSecrets(analysis, opts, result)
 	validateHealthProbes(analysis, result)
+	validateSchedules(analysis, result)
+	validateMigrations(analysis, opts.Config, result)
+	validateStaticSite(analysis, result)
 	validateMissingRequiredFields(analysis, result)
-	validateKubectlDryRun(files, opts, result)
+	validateOwnership(analysis, opts, result)
+	validateAppName(analysis, result)
+	validatePodSecurityStandard(analysis, files, opts, result)
+	validateKubectlDryRun(ctx, files, opts, result)
+	applySuppressions(result, opts.Suppressions, time.Now())
+	for i := range result.Issues {
+		describeIssue(&result.Issues[i])
+	}
+	for i := range result.Suppressed {
+		describeIssue(&result.Suppressed[i].ValidationIssue)
+	}
 
+	result.Passed = true
 	for _, issue := range result.Issues {
 		if issue.Severity == SeverityError {
 			result.Passed = false
@@ -88,6 +140,9 @@ func ValidateGenerated(analysis *types.AppAnalysis, files []GeneratedFile, opts
 		}
 		result.Summary = "Validation: " + strings.Join(parts, ", ")
 	}
+	if n := len(result.Suppressed); n > 0 {
+		result.Summary += fmt.Sprintf(" (%d suppressed)", n)
+	}
 	return result
 }
 
@@ -95,6 +150,7 @@ func validateImagePlaceholder(analysis *types.AppAnalysis, opts Options, result
 	registry := opts.Config.CI.Registry
 	if registry == "" {
 		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleImagePlaceholder,
 			Severity:   SeverityWarning,
 			Category:   "image",
 			File:       "deployment.yaml",
@@ -103,6 +159,7 @@ func validateImagePlaceholder(analysis *types.AppAnalysis, opts Options, result
 		})
 	}
 	result.Issues = append(result.Issues, ValidationIssue{
+		Rule:       RuleImageLatestTag,
 		Severity:   SeverityInfo,
 		Category:   "image",
 		File:       "deployment.yaml",
@@ -132,6 +189,7 @@ func validateResourceRequestsVsLimits(analysis *types.AppAnalysis, opts Options,
 	limCPU := parseCPUMillis(resources.Limits.CPU)
 	if reqCPU > 0 && limCPU > 0 && reqCPU > limCPU {
 		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleCPURequestOverLimit,
 			Severity:   SeverityError,
 			Category:   "resources",
 			File:       "deployment.yaml",
@@ -143,6 +201,7 @@ func validateResourceRequestsVsLimits(analysis *types.AppAnalysis, opts Options,
 	limMem := parseMemoryBytes(resources.Limits.Memory)
 	if reqMem > 0 && limMem > 0 && reqMem > limMem {
 		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleMemRequestOverLimit,
 			Severity:   SeverityError,
 			Category:   "resources",
 			File:       "deployment.yaml",
@@ -160,15 +219,126 @@ func validateServicePortMatch(analysis *types.AppAnalysis, result *ValidationRes
 	for _, p := range analysis.Ports {
 		portSet[p.Port] = true
 	}
-	if analysis.HealthCheck != nil && !portSet[analysis.HealthCheck.Port] {
+	liveness, readiness := resolveProbes(analysis)
+	for _, probe := range []struct {
+		kind string
+		hc   *types.HealthCheck
+	}{{"Liveness", liveness}, {"Readiness", readiness}} {
+		if probe.hc != nil && !portSet[probe.hc.Port] {
+			result.Issues = append(result.Issues, ValidationIssue{
+				Rule:       RuleProbePort,
+				Severity:   SeverityWarning,
+				Category:   "ports",
+				File:       "deployment.yaml",
+				Message:    fmt.Sprintf("%s probe port %d does not match any container port", probe.kind, probe.hc.Port),
+				Suggestion: "Ensure health check port matches one of the exposed container ports",
+			})
+		}
+	}
+}
+
+// validateListenPort warns when the port the framework config or code binds
+// is not a container port, or is one the Dockerfile does not EXPOSE
+func validateListenPort(analysis *types.AppAnalysis, result *ValidationResult) {
+	if analysis.Code == nil || analysis.Code.ListenPort == 0 {
+		return
+	}
+	listen, source := analysis.Code.ListenPort, analysis.Code.ListenPortSource
+	containerPort := false
+	for _, p := range analysis.Ports {
+		if p.Port == listen {
+			containerPort = true
+			break
+		}
+	}
+	switch {
+	case len(analysis.Ports) > 0 && !containerPort:
 		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleListenPort,
 			Severity:   SeverityWarning,
 			Category:   "ports",
 			File:       "deployment.yaml",
-			Message:    fmt.Sprintf("Health check port %d does not match any container port", analysis.HealthCheck.Port),
-			Suggestion: "Ensure health check port matches one of the exposed container ports",
+			Message:    fmt.Sprintf("The app listens on port %d (%s), which is not a container port", listen, source),
+			Suggestion: fmt.Sprintf("Change the port in %s, or pin ports in .dorgu-analysis.yaml to include %d", source, listen),
 		})
+	case analysis.Dockerfile != nil && len(analysis.Dockerfile.Ports) > 0 && !slices.Contains(analysis.Dockerfile.Ports, listen):
+		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleListenPort,
+			Severity:   SeverityWarning,
+			Category:   "ports",
+			File:       "deployment.yaml",
+			Message:    fmt.Sprintf("The app listens on port %d (%s), but the Dockerfile EXPOSEs %s", listen, source, strings.Trim(fmt.Sprint(analysis.Dockerfile.Ports), "[]")),
+			Suggestion: fmt.Sprintf("Change EXPOSE in the Dockerfile to %d", listen),
+		})
+	}
+}
+
+// validateSchedules reports the cron schedules the analysis found that no
+// job runs
+func validateSchedules(analysis *types.AppAnalysis, result *ValidationResult) {
+	for _, hint := range UnconfiguredSchedules(analysis) {
+		command := "<a command that runs the task once>"
+		if len(hint.Command) > 0 {
+			command = quotedCommand(hint.Command)
+		}
+		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleScheduleUnconfigured,
+			Severity:   SeverityInfo,
+			Category:   "jobs",
+			Message:    fmt.Sprintf("Found cron schedule %q in %s, but no job runs it", hint.Schedule, hint.Source),
+			Suggestion: fmt.Sprintf("To run it as a CronJob, add a job to .dorgu.yaml: name %s, schedule %q, command %s", hint.Name, hint.Schedule, command),
+		})
+	}
+}
+
+// validateStaticSite warns when a static site keeps the nginx configuration
+// its Dockerfile copies, which the generated Deployment may not run with
+func validateStaticSite(analysis *types.AppAnalysis, result *ValidationResult) {
+	site := analysis.StaticSite
+	if site == nil || site.NginxConfig == "" || analysis.Type != "web" {
+		return
 	}
+	result.Issues = append(result.Issues, ValidationIssue{
+		Rule:       RuleNginxConfigCopied,
+		Severity:   SeverityWarning,
+		Category:   "static",
+		File:       "deployment.yaml",
+		Message:    fmt.Sprintf("The Dockerfile copies its own nginx configuration to %s, so the site is deployed as a generic web app without the generated nginx.conf", site.NginxConfig),
+		Suggestion: fmt.Sprintf("Remove the COPY to serve the site with the generated nginx.conf on port %d as a non-root user, or make your configuration listen above 1024 and write its pid and temp files to a writable volume", types.StaticSitePort),
+	})
+}
+
+// quotedCommand renders a command as a YAML flow sequence
+func quotedCommand(command []string) string {
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = strconv.Quote(arg)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// validateMigrations suggests the pre-deploy job for migrations nothing
+// applies
+func validateMigrations(analysis *types.AppAnalysis, cfg *config.Config, result *ValidationResult) {
+	m := UnconfiguredMigrations(analysis, cfg)
+	if m == nil {
+		return
+	}
+	suggestion := fmt.Sprintf("To migrate before each rollout with %s, set migrations.job: true in the workspace .dorgu.yaml", quotedCommand(m.Command))
+	if m.Image != "" {
+		suggestion += fmt.Sprintf(" and migrations.image to an image built FROM %s with the migrations, as the app's image usually lacks the %s CLI", m.Image, m.Tool)
+	}
+	suggestion += fmt.Sprintf(", or add a job to the app's .dorgu.yaml: name %s, hook %s", migrationJobName, config.HookPreDeploy)
+	for _, ref := range envRefPattern.FindAllStringSubmatch(strings.Join(m.Command, " "), -1) {
+		suggestion += fmt.Sprintf("; the command reads %s, which the app's env or secrets must set", ref[1])
+	}
+	result.Issues = append(result.Issues, ValidationIssue{
+		Rule:       RuleMigrationsUnconfigured,
+		Severity:   SeverityInfo,
+		Category:   "jobs",
+		Message:    fmt.Sprintf("Found %s migrations (%s), but no job applies them and the app does not apply them as it starts", m.Tool, m.Source),
+		Suggestion: suggestion,
+	})
 }
 
 func validateHPAMinMax(result *ValidationResult, analysis *types.AppAnalysis) {
@@ -181,6 +351,7 @@ func validateHPAMinMax(result *ValidationResult, analysis *types.AppAnalysis) {
 	}
 	if scaling.MinReplicas > scaling.MaxReplicas {
 		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleHPAMinOverMax,
 			Severity:   SeverityError,
 			Category:   "scaling",
 			File:       "hpa.yaml",
@@ -190,26 +361,159 @@ func validateHPAMinMax(result *ValidationResult, analysis *types.AppAnalysis) {
 	}
 }
 
-func validateIngressHost(analysis *types.AppAnalysis, opts Options, result *ValidationResult) {
-	host := analysis.Name + opts.Config.Ingress.DomainSuffix
-	if analysis.AppConfig != nil && analysis.AppConfig.Ingress != nil && analysis.AppConfig.Ingress.Host != "" {
-		host = analysis.AppConfig.Ingress.Host
+// lookupHost resolves host names for validation.ingress.resolve_hosts
+var lookupHost = net.DefaultResolver.LookupHost
+
+// hostLookupTimeout bounds the DNS lookup of each ingress host
+const hostLookupTimeout = 5 * time.Second
+
+func validateIngressHost(ctx context.Context, analysis *types.AppAnalysis, opts Options, result *ValidationResult) {
+	if !hasIngress(analysis) {
+		return
+	}
+	policy := opts.Config.Validation.Ingress
+	for _, h := range ingressHosts(analysis, opts.Config) {
+		if h.Host == "" {
+			result.Issues = append(result.Issues, ValidationIssue{
+				Rule:       RuleIngressHostEmpty,
+				Severity:   SeverityWarning,
+				Category:   "ingress",
+				File:       "ingress.yaml",
+				Message:    "Ingress host is empty",
+				Suggestion: "Set ingress.host in .dorgu.yaml or ensure naming.domain_suffix is set in org config",
+			})
+			continue
+		}
+		if msg := checkIngressHost(h.Host); msg != "" {
+			result.Issues = append(result.Issues, ValidationIssue{
+				Rule:       RuleIngressHostInvalid,
+				Severity:   SeverityError,
+				Category:   "ingress",
+				File:       "ingress.yaml",
+				Message:    fmt.Sprintf("Ingress host %q %s", h.Host, msg),
+				Suggestion: "Use a fully qualified domain name such as orders.apps.example.com",
+			})
+			continue
+		}
+		if len(policy.DomainSuffixes) > 0 && !HostInDomains(h.Host, policy.DomainSuffixes) {
+			result.Issues = append(result.Issues, ValidationIssue{
+				Rule:       RuleIngressHostDomain,
+				Severity:   SeverityError,
+				Category:   "ingress",
+				File:       "ingress.yaml",
+				Message:    fmt.Sprintf("Ingress host %s is not under an allowed domain (%s)", h.Host, strings.Join(policy.DomainSuffixes, ", ")),
+				Suggestion: "Pick a host under validation.ingress.domain_suffixes, or ask the platform team to allow its domain",
+			})
+		}
+		if policy.ResolveHosts && !strings.HasPrefix(h.Host, "*.") {
+			lookupCtx, cancel := context.WithTimeout(ctx, hostLookupTimeout)
+			_, err := lookupHost(lookupCtx, h.Host)
+			cancel()
+			if err != nil {
+				result.Issues = append(result.Issues, ValidationIssue{
+					Rule:       RuleIngressHostDNS,
+					Severity:   SeverityWarning,
+					Category:   "ingress",
+					File:       "ingress.yaml",
+					Message:    fmt.Sprintf("Ingress host %s does not resolve: %v", h.Host, err),
+					Suggestion: "Create its DNS record (or let external-dns do it); clients and HTTP-01 certificate challenges need it",
+				})
+			}
+		}
+	}
+}
+
+// checkIngressHost returns why host is not a valid Ingress host, or ""
+func checkIngressHost(host string) string {
+	var errs []string
+	if strings.HasPrefix(host, "*.") {
+		errs = validation.IsWildcardDNS1123Subdomain(host)
+	} else {
+		errs = validation.IsDNS1123Subdomain(host)
+	}
+	switch {
+	case len(errs) > 0:
+		return "is not a valid DNS name: " + strings.Join(errs, "; ")
+	case !strings.Contains(strings.TrimPrefix(host, "*."), "."):
+		return "is not a fully qualified domain name"
+	}
+	return ""
+}
+
+// HostInDomains reports whether host is under one of the domain suffixes,
+// given with or without the leading dot
+func HostInDomains(host string, suffixes []string) bool {
+	for _, s := range suffixes {
+		if strings.HasSuffix(host, "."+strings.TrimPrefix(s, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateIngressBackendPorts checks that paths routed to the app's own
+// Service use a port the Service exposes. Other Services are not checked.
+func validateIngressBackendPorts(analysis *types.AppAnalysis, opts Options, result *ValidationResult) {
+	if !hasIngress(analysis) {
+		return
 	}
-	if host == "" {
+	servicePorts := make(map[int]bool)
+	for _, p := range podPorts(analysis, opts.Config) {
+		servicePorts[p.Port] = true
+	}
+	for _, p := range ingressPathDefs(analysis) {
+		if p.Port == 0 || (p.Service != "" && p.Service != analysis.Name) {
+			continue
+		}
+		if !servicePorts[p.Port] {
+			result.Issues = append(result.Issues, ValidationIssue{
+				Rule:       RuleIngressBackendPort,
+				Severity:   SeverityError,
+				Category:   "ingress",
+				File:       "ingress.yaml",
+				Message:    fmt.Sprintf("Ingress path %s routes to port %d, which the Service does not expose", p.Path, p.Port),
+				Suggestion: "Use one of the Service ports, or set ingress.paths[].service to route to another Service",
+			})
+		}
+	}
+}
+
+// validateServiceStaticIP warns where spec.loadBalancerIP is not honored
+func validateServiceStaticIP(analysis *types.AppAnalysis, opts Options, result *ValidationResult) {
+	if analysis.AppConfig == nil || analysis.AppConfig.Service == nil || analysis.AppConfig.Service.StaticIP == "" {
+		return
+	}
+	if serviceCloud(opts.Config) == "aws" {
 		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleServiceStaticIP,
 			Severity:   SeverityWarning,
-			Category:   "ingress",
-			File:       "ingress.yaml",
-			Message:    "Ingress host is empty",
-			Suggestion: "Set ingress.host in .dorgu.yaml or ensure naming.domain_suffix is set in org config",
+			Category:   "service",
+			File:       "service.yaml",
+			Message:    "AWS load balancers ignore spec.loadBalancerIP",
+			Suggestion: "Use an NLB with the service.beta.kubernetes.io/aws-load-balancer-eip-allocations annotation in service.annotations",
 		})
 	}
 }
 
+// validateVaultAgentSecrets reminds that injected secrets are a file, not env
+func validateVaultAgentSecrets(analysis *types.AppAnalysis, opts Options, result *ValidationResult) {
+	if secretsProvider(analysis, opts.Config) != config.SecretsVaultAgent || len(secretEnvVars(analysis)) == 0 {
+		return
+	}
+	result.Issues = append(result.Issues, ValidationIssue{
+		Rule:       RuleVaultAgentSecrets,
+		Severity:   SeverityInfo,
+		Category:   "secrets",
+		File:       "deployment.yaml",
+		Message:    fmt.Sprintf("Secret env vars are rendered by the Vault Agent injector to %s", VaultSecretsFile),
+		Suggestion: fmt.Sprintf("Start the app with `. %s && exec <command>` so it sees them as environment variables", VaultSecretsFile),
+	})
+}
+
 func validateHealthProbes(analysis *types.AppAnalysis, result *ValidationResult) {
-	hasHealth := (analysis.AppConfig != nil && analysis.AppConfig.Health != nil) || analysis.HealthCheck != nil
-	if !hasHealth {
+	if liveness, readiness := resolveProbes(analysis); liveness == nil && readiness == nil {
 		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleNoHealthProbes,
 			Severity:   SeverityWarning,
 			Category:   "health",
 			File:       "deployment.yaml",
@@ -222,6 +526,7 @@ func validateHealthProbes(analysis *types.AppAnalysis, result *ValidationResult)
 func validateMissingRequiredFields(analysis *types.AppAnalysis, result *ValidationResult) {
 	if analysis.Name == "" {
 		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleAppNameMissing,
 			Severity:   SeverityError,
 			Category:   "metadata",
 			File:       "deployment.yaml",
@@ -231,6 +536,7 @@ func validateMissingRequiredFields(analysis *types.AppAnalysis, result *Validati
 	}
 	if analysis.Repository == "" {
 		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleRepositoryMissing,
 			Severity:   SeverityInfo,
 			Category:   "metadata",
 			File:       "argocd/application.yaml",
@@ -240,9 +546,93 @@ func validateMissingRequiredFields(analysis *types.AppAnalysis, result *Validati
 	}
 }
 
+// validateOwnership checks app.team and app.owner against the owners
+// directory, so personas don't carry teams or people that no longer exist
+func validateOwnership(analysis *types.AppAnalysis, opts Options, result *ValidationResult) {
+	dir := opts.Owners
+	if dir == nil {
+		return
+	}
+	source := opts.Config.Owners.Source
+	if analysis.Team != "" && !dir.HasTeam(analysis.Team) {
+		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleTeamUnknown,
+			Severity:   SeverityError,
+			Category:   "ownership",
+			File:       "persona.yaml",
+			Message:    fmt.Sprintf("Team %q is not in the owners directory (%s)", analysis.Team, source),
+			Suggestion: "Set app.team in .dorgu.yaml to one of: " + strings.Join(dir.TeamNames(), ", "),
+		})
+	}
+	if analysis.Owner == "" {
+		return
+	}
+	switch {
+	case !dir.HasOwner(analysis.Owner):
+		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleOwnerUnknown,
+			Severity:   SeverityError,
+			Category:   "ownership",
+			File:       "persona.yaml",
+			Message:    fmt.Sprintf("Owner %q is not in the owners directory (%s)", analysis.Owner, source),
+			Suggestion: "Set app.owner in .dorgu.yaml to a current team member or shared mailbox",
+		})
+	case analysis.Team != "" && !dir.IsMember(analysis.Team, analysis.Owner):
+		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleOwnerNotInTeam,
+			Severity:   SeverityWarning,
+			Category:   "ownership",
+			File:       "persona.yaml",
+			Message:    fmt.Sprintf("Owner %q is not a member of team %q", analysis.Owner, analysis.Team),
+			Suggestion: "Check app.team and app.owner in .dorgu.yaml",
+		})
+	}
+}
+
+// validateAppName flags app names that are not valid resource names: renamed
+// ones under naming.dns_safe, and ones the API server rejects without it
+func validateAppName(analysis *types.AppAnalysis, result *ValidationResult) {
+	switch {
+	case analysis.OriginalName != "":
+		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleAppNameRenamed,
+			Severity:   SeverityError,
+			Category:   "metadata",
+			File:       "deployment.yaml",
+			Message:    fmt.Sprintf("App name %q is not a valid DNS-1123 name; resources were named %q", analysis.OriginalName, analysis.Name),
+			Suggestion: fmt.Sprintf("Set app.name: %s in .dorgu.yaml or use --name to keep this name", analysis.Name),
+		})
+	case analysis.Name != "" && !IsResourceName(analysis.Name):
+		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleAppNameInvalid,
+			Severity:   SeverityError,
+			Category:   "metadata",
+			File:       "deployment.yaml",
+			Message:    fmt.Sprintf("App name %q is not a valid DNS-1123 name; the API server will reject the resources", analysis.Name),
+			Suggestion: fmt.Sprintf("Set app.name: %s in .dorgu.yaml, or enable naming.dns_safe", ResourceName(analysis.Name)),
+		})
+	}
+}
+
 // validateKubectlDryRun runs kubectl apply --dry-run=client on generated K8s manifests.
 // If kubectl is not available, this step is skipped (no issue added).
-func validateKubectlDryRun(files []GeneratedFile, opts Options, result *ValidationResult) {
+// validatePodSecurityStandard checks that the generated pods comply with the
+// Pod Security Standard the app's namespace enforces
+func validatePodSecurityStandard(analysis *types.AppAnalysis, files []GeneratedFile, opts Options, result *ValidationResult) {
+	level := podSecurityStandard(analysis, opts.Config)
+	for _, v := range PodSecurityViolations(files, level) {
+		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RulePodSecurity,
+			Severity:   SeverityError,
+			Category:   "security",
+			File:       v.File,
+			Message:    fmt.Sprintf("Pod violates the %s Pod Security Standard: %s", level, v.Message),
+			Suggestion: "Fix the security settings in .dorgu.yaml or declare a lower security.pod_security_standard; the namespace would reject these pods",
+		})
+	}
+}
+
+func validateKubectlDryRun(ctx context.Context, files []GeneratedFile, opts Options, result *ValidationResult) {
 	if _, err := exec.LookPath("kubectl"); err != nil {
 		return // kubectl not available, skip
 	}
@@ -258,7 +648,7 @@ func validateKubectlDryRun(files []GeneratedFile, opts Options, result *Validati
 	}
 	combined := strings.Join(parts, "\n---\n")
 
-	cmd := exec.Command("kubectl", "apply", "-f", "-", "--dry-run=client", "-n", opts.Namespace)
+	cmd := exec.CommandContext(ctx, "kubectl", "apply", "-f", "-", "--dry-run=client", "-n", opts.Namespace)
 	cmd.Stdin = bytes.NewBufferString(combined)
 	out, err := cmd.CombinedOutput()
 	output := strings.TrimSpace(string(out))
@@ -273,6 +663,7 @@ func validateKubectlDryRun(files []GeneratedFile, opts Options, result *Validati
 			msg = msg + ": " + strings.ReplaceAll(output, "\n", " ")
 		}
 		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleKubectlDryRunFailed,
 			Severity:   SeverityError,
 			Category:   "kubectl",
 			File:       "manifests",
@@ -283,6 +674,7 @@ func validateKubectlDryRun(files []GeneratedFile, opts Options, result *Validati
 	}
 
 	result.Issues = append(result.Issues, ValidationIssue{
+		Rule:       RuleKubectlDryRunPassed,
 		Severity:   SeverityInfo,
 		Category:   "kubectl",
 		File:       "manifests",
@@ -291,45 +683,15 @@ func validateKubectlDryRun(files []GeneratedFile, opts Options, result *Validati
 	})
 }
 
-func parseCPUMillis(cpu string) int64 {
-	if cpu == "" {
-		return 0
-	}
-	if strings.HasSuffix(cpu, "m") {
-		var millis int64
-		fmt.Sscanf(strings.TrimSuffix(cpu, "m"), "%d", &millis)
-		return millis
-	}
-	var cores float64
-	fmt.Sscanf(cpu, "%f", &cores)
-	return int64(cores * 1000)
-}
-
-func parseMemoryBytes(mem string) int64 {
-	if mem == "" {
-		return 0
-	}
-	multipliers := map[string]int64{
-		"Ki": 1024, "Mi": 1024 * 1024, "Gi": 1024 * 1024 * 1024, "Ti": 1024 * 1024 * 1024 * 1024,
-	}
-	for suffix, mult := range multipliers {
-		if strings.HasSuffix(mem, suffix) {
-			var num int64
-			fmt.Sscanf(strings.TrimSuffix(mem, suffix), "%d", &num)
-			return num * mult
-		}
-	}
-	var bytes int64
-	fmt.Sscanf(mem, "%d", &bytes)
-	return bytes
-}
-
 // FormatValidationReport formats the validation result for terminal output
 func FormatValidationReport(result *ValidationResult) string {
+	var sb strings.Builder
+	for _, issue := range result.Suppressed {
+		sb.WriteString(fmt.Sprintf("  - %s [%s] %s (suppressed: %s)\n", issue.Rule, issue.Category, issue.Message, issue.Justification))
+	}
 	if len(result.Issues) == 0 {
-		return "  All validation checks passed"
+		return sb.String() + "  All validation checks passed"
 	}
-	var sb strings.Builder
 	for _, sev := range []ValidationSeverity{SeverityError, SeverityWarning, SeverityInfo} {
 		for _, issue := range result.Issues {
 			if issue.Severity != sev {
@@ -342,7 +704,7 @@ func FormatValidationReport(result *ValidationResult) string {
 			case SeverityWarning:
 				prefix = "  ⚠"
 			}
-			sb.WriteString(fmt.Sprintf("%s [%s] %s\n", prefix, issue.Category, issue.Message))
+			sb.WriteString(fmt.Sprintf("%s %s [%s] %s\n", prefix, issue.Rule, issue.Category, issue.Message))
 			if issue.Suggestion != "" {
 				sb.WriteString(fmt.Sprintf("    → %s\n", issue.Suggestion))
 			}
@@ -0,0 +1,272 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// ClusterState is what validation checks the manifests against in the target
+// cluster. The CLI gathers it for generate --against-cluster.
+type ClusterState struct {
+	// APIVersions are the group/versions the API server serves, including
+	// those of installed CRDs
+	APIVersions []string
+	// NamespaceExists is whether the namespace the app is generated for
+	// exists
+	NamespaceExists bool
+	// StorageClasses are the names of the StorageClasses, and
+	// DefaultStorageClass the one claims without a class get, if any
+	StorageClasses      []string
+	DefaultStorageClass string
+	// IngressClasses are the names of the IngressClasses
+	IngressClasses []string
+	// CertManager is whether the cert-manager CRDs are installed
+	CertManager bool
+	// ClusterIssuers are the names of the cert-manager ClusterIssuers
+	ClusterIssuers []string
+}
+
+// apiVersionHints say how to get an API version, or every version of an API
+// group, the cluster does not serve
+var apiVersionHints = map[string]string{
+	"autoscaling/v2":             "autoscaling/v2 needs Kubernetes 1.23 or later; upgrade the cluster or disable autoscaling",
+	"networking.k8s.io/v1":       "networking.k8s.io/v1 Ingresses need Kubernetes 1.19 or later; upgrade the cluster",
+	"policy/v1":                  "policy/v1 PodDisruptionBudgets need Kubernetes 1.21 or later; upgrade the cluster",
+	"gateway.networking.k8s.io":  "Install the Gateway API CRDs, or expose the app with an Ingress",
+	"argoproj.io":                "Install ArgoCD, apply the Application where ArgoCD runs, or generate with --skip-argocd",
+	"monitoring.coreos.com":      "Install the Prometheus Operator, or set metrics.scrape to annotations",
+	"cert-manager.io":            "Install cert-manager",
+	"external-secrets.io":        "Install the External Secrets Operator, or pick another secrets.provider",
+	"bitnami.com":                "Install the Sealed Secrets controller, or pick another secrets.provider",
+	"secrets-store.csi.x-k8s.io": "Install the Secrets Store CSI driver, or pick another secrets.provider",
+	"keda.sh":                    "Install KEDA, or scale on CPU and memory",
+	"dorgu.io":                   "Install the dorgu operator with 'dorgu operator install'",
+}
+
+// clusterObject is the part of a generated object the cluster checks read
+type clusterObject struct {
+	file       string
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		IngressClassName     string  `json:"ingressClassName"`
+		StorageClassName     *string `json:"storageClassName"`
+		VolumeClaimTemplates []struct {
+			Spec struct {
+				StorageClassName *string `json:"storageClassName"`
+			} `json:"spec"`
+		} `json:"volumeClaimTemplates"`
+		SyncPolicy struct {
+			SyncOptions []string `json:"syncOptions"`
+		} `json:"syncPolicy"`
+	} `json:"spec"`
+}
+
+// clusterObjects returns the objects among files that are applied to a
+// cluster: the manifests and the ArgoCD Application
+func clusterObjects(files []GeneratedFile) []clusterObject {
+	var objects []clusterObject
+	for _, f := range files {
+		if !IsManifest(f) && !(strings.HasPrefix(f.Path, "argocd/") && filepath.Ext(f.Path) == ".yaml") {
+			continue
+		}
+		for _, doc := range strings.Split(f.Content, "\n---\n") {
+			var obj clusterObject
+			if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || obj.Kind == "" {
+				continue
+			}
+			obj.file = f.Path
+			objects = append(objects, obj)
+		}
+	}
+	return objects
+}
+
+// validateAgainstCluster checks, when opts.Cluster is set, that the target
+// cluster accepts the generated objects: it serves their API versions and
+// has the namespace, classes, and issuers they reference
+func validateAgainstCluster(analysis *types.AppAnalysis, files []GeneratedFile, opts Options, result *ValidationResult) {
+	if opts.Cluster == nil {
+		return
+	}
+	objects := clusterObjects(files)
+	validateAPIVersions(objects, opts.Cluster, result)
+	validateNamespaceExists(objects, opts, result)
+	validateIngressClasses(objects, opts.Cluster, result)
+	validateStorageClasses(objects, opts.Cluster, result)
+	validateClusterIssuer(analysis, opts, result)
+}
+
+// validateAPIVersions checks the cluster serves the API version of every
+// object, each missing version reported once
+func validateAPIVersions(objects []clusterObject, cluster *ClusterState, result *ValidationResult) {
+	reported := map[string]bool{}
+	for _, obj := range objects {
+		if obj.APIVersion == "" || reported[obj.APIVersion] || slices.Contains(cluster.APIVersions, obj.APIVersion) {
+			continue
+		}
+		reported[obj.APIVersion] = true
+		group, _, _ := strings.Cut(obj.APIVersion, "/")
+		hint, ok := apiVersionHints[obj.APIVersion]
+		if !ok {
+			hint, ok = apiVersionHints[group]
+		}
+		if !ok {
+			hint = "Install the CRDs that provide it, or upgrade the cluster"
+		}
+		// The Application is often applied to a separate ArgoCD cluster
+		severity := SeverityError
+		if group == "argoproj.io" {
+			severity = SeverityWarning
+		}
+		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleAPIVersion,
+			Severity:   severity,
+			Category:   "cluster",
+			File:       obj.file,
+			Message:    fmt.Sprintf("The cluster does not serve %s, which %s %s uses", obj.APIVersion, obj.Kind, obj.Metadata.Name),
+			Suggestion: hint,
+		})
+	}
+}
+
+// validateNamespaceExists checks the app's namespace exists, unless the
+// ArgoCD Application creates it
+func validateNamespaceExists(objects []clusterObject, opts Options, result *ValidationResult) {
+	if opts.Cluster.NamespaceExists || opts.Namespace == "" {
+		return
+	}
+	for _, obj := range objects {
+		if obj.Kind == "Application" && slices.Contains(obj.Spec.SyncPolicy.SyncOptions, "CreateNamespace=true") {
+			result.Issues = append(result.Issues, ValidationIssue{
+				Rule:     RuleNamespaceCreated,
+				Severity: SeverityInfo,
+				Category: "cluster",
+				File:     obj.file,
+				Message:  fmt.Sprintf("Namespace %s does not exist yet; ArgoCD creates it on the first sync", opts.Namespace),
+			})
+			return
+		}
+	}
+	result.Issues = append(result.Issues, ValidationIssue{
+		Rule:       RuleNamespaceMissing,
+		Severity:   SeverityError,
+		Category:   "cluster",
+		Message:    fmt.Sprintf("Namespace %s does not exist in the cluster", opts.Namespace),
+		Suggestion: fmt.Sprintf("Create it with 'kubectl create namespace %s', or generate with --namespace set to an existing one", opts.Namespace),
+	})
+}
+
+// validateIngressClasses checks the class of every Ingress exists
+func validateIngressClasses(objects []clusterObject, cluster *ClusterState, result *ValidationResult) {
+	for _, obj := range objects {
+		if obj.Kind != "Ingress" {
+			continue
+		}
+		class := obj.Spec.IngressClassName
+		switch {
+		case class != "" && !slices.Contains(cluster.IngressClasses, class):
+			result.Issues = append(result.Issues, ValidationIssue{
+				Rule:       RuleIngressClassMissing,
+				Severity:   SeverityError,
+				Category:   "cluster",
+				File:       obj.file,
+				Message:    fmt.Sprintf("IngressClass %s does not exist in the cluster", class),
+				Suggestion: fmt.Sprintf("Set ingress.class to one that exists (%s), or install its controller", nameList(cluster.IngressClasses)),
+			})
+		case class == "" && len(cluster.IngressClasses) == 0:
+			result.Issues = append(result.Issues, ValidationIssue{
+				Rule:       RuleNoIngressClass,
+				Severity:   SeverityWarning,
+				Category:   "cluster",
+				File:       obj.file,
+				Message:    "The cluster has no IngressClass, so no controller may serve the Ingress",
+				Suggestion: "Install an ingress controller such as ingress-nginx",
+			})
+		}
+	}
+}
+
+// validateStorageClasses checks the storage class of every claim exists, or
+// that there is a default one for claims without a class
+func validateStorageClasses(objects []clusterObject, cluster *ClusterState, result *ValidationResult) {
+	for _, obj := range objects {
+		var classes []*string
+		switch obj.Kind {
+		case "PersistentVolumeClaim":
+			classes = append(classes, obj.Spec.StorageClassName)
+		case "StatefulSet":
+			for _, t := range obj.Spec.VolumeClaimTemplates {
+				classes = append(classes, t.Spec.StorageClassName)
+			}
+		}
+		for _, class := range classes {
+			switch {
+			case class == nil && cluster.DefaultStorageClass == "":
+				result.Issues = append(result.Issues, ValidationIssue{
+					Rule:       RuleNoDefaultStorage,
+					Severity:   SeverityWarning,
+					Category:   "cluster",
+					File:       obj.file,
+					Message:    fmt.Sprintf("%s %s claims storage without a class, and the cluster has no default StorageClass", obj.Kind, obj.Metadata.Name),
+					Suggestion: fmt.Sprintf("Set storageClassName to one that exists (%s)", nameList(cluster.StorageClasses)),
+				})
+			case class != nil && *class != "" && !slices.Contains(cluster.StorageClasses, *class):
+				result.Issues = append(result.Issues, ValidationIssue{
+					Rule:       RuleStorageClassMissing,
+					Severity:   SeverityError,
+					Category:   "cluster",
+					File:       obj.file,
+					Message:    fmt.Sprintf("StorageClass %s of %s %s does not exist in the cluster", *class, obj.Kind, obj.Metadata.Name),
+					Suggestion: fmt.Sprintf("Use one that exists (%s)", nameList(cluster.StorageClasses)),
+				})
+			}
+		}
+	}
+}
+
+// validateClusterIssuer checks the ClusterIssuer the ingress's certificate is
+// requested from exists; cert-manager leaves the certificate pending otherwise
+func validateClusterIssuer(analysis *types.AppAnalysis, opts Options, result *ValidationResult) {
+	issuer := opts.Config.Ingress.TLS.ClusterIssuer
+	if !hasIngress(analysis) || !ingressTLSEnabled(analysis, opts.Config) || issuer == "" {
+		return
+	}
+	switch {
+	case !opts.Cluster.CertManager:
+		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleIngressCertManager,
+			Severity:   SeverityError,
+			Category:   "ingress",
+			File:       "ingress.yaml",
+			Message:    fmt.Sprintf("TLS certificates come from cluster issuer %s, but cert-manager is not installed in the cluster", issuer),
+			Suggestion: "Install cert-manager, or clear ingress.tls.cluster_issuer and provide the TLS secret another way",
+		})
+	case !slices.Contains(opts.Cluster.ClusterIssuers, issuer):
+		result.Issues = append(result.Issues, ValidationIssue{
+			Rule:       RuleIngressClusterIssuer,
+			Severity:   SeverityError,
+			Category:   "ingress",
+			File:       "ingress.yaml",
+			Message:    fmt.Sprintf("ClusterIssuer %s does not exist in the cluster", issuer),
+			Suggestion: fmt.Sprintf("Create it, or set ingress.tls.cluster_issuer to one that exists (%s)", nameList(opts.Cluster.ClusterIssuers)),
+		})
+	}
+}
+
+// nameList lists names for a suggestion
+func nameList(names []string) string {
+	if len(names) == 0 {
+		return "there are none"
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestValidateAgainstCluster(t *testing.T) {
+	analysis := &types.AppAnalysis{
+		Name:      "orders",
+		Ports:     []types.Port{{Port: 8080}},
+		AppConfig: &types.AppConfigContext{Ingress: &types.IngressContext{Enabled: true, Host: "orders.example.com"}},
+		Scaling:   &types.ScalingConfig{MinReplicas: 2, MaxReplicas: 5, TargetCPU: 70},
+	}
+	cfg := config.Default()
+	cfg.Ingress.Class = "nginx"
+	files, err := Generate(context.Background(), analysis, Options{Namespace: "shop", Config: cfg, SkipCI: true, SkipPersona: true, SkipReadme: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, GeneratedFile{Path: "pvc.yaml", Content: "apiVersion: v1\nkind: PersistentVolumeClaim\nmetadata:\n  name: orders-data\nspec:\n  storageClassName: fast\n"})
+	full := ClusterState{
+		APIVersions:         []string{"v1", "apps/v1", "autoscaling/v2", "networking.k8s.io/v1", "policy/v1", "argoproj.io/v1alpha1"},
+		NamespaceExists:     true,
+		StorageClasses:      []string{"fast", "standard"},
+		DefaultStorageClass: "standard",
+		IngressClasses:      []string{"nginx"},
+		CertManager:         true,
+	}
+
+	tests := []struct {
+		name   string
+		change func(*ClusterState)
+		want   []string
+	}{
+		{name: "accepted", change: func(*ClusterState) {}},
+		{name: "old autoscaling", change: func(c *ClusterState) {
+			c.APIVersions = []string{"v1", "apps/v1", "networking.k8s.io/v1", "policy/v1", "argoproj.io/v1alpha1"}
+		}, want: []string{"error: The cluster does not serve autoscaling/v2"}},
+		{name: "no argocd", change: func(c *ClusterState) { c.APIVersions = c.APIVersions[:5] }, want: []string{"warning: The cluster does not serve argoproj.io/v1alpha1"}},
+		{name: "argocd creates the namespace", change: func(c *ClusterState) { c.NamespaceExists = false }, want: []string{"info: Namespace shop does not exist yet"}},
+		{name: "unknown ingress class", change: func(c *ClusterState) { c.IngressClasses = []string{"traefik"} }, want: []string{"error: IngressClass nginx does not exist"}},
+		{name: "unknown storage class", change: func(c *ClusterState) { c.StorageClasses = []string{"standard"} }, want: []string{"error: StorageClass fast of PersistentVolumeClaim orders-data"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := full
+			cluster.APIVersions = append([]string(nil), full.APIVersions...)
+			tt.change(&cluster)
+			result := &ValidationResult{}
+			validateAgainstCluster(analysis, files, Options{Namespace: "shop", Config: cfg, Cluster: &cluster}, result)
+			var got []string
+			for _, issue := range result.Issues {
+				got = append(got, string(issue.Severity)+": "+issue.Message)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("issues = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if !strings.HasPrefix(got[i], tt.want[i]) {
+					t.Errorf("issue %q, want %q...", got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateStorageClassDefault(t *testing.T) {
+	files := []GeneratedFile{{Path: "statefulset.yaml", Content: "apiVersion: apps/v1\nkind: StatefulSet\nmetadata:\n  name: db\nspec:\n  volumeClaimTemplates:\n  - spec:\n      accessModes: [ReadWriteOnce]\n"}}
+	for _, def := range []string{"", "standard"} {
+		result := &ValidationResult{}
+		validateStorageClasses(clusterObjects(files), &ClusterState{StorageClasses: []string{"standard"}, DefaultStorageClass: def}, result)
+		if want := def == ""; (len(result.Issues) == 1) != want {
+			t.Errorf("default %q: issues %+v", def, result.Issues)
+		}
+	}
+}
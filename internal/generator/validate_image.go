@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// imageCheckTimeout bounds the registry request for each image
+const imageCheckTimeout = 10 * time.Second
+
+// defaultImageExistsEnvironments are where a missing image is an error
+// unless validation.image_exists.environments is set
+var defaultImageExistsEnvironments = []string{"production"}
+
+var imageLinePattern = regexp.MustCompile(`(?m)^\s*(?:- )?image:\s*["']?([^"'\s]+)`)
+
+// manifestImages returns the images the manifests among files reference in
+// registry, sorted. ok is false when there are no YAML manifests, e.g. for a
+// program --format.
+func manifestImages(files []GeneratedFile, registry string) (images []string, ok bool) {
+	seen := map[string]bool{}
+	for _, f := range files {
+		if !IsManifest(f) {
+			continue
+		}
+		ok = true
+		for _, m := range imageLinePattern.FindAllStringSubmatch(f.Content, -1) {
+			if strings.HasPrefix(m[1], registry+"/") {
+				seen[m[1]] = true
+			}
+		}
+	}
+	for image := range seen {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	return images, ok
+}
+
+// validateImageExists checks, when validation.image_exists is enabled, that
+// the images in ci.registry the manifests reference were pushed. A missing
+// image is an error in the production environments, where the rollout would
+// stall on ImagePullBackOff, and a warning elsewhere, where the image is
+// often pushed after the manifests are generated.
+func validateImageExists(ctx context.Context, analysis *types.AppAnalysis, files []GeneratedFile, opts Options, result *ValidationResult) {
+	check := opts.Config.Validation.ImageExists
+	registry := opts.Config.CI.Registry
+	if !check.Enabled || registry == "" {
+		return
+	}
+	images, ok := manifestImages(files, registry)
+	if !ok {
+		images = []string{appImage(analysis, opts.Config)}
+	}
+	environments := check.Environments
+	if len(environments) == 0 {
+		environments = defaultImageExistsEnvironments
+	}
+	severity := SeverityWarning
+	if slices.Contains(environments, analysis.Environment) {
+		severity = SeverityError
+	}
+
+	for _, image := range images {
+		err := headImage(ctx, image, registryAuth(check))
+		var terr *transport.Error
+		switch {
+		case err == nil:
+			continue
+		case errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound:
+			result.Issues = append(result.Issues, ValidationIssue{
+				Rule:       RuleImageMissing,
+				Severity:   severity,
+				Category:   "image",
+				File:       "deployment.yaml",
+				Message:    fmt.Sprintf("Image %s does not exist in the registry", image),
+				Suggestion: "Push the image (or let CI build it) before deploying, or point the manifests at a tag that was pushed",
+			})
+		default:
+			result.Issues = append(result.Issues, ValidationIssue{
+				Rule:       RuleImageUnchecked,
+				Severity:   SeverityWarning,
+				Category:   "image",
+				File:       "deployment.yaml",
+				Message:    fmt.Sprintf("Could not check that image %s exists: %v", image, err),
+				Suggestion: "Check the registry is reachable and validation.image_exists.username_env/password_env or your Docker login grant pull access",
+			})
+		}
+	}
+}
+
+// registryAuth returns the credentials from the environment variables named
+// in check, or the Docker config's when they are unset
+func registryAuth(check config.ImageExistsConfig) authn.Keychain {
+	username := os.Getenv(check.UsernameEnv)
+	password := os.Getenv(check.PasswordEnv)
+	if check.UsernameEnv == "" || username == "" && password == "" {
+		return authn.DefaultKeychain
+	}
+	return staticKeychain{authn.FromConfig(authn.AuthConfig{Username: username, Password: password})}
+}
+
+// staticKeychain uses the same credentials for every registry
+type staticKeychain struct {
+	auth authn.Authenticator
+}
+
+func (k staticKeychain) Resolve(authn.Resource) (authn.Authenticator, error) {
+	return k.auth, nil
+}
+
+// headImage requests the manifest of image without downloading it
+func headImage(ctx context.Context, image string, keychain authn.Keychain) error {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return fmt.Errorf("invalid image reference: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, imageCheckTimeout)
+	defer cancel()
+	_, err = remote.Head(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain))
+	return err
+}
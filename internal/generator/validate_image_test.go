@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestValidateImageExists(t *testing.T) {
+	server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	img, err := random.Image(64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := name.ParseReference(host + "/orders:1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatalf("pushing the test image: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		image       string
+		environment string
+		enabled     bool
+		want        ValidationSeverity
+	}{
+		{name: "pushed", image: host + "/orders:1.0", enabled: true},
+		{name: "missing in production", image: host + "/orders:2.0", environment: "production", enabled: true, want: SeverityError},
+		{name: "missing in staging", image: host + "/orders:2.0", environment: "staging", enabled: true, want: SeverityWarning},
+		{name: "disabled", image: host + "/orders:2.0", environment: "production"},
+		{name: "other registry", image: "docker.io/library/nginx:1.27", environment: "production", enabled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.CI.Registry = host
+			cfg.Validation.ImageExists.Enabled = tt.enabled
+			analysis := &types.AppAnalysis{Name: "orders", Environment: tt.environment}
+			files := []GeneratedFile{{Path: "deployment.yaml", Content: "kind: Deployment\nspec:\n  containers:\n  - image: " + tt.image + "\n"}}
+
+			result := &ValidationResult{}
+			validateImageExists(context.Background(), analysis, files, Options{Config: cfg}, result)
+			var got ValidationSeverity
+			for _, issue := range result.Issues {
+				if issue.Category == "image" {
+					got = issue.Severity
+					if !strings.Contains(issue.Message, tt.image) {
+						t.Errorf("issue %q does not name the image", issue.Message)
+					}
+				}
+			}
+			if got != tt.want {
+				t.Errorf("severity = %q, want %q (issues %+v)", got, tt.want, result.Issues)
+			}
+		})
+	}
+}
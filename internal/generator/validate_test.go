@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestValidationResultFails(t *testing.T) {
+	warning := &ValidationResult{Issues: []ValidationIssue{{Severity: SeverityInfo}, {Severity: SeverityWarning}}}
+	failing := &ValidationResult{Issues: []ValidationIssue{{Severity: SeverityError}}}
+	tests := []struct {
+		result *ValidationResult
+		failOn string
+		want   bool
+	}{
+		{failing, "", false},
+		{failing, "error", true},
+		{failing, "warning", true},
+		{warning, "error", false},
+		{warning, "warning", true},
+		{&ValidationResult{}, "warning", false},
+	}
+	for _, tt := range tests {
+		if got := tt.result.Fails(tt.failOn); got != tt.want {
+			t.Errorf("Fails(%q) on %+v = %v, want %v", tt.failOn, tt.result.Issues, got, tt.want)
+		}
+	}
+}
+
+func TestValidateListenPort(t *testing.T) {
+	code := &types.CodeAnalysis{ListenPort: 8081, ListenPortSource: "main.go"}
+	tests := []struct {
+		name     string
+		analysis *types.AppAnalysis
+		want     string
+	}{
+		{"matches", &types.AppAnalysis{Code: code, Ports: []types.Port{{Port: 8081}}, Dockerfile: &types.DockerfileAnalysis{Ports: []int{8081}}}, ""},
+		{"not exposed", &types.AppAnalysis{Code: code, Ports: []types.Port{{Port: 8081}}, Dockerfile: &types.DockerfileAnalysis{Ports: []int{8080}}},
+			"The app listens on port 8081 (main.go), but the Dockerfile EXPOSEs 8080"},
+		{"not a container port", &types.AppAnalysis{Code: code, Ports: []types.Port{{Port: 8080}}},
+			"The app listens on port 8081 (main.go), which is not a container port"},
+		{"no listen port", &types.AppAnalysis{Code: &types.CodeAnalysis{}, Ports: []types.Port{{Port: 8080}}}, ""},
+	}
+	for _, tt := range tests {
+		result := &ValidationResult{}
+		validateListenPort(tt.analysis, result)
+		var got string
+		if len(result.Issues) > 0 {
+			got = result.Issues[0].Message
+			if result.Issues[0].Rule != RuleListenPort {
+				t.Errorf("%s: rule %s", tt.name, result.Issues[0].Rule)
+			}
+		}
+		if got != tt.want || len(result.Issues) > 1 {
+			t.Errorf("%s: issues %+v, want %q", tt.name, result.Issues, tt.want)
+		}
+	}
+}
+
+func TestValidateSchedules(t *testing.T) {
+	analysis := &types.AppAnalysis{Schedules: []types.ScheduleHint{
+		{Name: "backup", Schedule: "0 2 * * *", Command: []string{"/bin/sh", "-c", "./backup.sh"}, Source: "crontab:3"},
+		{Name: "report", Schedule: "@daily", Source: "app.py"},
+	}}
+	result := &ValidationResult{}
+	validateSchedules(analysis, result)
+	if len(result.Issues) != 2 {
+		t.Fatalf("validateSchedules() issues = %+v, want 2", result.Issues)
+	}
+	issue := result.Issues[0]
+	if issue.Rule != RuleScheduleUnconfigured || issue.Severity != SeverityInfo {
+		t.Errorf("issue = %+v", issue)
+	}
+	if !strings.Contains(issue.Message, "crontab:3") || !strings.Contains(issue.Suggestion, `command ["/bin/sh", "-c", "./backup.sh"]`) {
+		t.Errorf("issue = %+v", issue)
+	}
+	if !strings.Contains(result.Issues[1].Suggestion, "<a command that runs the task once>") {
+		t.Errorf("issue = %+v", result.Issues[1])
+	}
+
+	analysis.AppConfig = &types.AppConfigContext{Jobs: []types.JobContext{
+		{Name: "backup", Command: []string{"./backup.sh"}, Schedule: "0 2 * * *"},
+		{Name: "report", Command: []string{"./report"}, Schedule: "@daily"},
+	}}
+	result = &ValidationResult{}
+	validateSchedules(analysis, result)
+	if len(result.Issues) != 0 {
+		t.Errorf("validateSchedules() issues = %+v, want none once jobs run them", result.Issues)
+	}
+}
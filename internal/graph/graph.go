@@ -0,0 +1,292 @@
+// Package graph builds the service dependency graph of the apps in a
+// workspace or on a cluster.
+package graph
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/analyzer"
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// Node is an app, or a dependency that is not an onboarded app
+type Node struct {
+	Name string `json:"name"`
+	// Type is the app type (api, web, ...) or the dependency type
+	// (database, cache, ...)
+	Type string `json:"type,omitempty"`
+	Team string `json:"team,omitempty"`
+	// App is true for onboarded apps, false for dependencies only
+	App bool `json:"app"`
+	// Source is the .dorgu.yaml, compose file, or persona the node came from
+	Source string `json:"source,omitempty"`
+}
+
+// Edge says From depends on To
+type Edge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Required bool   `json:"required"`
+}
+
+// Graph is a service dependency graph
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// skipDirs are never searched for apps
+var skipDirs = map[string]bool{".git": true, "node_modules": true, "vendor": true, ".venv": true}
+
+// composeFiles are the docker-compose file names
+var composeFiles = map[string]bool{"docker-compose.yml": true, "docker-compose.yaml": true, "compose.yml": true, "compose.yaml": true}
+
+// builder collects nodes and edges, merging repeats
+type builder struct {
+	nodes map[string]*Node
+	edges map[[2]string]*Edge
+}
+
+func newBuilder() *builder {
+	return &builder{nodes: map[string]*Node{}, edges: map[[2]string]*Edge{}}
+}
+
+// node adds n, filling in what an earlier mention left out. Apps win over
+// dependencies of the same name.
+func (b *builder) node(n Node) {
+	n.Name = generator.ResourceName(n.Name)
+	existing, ok := b.nodes[n.Name]
+	if !ok {
+		b.nodes[n.Name] = &n
+		return
+	}
+	if n.App && !existing.App {
+		existing.App, existing.Source = true, n.Source
+		if n.Type != "" {
+			existing.Type = n.Type
+		}
+	}
+	if existing.Type == "" {
+		existing.Type = n.Type
+	}
+	if existing.Team == "" {
+		existing.Team = n.Team
+	}
+}
+
+// edge adds a dependency; required wins when it is declared twice
+func (b *builder) edge(from, to, toType string, required bool) {
+	from, to = generator.ResourceName(from), generator.ResourceName(to)
+	b.node(Node{Name: to, Type: toType})
+	key := [2]string{from, to}
+	if e, ok := b.edges[key]; ok {
+		e.Required = e.Required || required
+		return
+	}
+	b.edges[key] = &Edge{From: from, To: to, Required: required}
+}
+
+func (b *builder) graph() *Graph {
+	g := &Graph{Nodes: []Node{}, Edges: []Edge{}}
+	for _, n := range b.nodes {
+		g.Nodes = append(g.Nodes, *n)
+	}
+	for _, e := range b.edges {
+		g.Edges = append(g.Edges, *e)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].Name < g.Nodes[j].Name })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g
+}
+
+// FromWorkspace builds the graph of the apps under root: the dependencies in
+// each app .dorgu.yaml, and the depends_on of every docker-compose service
+func FromWorkspace(root string) (*Graph, error) {
+	b := newBuilder()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && skipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		switch {
+		case d.Name() == ".dorgu.yaml":
+			return addAppConfig(b, filepath.Dir(path), rel)
+		case composeFiles[d.Name()]:
+			compose, err := analyzer.ParseComposeFile(path)
+			if err != nil {
+				return fmt.Errorf("%s: %w", rel, err)
+			}
+			for _, svc := range compose.Services {
+				b.node(Node{Name: svc.Name, Source: rel})
+				for _, dep := range svc.DependsOn {
+					b.edge(svc.Name, dep, "", true)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b.graph(), nil
+}
+
+// addAppConfig adds the app in dir, when its .dorgu.yaml is an app config
+func addAppConfig(b *builder, dir, source string) error {
+	app, err := config.LoadAppConfig(dir)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	// Workspace config has no app section or dependencies
+	if app == nil || (app.App.Name == "" && len(app.Dependencies) == 0) {
+		return nil
+	}
+	name := app.App.Name
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+	b.node(Node{Name: name, Type: app.App.Type, Team: app.App.Team, App: true, Source: source})
+	for _, dep := range app.Dependencies {
+		b.edge(name, dep.Name, dep.Type, dep.Required)
+	}
+	return nil
+}
+
+// FromPersonas builds the graph of the dependencies in cluster personas
+func FromPersonas(personas []types.ApplicationPersona) *Graph {
+	b := newBuilder()
+	for _, p := range personas {
+		n := Node{Name: p.Spec.Name, Type: p.Spec.Type, App: true, Source: p.Metadata.Namespace + "/" + p.Metadata.Name}
+		if n.Name == "" {
+			n.Name = p.Metadata.Name
+		}
+		if p.Spec.Ownership != nil {
+			n.Team = p.Spec.Ownership.Team
+		}
+		b.node(n)
+		for _, dep := range p.Spec.Dependencies {
+			b.edge(n.Name, dep.Name, dep.Type, dep.Required)
+		}
+	}
+	return b.graph()
+}
+
+// Has reports whether the graph has a node named name
+func (g *Graph) Has(name string) bool {
+	for _, n := range g.Nodes {
+		if n.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// BlastRadius returns the subgraph of name and everything that depends on
+// it, directly or transitively: what breaks when name is down
+func (g *Graph) BlastRadius(name string) *Graph {
+	affected := map[string]bool{name: true}
+	for changed := true; changed; {
+		changed = false
+		for _, e := range g.Edges {
+			if affected[e.To] && !affected[e.From] {
+				affected[e.From] = true
+				changed = true
+			}
+		}
+	}
+	sub := &Graph{Nodes: []Node{}, Edges: []Edge{}}
+	for _, n := range g.Nodes {
+		if affected[n.Name] {
+			sub.Nodes = append(sub.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		if affected[e.From] && affected[e.To] {
+			sub.Edges = append(sub.Edges, e)
+		}
+	}
+	return sub
+}
+
+// DOT renders the graph in Graphviz DOT. Apps are boxes, other dependencies
+// ellipses; optional dependencies are dashed.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n  rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		shape := "ellipse"
+		if n.App {
+			shape = "box"
+		}
+		fmt.Fprintf(&b, "  %q [label=%q, shape=%s];\n", n.Name, label(n), shape)
+	}
+	for _, e := range g.Edges {
+		style := ""
+		if !e.Required {
+			style = " [style=dashed]"
+		}
+		fmt.Fprintf(&b, "  %q -> %q%s;\n", e.From, e.To, style)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart. Apps are boxes, other
+// dependencies rounded; optional dependencies are dotted.
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	ids := map[string]string{}
+	for i, n := range g.Nodes {
+		ids[n.Name] = fmt.Sprintf("n%d", i)
+		start, end := "(", ")"
+		if n.App {
+			start, end = "[", "]"
+		}
+		fmt.Fprintf(&b, "    %s%s\"%s\"%s\n", ids[n.Name], start, strings.ReplaceAll(label(n), `"`, "#quot;"), end)
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if !e.Required {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "    %s %s %s\n", ids[e.From], arrow, ids[e.To])
+	}
+	return b.String()
+}
+
+// label is a node's name with its type
+func label(n Node) string {
+	if n.Type == "" {
+		return n.Name
+	}
+	return n.Name + " (" + n.Type + ")"
+}
+
+// Dependents returns the apps that depend on name directly
+func (g *Graph) Dependents(name string) []string {
+	var from []string
+	for _, e := range g.Edges {
+		if e.To == name {
+			from = append(from, e.From)
+		}
+	}
+	return from
+}
@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFromWorkspace(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".dorgu.yaml"), "org:\n  name: acme\n")
+	writeFile(t, filepath.Join(root, "orders", ".dorgu.yaml"), "app:\n  name: orders\n  type: api\n  team: commerce\ndependencies:\n  - name: postgres\n    type: database\n    required: true\n  - name: billing\n    type: service\n")
+	writeFile(t, filepath.Join(root, "billing", ".dorgu.yaml"), "app:\n  name: billing\ndependencies:\n  - name: postgres\n    type: database\n    required: true\n")
+	writeFile(t, filepath.Join(root, "web", "docker-compose.yml"), "services:\n  web:\n    build: .\n    depends_on: [orders]\n")
+	writeFile(t, filepath.Join(root, "node_modules", "x", ".dorgu.yaml"), "app:\n  name: ignored\n")
+
+	g, err := FromWorkspace(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, n := range g.Nodes {
+		names = append(names, n.Name)
+	}
+	if want := []string{"billing", "orders", "postgres", "web"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("nodes = %v, want %v", names, want)
+	}
+	wantEdges := []Edge{
+		{From: "billing", To: "postgres", Required: true},
+		{From: "orders", To: "billing"},
+		{From: "orders", To: "postgres", Required: true},
+		{From: "web", To: "orders", Required: true},
+	}
+	if !reflect.DeepEqual(g.Edges, wantEdges) {
+		t.Errorf("edges = %+v, want %+v", g.Edges, wantEdges)
+	}
+	if !g.Nodes[1].App || g.Nodes[1].Team != "commerce" || g.Nodes[2].App {
+		t.Errorf("nodes = %+v", g.Nodes)
+	}
+
+	radius := g.BlastRadius("billing")
+	var affected []string
+	for _, n := range radius.Nodes {
+		affected = append(affected, n.Name)
+	}
+	if want := []string{"billing", "orders", "web"}; !reflect.DeepEqual(affected, want) {
+		t.Errorf("BlastRadius(billing) = %v, want %v", affected, want)
+	}
+}
+
+func TestFromPersonas(t *testing.T) {
+	personas := []types.ApplicationPersona{{
+		Metadata: types.PersonaMetadata{Name: "orders", Namespace: "shop"},
+		Spec: types.PersonaSpec{
+			Name: "orders",
+			Type: "api",
+			Dependencies: []types.PersonaDependency{
+				{Name: "redis", Type: "cache"},
+			},
+		},
+	}}
+	g := FromPersonas(personas)
+	if len(g.Nodes) != 2 || g.Nodes[0].Source != "shop/orders" {
+		t.Errorf("nodes = %+v", g.Nodes)
+	}
+
+	dot := g.DOT()
+	for _, want := range []string{`"orders" [label="orders (api)", shape=box];`, `"redis" [label="redis (cache)", shape=ellipse];`, `"orders" -> "redis" [style=dashed];`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT missing %q:\n%s", want, dot)
+		}
+	}
+	mermaid := g.Mermaid()
+	for _, want := range []string{`n0["orders (api)"]`, `n1("redis (cache)")`, "n0 -.-> n1"} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, mermaid)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package kube
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// api is the Kubernetes API connection of a client, set up on first use
+type api struct {
+	once      sync.Once
+	config    *rest.Config
+	dynamic   dynamic.Interface
+	clientset kubernetes.Interface
+	err       error
+}
+
+// NewAPIClient returns a client that talks to the API server directly, for
+// the kubeconfig context name or the current one when it is empty. Its
+// API-backed methods (persona reads and deletes, operator discovery, and
+// port-forwards) work without kubectl; the others
+// return ErrKubectlNotFound when kubectl is not on PATH.
+func NewAPIClient(name string) (*Client, error) {
+	c, err := NewClientForContext(name)
+	if err != nil {
+		c = &Client{context: name}
+	}
+	if _, err := c.restConfig(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// restConfig loads the client's kubeconfig context the way kubectl does:
+// $KUBECONFIG or ~/.kube/config, with its auth plugins, or the in-cluster
+// service account when there is no kubeconfig
+func (c *Client) restConfig() (*rest.Config, error) {
+	c.api.once.Do(func() {
+		rules := clientcmd.NewDefaultClientConfigLoadingRules()
+		overrides := &clientcmd.ConfigOverrides{CurrentContext: c.context}
+		config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+		if err != nil {
+			c.api.err = fmt.Errorf("failed to load kubeconfig: %w", err)
+			return
+		}
+		c.api.config = config
+		if c.api.dynamic, c.api.err = dynamic.NewForConfig(config); c.api.err != nil {
+			return
+		}
+		c.api.clientset, c.api.err = kubernetes.NewForConfig(config)
+	})
+	return c.api.config, c.api.err
+}
+
+// clientset returns the typed client for the built-in resources
+func (c *Client) clientset() (kubernetes.Interface, error) {
+	if _, err := c.restConfig(); err != nil {
+		return nil, err
+	}
+	return c.api.clientset, nil
+}
+
+// resource returns the dynamic client for resource in namespace, or for all
+// namespaces when it is empty
+func (c *Client) resource(gvr schema.GroupVersionResource, namespace string) (dynamic.ResourceInterface, error) {
+	if _, err := c.restConfig(); err != nil {
+		return nil, err
+	}
+	if namespace == "" {
+		return c.api.dynamic.Resource(gvr), nil
+	}
+	return c.api.dynamic.Resource(gvr).Namespace(namespace), nil
+}
+
+// decode converts an API object, e.g. an unstructured.Unstructured, into out
+func decode(obj json.Marshaler, out interface{}) error {
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode %T: %w", out, err)
+	}
+	return nil
+}
+
+// classifyAPIError maps API errors to ErrNotFound and ErrCRDNotInstalled
+// like classifyError does for kubectl. The API server answers requests for an
+// unknown resource type with a bare 404, which client-go reports as a
+// NotFound without the object's "not found" message.
+func classifyAPIError(err error) error {
+	switch {
+	case apierrors.IsNotFound(err) && strings.Contains(err.Error(), "could not find the requested resource"):
+		return fmt.Errorf("%w: %v", ErrCRDNotInstalled, err)
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return err
+}
@@ -0,0 +1,29 @@
+package kube
+
+import (
+	"context"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// ClusterPersonaResource is the kubectl resource name for ClusterPersonas
+const ClusterPersonaResource = "clusterpersonas.dorgu.io"
+
+// ListClusterPersonas lists all ClusterPersonas
+func (c *Client) ListClusterPersonas(ctx context.Context) ([]types.ClusterPersona, error) {
+	var list struct {
+		Items []types.ClusterPersona `json:"items"`
+	}
+	if err := c.GetJSON(ctx, &list, ClusterPersonaResource); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// GetClusterPersona fetches a single ClusterPersona including its status
+func (c *Client) GetClusterPersona(ctx context.Context, name string) (*types.ClusterPersona, error) {
+	var persona types.ClusterPersona
+	if err := c.GetJSON(ctx, &persona, ClusterPersonaResource, name); err != nil {
+		return nil, err
+	}
+	return &persona, nil
+}
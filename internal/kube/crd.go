@@ -0,0 +1,96 @@
+package kube
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed crds/*.yaml
+var crdFS embed.FS
+
+// CRD is a CustomResourceDefinition bundled with the CLI
+type CRD struct {
+	// Name is the CRD object name, e.g. applicationpersonas.dorgu.io
+	Name     string
+	Manifest []byte
+}
+
+// CRDStatus describes a CRD as installed on the cluster
+type CRDStatus struct {
+	Name           string
+	Installed      bool
+	Established    bool
+	StoredVersions []string
+}
+
+// BundledCRDs returns the dorgu.io CRDs embedded in the binary, sorted by name
+func BundledCRDs() ([]CRD, error) {
+	entries, err := crdFS.ReadDir("crds")
+	if err != nil {
+		return nil, err
+	}
+
+	var crds []CRD
+	for _, entry := range entries {
+		data, err := crdFS.ReadFile(path.Join("crds", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		crds = append(crds, CRD{
+			Name:     strings.TrimSuffix(entry.Name(), ".yaml"),
+			Manifest: data,
+		})
+	}
+	sort.Slice(crds, func(i, j int) bool { return crds[i].Name < crds[j].Name })
+	return crds, nil
+}
+
+// InstallCRD applies a bundled CRD and waits for it to be established
+func (c *Client) InstallCRD(ctx context.Context, crd CRD) error {
+	if _, err := c.RunWithInput(ctx, crd.Manifest, "apply", "-f", "-"); err != nil {
+		return err
+	}
+	_, err := c.Run(ctx, "wait", "--for=condition=Established", "--timeout=60s", "crd/"+crd.Name)
+	return err
+}
+
+// UninstallCRD deletes a CRD. All custom resources of that kind are deleted
+// by the API server along with it.
+func (c *Client) UninstallCRD(ctx context.Context, name string) error {
+	_, err := c.Run(ctx, "delete", "crd", name)
+	return err
+}
+
+// GetCRDStatus reports whether a CRD is installed and established
+func (c *Client) GetCRDStatus(ctx context.Context, name string) (*CRDStatus, error) {
+	var crd struct {
+		Status struct {
+			Conditions []struct {
+				Type   string `json:"type"`
+				Status string `json:"status"`
+			} `json:"conditions"`
+			StoredVersions []string `json:"storedVersions"`
+		} `json:"status"`
+	}
+
+	status := &CRDStatus{Name: name}
+	if err := c.GetJSON(ctx, &crd, "crd", name); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return status, nil
+		}
+		return nil, err
+	}
+
+	status.Installed = true
+	status.StoredVersions = crd.Status.StoredVersions
+	for _, cond := range crd.Status.Conditions {
+		if cond.Type == "Established" && cond.Status == "True" {
+			status.Established = true
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,118 @@
+package kube
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldChange is a single difference between two objects, keyed by a
+// dotted field path (e.g. "scaling.minReplicas", "networking.ports[0].port").
+type FieldChange struct {
+	Path string
+	Old  string // empty when the field was added
+	New  string // empty when the field was removed
+}
+
+// Kind describes the change as "added", "removed", or "changed"
+func (c FieldChange) Kind() string {
+	switch {
+	case c.Old == "":
+		return "added"
+	case c.New == "":
+		return "removed"
+	default:
+		return "changed"
+	}
+}
+
+// Diff compares two JSON-serializable objects field by field and returns the
+// changes sorted by path.
+func Diff(oldObj, newObj interface{}) ([]FieldChange, error) {
+	oldFields, err := flattenObject(oldObj)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenObject(newObj)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FieldChange
+	for path, newVal := range newFields {
+		oldVal, ok := oldFields[path]
+		if !ok {
+			changes = append(changes, FieldChange{Path: path, New: newVal})
+		} else if oldVal != newVal {
+			changes = append(changes, FieldChange{Path: path, Old: oldVal, New: newVal})
+		}
+	}
+	for path, oldVal := range oldFields {
+		if _, ok := newFields[path]; !ok {
+			changes = append(changes, FieldChange{Path: path, Old: oldVal})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// FormatDiff renders changes one per line with +/-/~ markers
+func FormatDiff(changes []FieldChange) string {
+	var sb strings.Builder
+	for _, c := range changes {
+		switch c.Kind() {
+		case "added":
+			sb.WriteString(fmt.Sprintf("  + %s: %s\n", c.Path, c.New))
+		case "removed":
+			sb.WriteString(fmt.Sprintf("  - %s: %s\n", c.Path, c.Old))
+		default:
+			sb.WriteString(fmt.Sprintf("  ~ %s: %s -> %s\n", c.Path, c.Old, c.New))
+		}
+	}
+	return sb.String()
+}
+
+// flattenObject maps every leaf value of obj to its dotted path
+func flattenObject(obj interface{}) (map[string]string, error) {
+	fields := map[string]string{}
+	if obj == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	flatten("", generic, fields)
+	return fields, nil
+}
+
+func flatten(prefix string, v interface{}, out map[string]string) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			path := k
+			if prefix != "" {
+				path = prefix + "." + k
+			}
+			flatten(path, child, out)
+		}
+	case []interface{}:
+		for i, child := range val {
+			flatten(fmt.Sprintf("%s[%d]", prefix, i), child, out)
+		}
+	case nil:
+		// absent and null are equivalent
+	case string:
+		out[prefix] = fmt.Sprintf("%q", val)
+	default:
+		data, _ := json.Marshal(val)
+		out[prefix] = string(data)
+	}
+}
@@ -0,0 +1,64 @@
+package kube
+
+import (
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestDiff(t *testing.T) {
+	oldSpec := types.PersonaSpec{
+		Name: "orders",
+		Type: "api",
+		Tier: "standard",
+		Scaling: &types.PersonaScaling{
+			MinReplicas: 2,
+			MaxReplicas: 10,
+		},
+		Networking: &types.PersonaNetworking{
+			Ports: []types.PersonaPort{{Port: 8080, Protocol: "TCP"}},
+		},
+	}
+	newSpec := types.PersonaSpec{
+		Name: "orders",
+		Type: "api",
+		Tier: "critical",
+		Scaling: &types.PersonaScaling{
+			MinReplicas: 5,
+			MaxReplicas: 10,
+		},
+		Health: &types.PersonaHealth{LivenessPath: "/healthz"},
+	}
+
+	changes, err := Diff(oldSpec, newSpec)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	want := map[string]FieldChange{
+		"tier":                         {Path: "tier", Old: `"standard"`, New: `"critical"`},
+		"scaling.minReplicas":          {Path: "scaling.minReplicas", Old: "2", New: "5"},
+		"health.livenessPath":          {Path: "health.livenessPath", New: `"/healthz"`},
+		"networking.ports[0].port":     {Path: "networking.ports[0].port", Old: "8080"},
+		"networking.ports[0].protocol": {Path: "networking.ports[0].protocol", Old: `"TCP"`},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Diff() returned %d changes, want %d: %+v", len(changes), len(want), changes)
+	}
+	for _, c := range changes {
+		if c != want[c.Path] {
+			t.Errorf("change %s = %+v, want %+v", c.Path, c, want[c.Path])
+		}
+	}
+}
+
+func TestDiffIdentical(t *testing.T) {
+	spec := types.PersonaSpec{Name: "orders", Type: "api"}
+	changes, err := Diff(spec, spec)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Diff() = %+v, want no changes", changes)
+	}
+}
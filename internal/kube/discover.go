@@ -0,0 +1,166 @@
+package kube
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// wellKnownAddons maps deployment names to the add-on they identify
+var wellKnownAddons = map[string]string{
+	"argocd-server":                  "argocd",
+	"ingress-nginx-controller":       "ingress-nginx",
+	"cert-manager":                   "cert-manager",
+	"traefik":                        "traefik",
+	"istiod":                         "istio",
+	"linkerd-destination":            "linkerd",
+	"external-dns":                   "external-dns",
+	"metrics-server":                 "metrics-server",
+	"keda-operator":                  "keda",
+	"kyverno":                        "kyverno",
+	"kyverno-admission-controller":   "kyverno",
+	"gatekeeper-controller-manager":  "gatekeeper",
+	"prometheus-operator":            "prometheus-operator",
+	"kube-prometheus-stack-operator": "prometheus-operator",
+	"external-secrets":               "external-secrets",
+	"sealed-secrets-controller":      "sealed-secrets",
+	"aws-load-balancer-controller":   "aws-load-balancer-controller",
+	"dorgu-operator":                 "dorgu-operator",
+}
+
+// platformLabels are node label prefixes that identify a managed platform
+var platformLabels = []struct {
+	label    string
+	platform string
+}{
+	{"eks.amazonaws.com/", "eks"},
+	{"cloud.google.com/gke-", "gke"},
+	{"kubernetes.azure.com/", "aks"},
+	{"doks.digitalocean.com/", "doks"},
+	{"minikube.k8s.io/", "minikube"},
+	{"node.openshift.io/", "openshift"},
+}
+
+// DiscoverCluster queries the API server for version, node, platform, and
+// add-on facts used to seed a ClusterPersona.
+func (c *Client) DiscoverCluster(ctx context.Context) (*types.ClusterFacts, error) {
+	facts := &types.ClusterFacts{}
+
+	var version struct {
+		ServerVersion struct {
+			GitVersion string `json:"gitVersion"`
+		} `json:"serverVersion"`
+	}
+	if err := c.runJSON(ctx, &version, "version", "-o", "json"); err != nil {
+		return nil, err
+	}
+	facts.KubernetesVersion = version.ServerVersion.GitVersion
+
+	var nodes struct {
+		Items []struct {
+			Metadata struct {
+				Name   string            `json:"name"`
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+			Spec struct {
+				ProviderID string `json:"providerID"`
+			} `json:"spec"`
+			Status struct {
+				NodeInfo struct {
+					KubeletVersion string `json:"kubeletVersion"`
+				} `json:"nodeInfo"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := c.GetJSON(ctx, &nodes, "nodes"); err != nil {
+		return nil, err
+	}
+	facts.NodeCount = len(nodes.Items)
+	for _, node := range nodes.Items {
+		if p := detectPlatform(node.Metadata.Name, node.Metadata.Labels, node.Spec.ProviderID, node.Status.NodeInfo.KubeletVersion); p != "" {
+			facts.Platform = p
+			break
+		}
+	}
+
+	var deployments struct {
+		Items []struct {
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+			Spec struct {
+				Template struct {
+					Spec struct {
+						Containers []struct {
+							Image string `json:"image"`
+						} `json:"containers"`
+					} `json:"spec"`
+				} `json:"template"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	// Add-on detection is best effort; RBAC may forbid listing all namespaces
+	if err := c.GetJSON(ctx, &deployments, "deployments", "--all-namespaces"); err == nil {
+		seen := map[string]bool{}
+		for _, d := range deployments.Items {
+			addon, ok := wellKnownAddons[d.Metadata.Name]
+			if !ok || seen[addon] {
+				continue
+			}
+			seen[addon] = true
+			version := ""
+			if containers := d.Spec.Template.Spec.Containers; len(containers) > 0 {
+				version = imageTag(containers[0].Image)
+			}
+			facts.Addons = append(facts.Addons, types.ClusterAddon{
+				Name:      addon,
+				Namespace: d.Metadata.Namespace,
+				Version:   version,
+			})
+		}
+		sort.Slice(facts.Addons, func(i, j int) bool { return facts.Addons[i].Name < facts.Addons[j].Name })
+	}
+
+	return facts, nil
+}
+
+// detectPlatform guesses the Kubernetes distribution from node metadata
+func detectPlatform(name string, labels map[string]string, providerID, kubeletVersion string) string {
+	for key := range labels {
+		for _, pl := range platformLabels {
+			if strings.HasPrefix(key, pl.label) {
+				return pl.platform
+			}
+		}
+	}
+
+	switch {
+	case strings.Contains(kubeletVersion, "+k3s"):
+		return "k3s"
+	case strings.Contains(kubeletVersion, "+rke2"):
+		return "rke2"
+	case strings.HasPrefix(providerID, "kind://"):
+		return "kind"
+	case strings.HasPrefix(providerID, "aws://"):
+		return "aws"
+	case strings.HasPrefix(providerID, "gce://"):
+		return "gcp"
+	case strings.HasPrefix(providerID, "azure://"):
+		return "azure"
+	case name == "docker-desktop":
+		return "docker-desktop"
+	}
+	return ""
+}
+
+// imageTag returns the tag of an image reference, ignoring digests
+func imageTag(image string) string {
+	image = strings.SplitN(image, "@", 2)[0]
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ClusterIssuerResource is the kubectl resource name for cert-manager
+// ClusterIssuers
+const ClusterIssuerResource = "clusterissuers.cert-manager.io"
+
+// defaultStorageClassAnnotation marks the StorageClass claims without one use
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// APIVersions returns the group/versions the API server serves, e.g. v1,
+// apps/v1, and autoscaling/v2, including those of installed CRDs
+func (c *Client) APIVersions(ctx context.Context) ([]string, error) {
+	out, err := c.Run(ctx, "api-versions")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// NamespaceExists reports whether namespace exists
+func (c *Client) NamespaceExists(ctx context.Context, namespace string) (bool, error) {
+	var ns struct{}
+	err := c.GetJSON(ctx, &ns, "namespace", namespace)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+// StorageClass is a StorageClass of the cluster
+type StorageClass struct {
+	Name string
+	// Default is whether claims without a storage class get this one
+	Default bool
+}
+
+// ListStorageClasses returns the cluster's StorageClasses, sorted by name
+func (c *Client) ListStorageClasses(ctx context.Context) ([]StorageClass, error) {
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name        string            `json:"name"`
+				Annotations map[string]string `json:"annotations"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := c.GetJSON(ctx, &list, "storageclasses"); err != nil {
+		return nil, err
+	}
+	classes := make([]StorageClass, 0, len(list.Items))
+	for _, item := range list.Items {
+		classes = append(classes, StorageClass{
+			Name:    item.Metadata.Name,
+			Default: item.Metadata.Annotations[defaultStorageClassAnnotation] == "true",
+		})
+	}
+	sort.Slice(classes, func(i, j int) bool { return classes[i].Name < classes[j].Name })
+	return classes, nil
+}
+
+// ListIngressClasses returns the names of the cluster's IngressClasses,
+// sorted
+func (c *Client) ListIngressClasses(ctx context.Context) ([]string, error) {
+	return c.listNames(ctx, "ingressclasses")
+}
+
+// ListClusterIssuers returns the names of the cert-manager ClusterIssuers,
+// sorted. It returns ErrCRDNotInstalled when cert-manager is not installed.
+func (c *Client) ListClusterIssuers(ctx context.Context) ([]string, error) {
+	return c.listNames(ctx, ClusterIssuerResource)
+}
+
+// listNames returns the names of the objects of a cluster-scoped resource,
+// sorted
+func (c *Client) listNames(ctx context.Context, resource string) ([]string, error) {
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := c.GetJSON(ctx, &list, resource); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		names = append(names, item.Metadata.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
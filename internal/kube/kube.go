@@ -0,0 +1,125 @@
+// Package kube provides a small client for the dorgu.io/v1 API group.
+// Persona reads and deletes, operator discovery, and port-forwards go to the
+// API server with client-go; the other calls shell out to kubectl. Both honor
+// the user's kubeconfig, contexts, and auth plugins exactly as the rest of the
+// CLI does.
+package kube
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+var (
+	// ErrKubectlNotFound is returned when kubectl is not on PATH
+	ErrKubectlNotFound = errors.New("kubectl not found in PATH")
+	// ErrNotFound is returned when the requested object does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrCRDNotInstalled is returned when the resource type is unknown to the cluster
+	ErrCRDNotInstalled = errors.New("CRD is not installed on this cluster")
+)
+
+// Client runs kubectl against the current kubeconfig context, or the
+// context it was created for
+type Client struct {
+	kubectl string
+	context string
+	api     api
+}
+
+// NewClient returns a client, failing when kubectl is unavailable
+func NewClient() (*Client, error) {
+	path, err := exec.LookPath("kubectl")
+	if err != nil {
+		return nil, ErrKubectlNotFound
+	}
+	return &Client{kubectl: path}, nil
+}
+
+// NewClientForContext returns a client for the kubeconfig context name
+// instead of the current one
+func NewClientForContext(name string) (*Client, error) {
+	c, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	c.context = name
+	return c, nil
+}
+
+// command returns the kubectl command for args, pinned to the client's
+// context. Cancelling ctx kills kubectl. Clients from NewAPIClient fail with
+// ErrKubectlNotFound when kubectl is not on PATH.
+func (c *Client) command(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	if c.kubectl == "" {
+		return nil, ErrKubectlNotFound
+	}
+	if c.context != "" {
+		args = append([]string{"--context", c.context}, args...)
+	}
+	return exec.CommandContext(ctx, c.kubectl, args...), nil
+}
+
+// Run executes kubectl with args and returns stdout. Failures are classified
+// into ErrNotFound / ErrCRDNotInstalled where possible.
+func (c *Client) Run(ctx context.Context, args ...string) ([]byte, error) {
+	return c.RunWithInput(ctx, nil, args...)
+}
+
+// RunWithInput executes kubectl with stdin set to input
+func (c *Client) RunWithInput(ctx context.Context, input []byte, args ...string) ([]byte, error) {
+	cmd, err := c.command(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return stdout.Bytes(), ctx.Err()
+		}
+		return stdout.Bytes(), classifyError(strings.TrimSpace(stderr.String()), err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// GetJSON runs `kubectl get ... -o json` and decodes the result into out
+func (c *Client) GetJSON(ctx context.Context, out interface{}, args ...string) error {
+	return c.runJSON(ctx, out, append(append([]string{"get"}, args...), "-o", "json")...)
+}
+
+// runJSON runs kubectl and decodes its JSON stdout into out
+func (c *Client) runJSON(ctx context.Context, out interface{}, args ...string) error {
+	data, err := c.Run(ctx, args...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode kubectl output: %w", err)
+	}
+	return nil
+}
+
+func classifyError(stderr string, err error) error {
+	switch {
+	case strings.Contains(stderr, "the server doesn't have a resource type"),
+		strings.Contains(stderr, "no matches for kind"):
+		return fmt.Errorf("%w: %s", ErrCRDNotInstalled, stderr)
+	case strings.Contains(stderr, "NotFound"), strings.Contains(stderr, "not found"):
+		return fmt.Errorf("%w: %s", ErrNotFound, stderr)
+	case stderr != "":
+		return errors.New(stderr)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,128 @@
+package kube
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Runtimes of a LocalCluster
+const (
+	RuntimeKind     = "kind"
+	RuntimeMinikube = "minikube"
+)
+
+// LocalCluster is a kind cluster or minikube profile on this machine, used
+// to preview generated manifests
+type LocalCluster struct {
+	Runtime string
+	Name    string
+}
+
+// Context returns the kubeconfig context the runtime creates for the cluster
+func (l LocalCluster) Context() string {
+	if l.Runtime == RuntimeKind {
+		return "kind-" + l.Name
+	}
+	return l.Name
+}
+
+// Exists reports whether the cluster has been created
+func (l LocalCluster) Exists(ctx context.Context) (bool, error) {
+	switch l.Runtime {
+	case RuntimeKind:
+		out, err := l.run(ctx, "kind", "get", "clusters")
+		if err != nil {
+			return false, err
+		}
+		return containsLine(out, l.Name), nil
+	case RuntimeMinikube:
+		out, err := l.run(ctx, "minikube", "profile", "list", "-o", "json")
+		if err != nil {
+			// minikube fails when there are no profiles at all
+			return false, nil
+		}
+		return strings.Contains(out, fmt.Sprintf("%q", l.Name)), nil
+	}
+	return false, fmt.Errorf("unsupported runtime %q (use kind or minikube)", l.Runtime)
+}
+
+// Create creates the cluster and waits until its node is ready
+func (l LocalCluster) Create(ctx context.Context) error {
+	var err error
+	switch l.Runtime {
+	case RuntimeKind:
+		_, err = l.run(ctx, "kind", "create", "cluster", "--name", l.Name, "--wait", "2m")
+	case RuntimeMinikube:
+		_, err = l.run(ctx, "minikube", "start", "-p", l.Name)
+	default:
+		return fmt.Errorf("unsupported runtime %q (use kind or minikube)", l.Runtime)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create %s cluster %s: %w", l.Runtime, l.Name, err)
+	}
+	return nil
+}
+
+// Delete deletes the cluster
+func (l LocalCluster) Delete(ctx context.Context) error {
+	var err error
+	switch l.Runtime {
+	case RuntimeKind:
+		_, err = l.run(ctx, "kind", "delete", "cluster", "--name", l.Name)
+	case RuntimeMinikube:
+		_, err = l.run(ctx, "minikube", "delete", "-p", l.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete %s cluster %s: %w", l.Runtime, l.Name, err)
+	}
+	return nil
+}
+
+// LoadImage copies a local Docker image into the cluster's nodes, so pods
+// use it without a registry
+func (l LocalCluster) LoadImage(ctx context.Context, image string) error {
+	var err error
+	switch l.Runtime {
+	case RuntimeKind:
+		_, err = l.run(ctx, "kind", "load", "docker-image", image, "--name", l.Name)
+	case RuntimeMinikube:
+		_, err = l.run(ctx, "minikube", "image", "load", image, "-p", l.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load %s into %s cluster %s: %w", image, l.Runtime, l.Name, err)
+	}
+	return nil
+}
+
+// run executes a runtime CLI and returns its stdout, or its stderr as the
+// error
+func (l LocalCluster) run(ctx context.Context, name string, args ...string) (string, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return "", fmt.Errorf("%s not found in PATH", name)
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", errors.New(msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// containsLine reports whether out has a line equal to s
+func containsLine(out, s string) bool {
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) == s {
+			return true
+		}
+	}
+	return false
+}
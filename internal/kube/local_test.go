@@ -0,0 +1,22 @@
+package kube
+
+import "testing"
+
+func TestLocalClusterContext(t *testing.T) {
+	if got := (LocalCluster{Runtime: RuntimeKind, Name: "preview"}).Context(); got != "kind-preview" {
+		t.Errorf("kind context = %q, want kind-preview", got)
+	}
+	if got := (LocalCluster{Runtime: RuntimeMinikube, Name: "preview"}).Context(); got != "preview" {
+		t.Errorf("minikube context = %q, want preview", got)
+	}
+}
+
+func TestContainsLine(t *testing.T) {
+	out := "dorgu-preview-2\ndorgu-preview\n"
+	if !containsLine(out, "dorgu-preview") {
+		t.Error("dorgu-preview not found")
+	}
+	if containsLine(out, "dorgu") {
+		t.Error("prefix matched a cluster name")
+	}
+}
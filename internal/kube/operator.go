@@ -0,0 +1,84 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// OperatorName is the name and app.kubernetes.io/name label of the operator Service
+	OperatorName = "dorgu-operator"
+	// OperatorWebSocketPort is the operator's default WebSocket port
+	OperatorWebSocketPort = 9090
+)
+
+// OperatorService locates the Dorgu Operator's WebSocket endpoint in the cluster
+type OperatorService struct {
+	Namespace string
+	Name      string
+	Port      int
+}
+
+// servicePort is the subset of a Service port used for discovery
+type servicePort struct {
+	Name string
+	Port int
+}
+
+// FindOperatorService searches all namespaces for the operator Service,
+// preferring the app.kubernetes.io/name label over a name match.
+func (c *Client) FindOperatorService(ctx context.Context) (*OperatorService, error) {
+	clientset, err := c.clientset()
+	if err != nil {
+		return nil, err
+	}
+	list, err := clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, classifyAPIError(err)
+	}
+
+	var byName *OperatorService
+	for _, svc := range list.Items {
+		var ports []servicePort
+		for _, p := range svc.Spec.Ports {
+			ports = append(ports, servicePort{Name: p.Name, Port: int(p.Port)})
+		}
+		port := operatorServicePort(ports)
+		if port == 0 {
+			continue
+		}
+		found := &OperatorService{Namespace: svc.Namespace, Name: svc.Name, Port: port}
+		if svc.Labels["app.kubernetes.io/name"] == OperatorName {
+			return found, nil
+		}
+		if byName == nil && strings.HasPrefix(svc.Name, OperatorName) {
+			byName = found
+		}
+	}
+	if byName != nil {
+		return byName, nil
+	}
+	return nil, fmt.Errorf("%w: no %s Service in any namespace", ErrNotFound, OperatorName)
+}
+
+// operatorServicePort picks the WebSocket port: a port named ws/websocket,
+// then the default port, then the only port
+func operatorServicePort(ports []servicePort) int {
+	for _, p := range ports {
+		if p.Name == "ws" || p.Name == "websocket" {
+			return p.Port
+		}
+	}
+	for _, p := range ports {
+		if p.Port == OperatorWebSocketPort {
+			return p.Port
+		}
+	}
+	if len(ports) == 1 {
+		return ports[0].Port
+	}
+	return 0
+}
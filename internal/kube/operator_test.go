@@ -0,0 +1,117 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestOperatorServicePort(t *testing.T) {
+	tests := []struct {
+		name  string
+		ports []servicePort
+		want  int
+	}{
+		{"named ws port", []servicePort{{Name: "metrics", Port: 8080}, {Name: "ws", Port: 9191}}, 9191},
+		{"default port", []servicePort{{Name: "metrics", Port: 8080}, {Name: "http", Port: 9090}}, 9090},
+		{"single port", []servicePort{{Name: "http", Port: 8000}}, 8000},
+		{"ambiguous", []servicePort{{Name: "a", Port: 1}, {Name: "b", Port: 2}}, 0},
+		{"none", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := operatorServicePort(tt.ports); got != tt.want {
+				t.Errorf("operatorServicePort() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeAPIClient returns a client whose API calls go to a fake clientset
+// holding objects
+func fakeAPIClient(objects ...runtime.Object) *Client {
+	c := &Client{}
+	c.api.once.Do(func() {})
+	c.api.config, c.api.clientset = &rest.Config{}, k8sfake.NewSimpleClientset(objects...)
+	return c
+}
+
+func TestFindOperatorService(t *testing.T) {
+	c := fakeAPIClient(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "dorgu-operator-metrics", Namespace: "tools"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 9090}}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "operator", Namespace: "dorgu-system", Labels: map[string]string{"app.kubernetes.io/name": OperatorName}},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "metrics", Port: 8080}, {Name: "ws", Port: 9191}}},
+		},
+	)
+	svc, err := c.FindOperatorService(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *svc != (OperatorService{Namespace: "dorgu-system", Name: "operator", Port: 9191}) {
+		t.Errorf("FindOperatorService() = %+v, want the labelled Service", svc)
+	}
+
+	if _, err := fakeAPIClient().FindOperatorService(context.Background()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FindOperatorService() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestForwardTarget(t *testing.T) {
+	selector := map[string]string{"app": "orders"}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "commerce"},
+		Spec: corev1.ServiceSpec{Selector: selector, Ports: []corev1.ServicePort{
+			{Name: "http", Port: 80, TargetPort: intstr.FromString("web")},
+			{Name: "admin", Port: 9000, TargetPort: intstr.FromInt32(9001)},
+			{Name: "metrics", Port: 9090},
+		}},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders-abc", Namespace: "commerce", Labels: selector},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:  "app",
+			Ports: []corev1.ContainerPort{{Name: "web", ContainerPort: 8080}},
+		}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	c := fakeAPIClient(svc, pod)
+	ctx := context.Background()
+
+	tests := []struct {
+		target   string
+		port     int
+		wantPod  string
+		wantPort int
+	}{
+		{"svc/orders", 80, "orders-abc", 8080},
+		{"svc/orders", 9000, "orders-abc", 9001},
+		{"svc/orders", 9090, "orders-abc", 9090},
+		{"pod/orders-abc", 8080, "orders-abc", 8080},
+	}
+	for _, tt := range tests {
+		pod, port, err := c.forwardTarget(ctx, "commerce", tt.target, tt.port)
+		if err != nil || pod != tt.wantPod || port != tt.wantPort {
+			t.Errorf("forwardTarget(%s, %d) = %s, %d, %v; want %s, %d", tt.target, tt.port, pod, port, err, tt.wantPod, tt.wantPort)
+		}
+	}
+
+	for _, target := range []string{"svc/missing", "deployment/orders"} {
+		if _, _, err := c.forwardTarget(ctx, "commerce", target, 80); err == nil {
+			t.Errorf("forwardTarget(%s) succeeded", target)
+		}
+	}
+	if _, _, err := c.forwardTarget(ctx, "commerce", "svc/orders", 443); err == nil {
+		t.Error("forwardTarget() succeeded for a port the Service lacks")
+	}
+}
@@ -0,0 +1,99 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// PersonaGVR is the API resource of ApplicationPersonas
+var PersonaGVR = schema.GroupVersionResource{Group: "dorgu.io", Version: "v1", Resource: "applicationpersonas"}
+
+// LastAppliedAnnotation is where kubectl apply records the applied manifest
+const LastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// DesiredPersona strips what the cluster records on a persona from it,
+// leaving the state its author declared: the status, creation timestamp, and
+// kubectl's last-applied annotation. Other server-managed metadata (uid,
+// resourceVersion, managedFields) is not part of PersonaMetadata.
+func DesiredPersona(p *types.ApplicationPersona) {
+	p.Status = nil
+	p.Metadata.CreationTimestamp = ""
+	delete(p.Metadata.Annotations, LastAppliedAnnotation)
+	if len(p.Metadata.Annotations) == 0 {
+		p.Metadata.Annotations = nil
+	}
+}
+
+// ListPersonas lists ApplicationPersonas in a namespace, or in all
+// namespaces when namespace is empty.
+func (c *Client) ListPersonas(ctx context.Context, namespace string) ([]types.ApplicationPersona, error) {
+	personas, err := c.resource(PersonaGVR, namespace)
+	if err != nil {
+		return nil, err
+	}
+	list, err := personas.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, classifyAPIError(err)
+	}
+	items := make([]types.ApplicationPersona, 0, len(list.Items))
+	for i := range list.Items {
+		var persona types.ApplicationPersona
+		if err := decode(&list.Items[i], &persona); err != nil {
+			return nil, err
+		}
+		items = append(items, persona)
+	}
+	return items, nil
+}
+
+// GetPersona fetches a single ApplicationPersona including its status
+func (c *Client) GetPersona(ctx context.Context, namespace, name string) (*types.ApplicationPersona, error) {
+	personas, err := c.resource(PersonaGVR, namespace)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := personas.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, classifyAPIError(err)
+	}
+	var persona types.ApplicationPersona
+	if err := decode(obj, &persona); err != nil {
+		return nil, err
+	}
+	return &persona, nil
+}
+
+// DeletePersona deletes an ApplicationPersona
+func (c *Client) DeletePersona(ctx context.Context, namespace, name string) error {
+	personas, err := c.resource(PersonaGVR, namespace)
+	if err != nil {
+		return err
+	}
+	return classifyAPIError(personas.Delete(ctx, name, metav1.DeleteOptions{}))
+}
+
+// ApplyPersona applies a persona manifest. With dryRun the request is sent as
+// a server-side dry run and the object the server would persist is returned.
+func (c *Client) ApplyPersona(ctx context.Context, namespace string, manifest []byte, dryRun bool) (*types.ApplicationPersona, error) {
+	args := []string{"apply", "-f", "-", "-n", namespace, "-o", "json"}
+	if dryRun {
+		args = append(args, "--dry-run=server")
+	}
+
+	data, err := c.RunWithInput(ctx, manifest, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var persona types.ApplicationPersona
+	if err := json.Unmarshal(data, &persona); err != nil {
+		return nil, fmt.Errorf("failed to decode kubectl output: %w", err)
+	}
+	return &persona, nil
+}
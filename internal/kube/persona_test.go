@@ -0,0 +1,121 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestDesiredPersona(t *testing.T) {
+	persona := &types.ApplicationPersona{
+		Metadata: types.PersonaMetadata{
+			Name:              "orders",
+			Labels:            map[string]string{"team": "payments"},
+			Annotations:       map[string]string{LastAppliedAnnotation: `{"kind":"ApplicationPersona"}`},
+			CreationTimestamp: "2026-01-02T03:04:05Z",
+		},
+		Spec:   types.PersonaSpec{Name: "orders", Type: "api"},
+		Status: &types.PersonaStatus{Phase: "Active"},
+	}
+	DesiredPersona(persona)
+	if persona.Status != nil || persona.Metadata.CreationTimestamp != "" || persona.Metadata.Annotations != nil {
+		t.Errorf("server-managed fields kept: %+v", persona)
+	}
+	if persona.Metadata.Labels["team"] != "payments" || persona.Spec.Type != "api" {
+		t.Errorf("declared fields lost: %+v", persona)
+	}
+
+	// Annotations the author set stay
+	persona.Metadata.Annotations = map[string]string{LastAppliedAnnotation: "{}", "dorgu.io/owner": "payments"}
+	DesiredPersona(persona)
+	if len(persona.Metadata.Annotations) != 1 || persona.Metadata.Annotations["dorgu.io/owner"] != "payments" {
+		t.Errorf("annotations = %v", persona.Metadata.Annotations)
+	}
+
+	// A metadata-only change shows in the diff
+	local := *persona
+	local.Metadata.Labels = map[string]string{"team": "orders"}
+	changes, err := Diff(local, *persona)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Path != "metadata.labels.team" {
+		t.Errorf("changes = %+v", changes)
+	}
+}
+
+func TestPersonaAPI(t *testing.T) {
+	orders := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": types.PersonaAPIVersion,
+		"kind":       types.PersonaKind,
+		"metadata":   map[string]interface{}{"name": "orders", "namespace": "commerce", "creationTimestamp": "2026-01-02T03:04:05Z"},
+		"spec":       map[string]interface{}{"name": "orders", "type": "api", "tier": "critical"},
+		"status":     map[string]interface{}{"phase": "Active"},
+	}}
+	scheme := runtime.NewScheme()
+	fake := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+		map[schema.GroupVersionResource]string{PersonaGVR: "ApplicationPersonaList"}, orders)
+	c := &Client{}
+	c.api.once.Do(func() {})
+	c.api.config, c.api.dynamic = &rest.Config{}, fake
+	ctx := context.Background()
+
+	list, err := c.ListPersonas(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Metadata.Namespace != "commerce" || list[0].Spec.Tier != "critical" || list[0].Status == nil || list[0].Status.Phase != "Active" {
+		t.Errorf("ListPersonas() = %+v", list)
+	}
+	if list, err := c.ListPersonas(ctx, "other"); err != nil || len(list) != 0 {
+		t.Errorf("ListPersonas(other) = %+v, %v", list, err)
+	}
+
+	persona, err := c.GetPersona(ctx, "commerce", "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if persona.Metadata.CreationTimestamp != "2026-01-02T03:04:05Z" || persona.Spec.Type != "api" {
+		t.Errorf("GetPersona() = %+v", persona)
+	}
+
+	if err := c.DeletePersona(ctx, "commerce", "orders"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetPersona(ctx, "commerce", "orders"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetPersona() after delete error = %v, want ErrNotFound", err)
+	}
+	if err := c.DeletePersona(ctx, "commerce", "orders"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeletePersona() twice error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestClassifyAPIError(t *testing.T) {
+	gr := schema.GroupResource{Group: "dorgu.io", Resource: "applicationpersonas"}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"missing object", apierrors.NewNotFound(gr, "orders"), ErrNotFound},
+		{"missing CRD", apierrors.NewGenericServerResponse(http.StatusNotFound, "GET", gr, "", "404 page not found", 0, true), ErrCRDNotInstalled},
+	}
+	for _, tt := range tests {
+		if got := classifyAPIError(tt.err); !errors.Is(got, tt.want) {
+			t.Errorf("%s: classifyAPIError() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if classifyAPIError(nil) != nil {
+		t.Error("classifyAPIError(nil) != nil")
+	}
+}
@@ -0,0 +1,231 @@
+package kube
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AppSelector is the label selector of the pods dorgu generated for app
+func AppSelector(app string) string {
+	return "app.kubernetes.io/name=" + app + ",app.kubernetes.io/managed-by=dorgu"
+}
+
+// Pod is the part of a pod the app commands need
+type Pod struct {
+	Name       string
+	Phase      string
+	Ready      bool
+	Containers []string
+	// Ports are the container ports, in container order
+	Ports []int
+}
+
+// ListPods returns the pods matching selector, oldest first
+func (c *Client) ListPods(ctx context.Context, namespace, selector string) ([]Pod, error) {
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name              string    `json:"name"`
+				CreationTimestamp time.Time `json:"creationTimestamp"`
+			} `json:"metadata"`
+			Spec struct {
+				Containers []struct {
+					Name  string `json:"name"`
+					Ports []struct {
+						ContainerPort int `json:"containerPort"`
+					} `json:"ports"`
+				} `json:"containers"`
+			} `json:"spec"`
+			Status struct {
+				Phase      string `json:"phase"`
+				Conditions []struct {
+					Type   string `json:"type"`
+					Status string `json:"status"`
+				} `json:"conditions"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := c.GetJSON(ctx, &list, "pods", "-n", namespace, "-l", selector); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(list.Items, func(i, j int) bool {
+		return list.Items[i].Metadata.CreationTimestamp.Before(list.Items[j].Metadata.CreationTimestamp)
+	})
+	var pods []Pod
+	for _, item := range list.Items {
+		pod := Pod{Name: item.Metadata.Name, Phase: item.Status.Phase}
+		for _, cond := range item.Status.Conditions {
+			if cond.Type == "Ready" {
+				pod.Ready = cond.Status == "True"
+			}
+		}
+		for _, container := range item.Spec.Containers {
+			pod.Containers = append(pod.Containers, container.Name)
+			for _, p := range container.Ports {
+				pod.Ports = append(pod.Ports, p.ContainerPort)
+			}
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+// LogOptions select the log lines StreamLogs returns
+type LogOptions struct {
+	Follow bool
+	// Tail is the number of recent lines to start with; negative for all
+	Tail  int
+	Since time.Duration
+	// Container is the container to read; all of the pod's when empty
+	Container string
+	// Previous reads the logs of the previous, crashed container
+	Previous bool
+}
+
+// StreamLogs calls line for each log line of pod until the logs end, or ctx
+// is cancelled when following them
+func (c *Client) StreamLogs(ctx context.Context, namespace, pod string, opts LogOptions, line func(string)) error {
+	args := []string{"logs", pod, "-n", namespace, fmt.Sprintf("--tail=%d", opts.Tail)}
+	if opts.Container != "" {
+		args = append(args, "-c", opts.Container)
+	} else {
+		args = append(args, "--all-containers")
+	}
+	if opts.Follow {
+		args = append(args, "--follow")
+	}
+	if opts.Since > 0 {
+		args = append(args, "--since", opts.Since.String())
+	}
+	if opts.Previous {
+		args = append(args, "--previous")
+	}
+	return c.stream(ctx, args, func(r io.Reader) error {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line(scanner.Text())
+		}
+		return scanner.Err()
+	})
+}
+
+// Event is a Kubernetes event about an object
+type Event struct {
+	Type           string `json:"type"`
+	Reason         string `json:"reason"`
+	Message        string `json:"message"`
+	Count          int    `json:"count"`
+	InvolvedObject struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+	} `json:"involvedObject"`
+	FirstTimestamp time.Time `json:"firstTimestamp"`
+	LastTimestamp  time.Time `json:"lastTimestamp"`
+	EventTime      time.Time `json:"eventTime"`
+}
+
+// Time returns when the event last happened
+func (e Event) Time() time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp
+	case !e.EventTime.IsZero():
+		return e.EventTime
+	}
+	return e.FirstTimestamp
+}
+
+// IsAbout reports whether the event is about one of app's objects: those
+// named after it (the Deployment, Service, HPA, ...) and those dorgu or
+// Kubernetes name with it as the prefix (ReplicaSets, pods, Jobs)
+func (e Event) IsAbout(app string) bool {
+	return e.InvolvedObject.Name == app || strings.HasPrefix(e.InvolvedObject.Name, app+"-")
+}
+
+// ListEvents returns the events in namespace, oldest first
+func (c *Client) ListEvents(ctx context.Context, namespace string) ([]Event, error) {
+	var list struct {
+		Items []Event `json:"items"`
+	}
+	if err := c.GetJSON(ctx, &list, "events", "-n", namespace); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(list.Items, func(i, j int) bool { return list.Items[i].Time().Before(list.Items[j].Time()) })
+	return list.Items, nil
+}
+
+// WatchEvents calls event for each new event in namespace until ctx is
+// cancelled
+func (c *Client) WatchEvents(ctx context.Context, namespace string, event func(Event)) error {
+	return c.stream(ctx, []string{"get", "events", "-n", namespace, "--watch-only", "-o", "json"}, func(r io.Reader) error {
+		dec := json.NewDecoder(r)
+		for {
+			var e Event
+			if err := dec.Decode(&e); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			event(e)
+		}
+	})
+}
+
+// stream runs kubectl with args, hands its stdout to read, and waits for it
+// to exit. Cancelling ctx stops kubectl and is not an error.
+func (c *Client) stream(ctx context.Context, args []string, read func(io.Reader) error) error {
+	cmd, err := c.command(ctx, args...)
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start kubectl: %w", err)
+	}
+
+	readErr := read(stdout)
+	waitErr := cmd.Wait()
+	switch {
+	case ctx.Err() != nil:
+		return nil
+	case waitErr != nil:
+		return classifyError(strings.TrimSpace(stderr.String()), waitErr)
+	}
+	return readErr
+}
+
+// Exec runs command in container of pod with stdin, stdout, and stderr
+// attached, and returns once it exits. tty allocates a terminal for
+// interactive commands such as shells.
+func (c *Client) Exec(ctx context.Context, namespace, pod, container string, tty bool, command []string) error {
+	args := []string{"exec", pod, "-n", namespace, "-i"}
+	if container != "" {
+		args = append(args, "-c", container)
+	}
+	if tty {
+		args = append(args, "-t")
+	}
+	cmd, err := c.command(ctx, append(append(args, "--"), command...)...)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
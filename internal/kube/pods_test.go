@@ -0,0 +1,38 @@
+package kube
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventIsAbout(t *testing.T) {
+	tests := []struct {
+		object string
+		want   bool
+	}{
+		{"orders", true},
+		{"orders-7d9f8c6b5", true},
+		{"orders-7d9f8c6b5-x2v9q", true},
+		{"orders-migrate", true},
+		{"orders2", false},
+		{"billing", false},
+	}
+	for _, tt := range tests {
+		var e Event
+		e.InvolvedObject.Name = tt.object
+		if got := e.IsAbout("orders"); got != tt.want {
+			t.Errorf("IsAbout(%q) = %v, want %v", tt.object, got, tt.want)
+		}
+	}
+}
+
+func TestEventTime(t *testing.T) {
+	first := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	last := first.Add(time.Minute)
+	if got := (Event{FirstTimestamp: first, LastTimestamp: last}).Time(); !got.Equal(last) {
+		t.Errorf("Time() = %v, want lastTimestamp %v", got, last)
+	}
+	if got := (Event{EventTime: first}).Time(); !got.Equal(first) {
+		t.Errorf("Time() = %v, want eventTime %v", got, first)
+	}
+}
@@ -0,0 +1,168 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// portForwardTimeout bounds how long a port-forward may take to be ready
+const portForwardTimeout = 15 * time.Second
+
+// PortForward is a running port-forward to a pod
+type PortForward struct {
+	LocalPort int
+	stop      chan struct{}
+	done      chan struct{}
+	once      sync.Once
+}
+
+// Stop terminates the port-forward and waits for it to close
+func (pf *PortForward) Stop() {
+	pf.once.Do(func() { close(pf.stop) })
+	<-pf.done
+}
+
+// PortForward forwards a random local port to target (e.g. svc/dorgu-operator)
+// and returns once the forward is ready. Cancelling ctx stops the forward.
+func (c *Client) PortForward(ctx context.Context, namespace, target string, remotePort int) (*PortForward, error) {
+	return c.PortForwardFrom(ctx, namespace, target, 0, remotePort)
+}
+
+// PortForwardFrom forwards localPort, or a random one when it is 0, to
+// target like PortForward
+func (c *Client) PortForwardFrom(ctx context.Context, namespace, target string, localPort, remotePort int) (*PortForward, error) {
+	config, err := c.restConfig()
+	if err != nil {
+		return nil, err
+	}
+	pod, podPort, err := c.forwardTarget(ctx, namespace, target, remotePort)
+	if err != nil {
+		return nil, err
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, err
+	}
+	url := c.api.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(namespace).Name(pod).SubResource("portforward").URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	pf := &PortForward{stop: make(chan struct{}), done: make(chan struct{})}
+	ready := make(chan struct{})
+	fw, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"},
+		[]string{fmt.Sprintf("%d:%d", localPort, podPort)}, pf.stop, ready, io.Discard, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+	failed := make(chan error, 1)
+	go func() {
+		defer close(pf.done)
+		failed <- fw.ForwardPorts()
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			pf.Stop()
+		case <-pf.done:
+		}
+	}()
+
+	select {
+	case <-ready:
+	case err := <-failed:
+		return nil, fmt.Errorf("port-forward to %s/%s failed: %w", namespace, target, err)
+	case <-ctx.Done():
+		pf.Stop()
+		return nil, ctx.Err()
+	case <-time.After(portForwardTimeout):
+		pf.Stop()
+		return nil, fmt.Errorf("timed out waiting for port-forward to %s/%s", namespace, target)
+	}
+	ports, err := fw.GetPorts()
+	if err != nil || len(ports) == 0 {
+		pf.Stop()
+		return nil, fmt.Errorf("port-forward to %s/%s has no local port: %v", namespace, target, err)
+	}
+	pf.LocalPort = int(ports[0].Local)
+	return pf, nil
+}
+
+// forwardTarget resolves target, pod/<name> or svc/<name>, to the pod to
+// forward to and the port remotePort reaches on it. Like kubectl, a Service
+// is forwarded to one of its running pods, at the Service port's target port.
+func (c *Client) forwardTarget(ctx context.Context, namespace, target string, remotePort int) (string, int, error) {
+	kind, name, _ := strings.Cut(target, "/")
+	switch kind {
+	case "pod", "pods", "po":
+		return name, remotePort, nil
+	case "svc", "service", "services":
+	default:
+		return "", 0, fmt.Errorf("cannot port-forward to %q; use pod/<name> or svc/<name>", target)
+	}
+
+	clientset, err := c.clientset()
+	if err != nil {
+		return "", 0, err
+	}
+	svc, err := clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", 0, classifyAPIError(err)
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return "", 0, fmt.Errorf("service/%s has no selector to find its pods", name)
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+		FieldSelector: "status.phase=Running",
+	})
+	if err != nil {
+		return "", 0, classifyAPIError(err)
+	}
+	if len(pods.Items) == 0 {
+		return "", 0, fmt.Errorf("%w: no running pods of service/%s", ErrNotFound, name)
+	}
+	pod := &pods.Items[0]
+	port, err := servicePodPort(svc, pod, remotePort)
+	if err != nil {
+		return "", 0, err
+	}
+	return pod.Name, port, nil
+}
+
+// servicePodPort returns the port of pod that port of svc targets: its
+// number, its named container port, or the Service port itself when the
+// target port is unset
+func servicePodPort(svc *corev1.Service, pod *corev1.Pod, port int) (int, error) {
+	for _, sp := range svc.Spec.Ports {
+		if int(sp.Port) != port {
+			continue
+		}
+		switch {
+		case sp.TargetPort.Type == intstr.String:
+			for _, c := range pod.Spec.Containers {
+				for _, cp := range c.Ports {
+					if cp.Name == sp.TargetPort.StrVal {
+						return int(cp.ContainerPort), nil
+					}
+				}
+			}
+			return 0, fmt.Errorf("pod/%s has no port named %q for service/%s", pod.Name, sp.TargetPort.StrVal, svc.Name)
+		case sp.TargetPort.IntVal != 0:
+			return int(sp.TargetPort.IntVal), nil
+		}
+		return port, nil
+	}
+	return 0, fmt.Errorf("service/%s has no port %d", svc.Name, port)
+}
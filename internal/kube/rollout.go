@@ -0,0 +1,228 @@
+package kube
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Revision is one entry of a Deployment's rollout history, read from the
+// ReplicaSet that rolled it out
+type Revision struct {
+	Number      int
+	Images      []string
+	ChangeCause string
+	Created     time.Time
+	// Current is the revision the Deployment runs now
+	Current bool
+}
+
+const (
+	revisionAnnotation    = "deployment.kubernetes.io/revision"
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+)
+
+// replicaSet is the part of a ReplicaSet rollout history needs
+type replicaSet struct {
+	Metadata struct {
+		Annotations       map[string]string `json:"annotations"`
+		CreationTimestamp time.Time         `json:"creationTimestamp"`
+		OwnerReferences   []struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		} `json:"ownerReferences"`
+	} `json:"metadata"`
+	Spec struct {
+		Template struct {
+			Spec struct {
+				Containers []struct {
+					Image string `json:"image"`
+				} `json:"containers"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+}
+
+// DeploymentHistory returns the Deployment's revisions, newest first. Only
+// revisions whose ReplicaSet is still kept (spec.revisionHistoryLimit) can
+// be rolled back to.
+func (c *Client) DeploymentHistory(ctx context.Context, namespace, name string) ([]Revision, error) {
+	var deployment struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+		Spec struct {
+			Selector struct {
+				MatchLabels map[string]string `json:"matchLabels"`
+			} `json:"selector"`
+		} `json:"spec"`
+	}
+	if err := c.GetJSON(ctx, &deployment, "deployment", name, "-n", namespace); err != nil {
+		return nil, err
+	}
+	var selector []string
+	for k, v := range deployment.Spec.Selector.MatchLabels {
+		selector = append(selector, k+"="+v)
+	}
+	sort.Strings(selector)
+
+	var list struct {
+		Items []replicaSet `json:"items"`
+	}
+	if err := c.GetJSON(ctx, &list, "replicasets", "-n", namespace, "-l", strings.Join(selector, ",")); err != nil {
+		return nil, err
+	}
+	current, _ := strconv.Atoi(deployment.Metadata.Annotations[revisionAnnotation])
+	return revisions(list.Items, name, current), nil
+}
+
+// revisions returns the history of the Deployment name from its ReplicaSets
+func revisions(items []replicaSet, name string, current int) []Revision {
+	var history []Revision
+	for _, rs := range items {
+		owned := false
+		for _, ref := range rs.Metadata.OwnerReferences {
+			owned = owned || ref.Kind == "Deployment" && ref.Name == name
+		}
+		number, err := strconv.Atoi(rs.Metadata.Annotations[revisionAnnotation])
+		if !owned || err != nil {
+			continue
+		}
+		r := Revision{
+			Number:      number,
+			ChangeCause: rs.Metadata.Annotations[changeCauseAnnotation],
+			Created:     rs.Metadata.CreationTimestamp,
+			Current:     number == current,
+		}
+		for _, c := range rs.Spec.Template.Spec.Containers {
+			r.Images = append(r.Images, c.Image)
+		}
+		history = append(history, r)
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Number > history[j].Number })
+	return history
+}
+
+// RollbackDeployment rolls the Deployment back to revision, which becomes
+// its newest revision
+func (c *Client) RollbackDeployment(ctx context.Context, namespace, name string, revision int) error {
+	_, err := c.Run(ctx, "rollout", "undo", "deployment/"+name, "-n", namespace, fmt.Sprintf("--to-revision=%d", revision))
+	return err
+}
+
+// ArgoCDApplicationResource is the kubectl resource name for ArgoCD
+// Applications
+const ArgoCDApplicationResource = "applications.argoproj.io"
+
+// ArgoCDRevision is one sync in an ArgoCD Application's history
+type ArgoCDRevision struct {
+	ID         int       `json:"id"`
+	Revision   string    `json:"revision"`
+	DeployedAt time.Time `json:"deployedAt"`
+}
+
+// ArgoCDApp is the part of an ArgoCD Application rollback needs
+type ArgoCDApp struct {
+	Spec struct {
+		SyncPolicy *struct {
+			Automated *struct{} `json:"automated"`
+		} `json:"syncPolicy"`
+	} `json:"spec"`
+	Status struct {
+		Sync struct {
+			Revision string `json:"revision"`
+		} `json:"sync"`
+		History []ArgoCDRevision `json:"history"`
+	} `json:"status"`
+}
+
+// AutoSync reports whether ArgoCD syncs the app automatically, which undoes
+// any change made to its resources outside ArgoCD
+func (a *ArgoCDApp) AutoSync() bool {
+	return a.Spec.SyncPolicy != nil && a.Spec.SyncPolicy.Automated != nil
+}
+
+// GetArgoCDApp fetches an ArgoCD Application with its sync history
+func (c *Client) GetArgoCDApp(ctx context.Context, namespace, name string) (*ArgoCDApp, error) {
+	var app ArgoCDApp
+	if err := c.GetJSON(ctx, &app, ArgoCDApplicationResource, name, "-n", namespace); err != nil {
+		return nil, err
+	}
+	sort.Slice(app.Status.History, func(i, j int) bool { return app.Status.History[i].ID > app.Status.History[j].ID })
+	return &app, nil
+}
+
+// ArgoCDNamespace is the default namespace of the ArgoCD control plane
+const ArgoCDNamespace = "argocd"
+
+// ArgoCDServer is an ArgoCD API server reached with a bearer token, such as
+// one from `argocd account generate-token`
+type ArgoCDServer struct {
+	URL   string
+	Token string
+	// Namespace is the namespace of the ArgoCD control plane. Applications
+	// in other namespaces are addressed with appNamespace, which needs
+	// apps-in-any-namespace enabled on the server.
+	Namespace  string
+	HTTPClient *http.Client
+}
+
+// NewArgoCDServer returns the ArgoCD API server at addr, which like
+// ARGOCD_SERVER may omit the https:// scheme
+func NewArgoCDServer(addr, token string) *ArgoCDServer {
+	if !strings.Contains(addr, "://") {
+		addr = "https://" + addr
+	}
+	return &ArgoCDServer{
+		URL:        strings.TrimSuffix(addr, "/"),
+		Token:      token,
+		Namespace:  ArgoCDNamespace,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// RollbackApp redeploys the history entry id of the Application name in
+// namespace. ArgoCD refuses to roll back an app with automated sync
+// enabled.
+func (s *ArgoCDServer) RollbackApp(ctx context.Context, namespace, name string, id int) error {
+	body := map[string]interface{}{"name": name, "id": id}
+	if namespace != "" && namespace != s.Namespace {
+		body["appNamespace"] = namespace
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	endpoint := s.URL + "/api/v1/applications/" + url.PathEscape(name) + "/rollback"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid ArgoCD server URL %q", s.URL)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.Token)
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("argocd rollback failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		// grpc-gateway errors carry the reason in message
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("argocd rollback failed: %s", apiErr.Message)
+		}
+		return fmt.Errorf("argocd rollback failed: %s", resp.Status)
+	}
+	return nil
+}
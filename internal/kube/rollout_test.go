@@ -0,0 +1,85 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRevisions(t *testing.T) {
+	data := `[
+  {"metadata": {"annotations": {"deployment.kubernetes.io/revision": "3"}, "ownerReferences": [{"kind": "Deployment", "name": "orders"}]},
+   "spec": {"template": {"spec": {"containers": [{"image": "orders:1.2"}, {"image": "envoy:1.30"}]}}}},
+  {"metadata": {"annotations": {"deployment.kubernetes.io/revision": "5", "kubernetes.io/change-cause": "deploy 1.3"}, "ownerReferences": [{"kind": "Deployment", "name": "orders"}]},
+   "spec": {"template": {"spec": {"containers": [{"image": "orders:1.3"}]}}}},
+  {"metadata": {"annotations": {"deployment.kubernetes.io/revision": "9"}, "ownerReferences": [{"kind": "Deployment", "name": "orders-worker"}]},
+   "spec": {"template": {"spec": {"containers": [{"image": "orders-worker:1.3"}]}}}}
+]`
+	var items []replicaSet
+	if err := json.Unmarshal([]byte(data), &items); err != nil {
+		t.Fatal(err)
+	}
+
+	got := revisions(items, "orders", 5)
+	want := []Revision{
+		{Number: 5, Images: []string{"orders:1.3"}, ChangeCause: "deploy 1.3", Current: true},
+		{Number: 3, Images: []string{"orders:1.2", "envoy:1.30"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("revisions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestArgoCDServerRollbackApp(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotBody map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		gotBody = nil
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		if gotBody["name"] == "locked" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code": 9, "message": "rollback cannot be initiated when auto-sync is enabled"}`))
+		}
+	}))
+	defer srv.Close()
+	s := NewArgoCDServer(srv.URL, "secret")
+
+	if err := s.RollbackApp(context.Background(), "argocd", "orders", 4); err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/api/v1/applications/orders/rollback" || gotAuth != "Bearer secret" {
+		t.Errorf("request = %s with %q", gotPath, gotAuth)
+	}
+	if gotBody["id"] != float64(4) {
+		t.Errorf("id = %v, want 4", gotBody["id"])
+	}
+	if _, ok := gotBody["appNamespace"]; ok {
+		t.Errorf("appNamespace sent for an app in the control-plane namespace: %v", gotBody)
+	}
+
+	if err := s.RollbackApp(context.Background(), "team-a", "orders", 4); err != nil {
+		t.Fatal(err)
+	}
+	if gotBody["appNamespace"] != "team-a" {
+		t.Errorf("appNamespace = %v, want team-a", gotBody["appNamespace"])
+	}
+
+	err := s.RollbackApp(context.Background(), "argocd", "locked", 4)
+	if err == nil || !strings.Contains(err.Error(), "auto-sync is enabled") {
+		t.Errorf("error = %v, want the server's message", err)
+	}
+}
+
+func TestNewArgoCDServer(t *testing.T) {
+	if got := NewArgoCDServer("argocd.example.com:443", "").URL; got != "https://argocd.example.com:443" {
+		t.Errorf("URL = %q", got)
+	}
+	if got := NewArgoCDServer("http://localhost:8080/", "").URL; got != "http://localhost:8080" {
+		t.Errorf("URL = %q", got)
+	}
+}
@@ -0,0 +1,637 @@
+// Package lint checks the semantics of .dorgu.yaml files beyond what parsing
+// catches: replica bounds, requests above limits, host and email formats, and
+// app labels that clash with org labels.
+package lint
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/dorgu-ai/dorgu/internal/analyzer"
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/owners"
+)
+
+// Severity of an issue
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a single lint finding
+type Issue struct {
+	File     string   `json:"file"`
+	Field    string   `json:"field,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// HasErrors reports whether any issue is an error
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// orgKeys are top-level keys that only appear in workspace (org) config
+var orgKeys = []string{"org", "naming", "resources", "labels", "annotations", "security", "ingress", "argocd", "ci", "hpa", "metrics", "service", "env", "secrets", "header", "owners", "notifications", "analyzers"}
+
+// File lints one .dorgu.yaml. An app section (app:) is checked against org,
+// the org config in effect for the app; workspace settings in the same file
+// are checked too, and take the place of org.
+func File(file string, data []byte, org *config.Config) []Issue {
+	l := &linter{file: file}
+
+	var top map[string]interface{}
+	if err := yaml.Unmarshal(data, &top); err != nil {
+		l.add(SeverityError, "", "invalid YAML: %v", err)
+		return l.issues
+	}
+
+	if _, ok := top["app"]; ok {
+		var app config.AppConfig
+		if err := yaml.Unmarshal(data, &app); err != nil {
+			l.add(SeverityError, "", "invalid app config: %v", err)
+			return l.issues
+		}
+		if org == nil {
+			org = &config.Config{}
+		}
+		l.app(&app, org)
+		return l.issues
+	}
+
+	for _, k := range orgKeys {
+		if _, ok := top[k]; ok {
+			cfg, err := config.Parse(data)
+			if err != nil {
+				l.add(SeverityError, "", "invalid workspace config: %v", err)
+				return l.issues
+			}
+			l.org(cfg)
+			break
+		}
+	}
+	return l.issues
+}
+
+// Collisions reports app configs whose apps get the same resource name.
+// files maps each .dorgu.yaml path to its content; apps without app.name are
+// named after their directory, as generate names them.
+func Collisions(files map[string][]byte) []Issue {
+	names := map[string]string{}
+	for file, data := range files {
+		var top map[string]interface{}
+		var app config.AppConfig
+		if yaml.Unmarshal(data, &top) != nil || top["app"] == nil || yaml.Unmarshal(data, &app) != nil {
+			continue
+		}
+		name := app.App.Name
+		if name == "" {
+			abs, err := filepath.Abs(file)
+			if err != nil {
+				continue
+			}
+			name = filepath.Base(filepath.Dir(abs))
+		}
+		names[file] = name
+	}
+
+	var issues []Issue
+	collisions := generator.NameCollisions(names)
+	for _, name := range sortedKeys(collisions) {
+		files := collisions[name]
+		for _, file := range files {
+			var others []string
+			for _, f := range files {
+				if f != file {
+					others = append(others, f)
+				}
+			}
+			issues = append(issues, Issue{
+				File:     file,
+				Field:    "app.name",
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("resource name %q is also generated by %s", name, strings.Join(others, ", ")),
+			})
+		}
+	}
+	return issues
+}
+
+type linter struct {
+	file   string
+	issues []Issue
+}
+
+func (l *linter) add(sev Severity, field, format string, args ...interface{}) {
+	l.issues = append(l.issues, Issue{File: l.file, Field: field, Severity: sev, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) app(app *config.AppConfig, org *config.Config) {
+	if name := app.App.Name; name != "" && !generator.IsResourceName(name) {
+		l.add(SeverityError, "app.name", "%q is not a valid DNS-1123 name; resources would be named %q", name, generator.ResourceName(name))
+	}
+	if s := app.Scaling; s != nil {
+		if s.MinReplicas < 0 {
+			l.add(SeverityError, "scaling.min_replicas", "must not be negative")
+		}
+		if s.MaxReplicas > 0 && s.MinReplicas > s.MaxReplicas {
+			l.add(SeverityError, "scaling.min_replicas", "min_replicas (%d) is greater than max_replicas (%d)", s.MinReplicas, s.MaxReplicas)
+		}
+		if s.TargetCPU < 0 || s.TargetCPU > 100 {
+			l.add(SeverityError, "scaling.target_cpu", "must be a percentage between 1 and 100, got %d", s.TargetCPU)
+		}
+		if s.TargetMemory < 0 || s.TargetMemory > 100 {
+			l.add(SeverityError, "scaling.target_memory", "must be a percentage between 1 and 100, got %d", s.TargetMemory)
+		}
+		switch s.Behavior {
+		case "", config.BehaviorAggressive, config.BehaviorBalanced, config.BehaviorConservative:
+		default:
+			l.add(SeverityError, "scaling.behavior", "%q is not one of aggressive, balanced, conservative", s.Behavior)
+		}
+		l.scalingRules("scaling.scale_up", s.ScaleUp)
+		l.scalingRules("scaling.scale_down", s.ScaleDown)
+		for i, m := range s.Metrics {
+			field := fmt.Sprintf("scaling.metrics[%d]", i)
+			if m.Name == "" {
+				l.add(SeverityError, field+".name", "is required")
+			}
+			if err := generator.ValidateScalingTarget(m.Type, m.AverageValue, m.Value); err != nil {
+				l.add(SeverityError, field, "%v", err)
+			}
+		}
+	}
+
+	if r := app.Resources; r != nil {
+		l.resources("resources", config.ResourceSpec{Requests: r.Requests, Limits: r.Limits})
+	}
+
+	if ing := app.Ingress; ing != nil {
+		if ing.Host != "" {
+			if msg := checkHost(ing.Host); msg != "" {
+				l.add(SeverityError, "ingress.host", "%q %s", ing.Host, msg)
+			}
+		}
+		seen := map[string]bool{ing.Host: ing.Host != ""}
+		for i, h := range ing.Hosts {
+			field := fmt.Sprintf("ingress.hosts[%d]", i)
+			switch {
+			case h.Host == "":
+				l.add(SeverityError, field, "host is empty")
+			case seen[h.Host]:
+				l.add(SeverityWarning, field, "%q is listed more than once", h.Host)
+			default:
+				if msg := checkHost(h.Host); msg != "" {
+					l.add(SeverityError, field, "%q %s", h.Host, msg)
+				}
+			}
+			seen[h.Host] = true
+		}
+		for i, p := range ing.Paths {
+			if p.Port < 0 || p.Port > 65535 {
+				l.add(SeverityError, fmt.Sprintf("ingress.paths[%d].port", i), "%d is not a valid port", p.Port)
+			}
+		}
+	}
+
+	if n := app.Networking; n != nil {
+		switch n.Expose {
+		case "", config.ExposePublic:
+		case config.ExposeInternal, config.ExposeHeadless:
+			if app.Ingress != nil && app.Ingress.Enabled {
+				l.add(SeverityWarning, "ingress", "ignored because networking.expose is %s; no Ingress is generated", n.Expose)
+			}
+		default:
+			l.add(SeverityError, "networking.expose", "%q is not one of public, internal, headless", n.Expose)
+		}
+		if dns := n.DNS; dns != nil {
+			if dns.Ndots != nil && (*dns.Ndots < 0 || *dns.Ndots > 15) {
+				l.add(SeverityError, "networking.dns.ndots", "must be between 0 and 15, got %d", *dns.Ndots)
+			}
+			for i, search := range dns.Searches {
+				if msg := checkHost(search); msg != "" {
+					l.add(SeverityError, fmt.Sprintf("networking.dns.searches[%d]", i), "%q %s", search, msg)
+				}
+			}
+			for i, a := range dns.HostAliases {
+				field := fmt.Sprintf("networking.dns.host_aliases[%d]", i)
+				if net.ParseIP(a.IP) == nil {
+					l.add(SeverityError, field+".ip", "%q is not an IP address", a.IP)
+				}
+				if len(a.Hostnames) == 0 {
+					l.add(SeverityError, field+".hostnames", "at least one host name is required")
+				}
+			}
+		}
+	}
+
+	if app.Service != nil {
+		l.service(app, org)
+	}
+
+	if sec := app.Secrets; sec != nil && sec.Provider != "" {
+		l.secretsProvider("secrets.provider", sec.Provider)
+		if sec.Provider == config.SecretsCSI && org.Secrets.Vault.Address == "" {
+			l.add(SeverityError, "secrets.provider", "csi needs secrets.vault.address in the org config")
+		}
+	}
+
+	owner := app.App.Owner
+	switch {
+	case owner == "":
+		l.add(SeverityWarning, "app.owner", "not set; add an owner email so alerts and reviews reach someone")
+	case !strings.Contains(owner, "@"):
+		l.add(SeverityWarning, "app.owner", "%q is not an email address", owner)
+	default:
+		if addr, err := mail.ParseAddress(owner); err != nil || addr.Address != owner {
+			l.add(SeverityError, "app.owner", "%q is not a valid email address", owner)
+		}
+	}
+
+	required := map[string]bool{}
+	for _, k := range org.Labels.Required {
+		required[k] = true
+	}
+	for _, k := range sortedKeys(app.Labels) {
+		switch err := generator.ValidateLabel(strings.TrimSpace(k), strings.TrimSpace(app.Labels[k])); {
+		case err != nil:
+			l.add(SeverityError, "labels."+k, "%v", err)
+		case required[k]:
+			l.add(SeverityError, "labels."+k, "overrides a label required by the org config")
+		case org.Labels.Custom[k] != "" && org.Labels.Custom[k] != app.Labels[k]:
+			l.add(SeverityWarning, "labels."+k, "overrides the org value %q with %q", org.Labels.Custom[k], app.Labels[k])
+		}
+	}
+	for _, k := range sortedKeys(app.Annotations) {
+		if err := generator.ValidateMetadataKey(strings.TrimSpace(k)); err != nil {
+			l.add(SeverityError, "annotations."+k, "%v", err)
+		}
+	}
+
+	jobs := map[string]bool{}
+	for i, job := range app.Jobs {
+		field := fmt.Sprintf("jobs[%d]", i)
+		if err := generator.ValidateJob(job.Name, job.Command, job.Hook, job.Schedule); err != nil {
+			l.add(SeverityError, field, "%v", err)
+		} else if jobs[job.Name] {
+			l.add(SeverityError, field+".name", "duplicate job name %q", job.Name)
+		}
+		jobs[job.Name] = true
+	}
+
+	if s := app.Security; s != nil {
+		l.security("security", s.RuntimeClass, s.AppArmorProfile, s.PodSecurityStandard)
+		if sp := s.SeccompProfile; sp != nil {
+			if err := generator.ValidateSeccompProfile(sp.Type, sp.LocalhostProfile); err != nil {
+				l.add(SeverityError, "security.seccomp_profile", "%v", err)
+			}
+		}
+	}
+
+	if s := app.SLO; s != nil {
+		l.slo(s, org)
+	}
+
+	containers := map[string]bool{app.App.Name: true}
+	for i, c := range app.Containers {
+		field := fmt.Sprintf("containers[%d]", i)
+		switch {
+		case !generator.IsResourceName(c.Name):
+			l.add(SeverityError, field+".name", "%q must be lowercase alphanumerics and '-'", c.Name)
+		case containers[c.Name]:
+			l.add(SeverityError, field+".name", "%q is already used by another container", c.Name)
+		}
+		containers[c.Name] = true
+		if strings.TrimSpace(c.Image) == "" {
+			l.add(SeverityError, field+".image", "is required")
+		}
+		for j, p := range c.Ports {
+			if p.Port < 1 || p.Port > 65535 {
+				l.add(SeverityError, fmt.Sprintf("%s.ports[%d].port", field, j), "must be between 1 and 65535, got %d", p.Port)
+			}
+		}
+		if r := c.Resources; r != nil {
+			l.resources(field+".resources", config.ResourceSpec{Requests: r.Requests, Limits: r.Limits})
+		}
+	}
+}
+
+// service checks the app's Service type, presets, and static IP
+func (l *linter) service(app *config.AppConfig, org *config.Config) {
+	svc := app.Service
+	switch svc.Type {
+	case "", "ClusterIP", "NodePort", "LoadBalancer":
+	default:
+		l.add(SeverityError, "service.type", "%q is not one of ClusterIP, NodePort, LoadBalancer", svc.Type)
+	}
+	if svc.Type == "LoadBalancer" || svc.Type == "NodePort" {
+		if n := app.Networking; n != nil && (n.Expose == config.ExposeInternal || n.Expose == config.ExposeHeadless) {
+			l.add(SeverityError, "service.type", "%s conflicts with networking.expose %s", svc.Type, n.Expose)
+		}
+	}
+	if svc.Type != "LoadBalancer" {
+		if len(svc.Presets) > 0 {
+			l.add(SeverityWarning, "service.presets", "load balancer presets have no effect unless service.type is LoadBalancer")
+		}
+		if svc.StaticIP != "" {
+			l.add(SeverityError, "service.static_ip", "requires service.type LoadBalancer")
+		}
+	}
+	for i, name := range svc.Presets {
+		if _, ok := generator.ServicePreset(name, org); !ok {
+			l.add(SeverityError, fmt.Sprintf("service.presets[%d]", i), "unknown preset %q; define it under service.presets in the org config", name)
+		}
+	}
+	if svc.StaticIP != "" && net.ParseIP(svc.StaticIP) == nil {
+		l.add(SeverityError, "service.static_ip", "%q is not an IP address", svc.StaticIP)
+	}
+}
+
+func (l *linter) secretsProvider(field, provider string) {
+	switch provider {
+	case config.SecretsKubernetes, config.SecretsVaultAgent, config.SecretsCSI:
+	default:
+		l.add(SeverityError, field, "%q is not one of kubernetes, vault-agent, csi", provider)
+	}
+}
+
+func (l *linter) org(cfg *config.Config) {
+	l.resources("resources.defaults", cfg.Resources.Defaults)
+	for _, name := range sortedKeys(cfg.Resources.Profiles) {
+		l.resources("resources.profiles."+name, cfg.Resources.Profiles[name])
+	}
+	allowed := cfg.Validation.Ingress.DomainSuffixes
+	for i, suffix := range allowed {
+		if msg := checkHost(strings.TrimPrefix(suffix, ".")); msg != "" {
+			l.add(SeverityError, fmt.Sprintf("validation.ingress.domain_suffixes[%d]", i), "%q %s", suffix, msg)
+		}
+	}
+	if suffix := cfg.Ingress.DomainSuffix; suffix != "" {
+		if !strings.HasPrefix(suffix, ".") {
+			l.add(SeverityError, "ingress.domain_suffix", "%q must start with a dot, e.g. .apps.example.com", suffix)
+		} else if msg := checkHost(strings.TrimPrefix(suffix, ".")); msg != "" {
+			l.add(SeverityError, "ingress.domain_suffix", "%q %s", suffix, msg)
+		} else if len(allowed) > 0 && !generator.HostInDomains("app"+suffix, allowed) {
+			l.add(SeverityWarning, "ingress.domain_suffix", "%q is not under validation.ingress.domain_suffixes, so default hosts fail validation", suffix)
+		}
+	}
+	switch cfg.Validation.FailOn {
+	case "", config.FailOnError, config.FailOnWarning:
+	default:
+		l.add(SeverityError, "validation.fail_on", "%q is not one of error, warning", cfg.Validation.FailOn)
+	}
+	switch cfg.HPA.Replicas {
+	case "", config.HPAReplicasOmit, config.HPAReplicasIgnore, config.HPAReplicasKeep:
+	default:
+		l.add(SeverityError, "hpa.replicas", "%q is not one of omit, ignore, keep", cfg.HPA.Replicas)
+	}
+	switch cfg.Metrics.Scrape {
+	case "", config.MetricsScrapeAnnotations, config.MetricsScrapeServiceMonitor:
+	default:
+		l.add(SeverityError, "metrics.scrape", "%q is not one of annotations, servicemonitor", cfg.Metrics.Scrape)
+	}
+	if p := cfg.Metrics.Port; p < 0 || p > 65535 {
+		l.add(SeverityError, "metrics.port", "%d is not a valid port", p)
+	}
+	if cfg.Secrets.Provider != "" {
+		l.secretsProvider("secrets.provider", cfg.Secrets.Provider)
+		if cfg.Secrets.Provider == config.SecretsCSI && cfg.Secrets.Vault.Address == "" {
+			l.add(SeverityError, "secrets.vault.address", "required when secrets.provider is csi")
+		}
+	}
+	seenEnv := make(map[string]bool)
+	for i, v := range cfg.Env.Standard.Vars {
+		field := fmt.Sprintf("env.standard.vars[%d]", i)
+		switch {
+		case !envName.MatchString(v.Name):
+			l.add(SeverityError, field+".name", "%q is not a valid environment variable name", v.Name)
+		case seenEnv[v.Name]:
+			l.add(SeverityWarning, field+".name", "%q is defined more than once; the first wins", v.Name)
+		}
+		seenEnv[v.Name] = true
+	}
+	for _, k := range sortedKeys(cfg.Labels.Custom) {
+		if err := generator.ValidateLabel(strings.TrimSpace(k), strings.TrimSpace(cfg.Labels.Custom[k])); err != nil {
+			l.add(SeverityError, "labels.custom."+k, "%v", err)
+		} else if cfg.Labels.Custom[k] == "" {
+			l.add(SeverityWarning, "labels.custom."+k, "has an empty value")
+		}
+	}
+	for _, k := range sortedKeys(cfg.Annotations.Custom) {
+		if err := generator.ValidateMetadataKey(strings.TrimSpace(k)); err != nil {
+			l.add(SeverityError, "annotations.custom."+k, "%v", err)
+		}
+	}
+	if err := generator.ValidateSLOFormat(cfg.SLO.Format); err != nil {
+		l.add(SeverityError, "slo.format", "%v", err)
+	}
+	if err := owners.ValidateSource(cfg.Owners.Source); err != nil {
+		l.add(SeverityError, "owners.source", "%v", err)
+	} else if cfg.Owners.TokenEnv != "" && !strings.Contains(cfg.Owners.Source, "://") {
+		l.add(SeverityWarning, "owners.token_env", "only used when owners.source is a URL")
+	}
+	switch cfg.CI.GitHub.Mode {
+	case "", config.CIModeFull:
+	case config.CIModeReusable:
+		if _, err := generator.ReusableWorkflow(cfg); err != nil {
+			l.add(SeverityError, "ci.github.workflow", "%v", err)
+		}
+	default:
+		l.add(SeverityError, "ci.github.mode", "%q is not one of full, reusable", cfg.CI.GitHub.Mode)
+	}
+	if err := generator.ValidateDependencyUpdates(cfg.CI.DependencyUpdates); err != nil {
+		l.add(SeverityError, "ci.dependency_updates", "%v", err)
+	}
+	if err := generator.ValidateDevFiles(cfg.Dev.Files); err != nil {
+		l.add(SeverityError, "dev.files", "%v", err)
+	}
+	if err := generator.ValidateSmokeTestRoutes(cfg.SmokeTest.Routes); err != nil {
+		l.add(SeverityError, "smoke_test.routes", "%v", err)
+	}
+	for i, n := range cfg.Notifications {
+		field := fmt.Sprintf("notifications[%d]", i)
+		switch n.Type {
+		case config.NotificationSlack, config.NotificationTeams:
+		default:
+			l.add(SeverityError, field+".type", "%q is not one of slack, teams", n.Type)
+		}
+		switch {
+		case n.WebhookURL == "" && n.WebhookURLEnv == "":
+			l.add(SeverityError, field, "needs webhook_url or webhook_url_env")
+		case n.WebhookURL != "":
+			l.add(SeverityWarning, field+".webhook_url", "webhook URLs are secrets; set webhook_url_env and keep the URL out of the repository")
+		}
+		if n.Channel != "" && n.Type == config.NotificationTeams {
+			l.add(SeverityWarning, field+".channel", "ignored for teams; Teams webhooks post to the channel they were created for")
+		}
+	}
+	if err := analyzer.ValidateDisabled(cfg.Analyzers.Disabled); err != nil {
+		l.add(SeverityError, "analyzers.disabled", "%v", err)
+	}
+	l.security("security", cfg.Security.RuntimeClass, cfg.Security.AppArmorProfile, cfg.Security.PodSecurityStandard)
+	if sp := cfg.Security.PodSecurityContext.SeccompProfile; sp != nil {
+		if err := generator.ValidateSeccompProfile(sp.Type, sp.LocalhostProfile); err != nil {
+			l.add(SeverityError, "security.pod_security_context.seccomp_profile", "%v", err)
+		}
+	}
+}
+
+// slo checks the app's objectives, window, and format
+func (l *linter) slo(s *config.AppSLO, org *config.Config) {
+	if s.Availability == 0 && s.Latency == nil {
+		l.add(SeverityError, "slo", "needs an availability or a latency objective")
+	}
+	if s.Availability != 0 {
+		if err := generator.ValidateSLOTarget(s.Availability); err != nil {
+			l.add(SeverityError, "slo.availability", "%v", err)
+		}
+	}
+	if s.Latency != nil {
+		if err := generator.ValidateLatencyThreshold(s.Latency.Threshold); err != nil {
+			l.add(SeverityError, "slo.latency.threshold", "%v", err)
+		}
+		if err := generator.ValidateSLOTarget(s.Latency.Target); err != nil {
+			l.add(SeverityError, "slo.latency.target", "%v", err)
+		}
+	}
+	if s.Window != "" {
+		if _, err := generator.ParseSLOWindow(s.Window); err != nil {
+			l.add(SeverityError, "slo.window", "%v", err)
+		}
+	}
+	if err := generator.ValidateSLOFormat(s.Format); err != nil {
+		l.add(SeverityError, "slo.format", "%v", err)
+	}
+	format := s.Format
+	if format == "" {
+		format = org.SLO.Format
+	}
+	if format == config.SLOFormatSloth && s.Window != "" && s.Window != generator.DefaultSLOWindow {
+		l.add(SeverityWarning, "slo.window", "Sloth measures SLOs over its --default-slo-period (30d), not %s", s.Window)
+	}
+}
+
+// security checks a runtime class, AppArmor profile, and Pod Security
+// Standard level
+func (l *linter) security(field, runtimeClass, appArmor, level string) {
+	if err := generator.ValidatePodSecurityStandard(level); err != nil {
+		l.add(SeverityError, field+".pod_security_standard", "%v", err)
+	}
+	if err := generator.ValidateRuntimeClass(runtimeClass); err != nil {
+		l.add(SeverityError, field+".runtime_class", "%v", err)
+	}
+	if err := generator.ValidateAppArmorProfile(appArmor); err != nil {
+		l.add(SeverityError, field+".apparmor_profile", "%v", err)
+	}
+}
+
+// scalingRules checks HPA scaling rules against the API's limits
+func (l *linter) scalingRules(field string, r *config.AppScalingRules) {
+	if r == nil {
+		return
+	}
+	if w := r.StabilizationWindowSeconds; w != nil && (*w < 0 || *w > 3600) {
+		l.add(SeverityError, field+".stabilization_window_seconds", "must be between 0 and 3600, got %d", *w)
+	}
+	switch r.SelectPolicy {
+	case "", "Max", "Min", "Disabled":
+	default:
+		l.add(SeverityError, field+".select_policy", "%q is not one of Max, Min, Disabled", r.SelectPolicy)
+	}
+	for i, p := range r.Policies {
+		pf := fmt.Sprintf("%s.policies[%d]", field, i)
+		if p.Type != "Pods" && p.Type != "Percent" {
+			l.add(SeverityError, pf+".type", "%q is not one of Pods, Percent", p.Type)
+		}
+		if p.Value <= 0 {
+			l.add(SeverityError, pf+".value", "must be greater than 0, got %d", p.Value)
+		}
+		if p.PeriodSeconds <= 0 || p.PeriodSeconds > 1800 {
+			l.add(SeverityError, pf+".period_seconds", "must be between 1 and 1800, got %d", p.PeriodSeconds)
+		}
+	}
+}
+
+// resources checks that quantities parse and requests do not exceed limits
+func (l *linter) resources(field string, spec config.ResourceSpec) {
+	pairs := []struct {
+		name       string
+		req, limit string
+	}{
+		{"cpu", spec.Requests.CPU, spec.Limits.CPU},
+		{"memory", spec.Requests.Memory, spec.Limits.Memory},
+	}
+	for _, p := range pairs {
+		req, reqOK := l.quantity(field+".requests."+p.name, p.req)
+		limit, limitOK := l.quantity(field+".limits."+p.name, p.limit)
+		if reqOK && limitOK && req.Cmp(limit) > 0 {
+			l.add(SeverityError, field+".requests."+p.name, "request %s is greater than limit %s", p.req, p.limit)
+		}
+	}
+}
+
+func (l *linter) quantity(field, value string) (resource.Quantity, bool) {
+	if value == "" {
+		return resource.Quantity{}, false
+	}
+	name := "cpu"
+	if strings.HasSuffix(field, ".memory") {
+		name = "memory"
+	}
+	q, err := generator.ParseQuantity(name, value)
+	if err != nil {
+		l.add(SeverityError, field, "%v", err)
+		return q, false
+	}
+	return q, true
+}
+
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// checkHost returns why host is not a valid ingress host, or "" if it is
+func checkHost(host string) string {
+	switch {
+	case strings.Contains(host, "://"):
+		return "must be a host name without a scheme"
+	case strings.ContainsAny(host, ":/"):
+		return "must be a host name without a port or path"
+	case len(host) > 253:
+		return "is longer than 253 characters"
+	}
+	labels := strings.Split(strings.TrimPrefix(host, "*."), ".")
+	for _, label := range labels {
+		if !dnsLabel.MatchString(label) {
+			return "is not a valid DNS name (lowercase letters, digits, and '-' per label)"
+		}
+	}
+	if len(labels) < 2 {
+		return "should be a fully qualified domain name"
+	}
+	return ""
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
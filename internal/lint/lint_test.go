@@ -0,0 +1,171 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+)
+
+func TestFileApp(t *testing.T) {
+	org := &config.Config{}
+	org.Labels.Required = []string{"team"}
+	org.Labels.Custom = map[string]string{"cost-center": "eng"}
+	org.Service.Cloud = "gcp"
+
+	tests := []struct {
+		name  string
+		yaml  string
+		field string
+		sev   Severity
+	}{
+		{"clean", "app:\n  owner: team@example.com\n", "", ""},
+		{"min above max", "app:\n  owner: a@b.co\nscaling:\n  min_replicas: 5\n  max_replicas: 2\n", "scaling.min_replicas", SeverityError},
+		{"target out of range", "app:\n  owner: a@b.co\nscaling:\n  target_cpu: 150\n", "scaling.target_cpu", SeverityError},
+		{"requests above limits", "app:\n  owner: a@b.co\nresources:\n  requests:\n    memory: 2Gi\n  limits:\n    memory: 512Mi\n", "resources.requests.memory", SeverityError},
+		{"bad quantity", "app:\n  owner: a@b.co\nresources:\n  requests:\n    cpu: lots\n", "resources.requests.cpu", SeverityError},
+		{"host with scheme", "app:\n  owner: a@b.co\ningress:\n  host: https://orders.example.com\n", "ingress.host", SeverityError},
+		{"host uppercase", "app:\n  owner: a@b.co\ningress:\n  host: Orders.Example.com\n", "ingress.host", SeverityError},
+		{"hosts mixed forms", "app:\n  owner: a@b.co\ningress:\n  host: a.example.com\n  hosts:\n    - b.example.com\n    - host: \"*.example.com\"\n      tls_secret: wild\n", "", ""},
+		{"hosts duplicate", "app:\n  owner: a@b.co\ningress:\n  host: a.example.com\n  hosts: [a.example.com]\n", "ingress.hosts[0]", SeverityWarning},
+		{"hosts invalid", "app:\n  owner: a@b.co\ningress:\n  hosts:\n    - host: b.example.com:443\n", "ingress.hosts[0]", SeverityError},
+		{"path port out of range", "app:\n  owner: a@b.co\ningress:\n  paths:\n    - path: /admin\n      port: 70000\n", "ingress.paths[0].port", SeverityError},
+		{"expose internal", "app:\n  owner: a@b.co\nnetworking:\n  expose: internal\n", "", ""},
+		{"expose internal with ingress", "app:\n  owner: a@b.co\nnetworking:\n  expose: headless\ningress:\n  enabled: true\n", "ingress", SeverityWarning},
+		{"expose unknown", "app:\n  owner: a@b.co\nnetworking:\n  expose: private\n", "networking.expose", SeverityError},
+		{"service lb preset", "app:\n  owner: a@b.co\nservice:\n  type: LoadBalancer\n  presets: [internal]\n  static_ip: 10.0.0.5\n", "", ""},
+		{"service type unknown", "app:\n  owner: a@b.co\nservice:\n  type: External\n", "service.type", SeverityError},
+		{"service preset unknown", "app:\n  owner: a@b.co\nservice:\n  type: LoadBalancer\n  presets: [nlb]\n", "service.presets[0]", SeverityError},
+		{"service static ip needs lb", "app:\n  owner: a@b.co\nservice:\n  static_ip: 10.0.0.5\n", "service.static_ip", SeverityError},
+		{"service lb internal", "app:\n  owner: a@b.co\nnetworking:\n  expose: internal\nservice:\n  type: LoadBalancer\n", "service.type", SeverityError},
+		{"dns ndots out of range", "app:\n  owner: a@b.co\nnetworking:\n  dns:\n    ndots: 20\n", "networking.dns.ndots", SeverityError},
+		{"dns bad alias", "app:\n  owner: a@b.co\nnetworking:\n  dns:\n    host_aliases:\n      - ip: db\n        hostnames: [db.local]\n", "networking.dns.host_aliases[0].ip", SeverityError},
+		{"secrets vault agent", "app:\n  owner: a@b.co\nsecrets:\n  provider: vault-agent\n  role: orders\n", "", ""},
+		{"secrets unknown provider", "app:\n  owner: a@b.co\nsecrets:\n  provider: sops\n", "secrets.provider", SeverityError},
+		{"secrets csi without address", "app:\n  owner: a@b.co\nsecrets:\n  provider: csi\n", "secrets.provider", SeverityError},
+		{"missing owner", "app:\n  name: orders\n", "app.owner", SeverityWarning},
+		{"owner not email", "app:\n  owner: payments-team\n", "app.owner", SeverityWarning},
+		{"owner malformed", "app:\n  owner: a@@b\n", "app.owner", SeverityError},
+		{"required label", "app:\n  owner: a@b.co\nlabels:\n  team: other\n", "labels.team", SeverityError},
+		{"custom label override", "app:\n  owner: a@b.co\nlabels:\n  cost-center: ops\n", "labels.cost-center", SeverityWarning},
+		{"app name not dns-safe", "app:\n  name: My_App\n  owner: a@b.co\n", "app.name", SeverityError},
+		{"scaling behavior unknown", "app:\n  owner: a@b.co\nscaling:\n  behavior: fast\n", "scaling.behavior", SeverityError},
+		{"scaling policy period", "app:\n  owner: a@b.co\nscaling:\n  behavior: conservative\n  scale_down:\n    policies:\n      - type: Pods\n        value: 1\n        period_seconds: 3600\n", "scaling.scale_down.policies[0].period_seconds", SeverityError},
+		{"scaling metric pods", "app:\n  owner: a@b.co\nscaling:\n  metrics:\n    - type: pods\n      name: http_requests_per_second\n      average_value: \"100\"\n", "", ""},
+		{"scaling metric pods value", "app:\n  owner: a@b.co\nscaling:\n  metrics:\n    - type: pods\n      name: rps\n      value: \"100\"\n", "scaling.metrics[0]", SeverityError},
+		{"job", "app:\n  owner: a@b.co\njobs:\n  - name: migrate\n    command: [npm, run, migrate]\n    hook: pre-deploy\n", "", ""},
+		{"job schedule", "app:\n  owner: a@b.co\njobs:\n  - name: report\n    command: [bin/report]\n    schedule: \"0 3 * * *\"\n", "", ""},
+		{"job schedule invalid", "app:\n  owner: a@b.co\njobs:\n  - name: report\n    command: [bin/report]\n    schedule: \"0 3 * *\"\n", "jobs[0]", SeverityError},
+		{"job hook unknown", "app:\n  owner: a@b.co\njobs:\n  - name: migrate\n    command: [migrate]\n    hook: PreSync\n", "jobs[0]", SeverityError},
+		{"container", "app:\n  owner: a@b.co\ncontainers:\n  - name: nginx\n    image: nginx:1.27\n    ports:\n      - port: 80\n", "", ""},
+		{"container no image", "app:\n  owner: a@b.co\ncontainers:\n  - name: nginx\n", "containers[0].image", SeverityError},
+		{"security", "app:\n  owner: a@b.co\nsecurity:\n  runtime_class: gvisor\n  seccomp_profile:\n    type: Localhost\n    localhost_profile: profiles/audit.json\n  apparmor_profile: localhost/k8s-deny-write\n", "", ""},
+		{"pod security standard unknown", "app:\n  owner: a@b.co\nsecurity:\n  pod_security_standard: strict\n", "security.pod_security_standard", SeverityError},
+		{"seccomp localhost without profile", "app:\n  owner: a@b.co\nsecurity:\n  seccomp_profile:\n    type: Localhost\n", "security.seccomp_profile", SeverityError},
+		{"slo", "app:\n  owner: a@b.co\nslo:\n  availability: 99.9\n  latency:\n    threshold: 300ms\n    target: 99\n  window: 28d\n  format: openslo\n", "", ""},
+		{"slo latency target out of range", "app:\n  owner: a@b.co\nslo:\n  availability: 99.9\n  latency:\n    threshold: 300ms\n    target: 100\n", "slo.latency.target", SeverityError},
+		{"slo latency threshold", "app:\n  owner: a@b.co\nslo:\n  latency:\n    threshold: fast\n    target: 99\n", "slo.latency.threshold", SeverityError},
+		{"slo sloth window", "app:\n  owner: a@b.co\nslo:\n  availability: 99.5\n  window: 7d\n  format: sloth\n", "slo.window", SeverityWarning},
+		{"custom label same", "app:\n  owner: a@b.co\nlabels:\n  cost-center: eng\n", "", ""},
+		{"label key with space", "app:\n  owner: a@b.co\nlabels:\n  \"cost center\": eng\n", "labels.cost center", SeverityError},
+		{"label value invalid", "app:\n  owner: a@b.co\nlabels:\n  tier: \"front end\"\n", "labels.tier", SeverityError},
+		{"annotation bad prefix", "app:\n  owner: a@b.co\nannotations:\n  \"Acme_Corp/owner\": x\n", "annotations.Acme_Corp/owner", SeverityError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := File(".dorgu.yaml", []byte(tt.yaml), org)
+			if tt.field == "" {
+				if len(issues) > 0 {
+					t.Errorf("unexpected issues: %+v", issues)
+				}
+				return
+			}
+			if len(issues) != 1 || issues[0].Field != tt.field || issues[0].Severity != tt.sev {
+				t.Errorf("issues = %+v, want one %s on %s", issues, tt.sev, tt.field)
+			}
+		})
+	}
+}
+
+func TestFileOrg(t *testing.T) {
+	data := `org:
+  name: acme
+resources:
+  defaults:
+    requests:
+      cpu: "2"
+    limits:
+      cpu: 500m
+ingress:
+  domain_suffix: apps.example.com
+env:
+  standard:
+    vars:
+      - name: OTEL_SERVICE_NAME
+        value: "{app}"
+      - name: 1BAD
+      - name: OTEL_SERVICE_NAME
+owners:
+  source: ldap://ldap.example.com
+ci:
+  github:
+    mode: composite
+  dependency_updates: snyk
+notifications:
+  - type: slack
+    webhook_url_env: SLACK_WEBHOOK_URL
+  - type: discord
+    webhook_url: https://example.com/hook
+analyzers:
+  disabled: [terraform]
+validation:
+  fail_on: warnings
+  ingress:
+    domain_suffixes: [bad_domain]
+`
+	issues := File(".dorgu.yaml", []byte(data), nil)
+	fields := make([]string, 0, len(issues))
+	for _, i := range issues {
+		fields = append(fields, i.Field)
+	}
+	got := strings.Join(fields, ",")
+	if got != "resources.defaults.requests.cpu,validation.ingress.domain_suffixes[0],ingress.domain_suffix,validation.fail_on,env.standard.vars[1].name,env.standard.vars[2].name,owners.source,ci.github.mode,ci.dependency_updates,notifications[1].type,notifications[1].webhook_url,analyzers.disabled" {
+		t.Errorf("fields = %s", got)
+	}
+	if !HasErrors(issues) {
+		t.Error("expected errors")
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	for host, ok := range map[string]bool{
+		"orders.example.com":        true,
+		"*.apps.example.com":        true,
+		"orders":                    false,
+		"orders.example.com:8443":   false,
+		"orders.example.com/api":    false,
+		"-orders.example.com":       false,
+		"orders..example.com":       false,
+		"http://orders.example.com": false,
+	} {
+		if got := checkHost(host) == ""; got != ok {
+			t.Errorf("checkHost(%q) valid = %v, want %v", host, got, ok)
+		}
+	}
+}
+
+func TestCollisions(t *testing.T) {
+	issues := Collisions(map[string][]byte{
+		"a/.dorgu.yaml":   []byte("app:\n  name: My_App\n"),
+		"b/.dorgu.yaml":   []byte("app:\n  name: my-app\n"),
+		"c/.dorgu.yaml":   []byte("app:\n  name: orders\n"),
+		"org/.dorgu.yaml": []byte("org:\n  name: acme\n"),
+		"bad/.dorgu.yaml": []byte(":"),
+	})
+	if len(issues) != 2 || issues[0].File != "a/.dorgu.yaml" || issues[1].File != "b/.dorgu.yaml" {
+		t.Fatalf("issues = %+v, want one per colliding app", issues)
+	}
+	if !strings.Contains(issues[0].Message, "b/.dorgu.yaml") {
+		t.Errorf("message %q does not name the other app", issues[0].Message)
+	}
+}
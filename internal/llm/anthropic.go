@@ -1,39 +1,63 @@
 package llm
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/dorgu-ai/dorgu/internal/types"
 )
 
+const (
+	// DefaultAnthropicModel is the Claude model used when none is configured
+	DefaultAnthropicModel = "claude-sonnet-4-5"
+
+	anthropicBaseURL    = "https://api.anthropic.com/v1"
+	anthropicAPIVersion = "2023-06-01"
+)
+
 // AnthropicClient implements the Client interface for Anthropic Claude
 type AnthropicClient struct {
-	apiKey string
-	model  string
-	client *http.Client
+	apiKey  string
+	model   string
+	baseURL string
+	client  *http.Client
 }
 
-// NewAnthropicClient creates a new Anthropic client
+// NewAnthropicClient creates a new Anthropic client using the default model
 func NewAnthropicClient(apiKey string) *AnthropicClient {
+	return NewAnthropicClientWithModel(apiKey, DefaultAnthropicModel)
+}
+
+// NewAnthropicClientWithModel creates an Anthropic client with a specific model
+func NewAnthropicClientWithModel(apiKey, model string) *AnthropicClient {
+	if model == "" {
+		model = DefaultAnthropicModel
+	}
 	return &AnthropicClient{
-		apiKey: apiKey,
-		model:  "claude-3-sonnet-20240229",
-		client: &http.Client{Timeout: 60 * time.Second},
+		apiKey:  apiKey,
+		model:   model,
+		baseURL: anthropicBaseURL,
+		// No client-level timeout: deadlines come from the request context so
+		// long streaming responses are not cut off mid-stream.
+		client: &http.Client{},
 	}
 }
 
-// anthropicRequest represents a request to the Anthropic API
+// anthropicRequest represents a request to the Anthropic Messages API
 type anthropicRequest struct {
-	Model     string             `json:"model"`
-	MaxTokens int                `json:"max_tokens"`
-	System    string             `json:"system,omitempty"`
-	Messages  []anthropicMessage `json:"messages"`
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	Temperature *float64           `json:"temperature,omitempty"`
+	Stream      bool               `json:"stream,omitempty"`
 }
 
 type anthropicMessage struct {
@@ -41,24 +65,43 @@ type anthropicMessage struct {
 	Content string `json:"content"`
 }
 
-// anthropicResponse represents a response from the Anthropic API
+// anthropicResponse represents a response from the Anthropic Messages API
 type anthropicResponse struct {
 	Content []struct {
+		Type string `json:"type"`
 		Text string `json:"text"`
 	} `json:"content"`
-	Error *struct {
-		Message string `json:"message"`
-	} `json:"error,omitempty"`
+	Error *anthropicError `json:"error,omitempty"`
+}
+
+type anthropicError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// anthropicStreamEvent is a single server-sent event from a streaming response
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta *struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta,omitempty"`
+	Error *anthropicError `json:"error,omitempty"`
 }
 
 // AnalyzeApp uses Claude to analyze an application
-func (c *AnthropicClient) AnalyzeApp(analysis *types.AppAnalysis) (*types.AppAnalysis, error) {
+func (c *AnthropicClient) AnalyzeApp(ctx context.Context, analysis *types.AppAnalysis) (*types.AppAnalysis, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
 	prompt := buildAnalysisPrompt(analysis)
 
-	response, err := c.complete(
-		"You are an expert DevOps engineer analyzing containerized applications. Respond only with valid JSON, no markdown formatting.",
-		prompt,
-	)
+	temp := temperature(0.3)
+	response, err := c.complete(ctx, anthropicRequest{
+		System:      "You are an expert DevOps engineer analyzing containerized applications. Respond only with valid JSON, no markdown formatting.",
+		Messages:    []anthropicMessage{{Role: "user", Content: prompt}},
+		Temperature: &temp,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -75,47 +118,91 @@ func (c *AnthropicClient) AnalyzeApp(analysis *types.AppAnalysis) (*types.AppAna
 }
 
 // GeneratePersona generates an application persona document
-func (c *AnthropicClient) GeneratePersona(analysis *types.AppAnalysis) (string, error) {
+func (c *AnthropicClient) GeneratePersona(ctx context.Context, analysis *types.AppAnalysis) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
 	prompt := buildPersonaPrompt(analysis)
 
-	return c.complete(
-		"You are a technical writer creating documentation for platform engineers.",
-		prompt,
-	)
+	temp := temperature(0.5)
+	return c.complete(ctx, anthropicRequest{
+		System:      "You are a technical writer creating documentation for platform engineers.",
+		Messages:    []anthropicMessage{{Role: "user", Content: prompt}},
+		Temperature: &temp,
+	})
 }
 
-// Complete sends a generic prompt and returns the completion
+// Complete sends a generic prompt and returns the completion.
+// The request is cancelled when ctx is done.
 func (c *AnthropicClient) Complete(ctx context.Context, prompt string) (string, error) {
-	return c.complete("", prompt)
+	return c.complete(ctx, anthropicRequest{
+		Messages: []anthropicMessage{{Role: "user", Content: prompt}},
+	})
 }
 
-func (c *AnthropicClient) complete(system, prompt string) (string, error) {
-	reqBody := anthropicRequest{
-		Model:     c.model,
-		MaxTokens: 4096,
-		System:    system,
-		Messages: []anthropicMessage{
-			{Role: "user", Content: prompt},
-		},
-	}
-
-	jsonBody, err := json.Marshal(reqBody)
+// Stream sends a prompt using the Messages streaming API and calls onDelta with
+// each text fragment as it arrives. The full completion is returned at the end.
+func (c *AnthropicClient) Stream(ctx context.Context, prompt string, onDelta func(string)) (string, error) {
+	resp, err := c.do(ctx, anthropicRequest{
+		Messages: []anthropicMessage{{Role: "user", Content: prompt}},
+		Stream:   true,
+	})
 	if err != nil {
 		return "", err
 	}
+	defer resp.Body.Close()
 
-	req, err := http.NewRequest("POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return "", err
+	var sb strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "" {
+			continue
+		}
+
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			continue
+		}
+
+		switch event.Type {
+		case "content_block_delta":
+			if event.Delta != nil && event.Delta.Type == "text_delta" {
+				sb.WriteString(event.Delta.Text)
+				if onDelta != nil {
+					onDelta(event.Delta.Text)
+				}
+			}
+		case "error":
+			if event.Error != nil {
+				return sb.String(), fmt.Errorf("Anthropic API error: %s", event.Error.Message)
+			}
+			return sb.String(), fmt.Errorf("Anthropic API error in stream")
+		case "message_stop":
+			return sb.String(), nil
+		}
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", c.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
+	if err := scanner.Err(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return sb.String(), ctxErr
+		}
+		return sb.String(), fmt.Errorf("failed to read Anthropic stream: %w", err)
+	}
 
-	resp, err := c.client.Do(req)
+	return sb.String(), nil
+}
+
+// complete performs a non-streaming Messages API call and returns the text content
+func (c *AnthropicClient) complete(ctx context.Context, reqBody anthropicRequest) (string, error) {
+	resp, err := c.do(ctx, reqBody)
 	if err != nil {
-		return "", fmt.Errorf("Anthropic API request failed: %w", err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
@@ -124,10 +211,6 @@ func (c *AnthropicClient) complete(system, prompt string) (string, error) {
 		return "", err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("Anthropic API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
 	var anthropicResp anthropicResponse
 	if err := json.Unmarshal(body, &anthropicResp); err != nil {
 		return "", fmt.Errorf("failed to parse Anthropic response: %w", err)
@@ -137,11 +220,63 @@ func (c *AnthropicClient) complete(system, prompt string) (string, error) {
 		return "", fmt.Errorf("Anthropic API error: %s", anthropicResp.Error.Message)
 	}
 
-	if len(anthropicResp.Content) == 0 {
+	var sb strings.Builder
+	for _, block := range anthropicResp.Content {
+		if block.Type == "" || block.Type == "text" {
+			sb.WriteString(block.Text)
+		}
+	}
+	if sb.Len() == 0 {
 		return "", fmt.Errorf("no content in Anthropic response")
 	}
 
-	return anthropicResp.Content[0].Text, nil
+	return sb.String(), nil
+}
+
+// do sends a Messages API request and returns the raw response on HTTP 200
+func (c *AnthropicClient) do(ctx context.Context, reqBody anthropicRequest) (*http.Response, error) {
+	reqBody.Model = c.model
+	if reqBody.Temperature == nil && deterministic.enabled {
+		zero := 0.0
+		reqBody.Temperature = &zero
+	}
+	if reqBody.MaxTokens == 0 {
+		reqBody.MaxTokens = 4096
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/messages", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", anthropicAPIVersion)
+	if reqBody.Stream {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Anthropic API request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		var errResp anthropicResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != nil {
+			return nil, fmt.Errorf("Anthropic API error (status %d): %s", resp.StatusCode, errResp.Error.Message)
+		}
+		return nil, fmt.Errorf("Anthropic API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	return resp, nil
 }
 
 // extractJSON tries to extract JSON from a potentially markdown-wrapped response
@@ -0,0 +1,165 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// deterministic is set by SetDeterministic and read when clients are created
+var deterministic struct {
+	enabled  bool
+	cacheDir string
+}
+
+// SetDeterministic makes clients created afterwards sample at temperature 0
+// and answer repeated requests from a response cache, so identical inputs
+// produce identical output. An empty cacheDir uses DefaultCacheDir.
+func SetDeterministic(cacheDir string) error {
+	if cacheDir == "" {
+		dir, err := DefaultCacheDir()
+		if err != nil {
+			return err
+		}
+		cacheDir = dir
+	}
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return fmt.Errorf("failed to create LLM cache directory: %w", err)
+	}
+	deterministic.enabled = true
+	deterministic.cacheDir = cacheDir
+	return nil
+}
+
+// DefaultCacheDir is the LLM response cache under the user cache directory
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "dorgu", "llm"), nil
+}
+
+// temperature returns def, or 0 in deterministic mode
+func temperature[T float32 | float64](def T) T {
+	if deterministic.enabled {
+		return 0
+	}
+	return def
+}
+
+// cachingClient answers requests it has seen before from disk. Entries are
+// keyed by model, request kind, and the full request, so changing any of them
+// misses the cache.
+type cachingClient struct {
+	inner Client
+	dir   string
+	model string
+}
+
+// withCache wraps a client with the on-disk response cache in dir
+func withCache(client Client, dir, model string) Client {
+	return &cachingClient{inner: client, dir: dir, model: model}
+}
+
+func (c *cachingClient) AnalyzeApp(ctx context.Context, analysis *types.AppAnalysis) (*types.AppAnalysis, error) {
+	key := c.key("analyze", analysis)
+	var cached types.AppAnalysis
+	if c.load(key, &cached) {
+		return &cached, nil
+	}
+	result, err := c.inner.AnalyzeApp(ctx, analysis)
+	if err == nil {
+		c.store(key, result)
+	}
+	return result, err
+}
+
+func (c *cachingClient) GeneratePersona(ctx context.Context, analysis *types.AppAnalysis) (string, error) {
+	return c.text(c.key("persona", analysis), func() (string, error) {
+		return c.inner.GeneratePersona(ctx, analysis)
+	})
+}
+
+func (c *cachingClient) Complete(ctx context.Context, prompt string) (string, error) {
+	return c.text(c.key("complete", prompt), func() (string, error) {
+		return c.inner.Complete(ctx, prompt)
+	})
+}
+
+// Stream replays a cached completion as a single delta
+func (c *cachingClient) Stream(ctx context.Context, prompt string, onDelta func(string)) (string, error) {
+	key := c.key("complete", prompt)
+	var cached string
+	if c.load(key, &cached) {
+		if onDelta != nil {
+			onDelta(cached)
+		}
+		return cached, nil
+	}
+	var text string
+	var err error
+	if sc, ok := c.inner.(StreamingClient); ok {
+		text, err = sc.Stream(ctx, prompt, onDelta)
+	} else if text, err = c.inner.Complete(ctx, prompt); err == nil && onDelta != nil {
+		onDelta(text)
+	}
+	if err == nil {
+		c.store(key, text)
+	}
+	return text, err
+}
+
+func (c *cachingClient) text(key string, call func() (string, error)) (string, error) {
+	var cached string
+	if c.load(key, &cached) {
+		return cached, nil
+	}
+	text, err := call()
+	if err == nil {
+		c.store(key, text)
+	}
+	return text, err
+}
+
+func (c *cachingClient) key(kind string, request interface{}) string {
+	data, _ := json.Marshal(request)
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", c.model, kind)
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *cachingClient) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+func (c *cachingClient) load(key string, v interface{}) bool {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return false
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		slog.Warn("ignoring corrupt LLM cache entry", "path", c.path(key), "err", err)
+		return false
+	}
+	slog.Debug("LLM cache hit", "model", c.model, "key", key)
+	return true
+}
+
+func (c *cachingClient) store(key string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err == nil {
+		err = os.WriteFile(c.path(key), data, 0644)
+	}
+	if err != nil {
+		slog.Warn("failed to cache LLM response", "err", err)
+	}
+}
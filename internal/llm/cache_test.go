@@ -0,0 +1,80 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// countingClient answers every request and counts the calls that reach it
+type countingClient struct {
+	calls int
+	fail  bool
+}
+
+func (c *countingClient) AnalyzeApp(ctx context.Context, analysis *types.AppAnalysis) (*types.AppAnalysis, error) {
+	c.calls++
+	return &types.AppAnalysis{Name: analysis.Name, Framework: "express"}, nil
+}
+
+func (c *countingClient) GeneratePersona(ctx context.Context, analysis *types.AppAnalysis) (string, error) {
+	c.calls++
+	return "# " + analysis.Name, nil
+}
+
+func (c *countingClient) Complete(ctx context.Context, prompt string) (string, error) {
+	c.calls++
+	if c.fail {
+		return "", errors.New("unavailable")
+	}
+	return "answer to " + prompt, nil
+}
+
+func TestCachingClient(t *testing.T) {
+	inner := &countingClient{}
+	client := withCache(inner, t.TempDir(), "openai/gpt-4")
+	analysis := &types.AppAnalysis{Name: "orders"}
+
+	for i := 0; i < 2; i++ {
+		result, err := client.AnalyzeApp(context.Background(), analysis)
+		if err != nil || result.Framework != "express" {
+			t.Fatalf("AnalyzeApp() = %+v, %v", result, err)
+		}
+		persona, err := client.GeneratePersona(context.Background(), analysis)
+		if err != nil || persona != "# orders" {
+			t.Fatalf("GeneratePersona() = %q, %v", persona, err)
+		}
+		text, err := client.Complete(context.Background(), "ping")
+		if err != nil || text != "answer to ping" {
+			t.Fatalf("Complete() = %q, %v", text, err)
+		}
+	}
+	if inner.calls != 3 {
+		t.Errorf("inner client called %d times, want 3", inner.calls)
+	}
+
+	var streamed string
+	text, err := client.(StreamingClient).Stream(context.Background(), "ping", func(d string) { streamed += d })
+	if err != nil || text != "answer to ping" || streamed != text || inner.calls != 3 {
+		t.Errorf("Stream() = %q, %v (streamed %q, calls %d)", text, err, streamed, inner.calls)
+	}
+
+	if _, err := client.AnalyzeApp(context.Background(), &types.AppAnalysis{Name: "billing"}); err != nil || inner.calls != 4 {
+		t.Errorf("different request served from cache (calls %d)", inner.calls)
+	}
+}
+
+func TestCachingClientSkipsFailures(t *testing.T) {
+	inner := &countingClient{fail: true}
+	client := withCache(inner, t.TempDir(), "ollama/llama2")
+	for i := 0; i < 2; i++ {
+		if _, err := client.Complete(context.Background(), "ping"); err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	if inner.calls != 2 {
+		t.Errorf("failed response was cached (calls %d)", inner.calls)
+	}
+}
@@ -3,24 +3,64 @@ package llm
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 
+	"github.com/sashabaranov/go-openai"
+
 	"github.com/dorgu-ai/dorgu/internal/config"
 	"github.com/dorgu-ai/dorgu/internal/types"
 )
 
 // Client is the interface for LLM providers
 type Client interface {
-	AnalyzeApp(analysis *types.AppAnalysis) (*types.AppAnalysis, error)
-	GeneratePersona(analysis *types.AppAnalysis) (string, error)
+	AnalyzeApp(ctx context.Context, analysis *types.AppAnalysis) (*types.AppAnalysis, error)
+	GeneratePersona(ctx context.Context, analysis *types.AppAnalysis) (string, error)
 	Complete(ctx context.Context, prompt string) (string, error)
 }
 
+// StreamingClient is implemented by providers that can stream completions.
+// onDelta is called with each text fragment as it arrives.
+type StreamingClient interface {
+	Stream(ctx context.Context, prompt string, onDelta func(string)) (string, error)
+}
+
 // NewClient creates a new LLM client based on the provider name.
 // API key resolution: env var > global config (~/.config/dorgu/config.yaml).
+// The model comes from llm.model in the global config, falling back to the
+// provider default when unset. After SetDeterministic, responses are cached.
+// Every client redacts secrets (secret-classified
+// env var values plus the org's llm.redact_patterns) before prompts are sent.
 func NewClient(provider string) (Client, error) {
 	globalCfg, _ := config.LoadGlobalConfig()
+
+	var redactPatterns []string
+	if orgCfg, err := config.Load(); err == nil {
+		redactPatterns = orgCfg.LLM.RedactPatterns
+	}
+	redactor, err := NewRedactor(redactPatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newProviderClient(provider, globalCfg)
+	if err != nil {
+		return nil, err
+	}
+	if deterministic.enabled {
+		client = withCache(client, deterministic.cacheDir, provider+"/"+ModelName(provider))
+	}
+	slog.Debug("created LLM client", "provider", provider, "redact_patterns", len(redactPatterns))
+	return WithRedaction(client, redactor), nil
+}
+
+// newProviderClient creates the unwrapped client for a provider
+func newProviderClient(provider string, globalCfg *config.GlobalConfig) (Client, error) {
 	apiKey := resolveAPIKey(provider, globalCfg)
+	model := ""
+	if globalCfg != nil {
+		model = globalCfg.LLM.Model
+	}
 
 	switch provider {
 	case "openai":
@@ -33,7 +73,7 @@ func NewClient(provider string) (Client, error) {
 		if apiKey == "" {
 			return nil, fmt.Errorf("Anthropic API key not set. Set ANTHROPIC_API_KEY or run: dorgu config set llm.api_key <key>")
 		}
-		return NewAnthropicClient(apiKey), nil
+		return NewAnthropicClientWithModel(apiKey, model), nil
 
 	case "gemini":
 		if apiKey == "" {
@@ -53,6 +93,32 @@ func NewClient(provider string) (Client, error) {
 	}
 }
 
+const (
+	// DefaultGeminiModel is the Gemini model used by NewGeminiClient, fast and capable
+	DefaultGeminiModel = "gemini-2.5-flash"
+	// DefaultOllamaModel is the local model used by NewOllamaClient
+	DefaultOllamaModel = "llama2"
+)
+
+// ModelName returns the model NewClient uses for provider, or "" for an
+// unknown provider
+func ModelName(provider string) string {
+	switch provider {
+	case "openai":
+		return openai.GPT4TurboPreview
+	case "anthropic":
+		if globalCfg, _ := config.LoadGlobalConfig(); globalCfg != nil && globalCfg.LLM.Model != "" {
+			return globalCfg.LLM.Model
+		}
+		return DefaultAnthropicModel
+	case "gemini":
+		return DefaultGeminiModel
+	case "ollama":
+		return DefaultOllamaModel
+	}
+	return ""
+}
+
 // resolveAPIKey returns API key: env var takes precedence over global config
 func resolveAPIKey(provider string, globalCfg *config.GlobalConfig) string {
 	switch provider {
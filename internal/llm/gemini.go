@@ -1,9 +1,13 @@
 package llm
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/sashabaranov/go-openai"
@@ -11,73 +15,89 @@ import (
 	"github.com/dorgu-ai/dorgu/internal/types"
 )
 
-// GeminiClient implements the Client interface for Google Gemini
-// Uses Google's OpenAI-compatible endpoint
+const geminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+// GeminiClient implements the Client interface for Google Gemini.
+// Free-form completions use Google's OpenAI-compatible endpoint; analysis uses
+// the native generateContent API so responses are constrained by responseSchema.
 type GeminiClient struct {
-	client *openai.Client
-	model  string
+	client     *openai.Client
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
 }
 
 // NewGeminiClient creates a new Gemini client using Google's OpenAI-compatible API
 func NewGeminiClient(apiKey string) *GeminiClient {
-	// Google's OpenAI-compatible endpoint
-	config := openai.DefaultConfig(apiKey)
-	config.BaseURL = "https://generativelanguage.googleapis.com/v1beta/openai"
-
-	return &GeminiClient{
-		client: openai.NewClientWithConfig(config),
-		model:  "gemini-2.5-flash", // Fast and capable model
-	}
+	return NewGeminiClientWithModel(apiKey, DefaultGeminiModel)
 }
 
 // NewGeminiClientWithModel creates a Gemini client with a specific model
 func NewGeminiClientWithModel(apiKey, model string) *GeminiClient {
 	config := openai.DefaultConfig(apiKey)
-	config.BaseURL = "https://generativelanguage.googleapis.com/v1beta/openai"
+	config.BaseURL = geminiBaseURL + "/openai"
 
 	return &GeminiClient{
-		client: openai.NewClientWithConfig(config),
-		model:  model,
+		client:     openai.NewClientWithConfig(config),
+		apiKey:     apiKey,
+		model:      model,
+		baseURL:    geminiBaseURL,
+		httpClient: &http.Client{},
 	}
 }
 
+// geminiContent is a single content entry in a generateContent request/response
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+// geminiGenerateRequest is the native generateContent request body
+type geminiGenerateRequest struct {
+	SystemInstruction *geminiContent         `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent        `json:"contents"`
+	GenerationConfig  geminiGenerationConfig `json:"generationConfig"`
+}
+
+type geminiGenerationConfig struct {
+	Temperature      float64                `json:"temperature"`
+	ResponseMimeType string                 `json:"responseMimeType,omitempty"`
+	ResponseSchema   map[string]interface{} `json:"responseSchema,omitempty"`
+}
+
+// geminiGenerateResponse is the native generateContent response body
+type geminiGenerateResponse struct {
+	Candidates []struct {
+		Content geminiContent `json:"content"`
+	} `json:"candidates"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
 // AnalyzeApp uses Gemini to analyze an application
-func (c *GeminiClient) AnalyzeApp(analysis *types.AppAnalysis) (*types.AppAnalysis, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+func (c *GeminiClient) AnalyzeApp(ctx context.Context, analysis *types.AppAnalysis) (*types.AppAnalysis, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	prompt := buildAnalysisPrompt(analysis)
 
-	// Note: Gemini's OpenAI-compatible API may not support ResponseFormat,
-	// so we rely on the prompt to request JSON output
-	resp, err := c.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model: c.model,
-		Messages: []openai.ChatCompletionMessage{
-			{
-				Role:    openai.ChatMessageRoleSystem,
-				Content: "You are an expert DevOps engineer analyzing containerized applications to generate Kubernetes deployment configurations. You MUST respond with valid JSON only. No markdown, no code blocks, no explanations - just the raw JSON object.",
-			},
-			{
-				Role:    openai.ChatMessageRoleUser,
-				Content: prompt,
-			},
-		},
-		Temperature: 0.3,
-	})
-
+	responseContent, err := c.generateJSON(ctx,
+		"You are an expert DevOps engineer analyzing containerized applications to generate Kubernetes deployment configurations.",
+		prompt,
+		toGeminiSchema(analysisResponseSchema()),
+	)
 	if err != nil {
-		return nil, fmt.Errorf("Gemini API error: %w", err)
+		return nil, err
 	}
 
-	if len(resp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from Gemini")
-	}
-
-	// Parse the response
+	// Parse the response; extractJSON remains as a safety net for older models
 	var result types.AppAnalysis
-	responseContent := resp.Choices[0].Message.Content
-
-	// Try to extract JSON if wrapped in markdown
 	jsonStr := extractJSON(responseContent)
 
 	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
@@ -87,9 +107,68 @@ func (c *GeminiClient) AnalyzeApp(analysis *types.AppAnalysis) (*types.AppAnalys
 	return &result, nil
 }
 
+// generateJSON calls the native generateContent API with JSON response mode and
+// the given response schema, returning the raw JSON text.
+func (c *GeminiClient) generateJSON(ctx context.Context, system, prompt string, schema map[string]interface{}) (string, error) {
+	reqBody := geminiGenerateRequest{
+		SystemInstruction: &geminiContent{Parts: []geminiPart{{Text: system}}},
+		Contents:          []geminiContent{{Role: "user", Parts: []geminiPart{{Text: prompt}}}},
+		GenerationConfig: geminiGenerationConfig{
+			Temperature:      temperature(0.3),
+			ResponseMimeType: "application/json",
+			ResponseSchema:   schema,
+		},
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	// The key goes in a header: request errors quote the URL
+	endpoint := fmt.Sprintf("%s/models/%s:generateContent", c.baseURL, c.model)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Gemini API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var geminiResp geminiGenerateResponse
+	if err := json.Unmarshal(body, &geminiResp); err != nil {
+		return "", fmt.Errorf("failed to parse Gemini response (status %d): %w", resp.StatusCode, err)
+	}
+	if geminiResp.Error != nil {
+		return "", fmt.Errorf("Gemini API error (status %d): %s", resp.StatusCode, geminiResp.Error.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Gemini API error (status %d): %s", resp.StatusCode, string(body))
+	}
+	if len(geminiResp.Candidates) == 0 {
+		return "", fmt.Errorf("no response from Gemini")
+	}
+
+	var sb strings.Builder
+	for _, part := range geminiResp.Candidates[0].Content.Parts {
+		sb.WriteString(part.Text)
+	}
+	return sb.String(), nil
+}
+
 // GeneratePersona generates an application persona document
-func (c *GeminiClient) GeneratePersona(analysis *types.AppAnalysis) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+func (c *GeminiClient) GeneratePersona(ctx context.Context, analysis *types.AppAnalysis) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	prompt := buildPersonaPrompt(analysis)
@@ -106,7 +185,7 @@ func (c *GeminiClient) GeneratePersona(analysis *types.AppAnalysis) (string, err
 				Content: prompt,
 			},
 		},
-		Temperature: 0.5,
+		Temperature: openAITemperature(0.5),
 	})
 
 	if err != nil {
@@ -130,6 +209,7 @@ func (c *GeminiClient) Complete(ctx context.Context, prompt string) (string, err
 				Content: prompt,
 			},
 		},
+		Temperature: openAITemperature(0),
 	})
 
 	if err != nil {
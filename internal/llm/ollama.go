@@ -23,7 +23,7 @@ type OllamaClient struct {
 func NewOllamaClient(host string) *OllamaClient {
 	return &OllamaClient{
 		host:   host,
-		model:  "llama2",                                 // Default model, can be configured
+		model:  DefaultOllamaModel,
 		client: &http.Client{Timeout: 120 * time.Second}, // Longer timeout for local inference
 	}
 }
@@ -34,7 +34,10 @@ type ollamaRequest struct {
 	Prompt string `json:"prompt"`
 	System string `json:"system,omitempty"`
 	Stream bool   `json:"stream"`
-	Format string `json:"format,omitempty"` // "json" for JSON output
+	// Format is either "json" or a JSON schema object for structured output
+	Format interface{} `json:"format,omitempty"`
+	// Options are model parameters such as temperature and seed
+	Options map[string]interface{} `json:"options,omitempty"`
 }
 
 // ollamaResponse represents a response from the Ollama API
@@ -45,13 +48,13 @@ type ollamaResponse struct {
 }
 
 // AnalyzeApp uses Ollama to analyze an application
-func (c *OllamaClient) AnalyzeApp(analysis *types.AppAnalysis) (*types.AppAnalysis, error) {
+func (c *OllamaClient) AnalyzeApp(ctx context.Context, analysis *types.AppAnalysis) (*types.AppAnalysis, error) {
 	prompt := buildAnalysisPrompt(analysis)
 
-	response, err := c.complete(
+	response, err := c.complete(ctx,
 		"You are an expert DevOps engineer analyzing containerized applications. Respond only with valid JSON.",
 		prompt,
-		true, // JSON format
+		analysisResponseSchema(), // structured output constrained by schema
 	)
 	if err != nil {
 		return nil, err
@@ -69,31 +72,33 @@ func (c *OllamaClient) AnalyzeApp(analysis *types.AppAnalysis) (*types.AppAnalys
 }
 
 // GeneratePersona generates an application persona document
-func (c *OllamaClient) GeneratePersona(analysis *types.AppAnalysis) (string, error) {
+func (c *OllamaClient) GeneratePersona(ctx context.Context, analysis *types.AppAnalysis) (string, error) {
 	prompt := buildPersonaPrompt(analysis)
 
-	return c.complete(
+	return c.complete(ctx,
 		"You are a technical writer creating documentation for platform engineers.",
 		prompt,
-		false, // Markdown output
+		nil, // Markdown output
 	)
 }
 
 // Complete sends a generic prompt and returns the completion
 func (c *OllamaClient) Complete(ctx context.Context, prompt string) (string, error) {
-	return c.complete("", prompt, false)
+	return c.complete(ctx, "", prompt, nil)
 }
 
-func (c *OllamaClient) complete(system, prompt string, jsonFormat bool) (string, error) {
+// complete sends a generate request. format may be nil (free text), "json",
+// or a JSON schema map; Ollama 0.5+ constrains the output to the schema.
+func (c *OllamaClient) complete(ctx context.Context, system, prompt string, format interface{}) (string, error) {
 	reqBody := ollamaRequest{
 		Model:  c.model,
 		System: system,
 		Prompt: prompt,
 		Stream: false,
+		Format: format,
 	}
-
-	if jsonFormat {
-		reqBody.Format = "json"
+	if deterministic.enabled {
+		reqBody.Options = map[string]interface{}{"temperature": 0, "seed": 0}
 	}
 
 	jsonBody, err := json.Marshal(reqBody)
@@ -102,7 +107,7 @@ func (c *OllamaClient) complete(system, prompt string, jsonFormat bool) (string,
 	}
 
 	url := fmt.Sprintf("%s/api/generate", c.host)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return "", err
 	}
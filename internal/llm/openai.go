@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/sashabaranov/go-openai"
@@ -26,8 +27,8 @@ func NewOpenAIClient(apiKey string) *OpenAIClient {
 }
 
 // AnalyzeApp uses GPT to analyze an application
-func (c *OpenAIClient) AnalyzeApp(analysis *types.AppAnalysis) (*types.AppAnalysis, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+func (c *OpenAIClient) AnalyzeApp(ctx context.Context, analysis *types.AppAnalysis) (*types.AppAnalysis, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	prompt := buildAnalysisPrompt(analysis)
@@ -47,7 +48,7 @@ func (c *OpenAIClient) AnalyzeApp(analysis *types.AppAnalysis) (*types.AppAnalys
 		ResponseFormat: &openai.ChatCompletionResponseFormat{
 			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
 		},
-		Temperature: 0.3, // Lower temperature for more consistent output
+		Temperature: openAITemperature(0.3), // Lower temperature for more consistent output
 	})
 
 	if err != nil {
@@ -68,8 +69,8 @@ func (c *OpenAIClient) AnalyzeApp(analysis *types.AppAnalysis) (*types.AppAnalys
 }
 
 // GeneratePersona generates an application persona document
-func (c *OpenAIClient) GeneratePersona(analysis *types.AppAnalysis) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+func (c *OpenAIClient) GeneratePersona(ctx context.Context, analysis *types.AppAnalysis) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	prompt := buildPersonaPrompt(analysis)
@@ -86,7 +87,7 @@ func (c *OpenAIClient) GeneratePersona(analysis *types.AppAnalysis) (string, err
 				Content: prompt,
 			},
 		},
-		Temperature: 0.5,
+		Temperature: openAITemperature(0.5),
 	})
 
 	if err != nil {
@@ -110,6 +111,7 @@ func (c *OpenAIClient) Complete(ctx context.Context, prompt string) (string, err
 				Content: prompt,
 			},
 		},
+		Temperature: openAITemperature(0),
 	})
 
 	if err != nil {
@@ -123,6 +125,16 @@ func (c *OpenAIClient) Complete(ctx context.Context, prompt string) (string, err
 	return resp.Choices[0].Message.Content, nil
 }
 
+// openAITemperature returns the temperature for a request; 0 leaves the API
+// default outside deterministic mode. go-openai drops a zero temperature from
+// the request, so deterministic mode sends the smallest non-zero value.
+func openAITemperature(def float32) float32 {
+	if deterministic.enabled {
+		return math.SmallestNonzeroFloat32
+	}
+	return def
+}
+
 // buildAnalysisPrompt creates the prompt for application analysis
 func buildAnalysisPrompt(analysis *types.AppAnalysis) string {
 	// Build context from existing analysis
@@ -172,6 +184,21 @@ Code Analysis:
 			analysis.Code.HealthPath,
 			analysis.Code.MetricsPath,
 		)
+		if analysis.Code.JobFramework != "" {
+			codeInfo += fmt.Sprintf("- Background Jobs: runs %s workers, queued in %s (a worker, not an API)\n",
+				analysis.Code.JobFramework, analysis.Code.JobQueue)
+		}
+		if m := analysis.Migrations; m != nil {
+			codeInfo += fmt.Sprintf("- Database Migrations: %s (%s)\n", m.Tool, m.Source)
+		}
+		if analysis.Code.StaticBuild != "" {
+			codeInfo += fmt.Sprintf("- Static Build: built to static files with %s, no server framework (a web app)\n",
+				analysis.Code.StaticBuild)
+		}
+		if analysis.Code.ListenPort > 0 {
+			codeInfo += fmt.Sprintf("- Listen Port: %d (from %s; the app binds this port whatever the Dockerfile EXPOSEs)\n",
+				analysis.Code.ListenPort, analysis.Code.ListenPortSource)
+		}
 	}
 
 	// Include app config context if available
@@ -0,0 +1,180 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// RedactedValue replaces masked values in prompts
+const RedactedValue = "[REDACTED]"
+
+// builtinRedactPatterns mask well-known credential shapes regardless of config
+var builtinRedactPatterns = []string{
+	`AKIA[0-9A-Z]{16}`, // AWS access key IDs
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+	`(?i)bearer\s+[a-z0-9\-._~+/]+=*`, // bearer tokens
+	`(gh[pousr]_[A-Za-z0-9]{36,})`,    // GitHub tokens
+	`(sk-[A-Za-z0-9_\-]{20,})`,        // OpenAI-style API keys
+}
+
+// credentialURLPattern matches user:password@ in connection strings
+var credentialURLPattern = regexp.MustCompile(`(://[^:/@\s]+:)[^@\s]+@`)
+
+// secretNameMarkers classify env var names whose values must never leave the machine
+var secretNameMarkers = []string{
+	"PASSWORD", "PASSWD", "SECRET", "TOKEN", "API_KEY", "APIKEY", "ACCESS_KEY",
+	"PRIVATE_KEY", "CREDENTIAL", "AUTH", "DSN", "DATABASE_URL", "CONNECTION_STRING",
+}
+
+// Redactor masks secrets in analysis data and prompts before they are sent to an LLM
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor creates a redactor from the built-in patterns plus the org's
+// llm.redact_patterns (Go regular expressions).
+func NewRedactor(extraPatterns []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, p := range append(append([]string{}, builtinRedactPatterns...), extraPatterns...) {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid llm.redact_patterns entry %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// IsSecretEnvName reports whether an env var name looks like it holds a secret
+func IsSecretEnvName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, marker := range secretNameMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactText applies all redaction patterns to free text
+func (r *Redactor) RedactText(s string) string {
+	s = credentialURLPattern.ReplaceAllString(s, "${1}"+RedactedValue+"@")
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, RedactedValue)
+	}
+	return s
+}
+
+// RedactAnalysis returns a deep copy of the analysis with secret env var values
+// masked and redaction patterns applied to every string field.
+func (r *Redactor) RedactAnalysis(analysis *types.AppAnalysis) *types.AppAnalysis {
+	if analysis == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(analysis)
+	if err != nil {
+		return &types.AppAnalysis{Name: analysis.Name}
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return &types.AppAnalysis{Name: analysis.Name}
+	}
+	generic = r.walk(generic)
+	data, err = json.Marshal(generic)
+	if err != nil {
+		return &types.AppAnalysis{Name: analysis.Name}
+	}
+
+	var redacted types.AppAnalysis
+	if err := json.Unmarshal(data, &redacted); err != nil {
+		return &types.AppAnalysis{Name: analysis.Name}
+	}
+
+	maskEnvVars(redacted.EnvVars)
+	if redacted.Dockerfile != nil {
+		maskEnvVars(redacted.Dockerfile.EnvVars)
+	}
+	if redacted.Compose != nil {
+		for i := range redacted.Compose.Services {
+			maskEnvVars(redacted.Compose.Services[i].Environment)
+		}
+	}
+	return &redacted
+}
+
+// walk applies text redaction to every string in a decoded JSON value
+func (r *Redactor) walk(v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		return r.RedactText(val)
+	case []interface{}:
+		for i := range val {
+			val[i] = r.walk(val[i])
+		}
+		return val
+	case map[string]interface{}:
+		for k := range val {
+			val[k] = r.walk(val[k])
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+func maskEnvVars(envVars []types.EnvVar) {
+	for i := range envVars {
+		if envVars[i].Value == "" {
+			continue
+		}
+		if envVars[i].Secret || IsSecretEnvName(envVars[i].Name) {
+			envVars[i].Value = RedactedValue
+		}
+	}
+}
+
+// redactingClient wraps a Client so no unredacted data reaches the provider
+type redactingClient struct {
+	inner    Client
+	redactor *Redactor
+}
+
+// WithRedaction wraps a client so analysis data and prompts are redacted first
+func WithRedaction(client Client, redactor *Redactor) Client {
+	return &redactingClient{inner: client, redactor: redactor}
+}
+
+func (c *redactingClient) AnalyzeApp(ctx context.Context, analysis *types.AppAnalysis) (*types.AppAnalysis, error) {
+	return c.inner.AnalyzeApp(ctx, c.redactor.RedactAnalysis(analysis))
+}
+
+func (c *redactingClient) GeneratePersona(ctx context.Context, analysis *types.AppAnalysis) (string, error) {
+	return c.inner.GeneratePersona(ctx, c.redactor.RedactAnalysis(analysis))
+}
+
+func (c *redactingClient) Complete(ctx context.Context, prompt string) (string, error) {
+	return c.inner.Complete(ctx, c.redactor.RedactText(prompt))
+}
+
+// Stream streams from the wrapped client when supported, otherwise it falls
+// back to a single Complete call delivered as one delta.
+func (c *redactingClient) Stream(ctx context.Context, prompt string, onDelta func(string)) (string, error) {
+	prompt = c.redactor.RedactText(prompt)
+	if sc, ok := c.inner.(StreamingClient); ok {
+		return sc.Stream(ctx, prompt, onDelta)
+	}
+	text, err := c.inner.Complete(ctx, prompt)
+	if err == nil && onDelta != nil {
+		onDelta(text)
+	}
+	return text, err
+}
@@ -0,0 +1,91 @@
+package llm
+
+import "strings"
+
+// analysisResponseSchema returns the JSON schema describing the analysis
+// response requested by buildAnalysisPrompt. Providers with native structured
+// output (Gemini responseSchema, Ollama format) use it to constrain decoding so
+// the response parses without relying on extractJSON heuristics.
+func analysisResponseSchema() map[string]interface{} {
+	str := map[string]interface{}{"type": "string"}
+	integer := map[string]interface{}{"type": "integer"}
+
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name":        str,
+			"type":        map[string]interface{}{"type": "string", "enum": []string{"api", "web", "worker", "cron"}},
+			"language":    str,
+			"framework":   str,
+			"description": str,
+			"ports": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"port":     integer,
+						"protocol": str,
+						"purpose":  str,
+					},
+					"required": []string{"port"},
+				},
+			},
+			"health_check": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path":                  str,
+					"port":                  integer,
+					"initial_delay_seconds": integer,
+					"period_seconds":        integer,
+				},
+				"required": []string{"path", "port"},
+			},
+			"dependencies": map[string]interface{}{
+				"type":  "array",
+				"items": str,
+			},
+			"resource_profile": map[string]interface{}{"type": "string", "enum": []string{"api", "worker", "web"}},
+			"scaling": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"min_replicas":       integer,
+					"max_replicas":       integer,
+					"target_cpu_percent": integer,
+				},
+				"required": []string{"min_replicas", "max_replicas"},
+			},
+		},
+		"required": []string{"name", "type", "language", "description", "ports", "resource_profile"},
+	}
+}
+
+// toGeminiSchema converts a JSON schema into the OpenAPI subset accepted by
+// Gemini's responseSchema (upper-case type names).
+func toGeminiSchema(schema map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(schema))
+	for k, v := range schema {
+		switch val := v.(type) {
+		case string:
+			if k == "type" {
+				out[k] = strings.ToUpper(val)
+			} else {
+				out[k] = val
+			}
+		case map[string]interface{}:
+			if k == "properties" {
+				props := make(map[string]interface{}, len(val))
+				for name, prop := range val {
+					if p, ok := prop.(map[string]interface{}); ok {
+						props[name] = toGeminiSchema(p)
+					}
+				}
+				out[k] = props
+			} else {
+				out[k] = toGeminiSchema(val)
+			}
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
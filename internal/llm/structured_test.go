@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+const testAPIKey = "AIza-test-secret"
+
+// failingTransport fails every request without sending it
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestGeminiAnalyzeApp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("x-goog-api-key"); got != testAPIKey {
+			t.Errorf("x-goog-api-key = %q, want %q", got, testAPIKey)
+		}
+		if r.URL.RawQuery != "" {
+			t.Errorf("query = %q, want none", r.URL.RawQuery)
+		}
+		if r.URL.Path != "/models/gemini-test:generateContent" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		var req geminiGenerateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.GenerationConfig.ResponseMimeType != "application/json" || req.GenerationConfig.ResponseSchema["type"] != "OBJECT" {
+			t.Errorf("generation config = %+v", req.GenerationConfig)
+		}
+		w.Write([]byte(`{"candidates": [{"content": {"parts": [{"text": "{\"name\": \"orders\", \"type\": \"api\", \"framework\": \"express\"}"}]}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewGeminiClientWithModel(testAPIKey, "gemini-test")
+	client.baseURL = server.URL
+	result, err := client.AnalyzeApp(context.Background(), &types.AppAnalysis{Name: "orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "orders" || result.Type != "api" || result.Framework != "express" {
+		t.Errorf("AnalyzeApp() = %+v", result)
+	}
+}
+
+func TestGeminiRequestErrorOmitsKey(t *testing.T) {
+	client := NewGeminiClientWithModel(testAPIKey, "gemini-test")
+	client.httpClient = &http.Client{Transport: failingTransport{}}
+	_, err := client.AnalyzeApp(context.Background(), &types.AppAnalysis{Name: "orders"})
+	if err == nil {
+		t.Fatal("AnalyzeApp() succeeded over a failing transport")
+	}
+	if strings.Contains(err.Error(), testAPIKey) {
+		t.Errorf("error leaks the API key: %v", err)
+	}
+}
+
+func TestOllamaAnalyzeApp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/generate" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		var req struct {
+			Stream bool                   `json:"stream"`
+			Format map[string]interface{} `json:"format"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Stream || req.Format["type"] != "object" || req.Format["properties"] == nil {
+			t.Errorf("request stream %v, format %v, want the analysis schema", req.Stream, req.Format)
+		}
+		w.Write([]byte(`{"response": "{\"name\": \"orders\", \"type\": \"worker\"}", "done": true}`))
+	}))
+	defer server.Close()
+
+	result, err := NewOllamaClient(server.URL).AnalyzeApp(context.Background(), &types.AppAnalysis{Name: "orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "orders" || result.Type != "worker" {
+		t.Errorf("AnalyzeApp() = %+v", result)
+	}
+}
@@ -0,0 +1,138 @@
+// Package logging configures the process-wide slog logger. Library packages
+// log through slog's package-level functions; the CLI picks level and format.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Options controls the logger installed by Setup
+type Options struct {
+	// Level is the minimum level that is written
+	Level slog.Level
+	// JSON writes one JSON object per record instead of plain text
+	JSON bool
+}
+
+// Setup installs a logger writing to w as the slog default
+func Setup(w io.Writer, opts Options) {
+	slog.SetDefault(slog.New(NewHandler(w, opts)))
+}
+
+// LevelFromFlags maps -v/--verbose and --debug to a level: warnings by
+// default, info with -v, debug with -vv or --debug
+func LevelFromFlags(verbosity int, debug bool) slog.Level {
+	switch {
+	case debug || verbosity >= 2:
+		return slog.LevelDebug
+	case verbosity == 1:
+		return slog.LevelInfo
+	default:
+		return slog.LevelWarn
+	}
+}
+
+// NewHandler returns a JSON handler or a plain-text console handler
+func NewHandler(w io.Writer, opts Options) slog.Handler {
+	if opts.JSON {
+		return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: opts.Level})
+	}
+	return &consoleHandler{w: w, mu: &sync.Mutex{}, level: opts.Level}
+}
+
+// consoleHandler writes "Warning: message key=value ..." lines without
+// timestamps, matching the CLI's other terminal output
+type consoleHandler struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	level  slog.Level
+	attrs  string
+	prefix string
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	b.WriteString(levelLabel(r.Level))
+	b.WriteString(r.Message)
+	b.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(&b, h.prefix, a)
+		return true
+	})
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	b.WriteString(h.attrs)
+	for _, a := range attrs {
+		appendAttr(&b, h.prefix, a)
+	}
+	h2 := *h
+	h2.attrs = b.String()
+	return &h2
+}
+
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+func levelLabel(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "Error: "
+	case level >= slog.LevelWarn:
+		return "Warning: "
+	case level >= slog.LevelInfo:
+		return "info: "
+	default:
+		return "debug: "
+	}
+}
+
+// appendAttr writes " key=value", flattening groups into dotted keys and
+// quoting values that contain spaces or quotes
+func appendAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			appendAttr(b, groupPrefix, ga)
+		}
+		return
+	}
+	value := a.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	b.WriteByte(' ')
+	b.WriteString(prefix)
+	b.WriteString(a.Key)
+	b.WriteByte('=')
+	b.WriteString(value)
+}
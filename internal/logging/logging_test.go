@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestLevelFromFlags(t *testing.T) {
+	tests := []struct {
+		verbosity int
+		debug     bool
+		want      slog.Level
+	}{
+		{0, false, slog.LevelWarn},
+		{1, false, slog.LevelInfo},
+		{2, false, slog.LevelDebug},
+		{0, true, slog.LevelDebug},
+	}
+	for _, tt := range tests {
+		if got := LevelFromFlags(tt.verbosity, tt.debug); got != tt.want {
+			t.Errorf("LevelFromFlags(%d, %v) = %v, want %v", tt.verbosity, tt.debug, got, tt.want)
+		}
+	}
+}
+
+func TestConsoleHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, Options{Level: slog.LevelInfo}))
+
+	logger.Debug("hidden")
+	logger.With("provider", "ollama").WithGroup("compose").
+		Warn("parse failed", "path", "docker-compose.yml", "err", errors.New("bad indent"))
+
+	want := "Warning: parse failed provider=ollama compose.path=docker-compose.yml compose.err=\"bad indent\"\n"
+	if got := buf.String(); got != want {
+		t.Errorf("console output = %q, want %q", got, want)
+	}
+}
+
+func TestJSONHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, Options{Level: slog.LevelDebug, JSON: true}))
+
+	logger.Debug("connected", "url", "ws://localhost:9090/ws")
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if record["msg"] != "connected" || record["url"] != "ws://localhost:9090/ws" || record["level"] != "DEBUG" {
+		t.Errorf("unexpected record: %v", record)
+	}
+}
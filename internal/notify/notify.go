@@ -0,0 +1,163 @@
+// Package notify posts onboarding events (generate, persona apply) to Slack
+// and Microsoft Teams incoming webhooks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+)
+
+// Event is a generation or apply to report
+type Event struct {
+	// Action is what happened, e.g. "generated manifests" or "applied persona"
+	Action      string `json:"action"`
+	App         string `json:"app"`
+	Namespace   string `json:"namespace,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	// Validation summarizes the validation result, e.g. "passed" or
+	// "Validation: 1 error(s)"
+	Validation string `json:"validation,omitempty"`
+	// Failed marks a failed validation; the message is colored accordingly
+	Failed      bool   `json:"failed,omitempty"`
+	PullRequest string `json:"pullRequest,omitempty"`
+}
+
+// Title is the one-line summary of the event
+func (e Event) Title() string {
+	return fmt.Sprintf("dorgu %s for %s", e.Action, e.App)
+}
+
+// fields are the event's details in display order
+func (e Event) fields() [][2]string {
+	var f [][2]string
+	for _, kv := range [][2]string{
+		{"App", e.App},
+		{"Namespace", e.Namespace},
+		{"Environment", e.Environment},
+		{"Validation", e.Validation},
+		{"Pull request", e.PullRequest},
+	} {
+		if kv[1] != "" {
+			f = append(f, kv)
+		}
+	}
+	return f
+}
+
+// SlackPayload is the message posted to a Slack incoming webhook. channel
+// overrides the webhook's default channel where the workspace allows it.
+func SlackPayload(e Event, channel string) map[string]interface{} {
+	lines := []string{"*" + e.Title() + "*"}
+	for _, f := range e.fields() {
+		lines = append(lines, fmt.Sprintf("• *%s:* %s", f[0], f[1]))
+	}
+	color := "good"
+	if e.Failed {
+		color = "danger"
+	}
+	payload := map[string]interface{}{
+		"text": e.Title(),
+		"attachments": []map[string]interface{}{{
+			"color": color,
+			"text":  strings.Join(lines, "\n"),
+		}},
+	}
+	if channel != "" {
+		payload["channel"] = channel
+	}
+	return payload
+}
+
+// TeamsPayload is the MessageCard posted to a Teams incoming webhook; the
+// webhook decides the channel
+func TeamsPayload(e Event) map[string]interface{} {
+	facts := []map[string]string{}
+	for _, f := range e.fields() {
+		facts = append(facts, map[string]string{"name": f[0], "value": f[1]})
+	}
+	color := "2EB886"
+	if e.Failed {
+		color = "D00000"
+	}
+	return map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    e.Title(),
+		"title":      e.Title(),
+		"themeColor": color,
+		"sections":   []map[string]interface{}{{"facts": facts}},
+	}
+}
+
+// WebhookURL returns the target's webhook URL, read from WebhookURLEnv when
+// set
+func WebhookURL(t config.NotificationConfig) string {
+	if t.WebhookURLEnv != "" {
+		return os.Getenv(t.WebhookURLEnv)
+	}
+	return t.WebhookURL
+}
+
+// Send posts the event to every target, continuing past failures
+func Send(ctx context.Context, targets []config.NotificationConfig, e Event) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	var errs []error
+	for _, t := range targets {
+		if err := send(ctx, client, t, e); err != nil {
+			errs = append(errs, fmt.Errorf("%s notification: %w", t.Type, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func send(ctx context.Context, client *http.Client, t config.NotificationConfig, e Event) error {
+	webhook := WebhookURL(t)
+	if webhook == "" {
+		if t.WebhookURLEnv != "" {
+			return fmt.Errorf("%s is not set", t.WebhookURLEnv)
+		}
+		return fmt.Errorf("no webhook_url")
+	}
+	var payload interface{}
+	switch t.Type {
+	case config.NotificationSlack:
+		payload = SlackPayload(e, t.Channel)
+	case config.NotificationTeams:
+		payload = TeamsPayload(e)
+	default:
+		return fmt.Errorf("unknown type %q (supported: slack, teams)", t.Type)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		// The URL carries the webhook secret; keep it out of the error
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return uerr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
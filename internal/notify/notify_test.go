@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+)
+
+var testEvent = Event{
+	Action:      "generated manifests",
+	App:         "orders",
+	Namespace:   "shop",
+	Validation:  "Validation: 1 error(s)",
+	Failed:      true,
+	PullRequest: "https://github.com/acme/orders/pull/7",
+}
+
+func TestSlackPayload(t *testing.T) {
+	p := SlackPayload(testEvent, "#onboarding")
+	if p["text"] != "dorgu generated manifests for orders" || p["channel"] != "#onboarding" {
+		t.Errorf("payload = %v", p)
+	}
+	att := p["attachments"].([]map[string]interface{})[0]
+	if att["color"] != "danger" {
+		t.Errorf("color = %v, want danger", att["color"])
+	}
+	text := att["text"].(string)
+	for _, want := range []string{"• *Namespace:* shop", "• *Pull request:* https://github.com/acme/orders/pull/7"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Environment") {
+		t.Errorf("empty fields should be left out:\n%s", text)
+	}
+	if _, ok := SlackPayload(Event{App: "orders"}, "")["channel"]; ok {
+		t.Error("channel set without one configured")
+	}
+}
+
+func TestTeamsPayload(t *testing.T) {
+	p := TeamsPayload(testEvent)
+	if p["@type"] != "MessageCard" || p["themeColor"] != "D00000" {
+		t.Errorf("payload = %v", p)
+	}
+	facts := p["sections"].([]map[string]interface{})[0]["facts"].([]map[string]string)
+	if len(facts) != 4 || facts[0]["name"] != "App" || facts[0]["value"] != "orders" {
+		t.Errorf("facts = %v", facts)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var got []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		got = append(got, body)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("TEAMS_WEBHOOK", srv.URL+"/teams")
+
+	err := Send(context.Background(), []config.NotificationConfig{
+		{Type: config.NotificationSlack, WebhookURL: srv.URL + "/slack", Channel: "#onboarding"},
+		{Type: config.NotificationTeams, WebhookURLEnv: "TEAMS_WEBHOOK"},
+		{Type: config.NotificationSlack, WebhookURL: srv.URL + "/fail"},
+		{Type: config.NotificationSlack, WebhookURLEnv: "UNSET_WEBHOOK"},
+	}, testEvent)
+	if len(got) != 3 || got[0]["channel"] != "#onboarding" || got[1]["@type"] != "MessageCard" {
+		t.Errorf("posted %v", got)
+	}
+	if err == nil || !strings.Contains(err.Error(), "403 Forbidden") || !strings.Contains(err.Error(), "UNSET_WEBHOOK is not set") {
+		t.Errorf("err = %v", err)
+	}
+}
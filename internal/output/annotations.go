@@ -0,0 +1,160 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dorgu-ai/dorgu/internal/generator"
+)
+
+// InGitHubActions reports whether dorgu runs in a GitHub Actions job
+func InGitHubActions() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// AnnotationPaths locate the files validation issues refer to
+type AnnotationPaths struct {
+	// Root is the repository root annotation paths are relative to
+	Root string
+	// OutputDir holds the generated files
+	OutputDir string
+	// AppDir holds the app and its SuppressionsFile
+	AppDir string
+	// ConfigFiles are the .dorgu.yaml files searched for the issues' config
+	// keys, in order
+	ConfigFiles []string
+}
+
+// WriteGitHubAnnotations writes a GitHub Actions workflow command for each
+// validation issue, so it shows inline on pull requests: errors as ::error,
+// warnings as ::warning, and info as ::notice. Each points at the generated
+// file and, when the issue comes from a setting, another at its key in
+// .dorgu.yaml.
+func WriteGitHubAnnotations(w io.Writer, result *generator.ValidationResult, paths AnnotationPaths) {
+	configs := map[string][]byte{}
+	for _, path := range paths.ConfigFiles {
+		if data, err := os.ReadFile(path); err == nil {
+			configs[path] = data
+		}
+	}
+	for _, issue := range result.Issues {
+		command := annotationCommand(issue.Severity)
+		title := issue.Rule
+		if rule, ok := generator.LookupRule(issue.Rule); ok {
+			title = rule.ID + ": " + rule.Title
+		}
+		message := issue.Message
+		if issue.Suggestion != "" {
+			message += "\n" + issue.Suggestion
+		}
+		if issue.DocURL != "" {
+			message += "\n" + issue.DocURL
+		}
+
+		var file string
+		switch {
+		case issue.File == generator.SuppressionsFile:
+			file = filepath.Join(paths.AppDir, issue.File)
+		case strings.HasSuffix(issue.File, ".yaml"):
+			file = filepath.Join(paths.OutputDir, issue.File)
+		}
+		fmt.Fprintln(w, annotation(command, relativeTo(paths.Root, file), 0, title, message))
+
+		if issue.ConfigKey == "" {
+			continue
+		}
+		for _, path := range paths.ConfigFiles {
+			if line := configKeyLine(configs[path], issue.ConfigKey); line > 0 {
+				fmt.Fprintln(w, annotation(command, relativeTo(paths.Root, path), line, title, message))
+				break
+			}
+		}
+	}
+}
+
+func annotationCommand(severity generator.ValidationSeverity) string {
+	switch severity {
+	case generator.SeverityError:
+		return "error"
+	case generator.SeverityWarning:
+		return "warning"
+	}
+	return "notice"
+}
+
+// annotation renders a workflow command, escaping its properties and message
+func annotation(command, file string, line int, title, message string) string {
+	var props []string
+	if file != "" {
+		props = append(props, "file="+escapeProperty(file))
+		if line > 0 {
+			props = append(props, fmt.Sprintf("line=%d", line))
+		}
+	}
+	if title != "" {
+		props = append(props, "title="+escapeProperty(title))
+	}
+	prefix := "::" + command
+	if len(props) > 0 {
+		prefix += " " + strings.Join(props, ",")
+	}
+	return prefix + "::" + escapeData(message)
+}
+
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// relativeTo returns path relative to root, with forward slashes, or path
+// when it is not under root
+func relativeTo(root, path string) string {
+	if path == "" || root == "" {
+		return filepath.ToSlash(path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// configKeyLine returns the line of the dotted key in the YAML document data,
+// or of its deepest ancestor present, or 0 when not even its first segment
+// is there
+func configKeyLine(data []byte, key string) int {
+	var doc yaml.Node
+	if len(data) == 0 || yaml.Unmarshal(data, &doc) != nil || len(doc.Content) == 0 {
+		return 0
+	}
+	node, line := doc.Content[0], 0
+	for _, segment := range strings.Split(key, ".") {
+		if node.Kind != yaml.MappingNode {
+			break
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == segment {
+				line, next = node.Content[i].Line, node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return line
+}
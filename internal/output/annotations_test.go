@@ -0,0 +1,60 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/generator"
+)
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	root := t.TempDir()
+	app := filepath.Join(root, "services", "orders")
+	if err := os.MkdirAll(app, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(app, ".dorgu.yaml")
+	if err := os.WriteFile(config, []byte("app:\n  name: orders\ningress:\n  enabled: true\n  host: orders\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result := &generator.ValidationResult{Issues: []generator.ValidationIssue{
+		{Rule: generator.RuleIngressHostInvalid, Severity: generator.SeverityError, File: "ingress.yaml", Message: "Ingress host \"orders\" is not a fully qualified domain name", ConfigKey: "ingress.host", DocURL: "https://example.com/rules#dorgu-ing-002"},
+		{Rule: generator.RuleNoHealthProbes, Severity: generator.SeverityWarning, File: "deployment.yaml", Message: "No health probes configured", Suggestion: "Add health.liveness, 100%", ConfigKey: "health"},
+		{Rule: generator.RuleKubectlDryRunPassed, Severity: generator.SeverityInfo, File: "manifests", Message: "kubectl apply --dry-run=client passed"},
+	}}
+
+	var out strings.Builder
+	WriteGitHubAnnotations(&out, result, AnnotationPaths{
+		Root:        root,
+		OutputDir:   filepath.Join(app, "k8s"),
+		AppDir:      app,
+		ConfigFiles: []string{config},
+	})
+	want := strings.Join([]string{
+		`::error file=services/orders/k8s/ingress.yaml,title=DORGU-ING-002%3A Ingress host is not a fully qualified domain name::Ingress host "orders" is not a fully qualified domain name%0Ahttps://example.com/rules#dorgu-ing-002`,
+		`::error file=services/orders/.dorgu.yaml,line=5,title=DORGU-ING-002%3A Ingress host is not a fully qualified domain name::Ingress host "orders" is not a fully qualified domain name%0Ahttps://example.com/rules#dorgu-ing-002`,
+		`::warning file=services/orders/k8s/deployment.yaml,title=DORGU-HLT-001%3A No health probes::No health probes configured%0AAdd health.liveness, 100%25`,
+		`::notice title=DORGU-KUB-002%3A kubectl dry run passed::kubectl apply --dry-run=client passed`,
+		``,
+	}, "\n")
+	if out.String() != want {
+		t.Errorf("annotations:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestConfigKeyLine(t *testing.T) {
+	data := []byte("app:\n  name: orders\ningress:\n  enabled: true\n  tls:\n    enabled: true\n")
+	for key, want := range map[string]int{
+		"app.name":                   2,
+		"ingress.tls.enabled":        6,
+		"ingress.tls.cluster_issuer": 5,
+		"ingress.host":               3,
+		"health":                     0,
+	} {
+		if got := configKeyLine(data, key); got != want {
+			t.Errorf("configKeyLine(%q) = %d, want %d", key, got, want)
+		}
+	}
+}
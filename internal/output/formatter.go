@@ -2,9 +2,11 @@ package output
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 var (
@@ -16,9 +18,32 @@ var (
 	dimStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 )
 
+// messages receives human-readable status messages. It is switched to stderr
+// when a command emits structured output on stdout.
+var messages io.Writer = os.Stdout
+
+// quiet suppresses Info and Dim messages
+var quiet bool
+
+// SetMessageWriter redirects Success, Warn, Info, Dim, and Header output
+func SetMessageWriter(w io.Writer) {
+	messages = w
+}
+
+// SetQuiet suppresses informational and dimmed hint messages. Success,
+// warnings, errors, and command output are still printed.
+func SetQuiet(q bool) {
+	quiet = q
+}
+
+// DisableColor renders all styles as plain text
+func DisableColor() {
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
 // Success prints a success message
 func Success(msg string) {
-	fmt.Println(successStyle.Render("✓ " + msg))
+	fmt.Fprintln(messages, successStyle.Render("✓ "+msg))
 }
 
 // Error prints an error message
@@ -28,24 +53,30 @@ func Error(msg string) {
 
 // Warn prints a warning message
 func Warn(msg string) {
-	fmt.Println(warnStyle.Render("⚠ " + msg))
+	fmt.Fprintln(messages, warnStyle.Render("⚠ "+msg))
 }
 
 // Info prints an info message
 func Info(msg string) {
-	fmt.Println(infoStyle.Render("ℹ " + msg))
+	if quiet {
+		return
+	}
+	fmt.Fprintln(messages, infoStyle.Render("ℹ "+msg))
 }
 
 // Dim prints a dimmed message
 func Dim(msg string) {
-	fmt.Println(dimStyle.Render(msg))
+	if quiet {
+		return
+	}
+	fmt.Fprintln(messages, dimStyle.Render(msg))
 }
 
 // Header prints a header
 func Header(msg string) {
-	fmt.Println()
-	fmt.Println(lipgloss.NewStyle().Bold(true).Render(msg))
-	fmt.Println()
+	fmt.Fprintln(messages)
+	fmt.Fprintln(messages, lipgloss.NewStyle().Bold(true).Render(msg))
+	fmt.Fprintln(messages)
 }
 
 // Green returns a green-colored string
@@ -67,3 +98,18 @@ func Blue(msg string) string {
 func Red(msg string) string {
 	return errorStyle.Render(msg)
 }
+
+// streamStyles tell apart interleaved streams, e.g. the logs of several pods
+var streamStyles = []lipgloss.Style{
+	lipgloss.NewStyle().Foreground(lipgloss.Color("39")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("42")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("170")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("45")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("141")),
+}
+
+// Stream returns msg in the color of the i-th stream
+func Stream(i int, msg string) string {
+	return streamStyles[i%len(streamStyles)].Render(msg)
+}
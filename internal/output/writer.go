@@ -4,26 +4,152 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/dorgu-ai/dorgu/internal/generator"
 )
 
-// WriteFiles writes generated files to disk
-func WriteFiles(baseDir string, files []generator.GeneratedFile) error {
+// BackupSuffix is appended to the copy of a file WriteFiles overwrites when
+// WriteOptions.Backup is set
+const BackupSuffix = ".bak"
+
+// WriteOptions controls where and how WriteFiles writes
+type WriteOptions struct {
+	// AllowOutside lets files resolve outside baseDir (e.g. ../PERSONA.md);
+	// without it such files are rejected
+	AllowOutside bool
+	// Backup keeps the previous content of an overwritten file in
+	// <file>.bak
+	Backup bool
+}
+
+// WriteSummary lists the files WriteFiles created, updated, and left alone
+// because their content was already identical
+type WriteSummary struct {
+	Created   []string
+	Updated   []string
+	Unchanged []string
+}
+
+// Status returns created, updated, or unchanged for a written path
+func (s WriteSummary) Status(path string) string {
+	for _, p := range s.Created {
+		if p == path {
+			return "created"
+		}
+	}
+	for _, p := range s.Updated {
+		if p == path {
+			return "updated"
+		}
+	}
+	return "unchanged"
+}
+
+// WriteFiles writes generated files to disk. File paths are relative to
+// baseDir; absolute paths are always rejected. Files whose content is
+// already on disk are not rewritten, so their mtimes stay stable; a run
+// whose only change is its time keeps the generatedAt of the dorgu.lock in
+// baseDir for that reason.
+func WriteFiles(baseDir string, files []generator.GeneratedFile, opts WriteOptions) (WriteSummary, error) {
+	var summary WriteSummary
+	for _, file := range files {
+		if err := checkPath(baseDir, file.Path, opts); err != nil {
+			return summary, err
+		}
+	}
+	if previous, err := generator.ReadLock(baseDir); err == nil {
+		files = generator.KeepGeneratedAt(files, previous)
+	}
+
 	for _, file := range files {
 		fullPath := filepath.Join(baseDir, file.Path)
 
+		existing, err := os.ReadFile(fullPath)
+		exists := err == nil
+		if exists && string(existing) == file.Content {
+			summary.Unchanged = append(summary.Unchanged, file.Path)
+			continue
+		}
+
 		// Create directory if needed
 		dir := filepath.Dir(fullPath)
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+			return summary, fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+
+		if exists && opts.Backup {
+			if err := os.WriteFile(fullPath+BackupSuffix, existing, 0644); err != nil {
+				return summary, fmt.Errorf("failed to back up %s: %w", fullPath, err)
+			}
 		}
 
 		// Write file
 		if err := os.WriteFile(fullPath, []byte(file.Content), 0644); err != nil {
-			return fmt.Errorf("failed to write file %s: %w", fullPath, err)
+			return summary, fmt.Errorf("failed to write file %s: %w", fullPath, err)
+		}
+		if exists {
+			summary.Updated = append(summary.Updated, file.Path)
+		} else {
+			summary.Created = append(summary.Created, file.Path)
 		}
 	}
 
+	return summary, nil
+}
+
+// Overwrites returns the paths of files that exist in baseDir with different
+// content, except dorgu.lock itself and the files it records as written by
+// dorgu and not edited since: overwriting them loses nothing
+func Overwrites(baseDir string, files []generator.GeneratedFile) ([]string, error) {
+	lock, err := generator.ReadLock(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, file := range files {
+		if file.Path == generator.LockFile {
+			continue
+		}
+		existing, err := os.ReadFile(filepath.Join(baseDir, file.Path))
+		if err != nil || string(existing) == file.Content {
+			continue
+		}
+		if lock != nil && lock.Files[file.Path] != "" && !lock.Edited(file.Path, existing) {
+			continue
+		}
+		paths = append(paths, file.Path)
+	}
+	return paths, nil
+}
+
+// OutsideDir returns the paths of the files that resolve outside baseDir
+func OutsideDir(files []generator.GeneratedFile) []string {
+	var outside []string
+	for _, file := range files {
+		if !isLocal(file.Path) {
+			outside = append(outside, file.Path)
+		}
+	}
+	return outside
+}
+
+// checkPath rejects empty and absolute paths, and paths that leave baseDir
+// unless opts allow it
+func checkPath(baseDir, path string, opts WriteOptions) error {
+	switch {
+	case path == "":
+		return fmt.Errorf("refusing to write a file with an empty path")
+	case filepath.IsAbs(path) || strings.HasPrefix(path, "/"):
+		return fmt.Errorf("refusing to write %s: path must be relative to %s", path, baseDir)
+	case !isLocal(path) && !opts.AllowOutside:
+		return fmt.Errorf("refusing to write %s: it is outside %s", path, baseDir)
+	}
 	return nil
 }
+
+// isLocal reports whether a relative path stays inside the directory it is
+// relative to
+func isLocal(path string) bool {
+	return filepath.IsLocal(filepath.FromSlash(path))
+}
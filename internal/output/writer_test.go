@@ -0,0 +1,177 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func TestWriteFilesPathSafety(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		opts    WriteOptions
+		wantErr bool
+	}{
+		{"inside", "argocd/application.yaml", WriteOptions{}, false},
+		{"outside", "../PERSONA.md", WriteOptions{}, true},
+		{"outside allowed", "../PERSONA.md", WriteOptions{AllowOutside: true}, false},
+		{"escapes through subdir", "argocd/../../x.yaml", WriteOptions{}, true},
+		{"absolute", "/tmp/x.yaml", WriteOptions{AllowOutside: true}, true},
+		{"empty", "", WriteOptions{AllowOutside: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := filepath.Join(t.TempDir(), "k8s")
+			_, err := WriteFiles(base, []generator.GeneratedFile{{Path: tt.path, Content: "x"}}, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteFiles(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if err == nil {
+				if _, err := os.Stat(filepath.Join(base, tt.path)); err != nil {
+					t.Errorf("file not written: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestOutsideDir(t *testing.T) {
+	files := []generator.GeneratedFile{
+		{Path: "deployment.yaml"},
+		{Path: "../.github/workflows/deploy.yaml"},
+		{Path: "../PERSONA.md"},
+		{Path: "docs/../PERSONA.md"},
+	}
+	want := []string{"../.github/workflows/deploy.yaml", "../PERSONA.md"}
+	if got := OutsideDir(files); !reflect.DeepEqual(got, want) {
+		t.Errorf("OutsideDir() = %v, want %v", got, want)
+	}
+}
+
+func TestWriteFilesSummary(t *testing.T) {
+	base := t.TempDir()
+	if err := os.WriteFile(filepath.Join(base, "service.yaml"), []byte("kind: Service\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "hpa.yaml"), []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files := []generator.GeneratedFile{
+		{Path: "deployment.yaml", Content: "kind: Deployment\n"},
+		{Path: "service.yaml", Content: "kind: Service\n"},
+		{Path: "hpa.yaml", Content: "kind: HorizontalPodAutoscaler\n"},
+	}
+	summary, err := WriteFiles(base, files, WriteOptions{Backup: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := WriteSummary{
+		Created:   []string{"deployment.yaml"},
+		Updated:   []string{"hpa.yaml"},
+		Unchanged: []string{"service.yaml"},
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("WriteFiles() summary = %+v, want %+v", summary, want)
+	}
+	if backup, err := os.ReadFile(filepath.Join(base, "hpa.yaml"+BackupSuffix)); err != nil || string(backup) != "old\n" {
+		t.Errorf("backup = %q, %v; want the previous content", backup, err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "service.yaml"+BackupSuffix)); !os.IsNotExist(err) {
+		t.Errorf("unchanged file was backed up")
+	}
+}
+
+func TestWriteFilesKeepsGeneratedAt(t *testing.T) {
+	base := t.TempDir()
+	// generate stamps files and records them in the lock, as a generate run
+	// at hour does
+	generate := func(hour int, deployment string) []generator.GeneratedFile {
+		at := time.Date(2026, 1, 2, hour, 0, 0, 0, time.UTC)
+		p := generator.Provenance{Version: "v1.0.0", AnalysisHash: "sha256:abc", GeneratedAt: &at}
+		files := generator.StampFiles([]generator.GeneratedFile{
+			{Path: "deployment.yaml", Content: deployment},
+			{Path: "PERSONA.md", Content: "# orders\n"},
+		}, p)
+		lock, err := generator.NewLock(&types.AppAnalysis{Name: "orders"}, generator.Options{Config: config.Default()}, p, files).Render()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return append(files, generator.GeneratedFile{Path: generator.LockFile, Content: lock})
+	}
+	const deployment = "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: orders\n"
+	if _, err := WriteFiles(base, generate(3, deployment), WriteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := WriteFiles(base, generate(4, deployment), WriteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.Created) != 0 || len(summary.Updated) != 0 {
+		t.Errorf("rerun without changes: summary = %+v, want every file unchanged", summary)
+	}
+
+	summary, err = WriteFiles(base, generate(5, deployment+"spec:\n  replicas: 2\n"), WriteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"deployment.yaml", "PERSONA.md", generator.LockFile}; !reflect.DeepEqual(summary.Updated, want) {
+		t.Errorf("rerun with a change: updated = %v, want %v", summary.Updated, want)
+	}
+	lock, err := generator.ReadLock(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock.GeneratedAt == nil || lock.GeneratedAt.Hour() != 5 {
+		t.Errorf("generatedAt = %v, want the changed run's", lock.GeneratedAt)
+	}
+	if persona, _ := os.ReadFile(filepath.Join(base, "PERSONA.md")); !strings.Contains(string(persona), "2026-01-02T05:00:00Z") {
+		t.Errorf("PERSONA.md is not stamped with the changed run's time:\n%s", persona)
+	}
+}
+
+func TestOverwrites(t *testing.T) {
+	base := t.TempDir()
+	write := func(path, content string) {
+		if err := os.WriteFile(filepath.Join(base, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("deployment.yaml", "kind: Deployment\n")
+	write("service.yaml", "kind: Service\n")
+	write("hpa.yaml", "edited\n")
+	lock := generator.NewLock(&types.AppAnalysis{Name: "orders"}, generator.Options{Config: config.Default()}, generator.Provenance{}, []generator.GeneratedFile{
+		{Path: "service.yaml", Content: "kind: Service\n"},
+		{Path: "hpa.yaml", Content: "kind: HorizontalPodAutoscaler\n"},
+	})
+	rendered, err := lock.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(generator.LockFile, rendered)
+
+	files := []generator.GeneratedFile{
+		{Path: "deployment.yaml", Content: "kind: Deployment\nspec: {}\n"},
+		{Path: "service.yaml", Content: "kind: Service\nspec: {}\n"},
+		{Path: "hpa.yaml", Content: "kind: HorizontalPodAutoscaler\nspec: {}\n"},
+		{Path: "ingress.yaml", Content: "kind: Ingress\n"},
+		{Path: generator.LockFile, Content: "files: {}\n"},
+	}
+	got, err := Overwrites(base, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// deployment.yaml is not in the lock and hpa.yaml was edited
+	want := []string{"deployment.yaml", "hpa.yaml"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Overwrites() = %v, want %v", got, want)
+	}
+}
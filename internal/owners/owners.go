@@ -0,0 +1,250 @@
+// Package owners loads the directory of teams and people that app.team and
+// app.owner are checked against, from a file, a URL, or a SCIM 2.0 endpoint.
+package owners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Team is a team and its members' email addresses or user names
+type Team struct {
+	Name    string   `yaml:"name" json:"name"`
+	Members []string `yaml:"members" json:"members"`
+}
+
+// Directory is the set of known teams and owners. A file or URL source
+// serves it as YAML or JSON:
+//
+//	teams:
+//	  - name: payments
+//	    members: [alice@example.com, bob@example.com]
+//	owners: [oncall@example.com]
+type Directory struct {
+	Teams []Team `yaml:"teams" json:"teams"`
+	// Owners are valid owners that are not members of a team, such as
+	// shared mailboxes
+	Owners []string `yaml:"owners" json:"owners"`
+}
+
+// HasTeam reports whether the directory has the team, ignoring case
+func (d *Directory) HasTeam(name string) bool {
+	return d.team(name) != nil
+}
+
+// HasOwner reports whether owner is a team member or a listed owner,
+// ignoring case
+func (d *Directory) HasOwner(owner string) bool {
+	for _, o := range d.Owners {
+		if strings.EqualFold(o, owner) {
+			return true
+		}
+	}
+	for _, t := range d.Teams {
+		if contains(t.Members, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMember reports whether owner is a member of team. Teams the directory
+// lists without members accept anyone.
+func (d *Directory) IsMember(team, owner string) bool {
+	t := d.team(team)
+	return t == nil || len(t.Members) == 0 || contains(t.Members, owner)
+}
+
+// TeamNames returns the team names, sorted
+func (d *Directory) TeamNames() []string {
+	names := make([]string, 0, len(d.Teams))
+	for _, t := range d.Teams {
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (d *Directory) team(name string) *Team {
+	for i := range d.Teams {
+		if strings.EqualFold(d.Teams[i].Name, name) {
+			return &d.Teams[i]
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// scimScheme prefixes SCIM 2.0 base URLs, e.g. scim+https://idp/scim/v2
+const scimScheme = "scim+"
+
+// ValidateSource rejects sources Load cannot read
+func ValidateSource(source string) error {
+	if strings.HasPrefix(source, "ldap://") || strings.HasPrefix(source, "ldaps://") {
+		return fmt.Errorf("LDAP is not supported; export the directory to a file or use its SCIM endpoint (scim+https://...)")
+	}
+	if i := strings.Index(source, "://"); i >= 0 {
+		switch strings.TrimPrefix(source[:i], scimScheme) {
+		case "http", "https":
+		default:
+			return fmt.Errorf("%q is not a file path, http(s) URL, or scim+http(s) URL", source)
+		}
+	}
+	return nil
+}
+
+// Load reads the directory from source: a YAML or JSON file, an http(s) URL
+// serving one, or a SCIM 2.0 base URL prefixed with scim+. token, when set,
+// is sent as a bearer token to URLs.
+func Load(ctx context.Context, source, token string) (*Directory, error) {
+	if err := ValidateSource(source); err != nil {
+		return nil, fmt.Errorf("owners source: %w", err)
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	switch {
+	case strings.HasPrefix(source, scimScheme):
+		return loadSCIM(ctx, client, strings.TrimPrefix(source, scimScheme), token)
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		data, err := get(ctx, client, source, token)
+		if err != nil {
+			return nil, err
+		}
+		return parse(source, data)
+	}
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read owners source: %w", err)
+	}
+	return parse(source, data)
+}
+
+// parse decodes a directory file; JSON is valid YAML
+func parse(source string, data []byte) (*Directory, error) {
+	var d Directory
+	if err := yaml.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("owners source %s: %w", source, err)
+	}
+	return &d, nil
+}
+
+func get(ctx context.Context, client *http.Client, rawURL, token string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json, application/scim+json, application/yaml")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch owners source: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch owners source %s: %s", rawURL, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// scimPageSize is the count requested per SCIM list page
+const scimPageSize = 100
+
+// scimList is a SCIM ListResponse
+type scimList struct {
+	TotalResults int               `json:"totalResults"`
+	Resources    []json.RawMessage `json:"Resources"`
+}
+
+type scimUser struct {
+	ID       string `json:"id"`
+	UserName string `json:"userName"`
+	Emails   []struct {
+		Value string `json:"value"`
+	} `json:"emails"`
+}
+
+type scimGroup struct {
+	DisplayName string `json:"displayName"`
+	Members     []struct {
+		Value string `json:"value"`
+	} `json:"members"`
+}
+
+// loadSCIM builds the directory from a SCIM 2.0 service's Users and Groups:
+// groups are teams, and users are known by user name and email
+func loadSCIM(ctx context.Context, client *http.Client, base, token string) (*Directory, error) {
+	base = strings.TrimSuffix(base, "/")
+	users, err := scimResources[scimUser](ctx, client, base+"/Users", token)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := scimResources[scimGroup](ctx, client, base+"/Groups", token)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Directory{}
+	names := map[string][]string{}
+	for _, u := range users {
+		n := []string{u.UserName}
+		for _, e := range u.Emails {
+			n = append(n, e.Value)
+		}
+		names[u.ID] = n
+		d.Owners = append(d.Owners, n...)
+	}
+	for _, g := range groups {
+		t := Team{Name: g.DisplayName}
+		for _, m := range g.Members {
+			t.Members = append(t.Members, names[m.Value]...)
+		}
+		d.Teams = append(d.Teams, t)
+	}
+	return d, nil
+}
+
+// scimResources fetches every page of a SCIM list endpoint
+func scimResources[T any](ctx context.Context, client *http.Client, endpoint, token string) ([]T, error) {
+	var all []T
+	for start := 1; ; {
+		q := url.Values{"startIndex": {fmt.Sprint(start)}, "count": {fmt.Sprint(scimPageSize)}}
+		data, err := get(ctx, client, endpoint+"?"+q.Encode(), token)
+		if err != nil {
+			return nil, err
+		}
+		var page scimList
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, fmt.Errorf("invalid SCIM response from %s: %w", endpoint, err)
+		}
+		for _, raw := range page.Resources {
+			var v T
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, fmt.Errorf("invalid SCIM resource from %s: %w", endpoint, err)
+			}
+			all = append(all, v)
+		}
+		start += len(page.Resources)
+		if len(page.Resources) == 0 || start > page.TotalResults {
+			return all, nil
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package owners
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+const directoryYAML = `teams:
+  - name: payments
+    members: [alice@example.com, bob@example.com]
+  - name: platform
+owners: [oncall@example.com]
+`
+
+func TestDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "owners.yaml")
+	if err := os.WriteFile(path, []byte(directoryYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d, err := Load(context.Background(), path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"team", d.HasTeam("Payments"), true},
+		{"unknown team", d.HasTeam("billing"), false},
+		{"member", d.HasOwner("bob@example.com"), true},
+		{"listed owner", d.HasOwner("oncall@example.com"), true},
+		{"unknown owner", d.HasOwner("carol@example.com"), false},
+		{"member of team", d.IsMember("payments", "ALICE@example.com"), true},
+		{"not member of team", d.IsMember("payments", "oncall@example.com"), false},
+		{"team without members", d.IsMember("platform", "alice@example.com"), true},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if got, want := d.TeamNames(), []string{"payments", "platform"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TeamNames() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"teams": [{"name": "payments", "members": ["alice@example.com"]}]}`))
+	}))
+	defer srv.Close()
+
+	d, err := Load(context.Background(), srv.URL, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.HasOwner("alice@example.com") {
+		t.Errorf("directory = %+v", d)
+	}
+	if _, err := Load(context.Background(), srv.URL, ""); err == nil {
+		t.Error("expected an error without the token")
+	}
+}
+
+func TestLoadSCIM(t *testing.T) {
+	users := []string{
+		`{"id": "1", "userName": "alice", "emails": [{"value": "alice@example.com"}]}`,
+		`{"id": "2", "userName": "bob", "emails": [{"value": "bob@example.com"}]}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/scim/v2/Users":
+			// One user per page, to exercise paging
+			i, _ := strconv.Atoi(r.URL.Query().Get("startIndex"))
+			w.Write([]byte(`{"totalResults": 2, "Resources": [` + users[i-1] + `]}`))
+		case "/scim/v2/Groups":
+			w.Write([]byte(`{"totalResults": 1, "Resources": [{"displayName": "payments", "members": [{"value": "2"}]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	d, err := Load(context.Background(), "scim+"+srv.URL+"/scim/v2/", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.HasOwner("alice") || !d.HasOwner("alice@example.com") {
+		t.Errorf("owners = %v", d.Owners)
+	}
+	if !d.IsMember("payments", "bob@example.com") || d.IsMember("payments", "alice@example.com") {
+		t.Errorf("teams = %+v", d.Teams)
+	}
+}
+
+func TestValidateSource(t *testing.T) {
+	tests := []struct {
+		source  string
+		wantErr bool
+	}{
+		{"owners.yaml", false},
+		{"https://example.com/owners.json", false},
+		{"scim+https://idp.example.com/scim/v2", false},
+		{"ldap://ldap.example.com", true},
+		{"ftp://example.com/owners.yaml", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateSource(tt.source); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateSource(%q) = %v, wantErr %v", tt.source, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package progress reports the steps of an analyze/generate run and how long
+// each took. Library packages mark steps through the context; the CLI decides
+// how to show them.
+package progress
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Step is a finished step of a run
+type Step struct {
+	// Name is the step path, e.g. "analyze/dockerfile" or "generate/deployment.yaml"
+	Name     string
+	Duration time.Duration
+}
+
+// Reporter receives steps as they start and finish. Steps may run
+// concurrently, so implementations must be safe for concurrent use.
+type Reporter interface {
+	StepStarted(name string)
+	StepDone(step Step)
+}
+
+type reporterKey struct{}
+
+// WithReporter returns a context whose steps are reported to r
+func WithReporter(ctx context.Context, r Reporter) context.Context {
+	return context.WithValue(ctx, reporterKey{}, r)
+}
+
+// Start marks the start of a step and returns the function that marks its
+// end. Without a Reporter in ctx both are no-ops.
+func Start(ctx context.Context, name string) func() {
+	r, ok := ctx.Value(reporterKey{}).(Reporter)
+	if !ok {
+		return func() {}
+	}
+	r.StepStarted(name)
+	start := time.Now()
+	return func() {
+		r.StepDone(Step{Name: name, Duration: time.Since(start)})
+	}
+}
+
+// Recorder is a Reporter that keeps the finished steps in order
+type Recorder struct {
+	mu    sync.Mutex
+	steps []Step
+}
+
+// StepStarted implements Reporter
+func (r *Recorder) StepStarted(string) {}
+
+// StepDone implements Reporter
+func (r *Recorder) StepDone(step Step) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, step)
+}
+
+// Steps returns the finished steps in the order they finished
+func (r *Recorder) Steps() []Step {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Step(nil), r.steps...)
+}
+
+// Slowest returns the finished steps, longest first
+func (r *Recorder) Slowest() []Step {
+	steps := r.Steps()
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].Duration > steps[j].Duration })
+	return steps
+}
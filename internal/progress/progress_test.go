@@ -0,0 +1,27 @@
+package progress
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStart(t *testing.T) {
+	// No Reporter: Start is a no-op
+	Start(context.Background(), "analyze/dockerfile")()
+
+	rec := &Recorder{}
+	ctx := WithReporter(context.Background(), rec)
+	Start(ctx, "analyze/dockerfile")()
+	done := Start(ctx, "analyze/llm")
+	time.Sleep(5 * time.Millisecond)
+	done()
+
+	steps := rec.Steps()
+	if len(steps) != 2 || steps[0].Name != "analyze/dockerfile" || steps[1].Name != "analyze/llm" {
+		t.Fatalf("Steps() = %+v", steps)
+	}
+	if slowest := rec.Slowest(); slowest[0].Name != "analyze/llm" || slowest[0].Duration < 5*time.Millisecond {
+		t.Errorf("Slowest() = %+v", slowest)
+	}
+}
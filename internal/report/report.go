@@ -0,0 +1,276 @@
+// Package report aggregates ApplicationPersonas into an org-wide inventory:
+// apps per team, ownership and operability gaps, and resource totals.
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// App is one row of the inventory
+type App struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Team      string `json:"team,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Tier      string `json:"tier,omitempty"`
+	Owner     string `json:"owner,omitempty"`
+	Runbook   string `json:"runbook,omitempty"`
+	// Replicas is the minimum replica count, which resource totals use
+	Replicas int `json:"replicas"`
+	// Requests and Limits are per pod
+	Requests types.PersonaResourceValues `json:"requests"`
+	Limits   types.PersonaResourceValues `json:"limits"`
+	Probes   bool                        `json:"probes"`
+	// Source is the file or namespace/name the persona came from
+	Source string `json:"source,omitempty"`
+}
+
+// Team lists the apps a team owns
+type Team struct {
+	Name string   `json:"name"`
+	Apps []string `json:"apps"`
+}
+
+// Totals are the resources of all apps at their minimum replica counts
+type Totals struct {
+	Requests types.PersonaResourceValues `json:"requests"`
+	Limits   types.PersonaResourceValues `json:"limits"`
+}
+
+// Report is the org-wide inventory
+type Report struct {
+	Apps           []App    `json:"apps"`
+	Teams          []Team   `json:"teams"`
+	MissingOwner   []string `json:"missingOwner"`
+	MissingRunbook []string `json:"missingRunbook"`
+	WithoutProbes  []string `json:"withoutProbes"`
+	Totals         Totals   `json:"totals"`
+}
+
+// NoTeam groups apps whose persona names no team
+const NoTeam = "(no team)"
+
+// skipDirs are never searched for personas
+var skipDirs = map[string]bool{".git": true, "node_modules": true, "vendor": true, ".venv": true}
+
+// LoadFiles reads the ApplicationPersonas in the YAML files under root, such
+// as the persona.yaml dorgu generate writes next to each app's manifests
+func LoadFiles(root string) ([]types.ApplicationPersona, []string, error) {
+	var personas []types.ApplicationPersona
+	var sources []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && skipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !bytes.Contains(data, []byte("kind: "+types.PersonaKind)) {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		for _, doc := range strings.Split(string(data), "\n---\n") {
+			var obj struct {
+				Kind string `json:"kind"`
+			}
+			if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || obj.Kind != types.PersonaKind {
+				continue
+			}
+			var p types.ApplicationPersona
+			if err := yaml.Unmarshal([]byte(doc), &p); err != nil {
+				return fmt.Errorf("%s: %w", rel, err)
+			}
+			personas = append(personas, p)
+			sources = append(sources, rel)
+		}
+		return nil
+	})
+	return personas, sources, err
+}
+
+// Build aggregates personas into a report. sources, when given, name where
+// each persona came from.
+func Build(personas []types.ApplicationPersona, sources []string) *Report {
+	r := &Report{Apps: []App{}, Teams: []Team{}, MissingOwner: []string{}, MissingRunbook: []string{}, WithoutProbes: []string{}}
+	teams := map[string][]string{}
+	var reqCPU, reqMem, limCPU, limMem resource.Quantity
+	for i, p := range personas {
+		app := App{
+			Name:      p.Spec.Name,
+			Namespace: p.Metadata.Namespace,
+			Type:      p.Spec.Type,
+			Tier:      p.Spec.Tier,
+			Replicas:  1,
+			Probes:    p.Spec.Health != nil && (p.Spec.Health.LivenessPath != "" || p.Spec.Health.ReadinessPath != ""),
+		}
+		if app.Name == "" {
+			app.Name = p.Metadata.Name
+		}
+		if i < len(sources) {
+			app.Source = sources[i]
+		} else if p.Metadata.Namespace != "" {
+			app.Source = p.Metadata.Namespace + "/" + p.Metadata.Name
+		}
+		if o := p.Spec.Ownership; o != nil {
+			app.Team, app.Owner, app.Runbook = o.Team, o.Owner, o.Runbook
+		}
+		if s := p.Spec.Scaling; s != nil && s.MinReplicas > 0 {
+			app.Replicas = s.MinReplicas
+		}
+		if res := p.Spec.Resources; res != nil {
+			app.Requests, app.Limits = res.Requests, res.Limits
+		}
+		r.Apps = append(r.Apps, app)
+
+		team := app.Team
+		if team == "" {
+			team = NoTeam
+		}
+		teams[team] = append(teams[team], app.Name)
+		if app.Owner == "" {
+			r.MissingOwner = append(r.MissingOwner, app.Name)
+		}
+		if app.Runbook == "" {
+			r.MissingRunbook = append(r.MissingRunbook, app.Name)
+		}
+		if !app.Probes {
+			r.WithoutProbes = append(r.WithoutProbes, app.Name)
+		}
+		addQuantity(&reqCPU, app.Requests.CPU, app.Replicas)
+		addQuantity(&reqMem, app.Requests.Memory, app.Replicas)
+		addQuantity(&limCPU, app.Limits.CPU, app.Replicas)
+		addQuantity(&limMem, app.Limits.Memory, app.Replicas)
+	}
+	sort.Slice(r.Apps, func(i, j int) bool {
+		if r.Apps[i].Namespace != r.Apps[j].Namespace {
+			return r.Apps[i].Namespace < r.Apps[j].Namespace
+		}
+		return r.Apps[i].Name < r.Apps[j].Name
+	})
+	for name, apps := range teams {
+		sort.Strings(apps)
+		r.Teams = append(r.Teams, Team{Name: name, Apps: apps})
+	}
+	sort.Slice(r.Teams, func(i, j int) bool { return r.Teams[i].Name < r.Teams[j].Name })
+	for _, list := range [][]string{r.MissingOwner, r.MissingRunbook, r.WithoutProbes} {
+		sort.Strings(list)
+	}
+	r.Totals = Totals{
+		Requests: types.PersonaResourceValues{CPU: reqCPU.String(), Memory: reqMem.String()},
+		Limits:   types.PersonaResourceValues{CPU: limCPU.String(), Memory: limMem.String()},
+	}
+	return r
+}
+
+// addQuantity adds value × replicas to total, skipping invalid quantities
+func addQuantity(total *resource.Quantity, value string, replicas int) {
+	q, err := resource.ParseQuantity(value)
+	if value == "" || err != nil {
+		return
+	}
+	q.Mul(int64(replicas))
+	total.Add(q)
+}
+
+// Markdown renders the report for a leadership review
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# Application inventory\n\n")
+	fmt.Fprintf(&b, "%d apps across %d teams.\n\n", len(r.Apps), len(r.Teams))
+
+	b.WriteString("## Apps per team\n\n| Team | Apps | Count |\n|------|------|-------|\n")
+	for _, t := range r.Teams {
+		fmt.Fprintf(&b, "| %s | %s | %d |\n", t.Name, strings.Join(t.Apps, ", "), len(t.Apps))
+	}
+
+	b.WriteString("\n## Gaps\n\n")
+	for _, gap := range []struct {
+		title string
+		apps  []string
+	}{
+		{"Missing owner", r.MissingOwner},
+		{"Missing runbook", r.MissingRunbook},
+		{"Without health probes", r.WithoutProbes},
+	} {
+		fmt.Fprintf(&b, "- **%s (%d):** ", gap.title, len(gap.apps))
+		if len(gap.apps) == 0 {
+			b.WriteString("none\n")
+		} else {
+			b.WriteString(strings.Join(gap.apps, ", ") + "\n")
+		}
+	}
+
+	b.WriteString("\n## Resource totals\n\nAt minimum replicas.\n\n| | CPU | Memory |\n|---|-----|--------|\n")
+	fmt.Fprintf(&b, "| Requests | %s | %s |\n", r.Totals.Requests.CPU, r.Totals.Requests.Memory)
+	fmt.Fprintf(&b, "| Limits | %s | %s |\n", r.Totals.Limits.CPU, r.Totals.Limits.Memory)
+
+	b.WriteString("\n## Apps\n\n| App | Namespace | Team | Tier | Replicas | Requests | Limits | Owner | Runbook | Probes |\n")
+	b.WriteString("|-----|-----------|------|------|----------|----------|--------|-------|---------|--------|\n")
+	for _, a := range r.Apps {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %d | %s | %s | %s | %s | %s |\n",
+			a.Name, dash(a.Namespace), dash(a.Team), dash(a.Tier), a.Replicas,
+			resources(a.Requests), resources(a.Limits), dash(a.Owner), dash(a.Runbook), yesNo(a.Probes))
+	}
+	return b.String()
+}
+
+// CSV renders one row per app, for spreadsheets
+func (r *Report) CSV() (string, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	rows := [][]string{{"app", "namespace", "team", "type", "tier", "owner", "runbook", "replicas",
+		"requests_cpu", "requests_memory", "limits_cpu", "limits_memory", "probes", "source"}}
+	for _, a := range r.Apps {
+		rows = append(rows, []string{a.Name, a.Namespace, a.Team, a.Type, a.Tier, a.Owner, a.Runbook, strconv.Itoa(a.Replicas),
+			a.Requests.CPU, a.Requests.Memory, a.Limits.CPU, a.Limits.Memory, strconv.FormatBool(a.Probes), a.Source})
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// resources renders a cpu/memory pair, e.g. 100m / 256Mi
+func resources(v types.PersonaResourceValues) string {
+	if v.CPU == "" && v.Memory == "" {
+		return "-"
+	}
+	return dash(v.CPU) + " / " + dash(v.Memory)
+}
@@ -0,0 +1,124 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+func testPersonas() []types.ApplicationPersona {
+	return []types.ApplicationPersona{
+		{
+			Kind:     types.PersonaKind,
+			Metadata: types.PersonaMetadata{Name: "orders", Namespace: "shop"},
+			Spec: types.PersonaSpec{
+				Name:      "orders",
+				Tier:      "critical",
+				Resources: &types.PersonaResources{Requests: types.PersonaResourceValues{CPU: "250m", Memory: "256Mi"}, Limits: types.PersonaResourceValues{CPU: "1", Memory: "1Gi"}},
+				Scaling:   &types.PersonaScaling{MinReplicas: 2, MaxReplicas: 4},
+				Health:    &types.PersonaHealth{LivenessPath: "/health"},
+				Ownership: &types.PersonaOwnership{Team: "commerce", Owner: "orders@example.com", Runbook: "https://wiki/orders"},
+			},
+		},
+		{
+			Kind:     types.PersonaKind,
+			Metadata: types.PersonaMetadata{Name: "billing", Namespace: "shop"},
+			Spec: types.PersonaSpec{
+				Name:      "billing",
+				Resources: &types.PersonaResources{Requests: types.PersonaResourceValues{CPU: "100m", Memory: "128Mi"}, Limits: types.PersonaResourceValues{CPU: "500m", Memory: "512Mi"}},
+				Ownership: &types.PersonaOwnership{Team: "commerce"},
+			},
+		},
+		{
+			Kind:     types.PersonaKind,
+			Metadata: types.PersonaMetadata{Name: "batch"},
+			Spec:     types.PersonaSpec{Name: "batch"},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	r := Build(testPersonas(), nil)
+
+	wantTeams := []Team{{Name: NoTeam, Apps: []string{"batch"}}, {Name: "commerce", Apps: []string{"billing", "orders"}}}
+	if !reflect.DeepEqual(r.Teams, wantTeams) {
+		t.Errorf("Teams = %+v, want %+v", r.Teams, wantTeams)
+	}
+	if want := []string{"batch", "billing"}; !reflect.DeepEqual(r.MissingOwner, want) {
+		t.Errorf("MissingOwner = %v, want %v", r.MissingOwner, want)
+	}
+	if want := []string{"batch", "billing"}; !reflect.DeepEqual(r.MissingRunbook, want) {
+		t.Errorf("MissingRunbook = %v, want %v", r.MissingRunbook, want)
+	}
+	if want := []string{"batch", "billing"}; !reflect.DeepEqual(r.WithoutProbes, want) {
+		t.Errorf("WithoutProbes = %v, want %v", r.WithoutProbes, want)
+	}
+	want := Totals{
+		Requests: types.PersonaResourceValues{CPU: "600m", Memory: "640Mi"},
+		Limits:   types.PersonaResourceValues{CPU: "2500m", Memory: "2560Mi"},
+	}
+	if r.Totals != want {
+		t.Errorf("Totals = %+v, want %+v", r.Totals, want)
+	}
+	if r.Apps[0].Name != "batch" || r.Apps[2].Source != "shop/orders" {
+		t.Errorf("Apps = %+v", r.Apps)
+	}
+
+	md := r.Markdown()
+	for _, w := range []string{
+		"3 apps across 2 teams.",
+		"| commerce | billing, orders | 2 |",
+		"- **Missing runbook (2):** batch, billing",
+		"| Requests | 600m | 640Mi |",
+		"| orders | shop | commerce | critical | 2 | 250m / 256Mi | 1 / 1Gi | orders@example.com | https://wiki/orders | yes |",
+	} {
+		if !strings.Contains(md, w) {
+			t.Errorf("Markdown missing %q:\n%s", w, md)
+		}
+	}
+
+	csv, err := r.CSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "app,namespace,team") || lines[3] != "orders,shop,commerce,,critical,orders@example.com,https://wiki/orders,2,250m,256Mi,1,1Gi,true,shop/orders" {
+		t.Errorf("CSV =\n%s", csv)
+	}
+}
+
+func TestLoadFiles(t *testing.T) {
+	root := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("orders/k8s/persona.yaml", "# Code generated by dorgu\napiVersion: dorgu.io/v1\nkind: ApplicationPersona\nmetadata:\n  name: orders\nspec:\n  name: orders\n  type: api\n")
+	write("billing/k8s/manifests.yaml", "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: billing\nspec:\n  replicas: 2\n---\napiVersion: dorgu.io/v1\nkind: ApplicationPersona\nmetadata:\n  name: billing\nspec:\n  name: billing\n  type: worker\n")
+	write("billing/k8s/deployment.yaml", "kind: Deployment\n")
+
+	personas, sources, err := LoadFiles(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range personas {
+		names = append(names, p.Spec.Name)
+	}
+	if want := []string{"billing", "orders"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("personas = %v, want %v", names, want)
+	}
+	if want := []string{filepath.Join("billing", "k8s", "manifests.yaml"), filepath.Join("orders", "k8s", "persona.yaml")}; !reflect.DeepEqual(sources, want) {
+		t.Errorf("sources = %v, want %v", sources, want)
+	}
+}
@@ -0,0 +1,310 @@
+// Package server exposes analyze, generate, and validate as an HTTP API
+// (dorgu serve), so developer portals can call dorgu without running the CLI
+// per request.
+//
+// Every endpoint takes a multipart or URL-encoded form with the app either
+// uploaded as the "archive" field (.tar.gz or .zip of the app directory) or
+// cloned from "git_url" (https only, optionally with "ref" and "path" inside
+// the repository):
+//
+//	POST /v1/analyze   the analysis
+//	POST /v1/generate  the generated files and validation report
+//	POST /v1/validate  the validation report of the generated files
+//
+// Generation options are form fields too: name, namespace, llm_provider,
+// skip_argocd, skip_ci, and skip_persona. llm_provider must be one the
+// server allows. With a token set, requests other than /healthz need it as
+// an "Authorization: Bearer" header.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/pkg/dorgu"
+)
+
+// DefaultMaxUploadBytes limits request bodies and extracted archives
+const DefaultMaxUploadBytes = 50 << 20
+
+// DefaultRequestTimeout bounds a request from fetching the app to the
+// response
+const DefaultRequestTimeout = 5 * time.Minute
+
+// Options configures the server
+type Options struct {
+	// Config is the workspace configuration applied to every app
+	Config *config.Config
+	// LLMProvider is used when a request does not set llm_provider. Empty
+	// analyzes without an LLM.
+	LLMProvider string
+	// LLMProviders are the providers a request may set as llm_provider,
+	// besides LLMProvider. They run with the server's API keys.
+	LLMProviders []string
+	// Token, when set, must be sent as a bearer token
+	Token string
+	// RequestTimeout bounds each request (default DefaultRequestTimeout)
+	RequestTimeout time.Duration
+	// MaxUploadBytes limits the request body and the extracted app
+	// (default DefaultMaxUploadBytes)
+	MaxUploadBytes int64
+}
+
+// Server serves the HTTP API
+type Server struct {
+	opts Options
+	mux  *http.ServeMux
+}
+
+// New returns a server with the given options
+func New(opts Options) *Server {
+	if opts.Config == nil {
+		opts.Config = config.Default()
+	}
+	if opts.MaxUploadBytes <= 0 {
+		opts.MaxUploadBytes = DefaultMaxUploadBytes
+	}
+	if opts.RequestTimeout <= 0 {
+		opts.RequestTimeout = DefaultRequestTimeout
+	}
+	s := &Server{opts: opts, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	s.mux.HandleFunc("/v1/analyze", s.post(s.analyze))
+	s.mux.HandleFunc("/v1/generate", s.post(s.generate))
+	s.mux.HandleFunc("/v1/validate", s.post(s.validate))
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// requestError is an invalid request, answered with 400
+type requestError struct{ err error }
+
+func (e requestError) Error() string { return e.err.Error() }
+
+func badRequest(format string, args ...interface{}) error {
+	return requestError{fmt.Errorf(format, args...)}
+}
+
+// handler serves a request for the app checked out in dir
+type handler func(ctx context.Context, r *http.Request, dir string) (interface{}, error)
+
+// post wraps h: it accepts only authorized POSTs, fetches the app into a
+// temporary directory, and renders the result or error as JSON
+func (s *Server) post(h handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, http.StatusMethodNotAllowed, errors.New("use POST"))
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), s.opts.RequestTimeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+		r.Body = http.MaxBytesReader(w, r.Body, s.opts.MaxUploadBytes)
+
+		tmp, err := os.MkdirTemp("", "dorgu-serve-")
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		defer os.RemoveAll(tmp)
+
+		var result interface{}
+		dir, err := s.fetchApp(r, tmp)
+		if err == nil {
+			result, err = h(r.Context(), r, dir)
+		}
+		var reqErr requestError
+		switch {
+		case err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+			writeError(w, http.StatusServiceUnavailable, fmt.Errorf("request did not finish within %s", s.opts.RequestTimeout))
+		case errors.As(err, &reqErr):
+			writeError(w, http.StatusBadRequest, err)
+		case err != nil:
+			slog.Warn("request failed", "path", r.URL.Path, "err", err)
+			writeError(w, http.StatusUnprocessableEntity, err)
+		default:
+			writeJSON(w, http.StatusOK, result)
+		}
+	}
+}
+
+// fetchApp extracts the uploaded archive or clones git_url into tmp and
+// returns the app directory
+func (s *Server) fetchApp(r *http.Request, tmp string) (string, error) {
+	if err := r.ParseMultipartForm(s.opts.MaxUploadBytes); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return "", badRequest("invalid form: %v", err)
+	}
+	gitURL := r.FormValue("git_url")
+	file, header, err := r.FormFile("archive")
+	switch {
+	case err == nil && gitURL != "":
+		file.Close()
+		return "", badRequest("set either archive or git_url, not both")
+	case err == nil:
+		defer file.Close()
+		if err := extractArchive(file, header.Filename, tmp, s.opts.MaxUploadBytes); err != nil {
+			return "", badRequest("archive: %v", err)
+		}
+		return archiveRoot(tmp), nil
+	case gitURL != "":
+		return cloneApp(r.Context(), gitURL, r.FormValue("ref"), r.FormValue("path"), tmp, s.opts.MaxUploadBytes)
+	default:
+		return "", badRequest("missing archive or git_url")
+	}
+}
+
+func (s *Server) generateOptions(r *http.Request) (dorgu.GenerateOptions, error) {
+	provider, err := s.provider(r)
+	if err != nil {
+		return dorgu.GenerateOptions{}, err
+	}
+	opts := dorgu.GenerateOptions{
+		Namespace:   r.FormValue("namespace"),
+		Config:      s.opts.Config,
+		LLMProvider: provider,
+		// Plugins run arbitrary executables; not for remote callers
+		SkipPlugins: true,
+	}
+	for name, field := range map[string]*bool{
+		"skip_argocd": &opts.SkipArgoCD, "skip_ci": &opts.SkipCI, "skip_persona": &opts.SkipPersona,
+	} {
+		if v := r.FormValue(name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return opts, badRequest("%s: %v", name, err)
+			}
+			*field = b
+		}
+	}
+	return opts, nil
+}
+
+// authorized reports whether r carries the server's token, if it has one
+func (s *Server) authorized(r *http.Request) bool {
+	if s.opts.Token == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1
+}
+
+// provider returns the LLM provider of the request: llm_provider, which must
+// be allowed, or the server's default
+func (s *Server) provider(r *http.Request) (string, error) {
+	p := r.FormValue("llm_provider")
+	if p == "" || p == s.opts.LLMProvider {
+		return s.opts.LLMProvider, nil
+	}
+	for _, allowed := range s.opts.LLMProviders {
+		if p == allowed {
+			return p, nil
+		}
+	}
+	return "", badRequest("llm_provider %q is not enabled on this server", p)
+}
+
+func (s *Server) analyze(ctx context.Context, r *http.Request, dir string) (interface{}, error) {
+	return s.analyzeApp(ctx, r, dir)
+}
+
+// analyzeApp analyzes the app in dir, named by the name field when set
+func (s *Server) analyzeApp(ctx context.Context, r *http.Request, dir string) (*dorgu.AppAnalysis, error) {
+	provider, err := s.provider(r)
+	if err != nil {
+		return nil, err
+	}
+	analysis, err := dorgu.Analyze(ctx, dir, dorgu.AnalyzeOptions{
+		LLMProvider:       provider,
+		DisabledAnalyzers: s.opts.Config.Analyzers.Disabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if name := r.FormValue("name"); name != "" {
+		analysis.Name = name
+	}
+	return analysis, nil
+}
+
+// File is a generated file in a response
+type File struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// GenerateResponse is the response of /v1/generate
+type GenerateResponse struct {
+	Name       string                  `json:"name"`
+	Namespace  string                  `json:"namespace"`
+	Files      []File                  `json:"files"`
+	Validation *dorgu.ValidationResult `json:"validation"`
+}
+
+func (s *Server) generate(ctx context.Context, r *http.Request, dir string) (interface{}, error) {
+	opts, err := s.generateOptions(r)
+	if err != nil {
+		return nil, err
+	}
+	analysis, err := s.analyzeApp(ctx, r, dir)
+	if err != nil {
+		return nil, err
+	}
+	files, err := dorgu.Generate(ctx, analysis, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &GenerateResponse{
+		Name:       analysis.Name,
+		Namespace:  opts.Namespace,
+		Validation: dorgu.ValidateContext(ctx, analysis, files, opts),
+	}
+	if resp.Namespace == "" {
+		resp.Namespace = "default"
+	}
+	for _, f := range files {
+		resp.Files = append(resp.Files, File{Path: f.Path, Content: f.Content})
+	}
+	return resp, nil
+}
+
+func (s *Server) validate(ctx context.Context, r *http.Request, dir string) (interface{}, error) {
+	result, err := s.generate(ctx, r, dir)
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GenerateResponse).Validation, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Debug("failed to write response", "err", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tarGz returns a .tar.gz holding files
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+// upload posts archive to path with the form fields
+func upload(t *testing.T, srv *httptest.Server, path string, archive []byte, fields map[string]string) *http.Response {
+	t.Helper()
+	return uploadWithToken(t, srv, path, archive, fields, "")
+}
+
+// uploadWithToken is upload with a bearer token, when set
+func uploadWithToken(t *testing.T, srv *httptest.Server, path string, archive []byte, fields map[string]string, token string) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for k, v := range fields {
+		mw.WriteField(k, v)
+	}
+	fw, _ := mw.CreateFormFile("archive", "orders.tar.gz")
+	fw.Write(archive)
+	mw.Close()
+	req, err := http.NewRequest(http.MethodPost, srv.URL+path, &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestGenerate(t *testing.T) {
+	srv := httptest.NewServer(New(Options{}))
+	defer srv.Close()
+
+	archive := tarGz(t, map[string]string{"orders/Dockerfile": "FROM golang:1.22\nEXPOSE 8080\n"})
+	resp := upload(t, srv, "/v1/generate", archive, map[string]string{"namespace": "commerce", "skip_ci": "true"})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	var result GenerateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "orders" || result.Namespace != "commerce" || result.Validation == nil {
+		t.Errorf("result = %s/%s, validation %v", result.Namespace, result.Name, result.Validation)
+	}
+	var deployment bool
+	for _, f := range result.Files {
+		deployment = deployment || f.Path == "deployment.yaml" && strings.Contains(f.Content, "namespace: commerce")
+	}
+	if !deployment {
+		t.Error("no deployment.yaml in the commerce namespace")
+	}
+}
+
+func TestBadRequests(t *testing.T) {
+	srv := httptest.NewServer(New(Options{}))
+	defer srv.Close()
+
+	escape := tarGz(t, map[string]string{"../Dockerfile": "FROM golang\n"})
+	if resp := upload(t, srv, "/v1/analyze", escape, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("archive escaping its root: status = %d", resp.StatusCode)
+	}
+	noApp := tarGz(t, map[string]string{"README.md": "# docs\n"})
+	if resp := upload(t, srv, "/v1/analyze", noApp, nil); resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("archive without Dockerfile: status = %d", resp.StatusCode)
+	}
+
+	resp, err := http.PostForm(srv.URL+"/v1/analyze", url.Values{"git_url": {"file:///etc"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("file:// git_url: status = %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(srv.URL + "/v1/generate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status = %d", resp.StatusCode)
+	}
+}
+
+func TestToken(t *testing.T) {
+	srv := httptest.NewServer(New(Options{Token: "s3cret"}))
+	defer srv.Close()
+
+	archive := tarGz(t, map[string]string{"orders/Dockerfile": "FROM golang:1.22\nEXPOSE 8080\n"})
+	if resp := upload(t, srv, "/v1/analyze", archive, nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("without token: status = %d", resp.StatusCode)
+	}
+	for token, want := range map[string]int{"wrong": http.StatusUnauthorized, "s3cret": http.StatusOK} {
+		if resp := uploadWithToken(t, srv, "/v1/analyze", archive, nil, token); resp.StatusCode != want {
+			t.Errorf("token %q: status = %d, want %d", token, resp.StatusCode, want)
+		}
+	}
+
+	resp, err := http.Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/healthz without token: status = %d", resp.StatusCode)
+	}
+}
+
+func TestLLMProviders(t *testing.T) {
+	srv := httptest.NewServer(New(Options{LLMProviders: []string{"ollama"}}))
+	defer srv.Close()
+
+	archive := tarGz(t, map[string]string{"orders/Dockerfile": "FROM golang:1.22\nEXPOSE 8080\n"})
+	if resp := upload(t, srv, "/v1/analyze", archive, map[string]string{"llm_provider": "openai"}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("provider not enabled: status = %d", resp.StatusCode)
+	}
+}
+
+func TestResolveApp(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "orders"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(t.TempDir(), filepath.Join(dir, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := resolveApp(dir, filepath.Join(dir, "orders")); err != nil {
+		t.Errorf("path inside the clone: %v", err)
+	}
+	if _, err := resolveApp(dir, filepath.Join(dir, "escape")); err == nil {
+		t.Error("expected error for a symlink leaving the clone")
+	}
+	if _, err := resolveApp(dir, filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for a missing path")
+	}
+}
@@ -0,0 +1,208 @@
+package server
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dorgu-ai/dorgu/internal/vcs"
+)
+
+// extractArchive extracts a .tar.gz, .tgz, or .zip archive into dir. Entries
+// outside dir, links, and more than limit bytes in total are rejected.
+func extractArchive(r io.Reader, name string, dir string, limit int64) error {
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return extractTarGz(r, dir, limit)
+	case strings.HasSuffix(name, ".zip"):
+		// zip needs random access; the request body is already size-limited
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return extractZip(data, dir, limit)
+	}
+	return fmt.Errorf("unsupported archive %q (use .tar.gz or .zip)", name)
+}
+
+func extractTarGz(r io.Reader, dir string, limit int64) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if _, err := localPath(dir, hdr.Name); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if limit -= hdr.Size; limit < 0 {
+				return fmt.Errorf("extracted files exceed the upload limit")
+			}
+			if err := writeEntry(dir, hdr.Name, tr); err != nil {
+				return err
+			}
+		}
+		// Links and special files are skipped
+	}
+}
+
+func extractZip(data []byte, dir string, limit int64) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		if limit -= int64(f.UncompressedSize64); limit < 0 {
+			return fmt.Errorf("extracted files exceed the upload limit")
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = writeEntry(dir, f.Name, io.LimitReader(rc, int64(f.UncompressedSize64)))
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveRoot returns the app directory of an extracted archive: its only
+// top-level directory, as in orders.tar.gz holding orders/, or dir itself
+func archiveRoot(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err == nil && len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name())
+	}
+	return dir
+}
+
+// localPath returns the path of an archive entry in dir, rejecting entries
+// that would land outside it
+func localPath(dir, name string) (string, error) {
+	name = filepath.FromSlash(strings.TrimPrefix(name, "./"))
+	if name == "" || name == "." {
+		return dir, nil
+	}
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("entry %q is outside the archive root", name)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+func writeEntry(dir, name string, r io.Reader) error {
+	path, err := localPath(dir, name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// cloneTimeout bounds a clone, which may be slowed down by the remote
+const cloneTimeout = 2 * time.Minute
+
+// cloneApp shallow-clones the https repository at rawURL into dir, under the
+// repository's name, and returns the app directory: path inside the clone,
+// or its root. The clone uses no credentials of the server and must fit in
+// limit bytes.
+func cloneApp(ctx context.Context, rawURL, ref, path, dir string, limit int64) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return "", badRequest("git_url must be an https URL")
+	}
+	repo := vcs.RepoName(u.Path)
+	if repo == "" || repo == "." {
+		repo = "app"
+	}
+	dir = filepath.Join(dir, repo)
+	app := dir
+	if path != "" {
+		if app, err = localPath(dir, path); err != nil {
+			return "", badRequest("path: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cloneTimeout)
+	defer cancel()
+	if err := vcs.CloneAnonymous(ctx, rawURL, ref, dir); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", badRequest("git clone did not finish within %s", cloneTimeout)
+		}
+		return "", badRequest("%v", err)
+	}
+	if size, err := treeSize(dir); err != nil {
+		return "", err
+	} else if size > limit {
+		return "", badRequest("the cloned repository exceeds the upload limit")
+	}
+	return resolveApp(dir, app)
+}
+
+// treeSize returns the size of the files under dir
+func treeSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// resolveApp resolves the symlinks in app, which must stay inside dir
+func resolveApp(dir, app string) (string, error) {
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(app)
+	if err != nil {
+		return "", badRequest("path: not found in the repository")
+	}
+	if rel, err := filepath.Rel(root, resolved); err != nil || rel != "." && !filepath.IsLocal(rel) {
+		return "", badRequest("path is outside the repository")
+	}
+	return resolved, nil
+}
@@ -3,11 +3,14 @@ package types
 // AppAnalysis represents the complete analysis of an application
 type AppAnalysis struct {
 	// Basic info
-	Name        string `json:"name"`
-	Type        string `json:"type"` // api, web, worker, cron
-	Language    string `json:"language"`
-	Framework   string `json:"framework"`
-	Description string `json:"description"`
+	Name string `json:"name"`
+	// OriginalName is the name before it was normalized into a valid
+	// resource name (empty when it already was one)
+	OriginalName string `json:"originalName,omitempty"`
+	Type         string `json:"type"` // api, web, worker, cron
+	Language     string `json:"language"`
+	Framework    string `json:"framework"`
+	Description  string `json:"description"`
 
 	// Deployment characteristics
 	Ports       []Port       `json:"ports"`
@@ -23,6 +26,19 @@ type AppAnalysis struct {
 	Dockerfile *DockerfileAnalysis `json:"dockerfile,omitempty"`
 	Compose    *ComposeAnalysis    `json:"compose,omitempty"`
 	Code       *CodeAnalysis       `json:"code,omitempty"`
+	Manifests  *ManifestAnalysis   `json:"manifests,omitempty"`
+	// Capabilities are what the analysis found the app does besides serving,
+	// e.g. CapabilityMigrations
+	Capabilities []string `json:"capabilities,omitempty"`
+	// Migrations is how the app migrates its database schema, with
+	// CapabilityMigrations
+	Migrations *Migrations `json:"migrations,omitempty"`
+	// StaticSite is set when the app is a frontend built to static files
+	// that nginx serves from its image
+	StaticSite *StaticSite `json:"static_site,omitempty"`
+	// Schedules are the cron schedules found in the source and compose
+	// file, proposed as CronJobs
+	Schedules []ScheduleHint `json:"schedules,omitempty"`
 
 	// App config from .dorgu.yaml (optional)
 	AppConfig *AppConfigContext `json:"app_config,omitempty"`
@@ -77,6 +93,45 @@ type AppConfigContext struct {
 
 	// Deployment policy
 	DeploymentPolicy *DeploymentPolicyContext `json:"deployment_policy,omitempty"`
+
+	// Networking
+	Networking *NetworkingContext `json:"networking,omitempty"`
+
+	// Service overrides
+	Service *ServiceContext `json:"service,omitempty"`
+
+	// Secret provider overrides
+	Secrets *SecretsContext `json:"secrets,omitempty"`
+
+	// Runtime class and seccomp/AppArmor overrides
+	Security *SecurityContext `json:"security,omitempty"`
+
+	// Jobs run with the app
+	Jobs []JobContext `json:"jobs,omitempty"`
+
+	// Additional containers in the app's pod
+	Containers []ContainerContext `json:"containers,omitempty"`
+
+	// Service level objectives
+	SLO *SLOContext `json:"slo,omitempty"`
+}
+
+// ContainerContext describes an additional container from app config
+type ContainerContext struct {
+	Name      string             `json:"name"`
+	Image     string             `json:"image"`
+	Command   []string           `json:"command,omitempty"`
+	Args      []string           `json:"args,omitempty"`
+	Ports     []ContainerPort    `json:"ports,omitempty"`
+	Env       map[string]string  `json:"env,omitempty"`
+	Health    *HealthContext     `json:"health,omitempty"`
+	Resources *ResourceOverrides `json:"resources,omitempty"`
+}
+
+// ContainerPort is a named port of an additional container
+type ContainerPort struct {
+	Name string `json:"name,omitempty"`
+	Port int    `json:"port"`
 }
 
 // ResourceOverrides contains resource configuration overrides
@@ -89,28 +144,91 @@ type ResourceOverrides struct {
 
 // IngressContext contains ingress configuration from app config
 type IngressContext struct {
-	Enabled    bool             `json:"enabled"`
-	Host       string           `json:"host,omitempty"`
-	Paths      []IngressPathDef `json:"paths,omitempty"`
-	TLSEnabled bool             `json:"tls_enabled"`
-	TLSSecret  string           `json:"tls_secret,omitempty"`
+	Enabled bool             `json:"enabled"`
+	Host    string           `json:"host,omitempty"`
+	Hosts   []IngressHostDef `json:"hosts,omitempty"`
+	Class   string           `json:"class,omitempty"`
+	// Annotations are Ingress-only annotations
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Paths       []IngressPathDef  `json:"paths,omitempty"`
+	TLSEnabled  bool              `json:"tls_enabled"`
+	TLSSecret   string            `json:"tls_secret,omitempty"`
+}
+
+// IngressHostDef is an additional ingress host
+type IngressHostDef struct {
+	Host      string `json:"host"`
+	TLSSecret string `json:"tls_secret,omitempty"` // overrides the shared TLS secret
 }
 
 // IngressPathDef defines an ingress path
 type IngressPathDef struct {
 	Path     string `json:"path"`
 	PathType string `json:"path_type"`
+	Port     int    `json:"port,omitempty"`    // backend port; default the app's HTTP port
+	Service  string `json:"service,omitempty"` // backend Service; default the app's own
 }
 
-// HealthContext contains health check configuration from app config
+// HealthContext contains health check configuration from app config. Each
+// probe is configured independently; nil means not set in app config.
 type HealthContext struct {
-	LivenessPath       string `json:"liveness_path,omitempty"`
-	LivenessPort       int    `json:"liveness_port,omitempty"`
-	ReadinessPath      string `json:"readiness_path,omitempty"`
-	ReadinessPort      int    `json:"readiness_port,omitempty"`
-	InitialDelay       int    `json:"initial_delay,omitempty"`
-	Period             int    `json:"period,omitempty"`
-	StartupGracePeriod string `json:"startup_grace_period,omitempty"` // e.g., "30s", "60s"
+	Liveness           *HealthCheck `json:"liveness,omitempty"`
+	Readiness          *HealthCheck `json:"readiness,omitempty"`
+	StartupGracePeriod string       `json:"startup_grace_period,omitempty"` // e.g., "30s", "60s"
+}
+
+// NetworkingContext contains networking configuration from app config
+type NetworkingContext struct {
+	Expose string      `json:"expose,omitempty"` // public, internal, headless
+	DNS    *DNSContext `json:"dns,omitempty"`
+}
+
+// DNSContext contains pod DNS tuning from app config
+type DNSContext struct {
+	Ndots       *int               `json:"ndots,omitempty"`
+	Searches    []string           `json:"searches,omitempty"`
+	HostAliases []HostAliasContext `json:"host_aliases,omitempty"`
+}
+
+// HostAliasContext is an /etc/hosts entry
+type HostAliasContext struct {
+	IP        string   `json:"ip"`
+	Hostnames []string `json:"hostnames"`
+}
+
+// ServiceContext contains Service configuration from app config
+type ServiceContext struct {
+	Type        string            `json:"type,omitempty"` // ClusterIP, NodePort, LoadBalancer
+	Presets     []string          `json:"presets,omitempty"`
+	StaticIP    string            `json:"static_ip,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// SecretsContext contains secret provider overrides from app config
+type SecretsContext struct {
+	Provider string `json:"provider,omitempty"` // kubernetes, vault-agent, csi
+	Role     string `json:"role,omitempty"`
+	Path     string `json:"path,omitempty"`
+}
+
+// SecurityContext contains runtime security overrides from app config
+type SecurityContext struct {
+	RuntimeClass            string `json:"runtime_class,omitempty"`
+	SeccompType             string `json:"seccomp_type,omitempty"`
+	SeccompLocalhostProfile string `json:"seccomp_localhost_profile,omitempty"`
+	AppArmorProfile         string `json:"apparmor_profile,omitempty"`
+	PodSecurityStandard     string `json:"pod_security_standard,omitempty"`
+}
+
+// JobContext describes a Job from app config
+type JobContext struct {
+	Name                  string   `json:"name"`
+	Command               []string `json:"command,omitempty"`
+	Image                 string   `json:"image,omitempty"`
+	Hook                  string   `json:"hook,omitempty"` // pre-deploy, post-deploy
+	Schedule              string   `json:"schedule,omitempty"`
+	BackoffLimit          *int     `json:"backoff_limit,omitempty"`
+	ActiveDeadlineSeconds int      `json:"active_deadline_seconds,omitempty"`
 }
 
 // DependencyContext describes a dependency from app config
@@ -171,6 +289,45 @@ type ScalingConfig struct {
 	TargetCPU    int    `json:"target_cpu_percent,omitempty"`
 	TargetMemory int    `json:"target_memory_percent,omitempty"`
 	Behavior     string `json:"behavior,omitempty"` // conservative, balanced, aggressive
+	// ScaleUp and ScaleDown override the rules of the behavior preset
+	ScaleUp   *ScalingRules `json:"scale_up,omitempty"`
+	ScaleDown *ScalingRules `json:"scale_down,omitempty"`
+	// Metrics are pods and external metrics to scale on
+	Metrics []ScalingMetric `json:"metrics,omitempty"`
+}
+
+// ScalingMetric is a pods or external HPA metric
+type ScalingMetric struct {
+	Type         string            `json:"type"`
+	Name         string            `json:"name"`
+	Selector     map[string]string `json:"selector,omitempty"`
+	AverageValue string            `json:"average_value,omitempty"`
+	Value        string            `json:"value,omitempty"`
+}
+
+// ScalingRules are the HPA scaling rules for one direction
+type ScalingRules struct {
+	StabilizationWindowSeconds *int            `json:"stabilization_window_seconds,omitempty"`
+	SelectPolicy               string          `json:"select_policy,omitempty"`
+	Policies                   []ScalingPolicy `json:"policies,omitempty"`
+}
+
+// ScalingPolicy limits how much the HPA may scale within a period
+type ScalingPolicy struct {
+	Type          string `json:"type"`
+	Value         int    `json:"value"`
+	PeriodSeconds int    `json:"period_seconds"`
+}
+
+// ManifestAnalysis contains what the app's existing (not dorgu-generated)
+// Kubernetes Deployment declares
+type ManifestAnalysis struct {
+	Files      []string `json:"files"`
+	Image      string   `json:"image,omitempty"`
+	Replicas   int      `json:"replicas,omitempty"`
+	Ports      []int    `json:"ports,omitempty"`
+	HealthPath string   `json:"health_path,omitempty"`
+	HealthPort int      `json:"health_port,omitempty"`
 }
 
 // DockerfileAnalysis contains parsed Dockerfile information
@@ -184,6 +341,9 @@ type DockerfileAnalysis struct {
 	User        string            `json:"user"`
 	Labels      map[string]string `json:"labels"`
 	BuildStages []string          `json:"build_stages"`
+	// NginxConfig is where the final stage copies its own nginx
+	// configuration, under /etc/nginx; empty when it copies none
+	NginxConfig string `json:"nginx_config,omitempty"`
 }
 
 // ComposeAnalysis contains parsed docker-compose information
@@ -191,6 +351,63 @@ type ComposeAnalysis struct {
 	Services []ComposeService `json:"services"`
 }
 
+// CapabilityMigrations marks apps that migrate a database schema with a
+// migration tool
+const CapabilityMigrations = "migrations"
+
+// Migrations is how an app migrates its database schema
+type Migrations struct {
+	// Tool is the migration tool: flyway, liquibase, alembic,
+	// golang-migrate, or prisma
+	Tool string `json:"tool"`
+	// Source is the file, relative to the app, that shows the tool is used
+	Source string `json:"source"`
+	// Command applies pending migrations once; empty when the app applies
+	// them with the tool's library
+	Command []string `json:"command,omitempty"`
+	// Image is the tool's official image, for tools whose CLI the app's
+	// image usually lacks; an image built from it with the migrations runs
+	// Command
+	Image string `json:"image,omitempty"`
+	// OnStartup is set when the app applies migrations as it starts, with the
+	// library or from its container command
+	OnStartup bool `json:"on_startup,omitempty"`
+}
+
+// StaticSitePort is the port nginx listens on for static sites: above 1024,
+// so it runs as a non-root user
+const StaticSitePort = 8080
+
+// StaticSiteHealthPath is the path nginx answers health checks on for
+// static sites
+const StaticSiteHealthPath = "/healthz"
+
+// StaticSite is a frontend whose build nginx serves
+type StaticSite struct {
+	// BuildTool builds the static files, e.g. vite
+	BuildTool string `json:"build_tool"`
+	// AssetsDir holds the content-hashed assets, relative to the site root,
+	// which are cached for a year; "" when they sit in the root
+	AssetsDir string `json:"assets_dir,omitempty"`
+	// NginxConfig is where the Dockerfile copies its own nginx
+	// configuration. The image's configuration and port are then kept and
+	// the app is deployed as a generic web app.
+	NginxConfig string `json:"nginx_config,omitempty"`
+}
+
+// ScheduleHint is a cron schedule found in the app, proposed as a CronJob
+type ScheduleHint struct {
+	// Name is the proposed job name
+	Name string `json:"name"`
+	// Schedule is in CronJob form: five fields or a macro such as @daily
+	Schedule string `json:"schedule"`
+	// Command runs the task once; empty when the app schedules it
+	// in-process, e.g. with node-cron
+	Command []string `json:"command,omitempty"`
+	// Source is where the schedule was found, e.g. crontab:3
+	Source string `json:"source"`
+}
+
 // ComposeService represents a service in docker-compose
 type ComposeService struct {
 	Name        string        `json:"name"`
@@ -201,6 +418,11 @@ type ComposeService struct {
 	Volumes     []string      `json:"volumes"`
 	DependsOn   []string      `json:"depends_on"`
 	HealthCheck *HealthCheck  `json:"healthcheck,omitempty"`
+	Command     []string      `json:"command,omitempty"`
+	// Schedule is the cron schedule a scheduler label sets, and
+	// ScheduleCommand the command it runs when not the service's
+	Schedule        string   `json:"schedule,omitempty"`
+	ScheduleCommand []string `json:"schedule_command,omitempty"`
 }
 
 // PortMapping represents a port mapping in docker-compose
@@ -218,4 +440,30 @@ type CodeAnalysis struct {
 	HealthPath   string   `json:"health_path"`
 	MetricsPath  string   `json:"metrics_path"`
 	Routes       []string `json:"routes"`
+	// ListenPort is the port the framework config or code binds, found in
+	// ListenPortSource, a path relative to the app
+	ListenPort       int    `json:"listen_port,omitempty"`
+	ListenPortSource string `json:"listen_port_source,omitempty"`
+	// JobFramework is the background job framework whose workers the app
+	// runs, e.g. celery, and JobQueue the dependency its jobs are queued in
+	JobFramework string `json:"job_framework,omitempty"`
+	JobQueue     string `json:"job_queue,omitempty"`
+	// StaticBuild is the tool that builds the app to static files, e.g.
+	// vite, when no server framework is a dependency; StaticAssetsDir holds
+	// the content-hashed assets it emits, relative to the site root
+	StaticBuild     string `json:"static_build,omitempty"`
+	StaticAssetsDir string `json:"static_assets_dir,omitempty"`
+}
+
+// SLOContext contains the app's service level objectives
+type SLOContext struct {
+	Availability      float64 `json:"availability,omitempty"` // percent
+	LatencyThreshold  string  `json:"latency_threshold,omitempty"`
+	LatencyTarget     float64 `json:"latency_target,omitempty"` // percent
+	Window            string  `json:"window,omitempty"`
+	ErrorBudgetPolicy string  `json:"error_budget_policy,omitempty"`
+	Format            string  `json:"format,omitempty"`
+	RequestsMetric    string  `json:"requests_metric,omitempty"`
+	DurationMetric    string  `json:"duration_metric,omitempty"`
+	Selector          string  `json:"selector,omitempty"`
 }
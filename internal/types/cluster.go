@@ -0,0 +1,88 @@
+package types
+
+// ClusterPersonaKind identifies the ClusterPersona CRD
+const ClusterPersonaKind = "ClusterPersona"
+
+// ClusterPersona is the cluster-scoped dorgu.io/v1 ClusterPersona resource
+type ClusterPersona struct {
+	APIVersion string                `json:"apiVersion"`
+	Kind       string                `json:"kind"`
+	Metadata   PersonaMetadata       `json:"metadata"`
+	Spec       ClusterPersonaSpec    `json:"spec"`
+	Status     *ClusterPersonaStatus `json:"status,omitempty"`
+}
+
+// ClusterPersonaSpec describes the cluster's identity and conventions
+type ClusterPersonaSpec struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Environment string                 `json:"environment,omitempty"` // development, staging, production, sandbox
+	Discovered  *ClusterFacts          `json:"discovered,omitempty"`
+	Policies    *ClusterPolicies       `json:"policies,omitempty"`
+	Conventions *ClusterConventions    `json:"conventions,omitempty"`
+	Defaults    *ClusterPersonaDefault `json:"defaults,omitempty"`
+}
+
+// ClusterFacts are facts discovered from the API server at init time
+type ClusterFacts struct {
+	KubernetesVersion string         `json:"kubernetesVersion,omitempty"`
+	Platform          string         `json:"platform,omitempty"` // eks, gke, aks, k3s, kind, minikube, ...
+	NodeCount         int            `json:"nodeCount,omitempty"`
+	Addons            []ClusterAddon `json:"addons,omitempty"`
+}
+
+// ClusterAddon is a well-known add-on found on the cluster
+type ClusterAddon struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Version   string `json:"version,omitempty"`
+}
+
+// ClusterPolicies holds cluster-wide policies
+type ClusterPolicies struct {
+	Security *ClusterSecurityPolicy `json:"security,omitempty"`
+}
+
+// ClusterSecurityPolicy is the cluster's security baseline
+type ClusterSecurityPolicy struct {
+	EnforceNonRoot      bool   `json:"enforceNonRoot"`
+	DisallowPrivileged  bool   `json:"disallowPrivileged"`
+	PodSecurityStandard string `json:"podSecurityStandard,omitempty"` // privileged, baseline, restricted
+}
+
+// ClusterConventions holds naming and labeling conventions
+type ClusterConventions struct {
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+}
+
+// ClusterPersonaDefault holds defaults applied to onboarded applications
+type ClusterPersonaDefault struct {
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ClusterPersonaStatus is written by the Dorgu Operator
+type ClusterPersonaStatus struct {
+	Phase             string                  `json:"phase,omitempty"` // Discovering, Ready, Degraded
+	LastDiscovery     string                  `json:"lastDiscovery,omitempty"`
+	KubernetesVersion string                  `json:"kubernetesVersion,omitempty"`
+	Platform          string                  `json:"platform,omitempty"`
+	ApplicationCount  int                     `json:"applicationCount,omitempty"`
+	Nodes             []ClusterNode           `json:"nodes,omitempty"`
+	Addons            []ClusterAddon          `json:"addons,omitempty"`
+	ResourceSummary   *ClusterResourceSummary `json:"resourceSummary,omitempty"`
+}
+
+// ClusterNode is a node as reported by the operator
+type ClusterNode struct {
+	Name           string   `json:"name"`
+	Roles          []string `json:"roles,omitempty"`
+	Ready          bool     `json:"ready"`
+	KubeletVersion string   `json:"kubeletVersion,omitempty"`
+}
+
+// ClusterResourceSummary aggregates cluster resource usage
+type ClusterResourceSummary struct {
+	RunningPods int    `json:"runningPods,omitempty"`
+	TotalCPU    string `json:"totalCPU,omitempty"`
+	TotalMemory string `json:"totalMemory,omitempty"`
+}
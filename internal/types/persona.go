@@ -0,0 +1,243 @@
+package types
+
+// PersonaAPIVersion and PersonaKind identify the ApplicationPersona CRD
+const (
+	PersonaAPIVersion = "dorgu.io/v1"
+	PersonaKind       = "ApplicationPersona"
+)
+
+// ApplicationPersona is the dorgu.io/v1 ApplicationPersona custom resource
+// (see docs/PERSONA_CRD_SPEC.md). JSON tags follow the CRD schema so the same
+// struct marshals to YAML (via sigs.k8s.io/yaml) and JSON.
+type ApplicationPersona struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Metadata   PersonaMetadata `json:"metadata"`
+	Spec       PersonaSpec     `json:"spec"`
+	Status     *PersonaStatus  `json:"status,omitempty"`
+}
+
+// PersonaMetadata is the subset of object metadata dorgu reads and writes
+type PersonaMetadata struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	CreationTimestamp string            `json:"creationTimestamp,omitempty"`
+}
+
+// PersonaSpec describes what the application is and what it needs
+type PersonaSpec struct {
+	Name         string              `json:"name"`
+	Version      string              `json:"version,omitempty"`
+	Type         string              `json:"type"`           // api, web, worker, cron, daemon
+	Tier         string              `json:"tier,omitempty"` // critical, standard, best-effort
+	Technical    *PersonaTechnical   `json:"technical,omitempty"`
+	Resources    *PersonaResources   `json:"resources,omitempty"`
+	Scaling      *PersonaScaling     `json:"scaling,omitempty"`
+	Health       *PersonaHealth      `json:"health,omitempty"`
+	Dependencies []PersonaDependency `json:"dependencies,omitempty"`
+	Networking   *PersonaNetworking  `json:"networking,omitempty"`
+	Ownership    *PersonaOwnership   `json:"ownership,omitempty"`
+	Policies     *PersonaPolicies    `json:"policies,omitempty"`
+	SLO          *PersonaSLO         `json:"slo,omitempty"`
+}
+
+// PersonaTechnical is the technical profile of the application
+type PersonaTechnical struct {
+	Language    string `json:"language,omitempty"`
+	Framework   string `json:"framework,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// PersonaResources holds resource constraints
+type PersonaResources struct {
+	Requests PersonaResourceValues `json:"requests"`
+	Limits   PersonaResourceValues `json:"limits"`
+	Profile  string                `json:"profile,omitempty"`
+}
+
+// PersonaResourceValues is a cpu/memory pair
+type PersonaResourceValues struct {
+	CPU    string `json:"cpu,omitempty"`
+	Memory string `json:"memory,omitempty"`
+}
+
+// PersonaScaling describes scaling behavior
+type PersonaScaling struct {
+	MinReplicas  int    `json:"minReplicas"`
+	MaxReplicas  int    `json:"maxReplicas"`
+	TargetCPU    int    `json:"targetCPU,omitempty"`
+	TargetMemory int    `json:"targetMemory,omitempty"`
+	Behavior     string `json:"behavior,omitempty"` // conservative, balanced, aggressive
+}
+
+// PersonaHealth describes health check configuration
+type PersonaHealth struct {
+	LivenessPath       string `json:"livenessPath,omitempty"`
+	ReadinessPath      string `json:"readinessPath,omitempty"`
+	Port               int    `json:"port,omitempty"`
+	StartupGracePeriod string `json:"startupGracePeriod,omitempty"`
+}
+
+// PersonaDependency is an external dependency of the application
+type PersonaDependency struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"` // database, cache, queue, service, external
+	Required    bool   `json:"required"`
+	HealthCheck string `json:"healthCheck,omitempty"`
+}
+
+// PersonaNetworking describes ports and ingress
+type PersonaNetworking struct {
+	Ports   []PersonaPort   `json:"ports,omitempty"`
+	Ingress *PersonaIngress `json:"ingress,omitempty"`
+}
+
+// PersonaPort is an exposed port
+type PersonaPort struct {
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol,omitempty"`
+	Purpose  string `json:"purpose,omitempty"`
+}
+
+// PersonaIngress describes external exposure
+type PersonaIngress struct {
+	Enabled    bool     `json:"enabled"`
+	Host       string   `json:"host,omitempty"`
+	Paths      []string `json:"paths,omitempty"`
+	TLSEnabled bool     `json:"tlsEnabled"`
+}
+
+// PersonaOwnership describes who owns and operates the application
+type PersonaOwnership struct {
+	Team       string `json:"team,omitempty"`
+	Owner      string `json:"owner,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	Oncall     string `json:"oncall,omitempty"`
+	Runbook    string `json:"runbook,omitempty"`
+}
+
+// PersonaPolicies holds security, deployment, and maintenance policies
+type PersonaPolicies struct {
+	Security    *PersonaSecurityPolicy    `json:"security,omitempty"`
+	Deployment  *PersonaDeploymentPolicy  `json:"deployment,omitempty"`
+	Maintenance *PersonaMaintenancePolicy `json:"maintenance,omitempty"`
+}
+
+// PersonaSecurityPolicy is the required container security posture
+type PersonaSecurityPolicy struct {
+	RunAsNonRoot             bool `json:"runAsNonRoot"`
+	ReadOnlyRootFilesystem   bool `json:"readOnlyRootFilesystem"`
+	AllowPrivilegeEscalation bool `json:"allowPrivilegeEscalation"`
+	// PodSecurityStandard is the Pod Security Standard level the app's pods
+	// comply with, set only when they do
+	PodSecurityStandard string `json:"podSecurityStandard,omitempty"`
+}
+
+// PersonaDeploymentPolicy is the rollout strategy
+type PersonaDeploymentPolicy struct {
+	Strategy       string `json:"strategy,omitempty"` // RollingUpdate, Recreate, BlueGreen, Canary
+	MaxSurge       string `json:"maxSurge,omitempty"`
+	MaxUnavailable string `json:"maxUnavailable,omitempty"`
+}
+
+// PersonaMaintenancePolicy describes maintenance windows
+type PersonaMaintenancePolicy struct {
+	Window      string `json:"window,omitempty"`
+	AutoRestart bool   `json:"autoRestart"`
+}
+
+// PersonaSLO holds the application's service level objectives
+type PersonaSLO struct {
+	Availability      float64            `json:"availability,omitempty"` // percent of requests that succeed
+	Latency           *PersonaLatencySLO `json:"latency,omitempty"`
+	Window            string             `json:"window,omitempty"`
+	ErrorBudgetPolicy string             `json:"errorBudgetPolicy,omitempty"`
+}
+
+// PersonaLatencySLO is the percent of requests that finish within a threshold
+type PersonaLatencySLO struct {
+	Threshold string  `json:"threshold"`
+	Target    float64 `json:"target"`
+}
+
+// PersonaStatus is written by the Dorgu Operator
+type PersonaStatus struct {
+	Phase           string                  `json:"phase,omitempty"` // Pending, Active, Degraded, Failed
+	LastUpdated     string                  `json:"lastUpdated,omitempty"`
+	Deployments     *PersonaDeployments     `json:"deployments,omitempty"`
+	Health          *PersonaHealthStatus    `json:"health,omitempty"`
+	Validation      *PersonaValidation      `json:"validation,omitempty"`
+	Learned         *PersonaLearned         `json:"learned,omitempty"`
+	Recommendations []PersonaRecommendation `json:"recommendations,omitempty"`
+}
+
+// PersonaDeployments tracks deployment history
+type PersonaDeployments struct {
+	Current        string                     `json:"current,omitempty"`
+	LastSuccessful string                     `json:"lastSuccessful,omitempty"`
+	LastFailed     string                     `json:"lastFailed,omitempty"`
+	History        []PersonaDeploymentHistory `json:"history,omitempty"`
+}
+
+// PersonaDeploymentHistory is a single deployment record
+type PersonaDeploymentHistory struct {
+	Version     string `json:"version,omitempty"`
+	Timestamp   string `json:"timestamp,omitempty"`
+	Status      string `json:"status,omitempty"`
+	TriggeredBy string `json:"triggeredBy,omitempty"`
+}
+
+// PersonaHealthStatus is the observed health of the application
+type PersonaHealthStatus struct {
+	Status    string `json:"status,omitempty"` // Healthy, Degraded, Unhealthy, Unknown
+	LastCheck string `json:"lastCheck,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// PersonaValidation holds the operator's validation results
+type PersonaValidation struct {
+	Passed      bool                     `json:"passed"`
+	LastChecked string                   `json:"lastChecked,omitempty"`
+	Issues      []PersonaValidationIssue `json:"issues,omitempty"`
+}
+
+// PersonaValidationIssue is a single validation finding
+type PersonaValidationIssue struct {
+	Severity   string `json:"severity,omitempty"` // error, warning, info
+	Field      string `json:"field,omitempty"`
+	Message    string `json:"message,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// PersonaLearned holds patterns learned by the operator
+type PersonaLearned struct {
+	ResourceBaseline *PersonaResourceBaseline `json:"resourceBaseline,omitempty"`
+	IncidentCount    int                      `json:"incidentCount,omitempty"`
+	LastIncident     string                   `json:"lastIncident,omitempty"`
+	Patterns         []PersonaPattern         `json:"patterns,omitempty"`
+}
+
+// PersonaResourceBaseline is observed resource usage
+type PersonaResourceBaseline struct {
+	AvgCPU     string `json:"avgCPU,omitempty"`
+	AvgMemory  string `json:"avgMemory,omitempty"`
+	PeakCPU    string `json:"peakCPU,omitempty"`
+	PeakMemory string `json:"peakMemory,omitempty"`
+}
+
+// PersonaPattern is a learned behavioral pattern
+type PersonaPattern struct {
+	Type        string  `json:"type,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Confidence  float64 `json:"confidence,omitempty"`
+}
+
+// PersonaRecommendation is an operator recommendation
+type PersonaRecommendation struct {
+	Type     string `json:"type,omitempty"`     // resource, scaling, security, cost, performance
+	Priority string `json:"priority,omitempty"` // high, medium, low
+	Message  string `json:"message,omitempty"`
+	Action   string `json:"action,omitempty"`
+}
@@ -0,0 +1,292 @@
+// Package update finds the latest dorgu GitHub release and installs it over
+// the running binary after verifying the published SHA-256 checksum.
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRepo is the GitHub repository releases are fetched from
+	DefaultRepo = "dorgu-ai/dorgu"
+	// DefaultAPIURL is the GitHub REST API endpoint
+	DefaultAPIURL = "https://api.github.com"
+	// ChecksumsFile is the checksum manifest published with every release
+	ChecksumsFile = "checksums.txt"
+)
+
+// Release is the subset of a GitHub release used for upgrades
+type Release struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Version returns the release version without the leading "v"
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// ArchiveName returns the archive GoReleaser publishes for a platform
+func ArchiveName(version, goos, goarch string) string {
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("dorgu_%s_%s_%s%s", strings.TrimPrefix(version, "v"), goos, goarch, ext)
+}
+
+// Asset returns the asset with the given name
+func (r *Release) Asset(name string) (*Asset, error) {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("release %s has no asset %s", r.TagName, name)
+}
+
+// Client talks to the GitHub releases API
+type Client struct {
+	APIURL string
+	Repo   string
+	// Token is sent as a bearer token when set, raising the API rate limit
+	Token string
+	HTTP  *http.Client
+}
+
+// NewClient returns a client for the dorgu repository, using GITHUB_TOKEN
+// when it is set
+func NewClient() *Client {
+	return &Client{
+		APIURL: DefaultAPIURL,
+		Repo:   DefaultRepo,
+		Token:  os.Getenv("GITHUB_TOKEN"),
+		HTTP:   &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// Latest returns the latest published release
+func (c *Client) Latest(ctx context.Context) (*Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", strings.TrimSuffix(c.APIURL, "/"), c.Repo)
+	data, err := c.get(ctx, url, "application/vnd.github+json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+	var release Release
+	if err := json.Unmarshal(data, &release); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	if release.TagName == "" {
+		return nil, fmt.Errorf("latest release has no tag")
+	}
+	return &release, nil
+}
+
+// Download fetches an asset
+func (c *Client) Download(ctx context.Context, asset *Asset) ([]byte, error) {
+	data, err := c.get(ctx, asset.URL, "application/octet-stream")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", asset.Name, err)
+	}
+	return data, nil
+}
+
+func (c *Client) get(ctx context.Context, url, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// VerifyChecksum checks data against the entry for name in a sha256sum-style
+// checksums file
+func VerifyChecksum(data []byte, name string, checksums []byte) error {
+	var want string
+	for _, line := range strings.Split(string(checksums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			want = strings.ToLower(fields[0])
+			break
+		}
+	}
+	if want == "" {
+		return fmt.Errorf("no checksum for %s in %s", name, ChecksumsFile)
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, want)
+	}
+	return nil
+}
+
+// ExtractBinary returns the named binary from a .tar.gz or .zip archive
+func ExtractBinary(archive []byte, archiveName, binary string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", archiveName, err)
+		}
+		for _, f := range zr.File {
+			if path.Base(f.Name) != binary {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+		return nil, fmt.Errorf("%s not found in %s", binary, archiveName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", archiveName, err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", archiveName, err)
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == binary {
+			return io.ReadAll(tr)
+		}
+	}
+	return nil, fmt.Errorf("%s not found in %s", binary, archiveName)
+}
+
+// ReplaceExecutable atomically swaps the file at exe for data. The new
+// binary is written next to exe so the final rename stays on one filesystem;
+// the old binary is moved aside first because Windows cannot overwrite a
+// running executable.
+func ReplaceExecutable(exe string, data []byte) error {
+	dir := filepath.Dir(exe)
+	tmp, err := os.CreateTemp(dir, ".dorgu-upgrade-*")
+	if err != nil {
+		return fmt.Errorf("cannot write to %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0755); err != nil {
+		return err
+	}
+
+	old := exe + ".old"
+	os.Remove(old)
+	if err := os.Rename(exe, old); err != nil {
+		return fmt.Errorf("failed to move current binary aside: %w", err)
+	}
+	if err := os.Rename(tmpPath, exe); err != nil {
+		// Put the original back so the install is never left empty
+		os.Rename(old, exe)
+		return fmt.Errorf("failed to install new binary: %w", err)
+	}
+	// Fails on Windows while the old binary is running; it is replaced next time
+	os.Remove(old)
+	return nil
+}
+
+// CompareVersions compares two semantic versions (with or without a leading
+// "v"), returning -1, 0, or 1. A pre-release sorts before its release.
+func CompareVersions(a, b string) int {
+	aCore, aPre, _ := splitVersion(a)
+	bCore, bPre, _ := splitVersion(b)
+	for i := 0; i < 3; i++ {
+		if aCore[i] != bCore[i] {
+			if aCore[i] < bCore[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	case aPre < bPre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// IsRelease reports whether version looks like a released semantic version
+// rather than a local build such as "dev"
+func IsRelease(version string) bool {
+	_, _, ok := splitVersion(version)
+	return ok
+}
+
+// splitVersion parses MAJOR.MINOR.PATCH[-PRE]; ok is false when the core
+// is not numeric
+func splitVersion(v string) (core [3]int, pre string, ok bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v, pre = v[:i], v[i+1:]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return core, pre, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return [3]int{}, pre, false
+		}
+		core[i] = n
+	}
+	return core, pre, true
+}
@@ -0,0 +1,159 @@
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"0.1.0", "v0.1.0", 0},
+		{"v0.1.0", "v0.2.0", -1},
+		{"1.10.0", "1.9.3", 1},
+		{"0.2.0-rc.1", "0.2.0", -1},
+		{"0.2.0", "0.2.0-rc.1", 1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestIsRelease(t *testing.T) {
+	for v, want := range map[string]bool{"v0.1.0": true, "1.2.3-rc.1": true, "dev": false, "none": false, "1.2": false} {
+		if got := IsRelease(v); got != want {
+			t.Errorf("IsRelease(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	if got := ArchiveName("v0.3.0", "linux", "amd64"); got != "dorgu_0.3.0_linux_amd64.tar.gz" {
+		t.Errorf("ArchiveName() = %q", got)
+	}
+	if got := ArchiveName("0.3.0", "windows", "arm64"); got != "dorgu_0.3.0_windows_arm64.zip" {
+		t.Errorf("ArchiveName() = %q", got)
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("archive contents")
+	sum := sha256.Sum256(data)
+	checksums := []byte(fmt.Sprintf("deadbeef  other.tar.gz\n%s  dorgu_0.3.0_linux_amd64.tar.gz\n", hex.EncodeToString(sum[:])))
+
+	if err := VerifyChecksum(data, "dorgu_0.3.0_linux_amd64.tar.gz", checksums); err != nil {
+		t.Errorf("VerifyChecksum() unexpected error: %v", err)
+	}
+	if err := VerifyChecksum([]byte("tampered"), "dorgu_0.3.0_linux_amd64.tar.gz", checksums); err == nil {
+		t.Error("VerifyChecksum() accepted tampered data")
+	}
+	if err := VerifyChecksum(data, "missing.tar.gz", checksums); err == nil {
+		t.Error("VerifyChecksum() accepted a file without a checksum entry")
+	}
+}
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestExtractBinary(t *testing.T) {
+	archive := tarGz(t, map[string]string{"README.md": "readme", "dorgu": "new binary"})
+
+	got, err := ExtractBinary(archive, "dorgu_0.3.0_linux_amd64.tar.gz", "dorgu")
+	if err != nil {
+		t.Fatalf("ExtractBinary() error: %v", err)
+	}
+	if string(got) != "new binary" {
+		t.Errorf("ExtractBinary() = %q", got)
+	}
+	if _, err := ExtractBinary(archive, "dorgu_0.3.0_linux_amd64.tar.gz", "dorgu.exe"); err == nil {
+		t.Error("ExtractBinary() found a binary that is not in the archive")
+	}
+}
+
+func TestClient_LatestAndDownload(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/dorgu-ai/dorgu/releases/latest":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				t.Errorf("missing token, got %q", r.Header.Get("Authorization"))
+			}
+			fmt.Fprintf(w, `{"tag_name":"v0.3.0","assets":[{"name":"checksums.txt","browser_download_url":"%s/dl/checksums.txt"}]}`, srv.URL)
+		case "/dl/checksums.txt":
+			fmt.Fprint(w, "abc  dorgu_0.3.0_linux_amd64.tar.gz\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient()
+	c.APIURL = srv.URL
+	c.Token = "token"
+
+	release, err := c.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Latest() error: %v", err)
+	}
+	if release.Version() != "0.3.0" {
+		t.Errorf("Version() = %q, want 0.3.0", release.Version())
+	}
+	asset, err := release.Asset(ChecksumsFile)
+	if err != nil {
+		t.Fatalf("Asset() error: %v", err)
+	}
+	data, err := c.Download(context.Background(), asset)
+	if err != nil {
+		t.Fatalf("Download() error: %v", err)
+	}
+	if string(data) != "abc  dorgu_0.3.0_linux_amd64.tar.gz\n" {
+		t.Errorf("Download() = %q", data)
+	}
+	if _, err := release.Asset("dorgu_0.3.0_plan9_386.tar.gz"); err == nil {
+		t.Error("Asset() found a missing asset")
+	}
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "dorgu")
+	if err := os.WriteFile(exe, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReplaceExecutable(exe, []byte("new")); err != nil {
+		t.Fatalf("ReplaceExecutable() error: %v", err)
+	}
+	got, _ := os.ReadFile(exe)
+	if string(got) != "new" {
+		t.Errorf("binary = %q, want new", got)
+	}
+	if _, err := os.Stat(exe + ".old"); !os.IsNotExist(err) {
+		t.Error("old binary was left behind")
+	}
+}
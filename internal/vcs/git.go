@@ -0,0 +1,166 @@
+// Package vcs commits generated files to a branch with the git CLI and
+// opens pull requests on GitHub or merge requests on GitLab.
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Repo is a local git working tree
+type Repo struct {
+	// Root is the top-level directory of the working tree
+	Root string
+}
+
+// Open returns the repository containing dir
+func Open(ctx context.Context, dir string) (*Repo, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git not found in PATH")
+	}
+	root, err := runGit(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in a git repository", dir)
+	}
+	return &Repo{Root: root}, nil
+}
+
+// Clone shallow-clones the repository at url into dir, checking out ref
+// (a branch or tag) when set. git never prompts for credentials.
+func Clone(ctx context.Context, url, ref, dir string) error {
+	return clone(ctx, url, ref, dir, false)
+}
+
+// CloneAnonymous is Clone for untrusted URLs: git ignores the user's and
+// system's config, so no credential helper, url rewrite, or extra header
+// can authenticate the request, and checks out symlinks as plain files.
+func CloneAnonymous(ctx context.Context, url, ref, dir string) error {
+	return clone(ctx, url, ref, dir, true)
+}
+
+func clone(ctx context.Context, url, ref, dir string, anonymous bool) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("git not found in PATH")
+	}
+	if strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("invalid ref %q", ref)
+	}
+	var args []string
+	env := os.Environ()
+	if anonymous {
+		args = []string{"-c", "credential.helper=", "-c", "core.symlinks=false"}
+		// GIT_CONFIG_* and GIT_ASKPASS in the environment could supply
+		// credentials too
+		env = nil
+		for _, kv := range os.Environ() {
+			if !strings.HasPrefix(kv, "GIT_") && !strings.HasPrefix(kv, "SSH_ASKPASS=") {
+				env = append(env, kv)
+			}
+		}
+		env = append(env, "GIT_CONFIG_GLOBAL="+os.DevNull, "GIT_CONFIG_NOSYSTEM=1")
+	}
+	args = append(args, "clone", "--quiet", "--depth", "1")
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	cmd := exec.CommandContext(ctx, "git", append(args, "--", url, dir)...)
+	cmd.Env = append(env, "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git clone %s: %s", url, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// RepoName returns the repository name in a clone URL, e.g. service for
+// https://github.com/org/service.git or git@github.com:org/service
+func RepoName(url string) string {
+	url = strings.TrimRight(url, "/")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+	return strings.TrimSuffix(path.Base(url), ".git")
+}
+
+// CurrentBranch returns the checked-out branch
+func (r *Repo) CurrentBranch(ctx context.Context) (string, error) {
+	return r.git(ctx, "rev-parse", "--abbrev-ref", "HEAD")
+}
+
+// RemoteURL returns the fetch URL of a remote
+func (r *Repo) RemoteURL(ctx context.Context, remote string) (string, error) {
+	return r.git(ctx, "remote", "get-url", remote)
+}
+
+// CreateBranch creates and checks out a new branch from HEAD
+func (r *Repo) CreateBranch(ctx context.Context, name string) error {
+	_, err := r.git(ctx, "checkout", "-b", name)
+	return err
+}
+
+// CommitPaths stages and commits only the given paths, leaving anything else
+// that is staged untouched
+func (r *Repo) CommitPaths(ctx context.Context, message string, paths ...string) error {
+	if _, err := r.git(ctx, append([]string{"add", "--"}, paths...)...); err != nil {
+		return err
+	}
+	_, err := r.git(ctx, append([]string{"commit", "-m", message, "--"}, paths...)...)
+	return err
+}
+
+// Push pushes branch to remote and sets it as upstream
+func (r *Repo) Push(ctx context.Context, remote, branch string) error {
+	_, err := r.git(ctx, "push", "-u", remote, branch)
+	return err
+}
+
+// StagedFiles returns the paths, relative to Root, of files added, copied, or
+// modified in the index
+func (r *Repo) StagedFiles(ctx context.Context) ([]string, error) {
+	out, err := r.git(ctx, "diff", "--cached", "--name-only", "--diff-filter=ACM")
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// StagedContent returns the staged content of path, which is relative to Root
+func (r *Repo) StagedContent(ctx context.Context, path string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", "show", ":"+path)
+	cmd.Dir = r.Root
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git show :%s: %w", path, err)
+	}
+	return out, nil
+}
+
+// HooksDir returns the absolute path of the repository's hooks directory
+func (r *Repo) HooksDir(ctx context.Context) (string, error) {
+	dir, err := r.git(ctx, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(r.Root, dir)
+	}
+	return dir, nil
+}
+
+func (r *Repo) git(ctx context.Context, args ...string) (string, error) {
+	return runGit(ctx, r.Root, args...)
+}
+
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
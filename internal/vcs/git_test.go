@@ -0,0 +1,89 @@
+package vcs
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepoName(t *testing.T) {
+	for url, want := range map[string]string{
+		"https://github.com/org/service.git": "service",
+		"https://github.com/org/service/":    "service",
+		"git@github.com:org/service.git":     "service",
+		"git@github.com:service":             "service",
+	} {
+		if got := RepoName(url); got != want {
+			t.Errorf("RepoName(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+// sourceRepo returns a repository with a Dockerfile on the release branch
+// and the symlink link to /etc/passwd
+func sourceRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "Dockerfile"), []byte("FROM nginx\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/etc/passwd", filepath.Join(src, "link")); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch", "main"},
+		{"checkout", "--quiet", "-b", "release"},
+		{"add", "Dockerfile", "link"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "init"},
+	} {
+		if _, err := runGit(context.Background(), src, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return src
+}
+
+func TestClone(t *testing.T) {
+	src := sourceRepo(t)
+	dst := filepath.Join(t.TempDir(), "service")
+	if err := Clone(context.Background(), "file://"+src, "release", dst); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "Dockerfile")); err != nil {
+		t.Error(err)
+	}
+	if err := Clone(context.Background(), "file://"+src, "--upload-pack=evil", t.TempDir()); err == nil {
+		t.Error("expected error for a ref starting with -")
+	}
+}
+
+func TestCloneAnonymous(t *testing.T) {
+	src := sourceRepo(t)
+	// A user config rewriting the URL, as one adding credentials would
+	global := filepath.Join(t.TempDir(), "gitconfig")
+	config := "[url \"file:///nonexistent\"]\n\tinsteadOf = file://" + src + "\n"
+	if err := os.WriteFile(global, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", global)
+
+	if err := Clone(context.Background(), "file://"+src, "release", filepath.Join(t.TempDir(), "service")); err == nil {
+		t.Fatal("Clone ignored the user's config")
+	}
+	dst := filepath.Join(t.TempDir(), "service")
+	if err := CloneAnonymous(context.Background(), "file://"+src, "release", dst); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Lstat(filepath.Join(dst, "link"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Mode().IsRegular() {
+		t.Errorf("link checked out as %v, want a regular file", info.Mode())
+	}
+}
@@ -0,0 +1,169 @@
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Remote identifies a hosted repository
+type Remote struct {
+	Host string
+	// Path is owner/repo on GitHub or group/subgroup/project on GitLab
+	Path string
+}
+
+// ParseRemote parses an HTTPS, ssh://, or scp-style (git@host:path) git URL
+func ParseRemote(raw string) (*Remote, error) {
+	raw = strings.TrimSpace(raw)
+	var host, path string
+	switch {
+	case strings.Contains(raw, "://"):
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote URL %q: %w", raw, err)
+		}
+		host, path = u.Hostname(), u.Path
+	case strings.Contains(raw, ":"):
+		// git@github.com:owner/repo.git
+		at := strings.Index(raw, "@")
+		colon := strings.Index(raw, ":")
+		host, path = raw[at+1:colon], raw[colon+1:]
+	default:
+		return nil, fmt.Errorf("unrecognized remote URL %q", raw)
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return nil, fmt.Errorf("unrecognized remote URL %q", raw)
+	}
+	return &Remote{Host: host, Path: path}, nil
+}
+
+// Kind returns "github" or "gitlab" based on the host name, or "" if unknown
+func (r *Remote) Kind() string {
+	switch {
+	case strings.Contains(r.Host, "github"):
+		return "github"
+	case strings.Contains(r.Host, "gitlab"):
+		return "gitlab"
+	default:
+		return ""
+	}
+}
+
+// PullRequest describes a pull (or merge) request to open
+type PullRequest struct {
+	Title string
+	Body  string
+	// Head is the branch with the changes, Base the branch to merge into
+	Head string
+	Base string
+}
+
+// Provider opens pull requests on a hosting service
+type Provider interface {
+	// CreatePullRequest opens the request and returns its web URL
+	CreatePullRequest(ctx context.Context, pr PullRequest) (string, error)
+}
+
+// NewProvider returns the provider for a remote. GitHub Enterprise and
+// self-managed GitLab are reached on the remote's own host.
+func NewProvider(remote *Remote, token string) (Provider, error) {
+	kind := remote.Kind()
+	if kind == "" {
+		return nil, fmt.Errorf("cannot open pull requests on %s (supported: GitHub, GitLab)", remote.Host)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("no %s token; set %s or run 'dorgu config set git.%s_token <token>'", kind, map[string]string{"github": "GITHUB_TOKEN", "gitlab": "GITLAB_TOKEN"}[kind], kind)
+	}
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	if kind == "github" {
+		api := "https://api.github.com"
+		if remote.Host != "github.com" {
+			api = "https://" + remote.Host + "/api/v3"
+		}
+		return &GitHub{APIURL: api, Repo: remote.Path, Token: token, HTTP: httpClient}, nil
+	}
+	return &GitLab{APIURL: "https://" + remote.Host + "/api/v4", Project: remote.Path, Token: token, HTTP: httpClient}, nil
+}
+
+// GitHub opens pull requests through the GitHub REST API
+type GitHub struct {
+	APIURL string
+	Repo   string
+	Token  string
+	HTTP   *http.Client
+}
+
+// CreatePullRequest implements Provider
+func (g *GitHub) CreatePullRequest(ctx context.Context, pr PullRequest) (string, error) {
+	body := map[string]string{"title": pr.Title, "body": pr.Body, "head": pr.Head, "base": pr.Base}
+	var resp struct {
+		HTMLURL string `json:"html_url"`
+	}
+	headers := map[string]string{
+		"Authorization": "Bearer " + g.Token,
+		"Accept":        "application/vnd.github+json",
+	}
+	if err := postJSON(ctx, g.HTTP, g.APIURL+"/repos/"+g.Repo+"/pulls", headers, body, &resp); err != nil {
+		return "", fmt.Errorf("failed to open pull request: %w", err)
+	}
+	return resp.HTMLURL, nil
+}
+
+// GitLab opens merge requests through the GitLab REST API
+type GitLab struct {
+	APIURL  string
+	Project string
+	Token   string
+	HTTP    *http.Client
+}
+
+// CreatePullRequest implements Provider
+func (g *GitLab) CreatePullRequest(ctx context.Context, pr PullRequest) (string, error) {
+	body := map[string]string{
+		"title":         pr.Title,
+		"description":   pr.Body,
+		"source_branch": pr.Head,
+		"target_branch": pr.Base,
+	}
+	var resp struct {
+		WebURL string `json:"web_url"`
+	}
+	endpoint := g.APIURL + "/projects/" + url.PathEscape(g.Project) + "/merge_requests"
+	if err := postJSON(ctx, g.HTTP, endpoint, map[string]string{"PRIVATE-TOKEN": g.Token}, body, &resp); err != nil {
+		return "", fmt.Errorf("failed to open merge request: %w", err)
+	}
+	return resp.WebURL, nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return json.Unmarshal(respBody, out)
+}
@@ -0,0 +1,118 @@
+package vcs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		raw      string
+		wantHost string
+		wantPath string
+		wantKind string
+		wantErr  bool
+	}{
+		{"https://github.com/dorgu-ai/dorgu.git", "github.com", "dorgu-ai/dorgu", "github", false},
+		{"git@github.com:dorgu-ai/dorgu.git", "github.com", "dorgu-ai/dorgu", "github", false},
+		{"ssh://git@gitlab.example.com:2222/platform/apps/orders.git", "gitlab.example.com", "platform/apps/orders", "gitlab", false},
+		{"https://gitlab.com/group/project", "gitlab.com", "group/project", "gitlab", false},
+		{"https://bitbucket.org/team/repo.git", "bitbucket.org", "team/repo", "", false},
+		{"/srv/git/repo.git", "", "", "", true},
+		{"https://github.com/onlyowner", "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseRemote(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRemote() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Host != tt.wantHost || got.Path != tt.wantPath {
+				t.Errorf("ParseRemote() = %+v, want host %q path %q", got, tt.wantHost, tt.wantPath)
+			}
+			if got.Kind() != tt.wantKind {
+				t.Errorf("Kind() = %q, want %q", got.Kind(), tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	p, err := NewProvider(&Remote{Host: "github.example.com", Path: "a/b"}, "t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gh, ok := p.(*GitHub); !ok || gh.APIURL != "https://github.example.com/api/v3" {
+		t.Errorf("GitHub Enterprise provider = %#v", p)
+	}
+	if _, err := NewProvider(&Remote{Host: "gitlab.com", Path: "a/b"}, ""); err == nil {
+		t.Error("expected an error without a token")
+	}
+	if _, err := NewProvider(&Remote{Host: "bitbucket.org", Path: "a/b"}, "t"); err == nil {
+		t.Error("expected an error for an unsupported host")
+	}
+}
+
+func TestGitHubCreatePullRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/orders/pulls" || r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["head"] != "dorgu/orders" || body["base"] != "main" || body["title"] != "Add manifests" {
+			http.Error(w, "unexpected body", http.StatusUnprocessableEntity)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"html_url": "https://github.com/acme/orders/pull/7"})
+	}))
+	defer srv.Close()
+
+	gh := &GitHub{APIURL: srv.URL, Repo: "acme/orders", Token: "secret", HTTP: srv.Client()}
+	url, err := gh.CreatePullRequest(context.Background(), PullRequest{Title: "Add manifests", Head: "dorgu/orders", Base: "main"})
+	if err != nil {
+		t.Fatalf("CreatePullRequest() error: %v", err)
+	}
+	if url != "https://github.com/acme/orders/pull/7" {
+		t.Errorf("url = %q", url)
+	}
+
+	gh.Token = "wrong"
+	if _, err := gh.CreatePullRequest(context.Background(), PullRequest{}); err == nil {
+		t.Error("expected an error for a rejected request")
+	}
+}
+
+func TestGitLabCreatePullRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/projects/platform%2Forders/merge_requests" || r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["source_branch"] != "dorgu/orders" || body["target_branch"] != "main" || body["description"] != "body" {
+			http.Error(w, "unexpected body", http.StatusUnprocessableEntity)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{"web_url": "https://gitlab.com/platform/orders/-/merge_requests/3"})
+	}))
+	defer srv.Close()
+
+	gl := &GitLab{APIURL: srv.URL, Project: "platform/orders", Token: "secret", HTTP: srv.Client()}
+	url, err := gl.CreatePullRequest(context.Background(), PullRequest{Title: "t", Body: "body", Head: "dorgu/orders", Base: "main"})
+	if err != nil {
+		t.Fatalf("CreatePullRequest() error: %v", err)
+	}
+	if url != "https://gitlab.com/platform/orders/-/merge_requests/3" {
+		t.Errorf("url = %q", url)
+	}
+}
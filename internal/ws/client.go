@@ -3,11 +3,16 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
 )
 
 // MessageType defines the type of WebSocket message.
@@ -32,15 +37,40 @@ const (
 	TopicEvents      Topic = "events"
 )
 
+// Action selects the operation of a request within its topic.
+type Action string
+
+const (
+	ActionList                  Action = "list"
+	ActionGet                   Action = "get"
+	ActionListValidationResults Action = "listValidationResults"
+	ActionGetRecommendations    Action = "getRecommendations"
+	ActionApply                 Action = "apply"
+	ActionHistory               Action = "history"
+)
+
 // Message is the base WebSocket message structure.
 type Message struct {
 	Type      MessageType     `json:"type"`
 	Topic     Topic           `json:"topic,omitempty"`
+	Action    Action          `json:"action,omitempty"`
 	RequestID string          `json:"requestId,omitempty"`
 	Payload   json.RawMessage `json:"payload,omitempty"`
 	Timestamp time.Time       `json:"timestamp"`
 }
 
+// SubscribeFilter narrows the events the operator sends for a subscription.
+// Empty fields match everything.
+type SubscribeFilter struct {
+	Namespace     string `json:"namespace,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+// IsEmpty reports whether the filter matches all events.
+func (f SubscribeFilter) IsEmpty() bool {
+	return f.Namespace == "" && f.LabelSelector == ""
+}
+
 // PersonaEvent represents a persona change event.
 type PersonaEvent struct {
 	EventType string `json:"eventType"`
@@ -87,37 +117,145 @@ type ClusterResponse struct {
 	Addons           []string `json:"addons"`
 }
 
-// ErrorPayload is the payload for error messages.
+// ValidationResult is the latest validation outcome for one persona.
+type ValidationResult struct {
+	Namespace   string                         `json:"namespace"`
+	Name        string                         `json:"name"`
+	Passed      bool                           `json:"passed"`
+	LastChecked string                         `json:"lastChecked,omitempty"`
+	Issues      []types.PersonaValidationIssue `json:"issues,omitempty"`
+}
+
+// ValidationResultsResponse is the response for listing validation results.
+type ValidationResultsResponse struct {
+	Results []ValidationResult `json:"results"`
+}
+
+// RecommendationsResponse is the response for a persona's recommendations.
+type RecommendationsResponse struct {
+	Namespace       string                        `json:"namespace"`
+	Name            string                        `json:"name"`
+	Recommendations []types.PersonaRecommendation `json:"recommendations"`
+}
+
+// ApplyPersonaRequest is the payload for applying a persona via the operator.
+type ApplyPersonaRequest struct {
+	Persona *types.ApplicationPersona `json:"persona"`
+	DryRun  bool                      `json:"dryRun,omitempty"`
+}
+
+// ApplyPersonaResponse is the operator's result of an apply.
+type ApplyPersonaResponse struct {
+	// Result is created, configured, or unchanged.
+	Result     string                    `json:"result"`
+	Persona    *types.ApplicationPersona `json:"persona,omitempty"`
+	Validation *types.PersonaValidation  `json:"validation,omitempty"`
+}
+
+// HistoryRequest asks the operator for buffered events on a topic.
+type HistoryRequest struct {
+	SubscribeFilter
+	Since time.Time `json:"since"`
+	// Limit is the maximum number of events per page.
+	Limit int `json:"limit,omitempty"`
+	// Continue is the token from the previous page.
+	Continue string `json:"continue,omitempty"`
+}
+
+// HistoryResponse is one page of buffered events, oldest first.
+type HistoryResponse struct {
+	Events []Message `json:"events"`
+	// Continue is set when more events are available.
+	Continue string `json:"continue,omitempty"`
+}
+
+// ErrorPayload is the payload for error messages. It is returned as the
+// error of a failed request.
 type ErrorPayload struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 }
 
+// ErrorCodeNotFound is the error code for requests on missing objects.
+const ErrorCodeNotFound = "NOT_FOUND"
+
+// ErrConnectionLost is returned by requests whose connection drops before
+// the response arrives.
+var ErrConnectionLost = errors.New("connection to operator lost")
+
+func (e *ErrorPayload) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// IsNotFound reports whether err is an operator NOT_FOUND error.
+func IsNotFound(err error) bool {
+	var e *ErrorPayload
+	return errors.As(err, &e) && e.Code == ErrorCodeNotFound
+}
+
+const (
+	// pingInterval is how often ping frames are sent. It must stay below the
+	// idle timeout of load balancers in front of the operator (often 60s).
+	pingInterval = 30 * time.Second
+	// pongWait is how long the connection may stay silent before it is
+	// considered dead. Any frame, including a pong, resets the deadline.
+	pongWait = 45 * time.Second
+	// writeWait bounds how long a single write may block.
+	writeWait = 10 * time.Second
+	// DefaultRequestTimeout is how long a request waits for its response
+	// unless overridden by SetRequestTimeout or RequestOptions.
+	DefaultRequestTimeout = 30 * time.Second
+)
+
+// RequestOptions tunes a single request/response call.
+type RequestOptions struct {
+	// Timeout overrides the client's request timeout when non-zero.
+	Timeout time.Duration
+}
+
 // Client is a WebSocket client for communicating with the Dorgu Operator.
 type Client struct {
-	url           string
-	conn          *websocket.Conn
-	connected     bool
-	mu            sync.RWMutex
-	handlers      map[Topic]func(*Message)
-	handlersMu    sync.RWMutex
-	responses     map[string]chan *Message
-	responsesMu   sync.Mutex
-	done          chan struct{}
-	reconnectWait time.Duration
+	url            string
+	conn           *websocket.Conn
+	connected      bool
+	mu             sync.RWMutex
+	writeMu        sync.Mutex
+	handlers       map[Topic]func(*Message)
+	handlersMu     sync.RWMutex
+	responses      map[string]chan *Message
+	responsesMu    sync.Mutex
+	done           chan struct{}
+	disconnected   chan struct{}
+	reconnectWait  time.Duration
+	pingInterval   time.Duration
+	pongWait       time.Duration
+	requestTimeout time.Duration
 }
 
 // NewClient creates a new WebSocket client.
 func NewClient(url string) *Client {
 	return &Client{
-		url:           url,
-		handlers:      make(map[Topic]func(*Message)),
-		responses:     make(map[string]chan *Message),
-		done:          make(chan struct{}),
-		reconnectWait: 5 * time.Second,
+		url:            url,
+		handlers:       make(map[Topic]func(*Message)),
+		responses:      make(map[string]chan *Message),
+		done:           make(chan struct{}),
+		disconnected:   make(chan struct{}),
+		reconnectWait:  5 * time.Second,
+		pingInterval:   pingInterval,
+		pongWait:       pongWait,
+		requestTimeout: DefaultRequestTimeout,
 	}
 }
 
+// SetRequestTimeout sets the default timeout for request/response calls.
+// Non-positive values restore DefaultRequestTimeout.
+func (c *Client) SetRequestTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultRequestTimeout
+	}
+	c.requestTimeout = d
+}
+
 // Connect establishes a WebSocket connection.
 func (c *Client) Connect(ctx context.Context) error {
 	c.mu.Lock()
@@ -135,12 +273,16 @@ func (c *Client) Connect(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to connect to %s: %w", c.url, err)
 	}
+	slog.Debug("connected to operator", "url", c.url)
 
 	c.conn = conn
 	c.connected = true
+	c.done = make(chan struct{})
+	c.disconnected = make(chan struct{})
 
-	// Start read pump
-	go c.readPump()
+	// Start read pump and keepalive
+	go c.readPump(conn, c.done, c.disconnected)
+	go c.pingLoop(conn, c.done, c.disconnected)
 
 	return nil
 }
@@ -163,6 +305,15 @@ func (c *Client) Close() error {
 	return nil
 }
 
+// Disconnected returns a channel that is closed when the current connection
+// is lost, either because the server went away or because it stopped
+// answering pings.
+func (c *Client) Disconnected() <-chan struct{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.disconnected
+}
+
 // IsConnected returns whether the client is connected.
 func (c *Client) IsConnected() bool {
 	c.mu.RLock()
@@ -170,8 +321,14 @@ func (c *Client) IsConnected() bool {
 	return c.connected
 }
 
-// Subscribe subscribes to a topic.
+// Subscribe subscribes to all events on a topic.
 func (c *Client) Subscribe(ctx context.Context, topic Topic, handler func(*Message)) error {
+	return c.SubscribeWithFilter(ctx, topic, SubscribeFilter{}, handler)
+}
+
+// SubscribeWithFilter subscribes to a topic, asking the operator to only send
+// events matching filter.
+func (c *Client) SubscribeWithFilter(ctx context.Context, topic Topic, filter SubscribeFilter, handler func(*Message)) error {
 	c.handlersMu.Lock()
 	c.handlers[topic] = handler
 	c.handlersMu.Unlock()
@@ -182,6 +339,14 @@ func (c *Client) Subscribe(ctx context.Context, topic Topic, handler func(*Messa
 		RequestID: generateRequestID(),
 		Timestamp: time.Now(),
 	}
+	if !filter.IsEmpty() {
+		payload, err := json.Marshal(filter)
+		if err != nil {
+			return err
+		}
+		msg.Payload = payload
+	}
+	slog.Debug("subscribing", "topic", topic, "namespace", filter.Namespace, "selector", filter.LabelSelector)
 
 	return c.send(msg)
 }
@@ -203,65 +368,132 @@ func (c *Client) Unsubscribe(ctx context.Context, topic Topic) error {
 }
 
 // ListPersonas requests a list of personas.
-func (c *Client) ListPersonas(ctx context.Context, namespace string) (*ListPersonasResponse, error) {
+func (c *Client) ListPersonas(ctx context.Context, namespace string, opts ...RequestOptions) (*ListPersonasResponse, error) {
 	payload := map[string]string{}
 	if namespace != "" {
 		payload["namespace"] = namespace
 	}
 
-	payloadBytes, _ := json.Marshal(payload)
-	msg := &Message{
-		Type:      MessageTypeRequest,
-		Topic:     TopicPersonas,
-		RequestID: generateRequestID(),
-		Payload:   payloadBytes,
-		Timestamp: time.Now(),
+	var result ListPersonasResponse
+	if err := c.call(ctx, TopicPersonas, ActionList, payload, &result, opts...); err != nil {
+		return nil, err
 	}
+	return &result, nil
+}
 
-	resp, err := c.request(ctx, msg)
-	if err != nil {
+// GetPersona requests a single ApplicationPersona including its status.
+func (c *Client) GetPersona(ctx context.Context, namespace, name string, opts ...RequestOptions) (*types.ApplicationPersona, error) {
+	payload := map[string]string{"namespace": namespace, "name": name}
+
+	var result types.ApplicationPersona
+	if err := c.call(ctx, TopicPersonas, ActionGet, payload, &result, opts...); err != nil {
 		return nil, err
 	}
+	return &result, nil
+}
 
-	var result ListPersonasResponse
-	if err := json.Unmarshal(resp.Payload, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+// ListValidationResults requests the latest validation result of every
+// persona, optionally limited to a namespace.
+func (c *Client) ListValidationResults(ctx context.Context, namespace string, opts ...RequestOptions) (*ValidationResultsResponse, error) {
+	payload := map[string]string{}
+	if namespace != "" {
+		payload["namespace"] = namespace
+	}
+
+	var result ValidationResultsResponse
+	if err := c.call(ctx, TopicPersonas, ActionListValidationResults, payload, &result, opts...); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetRecommendations requests the operator's recommendations for a persona.
+func (c *Client) GetRecommendations(ctx context.Context, namespace, name string, opts ...RequestOptions) (*RecommendationsResponse, error) {
+	payload := map[string]string{"namespace": namespace, "name": name}
+
+	var result RecommendationsResponse
+	if err := c.call(ctx, TopicPersonas, ActionGetRecommendations, payload, &result, opts...); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ApplyPersona submits a persona to the operator, which validates it, records
+// provenance, and applies it with its own service account. With dryRun the
+// operator only reports what would be persisted.
+func (c *Client) ApplyPersona(ctx context.Context, persona *types.ApplicationPersona, dryRun bool, opts ...RequestOptions) (*ApplyPersonaResponse, error) {
+	payload := ApplyPersonaRequest{Persona: persona, DryRun: dryRun}
+
+	var result ApplyPersonaResponse
+	if err := c.call(ctx, TopicPersonas, ActionApply, payload, &result, opts...); err != nil {
+		return nil, err
 	}
+	return &result, nil
+}
 
+// EventHistory requests one page of buffered events on topic.
+func (c *Client) EventHistory(ctx context.Context, topic Topic, req HistoryRequest, opts ...RequestOptions) (*HistoryResponse, error) {
+	var result HistoryResponse
+	if err := c.call(ctx, topic, ActionHistory, req, &result, opts...); err != nil {
+		return nil, err
+	}
 	return &result, nil
 }
 
 // GetCluster requests cluster information.
-func (c *Client) GetCluster(ctx context.Context, name string) (*ClusterResponse, error) {
+func (c *Client) GetCluster(ctx context.Context, name string, opts ...RequestOptions) (*ClusterResponse, error) {
 	payload := map[string]string{}
 	if name != "" {
 		payload["name"] = name
 	}
 
-	payloadBytes, _ := json.Marshal(payload)
+	var result ClusterResponse
+	if err := c.call(ctx, TopicCluster, ActionGet, payload, &result, opts...); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// call sends a request for action on topic and decodes the response payload
+// into out.
+func (c *Client) call(ctx context.Context, topic Topic, action Action, payload, out interface{}, opts ...RequestOptions) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
 	msg := &Message{
 		Type:      MessageTypeRequest,
-		Topic:     TopicCluster,
+		Topic:     topic,
+		Action:    action,
 		RequestID: generateRequestID(),
 		Payload:   payloadBytes,
 		Timestamp: time.Now(),
 	}
 
-	resp, err := c.request(ctx, msg)
+	start := time.Now()
+	resp, err := c.request(ctx, msg, opts...)
 	if err != nil {
-		return nil, err
+		slog.Debug("operator request failed", "topic", topic, "action", action, "request_id", msg.RequestID, "err", err)
+		return err
 	}
+	slog.Debug("operator request", "topic", topic, "action", action, "request_id", msg.RequestID,
+		"duration", time.Since(start).Round(time.Millisecond))
 
-	var result ClusterResponse
-	if err := json.Unmarshal(resp.Payload, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := json.Unmarshal(resp.Payload, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
 	}
-
-	return &result, nil
+	return nil
 }
 
 // request sends a request and waits for a response.
-func (c *Client) request(ctx context.Context, msg *Message) (*Message, error) {
+func (c *Client) request(ctx context.Context, msg *Message, opts ...RequestOptions) (*Message, error) {
+	timeout := c.requestTimeout
+	for _, o := range opts {
+		if o.Timeout > 0 {
+			timeout = o.Timeout
+		}
+	}
+
 	respChan := make(chan *Message, 1)
 
 	c.responsesMu.Lock()
@@ -274,23 +506,38 @@ func (c *Client) request(ctx context.Context, msg *Message) (*Message, error) {
 		c.responsesMu.Unlock()
 	}()
 
+	// Taken before sending so a drop right after the send is not missed
+	disconnected := c.Disconnected()
 	if err := c.send(msg); err != nil {
 		return nil, err
 	}
 
 	select {
 	case resp := <-respChan:
-		if resp.Type == MessageTypeError {
-			var errPayload ErrorPayload
-			json.Unmarshal(resp.Payload, &errPayload)
-			return nil, fmt.Errorf("%s: %s", errPayload.Code, errPayload.Message)
+		return response(resp)
+	case <-disconnected:
+		// The response may have been read just before the connection dropped
+		select {
+		case resp := <-respChan:
+			return response(resp)
+		default:
 		}
-		return resp, nil
+		return nil, ErrConnectionLost
 	case <-ctx.Done():
 		return nil, ctx.Err()
-	case <-time.After(30 * time.Second):
-		return nil, fmt.Errorf("request timeout")
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("request timed out after %s", timeout)
+	}
+}
+
+// response returns resp, or its error payload when it is an error.
+func response(resp *Message) (*Message, error) {
+	if resp.Type == MessageTypeError {
+		var errPayload ErrorPayload
+		json.Unmarshal(resp.Payload, &errPayload)
+		return nil, &errPayload
 	}
+	return resp, nil
 }
 
 // send sends a message over the WebSocket connection.
@@ -307,37 +554,74 @@ func (c *Client) send(msg *Message) error {
 		return err
 	}
 
+	// gorilla/websocket allows only one concurrent writer
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 	return c.conn.WriteMessage(websocket.TextMessage, data)
 }
 
-// readPump reads messages from the WebSocket connection.
-func (c *Client) readPump() {
+// pingLoop sends periodic ping frames so idle connections are kept open by
+// intermediaries and dead peers are detected by the read deadline.
+func (c *Client) pingLoop(conn *websocket.Conn, done, disconnected chan struct{}) {
+	ticker := time.NewTicker(c.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// WriteControl is safe to call concurrently with other writes
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				conn.Close()
+				return
+			}
+		case <-disconnected:
+			return
+		case <-done:
+			return
+		}
+	}
+}
+
+// readPump reads messages from the WebSocket connection. The read deadline
+// is extended on every frame; if nothing (not even a pong) arrives within
+// pongWait the read fails and the connection is marked as lost.
+func (c *Client) readPump(conn *websocket.Conn, done, disconnected chan struct{}) {
 	defer func() {
 		c.mu.Lock()
 		c.connected = false
-		if c.conn != nil {
-			c.conn.Close()
-		}
+		conn.Close()
+		close(disconnected)
 		c.mu.Unlock()
 	}()
 
+	conn.SetReadDeadline(time.Now().Add(c.pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(c.pongWait))
+	})
+
 	for {
 		select {
-		case <-c.done:
+		case <-done:
 			return
 		default:
 		}
 
-		_, data, err := c.conn.ReadMessage()
+		_, data, err := conn.ReadMessage()
 		if err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				// Log error if needed
+			select {
+			case <-done:
+				// Closed by Close
+			default:
+				slog.Debug("operator connection lost", "url", c.url, "err", err)
 			}
 			return
 		}
+		conn.SetReadDeadline(time.Now().Add(c.pongWait))
 
 		var msg Message
 		if err := json.Unmarshal(data, &msg); err != nil {
+			slog.Debug("ignoring malformed operator message", "err", err)
 			continue
 		}
 
@@ -371,5 +655,5 @@ func (c *Client) handleMessage(msg *Message) {
 
 // generateRequestID generates a unique request ID.
 func generateRequestID() string {
-	return fmt.Sprintf("%d", time.Now().UnixNano())
+	return uuid.NewString()
 }
@@ -9,9 +9,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/dorgu-ai/dorgu/internal/types"
 )
 
 // mockWebSocketServer creates a mock WebSocket server for testing
@@ -109,6 +112,44 @@ func TestClient_Subscribe(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestClient_SubscribeWithFilter(t *testing.T) {
+	filters := make(chan SubscribeFilter, 1)
+	server := mockWebSocketServer(t, func(conn *websocket.Conn) {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return
+		}
+		var filter SubscribeFilter
+		json.Unmarshal(msg.Payload, &filter)
+		filters <- filter
+		conn.ReadMessage()
+	})
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	client := NewClient(wsURL)
+
+	ctx := context.Background()
+	err := client.Connect(ctx)
+	require.NoError(t, err)
+	defer client.Close()
+
+	want := SubscribeFilter{Namespace: "prod", LabelSelector: "tier=backend"}
+	err = client.SubscribeWithFilter(ctx, TopicPersonas, want, func(msg *Message) {})
+	require.NoError(t, err)
+
+	select {
+	case got := <-filters:
+		assert.Equal(t, want, got)
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscribe message not received")
+	}
+}
+
 func TestClient_Unsubscribe(t *testing.T) {
 	server := mockWebSocketServer(t, func(conn *websocket.Conn) {
 		for {
@@ -262,6 +303,133 @@ func TestClient_GetCluster(t *testing.T) {
 	assert.Equal(t, 3, cluster.NodeCount)
 }
 
+func TestClient_PersonaActions(t *testing.T) {
+	server := mockWebSocketServer(t, func(conn *websocket.Conn) {
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			var msg Message
+			if err := json.Unmarshal(data, &msg); err != nil {
+				continue
+			}
+
+			var req map[string]string
+			json.Unmarshal(msg.Payload, &req)
+
+			var result interface{}
+			switch msg.Action {
+			case ActionGet:
+				result = types.ApplicationPersona{
+					Metadata: types.PersonaMetadata{Name: req["name"], Namespace: req["namespace"]},
+					Status:   &types.PersonaStatus{Phase: "Active"},
+				}
+			case ActionListValidationResults:
+				result = ValidationResultsResponse{Results: []ValidationResult{{
+					Namespace: "prod",
+					Name:      "orders",
+					Issues:    []types.PersonaValidationIssue{{Severity: "warning", Message: "no limits"}},
+				}}}
+			case ActionGetRecommendations:
+				result = RecommendationsResponse{
+					Namespace:       req["namespace"],
+					Name:            req["name"],
+					Recommendations: []types.PersonaRecommendation{{Type: "cost", Priority: "low"}},
+				}
+			default:
+				continue
+			}
+			payload, _ := json.Marshal(result)
+			conn.WriteJSON(Message{
+				Type:      MessageTypeResponse,
+				Topic:     msg.Topic,
+				RequestID: msg.RequestID,
+				Payload:   payload,
+				Timestamp: time.Now(),
+			})
+		}
+	})
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	client := NewClient(wsURL)
+
+	ctx := context.Background()
+	err := client.Connect(ctx)
+	require.NoError(t, err)
+	defer client.Close()
+
+	persona, err := client.GetPersona(ctx, "prod", "orders")
+	require.NoError(t, err)
+	assert.Equal(t, "orders", persona.Metadata.Name)
+	assert.Equal(t, "Active", persona.Status.Phase)
+
+	results, err := client.ListValidationResults(ctx, "prod")
+	require.NoError(t, err)
+	require.Len(t, results.Results, 1)
+	assert.Equal(t, "no limits", results.Results[0].Issues[0].Message)
+
+	recs, err := client.GetRecommendations(ctx, "prod", "orders")
+	require.NoError(t, err)
+	assert.Equal(t, "orders", recs.Name)
+	assert.Len(t, recs.Recommendations, 1)
+}
+
+func TestClient_EventHistory(t *testing.T) {
+	server := mockWebSocketServer(t, func(conn *websocket.Conn) {
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			var msg Message
+			if err := json.Unmarshal(data, &msg); err != nil || msg.Action != ActionHistory {
+				continue
+			}
+			var req HistoryRequest
+			json.Unmarshal(msg.Payload, &req)
+
+			// Two pages of one event each
+			page := HistoryResponse{Events: []Message{{Type: MessageTypeEvent, Topic: msg.Topic, Timestamp: req.Since.Add(time.Minute)}}}
+			if req.Continue == "" {
+				page.Continue = "page-2"
+			}
+			payload, _ := json.Marshal(page)
+			conn.WriteJSON(Message{
+				Type:      MessageTypeResponse,
+				Topic:     msg.Topic,
+				RequestID: msg.RequestID,
+				Payload:   payload,
+				Timestamp: time.Now(),
+			})
+		}
+	})
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	client := NewClient(wsURL)
+
+	ctx := context.Background()
+	err := client.Connect(ctx)
+	require.NoError(t, err)
+	defer client.Close()
+
+	req := HistoryRequest{SubscribeFilter: SubscribeFilter{Namespace: "prod"}, Since: time.Now().Add(-time.Hour), Limit: 1}
+	first, err := client.EventHistory(ctx, TopicPersonas, req)
+	require.NoError(t, err)
+	require.Len(t, first.Events, 1)
+	assert.Equal(t, "page-2", first.Continue)
+
+	req.Continue = first.Continue
+	second, err := client.EventHistory(ctx, TopicPersonas, req)
+	require.NoError(t, err)
+	assert.Len(t, second.Events, 1)
+	assert.Empty(t, second.Continue)
+}
+
 func TestClient_ErrorResponse(t *testing.T) {
 	server := mockWebSocketServer(t, func(conn *websocket.Conn) {
 		for {
@@ -304,6 +472,7 @@ func TestClient_ErrorResponse(t *testing.T) {
 	_, err = client.ListPersonas(ctx, "")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "NOT_FOUND")
+	assert.True(t, IsNotFound(err))
 }
 
 func TestClient_RequestTimeout(t *testing.T) {
@@ -425,12 +594,114 @@ func TestClient_NotConnected(t *testing.T) {
 }
 
 func TestGenerateRequestID(t *testing.T) {
-	id1 := generateRequestID()
-	time.Sleep(time.Nanosecond) // Ensure different timestamps
-	id2 := generateRequestID()
-
-	assert.NotEmpty(t, id1)
-	assert.NotEmpty(t, id2)
-	// IDs should be unique (though they may be equal if generated at the same nanosecond)
-	// The important thing is they are non-empty strings
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := generateRequestID()
+		_, err := uuid.Parse(id)
+		require.NoError(t, err)
+		assert.False(t, seen[id], "duplicate request ID %s", id)
+		seen[id] = true
+	}
+}
+
+func TestClient_RequestOptionsTimeout(t *testing.T) {
+	server := mockWebSocketServer(t, func(conn *websocket.Conn) {
+		// Never respond
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	client := NewClient(wsURL)
+
+	ctx := context.Background()
+	err := client.Connect(ctx)
+	require.NoError(t, err)
+	defer client.Close()
+
+	start := time.Now()
+	_, err = client.ListPersonas(ctx, "", RequestOptions{Timeout: 100 * time.Millisecond})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out after 100ms")
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	client.SetRequestTimeout(50 * time.Millisecond)
+	_, err = client.GetCluster(ctx, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out after 50ms")
+}
+
+func TestClient_KeepaliveDetectsDeadPeer(t *testing.T) {
+	// Server that never reads, so pings are never answered with pongs
+	stop := make(chan struct{})
+	server := mockWebSocketServer(t, func(conn *websocket.Conn) {
+		<-stop
+	})
+	defer server.Close()
+	defer close(stop)
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	client := NewClient(wsURL)
+	client.pingInterval = 20 * time.Millisecond
+	client.pongWait = 100 * time.Millisecond
+
+	err := client.Connect(context.Background())
+	require.NoError(t, err)
+	defer client.Close()
+
+	select {
+	case <-client.Disconnected():
+		assert.False(t, client.IsConnected())
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected dead connection to be detected")
+	}
+}
+
+func TestClient_KeepaliveHealthyPeer(t *testing.T) {
+	server := mockWebSocketServer(t, func(conn *websocket.Conn) {
+		// Reading processes pings and answers them with pongs
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	client := NewClient(wsURL)
+	client.pingInterval = 20 * time.Millisecond
+	client.pongWait = 100 * time.Millisecond
+
+	err := client.Connect(context.Background())
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Stay idle well past pongWait; pongs must keep the connection alive
+	time.Sleep(300 * time.Millisecond)
+	assert.True(t, client.IsConnected())
+}
+
+func TestClient_RequestConnectionLost(t *testing.T) {
+	// Server that drops the connection once it receives a request
+	server := mockWebSocketServer(t, func(conn *websocket.Conn) {
+		conn.ReadMessage()
+	})
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	client := NewClient(wsURL)
+
+	err := client.Connect(context.Background())
+	require.NoError(t, err)
+	defer client.Close()
+
+	start := time.Now()
+	_, err = client.ListPersonas(context.Background(), "")
+	assert.ErrorIs(t, err, ErrConnectionLost)
+	assert.Less(t, time.Since(start), DefaultRequestTimeout/2, "request waited for its timeout")
 }
@@ -0,0 +1,135 @@
+// Package dorgu is the public Go API of dorgu, for tools that embed its
+// analyzer and generator instead of shelling out to the CLI.
+//
+// The functions and option structs in this package follow semantic
+// versioning: within a major version they are not removed or changed
+// incompatibly, and new option fields default to the previous behavior. The
+// aliased types may gain fields.
+//
+//	analysis, err := dorgu.Analyze(ctx, "./orders", dorgu.AnalyzeOptions{})
+//	files, err := dorgu.Generate(ctx, analysis, dorgu.GenerateOptions{Namespace: "commerce"})
+//	report := dorgu.Validate(analysis, files, dorgu.GenerateOptions{Namespace: "commerce"})
+package dorgu
+
+import (
+	"context"
+
+	"github.com/dorgu-ai/dorgu/internal/analyzer"
+	"github.com/dorgu-ai/dorgu/internal/config"
+	"github.com/dorgu-ai/dorgu/internal/generator"
+	"github.com/dorgu-ai/dorgu/internal/types"
+)
+
+// AppAnalysis is the analysis of a containerized application
+type AppAnalysis = types.AppAnalysis
+
+// Config is the workspace configuration read from .dorgu.yaml
+type Config = config.Config
+
+// File is a generated file. Path is relative to the output directory.
+type File = generator.GeneratedFile
+
+// ValidationResult is the report of Validate
+type ValidationResult = generator.ValidationResult
+
+// ValidationIssue is a single finding in a ValidationResult
+type ValidationIssue = generator.ValidationIssue
+
+// Validation severities
+const (
+	SeverityError   = generator.SeverityError
+	SeverityWarning = generator.SeverityWarning
+	SeverityInfo    = generator.SeverityInfo
+)
+
+// DefaultConfig returns the configuration used without a .dorgu.yaml
+func DefaultConfig() *Config {
+	return config.Default()
+}
+
+// LoadConfig reads the .dorgu.yaml at path, filling in defaults for what it
+// leaves out
+func LoadConfig(path string) (*Config, error) {
+	return config.LoadFile(path)
+}
+
+// AnalyzeOptions controls Analyze
+type AnalyzeOptions struct {
+	// LLMProvider enhances the static analysis with an LLM: openai,
+	// anthropic, gemini, or ollama. Empty analyzes without an LLM.
+	LLMProvider string
+	// DisabledAnalyzers are skipped: appconfig, dockerfile, compose, code,
+	// git, or k8smanifest
+	DisabledAnalyzers []string
+}
+
+// Analyze analyzes the application in the directory at path, which must hold
+// a Dockerfile or docker-compose file. An LLM failure falls back to the
+// deterministic analysis; cancelling ctx aborts the analysis.
+func Analyze(ctx context.Context, path string, opts AnalyzeOptions) (*AppAnalysis, error) {
+	if opts.LLMProvider == "" {
+		return analyzer.AnalyzeDeterministic(ctx, path, opts.DisabledAnalyzers)
+	}
+	return analyzer.Analyze(ctx, path, opts.LLMProvider, opts.DisabledAnalyzers)
+}
+
+// GenerateOptions controls Generate and Validate
+type GenerateOptions struct {
+	// Namespace is the target namespace (default "default")
+	Namespace string
+	// Config is the workspace configuration (default DefaultConfig())
+	Config *Config
+	// LLMProvider writes PERSONA.md with an LLM. Empty uses the template.
+	LLMProvider string
+
+	SkipArgoCD  bool
+	SkipCI      bool
+	SkipPersona bool
+	SkipReadme  bool
+	SkipPlugins bool
+}
+
+func (o GenerateOptions) internal() generator.Options {
+	opts := generator.Options{
+		Namespace:    o.Namespace,
+		Config:       o.Config,
+		SkipArgoCD:   o.SkipArgoCD,
+		SkipCI:       o.SkipCI,
+		SkipPersona:  o.SkipPersona,
+		SkipReadme:   o.SkipReadme,
+		SkipPlugins:  o.SkipPlugins,
+		NoLLMPersona: o.LLMProvider == "",
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = "default"
+	}
+	if opts.Config == nil {
+		opts.Config = DefaultConfig()
+	}
+	if o.LLMProvider != "" {
+		// Leave the caller's config unchanged
+		cfg := *opts.Config
+		cfg.LLM.Provider = o.LLMProvider
+		opts.Config = &cfg
+	}
+	return opts
+}
+
+// Generate renders the Kubernetes manifests, ArgoCD Application, CI workflow,
+// and documentation for an analyzed application. Nothing is written to disk.
+// Generate may adjust the analysis, e.g. normalizing the app name.
+func Generate(ctx context.Context, analysis *AppAnalysis, opts GenerateOptions) ([]File, error) {
+	return generator.Generate(ctx, analysis, opts.internal())
+}
+
+// Validate checks files generated for analysis with the same opts, as
+// dorgu generate does after generating
+func Validate(analysis *AppAnalysis, files []File, opts GenerateOptions) *ValidationResult {
+	return ValidateContext(context.Background(), analysis, files, opts)
+}
+
+// ValidateContext is Validate with a context; canceling it stops the checks
+// that run kubectl or go over the network
+func ValidateContext(ctx context.Context, analysis *AppAnalysis, files []File, opts GenerateOptions) *ValidationResult {
+	return generator.ValidateGenerated(ctx, analysis, files, opts.internal())
+}
@@ -0,0 +1,49 @@
+package dorgu_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dorgu-ai/dorgu/pkg/dorgu"
+)
+
+func TestAnalyzeGenerateValidate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "orders")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM golang:1.22\nEXPOSE 8080\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	analysis, err := dorgu.Analyze(ctx, dir, dorgu.AnalyzeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analysis.Name != "orders" || analysis.Type == "" {
+		t.Errorf("Analyze() = name %q, type %q", analysis.Name, analysis.Type)
+	}
+
+	opts := dorgu.GenerateOptions{Namespace: "commerce", SkipCI: true}
+	files, err := dorgu.Generate(ctx, analysis, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := map[string]bool{}
+	for _, f := range files {
+		paths[f.Path] = true
+	}
+	if !paths["deployment.yaml"] || !paths["service.yaml"] {
+		t.Errorf("Generate() files = %v", paths)
+	}
+	if report := dorgu.Validate(analysis, files, opts); report == nil {
+		t.Error("Validate() = nil")
+	}
+
+	if _, err := dorgu.Analyze(ctx, t.TempDir(), dorgu.AnalyzeOptions{}); err == nil {
+		t.Error("expected error without Dockerfile")
+	}
+}